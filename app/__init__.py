@@ -0,0 +1,3 @@
+"""CS-Server application package."""
+
+__version__ = "1.0.0"
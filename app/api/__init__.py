@@ -1,4 +1,18 @@
 """API clients package."""
-from app.api.besttime_client import BestTimeAPIClient, BestTimeInvalidResponseError
+from app.api.besttime_client import (
+    BestTimeAPIClient,
+    BestTimeAPIError,
+    BestTimeCircuitOpenError,
+    BestTimeInvalidResponseError,
+    RetryPolicy,
+)
+from app.api.besttime_recorder import ReplayFixtureMissingError
 
-__all__ = ["BestTimeAPIClient", "BestTimeInvalidResponseError"]
+__all__ = [
+    "BestTimeAPIClient",
+    "BestTimeAPIError",
+    "BestTimeCircuitOpenError",
+    "BestTimeInvalidResponseError",
+    "ReplayFixtureMissingError",
+    "RetryPolicy",
+]
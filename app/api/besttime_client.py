@@ -1,17 +1,27 @@
 """BestTime API client with async HTTP support."""
 import asyncio
 import logging
+import random
 import time
 from collections import deque
-from typing import Callable, Optional
+from contextlib import contextmanager, nullcontext
+from contextvars import ContextVar
+from dataclasses import dataclass
+from typing import Callable, ContextManager, Iterator, Optional
 import httpx
+from opentelemetry import trace
 from pydantic import ValidationError
 
 from typing import AsyncIterator
 
+from app import __version__
+from app.api.besttime_decoding import BestTimeDecoder
+from app.api.besttime_recorder import build_transport
 from app.models import (
     LiveForecastResponse,
     WeekRawResponse,
+    DayForecastResponse,
+    HourForecastResponse,
     VenueFilterParams,
     VenueFilterResponse,
     NewVenueResponse,
@@ -21,11 +31,20 @@ from app.metrics import (
     BESTTIME_API_CALLS_TOTAL,
     BESTTIME_API_CALL_DURATION_SECONDS,
     BESTTIME_API_ERRORS_TOTAL,
+    BESTTIME_API_RETRIES_TOTAL,
+    BESTTIME_CIRCUIT_SHORT_CIRCUITED_TOTAL,
+    BESTTIME_CIRCUIT_STATE,
+    BESTTIME_KEY_ROTATIONS_TOTAL,
     BESTTIME_SEARCH_RATE_LIMIT_TOTAL,
+    BESTTIME_THROTTLE_WAIT_SECONDS_TOTAL,
 )
+from app.tracing import traced
 
 logger = logging.getLogger(__name__)
 
+# Identifies our traffic to BestTime support (and to egress proxies).
+DEFAULT_USER_AGENT = f"cs-server/{__version__}"
+
 
 class BestTimeInvalidResponseError(Exception):
     """BestTime answered 2xx but the body's envelope (status / venue_info)
@@ -41,6 +60,110 @@ class BestTimeRateLimitedError(Exception):
     venue rejection."""
 
 
+class BestTimeCircuitOpenError(Exception):
+    """The BestTime circuit breaker is open after consecutive failures; the
+    call was short-circuited without being sent. Retryable after the
+    cooldown."""
+
+
+class BestTimeAPIError(httpx.HTTPStatusError):
+    """A non-2xx BestTime answer, parsed and classified.
+
+    BestTime error bodies are {"status": "Error", "message": ...}, sometimes
+    with credit counters; `message` and `quota` (any credit/quota fields of
+    the body) carry them. `kind` says what went wrong and `action` what a
+    batch caller should do about it:
+
+    - quota_exceeded (credits or the monthly cap are used up) -> abort
+    - unauthorized (401/403: bad or revoked key) -> abort
+    - invalid_venue (404, or a 400 about the venue id) -> skip
+    - transient (408/429/5xx, left after the client's retries) -> retry
+    - rejected (any other 4xx: a bad request for this call) -> skip
+
+    Subclasses httpx.HTTPStatusError so existing handlers keep working.
+    """
+
+    QUOTA_EXCEEDED = "quota_exceeded"
+    UNAUTHORIZED = "unauthorized"
+    INVALID_VENUE = "invalid_venue"
+    TRANSIENT = "transient"
+    REJECTED = "rejected"
+
+    ABORT, SKIP, RETRY = "abort", "skip", "retry"
+    _ACTIONS = {
+        QUOTA_EXCEEDED: ABORT,
+        UNAUTHORIZED: ABORT,
+        INVALID_VENUE: SKIP,
+        TRANSIENT: RETRY,
+        REJECTED: SKIP,
+    }
+
+    def __init__(
+        self,
+        status_code: int,
+        message: Optional[str],
+        endpoint: str,
+        quota: Optional[dict] = None,
+        *,
+        request: httpx.Request,
+        response: httpx.Response,
+    ):
+        self.status_code = status_code
+        self.message = message
+        self.endpoint = endpoint
+        self.quota = quota or {}
+        self.kind = _classify_error(status_code, message)
+        super().__init__(
+            f"BestTime {status_code} on {endpoint} ({self.kind}): {message or 'no message'}",
+            request=request,
+            response=response,
+        )
+
+    @property
+    def action(self) -> str:
+        return self._ACTIONS[self.kind]
+
+    @classmethod
+    def from_status_error(cls, error: httpx.HTTPStatusError, endpoint: str) -> "BestTimeAPIError":
+        """Parse the error body of `error`'s response (never raises)."""
+        response = error.response
+        try:
+            body = response.json()
+        except Exception:
+            body = None
+        if not isinstance(body, dict):
+            body = {}
+        message = body.get("message")
+        quota = {
+            k: v for k, v in body.items()
+            if "credit" in k.lower() or "quota" in k.lower()
+        }
+        status_code = response.status_code
+        return cls(
+            status_code if isinstance(status_code, int) else 0,
+            message if isinstance(message, str) else None,
+            endpoint,
+            quota,
+            request=error.request,
+            response=response,
+        )
+
+
+def _classify_error(status_code: int, message: Optional[str]) -> str:
+    low = (message or "").lower()
+    if status_code == 402 or any(
+        word in low for word in ("credit", "quota", "monthly venues", "venue counter will reset")
+    ):
+        return BestTimeAPIError.QUOTA_EXCEEDED
+    if status_code in (401, 403):
+        return BestTimeAPIError.UNAUTHORIZED
+    if status_code == 408 or status_code == 429 or status_code >= 500:
+        return BestTimeAPIError.TRANSIENT
+    if status_code == 404 or (status_code == 400 and "venue" in low):
+        return BestTimeAPIError.INVALID_VENUE
+    return BestTimeAPIError.REJECTED
+
+
 # BestTime's documented Venue Search limits (documentation.besttime.app):
 # 30 requests/minute and 300 requests/hour. The create call (POST /forecasts)
 # draws the same "Venue Search" monthly quota, so it is paced with the family.
@@ -48,6 +171,48 @@ _MINUTE_WINDOW_SECONDS = 60.0
 _HOUR_WINDOW_SECONDS = 3600.0
 
 
+@dataclass(frozen=True)
+class RetryPolicy:
+    """How transient BestTime answers are retried.
+
+    A response whose status is in ``retry_on_status`` is retried up to
+    ``max_attempts`` total sends. The wait honors Retry-After when present and
+    parseable (``honor_retry_after``), else exponential backoff
+    ``backoff_base_seconds * 2**attempt`` capped at ``backoff_max_seconds``,
+    spread by +/- ``jitter_ratio`` so a batch of refreshes does not retry in
+    lockstep. The total wait per call stays within the client's
+    ``rate_max_wait_seconds``.
+    """
+
+    max_attempts: int = 3
+    backoff_base_seconds: float = 1.0
+    backoff_max_seconds: float = 30.0
+    jitter_ratio: float = 0.2
+    retry_on_status: frozenset[int] = frozenset({429, 500, 502, 503, 504})
+    honor_retry_after: bool = True
+
+    def wait_seconds(
+        self, response: httpx.Response, attempt: int,
+        rand: Callable[[], float] = random.random,
+    ) -> float:
+        """Seconds to wait before retry number ``attempt + 1``."""
+        header = response.headers.get("retry-after") if self.honor_retry_after else None
+        if header is not None:
+            try:
+                return max(0.0, float(header))
+            except ValueError:
+                pass
+        backoff = min(self.backoff_max_seconds, self.backoff_base_seconds * 2**attempt)
+        return max(0.0, backoff * (1 + self.jitter_ratio * (2 * rand() - 1)))
+
+
+# Endpoints that charge credits for a send BestTime accepted: a 5xx or a
+# timeout may already have been charged, so only a 429 (refused before the
+# meter) is retried. The create (POST /forecasts) passes the same policy.
+METERED_ENDPOINTS = frozenset({"/forecasts/live"})
+METERED_RETRY_STATUSES = frozenset({429})
+
+
 def _looks_like_monthly_cap_body(response: httpx.Response) -> bool:
     """True when a 429 body carries BestTime's monthly unique-venue cap message
     (mirrors the handler's `_is_monthly_cap_rejection` keywords). Cap answers
@@ -130,6 +295,282 @@ class _SearchRateLimiter:
             waited_total += wait
 
 
+class _CircuitBreaker:
+    """Consecutive-failure circuit breaker over every BestTime send.
+
+    closed: calls flow; ``failure_threshold`` consecutive failures (transport
+    errors, timeouts, 5xx after retries) open it. open: calls fail fast with
+    BestTimeCircuitOpenError for ``cooldown_seconds``. half_open: after the
+    cooldown one trial call is let through; success closes the circuit,
+    failure re-opens it for another cooldown. ``failure_threshold`` <= 0
+    disables it.
+    """
+
+    CLOSED, OPEN, HALF_OPEN = "closed", "open", "half_open"
+
+    def __init__(
+        self,
+        failure_threshold: int,
+        cooldown_seconds: float,
+        time_func: Callable[[], float] = time.monotonic,
+    ):
+        self.failure_threshold = failure_threshold
+        self.cooldown_seconds = cooldown_seconds
+        self._time = time_func
+        self._failures = 0
+        self._opened_at: Optional[float] = None
+        self._trial_in_flight = False
+        BESTTIME_CIRCUIT_STATE.set(0)
+
+    @property
+    def state(self) -> str:
+        if self._opened_at is None:
+            return self.CLOSED
+        if self._time() - self._opened_at >= self.cooldown_seconds:
+            return self.HALF_OPEN
+        return self.OPEN
+
+    def before_call(self, endpoint: str) -> None:
+        """Raise BestTimeCircuitOpenError unless the call may be sent."""
+        if self.failure_threshold <= 0:
+            return
+        state = self.state
+        if state == self.CLOSED:
+            return
+        if state == self.HALF_OPEN and not self._trial_in_flight:
+            self._trial_in_flight = True
+            logger.info(f"[BestTimeAPIClient] circuit half-open; trial call on {endpoint}")
+            return
+        BESTTIME_CIRCUIT_SHORT_CIRCUITED_TOTAL.labels(endpoint=endpoint).inc()
+        raise BestTimeCircuitOpenError(
+            f"BestTime circuit open after {self._failures} consecutive failures; "
+            f"{endpoint} not sent"
+        )
+
+    def record_success(self) -> None:
+        if self._opened_at is not None:
+            logger.info("[BestTimeAPIClient] circuit closed; BestTime answering again")
+        self._failures = 0
+        self._opened_at = None
+        self._trial_in_flight = False
+        BESTTIME_CIRCUIT_STATE.set(0)
+
+    def record_failure(self) -> None:
+        if self.failure_threshold <= 0:
+            return
+        self._failures += 1
+        self._trial_in_flight = False
+        if self._opened_at is not None or self._failures >= self.failure_threshold:
+            self._opened_at = self._time()
+            BESTTIME_CIRCUIT_STATE.set(1)
+            logger.error(
+                f"[BestTimeAPIClient] circuit open for {self.cooldown_seconds:.0f}s "
+                f"after {self._failures} consecutive failures"
+            )
+
+    def abandon_trial(self) -> None:
+        """A half-open trial ended without a verdict (e.g. cancelled): let the
+        next call try instead."""
+        self._trial_in_flight = False
+
+    def snapshot(self) -> dict:
+        """State for the health endpoint."""
+        return {"state": self.state, "consecutive_failures": self._failures}
+
+
+@dataclass(frozen=True)
+class BestTimeKeyPair:
+    """One BestTime account's public/private API keys."""
+
+    public: str
+    private: str
+
+    @property
+    def label(self) -> str:
+        """Loggable id of the pair (last 4 chars of the public key)."""
+        return f"...{self.public[-4:]}" if self.public else "unset"
+
+
+class _KeyRing:
+    """The BestTime key pairs a client sends with, and which one to use next.
+
+    "failover" uses the first pair until BestTime reports its credits/quota
+    used up, then the next one; "round_robin" spreads consecutive sends over
+    all pairs. Either way a pair that hit its quota is skipped for
+    ``exhausted_cooldown_seconds``; when every pair is exhausted, the one
+    whose cooldown ends first is used (BestTime then answers the quota error
+    as usual).
+
+    Venues and inventories belong to one account, so calls that build on each
+    other (a create and its follow-up reads) run under ``pinned()``: every
+    send in the block uses the same pair, and a quota answer is not resent on
+    another one.
+    """
+
+    FAILOVER, ROUND_ROBIN = "failover", "round_robin"
+    STRATEGIES = (FAILOVER, ROUND_ROBIN)
+
+    def __init__(
+        self,
+        pairs: list[BestTimeKeyPair],
+        strategy: str = FAILOVER,
+        exhausted_cooldown_seconds: float = 3600.0,
+        time_func: Callable[[], float] = time.monotonic,
+    ):
+        if not pairs:
+            raise ValueError("at least one BestTime key pair is required")
+        if strategy not in self.STRATEGIES:
+            raise ValueError(
+                f"unknown BestTime key rotation {strategy!r}; expected one of {self.STRATEGIES}"
+            )
+        self.pairs = list(pairs)
+        self.strategy = strategy
+        self.exhausted_cooldown_seconds = exhausted_cooldown_seconds
+        self._time = time_func
+        self._cursor = 0
+        self._exhausted_until: dict[int, float] = {}
+        # The pair of the enclosing pinned() block, per task.
+        self._pinned: ContextVar[Optional[BestTimeKeyPair]] = ContextVar(
+            "besttime_pinned_pair", default=None
+        )
+
+    def _available(self, index: int) -> bool:
+        until = self._exhausted_until.get(index)
+        return until is None or self._time() >= until
+
+    @contextmanager
+    def pinned(self) -> Iterator[BestTimeKeyPair]:
+        """Send every call of the block (in this task) with one pair; a nested
+        block keeps the outer pair."""
+        pair = self._pinned.get()
+        if pair is not None:
+            yield pair
+            return
+        pair = self.pick()
+        token = self._pinned.set(pair)
+        try:
+            yield pair
+        finally:
+            self._pinned.reset(token)
+
+    def is_pinned(self) -> bool:
+        return self._pinned.get() is not None
+
+    def pick(self) -> BestTimeKeyPair:
+        """The pair for the next send (the pinned one inside pinned())."""
+        pinned = self._pinned.get()
+        if pinned is not None:
+            return pinned
+        n = len(self.pairs)
+        start = self._cursor if self.strategy == self.ROUND_ROBIN else 0
+        for offset in range(n):
+            index = (start + offset) % n
+            if self._available(index):
+                if self.strategy == self.ROUND_ROBIN:
+                    self._cursor = (index + 1) % n
+                return self.pairs[index]
+        return self.pairs[min(self._exhausted_until, key=self._exhausted_until.get)]
+
+    def mark_exhausted(self, pair: BestTimeKeyPair) -> bool:
+        """Take `pair` out of rotation for the cooldown; True when another
+        pair is still available to retry with."""
+        index = self.pairs.index(pair)
+        self._exhausted_until[index] = self._time() + self.exhausted_cooldown_seconds
+        BESTTIME_KEY_ROTATIONS_TOTAL.labels(reason="quota_exceeded").inc()
+        others = any(self._available(i) for i in range(len(self.pairs)) if i != index)
+        logger.warning(
+            f"[BestTimeAPIClient] key {pair.label} out of credits; skipped for "
+            f"{self.exhausted_cooldown_seconds:.0f}s"
+            + ("" if others else " (no other key available)")
+        )
+        return others
+
+    def snapshot(self) -> list[dict]:
+        """Per-pair state (labels only, never the keys)."""
+        return [
+            {"key": pair.label, "available": self._available(i)}
+            for i, pair in enumerate(self.pairs)
+        ]
+
+
+def pinned_key_pair(client) -> ContextManager:
+    """``client.keys.pinned()``, or a no-op for stand-ins of the client
+    without a key ring (tests, replays)."""
+    keys = getattr(client, "keys", None)
+    return keys.pinned() if isinstance(keys, _KeyRing) else nullcontext()
+
+
+def _with_keys(params: Optional[dict], pair: BestTimeKeyPair) -> Optional[dict]:
+    """`params` with the API keys it carries swapped for `pair`'s."""
+    if not params:
+        return params
+    out = dict(params)
+    if "api_key_private" in out:
+        out["api_key_private"] = pair.private
+    if "api_key_public" in out:
+        out["api_key_public"] = pair.public
+    return out
+
+
+def _is_quota_answer(response: httpx.Response) -> bool:
+    """A 4xx whose body says the key's credits/quota are used up."""
+    if not 400 <= response.status_code < 500:
+        return False
+    try:
+        body = response.json()
+    except Exception:
+        body = None
+    message = body.get("message") if isinstance(body, dict) else None
+    return (
+        _classify_error(response.status_code, message if isinstance(message, str) else None)
+        == BestTimeAPIError.QUOTA_EXCEEDED
+    )
+
+
+class _TokenBucket:
+    """Client-wide token bucket pacing every BestTime send.
+
+    Refills at ``rate`` tokens/second up to ``burst``; each send takes one
+    token, waiting for the refill when the bucket is empty. Shared by every
+    job using the client (live, weekly, discovery, admin adds), so a live
+    refresh over hundreds of venues is spread out instead of bursting into
+    BestTime's quota. ``rate`` <= 0 disables it. Clock and sleep are injectable
+    so tests never sleep for real.
+    """
+
+    def __init__(
+        self,
+        rate: float,
+        burst: int,
+        time_func: Callable[[], float] = time.monotonic,
+        sleep_func: Callable[[float], "asyncio.Future"] = asyncio.sleep,
+    ):
+        self.rate = rate
+        self.burst = max(1, burst)
+        self._time = time_func
+        self._sleep = sleep_func
+        self._tokens = float(self.burst)
+        self._updated = time_func()
+        self._lock = asyncio.Lock()
+
+    async def acquire(self) -> float:
+        """Take one token; returns the seconds waited."""
+        if self.rate <= 0:
+            return 0.0
+        async with self._lock:
+            now = self._time()
+            self._tokens = min(self.burst, self._tokens + (now - self._updated) * self.rate)
+            self._updated = now
+            self._tokens -= 1
+            # A negative balance reserves this caller's slot; it sleeps outside
+            # the lock so later callers reserve their own (later) slots meanwhile.
+            wait = -self._tokens / self.rate if self._tokens < 0 else 0.0
+        if wait > 0:
+            BESTTIME_THROTTLE_WAIT_SECONDS_TOTAL.inc(wait)
+            await self._sleep(wait)
+        return wait
+
+
 class BestTimeAPIClient:
     """Async HTTP client for BestTime API."""
 
@@ -143,6 +584,20 @@ class BestTimeAPIClient:
         search_rate_per_minute: int = 30,
         search_rate_per_hour: int = 300,
         rate_max_wait_seconds: float = 75.0,
+        user_agent: Optional[str] = None,
+        extra_headers: Optional[dict[str, str]] = None,
+        proxy_url: Optional[str] = None,
+        retry_policy: Optional[RetryPolicy] = None,
+        rate_per_second: float = 0.0,
+        rate_burst: int = 10,
+        circuit_failure_threshold: int = 0,
+        circuit_cooldown_seconds: float = 60.0,
+        mode: str = "live",
+        fixtures_dir: str = "",
+        extra_key_pairs: Optional[list[tuple[str, str]]] = None,
+        key_rotation: str = _KeyRing.FAILOVER,
+        key_exhausted_cooldown_seconds: float = 3600.0,
+        strict_decoding: bool = False,
     ):
         """Initialize BestTime API client.
 
@@ -160,41 +615,101 @@ class BestTimeAPIClient:
                 calls client-side. <=0 disables that window.
             rate_max_wait_seconds: longest total pacing/429 wait per call before
                 failing fast with BestTimeRateLimitedError.
+            user_agent: User-Agent sent on every call (empty/None uses
+                ``cs-server/<version>``).
+            extra_headers: extra headers sent on every call (e.g. a corporate
+                egress tag). Cannot override User-Agent; use ``user_agent``.
+            proxy_url: outbound proxy for all BestTime traffic (e.g.
+                "http://proxy.internal:3128"); empty/None connects directly.
+            retry_policy: retry of transient 429/5xx answers (None = the
+                RetryPolicy defaults). The create retries 429 only, since a 5xx
+                there may already have created the venue.
+            rate_per_second / rate_burst: client-wide token bucket over every
+                send, shared by all jobs using this client (<=0 disables).
+            circuit_failure_threshold / circuit_cooldown_seconds: open the
+                circuit breaker after this many consecutive failures and fail
+                fast for the cooldown (threshold <=0 disables).
+            mode / fixtures_dir: "live", or "record" / "replay" BestTime
+                responses as JSON fixtures in fixtures_dir (see
+                app/api/besttime_recorder.py).
+            extra_key_pairs: more (public, private) key pairs to send with
+                besides api_key_public/api_key_private.
+            key_rotation / key_exhausted_cooldown_seconds: "failover" (next
+                pair once one reports its credits used up) or "round_robin"
+                (every send on the next pair); a pair out of credits is
+                skipped for the cooldown.
+            strict_decoding: reject response bodies with fields the models do
+                not declare or without fields they expect, instead of only
+                counting them (app/api/besttime_decoding.py).
         """
         self.base_url = base_url.rstrip("/")
         self.api_key_public = api_key_public
         self.api_key_private = api_key_private
+        self.keys = _KeyRing(
+            [BestTimeKeyPair(api_key_public, api_key_private)]
+            + [BestTimeKeyPair(pub, priv) for pub, priv in extra_key_pairs or []],
+            strategy=key_rotation,
+            exhausted_cooldown_seconds=key_exhausted_cooldown_seconds,
+        )
         self.timeout = timeout
         self.add_venue_timeout = add_venue_timeout
         self.rate_max_wait_seconds = rate_max_wait_seconds
+        self.retry_policy = retry_policy or RetryPolicy()
+        self._throttle = _TokenBucket(rate_per_second, rate_burst)
+        # Optional (endpoint, credits) callback counting credit usage
+        # (BestTimeQuotaService.record); set via set_usage_recorder.
+        self.usage_recorder: Optional[Callable[[str, int], None]] = None
+        self.circuit = _CircuitBreaker(circuit_failure_threshold, circuit_cooldown_seconds)
+        self.decoder = BestTimeDecoder(strict=strict_decoding)
         self._search_limiter = _SearchRateLimiter(
             per_minute=search_rate_per_minute,
             per_hour=search_rate_per_hour,
             max_wait_seconds=rate_max_wait_seconds,
         )
 
+        headers = dict(extra_headers or {})
+        headers["User-Agent"] = user_agent or DEFAULT_USER_AGENT
+
         # Create async HTTP client with connection pooling
-        self.client = httpx.AsyncClient(
-            timeout=timeout,
-            limits=httpx.Limits(max_keepalive_connections=10, max_connections=20),
-        )
+        limits = httpx.Limits(max_keepalive_connections=10, max_connections=20)
+        transport = build_transport(mode, fixtures_dir, proxy_url=proxy_url, limits=limits)
+        if transport is None:
+            self.client = httpx.AsyncClient(
+                timeout=timeout,
+                limits=limits,
+                headers=headers,
+                proxy=proxy_url or None,
+            )
+        else:
+            # Pool limits and proxy live on the transport (record mode's inner one).
+            self.client = httpx.AsyncClient(timeout=timeout, headers=headers, transport=transport)
+
+    def set_usage_recorder(self, recorder: Optional[Callable[[str, int], None]]) -> None:
+        """Wire the callback that counts credits per successful call."""
+        self.usage_recorder = recorder
+
+    def _record_credits(self, endpoint: str, credits: int = 1) -> None:
+        # The inventory listing (/venues) is free.
+        if self.usage_recorder is None or endpoint == "/venues":
+            return
+        try:
+            self.usage_recorder(endpoint, credits)
+        except Exception as e:
+            logger.warning(f"[BestTimeAPIClient] credit usage recording failed: {e}")
+
+    def set_rate_limit(self, rate_per_second: Optional[float] = None, burst: Optional[int] = None) -> None:
+        """Change the client-wide token bucket at runtime (hot reload); None
+        keeps that part. Callers already waiting keep their reserved slots."""
+        if rate_per_second is not None:
+            self._throttle.rate = rate_per_second
+        if burst is not None:
+            self._throttle.burst = max(1, burst)
+            self._throttle._tokens = min(self._throttle._tokens, self._throttle.burst)
 
     async def close(self):
         """Close the HTTP client and clean up resources."""
         await self.client.aclose()
 
-    @staticmethod
-    def _retry_after_seconds(response: httpx.Response, attempt: int) -> float:
-        """Wait before retrying a 429: honor Retry-After when parseable, else
-        exponential backoff (1s, 2s, ...)."""
-        header = response.headers.get("retry-after")
-        if header is not None:
-            try:
-                return max(0.0, float(header))
-            except ValueError:
-                pass
-        return float(2**attempt)
-
     async def _send_with_retry(
         self,
         method: str,
@@ -204,32 +719,51 @@ class BestTimeAPIClient:
         endpoint: str,
         json_body: Optional[dict] = None,
         timeout: Optional[float] = None,
-        retry_429: bool = False,
+        retry_statuses: Optional[frozenset[int]] = None,
         stop_retry_on: Optional[Callable[[httpx.Response], bool]] = None,
         retry_log_suffix: str = "",
+        pair: Optional[BestTimeKeyPair] = None,
     ) -> httpx.Response:
-        """Send the request, applying the bounded, Retry-After-aware 429 retry.
+        """Send the request, applying the bounded, Retry-After-aware retry of
+        self.retry_policy.
+
+        Each send carries the API keys of ``pair``, else of the pair self.keys
+        picks (replacing the ones in ``params``); a quota answer on one pair
+        is resent once on each other available pair before the normal
+        handling, unless the pair is fixed (``pair`` or a pinned() block).
 
-        The single retry loop shared by `_request` (search-family reads, via
-        ``retry_429``) and `add_venue_to_account` (the create, via ``timeout`` +
-        ``stop_retry_on``), so the two can no longer drift. Returns the final
-        `httpx.Response`; the caller owns all response parsing, ``raise_for_status``,
-        and success/error result metrics + logs. Transport errors from
-        ``client.request`` propagate to the caller's own except blocks unchanged.
+        The single retry loop shared by `_request` (every read) and
+        `add_venue_to_account` (the create, via ``timeout`` + ``stop_retry_on``),
+        so the two can no longer drift. Returns the final `httpx.Response`; the
+        caller owns all response parsing, ``raise_for_status``, and success/error
+        result metrics + logs. Transport errors from ``client.request`` propagate
+        to the caller's own except blocks unchanged.
 
         Args:
             timeout: per-call timeout passed to ``client.request`` (omitted when
                 None so read calls inherit the client-wide default).
-            retry_429: retry HTTP 429 answers (bounded, Retry-After-aware).
-            stop_retry_on: predicate on a 429 response that, when true, breaks the
-                loop and surfaces that response as terminal (never retried) — the
-                monthly-cap 429 for the create.
+            retry_statuses: statuses to retry; None means the policy's
+                ``retry_on_status``, or 429 only for METERED_ENDPOINTS.
+            stop_retry_on: predicate on a retryable response that, when true,
+                breaks the loop and surfaces that response as terminal (never
+                retried) — the monthly-cap 429 for the create. Quota answers
+                (_is_quota_answer) are terminal on every call.
             retry_log_suffix: appended after ``<method> <endpoint>`` in the retry
                 warning (e.g. " (create)"), preserving the original messages.
+            pair: the key pair to send every attempt with (a paginated read
+                stays on one account).
 
         Raises:
-            BestTimeRateLimitedError: bounded 429 retries were exhausted.
+            BestTimeRateLimitedError: bounded 429 retries were exhausted. An
+                exhausted 5xx is returned for the caller's ``raise_for_status``.
+            BestTimeCircuitOpenError: the circuit breaker is open; nothing sent.
         """
+        policy = self.retry_policy
+        if retry_statuses is None:
+            retry_statuses = (
+                METERED_RETRY_STATUSES if endpoint in METERED_ENDPOINTS
+                else policy.retry_on_status
+            )
         request_kwargs: dict = {
             "method": method,
             "url": url,
@@ -240,66 +774,117 @@ class BestTimeAPIClient:
         if timeout is not None:
             request_kwargs["timeout"] = timeout
 
-        attempt = 0
-        waited = 0.0
-        while True:
-            response = await self.client.request(**request_kwargs)
-            if not (retry_429 and response.status_code == 429):
-                break
-            # A 429 the predicate claims as terminal (e.g. the monthly-cap body)
-            # flows to the caller's normal parse path — never retried.
-            if stop_retry_on is not None and stop_retry_on(response):
-                break
-            wait = self._retry_after_seconds(response, attempt)
-            if attempt >= 2 or waited + wait > self.rate_max_wait_seconds:
-                BESTTIME_SEARCH_RATE_LIMIT_TOTAL.labels(
-                    endpoint=endpoint, event="rejected"
-                ).inc()
-                BESTTIME_API_CALLS_TOTAL.labels(endpoint=endpoint, status="error").inc()
-                raise BestTimeRateLimitedError(
-                    f"BestTime kept answering 429 on {method} {endpoint}"
+        fixed_pair = pair
+        rotate = fixed_pair is None and not self.keys.is_pinned()
+        # Open circuit: fail fast without sending (BestTimeCircuitOpenError).
+        self.circuit.before_call(endpoint)
+        try:
+            attempt = 0
+            waited = 0.0
+            rotations = 0
+            while True:
+                await self._throttle.acquire()
+                pair = fixed_pair or self.keys.pick()
+                request_kwargs["params"] = _with_keys(params, pair)
+                response = await self.client.request(**request_kwargs)
+                status = response.status_code
+                # Out of credits on this key: resend on the next one (bounded by
+                # the number of pairs; not a retry attempt).
+                if len(self.keys.pairs) > 1 and _is_quota_answer(response):
+                    if (
+                        self.keys.mark_exhausted(pair)
+                        and rotate
+                        and rotations < len(self.keys.pairs) - 1
+                    ):
+                        rotations += 1
+                        continue
+                if status not in retry_statuses:
+                    break
+                # A used-up quota, or a response the predicate claims as terminal
+                # (e.g. the monthly-cap 429 body), flows to the caller's normal
+                # parse path — never retried.
+                if _is_quota_answer(response) or (
+                    stop_retry_on is not None and stop_retry_on(response)
+                ):
+                    break
+                wait = policy.wait_seconds(response, attempt)
+                exhausted = attempt + 1 >= policy.max_attempts
+                if exhausted or waited + wait > self.rate_max_wait_seconds:
+                    if status != 429:
+                        break
+                    BESTTIME_SEARCH_RATE_LIMIT_TOTAL.labels(
+                        endpoint=endpoint, event="rejected"
+                    ).inc()
+                    BESTTIME_API_CALLS_TOTAL.labels(endpoint=endpoint, status="error").inc()
+                    raise BestTimeRateLimitedError(
+                        f"BestTime kept answering 429 on {method} {endpoint}"
+                    )
+                if status == 429:
+                    BESTTIME_SEARCH_RATE_LIMIT_TOTAL.labels(
+                        endpoint=endpoint, event="retry_429"
+                    ).inc()
+                BESTTIME_API_RETRIES_TOTAL.labels(endpoint=endpoint, status_code=str(status)).inc()
+                logger.warning(
+                    f"[BestTimeAPIClient] {status} on {method} {endpoint}{retry_log_suffix}; "
+                    f"retrying in {wait:.1f}s (attempt {attempt + 1}/{policy.max_attempts})"
                 )
-            BESTTIME_SEARCH_RATE_LIMIT_TOTAL.labels(
-                endpoint=endpoint, event="retry_429"
-            ).inc()
-            logger.warning(
-                f"[BestTimeAPIClient] 429 on {method} {endpoint}{retry_log_suffix}; "
-                f"retrying in {wait:.1f}s (attempt {attempt + 1}/3)"
-            )
-            await asyncio.sleep(wait)
-            waited += wait
-            attempt += 1
+                await asyncio.sleep(wait)
+                waited += wait
+                attempt += 1
+        except BestTimeRateLimitedError:
+            self.circuit.record_success()  # answering, just throttling us
+            raise
+        except httpx.TransportError:
+            self.circuit.record_failure()
+            raise
+        except BaseException:  # cancelled, or a non-transport bug: no verdict
+            self.circuit.abandon_trial()
+            raise
 
+        if response.status_code >= 500:
+            self.circuit.record_failure()
+        else:
+            self.circuit.record_success()
         return response
 
+    @traced("BestTimeAPIClient.request")
     async def _request(
         self,
         method: str,
         endpoint: str,
         params: Optional[dict] = None,
         json_body: Optional[dict] = None,
-        retry_429: bool = False,
+        timeout: Optional[float] = None,
+        pair: Optional[BestTimeKeyPair] = None,
     ) -> dict:
         """Make an HTTP request to the BestTime API.
 
+        Transient answers (429/5xx by default) are retried under
+        self.retry_policy before the status is raised. Cancelling the awaiting
+        task (e.g. a refresh job on shutdown) aborts the in-flight request and
+        any retry wait.
+
         Args:
             method: HTTP method (GET, POST, etc.)
             endpoint: API endpoint path
             params: Query parameters
             json_body: JSON request body
-            retry_429: retry HTTP 429 answers (bounded, Retry-After-aware) —
-                used by the venue-search-family calls, which BestTime rate
-                limits at 30/min and 300/hour.
+            timeout: per-call timeout in seconds; None uses the client-wide one
+            pair: key pair to send with; None lets self.keys pick
 
         Returns:
             JSON response as dict
 
         Raises:
-            httpx.HTTPStatusError: If response status is not 2xx
+            BestTimeAPIError: If response status is not 2xx (an
+                httpx.HTTPStatusError carrying the parsed, classified body)
             httpx.RequestError: If request fails
-            BestTimeRateLimitedError: retry_429 exhausted its bounded retries
+            BestTimeRateLimitedError: 429 retries were exhausted
         """
         url = f"{self.base_url}{endpoint}"
+        span = trace.get_current_span()
+        span.set_attribute("http.request.method", method)
+        span.set_attribute("besttime.endpoint", endpoint)
 
         logger.debug(f"[BestTimeAPIClient] {method} {url} params={params} body={json_body}")
 
@@ -312,7 +897,8 @@ class BestTimeAPIClient:
                 params=params,
                 endpoint=endpoint,
                 json_body=json_body,
-                retry_429=retry_429,
+                timeout=timeout,
+                pair=pair,
             )
 
             logger.debug(f"[BestTimeAPIClient] Response status: {response.status_code}")
@@ -326,6 +912,11 @@ class BestTimeAPIClient:
             duration = time.perf_counter() - start_time
             BESTTIME_API_CALL_DURATION_SECONDS.labels(endpoint=endpoint).observe(duration)
             BESTTIME_API_CALLS_TOTAL.labels(endpoint=endpoint, status="success").inc()
+            # /venues/filter draws a credit per venue returned.
+            venues = response_json.get("venues") if isinstance(response_json, dict) else None
+            self._record_credits(
+                endpoint, max(1, len(venues)) if isinstance(venues, list) else 1
+            )
 
             return response_json
 
@@ -334,8 +925,9 @@ class BestTimeAPIClient:
             BESTTIME_API_CALL_DURATION_SECONDS.labels(endpoint=endpoint).observe(duration)
             BESTTIME_API_CALLS_TOTAL.labels(endpoint=endpoint, status="error").inc()
             BESTTIME_API_ERRORS_TOTAL.labels(endpoint=endpoint, error_type="http_error").inc()
-            logger.error(f"[BestTimeAPIClient] HTTP error on {method} {endpoint}: {e}")
-            raise
+            error = BestTimeAPIError.from_status_error(e, endpoint)
+            logger.error(f"[BestTimeAPIClient] HTTP error on {method}: {error}")
+            raise error from e
         except httpx.TimeoutException as e:
             duration = time.perf_counter() - start_time
             BESTTIME_API_CALL_DURATION_SECONDS.labels(endpoint=endpoint).observe(duration)
@@ -351,13 +943,16 @@ class BestTimeAPIClient:
             logger.error(f"[BestTimeAPIClient] Request error on {method} {endpoint}: {e}")
             raise
 
-    async def venue_filter(self, params: VenueFilterParams) -> VenueFilterResponse:
+    async def venue_filter(
+        self, params: VenueFilterParams, timeout: Optional[float] = None
+    ) -> VenueFilterResponse:
         """Call GET /venues/filter with given parameters.
 
         This is the preferred endpoint for venue discovery.
 
         Args:
             params: VenueFilterParams object with filter criteria
+            timeout: per-call timeout in seconds (None = client default)
 
         Returns:
             VenueFilterResponse with matching venues
@@ -371,7 +966,7 @@ class BestTimeAPIClient:
         await self._search_limiter.acquire("/venues/filter")
         try:
             response_data = await self._request(
-                "GET", "/venues/filter", params=query_params, retry_429=True
+                "GET", "/venues/filter", params=query_params, timeout=timeout
             )
         except httpx.HTTPStatusError as e:
             # BestTime answers a ZERO-MATCH filter with HTTP 404 and a
@@ -402,7 +997,7 @@ class BestTimeAPIClient:
                 venues_n=0,
             )
 
-        response = VenueFilterResponse(**response_data)
+        response = self.decoder.decode(VenueFilterResponse, response_data, "/venues/filter")
         logger.info(
             f"[BestTimeAPIClient] venue_filter success: status={response.status}, "
             f"venues_n={response.venues_n}"
@@ -415,6 +1010,7 @@ class BestTimeAPIClient:
         venue_id: Optional[str] = None,
         venue_name: Optional[str] = None,
         venue_address: Optional[str] = None,
+        timeout: Optional[float] = None,
     ) -> LiveForecastResponse:
         """Retrieve live busyness forecast for a venue.
 
@@ -422,6 +1018,7 @@ class BestTimeAPIClient:
             venue_id: Venue ID (preferred)
             venue_name: Venue name (required if venue_id not provided)
             venue_address: Venue address (required if venue_id not provided)
+            timeout: per-call timeout in seconds (None = client default)
 
         Returns:
             LiveForecastResponse with live busyness data
@@ -444,16 +1041,19 @@ class BestTimeAPIClient:
 
         # Construct endpoint with query params
         response_data = await self._request(
-            "POST", "/forecasts/live", params=query_params
+            "POST", "/forecasts/live", params=query_params, timeout=timeout
         )
 
-        return LiveForecastResponse(**response_data)
+        return self.decoder.decode(LiveForecastResponse, response_data, "/forecasts/live")
 
-    async def get_week_raw_forecast(self, venue_id: str) -> WeekRawResponse:
+    async def get_week_raw_forecast(
+        self, venue_id: str, timeout: Optional[float] = None
+    ) -> WeekRawResponse:
         """Retrieve full weekly raw forecast for a venue.
 
         Args:
             venue_id: Venue identifier
+            timeout: per-call timeout in seconds (None = client default)
 
         Returns:
             WeekRawResponse with 7 days of hourly forecast data
@@ -470,10 +1070,80 @@ class BestTimeAPIClient:
         }
 
         response_data = await self._request(
-            "GET", "/forecasts/week/raw2", params=query_params
+            "GET", "/forecasts/week/raw2", params=query_params, timeout=timeout
+        )
+
+        return self.decoder.decode(WeekRawResponse, response_data, "/forecasts/week/raw2")
+
+    async def get_day_forecast(
+        self, venue_id: str, day_int: int, timeout: Optional[float] = None
+    ) -> DayForecastResponse:
+        """Retrieve the forecast of one day of the week for a venue.
+
+        Args:
+            venue_id: Venue identifier
+            day_int: 0=Monday to 6=Sunday
+            timeout: per-call timeout in seconds (None = client default)
+
+        Returns:
+            DayForecastResponse with the day's hourly raw values and analysis
+
+        Raises:
+            ValueError: If venue_id is empty or day_int is out of range
+        """
+        if not venue_id:
+            raise ValueError("venue_id must be provided")
+        if not 0 <= day_int <= 6:
+            raise ValueError("day_int must be between 0 and 6")
+
+        query_params = {
+            "api_key_public": self.api_key_public,
+            "venue_id": venue_id,
+            "day_int": str(day_int),
+        }
+
+        response_data = await self._request(
+            "GET", "/forecasts/day", params=query_params, timeout=timeout
+        )
+
+        return self.decoder.decode(DayForecastResponse, response_data, "/forecasts/day")
+
+    async def get_hour_forecast(
+        self, venue_id: str, day_int: int, hour: int, timeout: Optional[float] = None
+    ) -> HourForecastResponse:
+        """Retrieve the forecast of one hour of one day of the week for a venue.
+
+        Args:
+            venue_id: Venue identifier
+            day_int: 0=Monday to 6=Sunday
+            hour: Hour of the day, 0-23
+            timeout: per-call timeout in seconds (None = client default)
+
+        Returns:
+            HourForecastResponse with the hour's intensity
+
+        Raises:
+            ValueError: If venue_id is empty or day_int/hour is out of range
+        """
+        if not venue_id:
+            raise ValueError("venue_id must be provided")
+        if not 0 <= day_int <= 6:
+            raise ValueError("day_int must be between 0 and 6")
+        if not 0 <= hour <= 23:
+            raise ValueError("hour must be between 0 and 23")
+
+        query_params = {
+            "api_key_public": self.api_key_public,
+            "venue_id": venue_id,
+            "day_int": str(day_int),
+            "hour": str(hour),
+        }
+
+        response_data = await self._request(
+            "GET", "/forecasts/hour", params=query_params, timeout=timeout
         )
 
-        return WeekRawResponse(**response_data)
+        return self.decoder.decode(HourForecastResponse, response_data, "/forecasts/hour")
 
     async def add_venue_to_account(
         self, venue_name: str, venue_address: str
@@ -503,8 +1173,9 @@ class BestTimeAPIClient:
         await self._search_limiter.acquire(endpoint)
         start_time = time.perf_counter()
         try:
-            # Same bounded 429 retry as the reads (shared _send_with_retry), plus
-            # two create-specific deltas: the per-call add-venue timeout, and the
+            # Same bounded retry as the reads (shared _send_with_retry), with
+            # three create-specific deltas: 429 only (a 5xx may already have
+            # created the venue), the per-call add-venue timeout, and the
             # monthly-cap 429 treated as terminal (never retried) so it flows to
             # the parse path below and the handler can surface the cap legibly.
             response = await self._send_with_retry(
@@ -513,7 +1184,7 @@ class BestTimeAPIClient:
                 params=query_params,
                 endpoint=endpoint,
                 timeout=self.add_venue_timeout,
-                retry_429=True,
+                retry_statuses=METERED_RETRY_STATUSES,
                 stop_retry_on=_looks_like_monthly_cap_body,
                 retry_log_suffix=" (create)",
             )
@@ -537,6 +1208,9 @@ class BestTimeAPIClient:
                 ).inc()
                 raise
 
+            # Analysis is tolerant by design, so drift is only counted here,
+            # never rejected, whatever the decoding mode.
+            self.decoder.note_unknown_fields(NewVenueResponse, body, endpoint)
             try:
                 parsed = NewVenueResponse.model_validate(body)
             except ValidationError as e:
@@ -560,6 +1234,7 @@ class BestTimeAPIClient:
                 ) from e
             if parsed.is_ok():
                 BESTTIME_API_CALLS_TOTAL.labels(endpoint=endpoint, status="success").inc()
+                self._record_credits(endpoint)
             else:
                 BESTTIME_API_CALLS_TOTAL.labels(endpoint=endpoint, status="error").inc()
                 logger.warning(
@@ -595,9 +1270,11 @@ class BestTimeAPIClient:
 
         This endpoint does not consume BestTime credits — it just enumerates
         venues already registered to the API key. Yields one venue at a
-        time; the caller decides how to batch or filter.
+        time; the caller decides how to batch or filter. Every page is read
+        with the same key pair, so they all list one account.
         """
         endpoint = "/venues"
+        pair = self.keys.pick()
         page = 0
         while True:
             params = {
@@ -606,7 +1283,7 @@ class BestTimeAPIClient:
                 "page": page,
             }
             try:
-                data = await self._request("GET", endpoint, params=params)
+                data = await self._request("GET", endpoint, params=params, pair=pair)
             except Exception as e:
                 logger.error(
                     f"[BestTimeAPIClient] list_account_inventory page={page} failed: {e}"
@@ -616,7 +1293,7 @@ class BestTimeAPIClient:
                 return
             for row in data:
                 try:
-                    yield AccountInventoryVenue.model_validate(row)
+                    yield self.decoder.decode(AccountInventoryVenue, row, endpoint)
                 except Exception as e:
                     logger.warning(
                         f"[BestTimeAPIClient] Skipping bad inventory row on page "
@@ -0,0 +1,185 @@
+"""Decoding BestTime response bodies into models, with schema-drift reporting.
+
+The response models ignore fields they do not declare and fill absent ones
+with defaults, so an upstream rename (`venue_lng` -> `venue_lon`) silently
+turns into zero-valued data. Every body decoded here is first compared with
+its model:
+
+- unknown fields: keys the model (or a nested model) does not declare, by
+  dotted path with list indexes collapsed ("venues[].venue_score"). Always
+  counted (BESTTIME_UNKNOWN_FIELDS_TOTAL) and kept per endpoint for
+  GET /admin/besttime/schema-drift; logged once per process per field;
+- missing fields: declared fields absent from the body, except those whose
+  default is None or an empty container (BestTime omits those in practice).
+
+Strict mode (settings.besttime_strict_decoding) rejects a body with unknown or
+missing fields. Either mode rejects a body that fails validation. A rejection
+raises BestTimeSchemaError listing every problem by field, instead of
+pydantic's first-error summary.
+"""
+from __future__ import annotations
+
+import logging
+import time
+import types
+import typing
+from collections import Counter, deque
+from typing import Any, TypeVar, Union
+
+from pydantic import BaseModel, ValidationError
+
+from app.metrics import BESTTIME_SCHEMA_ERRORS_TOTAL, BESTTIME_UNKNOWN_FIELDS_TOTAL
+
+logger = logging.getLogger(__name__)
+
+M = TypeVar("M", bound=BaseModel)
+
+# Rejections kept for the admin endpoint.
+MAX_RECENT_ERRORS = 50
+# Problems listed per rejection (the count is always complete).
+MAX_PROBLEMS = 20
+
+
+class BestTimeSchemaError(ValueError):
+    """A BestTime body does not match its response model."""
+
+    def __init__(self, endpoint: str, problems: list[str]):
+        self.endpoint = endpoint
+        self.problems = problems
+        shown = "; ".join(problems[:MAX_PROBLEMS])
+        more = f" (+{len(problems) - MAX_PROBLEMS} more)" if len(problems) > MAX_PROBLEMS else ""
+        super().__init__(f"{endpoint} response does not match its schema: {shown}{more}")
+
+
+def _models_in(annotation: Any) -> tuple[list[type[BaseModel]], list[Any]]:
+    """(model classes, list item annotations) an annotation can hold, looking
+    through Optional / Union."""
+    origin = typing.get_origin(annotation)
+    if origin in (Union, types.UnionType):
+        models, items = [], []
+        for arg in typing.get_args(annotation):
+            m, i = _models_in(arg)
+            models += m
+            items += i
+        return models, items
+    if origin is list:
+        args = typing.get_args(annotation)
+        return [], [args[0] if args else Any]
+    if isinstance(annotation, type) and issubclass(annotation, BaseModel):
+        return [annotation], []
+    return [], []
+
+
+def _keys(field_name: str, field) -> set[str]:
+    keys = {field_name}
+    if field.alias:
+        keys.add(field.alias)
+    if isinstance(field.validation_alias, str):
+        keys.add(field.validation_alias)
+    return keys
+
+
+def _optional_when_absent(field) -> bool:
+    """Absent is fine for a None default or a default container (list / dict
+    factories); a required field or a zero default ("" / 0 / False) is not,
+    the latter would hide a dropped field."""
+    return field.default_factory is not None or field.default is None
+
+
+def _walk(annotation: Any, value: Any, path: str, unknown: list[str], missing: list[str]) -> None:
+    models, items = _models_in(annotation)
+    if isinstance(value, dict) and models:
+        model = models[0]
+        known: set[str] = set()
+        for name, field in model.model_fields.items():
+            keys = _keys(name, field)
+            known |= keys
+            present = next((k for k in keys if k in value), None)
+            if present is None:
+                if not _optional_when_absent(field):
+                    missing.append(f"{path}{name}")
+                continue
+            _walk(field.annotation, value[present], f"{path}{name}.", unknown, missing)
+        unknown.extend(f"{path}{key}" for key in value if key not in known)
+    elif isinstance(value, list) and items:
+        prefix = f"{path[:-1]}[]." if path else "[]."
+        for element in value:
+            _walk(items[0], element, prefix, unknown, missing)
+
+
+def inspect_payload(model_cls: type[BaseModel], data: Any) -> tuple[list[str], list[str]]:
+    """(unknown field paths, missing field paths) of `data` against `model_cls`,
+    each path listed once."""
+    unknown: list[str] = []
+    missing: list[str] = []
+    _walk(model_cls, data, "", unknown, missing)
+    return list(dict.fromkeys(unknown)), list(dict.fromkeys(missing))
+
+
+def validation_problems(error: ValidationError) -> list[str]:
+    """One "field: message" line per pydantic error."""
+    problems = []
+    for err in error.errors():
+        loc = "".join(f"[{p}]" if isinstance(p, int) else f".{p}" for p in err["loc"]).lstrip(".")
+        problems.append(f"{loc or '<body>'}: {err['msg']}")
+    return problems
+
+
+class BestTimeDecoder:
+    """Decodes BestTime bodies and keeps the drift seen (module docstring)."""
+
+    def __init__(self, strict: bool = False):
+        self.strict = strict
+        self.unknown_fields: dict[str, Counter] = {}
+        self.recent_errors: deque = deque(maxlen=MAX_RECENT_ERRORS)
+
+    def note_unknown_fields(
+        self, model_cls: type[BaseModel], data: Any, endpoint: str
+    ) -> tuple[list[str], list[str]]:
+        """Count the unknown fields of `data`; returns (unknown, missing)."""
+        unknown, missing = inspect_payload(model_cls, data)
+        seen = self.unknown_fields.setdefault(endpoint, Counter())
+        for path in unknown:
+            if path not in seen:
+                logger.warning(
+                    f"[BestTimeDecoder] {endpoint} returned a field the model does not know: {path}"
+                )
+            seen[path] += 1
+            BESTTIME_UNKNOWN_FIELDS_TOTAL.labels(endpoint=endpoint, field=path).inc()
+        return unknown, missing
+
+    def decode(self, model_cls: type[M], data: Any, endpoint: str) -> M:
+        """`data` as a `model_cls`.
+
+        Raises:
+            BestTimeSchemaError: invalid body, or (strict) unknown or missing fields
+        """
+        unknown, missing = self.note_unknown_fields(model_cls, data, endpoint)
+        problems = []
+        if self.strict:
+            problems += [f"{path}: unknown field" for path in unknown]
+            problems += [f"{path}: missing" for path in missing]
+        if not problems:
+            try:
+                return model_cls.model_validate(data)
+            except ValidationError as e:
+                problems = validation_problems(e)
+                kind = "invalid"
+        else:
+            kind = "drift"
+        BESTTIME_SCHEMA_ERRORS_TOTAL.labels(endpoint=endpoint, kind=kind).inc()
+        error = BestTimeSchemaError(endpoint, problems)
+        self.recent_errors.appendleft(
+            {"at": time.time(), "endpoint": endpoint, "problems": problems[:MAX_PROBLEMS]}
+        )
+        logger.error(f"[BestTimeDecoder] {error}")
+        raise error
+
+    def snapshot(self) -> dict:
+        return {
+            "strict": self.strict,
+            "unknown_fields": {
+                endpoint: dict(counts.most_common()) for endpoint, counts in self.unknown_fields.items()
+            },
+            "recent_errors": list(self.recent_errors),
+        }
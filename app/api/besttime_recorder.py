@@ -0,0 +1,165 @@
+"""Record / replay of BestTime HTTP exchanges as JSON fixtures.
+
+settings.besttime_mode selects how BestTimeAPIClient talks to BestTime:
+
+- "live" (default): straight to the API;
+- "record": to the API, and every response is also written to
+  settings.besttime_fixtures_dir;
+- "replay": never touches the network; each request is answered from the
+  fixture recorded for it, and a request with no fixture fails with
+  ReplayFixtureMissingError.
+
+A fixture is named after the endpoint and a hash of the request (method,
+query parameters and body, with the API keys left out), e.g.
+`forecasts_live_3f9c0a1b2c4d.json`, so the same call always maps to the same
+file and one directory can hold many shapes of the same endpoint. Fixtures
+store the status, content type and body, and never the keys.
+
+Both modes are httpx transports under the client, so everything above them
+(pacing, retries, the circuit breaker, response parsing) runs unchanged.
+"""
+from __future__ import annotations
+
+import hashlib
+import json
+import logging
+import os
+from typing import Optional
+from urllib.parse import parse_qsl
+
+import httpx
+
+logger = logging.getLogger(__name__)
+
+BESTTIME_MODES = ("live", "record", "replay")
+
+# Never part of a fixture name or file.
+_SECRET_PARAMS = frozenset({"api_key_private", "api_key_public"})
+
+
+class ReplayFixtureMissingError(LookupError):
+    """Replay mode got a request with no recorded fixture."""
+
+
+def _request_shape(request: httpx.Request) -> dict:
+    params = sorted(
+        (k, v) for k, v in parse_qsl(request.url.query.decode(), keep_blank_values=True)
+        if k not in _SECRET_PARAMS
+    )
+    body = request.content.decode("utf-8", errors="replace") if request.content else ""
+    return {"method": request.method, "path": request.url.path, "params": params, "body": body}
+
+
+def fixture_name(request: httpx.Request) -> str:
+    """`<endpoint slug>_<12 hex digits of the request hash>.json`."""
+    shape = _request_shape(request)
+    path = shape["path"]
+    # Drop the base path (/api/v1) so fixtures survive a base URL change.
+    if "/api/v1/" in path:
+        path = path.split("/api/v1/", 1)[1]
+    slug = "_".join(part for part in path.strip("/").split("/") if part) or "root"
+    digest = hashlib.sha256(
+        json.dumps([shape["method"], shape["params"], shape["body"]]).encode()
+    ).hexdigest()[:12]
+    return f"{slug}_{digest}.json"
+
+
+# Headers describing the wire body, wrong for the decoded body handed on.
+_DECODED_BODY_DROPPED_HEADERS = ("content-encoding", "content-length")
+
+
+class RecordingTransport(httpx.AsyncBaseTransport):
+    """Sends through `inner` and writes every response to `directory`."""
+
+    def __init__(self, inner: httpx.AsyncBaseTransport, directory: str) -> None:
+        self.inner = inner
+        self.directory = directory
+        os.makedirs(directory, exist_ok=True)
+
+    async def handle_async_request(self, request: httpx.Request) -> httpx.Response:
+        response = await self.inner.handle_async_request(request)
+        content = await response.aread()
+        try:
+            body = json.loads(content)
+            body_kind = "json"
+        except ValueError:
+            body = content.decode("utf-8", errors="replace")
+            body_kind = "text"
+        shape = _request_shape(request)
+        fixture = {
+            "request": {"method": shape["method"], "path": shape["path"], "params": shape["params"]},
+            "status_code": response.status_code,
+            "content_type": response.headers.get("content-type"),
+            "body_kind": body_kind,
+            "body": body,
+        }
+        path = os.path.join(self.directory, fixture_name(request))
+        try:
+            with open(path, "w", encoding="utf-8") as f:
+                json.dump(fixture, f, ensure_ascii=False, indent=2)
+        except OSError as e:
+            logger.warning(f"[BestTimeRecorder] Failed to write {path}: {e}")
+        else:
+            logger.debug(f"[BestTimeRecorder] Recorded {request.method} {shape['path']} -> {path}")
+        # `content` is already decoded: a content-encoding or the wire length
+        # would make the client decode or size it a second time.
+        headers = [
+            (name, value) for name, value in response.headers.multi_items()
+            if name.lower() not in _DECODED_BODY_DROPPED_HEADERS
+        ]
+        return httpx.Response(
+            response.status_code, headers=headers, content=content, request=request
+        )
+
+    async def aclose(self) -> None:
+        await self.inner.aclose()
+
+
+class ReplayTransport(httpx.AsyncBaseTransport):
+    """Answers every request from the fixture recorded for it."""
+
+    def __init__(self, directory: str) -> None:
+        self.directory = directory
+
+    async def handle_async_request(self, request: httpx.Request) -> httpx.Response:
+        path = os.path.join(self.directory, fixture_name(request))
+        try:
+            with open(path, encoding="utf-8") as f:
+                fixture = json.load(f)
+        except FileNotFoundError:
+            raise ReplayFixtureMissingError(
+                f"no BestTime fixture {path} for {request.method} {request.url.path}"
+            ) from None
+        headers = {}
+        if fixture.get("content_type"):
+            headers["content-type"] = fixture["content_type"]
+        body = fixture.get("body")
+        content = (
+            json.dumps(body).encode() if fixture.get("body_kind", "json") == "json"
+            else str(body).encode()
+        )
+        return httpx.Response(
+            fixture["status_code"], headers=headers, content=content, request=request
+        )
+
+
+def build_transport(
+    mode: str, fixtures_dir: str, proxy_url: Optional[str] = None, limits: Optional[httpx.Limits] = None
+) -> Optional[httpx.AsyncBaseTransport]:
+    """The transport for a BestTime client in `mode` (None = httpx default).
+
+    Raises:
+        ValueError: unknown mode
+    """
+    if mode not in BESTTIME_MODES:
+        raise ValueError(f"unknown besttime_mode {mode!r}; expected one of {BESTTIME_MODES}")
+    if mode == "live":
+        return None
+    if mode == "replay":
+        logger.warning(f"[BestTimeRecorder] Replay mode: BestTime answers come from {fixtures_dir}")
+        return ReplayTransport(fixtures_dir)
+    logger.warning(f"[BestTimeRecorder] Record mode: BestTime responses are written to {fixtures_dir}")
+    inner = httpx.AsyncHTTPTransport(
+        limits=limits or httpx.Limits(), proxy=proxy_url or None
+    )
+    return RecordingTransport(inner, fixtures_dir)
@@ -0,0 +1,117 @@
+"""Firebase Cloud Messaging client (HTTP v1 API).
+
+Sends one notification to one device token
+(POST /v1/projects/{project}/messages:send). FCM delivers to Android directly
+and to iOS through APNs, so one client covers both platforms. Authentication
+is an OAuth2 access token minted from the Firebase service-account JSON
+(google-auth) and refreshed shortly before it expires.
+"""
+import asyncio
+import json
+import logging
+from typing import Callable, Optional
+
+import httpx
+
+logger = logging.getLogger(__name__)
+
+FCM_API_BASE = "https://fcm.googleapis.com/v1"
+FCM_SCOPE = "https://www.googleapis.com/auth/firebase.messaging"
+FCM_ERROR_TYPE = "type.googleapis.com/google.firebase.fcm.v1.FcmError"
+
+
+class FcmUnregisteredError(Exception):
+    """The device token is no longer valid (app uninstalled, token rotated)."""
+
+
+def _fcm_error_code(response: httpx.Response) -> str:
+    """The FcmError `errorCode` of an error response ("UNREGISTERED", ...), or ""."""
+    try:
+        details = ((response.json() or {}).get("error") or {}).get("details") or []
+    except ValueError:
+        return ""
+    for detail in details:
+        if isinstance(detail, dict) and detail.get("@type") == FCM_ERROR_TYPE:
+            return detail.get("errorCode", "")
+    return ""
+
+
+def service_account_project_id(credentials_file: str) -> str:
+    with open(credentials_file) as f:
+        return json.load(f).get("project_id", "")
+
+
+def service_account_token_provider(credentials_file: str) -> Callable[[], str]:
+    """A blocking callable returning a valid access token for FCM_SCOPE."""
+    # Lazy import: only deployments with push notifications need google-auth.
+    from google.auth.transport.requests import Request
+    from google.oauth2 import service_account
+
+    credentials = service_account.Credentials.from_service_account_file(
+        credentials_file, scopes=[FCM_SCOPE]
+    )
+
+    def token() -> str:
+        if not credentials.valid:
+            credentials.refresh(Request())
+        return credentials.token
+
+    return token
+
+
+class FcmClient:
+    """Async client for FCM's HTTP v1 send endpoint."""
+
+    def __init__(
+        self,
+        project_id: str,
+        token_provider: Callable[[], str],
+        timeout: float = 10.0,
+        http_client: Optional[httpx.AsyncClient] = None,
+    ):
+        """
+        Args:
+            project_id: Firebase project id
+            token_provider: blocking callable returning an OAuth2 access token
+                (service_account_token_provider); run in a thread
+            timeout: per-request timeout
+            http_client: injected client (tests)
+        """
+        self.project_id = project_id
+        self.token_provider = token_provider
+        self.client = http_client or httpx.AsyncClient(timeout=timeout)
+
+    async def close(self):
+        """Close the HTTP client and clean up resources."""
+        await self.client.aclose()
+
+    async def send(self, device_token: str, title: str, body: str, data: dict[str, str]) -> str:
+        """Send a notification; returns FCM's message name.
+
+        Only a 404 or an `UNREGISTERED` error code means the token is gone;
+        INVALID_ARGUMENT also covers a malformed message, so it is left to
+        raise_for_status like any other failure and the token is kept.
+
+        Raises:
+            FcmUnregisteredError: the token is unregistered
+            httpx.HTTPError: any other failure
+        """
+        access_token = await asyncio.to_thread(self.token_provider)
+        message = {
+            "message": {
+                "token": device_token,
+                "notification": {"title": title, "body": body},
+                "data": data,
+            }
+        }
+        response = await self.client.post(
+            f"{FCM_API_BASE}/projects/{self.project_id}/messages:send",
+            json=message,
+            headers={"Authorization": f"Bearer {access_token}"},
+        )
+        if response.status_code == 404 or (
+            response.is_error and _fcm_error_code(response) == "UNREGISTERED"
+        ):
+            raise FcmUnregisteredError("UNREGISTERED")
+        response.raise_for_status()
+        return (response.json() or {}).get("name", "")
@@ -0,0 +1,86 @@
+"""Foursquare Places API client for venue directory data.
+
+Searches places by name around a point (GET /v3/places/search) and returns
+them as OpenDataCandidate with Foursquare's categories, website, phone,
+opening hours display text and photo URLs.
+"""
+import logging
+from typing import Optional
+
+import httpx
+
+from app.metrics import OPEN_DATA_API_CALLS_TOTAL
+from app.models.open_data import OpenDataCandidate
+
+logger = logging.getLogger(__name__)
+
+FOURSQUARE_API_BASE = "https://api.foursquare.com/v3"
+FOURSQUARE_FIELDS = "fsq_id,name,geocodes,categories,website,tel,hours,photos"
+# Size segment of a photo URL (prefix + size + suffix); the CDN links are stable.
+FOURSQUARE_PHOTO_SIZE = "original"
+
+
+def _place_to_candidate(place: dict) -> Optional[OpenDataCandidate]:
+    main = (place.get("geocodes") or {}).get("main") or {}
+    lat, lng = main.get("latitude"), main.get("longitude")
+    if not place.get("fsq_id") or not place.get("name") or lat is None or lng is None:
+        return None
+    return OpenDataCandidate(
+        source="foursquare",
+        source_id=place["fsq_id"],
+        name=place["name"],
+        lat=float(lat),
+        lng=float(lng),
+        categories=[c["name"] for c in place.get("categories") or [] if c.get("name")],
+        website=place.get("website"),
+        phone=place.get("tel"),
+        opening_hours=(place.get("hours") or {}).get("display"),
+        photos=[
+            f"{p['prefix']}{FOURSQUARE_PHOTO_SIZE}{p['suffix']}"
+            for p in place.get("photos") or []
+            if p.get("prefix") and p.get("suffix")
+        ],
+    )
+
+
+class FoursquareClient:
+    """Async client for the Foursquare Places API."""
+
+    name = "foursquare"
+
+    def __init__(self, api_key: str, timeout: float = 15.0):
+        self.api_key = api_key
+        self.client = httpx.AsyncClient(
+            timeout=timeout,
+            headers={"Authorization": api_key, "Accept": "application/json"},
+        )
+
+    async def close(self):
+        """Close the HTTP client and clean up resources."""
+        await self.client.aclose()
+
+    async def search_around(
+        self, lat: float, lng: float, radius_m: float, name: str
+    ) -> list[OpenDataCandidate]:
+        """Places matching `name` within `radius_m` of the point.
+
+        Raises:
+            httpx.HTTPError: the call failed
+        """
+        params = {
+            "ll": f"{lat:.6f},{lng:.6f}",
+            "radius": str(int(radius_m)),
+            "query": name,
+            "limit": "5",
+            "fields": FOURSQUARE_FIELDS,
+        }
+        try:
+            response = await self.client.get(f"{FOURSQUARE_API_BASE}/places/search", params=params)
+            response.raise_for_status()
+            results = (response.json() or {}).get("results", [])
+        except httpx.HTTPError as e:
+            OPEN_DATA_API_CALLS_TOTAL.labels(source=self.name, status="error").inc()
+            logger.warning(f"[FoursquareClient] Search for {name!r} failed: {e}")
+            raise
+        OPEN_DATA_API_CALLS_TOTAL.labels(source=self.name, status="success").inc()
+        return [c for c in (_place_to_candidate(p) for p in results) if c is not None]
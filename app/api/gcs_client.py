@@ -0,0 +1,61 @@
+"""Google Cloud Storage client for backup snapshots.
+
+The same put_object / get_object / list_keys surface as S3Client, so a service
+can write to either store. Uses google-cloud-storage (imported lazily: only
+deployments that back up to GCS need it) through asyncio.to_thread.
+Credentials come from `credentials_file` (a service-account JSON) or, when it
+is empty, the environment's default credentials.
+"""
+import asyncio
+import logging
+
+logger = logging.getLogger(__name__)
+
+
+class GcsClient:
+    """Async-friendly GCS client for one bucket."""
+
+    def __init__(self, bucket: str, credentials_file: str = ""):
+        from google.cloud import storage
+
+        self.bucket_name = bucket
+        if credentials_file:
+            self._client = storage.Client.from_service_account_json(credentials_file)
+        else:
+            self._client = storage.Client()
+        self._bucket = self._client.bucket(bucket)
+
+    async def close(self):
+        """Close the GCS client."""
+        await asyncio.to_thread(self._client.close)
+
+    async def put_object(
+        self,
+        key: str,
+        body: bytes,
+        content_type: str = "application/octet-stream",
+        content_encoding: str | None = None,
+    ) -> str:
+        """Upload an object; returns its gs:// URI."""
+        blob = self._bucket.blob(key)
+        if content_encoding:
+            blob.content_encoding = content_encoding
+        await asyncio.to_thread(blob.upload_from_string, body, content_type=content_type)
+        logger.debug(f"[GcsClient] Uploaded {key} ({len(body)} bytes)")
+        return f"gs://{self.bucket_name}/{key}"
+
+    async def get_object(self, key: str) -> bytes:
+        """Download an object's body.
+
+        Raises:
+            google.api_core.exceptions.NotFound
+        """
+        # raw_download: hand back the stored bytes, not a transcoded body.
+        return await asyncio.to_thread(self._bucket.blob(key).download_as_bytes, raw_download=True)
+
+    async def list_keys(self, prefix: str) -> list[str]:
+        """Every object key under `prefix`, in key order."""
+        def list_all() -> list[str]:
+            return [blob.name for blob in self._client.list_blobs(self.bucket_name, prefix=prefix)]
+
+        return sorted(await asyncio.to_thread(list_all))
@@ -70,6 +70,18 @@ VIBE_FIELDS_MASK = ",".join([
     "priceRange",
 ])
 
+# Field mask for Nearby Search (New) discovery results
+NEARBY_FIELDS_MASK = ",".join(
+    f"places.{field}"
+    for field in (
+        "id", "displayName", "formattedAddress", "location", "primaryType",
+        "rating", "userRatingCount", "priceLevel",
+    )
+)
+# Nearby Search (New) limits: at most 20 results and a 50 km radius per call.
+NEARBY_MAX_RESULTS = 20
+NEARBY_MAX_RADIUS_METERS = 50000.0
+
 # Language code for Portuguese (Brazil) - used for opening hours descriptions
 LANGUAGE_CODE = "pt-BR"
 
@@ -230,6 +242,55 @@ class GooglePlacesAPIClient:
                 raise GooglePlacesSearchError(f"text search failed: {type(e).__name__}: {e}") from e
             return None
 
+    async def search_nearby(
+        self,
+        lat: float,
+        lng: float,
+        radius_meters: float,
+        max_results: int = NEARBY_MAX_RESULTS,
+        included_types: Optional[list[str]] = None,
+    ) -> list[dict]:
+        """Places around a point via Nearby Search (New), nearest first.
+
+        Args:
+            lat / lng / radius_meters: the search circle (radius capped at 50 km)
+            max_results: capped at Google's 20 per call
+            included_types: Google place types to restrict to (e.g. "bar")
+
+        Returns:
+            The raw `places` entries (NEARBY_FIELDS_MASK fields)
+
+        Raises:
+            httpx.HTTPStatusError / httpx.RequestError: the call failed (unlike
+                the enrichment lookups, discovery must not mistake an outage
+                for an empty area)
+        """
+        url = f"{GOOGLE_PLACES_API_BASE}/places:searchNearby"
+        headers = {
+            "Content-Type": "application/json",
+            "X-Goog-Api-Key": self.api_key,
+            "X-Goog-FieldMask": NEARBY_FIELDS_MASK,
+        }
+        body: dict = {
+            "maxResultCount": max(1, min(max_results, NEARBY_MAX_RESULTS)),
+            "rankPreference": "DISTANCE",
+            "languageCode": LANGUAGE_CODE,
+            "locationRestriction": {
+                "circle": {
+                    "center": {"latitude": lat, "longitude": lng},
+                    "radius": min(float(radius_meters), NEARBY_MAX_RADIUS_METERS),
+                }
+            },
+        }
+        if included_types:
+            body["includedTypes"] = included_types
+
+        logger.debug(f"[GooglePlacesAPIClient] Nearby search at {lat:.5f},{lng:.5f} r={radius_meters}")
+        async with self._instrumented("nearby_search"):
+            response = await self.client.post(url, headers=headers, json=body)
+            response.raise_for_status()
+            return (response.json() or {}).get("places", [])
+
     async def get_place_location(
         self, place_id: str
     ) -> Optional[tuple[float, float]]:
@@ -0,0 +1,110 @@
+"""OpenStreetMap Overpass API client for venue directory data.
+
+Finds named food/drink/nightlife places (amenity=bar|pub|restaurant|...) around
+a point and returns them as OpenDataCandidate, carrying the OSM `website`,
+`phone` and `opening_hours` tags, and photo URLs from the `image` and
+`wikimedia_commons` tags. Name matching is left to the caller
+(app/services/open_data_enrichment_service.py).
+"""
+import logging
+from typing import Optional
+from urllib.parse import quote
+
+import httpx
+
+from app.metrics import OPEN_DATA_API_CALLS_TOTAL
+from app.models.open_data import OpenDataCandidate
+
+logger = logging.getLogger(__name__)
+
+OVERPASS_DEFAULT_ENDPOINT = "https://overpass-api.de/api/interpreter"
+# Redirects to the current file of a Wikimedia Commons title.
+COMMONS_FILE_URL = "https://commons.wikimedia.org/wiki/Special:FilePath/"
+
+# amenity values worth matching against our venues.
+OSM_AMENITIES = (
+    "bar", "biergarten", "cafe", "fast_food", "food_court", "ice_cream",
+    "nightclub", "pub", "restaurant",
+)
+
+
+def _query(lat: float, lng: float, radius_m: float) -> str:
+    amenities = "|".join(OSM_AMENITIES)
+    return (
+        "[out:json][timeout:25];"
+        f'nwr(around:{radius_m:.0f},{lat:.6f},{lng:.6f})["name"]["amenity"~"^({amenities})$"];'
+        "out center tags;"
+    )
+
+
+def _photos(tags: dict) -> list[str]:
+    """http(s) URLs in `image` and Commons files in `wikimedia_commons` (both
+    may list several, separated by ";"); Commons categories are skipped."""
+    photos = [
+        url.strip() for url in (tags.get("image") or "").split(";")
+        if url.strip().startswith(("http://", "https://"))
+    ]
+    for title in (tags.get("wikimedia_commons") or "").split(";"):
+        title = title.strip()
+        if title.startswith("File:"):
+            photos.append(COMMONS_FILE_URL + quote(title[len("File:"):].replace(" ", "_")))
+    return photos
+
+
+def _element_to_candidate(element: dict) -> Optional[OpenDataCandidate]:
+    tags = element.get("tags") or {}
+    lat = element.get("lat", (element.get("center") or {}).get("lat"))
+    lng = element.get("lon", (element.get("center") or {}).get("lon"))
+    if not tags.get("name") or lat is None or lng is None:
+        return None
+    categories = [tags["amenity"]] if tags.get("amenity") else []
+    if tags.get("cuisine"):
+        categories += [c.strip() for c in tags["cuisine"].split(";") if c.strip()]
+    return OpenDataCandidate(
+        source="osm",
+        source_id=f"{element.get('type', 'node')}/{element.get('id')}",
+        name=tags["name"],
+        lat=float(lat),
+        lng=float(lng),
+        categories=categories,
+        website=tags.get("website") or tags.get("contact:website"),
+        phone=tags.get("phone") or tags.get("contact:phone"),
+        opening_hours=tags.get("opening_hours"),
+        photos=_photos(tags),
+    )
+
+
+class OverpassClient:
+    """Async client for one Overpass API endpoint."""
+
+    name = "osm"
+
+    def __init__(self, endpoint: str = OVERPASS_DEFAULT_ENDPOINT, timeout: float = 30.0):
+        self.endpoint = endpoint
+        self.client = httpx.AsyncClient(timeout=timeout)
+
+    async def close(self):
+        """Close the HTTP client and clean up resources."""
+        await self.client.aclose()
+
+    async def search_around(
+        self, lat: float, lng: float, radius_m: float, name: str
+    ) -> list[OpenDataCandidate]:
+        """Named food/drink places within `radius_m` of the point. `name` is not
+        sent (Overpass has no fuzzy name search); the caller matches it.
+
+        Raises:
+            httpx.HTTPError: the call failed
+        """
+        try:
+            response = await self.client.post(
+                self.endpoint, data={"data": _query(lat, lng, radius_m)}
+            )
+            response.raise_for_status()
+            elements = (response.json() or {}).get("elements", [])
+        except httpx.HTTPError as e:
+            OPEN_DATA_API_CALLS_TOTAL.labels(source=self.name, status="error").inc()
+            logger.warning(f"[OverpassClient] Search around {lat:.5f},{lng:.5f} failed: {e}")
+            raise
+        OPEN_DATA_API_CALLS_TOTAL.labels(source=self.name, status="success").inc()
+        return [c for c in (_element_to_candidate(e) for e in elements) if c is not None]
@@ -85,6 +85,60 @@ class S3Client:
             logger.error(f"[S3Client] Failed to upload {s3_key}: {e}")
             raise
 
+    async def put_object(
+        self,
+        key: str,
+        body: bytes,
+        content_type: str = "application/octet-stream",
+        content_encoding: str | None = None,
+    ) -> str:
+        """Upload an arbitrary object (e.g. an analytics export file).
+
+        Returns:
+            The s3:// URI of the written object
+        """
+        extra = {"ContentEncoding": content_encoding} if content_encoding else {}
+        start_time = time.perf_counter()
+        try:
+            await asyncio.to_thread(
+                self._s3.put_object,
+                Bucket=self.bucket,
+                Key=key,
+                Body=body,
+                ContentType=content_type,
+                **extra,
+            )
+        except ClientError as e:
+            S3_UPLOAD_DURATION_SECONDS.observe(time.perf_counter() - start_time)
+            S3_UPLOADS_TOTAL.labels(status="error").inc()
+            logger.error(f"[S3Client] Failed to upload {key}: {e}")
+            raise
+        S3_UPLOAD_DURATION_SECONDS.observe(time.perf_counter() - start_time)
+        S3_UPLOADS_TOTAL.labels(status="success").inc()
+        logger.debug(f"[S3Client] Uploaded {key} ({len(body)} bytes)")
+        return f"s3://{self.bucket}/{key}"
+
+    async def get_object(self, key: str) -> bytes:
+        """Download an object's body.
+
+        Raises:
+            ClientError: e.g. NoSuchKey
+        """
+        response = await asyncio.to_thread(self._s3.get_object, Bucket=self.bucket, Key=key)
+        return await asyncio.to_thread(response["Body"].read)
+
+    async def list_keys(self, prefix: str) -> list[str]:
+        """Every object key under `prefix`, in key order."""
+        def list_all() -> list[str]:
+            paginator = self._s3.get_paginator("list_objects_v2")
+            return [
+                obj["Key"]
+                for page in paginator.paginate(Bucket=self.bucket, Prefix=prefix)
+                for obj in page.get("Contents", [])
+            ]
+
+        return sorted(await asyncio.to_thread(list_all))
+
     async def generate_presigned_url(
         self, s3_key: str, expires_in: int = 3600
     ) -> str:
@@ -0,0 +1,234 @@
+"""Command-line entry point: `python main.py <command>`.
+
+Commands:
+    serve          HTTP server + scheduled jobs (the default with no command)
+    refresh        one venue catalog refresh (inventory sync + discovery)
+    refresh-live   one live forecast refresh for every venue
+    export         one history export (needs history_export_enabled + S3)
+    migrate        Redis key-layout migrations; --rds also runs Alembic
+    seed           load venues and forecasts from a JSON fixture into Redis
+    openapi        write the OpenAPI document (JSON) without starting anything
+
+Every command reads the same settings as the server (env, CONFIG_FILE,
+secrets). refresh, refresh-live and export build the server's Container and
+run the job once; migrate and seed only connect to Redis; openapi connects to
+nothing. Exit status is 0 on
+success, 1 on failure and 2 on argument errors.
+"""
+from __future__ import annotations
+
+import argparse
+import asyncio
+import logging
+from datetime import date
+from typing import Optional
+
+from app import __version__
+from app.config import ConfigError, Settings
+
+logger = logging.getLogger(__name__)
+
+
+def build_parser() -> argparse.ArgumentParser:
+    parser = argparse.ArgumentParser(
+        prog="cs-server", description="Venue discovery and crowd tracking service."
+    )
+    parser.add_argument("--version", action="version", version=f"cs-server {__version__}")
+    commands = parser.add_subparsers(dest="command", metavar="command")
+
+    commands.add_parser("serve", help="run the HTTP server and the scheduled jobs")
+
+    commands.add_parser("refresh", help="run one venue catalog refresh and exit")
+
+    refresh_live = commands.add_parser("refresh-live", help="run one live forecast refresh and exit")
+    refresh_live.add_argument(
+        "--include-closed",
+        action="store_true",
+        help="also refresh venues that are closed now (the scheduled job skips them)",
+    )
+
+    export = commands.add_parser("export", help="export one day of history to object storage")
+    export.add_argument(
+        "--date",
+        type=date.fromisoformat,
+        default=None,
+        metavar="YYYY-MM-DD",
+        help="day to export (default: yesterday UTC)",
+    )
+
+    migrate = commands.add_parser("migrate", help="apply pending schema migrations")
+    migrate.add_argument(
+        "--target", type=int, default=None, help="Redis schema version to stop at (default: latest)"
+    )
+    migrate.add_argument(
+        "--rds", action="store_true", help="also run `alembic upgrade head` against RDS"
+    )
+
+    seed = commands.add_parser("seed", help="load a JSON fixture into Redis (no BestTime calls)")
+    seed.add_argument(
+        "--fixture",
+        default=None,
+        metavar="FILE",
+        help="fixture file (default: resources/seed_fixture.json)",
+    )
+
+    openapi = commands.add_parser("openapi", help="write the OpenAPI document as JSON")
+    openapi.add_argument("--output", default=None, metavar="FILE", help="file to write (default: stdout)")
+    return parser
+
+
+def _setup_logging(settings: Settings) -> None:
+    from app.log_control import install_log_control
+    from app.log_format import install_log_format
+    from app.log_redaction import install_secret_redaction
+
+    logging.basicConfig(
+        level=logging.INFO,
+        format="%(asctime)s - %(name)s - %(levelname)s - %(message)s",
+    )
+    install_secret_redaction()
+    install_log_control(settings.log_level)
+    try:
+        install_log_format(settings.log_format)
+    except ValueError as e:
+        logger.warning(f"[CLI] {e}; keeping text logs")
+
+
+def serve(settings: Settings) -> int:
+    from app import http_server
+
+    scheme = "https" if settings.server_tls_certfile else "http"
+    logger.info(
+        f"[Main] Starting CS-Server on {scheme}://{settings.server_host}:{settings.server_port}"
+    )
+    http_server.serve(settings)
+    return 0
+
+
+async def _with_container(settings: Settings, run) -> int:
+    """Build the container, await `run(container)`, always shut it down."""
+    from app.container import Container
+
+    container = Container(settings)
+    try:
+        await run(container)
+    finally:
+        await container.shutdown()
+    return 0
+
+
+async def refresh(container) -> None:
+    summaries = await container.venues_refresher_service.refresh_venues_by_filter_for_default_locations()
+    logger.info(f"[CLI] Catalog refresh done ({len(summaries or [])} locations)")
+
+
+def refresh_live(include_closed: bool):
+    async def run(container) -> None:
+        await container.venues_refresher_service.refresh_live_forecasts_for_all_venues(
+            include_closed=include_closed
+        )
+        logger.info("[CLI] Live forecast refresh done")
+
+    return run
+
+
+def export(day: Optional[date]):
+    async def run(container) -> None:
+        if container.history_export_service is None:
+            raise RuntimeError(
+                "history export is not configured (history_export_enabled + S3 credentials)"
+            )
+        summary = await container.history_export_service.export_day(day)
+        logger.info(f"[CLI] History export done: {summary}")
+
+    return run
+
+
+def migrate(settings: Settings, target: Optional[int], rds: bool) -> int:
+    from app.dao import redis_migrations
+    from app.db.redis_factory import build_redis_client
+
+    client = build_redis_client(settings)
+    try:
+        result = redis_migrations.migrate(client, target=target)
+    finally:
+        client.close()
+    logger.info(
+        f"[CLI] Redis schema v{result['from']} -> v{result['to']} "
+        f"({len(result['applied'])} migrations applied)"
+    )
+    if rds:
+        from alembic import command
+        from alembic.config import Config
+
+        config = Config(str(settings.base_dir / "alembic.ini"))
+        # script_location in alembic.ini is relative to the working directory.
+        config.set_main_option("script_location", str(settings.base_dir / "migrations"))
+        command.upgrade(config, "head")
+        logger.info("[CLI] RDS schema upgraded to head")
+    return 0
+
+
+def seed(settings: Settings, fixture_path: Optional[str]) -> int:
+    from app.dao import RedisVenueDAO
+    from app.db import GeoRedisClient
+    from app.db.redis_factory import build_redis_client
+    from app.db.value_compression import ValueCompressor
+    from app.services.fixture_seed import load_fixture, seed_redis
+
+    path = fixture_path or settings.get_resource_path("seed_fixture.json")
+    # Validate the whole file before connecting, so a bad fixture writes nothing.
+    fixture = load_fixture(path)
+    client = build_redis_client(settings)
+    try:
+        geo_client = GeoRedisClient(
+            client,
+            compressor=ValueCompressor(
+                settings.redis_compression, settings.redis_compression_min_bytes
+            ),
+        )
+        seed_redis(RedisVenueDAO(geo_client), fixture)
+    finally:
+        client.close()
+    return 0
+
+
+def openapi(output: Optional[str]) -> int:
+    # Importing main builds the app (routes only; no connections until its
+    # lifespan runs).
+    from main import app
+    from app.openapi import write_openapi
+
+    write_openapi(app, output)
+    return 0
+
+
+def main(argv: Optional[list[str]] = None) -> int:
+    args = build_parser().parse_args(argv)
+    command = args.command or "serve"
+    settings = Settings()
+    _setup_logging(settings)
+
+    try:
+        if command == "serve":
+            return serve(settings)
+        if command == "migrate":
+            return migrate(settings, args.target, args.rds)
+        if command == "seed":
+            return seed(settings, args.fixture)
+        if command == "openapi":
+            return openapi(args.output)
+        settings.check()
+        if command == "refresh":
+            return asyncio.run(_with_container(settings, refresh))
+        if command == "refresh-live":
+            return asyncio.run(_with_container(settings, refresh_live(args.include_closed)))
+        if command == "export":
+            return asyncio.run(_with_container(settings, export(args.date)))
+    except ConfigError as e:
+        logger.critical(f"[CLI] {e}")
+        return 1
+    except Exception as e:
+        logger.error(f"[CLI] {command} failed: {e}")
+        return 1
+    raise AssertionError(f"unhandled command {command!r}")
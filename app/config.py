@@ -1,4 +1,4 @@
-"""Configuration management using Pydantic BaseSettings with JSON file support."""
+"""Configuration management using Pydantic BaseSettings with JSON/YAML file support."""
 import json
 import logging
 import os
@@ -7,6 +7,9 @@ from typing import Any, Optional
 
 from pydantic_settings import BaseSettings
 
+from app.secret_providers import SECRET_SETTINGS, build_providers, resolve_secrets
+from app.tenancy import TENANT_ID, TenantRegistry
+
 logger = logging.getLogger(__name__)
 
 
@@ -42,14 +45,14 @@ def flatten_json_config(config: dict[str, Any]) -> dict[str, Any]:
 
 
 def load_json_config(config_file: Optional[str] = None) -> dict[str, Any]:
-    """Load configuration from a JSON file.
+    """Load configuration from a JSON file, or YAML when it ends in .yaml/.yml.
 
-    Supports both flat and nested JSON structures. Nested structures are
+    Supports both flat and nested structures. Nested structures are
     automatically flattened. Keys starting with "_" are treated as comments
     and ignored.
 
     Args:
-        config_file: Path to JSON config file. If None, checks CONFIG_FILE env var.
+        config_file: Path to the config file. If None, checks CONFIG_FILE env var.
 
     Returns:
         Dictionary of configuration values (flattened), or empty dict if no file found.
@@ -66,7 +69,15 @@ def load_json_config(config_file: Optional[str] = None) -> dict[str, Any]:
 
     try:
         with open(path, "r", encoding="utf-8") as f:
-            config = json.load(f)
+            if path.suffix.lower() in (".yaml", ".yml"):
+                import yaml  # PyYAML ships with uvicorn[standard]
+
+                config = yaml.safe_load(f) or {}
+            else:
+                config = json.load(f)
+            if not isinstance(config, dict):
+                logger.error(f"Config file {file_path} must hold an object at the top level")
+                return {}
             logger.info(f"Loaded configuration from: {file_path}")
             # Flatten nested structure
             return flatten_json_config(config)
@@ -78,13 +89,39 @@ def load_json_config(config_file: Optional[str] = None) -> dict[str, Any]:
         return {}
 
 
+ENVIRONMENTS = ("prod", "staging", "dev", "test")
+
+# Defaults each environment profile applies to settings not set explicitly
+# (kwargs, env, config file). prod and staging run the built-in defaults.
+PROFILE_DEFAULTS: dict[str, dict[str, Any]] = {
+    "prod": {},
+    "staging": {},
+    "dev": {"dev_mode": True, "log_level": "DEBUG", "systemd_notify_enabled": False},
+    "test": {
+        "dev_mode": True,
+        "besttime_mode": "replay",
+        "systemd_notify_enabled": False,
+        "config_reload_seconds": 0,
+    },
+}
+
+_ENVIRONMENT_ALIASES = {"production": "prod", "stage": "staging", "development": "dev"}
+
+
+class ConfigError(ValueError):
+    """The settings cannot run the server (see Settings.config_errors)."""
+
+
 class Settings(BaseSettings):
-    """Application configuration with JSON file and environment variable support.
+    """Application configuration with JSON/YAML file and environment variable support.
 
     Configuration priority (highest to lowest):
     1. Environment variables
-    2. JSON config file (specified via CONFIG_FILE env var)
+    2. JSON or YAML config file (specified via CONFIG_FILE env var)
     3. Default values
+
+    Loading never fails on a bad value: the server checks config_errors() at
+    startup, so tools and tests can still build Settings from partial config.
     """
 
     # Redis Configuration
@@ -92,6 +129,86 @@ class Settings(BaseSettings):
     redis_port: int = 6379
     redis_password: str = ""
     redis_db: int = 0
+    # Redis topology (app/db/redis_factory.py): "standalone" uses redis_host/
+    # redis_port; "sentinel" discovers redis_sentinel_master through
+    # redis_sentinel_nodes ("host:port" list, port defaults to 26379) and
+    # follows failovers; "cluster" seeds a RedisCluster from redis_cluster_nodes
+    # (redis_db is ignored there). redis_password authenticates to the data
+    # nodes; redis_sentinel_password to the sentinels themselves.
+    redis_mode: str = "standalone"
+    redis_sentinel_nodes: list[str] = []
+    redis_sentinel_master: str = "mymaster"
+    redis_sentinel_password: str = ""
+    redis_cluster_nodes: list[str] = []
+    # Connection pool and timeouts, shared by every redis_mode. The socket
+    # timeout is the per-command deadline (redis-py applies one value to both
+    # reads and writes); timed-out or dropped commands are retried
+    # redis_retry_attempts times with exponential backoff (base doubling up to
+    # the cap, with jitter) before the error reaches the caller.
+    # redis_min_idle_connections are opened at startup so the first burst of
+    # requests does not pay the connect cost (ignored in cluster mode).
+    redis_max_connections: int = 50
+    redis_min_idle_connections: int = 0
+    redis_connect_timeout_seconds: float = 5.0
+    redis_socket_timeout_seconds: float = 10.0
+    redis_health_check_interval_seconds: int = 30
+    redis_retry_attempts: int = 3
+    redis_retry_backoff_base_seconds: float = 0.05
+    redis_retry_backoff_cap_seconds: float = 1.0
+    # In-process read-through cache on the serving DAO (app/dao/read_cache.py)
+    # for nearby answers, venue documents and live forecasts. Writes through
+    # the serving DAO invalidate it; writes from other replicas show up once
+    # redis_read_cache_ttl_seconds elapses, so keep the TTL short.
+    redis_read_cache_enabled: bool = False
+    redis_read_cache_max_entries: int = 10000
+    redis_read_cache_ttl_seconds: float = 5.0
+    # Store weekly forecast days in the compact varint encoding
+    # (app/dao/forecast_codec.py) instead of JSON. Reads accept both, so this
+    # can be flipped either way without migrating existing keys.
+    redis_compact_forecasts: bool = True
+    # Publish venue_upserted / live_forecast_set / live_forecast_deleted events
+    # on the venue_changes_v1 channel after successful DAO writes
+    # (app/dao/change_events.py). Best-effort; nothing is stored.
+    redis_change_events_enabled: bool = True
+    # Compress string values of at least redis_compression_min_bytes (venue
+    # documents, enrichment blobs) before writing them: "none", "gzip" or
+    # "snappy" (needs python-snappy). Reads expand compressed values whatever
+    # this is set to, so it can be changed without migrating keys.
+    redis_compression: str = "none"
+    redis_compression_min_bytes: int = 1024
+    # Where /v1/venues/nearby reads venues from (app/dao/rds_fallback_dao.py).
+    # Writes always go to RDS (system of record) and are projected to Redis.
+    # "redis": the Redis geo index only; "dual": Redis, falling back to RDS
+    # when Redis errors or its geo index is empty (e.g. after a flush);
+    # "rds": always RDS (bounding box + haversine on venues.address).
+    venue_read_backend: str = "redis"
+    # Last-known-good in-memory snapshot of the nearby serving data
+    # (app/dao/snapshot_venue_dao.py), refreshed every
+    # nearby_snapshot_refresh_minutes. On a Redis error /v1/venues/nearby
+    # answers from it, with `stale: true` on every venue, while it is at most
+    # nearby_snapshot_max_age_minutes old. Costs one copy of the catalog in
+    # memory per process.
+    nearby_snapshot_enabled: bool = False
+    nearby_snapshot_refresh_minutes: int = 5
+    nearby_snapshot_max_age_minutes: int = 60
+    # Precomputed nearby responses (app/services/nearby_precompute.py): after
+    # each Redis projection the responses for every discovery location at
+    # nearby_precompute_radii_km (today's forecast; verbose too with
+    # nearby_precompute_verbose) are rendered and stored as JSON, so an exact
+    # match is served with one Redis GET. Entries expire after
+    # nearby_precompute_ttl_seconds (keep it above redis_projection_minutes).
+    nearby_precompute_enabled: bool = False
+    nearby_precompute_radii_km: list[float] = [1.0, 3.0, 5.0]
+    nearby_precompute_verbose: bool = False
+    nearby_precompute_ttl_seconds: int = 600
+    # Return the per-stage nearby timing (app/latency_budget.py) in a
+    # `Server-Timing` response header. The stage histogram is always recorded.
+    nearby_server_timing_enabled: bool = False
+    # Apply pending Redis key-schema migrations (app/dao/redis_migrations.py)
+    # during essential startup, before serving. Already-applied migrations are
+    # skipped, so this is cheap on every start; disable to run them only via
+    # POST /admin/redis/migrate.
+    redis_migrate_on_startup: bool = True
 
     # RDS (Postgres) system-of-record connection. See
     # plans/rds_system_of_record_01_06_26.md.
@@ -126,7 +243,70 @@ class Settings(BaseSettings):
     # 43200 minutes = 30 days
     venues_catalog_refresh_minutes: int = 43200
     venues_live_refresh_minutes: int = 5
+    # Live refreshes skip venues whose cached live forecast was refreshed
+    # (LiveForecastResponse.refreshed_at) less than this many seconds ago, so
+    # overlapping triggers (admin runs, restarts) don't re-buy fresh data.
+    # 0 disables the cooldown.
+    live_refresh_cooldown_seconds: int = 0
+    # Live refreshes skip venues that are closed at refresh time according to
+    # their stored BestTime weekly hours (venue_open_close_v2), saving a
+    # credit per closed venue. Venues with unknown hours are still fetched.
+    # Admin runs can override per run with {"include_closed": true}.
+    live_refresh_skip_closed: bool = False
+    # Minutes before opening / after closing a venue still counts as open
+    # for live_refresh_skip_closed.
+    live_refresh_open_margin_minutes: int = 30
+    # Live forecasts fetched concurrently during a live refresh. The BestTime
+    # token bucket (besttime_rate_per_second) still paces the sends, so raise
+    # it together with this. 1 fetches one venue at a time.
+    live_fetch_concurrency: int = 1
+    # Discovery locations / points queried concurrently in a catalog refresh.
+    # The fetch_venue_total_limit / monthly budget is shared across them.
+    discovery_concurrency: int = 1
+    # Regional holiday calendar (app/services/holiday_calendar.py). On: nearby
+    # responses carry `special_day`, and on boost days (Carnaval, São João)
+    # the live refresh runs every holiday_live_refresh_minutes (0 = unchanged)
+    # unless admin_config:live_refresh_minutes is set. Region is "BR",
+    # "BR-PE" or "BR-PE-RECIFE"; the optional JSON file adds/overrides days.
+    holiday_calendar_enabled: bool = False
+    holiday_calendar_region: str = "BR-PE-RECIFE"
+    holiday_calendar_file: str = ""
+    holiday_live_refresh_minutes: int = 0
     weekly_forecast_cron: str = "0 0 * * 0"  # Sundays at 00:00
+    # Thursday prefetch of the weekend's weekly-forecast days (BestTime
+    # day_int 4=Fri, 5=Sat) for the priority-selected venues inside the
+    # nightlife regions ({"lat", "lng", "radius_km"} circles; empty = every
+    # selected venue). Only venues missing one of the days are fetched.
+    weekend_prefetch_enabled: bool = False
+    # Named day: APScheduler numbers days from Monday = 0, so "4" is Friday.
+    weekend_prefetch_cron: str = "0 12 * * thu"  # Thursdays at 12:00
+    weekend_prefetch_days: list[int] = [4, 5]
+    weekend_prefetch_regions: list[dict] = []
+
+    # Stale eviction (app/services/stale_eviction.py): soft-deletes venues no
+    # catalog/inventory refresh has touched for stale_venue_max_age_days and
+    # deletes live forecasts older than stale_live_forecast_max_age_minutes
+    # (both by refreshed_at; 0 keeps that kind). Keep the venue age well above
+    # venues_catalog_refresh_minutes. With stale_eviction_dry_run the job only
+    # reports what it would evict (GET /admin/stale-eviction/last-run).
+    stale_eviction_enabled: bool = False
+    stale_eviction_cron: str = "15 4 * * *"  # Daily at 04:15
+    stale_venue_max_age_days: int = 90
+    stale_live_forecast_max_age_minutes: int = 180
+    stale_eviction_dry_run: bool = False
+
+    # Fuzzy venue dedup (app/services/venue_dedup.py): venues within
+    # dedup_max_distance_m whose names (folded, without city words) have a
+    # similarity >= dedup_min_name_similarity are one place. With dedup_enabled
+    # the discovery refresh skips new venues duplicating an existing one, and a
+    # daily cleanup (dedup_cleanup_cron) soft-deletes the duplicates already in
+    # the catalog, keeping the most reviewed. dedup_cleanup_dry_run only
+    # reports (GET /admin/dedup/last-run).
+    dedup_enabled: bool = False
+    dedup_max_distance_m: float = 75.0
+    dedup_min_name_similarity: float = 0.9
+    dedup_cleanup_cron: str = "30 4 * * *"  # Daily at 04:30
+    dedup_cleanup_dry_run: bool = False
 
     # Serve-time live-busyness freshness gate. The stale window is DERIVED from
     # the live refresh cadence so the two never desync: a cached live value is
@@ -138,6 +318,196 @@ class Settings(BaseSettings):
     # venues fall back to the forecast estimate in vibes_bot.
     live_freshness_refresh_factor: float = 2.0
     live_freshness_min_minutes: int = 5
+    # How far back the per-venue live busyness history (live_history_v1:{id},
+    # appended on every live forecast write) is kept. Samples older than this
+    # relative to the newest one are trimmed on write, and the whole key expires
+    # after the same window once a venue stops getting live updates. The
+    # nightly history export reads the whole previous UTC day after midnight,
+    # so with history_export_enabled it must cover more than 24 hours.
+    live_history_window_hours: int = 26
+    # Busyness trend on nearby items (app/services/busyness_trend.py): a
+    # `trend` with direction (rising/falling/steady), delta_1h (live busyness
+    # now minus the history sample nearest an hour ago) and the forecast peak
+    # still ahead in the business day. A change of at least
+    # busyness_trend_threshold points counts as rising or falling.
+    busyness_trend_enabled: bool = False
+    busyness_trend_threshold: int = 10
+    # Open-now on nearby items (app/services/venue_open_hours.py): `open_now`
+    # from the stored BestTime hours in each venue's timezone (two extra bulk
+    # reads per request). GET /v1/venues/nearby?open_now=true filters on it
+    # whether or not this is on.
+    nearby_open_now_enabled: bool = False
+    # Venue-local time on nearby items (app/utils/venue_time.py): `tz` (the
+    # venue's IANA timezone, Recife when BestTime has none) and `local_time`,
+    # so clients outside the venue's timezone need no conversion of their own.
+    nearby_local_time_enabled: bool = False
+    # Estimated headcount on nearby items (app/services/venue_occupancy.py):
+    # `estimated_occupancy` = fresh live busyness x the venue's capacity,
+    # scaled down for short dwell times. Capacity is area_capacity_hints for
+    # the venue, else occupancy_capacity_by_type[venue_type], else
+    # occupancy_default_capacity; the type figures assume visitors stay
+    # occupancy_reference_dwell_minutes or longer.
+    nearby_occupancy_enabled: bool = False
+    occupancy_capacity_by_type: dict[str, int] = {
+        "BAR": 80,
+        "BEER": 80,
+        "BREWERY": 150,
+        "CLUBS": 400,
+        "CONCERT_HALL": 1000,
+        "EVENT_VENUE": 500,
+        "RESTAURANT": 60,
+        "CAFE": 30,
+    }
+    occupancy_default_capacity: int = 60
+    occupancy_reference_dwell_minutes: int = 60
+    # GET /v1/venues/nearby?sort=score (app/services/venue_score.py): items
+    # ordered by, and carrying, a 0-100 `score`, the weighted mean of busyness
+    # (fresh live), rating, reviews (log-scaled, full at
+    # score_reviews_saturation), price (cheaper is better) and distance
+    # (closer is better, within the search radius). Weights need not sum to 1.
+    score_weights: dict[str, float] = {
+        "busyness": 0.35,
+        "rating": 0.25,
+        "reviews": 0.1,
+        "price": 0.05,
+        "distance": 0.25,
+    }
+    score_reviews_saturation: int = 500
+    # How live/weekly busyness is chosen when several CrowdDataProviders
+    # (app/services/crowd_providers.py) cover a venue: "priority" takes the
+    # first provider in registration order with usable data, "freshest" the
+    # usable live sample with the latest venue_current_gmttime. With BestTime
+    # as the only provider both behave identically.
+    crowd_merge_policy: str = "priority"
+    # Where catalog discovery finds venues (app/services/venue_data_providers.py):
+    # "besttime" (/venues/filter) or "google_places" (Nearby Search; needs
+    # google_places_api_key). google_places_discovery_types narrows the Google
+    # search to these place types (empty = any type).
+    venue_data_provider: str = "besttime"
+    google_places_discovery_types: list[str] = ["bar", "night_club", "restaurant"]
+    # Busyness validation (app/services/busyness_validation.py), applied to
+    # every live, weekly and partner value before it is cached. Values below 0
+    # or above busyness_reject_above are rejected; values in (100, reject] are
+    # clamped to 100. A venue is flagged as flip-flopping when its live value
+    # swings by >= busyness_flipflop_delta points in alternating directions
+    # busyness_flipflop_swings times in a row (0 disables the check).
+    busyness_reject_above: int = 200
+    busyness_flipflop_delta: int = 50
+    busyness_flipflop_swings: int = 3
+    # Venue validation (app/services/venue_validation.py), applied to every
+    # venue the refresher upserts. Venues with no id or name or with bad
+    # coordinates are not written; an out-of-bounds rating, price level or
+    # review count is dropped. Both are listed in a quarantine of at most
+    # venue_quarantine_max_entries venues (GET /admin/venues/quarantine).
+    venue_quarantine_max_entries: int = 1000
+    # Partner occupancy ingestion (POST /v1/partners/venues/{id}/occupancy).
+    # partner_api_keys maps each secret X-Partner-Key to a partner id (empty =
+    # endpoint disabled, 503); partner_venues maps a partner id to the venue ids
+    # it may report for. A reading is preferred over BestTime live data until
+    # it is partner_reading_max_age_minutes old, then BestTime takes over again.
+    partner_api_keys: dict[str, str] = {}
+    partner_venues: dict[str, list[str]] = {}
+    partner_reading_max_age_minutes: int = 30
+    # Tenants (app/tenancy.py): cities or client apps with their own Redis
+    # serving keyspace (keys prefixed t:<id>:). tenants maps a tenant id to
+    # {"api_keys": [...], "discovery_locations_file", "fetch_venue_total_limit",
+    # "monthly_new_venue_quota", "bbox": [min_lat, min_lng, max_lat, max_lng]};
+    # empty = single-tenant, nothing prefixed. Requests pick their tenant by
+    # X-API-Key, else default_tenant ("" = a key is required); an X-Tenant-Id
+    # header must match it. /admin, /internal and /v1/partners run unscoped.
+    tenants: dict[str, dict] = {}
+    default_tenant: str = ""
+    # Area crowd index (app/services/area_crowd_index.py; GET /v1/areas and
+    # /v1/areas/{id}/index). crowd_index_areas lists the neighborhoods, each
+    # {"id", "name", "polygon": [[lat, lng], ...]} or {"id", "name", "geohash":
+    # [prefixes]}; empty = feature off. An area's index is the capacity-weighted
+    # mean fresh live busyness of its venues, recomputed after every live
+    # refresh. area_capacity_hints maps venue_id -> capacity; venues without a
+    # hint weigh area_default_capacity.
+    crowd_index_areas: list[dict] = []
+    area_capacity_hints: dict[str, int] = {}
+    area_default_capacity: int = 100
+    # Busyness alert webhooks (app/services/webhooks.py; POST /v1/webhooks),
+    # evaluated in the background after every live refresh. Registering needs
+    # an X-Webhook-Key from webhooks_api_keys (key -> client id; empty = no
+    # registrations). At most webhooks_max registrations; URLs must be https
+    # unless webhooks_require_https is off, and must resolve to public
+    # addresses. Up to webhooks_max_concurrency POSTs run at once. A failed
+    # POST is retried webhooks_max_attempts times, backing off from
+    # webhooks_backoff_seconds (doubling), at the following evaluations.
+    webhooks_enabled: bool = False
+    webhooks_api_keys: dict[str, str] = {}
+    webhooks_max: int = 1000
+    webhooks_require_https: bool = True
+    webhooks_timeout_seconds: float = 5.0
+    webhooks_max_concurrency: int = 10
+    webhooks_max_attempts: int = 5
+    webhooks_backoff_seconds: float = 60.0
+    # Favorite-venue push notifications (app/services/push_notifications.py)
+    # through Firebase Cloud Messaging, which also reaches iOS through APNs.
+    # Devices subscribe with POST /v1/push/subscriptions; after every live
+    # refresh a subscriber gets a push when a favorite reaches their threshold
+    # (push_default_threshold when they set none). fcm_credentials_file is the
+    # Firebase service-account JSON; fcm_project_id defaults to its project_id.
+    push_notifications_enabled: bool = False
+    fcm_credentials_file: str = ""
+    fcm_project_id: str = ""
+    push_default_threshold: int = 80
+    # Anonymous check-ins (app/services/checkins.py; POST
+    # /v1/venues/{id}/checkin). A device counts once per venue per
+    # checkin_dedup_minutes, a client IP at most checkin_max_per_ip times, and
+    # only from within checkin_max_distance_m of the venue. A venue without
+    # BestTime live busyness shows one from its check-ins in the last
+    # checkin_window_minutes: checkin_full_count of them read as 100%, fewer
+    # than checkin_min_count show nothing.
+    checkins_enabled: bool = False
+    checkin_window_minutes: int = 60
+    checkin_dedup_minutes: int = 60
+    checkin_full_count: int = 30
+    checkin_min_count: int = 3
+    checkin_max_distance_m: int = 200
+    checkin_max_per_ip: int = 5
+    # User venue suggestions (app/services/venue_suggestions.py; POST
+    # /v1/venues/suggest, moderated under /admin/venues/suggestions). At most
+    # venue_suggestions_max_pending wait for review; decided ones are kept up
+    # to venue_suggestions_max_entries in all. Approving runs the admin
+    # add-by-address flow (BestTime create + upsert).
+    venue_suggestions_enabled: bool = False
+    venue_suggestions_max_pending: int = 500
+    venue_suggestions_max_entries: int = 5000
+    # Per-user recommendations (app/services/recommendations.py; GET
+    # /v1/users/{user_id}/recommendations, visits via POST /v1/visits). A user's
+    # newest recommendation_max_seeds favorites/visits seed a search within
+    # recommendation_radius_km; candidates are ranked by
+    # recommendation_weights over type, price, proximity and live busyness.
+    # Visits keep the newest recommendation_max_visits per user and expire
+    # recommendation_history_days after the last one.
+    recommendations_enabled: bool = False
+    recommendation_radius_km: float = 5.0
+    recommendation_max_seeds: int = 20
+    recommendation_max_visits: int = 200
+    recommendation_history_days: int = 90
+    recommendation_weights: dict[str, float] = {
+        "type": 0.35,
+        "price": 0.15,
+        "proximity": 0.2,
+        "busyness": 0.3,
+    }
+    # Venue events for analytics (app/services/event_publishing.py):
+    # events_broker "kafka" or "nats" ("" = off). The refresher writes
+    # venue_upserted / live_forecast_set events to a Redis outbox (at most
+    # events_outbox_max, oldest dropped first), published every
+    # events_publish_interval_seconds in batches of events_publish_batch_size
+    # to events_topic (Kafka topic; NATS subject prefix, "<topic>.<type>").
+    # A failed publish stays in the outbox for the next run.
+    events_broker: str = ""
+    events_kafka_bootstrap_servers: list[str] = []
+    events_nats_servers: list[str] = []
+    events_topic: str = "cs_server.venue_events"
+    events_outbox_max: int = 100_000
+    events_publish_interval_seconds: int = 10
+    events_publish_batch_size: int = 500
+    events_publish_timeout_seconds: float = 10.0
 
     # Serve-time attachment of the previous business day's weekly forecast
     # (plans/260710_prev-day-weekly-forecast.md). Under the BestTime day_raw
@@ -148,6 +518,16 @@ class Settings(BaseSettings):
     # readers that don't know about it; the flag exists purely as an instant
     # rollback lever, default on.
     weekly_forecast_prev_day_enabled: bool = True
+    # How much of the venue's embedded foot-traffic forecast (a week of hourly
+    # values per venue) /v1/venues/nearby returns. "full": the whole week (the
+    # historical shape); "current_day": only the selected day
+    # (besttime_day_int); "omit": none; "link": none, plus a `forecast_url`
+    # pointing at GET /v1/venues/{venue_id}/forecast. When
+    # nearby_forecast_max_venues > 0 only that many venues (in response order)
+    # embed anything; the rest get the link instead. `forecast_url` is only
+    # serialized when one of the two can set it.
+    nearby_foot_traffic_forecast: str = "full"
+    nearby_forecast_max_venues: int = 0
 
     # Venue discovery (catalog refresh + venue-filter). Disabled by default so
     # discovery does not spend BestTime's scarce monthly unique-venue cap; the
@@ -157,8 +537,18 @@ class Settings(BaseSettings):
     discovery_enabled: bool = False
 
     # BestTime API Configuration
-    besttime_private_key: str = "pri_aff50a71a038456db88864b16d9d6800"
-    besttime_public_key: str = "pub_4f4f184e1a5f4f50a48e945fde7ab2ea"
+    # Required (BESTTIME_PRIVATE_KEY / BESTTIME_PUBLIC_KEY, a secret store or
+    # the config file) unless demo_mode or besttime_mode "replay".
+    besttime_private_key: str = ""
+    besttime_public_key: str = ""
+    # More BestTime accounts to spread calls over, as
+    # [{"public": "pub_...", "private": "pri_..."}]; the pair above is always
+    # the first. besttime_key_rotation: "failover" (next pair once one reports
+    # its credits used up) or "round_robin" (every call on the next pair). A
+    # pair out of credits is skipped for besttime_key_exhausted_cooldown_seconds.
+    besttime_extra_key_pairs: list[dict[str, str]] = []
+    besttime_key_rotation: str = "failover"
+    besttime_key_exhausted_cooldown_seconds: float = 3600.0
     besttime_endpoint_base_v1: str = "https://besttime.app/api/v1"
     besttime_search_polling_wait_seconds: int = 15
     # Dedicated timeout (seconds) for the slow, synchronous POST /forecasts
@@ -168,6 +558,11 @@ class Settings(BaseSettings):
     # slow-but-healthy BestTime latency instead of raising ReadTimeout (prod
     # incidents 2026-07-01/02 saw healthy creates outlive 30s).
     besttime_add_venue_timeout_seconds: float = 60.0
+    # Per-call timeouts (seconds) for the refresh reads — live forecasts are
+    # cheap and should fail fast, the weekly raw forecast is larger. 0 keeps
+    # the client-wide 10s default.
+    besttime_live_timeout_seconds: float = 0.0
+    besttime_weekly_timeout_seconds: float = 0.0
     # BestTime's documented Venue Search rate limits (30 requests/minute,
     # 300 requests/hour). The client paces the search family — POST /forecasts
     # create, /venues/filter, /venues/search, /venues/progress — inside these
@@ -179,6 +574,52 @@ class Settings(BaseSettings):
     besttime_search_rate_per_minute: int = 30
     besttime_search_rate_per_hour: int = 300
     besttime_rate_max_wait_seconds: float = 75.0
+    # Client-wide token bucket over EVERY BestTime call (live, weekly,
+    # discovery, adds), shared by all jobs: refills at rate_per_second up to
+    # burst. Smooths a live refresh over hundreds of venues so it does not
+    # trip BestTime's quota limits. 0 disables.
+    besttime_rate_per_second: float = 0.0
+    besttime_rate_burst: int = 10
+    # Circuit breaker over every BestTime call: after this many consecutive
+    # failures (transport errors, timeouts, 5xx after retries) calls fail fast
+    # for the cooldown, then one trial call decides whether to close it again.
+    # Refresh runs stop at the first short-circuited call. 0 disables.
+    besttime_circuit_failure_threshold: int = 5
+    besttime_circuit_cooldown_seconds: float = 60.0
+    # Outbound identity for BestTime calls. The User-Agent defaults (empty) to
+    # `cs-server/<version>` so BestTime support can pick our traffic out of
+    # their logs; extra headers are sent on every call (some corporate egress
+    # policies require a tagging header); the proxy URL routes all BestTime
+    # traffic through an egress proxy (empty = direct).
+    besttime_user_agent: str = ""
+    besttime_extra_headers: dict[str, str] = {}
+    besttime_proxy_url: str = ""
+    # "live", or "record" (also write every BestTime response to
+    # besttime_fixtures_dir) / "replay" (answer from those fixtures, never
+    # calling BestTime); see app/api/besttime_recorder.py.
+    besttime_mode: str = "live"
+    besttime_fixtures_dir: str = "tests/fixtures/besttime/recorded"
+    # Reject BestTime bodies with fields the response models do not declare or
+    # without fields they expect (app/api/besttime_decoding.py). Off: drift is
+    # only counted (besttime_unknown_fields_total, GET
+    # /admin/besttime/schema-drift) and bodies still decode as before.
+    besttime_strict_decoding: bool = False
+    # Daily BestTime credit budget (app/services/besttime_quota.py): once the
+    # day's estimated credits reach it, catalog discovery, the weekly refresh
+    # and the weekend prefetch are skipped until the next Recife day; the live
+    # refresh keeps running. 0 = no budget (usage is still counted).
+    besttime_daily_credit_budget: int = 0
+    # Retry of transient BestTime answers (app.api.RetryPolicy): up to
+    # max_attempts sends per call, honoring Retry-After, else exponential
+    # backoff from base to max seconds spread by +/- jitter (a ratio). Reads
+    # retry every listed status; the metered create and live forecast retry
+    # 429 only, and a used-up quota is never retried. The total wait per call
+    # stays within besttime_rate_max_wait_seconds.
+    besttime_retry_max_attempts: int = 3
+    besttime_retry_backoff_base_seconds: float = 1.0
+    besttime_retry_backoff_max_seconds: float = 30.0
+    besttime_retry_jitter: float = 0.2
+    besttime_retry_statuses: list[int] = [429, 500, 502, 503, 504]
 
     # Google Places API Configuration
     # Enrichment includes: vibe attributes, business status checks, permanently closed detection
@@ -257,6 +698,36 @@ class Settings(BaseSettings):
     instagram_cache_ttl_days: int = 30
     instagram_not_found_cache_ttl_days: int = 7
 
+    # OpenStreetMap / Foursquare enrichment (app/services/open_data_enrichment_service.py):
+    # categories, website, phone and opening hours matched by name within
+    # open_data_match_radius_m of the venue, stored on the venue document.
+    # open_data_sources in priority order ("osm" needs no key, "foursquare"
+    # needs foursquare_api_key). A venue nothing matched is retried after
+    # open_data_miss_ttl_days.
+    open_data_enrichment_enabled: bool = False
+    open_data_enrichment_cron: str = "0 5 * * tue"  # Weekly: Tuesday at 5 AM
+    open_data_sources: list[str] = ["osm"]
+    osm_overpass_endpoint: str = "https://overpass-api.de/api/interpreter"
+    foursquare_api_key: str = ""
+    open_data_match_radius_m: float = 150.0
+    open_data_min_name_similarity: float = 0.75
+    open_data_enrichment_limit: int = 0  # Max venues searched per run (0 = unlimited)
+    open_data_miss_ttl_days: int = 14
+
+    # Venue photos, website and social links (app/services/venue_media.py),
+    # stored on the venue document and managed under
+    # /admin/venues/{venue_id}/media. venue_media_provider fills venues
+    # without media on venue_media_enrichment_cron ("enrichment": from the
+    # open-data and Instagram enrichments; "none": operators only). Media
+    # holds up to venue_media_max_photos photos. Verbose nearby items carry
+    # it on the venue; minified ones as `media` when nearby_media_enabled.
+    venue_media_enabled: bool = False
+    venue_media_provider: str = "enrichment"
+    venue_media_enrichment_cron: str = "0 6 * * tue"  # Weekly: Tuesday at 6 AM, after open data
+    venue_media_enrichment_limit: int = 0  # Max venues fetched per run (0 = unlimited)
+    venue_media_max_photos: int = 10
+    nearby_media_enabled: bool = False
+
     # Instagram Posts Scraping (feeds post captions into vibe classifier)
     ig_posts_enrichment_enabled: bool = False
     ig_posts_enrichment_on_startup: bool = False
@@ -298,6 +769,31 @@ class Settings(BaseSettings):
     s3_access_key_id: str = ""
     s3_secret_access_key: str = ""
 
+    # Nightly analytics export (app/services/history_export_service.py): the
+    # previous UTC day's busyness history and catalog deltas, written as
+    # date-partitioned objects for the data team. Uses the S3 credentials
+    # above; history_export_bucket defaults to s3_bucket. Template fields:
+    # {dataset} {date} {year} {month} {day} {ext}. Format: ndjson (gzip) or
+    # parquet (requires pyarrow).
+    history_export_enabled: bool = False
+    history_export_cron: str = "30 0 * * *"  # Daily at 00:30
+    history_export_bucket: str = ""
+    history_export_path_template: str = "analytics/{dataset}/date={date}/part-0000.{ext}"
+    history_export_format: str = "ndjson"
+
+    # Venue data snapshots (app/services/backup_service.py): every active venue,
+    # live forecast and weekly forecast day in RDS, written as gzip JSON to
+    # backup_storage ("s3" or "gcs") on backup_cron, under backup_prefix.
+    # backup_bucket defaults to s3_bucket for S3 (same credentials); GCS uses
+    # gcs_credentials_file, or the default credentials when empty. Restore with
+    # POST /admin/backups/restore (writes RDS, then runs the projection).
+    backup_enabled: bool = False
+    backup_storage: str = "s3"
+    backup_bucket: str = ""
+    backup_prefix: str = "backups/venues/"
+    backup_cron: str = "15 */6 * * *"  # Every 6 hours at :15
+    gcs_credentials_file: str = ""
+
     # Menu Data Extraction (OpenAI GPT-4o-mini)
     openai_api_key: str = ""
     menu_extraction_enabled: bool = False
@@ -319,6 +815,12 @@ class Settings(BaseSettings):
     vibe_classifier_early_stop_min_photos: int = 6
     vibe_classifier_early_stop_confidence: float = 0.92
 
+    # Environment profile: "prod" (default), "staging", "dev" or "test". A
+    # profile sets defaults for the settings it covers (PROFILE_DEFAULTS: dev
+    # turns on dev_mode and DEBUG logs, test also replays BestTime fixtures);
+    # anything set explicitly wins. Reported by /health.
+    environment: str = "prod"
+
     # Dev Mode - overrides default locations for venue discovery
     dev_mode: bool = False
     dev_lat: float = -8.07834       # Default: Recife ZS/ZN
@@ -326,9 +828,108 @@ class Settings(BaseSettings):
     dev_radius: int = 6000          # Meters
     dev_vibesense_pipeline_priority_venues: list[str] = []  # Venue names to classify first
 
+    # Hot reload (app/services/runtime_config.py): every config_reload_seconds
+    # the config file (when it changed) and the runtime_config:v1 Redis
+    # overrides (PUT /admin/runtime-config) are applied to the reloadable
+    # settings: refresh schedules, BestTime pacing, refresh concurrency and
+    # limits, discovery_locations_file and log_level. 0 = no periodic check.
+    config_reload_seconds: int = 30
+
+    # Secret stores (app/secret_providers.py) for the credential settings
+    # (BestTime keys, Redis/RDS passwords, API keys). secrets_dir: a directory
+    # with one file per secret, named after the setting (Docker /run/secrets,
+    # a Kubernetes secret volume). vault_addr + vault_path: a Vault KV secret
+    # whose keys are setting names, read with VAULT_TOKEN or vault_token_file.
+    # An explicitly set env var still wins over both.
+    secrets_dir: str = ""
+    vault_addr: str = ""
+    vault_path: str = ""
+    vault_mount: str = "secret"
+    vault_kv_version: int = 2
+    vault_token_file: str = ""
+
     # Server Configuration
     server_port: int = 8080
+    # HTTP listener of `python main.py serve` (app/http_server.py). TLS is on
+    # when server_tls_certfile and server_tls_keyfile (PEM paths) are both set.
+    server_host: str = "0.0.0.0"
+    server_tls_certfile: str = ""
+    server_tls_keyfile: str = ""
+    # Seconds. request: a handler that has not started its response by then
+    # gets 504 (0 = no limit; sync routes keep running in their thread after
+    # the 504). idle: an idle keep-alive connection is closed.
+    # shutdown: how long in-flight requests may finish on SIGTERM before they
+    # are cancelled (0 = wait for them).
+    server_request_timeout_seconds: int = 0
+    server_idle_timeout_seconds: int = 5
+    server_shutdown_timeout_seconds: int = 30
+    # Peers (IPs or CIDRs, "*" = any) whose X-Forwarded-For/-Proto headers are
+    # trusted, i.e. the load balancer in front of the listener. Their requests
+    # take the forwarded client address, so per-IP limits (check-ins) see the
+    # real client instead of the balancer; anyone else's headers are ignored.
+    server_forwarded_allow_ips: list[str] = ["127.0.0.1"]
+    # Serve the OpenAPI document (/openapi.json) with Swagger UI (/docs). The
+    # UI's assets are bundled (swagger-ui-bundle) and served under /docs/assets.
+    openapi_enabled: bool = True
     log_level: str = "INFO"
+    # "text" (human-readable) or "json": one object per line with level,
+    # logger, component ([VenueHandler]-style tag), request path and trace ids
+    # (app/log_format.py).
+    log_format: str = "text"
+    # sd_notify READY / STOPPING / WATCHDOG messages (app/sd_notify.py) for
+    # Type=notify systemd units. Inert unless systemd sets NOTIFY_SOCKET.
+    systemd_notify_enabled: bool = True
+    # OpenTelemetry tracing (app/tracing.py): spans for HTTP requests, the
+    # handler/refresher entry points, RedisVenueDAO and BestTime calls. The
+    # OTLP exporter is configured by the standard OTEL_EXPORTER_OTLP_* /
+    # OTEL_SERVICE_NAME environment variables. tracing_sample_ratio is the share
+    # of new traces recorded; requests with a sampled parent always are.
+    tracing_enabled: bool = False
+    tracing_sample_ratio: float = 1.0
+    # Internal diagnostics port (app/diagnostics.py): /debug/vars and
+    # /debug/pprof (profile, tasks, heap). 0 = off. Bind it to an interface
+    # the load balancer does not reach. diagnostics_tracemalloc_frames > 0
+    # starts tracemalloc for /debug/pprof/heap (memory and CPU overhead).
+    diagnostics_port: int = 0
+    diagnostics_host: str = "127.0.0.1"
+    diagnostics_tracemalloc_frames: int = 0
+    # gRPC VenueService (app/grpc_api/) on its own port next to HTTP: nearby
+    # venues, one venue, one live forecast and a live-update stream, answered
+    # by the same handler as the HTTP routes. Needs the code generated by
+    # `make proto` (the Docker image has it). Uses the server_tls_* pair too.
+    # Every call needs an x-api-key metadata entry from grpc_api_keys (key ->
+    # client id; required when enabled).
+    grpc_enabled: bool = False
+    grpc_host: str = "0.0.0.0"
+    grpc_port: int = 9090
+    grpc_api_keys: dict[str, str] = {}
+
+    # How long (seconds) GET /v1/stats/public reuses its computed aggregates;
+    # also sent as the response's Cache-Control max-age.
+    public_stats_cache_seconds: int = 300
+
+    # Public demo mode. When True the server serves /v1/venues/nearby from a
+    # deterministic synthetic catalog (app/services/demo_data.py) with
+    # artificial live curves, connects to neither Redis nor RDS, schedules no
+    # jobs, does not mount the admin/debug/internal/engagement routers, and caps
+    # each client IP at demo_rate_limit_per_minute requests (<=0 disables the
+    # cap). Safe to expose publicly: no BestTime-derived data and no credentials
+    # are needed or reachable.
+    demo_mode: bool = False
+    demo_center_lat: float = -8.05428
+    demo_center_lng: float = -34.88126
+    demo_venue_count: int = 40
+    demo_spread_km: float = 5.0
+    demo_rate_limit_per_minute: int = 20
+
+    # On shutdown, running refresh jobs are cancelled (aborting their BestTime
+    # calls); wait at most this long for them to unwind before closing clients.
+    shutdown_job_grace_seconds: float = 10.0
+    # Multi-replica deployments: also take each refresh job's lock in Redis
+    # (job_lock:<job>, SET NX with this TTL, renewed while the run lasts), so
+    # only one replica runs a job at a time (app/services/job_lock.py).
+    distributed_job_lock_enabled: bool = False
+    job_lock_ttl_seconds: int = 300
 
     # Startup Configuration
     # If False, skip initial venue refresh on startup (only schedule jobs)
@@ -337,6 +938,42 @@ class Settings(BaseSettings):
     fetch_venue_limit_override: int = 0
     # Global cap on total venues fetched from BestTime API across all locations (-1 = disabled, 0 = fetch none)
     fetch_venue_total_limit: int = -1
+    # Overrides for the /venues/filter query discovery sends at each location
+    # (app.models.SearchParams fields: types, busy_min, foot_traffic, now, live,
+    # own_venues_only), e.g. {"types": ["RESTAURANT"], "now": true}. Empty keeps
+    # the standard nightlife query.
+    discovery_search: dict = {}
+    # Region profiles (app/services/regions.py; GET /v1/regions): [{"id",
+    # "name", "bbox": [min_lat, min_lng, max_lat, max_lng], "points": [discovery
+    # points], "search_city", "cities", "catalog_refresh_minutes"}]. Discovery
+    # queries the points of the regions that are due when neither admin points
+    # nor discovery_locations_file are set; empty = the built-in locations.
+    regions: list[dict] = []
+    # JSON or YAML file of discovery points ([{"id", "lat", "lng", "radius",
+    # "limit"}] or {"points": [...]}), re-read every catalog refresh. Used when
+    # the discovery_points admin config (/admin/locations) is empty; "" falls
+    # back to the built-in locations.
+    discovery_locations_file: str = ""
+    # Refresh run reports (start/end, counts, errors) kept in Redis for
+    # GET /admin/refresh/history, newest first.
+    refresh_report_history_size: int = 200
+    # Retry queue (app/services/retry_queue.py): venue upserts and live
+    # forecast fetches that fail during a refresh are queued in Redis and
+    # retried every retry_queue_interval_minutes, waiting
+    # retry_queue_backoff_seconds (doubling per attempt, capped at
+    # retry_queue_max_backoff_seconds). After retry_queue_max_attempts failed
+    # retries an item is dead-lettered (GET /admin/retry-queue).
+    retry_queue_enabled: bool = False
+    retry_queue_interval_minutes: int = 5
+    retry_queue_batch_size: int = 100
+    retry_queue_max_attempts: int = 5
+    retry_queue_backoff_seconds: float = 60.0
+    retry_queue_max_backoff_seconds: float = 3600.0
+    # Per-region bandit over discovery radius/busy_min/limit, scored by venues
+    # with live data per credit (app/services/filter_tuner.py). Off by default.
+    filter_tuner_enabled: bool = False
+    # Share of discovery calls that explore a random profile instead of the best
+    filter_tuner_epsilon: float = 0.1
     # Global cap on how many venues get processed by enrichment services (photo, instagram, menu, vibe classifier)
     # -1 = disabled (use each service's own limit), 0 = process none
     process_venue_total_limit: int = -1
@@ -361,7 +998,7 @@ class Settings(BaseSettings):
     def __init__(self, **kwargs):
         """Initialize settings from JSON file and environment variables.
 
-        Priority: env vars > JSON config > defaults
+        Priority: env vars > secret stores (credentials only) > JSON config > defaults
         """
         # Load JSON config first (if CONFIG_FILE is set)
         json_config = load_json_config()
@@ -379,6 +1016,19 @@ class Settings(BaseSettings):
 
         super().__init__(**merged_kwargs)
 
+        # Environment profile defaults, below anything set explicitly.
+        self.environment = _ENVIRONMENT_ALIASES.get(
+            self.environment.strip().lower(), self.environment.strip().lower()
+        )
+        for name, value in PROFILE_DEFAULTS.get(self.environment, {}).items():
+            if name not in self.model_fields_set:
+                setattr(self, name, value)
+
+        # Credentials from the secret stores, unless passed or set in env.
+        explicit = {k for k in SECRET_SETTINGS if k in kwargs or os.getenv(k.upper()) is not None}
+        for name, value in resolve_secrets(build_providers(self), skip=explicit).items():
+            setattr(self, name, value)
+
         if not self.project_root:
             # Use PROJECT_ROOT env var or current working directory
             self.project_root = os.getenv("PROJECT_ROOT", os.getcwd())
@@ -397,6 +1047,160 @@ class Settings(BaseSettings):
         """Get Redis connection address in host:port format."""
         return f"{self.redis_host}:{self.redis_port}"
 
+    def config_errors(self) -> list[str]:
+        """Settings the server cannot run with, one message each (empty = OK)."""
+        errors = []
+
+        def one_of(name: str, allowed: tuple) -> None:
+            if getattr(self, name) not in allowed:
+                errors.append(f"{name} must be one of {', '.join(allowed)}")
+
+        if not self.demo_mode and self.besttime_mode != "replay":
+            for name in ("besttime_private_key", "besttime_public_key"):
+                if not getattr(self, name):
+                    errors.append(f"{name} is required (env {name.upper()})")
+        for name in ("server_port", "redis_port", "rds_port"):
+            if not 1 <= getattr(self, name) <= 65535:
+                errors.append(f"{name} must be in 1-65535")
+        if not 0 <= self.diagnostics_port <= 65535:
+            errors.append("diagnostics_port must be in 0-65535 (0 = off)")
+        elif self.diagnostics_port == self.server_port:
+            errors.append("diagnostics_port must differ from server_port")
+        if self.grpc_enabled:
+            if not 1 <= self.grpc_port <= 65535:
+                errors.append("grpc_port must be in 1-65535")
+            elif self.grpc_port in (self.server_port, self.diagnostics_port):
+                errors.append("grpc_port must differ from server_port and diagnostics_port")
+            if not self.grpc_api_keys:
+                errors.append("grpc_enabled needs grpc_api_keys")
+        if bool(self.server_tls_certfile) != bool(self.server_tls_keyfile):
+            errors.append("server_tls_certfile and server_tls_keyfile must be set together")
+        for name in ("server_tls_certfile", "server_tls_keyfile"):
+            path = getattr(self, name)
+            if path and not Path(path).is_file():
+                errors.append(f"{name} {path} does not exist")
+        for name in (
+            "server_request_timeout_seconds",
+            "server_idle_timeout_seconds",
+            "server_shutdown_timeout_seconds",
+        ):
+            if getattr(self, name) < 0:
+                errors.append(f"{name} must not be negative")
+        for name in (
+            "venues_live_refresh_minutes",
+            "venues_catalog_refresh_minutes",
+            "redis_projection_minutes",
+            "retry_queue_interval_minutes",
+            "dedup_max_distance_m",
+            "venue_quarantine_max_entries",
+        ):
+            if getattr(self, name) <= 0:
+                errors.append(f"{name} must be positive")
+        if not isinstance(logging.getLevelName(self.log_level.upper()), int):
+            errors.append(f"log_level {self.log_level!r} is not a logging level")
+        one_of("environment", ENVIRONMENTS)
+        one_of("log_format", ("text", "json"))
+        one_of("redis_mode", ("standalone", "sentinel", "cluster"))
+        one_of("besttime_mode", ("live", "record", "replay"))
+        one_of("besttime_key_rotation", ("failover", "round_robin"))
+        one_of("venue_data_provider", ("besttime", "google_places"))
+        one_of("venue_media_provider", ("enrichment", "none"))
+        if self.venue_data_provider == "google_places" and not self.google_places_api_key:
+            errors.append("venue_data_provider google_places needs google_places_api_key")
+        if self.vault_kv_version not in (1, 2):
+            errors.append("vault_kv_version must be 1 or 2")
+        if self.backup_enabled:
+            one_of("backup_storage", ("s3", "gcs"))
+            if self.backup_storage == "s3" and not self.s3_access_key_id:
+                errors.append("backup_storage s3 needs s3_access_key_id")
+            if not (self.backup_bucket or (self.backup_storage == "s3" and self.s3_bucket)):
+                errors.append("backup_enabled needs backup_bucket")
+        if self.events_broker not in ("", "kafka", "nats"):
+            errors.append("events_broker must be empty, kafka or nats")
+        elif self.events_broker:
+            servers = {
+                "kafka": "events_kafka_bootstrap_servers",
+                "nats": "events_nats_servers",
+            }[self.events_broker]
+            if not getattr(self, servers):
+                errors.append(f"events_broker {self.events_broker} needs {servers}")
+            for name in ("events_outbox_max", "events_publish_interval_seconds",
+                         "events_publish_batch_size", "events_publish_timeout_seconds"):
+                if getattr(self, name) <= 0:
+                    errors.append(f"{name} must be positive")
+        if self.regions:
+            # Imported here: app.services imports this module.
+            from app.services.regions import RegionRegistry
+
+            try:
+                RegionRegistry(self.regions)
+            except (TypeError, ValueError) as e:
+                errors.append(f"regions: {e}")
+        if self.tenants:
+            try:
+                registry = TenantRegistry(self.tenants)
+            except (TypeError, ValueError) as e:
+                errors.append(f"tenants: {e}")
+            else:
+                for tenant in registry:
+                    if not TENANT_ID.fullmatch(tenant.id):
+                        errors.append(f"tenant id {tenant.id!r} must match {TENANT_ID.pattern}")
+                keys = [key for tenant in registry for key in tenant.api_keys]
+                if len(keys) != len(set(keys)):
+                    errors.append("tenants share an API key")
+            if self.default_tenant and self.default_tenant not in self.tenants:
+                errors.append(f"default_tenant {self.default_tenant} is not in tenants")
+        elif self.default_tenant:
+            errors.append("default_tenant needs tenants")
+        if not 0 <= self.tracing_sample_ratio <= 1:
+            errors.append("tracing_sample_ratio must be in 0-1")
+        if not 0 < self.dedup_min_name_similarity <= 1:
+            errors.append("dedup_min_name_similarity must be in (0, 1]")
+        bad_capacities = sorted(t for t, c in self.occupancy_capacity_by_type.items() if c <= 0)
+        if bad_capacities:
+            errors.append(f"occupancy_capacity_by_type must be positive: {', '.join(bad_capacities)}")
+        if self.occupancy_default_capacity <= 0:
+            errors.append("occupancy_default_capacity must be positive")
+        if self.occupancy_reference_dwell_minutes <= 0:
+            errors.append("occupancy_reference_dwell_minutes must be positive")
+        # Imported here: app.services imports this module.
+        from app.services.venue_score import score_errors
+
+        errors += score_errors(self.score_weights)
+        if self.score_reviews_saturation <= 0:
+            errors.append("score_reviews_saturation must be positive")
+        for name in (
+            "checkin_window_minutes", "checkin_dedup_minutes",
+            "checkin_full_count", "checkin_min_count",
+            "checkin_max_distance_m", "checkin_max_per_ip",
+            "venue_suggestions_max_pending", "venue_suggestions_max_entries",
+            "venue_media_max_photos",
+        ):
+            if getattr(self, name) <= 0:
+                errors.append(f"{name} must be positive")
+        if self.recommendations_enabled:
+            from app.services.recommendations import weight_errors
+
+            errors += weight_errors(self.recommendation_weights)
+            for name in (
+                "recommendation_radius_km", "recommendation_max_seeds",
+                "recommendation_max_visits", "recommendation_history_days",
+            ):
+                if getattr(self, name) <= 0:
+                    errors.append(f"{name} must be positive")
+        if self.history_export_enabled and self.live_history_window_hours < 25:
+            errors.append(
+                "live_history_window_hours must be at least 25 with history_export_enabled "
+                "(the export reads the whole previous day)"
+            )
+        return errors
+
+    def check(self) -> None:
+        """Raise ConfigError listing every problem of config_errors()."""
+        errors = self.config_errors()
+        if errors:
+            raise ConfigError("invalid configuration: " + "; ".join(errors))
+
 
 # Global settings instance
 settings = Settings()
@@ -2,17 +2,26 @@
 import logging
 from typing import Optional
 
-import redis
-
 from app.config import Settings
 from app.db import GeoRedisClient
+from app.db.redis_factory import build_redis_client, prewarm_pool
+from app.db.value_compression import ValueCompressor
 from app.dao import RedisVenueDAO, VenueBudgetDao
+from app.dao.snapshot_venue_dao import SnapshotVenueDAO
 from app.dao.venue_repository import VenueRepository
-from app.api import BestTimeAPIClient
+from app.api import BestTimeAPIClient, RetryPolicy
 from app.api.google_places_client import GooglePlacesAPIClient
+from app.models import SearchParams
 from app.services import VenuesRefresherService, VenueBudgetService
 from app.handlers import AddVenueHandler
+from app.services.area_crowd_index import AreaCrowdIndexService, parse_areas
 from app.services.batch_add_service import BatchAddService
+from app.services.busyness_trend import TrendService
+from app.services.checkins import CheckinService
+from app.services import job_lock
+from app.services.besttime_quota import BestTimeQuotaService
+from app.services.filter_tuner import FilterTuner
+from app.services.public_stats import PublicStatsService
 from app.services.google_places_enrichment_service import GooglePlacesEnrichmentService
 from app.services.photo_enrichment_service import PhotoEnrichmentService
 from app.api.apify_instagram_client import ApifyInstagramClient
@@ -20,6 +29,14 @@ from app.services.instagram_enrichment_service import InstagramEnrichmentService
 from app.services.instagram_posts_enrichment_service import InstagramPostsEnrichmentService
 from app.services.instagram_validator import InstagramValidator
 from app.api.s3_client import S3Client
+from app.api.osm_overpass_client import OverpassClient
+from app.api.fcm_client import (
+    FcmClient,
+    service_account_project_id,
+    service_account_token_provider,
+)
+from app.api.foursquare_client import FoursquareClient
+from app.services.open_data_enrichment_service import OpenDataEnrichmentService
 from app.api.apify_instagram_highlights_client import ApifyInstagramHighlightsClient
 from app.api.apify_gmaps_extractor_client import ApifyGMapsExtractorClient
 from app.api.openai_menu_client import OpenAIMenuClient
@@ -29,7 +46,23 @@ from app.api.openai_vibe_client import OpenAIVibeClient
 from app.services.vibe_classifier_service import VibeClassifierService
 from app.handlers import VenueHandler
 from app.services.engagement_service import EngagementService
+from app.services.push_notifications import PushNotifier
+from app.services.recommendations import RecommendationService, UserHistoryStore
+from app.services.nearby_precompute import NearbyPrecomputeService
 from app.services.redis_projection_service import RedisProjectionService
+from app.services.retry_queue import RetryQueue
+from app.services.event_publishing import EventOutbox, EventRelay, build_event_publisher
+from app.services.stale_eviction import StaleEvictionService
+from app.services.venue_dedup import VenueDeduplicator
+from app.services.venue_suggestions import VenueSuggestionService
+from app.services.venue_media import VenueMediaService, build_media_provider
+from app.services.venue_validation import VenueQuarantine
+from app.services.webhooks import WebhookService
+from app.services.crowd_providers import BestTimeCrowdProvider, CrowdProviderRegistry, RegionalProvider
+from app.services.venue_data_providers import build_venue_data_provider
+from app.services.partner_occupancy_service import PartnerCrowdProvider, PartnerOccupancyService
+from app.services.regions import RegionRegistry
+from app.tenancy import TenantRegistry
 
 logger = logging.getLogger(__name__)
 
@@ -41,14 +74,32 @@ class Container:
     Initializes and wires up all application dependencies.
     """
 
-    def __init__(self, settings: Settings):
+    def __init__(
+        self,
+        settings: Settings,
+        *,
+        redis_client=None,
+        besttime_api: Optional[BestTimeAPIClient] = None,
+        rds_store=None,
+    ):
         """Initialize container with all dependencies.
 
         Args:
             settings: Application settings
+            redis_client: raw Redis client to use instead of building one from
+                settings (tests, embedding); the container does not close it
+            besttime_api: BestTime client to use instead of building one
+            rds_store: RDS store to use instead of connecting to rds_sqlalchemy_url
         """
-        logger.info(f"[Container] Initializing container")
+        logger.info(f"[Container] Initializing container (environment={settings.environment})")
         self.settings = settings
+        # Configured tenants (app/tenancy.py); empty = single-tenant.
+        self.tenants = TenantRegistry(settings.tenants, settings.default_tenant)
+        # Region profiles (app/services/regions.py); empty = the built-in
+        # Recife locations.
+        self.regions = RegionRegistry(settings.regions)
+        self._closed = False
+        self._owns_redis = redis_client is None
 
         # Global processing cap — applied to all enrichment services
         global_cap = settings.process_venue_total_limit  # -1 = disabled
@@ -65,42 +116,86 @@ class Container:
             logger.info(f"[Container] Global process_venue_total_limit={global_cap}")
 
         # Initialize Redis client
-        logger.info(
-            f"[Container] Connecting to Redis at {settings.redis_host}:{settings.redis_port}"
-        )
-        redis_internal_client = redis.Redis(
-            host=settings.redis_host,
-            port=settings.redis_port,
-            password=settings.redis_password,
-            db=settings.redis_db,
-            decode_responses=True,
-        )
+        if redis_client is not None:
+            redis_internal_client = redis_client
+        else:
+            logger.info(f"[Container] Connecting to Redis (mode={settings.redis_mode})")
+            redis_internal_client = build_redis_client(settings)
 
         # Test Redis connection
         try:
             redis_internal_client.ping()
             logger.info("[Container] Redis connection successful")
+            opened = prewarm_pool(redis_internal_client, settings.redis_min_idle_connections)
+            if opened:
+                logger.info(f"[Container] Prewarmed {opened} idle Redis connections")
         except Exception as e:
             logger.error(f"[Container] Failed to connect to Redis: {e}")
             raise
 
         # Initialize Redis client wrapper
-        self.redis_client = GeoRedisClient(redis_internal_client)
-
-        # Redis-only DAO used by the projection/rebuild path (writes Redis only,
-        # never RDS) so a rebuild does not re-write the system of record.
-        self.serving_redis_dao = RedisVenueDAO(self.redis_client)
+        self.redis_client = GeoRedisClient(
+            redis_internal_client,
+            compressor=ValueCompressor(
+                settings.redis_compression, settings.redis_compression_min_bytes
+            ),
+        )
 
         # RDS system-of-record store. RDS is the durable truth for all venue +
         # admin data; Redis is the serving/geo projection.
         from app.dao.rds_venue_store import RdsVenueStore
 
-        try:
-            self.rds_store = RdsVenueStore(settings.rds_sqlalchemy_url)
-            logger.info("[Container] RDS system-of-record initialized")
-        except Exception as e:
-            logger.error(f"[Container] Failed to init RDS store: {e}")
-            raise
+        self._owns_rds = rds_store is None
+        if rds_store is not None:
+            self.rds_store = rds_store
+        else:
+            try:
+                self.rds_store = RdsVenueStore(settings.rds_sqlalchemy_url)
+                logger.info("[Container] RDS system-of-record initialized")
+            except Exception as e:
+                logger.error(f"[Container] Failed to init RDS store: {e}")
+                raise
+        # A region outside the geo-fence would pay for venues never served.
+        if self.regions:
+            outside = self.regions.outside_fence(self.rds_store.get_geo_fence())
+            if outside:
+                raise ValueError(
+                    f"regions {', '.join(outside)} lie outside the geo-fence; "
+                    "add their cities with PUT /admin/config/geofence"
+                )
+
+        # Redis-only DAO used by the projection/rebuild path (writes Redis only,
+        # never RDS) so a rebuild does not re-write the system of record.
+        # It is also the DAO the serving handler reads through, so it carries
+        # the optional in-process read cache.
+        read_cache = None
+        if settings.redis_read_cache_enabled:
+            from app.dao.read_cache import DaoReadCache
+            read_cache = DaoReadCache(
+                settings.redis_read_cache_max_entries,
+                settings.redis_read_cache_ttl_seconds,
+            )
+            logger.info(
+                f"[Container] Serving DAO read cache enabled "
+                f"(max_entries={settings.redis_read_cache_max_entries}, "
+                f"ttl={settings.redis_read_cache_ttl_seconds}s)"
+            )
+        if settings.venue_read_backend == "redis":
+            self.serving_redis_dao = RedisVenueDAO(self.redis_client, read_cache=read_cache)
+        else:
+            # Nearby reads may be served from RDS (app/dao/rds_fallback_dao.py).
+            from app.dao.rds_fallback_dao import RdsFallbackVenueDAO
+            self.serving_redis_dao = RdsFallbackVenueDAO(
+                self.redis_client,
+                self.rds_store,
+                mode=settings.venue_read_backend,
+                read_cache=read_cache,
+            )
+            logger.info(
+                f"[Container] Serving nearby reads with venue_read_backend="
+                f"{settings.venue_read_backend}"
+            )
+
         # Pipelines receive this as their venue DAO: it reads its data inputs and
         # cache-freshness gating from RDS (truth) and writes RDS-only — the
         # scheduled projector is the sole Redis writer for pipeline data. Geo reads
@@ -111,7 +206,7 @@ class Container:
         )
 
         # Initialize BestTime API client
-        self.besttime_api = BestTimeAPIClient(
+        self.besttime_api = besttime_api or BestTimeAPIClient(
             api_key_public=settings.besttime_public_key,
             api_key_private=settings.besttime_private_key,
             base_url=settings.besttime_endpoint_base_v1,
@@ -119,7 +214,35 @@ class Container:
             search_rate_per_minute=settings.besttime_search_rate_per_minute,
             search_rate_per_hour=settings.besttime_search_rate_per_hour,
             rate_max_wait_seconds=settings.besttime_rate_max_wait_seconds,
+            rate_per_second=settings.besttime_rate_per_second,
+            rate_burst=settings.besttime_rate_burst,
+            circuit_failure_threshold=settings.besttime_circuit_failure_threshold,
+            circuit_cooldown_seconds=settings.besttime_circuit_cooldown_seconds,
+            user_agent=settings.besttime_user_agent,
+            extra_headers=settings.besttime_extra_headers,
+            proxy_url=settings.besttime_proxy_url,
+            mode=settings.besttime_mode,
+            fixtures_dir=settings.besttime_fixtures_dir,
+            extra_key_pairs=[
+                (pair["public"], pair["private"]) for pair in settings.besttime_extra_key_pairs
+            ],
+            key_rotation=settings.besttime_key_rotation,
+            key_exhausted_cooldown_seconds=settings.besttime_key_exhausted_cooldown_seconds,
+            strict_decoding=settings.besttime_strict_decoding,
+            retry_policy=RetryPolicy(
+                max_attempts=settings.besttime_retry_max_attempts,
+                backoff_base_seconds=settings.besttime_retry_backoff_base_seconds,
+                backoff_max_seconds=settings.besttime_retry_backoff_max_seconds,
+                jitter_ratio=settings.besttime_retry_jitter,
+                retry_on_status=frozenset(settings.besttime_retry_statuses),
+            ),
         )
+        # Daily credit usage per endpoint family (GET /admin/quota); with a
+        # budget set, the non-critical scheduled jobs pause once it is spent.
+        self.besttime_quota = BestTimeQuotaService(
+            redis_internal_client, daily_budget=settings.besttime_daily_credit_budget
+        )
+        self.besttime_api.set_usage_recorder(self.besttime_quota.record)
 
         # Initialize Google Places API client (for enrichment and photos)
         self.google_places_api = None
@@ -180,6 +303,7 @@ class Container:
                 enrichment_limit=_capped(settings.instagram_enrichment_limit),
                 cache_ttl_days=settings.instagram_cache_ttl_days,
                 not_found_ttl_days=settings.instagram_not_found_cache_ttl_days,
+                regions=self.regions,
             )
             logger.info("[Container] Instagram Enrichment service initialized")
         else:
@@ -200,6 +324,48 @@ class Container:
             )
             logger.info("[Container] Instagram Posts Enrichment service initialized")
 
+        # OpenStreetMap / Foursquare directory enrichment (categories, website,
+        # phone, opening hours on the venue document).
+        self.open_data_clients = []
+        for source in settings.open_data_sources:
+            if source == "osm":
+                self.open_data_clients.append(OverpassClient(settings.osm_overpass_endpoint))
+            elif source == "foursquare" and settings.foursquare_api_key:
+                self.open_data_clients.append(FoursquareClient(settings.foursquare_api_key))
+            else:
+                logger.warning(f"[Container] Open data source {source!r} skipped (unknown or no key)")
+        self.open_data_enrichment_service = None
+        if self.open_data_clients:
+            self.open_data_enrichment_service = OpenDataEnrichmentService(
+                self.open_data_clients,
+                self.pipeline_repository,
+                redis_client=redis_internal_client,
+                match_radius_m=settings.open_data_match_radius_m,
+                min_name_similarity=settings.open_data_min_name_similarity,
+                enrichment_limit=_capped(settings.open_data_enrichment_limit),
+                miss_ttl_days=settings.open_data_miss_ttl_days,
+            )
+            logger.info("[Container] Open data enrichment service initialized")
+
+        # Venue photos / website / social links (Venue.media): operator edits
+        # and the configured media provider.
+        self.venue_media_service = None
+        if settings.venue_media_enabled:
+            self.venue_media_service = VenueMediaService(
+                self.pipeline_repository,
+                provider=build_media_provider(
+                    settings.venue_media_provider,
+                    self.pipeline_repository,
+                    max_photos=settings.venue_media_max_photos,
+                ),
+                enrichment_limit=_capped(settings.venue_media_enrichment_limit),
+                max_photos=settings.venue_media_max_photos,
+            )
+            logger.info(
+                f"[Container] Venue media service initialized "
+                f"(provider={settings.venue_media_provider})"
+            )
+
         # Initialize Instagram Highlights client (for menu photo discovery from IG)
         self.apify_instagram_highlights_client = None
         if settings.apify_api_token:
@@ -260,6 +426,58 @@ class Container:
                 "(missing S3 bucket or S3 credentials)"
             )
 
+        # Nightly analytics export (needs: S3 credentials; own bucket optional)
+        self.history_export_service = None
+        if settings.history_export_enabled and settings.s3_access_key_id:
+            from app.services.history_export_service import HistoryExportService
+
+            export_bucket = settings.history_export_bucket or settings.s3_bucket
+            export_storage = self.s3_client
+            if export_storage is None or export_bucket != settings.s3_bucket:
+                export_storage = S3Client(
+                    bucket=export_bucket,
+                    region=settings.s3_region,
+                    access_key_id=settings.s3_access_key_id,
+                    secret_access_key=settings.s3_secret_access_key,
+                )
+            self.history_export_service = HistoryExportService(
+                venue_dao=self.serving_redis_dao,
+                rds_store=self.rds_store,
+                storage=export_storage,
+                path_template=settings.history_export_path_template,
+                fmt=settings.history_export_format,
+            )
+            logger.info(
+                f"[Container] History export initialized "
+                f"(s3://{export_bucket}, format={settings.history_export_format})"
+            )
+
+        # Venue data snapshots to S3 or GCS, with an admin-triggered restore.
+        self.backup_service = None
+        if settings.backup_enabled:
+            from app.services.backup_service import BackupService
+
+            if settings.backup_storage == "gcs":
+                from app.api.gcs_client import GcsClient
+
+                backup_storage = GcsClient(settings.backup_bucket, settings.gcs_credentials_file)
+            elif self.s3_client is not None and settings.backup_bucket in ("", settings.s3_bucket):
+                backup_storage = self.s3_client
+            else:
+                backup_storage = S3Client(
+                    bucket=settings.backup_bucket or settings.s3_bucket,
+                    region=settings.s3_region,
+                    access_key_id=settings.s3_access_key_id,
+                    secret_access_key=settings.s3_secret_access_key,
+                )
+            self.backup_service = BackupService(
+                self.pipeline_repository, backup_storage, prefix=settings.backup_prefix
+            )
+            logger.info(
+                f"[Container] Backups initialized ({settings.backup_storage}, "
+                f"prefix={settings.backup_prefix})"
+            )
+
         # Initialize Menu Extraction (needs: openai_api_key + s3_client for presigned URLs)
         if settings.openai_api_key and self.s3_client:
             self.openai_menu_client = OpenAIMenuClient(
@@ -308,6 +526,12 @@ class Container:
                 "(missing OpenAI API key)"
             )
 
+        # Optional per-region bandit over the discovery filter profile.
+        self.filter_tuner = (
+            FilterTuner(redis_internal_client, epsilon=settings.filter_tuner_epsilon)
+            if settings.filter_tuner_enabled else None
+        )
+
         # Initialize services. Serving reads the Redis-only DAO (serving_dao) so
         # public serving is independent of RDS at request time (an RDS outage
         # cannot break nearby serving) and unaffected by the pipeline RDS reads.
@@ -321,10 +545,212 @@ class Container:
             dev_lat=settings.dev_lat,
             dev_lng=settings.dev_lng,
             dev_radius=settings.dev_radius,
+            live_refresh_cooldown_seconds=settings.live_refresh_cooldown_seconds,
+            search_params=SearchParams(**settings.discovery_search),
+            filter_tuner=self.filter_tuner,
+            live_fetch_concurrency=settings.live_fetch_concurrency,
+            discovery_concurrency=settings.discovery_concurrency,
+            locations_file=settings.discovery_locations_file,
+            report_history_size=settings.refresh_report_history_size,
+            live_skip_closed=settings.live_refresh_skip_closed,
+            live_open_margin_minutes=settings.live_refresh_open_margin_minutes,
+        )
+        if self.regions:
+            self.regions.set_redis(redis_internal_client)
+            self.venues_refresher_service.set_regions(self.regions)
+        # Busyness sources behind the refresher. BestTime covers every venue;
+        # regional/partner providers are registered ahead of it so the merge
+        # policy can prefer them where they have data.
+        def _venue_location(venue_id: str):
+            venue = self.pipeline_repository.get_venue(venue_id)
+            return (venue.venue_lat, venue.venue_lng) if venue else None
+
+        # Partner occupancy readings are short-lived live data, so they live in
+        # Redis only (like the live history) rather than in the RDS record.
+        self.partner_occupancy_service = None
+        crowd_providers = [BestTimeCrowdProvider(
+            self.besttime_api,
+            live_timeout=settings.besttime_live_timeout_seconds or None,
+            weekly_timeout=settings.besttime_weekly_timeout_seconds or None,
+        )]
+        if settings.partner_api_keys:
+            self.partner_occupancy_service = PartnerOccupancyService(
+                self.serving_redis_dao,
+                api_keys=settings.partner_api_keys,
+                partner_venues=settings.partner_venues,
+                max_age_minutes=settings.partner_reading_max_age_minutes,
+                validator=self.venues_refresher_service.busyness_validator,
+            )
+            crowd_providers.insert(0, RegionalProvider(
+                PartnerCrowdProvider(self.serving_redis_dao),
+                venue_ids=self.partner_occupancy_service.venue_ids(),
+            ))
+        self.crowd_provider_registry = CrowdProviderRegistry(
+            crowd_providers,
+            policy=settings.crowd_merge_policy,
+            locate=_venue_location,
+        )
+        self.venues_refresher_service.set_crowd_providers(self.crowd_provider_registry)
+        # Discovery source (BestTime /venues/filter or Google Places Nearby Search).
+        self.venues_refresher_service.set_venue_data_provider(build_venue_data_provider(
+            settings.venue_data_provider,
+            self.besttime_api,
+            google_places_api=self.google_places_api,
+            google_included_types=settings.google_places_discovery_types,
+        ))
+
+        # Failed upserts / live fetches go to a Redis retry queue when enabled.
+        self.retry_queue = None
+        if settings.retry_queue_enabled:
+            self.retry_queue = RetryQueue(
+                redis_internal_client,
+                max_attempts=settings.retry_queue_max_attempts,
+                backoff_seconds=settings.retry_queue_backoff_seconds,
+                max_backoff_seconds=settings.retry_queue_max_backoff_seconds,
+            )
+            self.venues_refresher_service.set_retry_queue(self.retry_queue)
+
+        # Venue events for the message broker, through a Redis outbox.
+        self.event_outbox = None
+        self.event_relay = None
+        event_publisher = build_event_publisher(settings)
+        if event_publisher is not None:
+            self.event_outbox = EventOutbox(redis_internal_client, max_events=settings.events_outbox_max)
+            self.event_relay = EventRelay(
+                self.event_outbox, event_publisher, batch_size=settings.events_publish_batch_size
+            )
+            self.venues_refresher_service.set_event_outbox(self.event_outbox)
+
+        # Per-neighborhood crowd index, recomputed after each live refresh.
+        self.area_crowd_index = None
+        if settings.crowd_index_areas:
+            self.area_crowd_index = AreaCrowdIndexService(
+                self.pipeline_repository,
+                redis_internal_client,
+                parse_areas(settings.crowd_index_areas),
+                capacity_hints=settings.area_capacity_hints,
+                default_capacity=settings.area_default_capacity,
+            )
+            self.venues_refresher_service.set_area_index(self.area_crowd_index)
+
+        # Busyness alert webhooks, evaluated after each live refresh.
+        self.webhook_service = None
+        if settings.webhooks_enabled:
+            self.webhook_service = WebhookService(
+                self.pipeline_repository,
+                redis_internal_client,
+                max_webhooks=settings.webhooks_max,
+                require_https=settings.webhooks_require_https,
+                timeout_seconds=settings.webhooks_timeout_seconds,
+                max_attempts=settings.webhooks_max_attempts,
+                backoff_seconds=settings.webhooks_backoff_seconds,
+                api_keys=settings.webhooks_api_keys,
+                max_concurrency=settings.webhooks_max_concurrency,
+            )
+            self.venues_refresher_service.set_webhooks(self.webhook_service)
+
+        # Favorite-venue pushes through FCM, evaluated after each live refresh.
+        self.push_notifier = None
+        if settings.push_notifications_enabled:
+            if not settings.fcm_credentials_file:
+                logger.warning(
+                    "[Container] push_notifications_enabled without fcm_credentials_file; "
+                    "push notifications disabled"
+                )
+            else:
+                fcm = FcmClient(
+                    settings.fcm_project_id
+                    or service_account_project_id(settings.fcm_credentials_file),
+                    service_account_token_provider(settings.fcm_credentials_file),
+                )
+                self.push_notifier = PushNotifier(
+                    self.pipeline_repository,
+                    redis_internal_client,
+                    fcm,
+                    default_threshold=settings.push_default_threshold,
+                )
+                self.venues_refresher_service.set_push_notifier(self.push_notifier)
+                logger.info("[Container] FCM push notifications initialized")
+
+        # Venues the refresher's or the repository's validation rejected or corrected.
+        self.venue_quarantine = VenueQuarantine(
+            redis_internal_client, max_entries=settings.venue_quarantine_max_entries
         )
+        self.venues_refresher_service.set_venue_quarantine(self.venue_quarantine)
+        self.pipeline_repository.set_venue_quarantine(self.venue_quarantine)
+
+        # Ages out venues and live forecasts the refreshers stopped touching.
+        self.stale_eviction_service = StaleEvictionService(
+            self.pipeline_repository,
+            venue_max_age_days=settings.stale_venue_max_age_days,
+            live_max_age_minutes=settings.stale_live_forecast_max_age_minutes,
+            dry_run=settings.stale_eviction_dry_run,
+        )
+
+        # Merges venues that are one place under two ids; checked at upsert
+        # time only with dedup_enabled, the cleanup runs from the admin API too.
+        self.venue_deduplicator = VenueDeduplicator(
+            self.pipeline_repository,
+            redis_internal_client,
+            max_distance_m=settings.dedup_max_distance_m,
+            min_similarity=settings.dedup_min_name_similarity,
+            drop_words=[w for r in self.regions for w in (r.name, r.search_city, *r.cities)],
+            dry_run=settings.dedup_cleanup_dry_run,
+        )
+        if settings.dedup_enabled:
+            self.venues_refresher_service.set_deduplicator(self.venue_deduplicator)
+
+        # Last-known-good copy of the serving data for Redis outages; filled
+        # by the scheduled nearby_snapshot job.
+        self.nearby_snapshot = None
+        if settings.nearby_snapshot_enabled:
+            self.nearby_snapshot = SnapshotVenueDAO(
+                self.serving_redis_dao,
+                max_age_seconds=settings.nearby_snapshot_max_age_minutes * 60,
+            )
+
+        # Rising/falling/peak indicators on nearby items with live busyness.
+        self.trend_service = None
+        if settings.busyness_trend_enabled:
+            self.trend_service = TrendService(
+                self.serving_redis_dao, threshold=settings.busyness_trend_threshold,
+            )
+
+        # First-party check-ins, standing in for missing live busyness.
+        self.checkin_service = None
+        if settings.checkins_enabled:
+            self.checkin_service = CheckinService(
+                self.redis_client.client,
+                window_minutes=settings.checkin_window_minutes,
+                dedup_minutes=settings.checkin_dedup_minutes,
+                full_count=settings.checkin_full_count,
+                min_count=settings.checkin_min_count,
+                max_distance_m=settings.checkin_max_distance_m,
+                max_per_ip=settings.checkin_max_per_ip,
+            )
 
         # Initialize handlers (serving reads the Redis-only DAO — see above).
-        self.venue_handler = VenueHandler(self.serving_redis_dao)
+        self.venue_handler = VenueHandler(
+            self.serving_redis_dao, snapshot=self.nearby_snapshot, trend_service=self.trend_service,
+            checkin_service=self.checkin_service,
+        )
+        # Hottest nearby queries, rendered after each projection.
+        self.nearby_precompute = None
+        if settings.nearby_precompute_enabled:
+            self.nearby_precompute = NearbyPrecomputeService(
+                self.venue_handler,
+                self.redis_client.client,
+                locations_source=lambda: self.venues_refresher_service.active_locations()[1],
+                radii_km=settings.nearby_precompute_radii_km,
+                ttl_seconds=settings.nearby_precompute_ttl_seconds,
+                verbose_variants=settings.nearby_precompute_verbose,
+            )
+        # Coarse public aggregates for GET /v1/stats/public (status page).
+        self.public_stats_service = PublicStatsService(
+            self.serving_redis_dao,
+            redis_client=self.redis_client.client,
+            ttl_seconds=settings.public_stats_cache_seconds,
+        )
 
         # Engagement (favorites/hot_likes) write-through API service, and the
         # projection service that rebuilds the Redis serving projection from RDS.
@@ -333,6 +759,20 @@ class Container:
             rds_store=self.rds_store,
             pseudonymization_key=settings.engagement_pseudonymization_key,
         )
+        # Recommendations from the favorites projection and recorded visits.
+        self.recommendation_service = None
+        if settings.recommendations_enabled:
+            self.recommendation_service = RecommendationService(
+                self.serving_redis_dao,
+                UserHistoryStore(
+                    self.redis_client.client,
+                    max_visits=settings.recommendation_max_visits,
+                    history_days=settings.recommendation_history_days,
+                ),
+                weights=settings.recommendation_weights,
+                radius_km=settings.recommendation_radius_km,
+                max_seeds=settings.recommendation_max_seeds,
+            )
         self.redis_projection_service = RedisProjectionService(
             redis_only_dao=self.serving_redis_dao,
             rds_store=self.rds_store,
@@ -345,6 +785,15 @@ class Container:
         from app.services.force_update import validate_force_update_config
         from app.services.venue_eligibility import EligibilityConfig
         from app.services.vibe_modes_config import validate_vibe_modes_config
+        from app.services.venue_notes import VenueNotesService, validate_venue_notes_config
+        from app.services.venue_closures import (
+            VenueClosuresService,
+            validate_venue_closures_config,
+        )
+        from app.services.discovery_locations import (
+            DiscoveryLocationsService,
+            validate_discovery_points_config,
+        )
 
         def _validate_eligibility_config(value):
             EligibilityConfig.from_dict(value, from_admin_override=True)  # raises on invalid
@@ -357,8 +806,22 @@ class Container:
                 "venue_eligibility": _validate_eligibility_config,
                 "force_update": validate_force_update_config,
                 "vibe_modes": validate_vibe_modes_config,
+                "venue_notes": validate_venue_notes_config,
+                "venue_closures": validate_venue_closures_config,
+                "discovery_points": validate_discovery_points_config,
             },
         )
+        self.venue_notes_service = VenueNotesService(self.admin_config_service)
+        # A dedup merge moves what these keep under the duplicate's id to the
+        # kept venue; partner venue ids resolve through the aliases instead.
+        self.venue_deduplicator.add_reference_mover(self.engagement_service.move_venue)
+        self.venue_deduplicator.add_reference_mover(self.venue_notes_service.move_venue)
+        if self.checkin_service is not None:
+            self.venue_deduplicator.add_reference_mover(self.checkin_service.move_venue)
+        if self.partner_occupancy_service is not None:
+            self.partner_occupancy_service.set_alias_resolver(self.venue_deduplicator.alias_of)
+        self.venue_closures_service = VenueClosuresService(self.admin_config_service)
+        self.discovery_locations_service = DiscoveryLocationsService(self.admin_config_service)
         # The serve handler resolves the live-busyness freshness window through the
         # admin-config mirror; wire it now that the service exists (venue_handler
         # was built above, before admin_config_service).
@@ -374,8 +837,16 @@ class Container:
         # Let the periodic projector self-heal the eligibility serving mirror from
         # its rows each cycle (wired after both exist; the projector is built above).
         self.redis_projection_service.eligibility_rule_service = self.eligibility_rule_service
+        # A restore writes RDS; project it right away (per tenant when configured).
+        if self.backup_service is not None:
+            projection = self.redis_projection_service
+            self.backup_service.project = (
+                (lambda: projection.rebuild_redis_for_tenants(self.tenants))
+                if self.tenants else projection.rebuild_redis_from_rds
+            )
 
-        # Monthly budget DAO + service (used by add-by-address + discovery).
+        # Monthly budget DAO + service (used by add-by-address, discovery and
+        # catalog imports).
         self.venue_budget_dao = VenueBudgetDao(redis_internal_client)
         self.venue_budget_service = VenueBudgetService(
             redis_client=redis_internal_client,
@@ -397,6 +868,16 @@ class Container:
             rds_store=self.rds_store,
         )
 
+        # User-suggested venues, added through add_venue_handler on approval.
+        self.venue_suggestion_service = None
+        if settings.venue_suggestions_enabled:
+            self.venue_suggestion_service = VenueSuggestionService(
+                redis_internal_client,
+                self.add_venue_handler,
+                max_pending=settings.venue_suggestions_max_pending,
+                max_entries=settings.venue_suggestions_max_entries,
+            )
+
         # Server-side batch venue-add: runs a curated list through the same
         # add_venue_handler in one pollable background job.
         self.batch_add_service = BatchAddService(
@@ -410,11 +891,36 @@ class Container:
         # observe the monthly cap and reserve.
         self.venues_refresher_service.set_budget_service(self.venue_budget_service)
 
+        # Set by main.start_background_jobs once the scheduler runs.
+        self.scheduler_control = None
+        self.runtime_config = None
+
+        # Cross-replica job locks on top of the in-process guard.
+        self.job_lock = None
+        if settings.distributed_job_lock_enabled:
+            self.job_lock = job_lock.RedisJobLock(
+                redis_internal_client, ttl_seconds=settings.job_lock_ttl_seconds
+            )
+            logger.info(f"[Container] Distributed job lock on (owner {self.job_lock.owner})")
+        job_lock.use_distributed(self.job_lock)
+
         logger.info("[Container] Container initialized successfully")
 
     async def shutdown(self):
-        """Clean up resources on shutdown."""
+        """Clean up resources on shutdown: stop the scheduled jobs (if main has
+        not already), close every client the container built, then the Redis
+        and RDS connections it opened. Idempotent."""
+        if self._closed:
+            return
+        self._closed = True
         logger.info("[Container] Shutting down container")
+        if self.scheduler_control is not None and self.scheduler_control.scheduler.running:
+            try:
+                self.scheduler_control.scheduler.shutdown(wait=False)
+                logger.info("[Container] Scheduler stopped")
+            except Exception as e:
+                logger.error(f"[Container] Error stopping scheduler: {e}")
+
         try:
             await self.besttime_api.close()
             logger.info("[Container] BestTime API client closed")
@@ -428,6 +934,12 @@ class Container:
             except Exception as e:
                 logger.error(f"[Container] Error closing Google Places API client: {e}")
 
+        for client in self.open_data_clients:
+            try:
+                await client.close()
+            except Exception as e:
+                logger.error(f"[Container] Error closing {client.name} open data client: {e}")
+
         if self.apify_instagram_client:
             try:
                 await self.apify_instagram_client.close()
@@ -469,3 +981,38 @@ class Container:
                 logger.info("[Container] OpenAI Vibe client closed")
             except Exception as e:
                 logger.error(f"[Container] Error closing OpenAI Vibe client: {e}")
+
+        if self.webhook_service:
+            try:
+                await self.webhook_service.close()
+                logger.info("[Container] Webhook HTTP client closed")
+            except Exception as e:
+                logger.error(f"[Container] Error closing webhook HTTP client: {e}")
+
+        if self.event_relay:
+            try:
+                await self.event_relay.close()
+                logger.info("[Container] Event broker connection closed")
+            except Exception as e:
+                logger.error(f"[Container] Error closing event broker connection: {e}")
+
+        if self.push_notifier:
+            try:
+                await self.push_notifier.sender.close()
+                logger.info("[Container] FCM client closed")
+            except Exception as e:
+                logger.error(f"[Container] Error closing FCM client: {e}")
+
+        if self._owns_rds:
+            try:
+                self.rds_store.engine.dispose()
+                logger.info("[Container] RDS connections closed")
+            except Exception as e:
+                logger.error(f"[Container] Error closing RDS connections: {e}")
+
+        if self._owns_redis:
+            try:
+                self.redis_client.client.close()
+                logger.info("[Container] Redis connections closed")
+            except Exception as e:
+                logger.error(f"[Container] Error closing Redis connections: {e}")
@@ -1,5 +1,6 @@
 """Data Access Objects package."""
 from app.dao.redis_venue_dao import RedisVenueDAO
 from app.dao.venue_budget_dao import VenueBudgetDao
+from app.dao.venue_dao import PrioritizedVenueDAO, VenueDAO
 
-__all__ = ["RedisVenueDAO", "VenueBudgetDao"]
+__all__ = ["RedisVenueDAO", "VenueBudgetDao", "VenueDAO", "PrioritizedVenueDAO"]
@@ -0,0 +1,49 @@
+"""Change notifications published by RedisVenueDAO.
+
+After a successful venue upsert, live forecast write or live forecast removal
+the DAO publishes a small JSON event on VENUE_CHANGES_CHANNEL, so other
+processes (read-cache holders, the push/SSE layer) can react without polling:
+
+    {"venue_id": "ven_123", "change_type": "live_forecast_set", "at": 1760601600.0}
+
+Publishing is best-effort and fire-and-forget (Redis pub/sub has no delivery
+guarantee or replay); a subscriber that needs the data reads it back through
+the DAO. Disable with settings.redis_change_events_enabled.
+"""
+from __future__ import annotations
+
+import json
+from dataclasses import asdict, dataclass
+
+VENUE_CHANGES_CHANNEL = "venue_changes_v1"
+
+VENUE_UPSERTED = "venue_upserted"
+LIVE_FORECAST_SET = "live_forecast_set"
+LIVE_FORECAST_DELETED = "live_forecast_deleted"
+CHANGE_TYPES = (VENUE_UPSERTED, LIVE_FORECAST_SET, LIVE_FORECAST_DELETED)
+
+
+@dataclass(frozen=True)
+class VenueChangeEvent:
+    venue_id: str
+    change_type: str
+    at: float  # unix seconds at publish time
+
+    def to_json(self) -> str:
+        return json.dumps(asdict(self), separators=(",", ":"))
+
+    @classmethod
+    def from_json(cls, raw: str) -> "VenueChangeEvent":
+        """Parse a published message.
+
+        Raises:
+            ValueError: not a venue change event
+        """
+        try:
+            doc = json.loads(raw)
+            event = cls(venue_id=str(doc["venue_id"]), change_type=doc["change_type"], at=float(doc["at"]))
+        except (TypeError, KeyError, json.JSONDecodeError) as e:
+            raise ValueError(f"malformed venue change event: {raw!r}") from e
+        if event.change_type not in CHANGE_TYPES:
+            raise ValueError(f"unknown change_type {event.change_type!r}")
+        return event
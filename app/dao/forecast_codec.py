@@ -0,0 +1,87 @@
+"""Compact Redis encoding for weekly forecast days.
+
+A WeekRawDay stored as plain JSON spends most of its bytes on the 24-value
+`day_raw` array and on DayInfo fields left at their defaults. The compact
+encoding packs the array as zigzag varints (one byte per hour for every value
+below 64) and drops default-valued fields from the rest of the document:
+
+    WR1:<base64 varints>:<json without day_raw and default fields>
+
+The base64 step is needed because the Redis clients run with
+decode_responses=True, so every stored value must be valid UTF-8 text. The
+version prefix can never start a JSON document, so `decode_week_raw_day`
+reads both encodings: legacy JSON keys keep working until the projector
+rewrites them, and settings.redis_compact_forecasts can be turned off again
+without a migration.
+"""
+from __future__ import annotations
+
+import base64
+import json
+
+from app.models import WeekRawDay
+
+COMPACT_PREFIX = "WR1:"
+
+
+def pack_varints(values: list[int]) -> bytes:
+    """Zigzag + LEB128 varint encoding (negatives stay cheap and lossless)."""
+    out = bytearray()
+    for value in values:
+        n = (value << 1) ^ (value >> 63)
+        while True:
+            byte = n & 0x7F
+            n >>= 7
+            if n:
+                out.append(byte | 0x80)
+            else:
+                out.append(byte)
+                break
+    return bytes(out)
+
+
+def unpack_varints(data: bytes) -> list[int]:
+    """Inverse of pack_varints.
+
+    Raises:
+        ValueError: truncated input
+    """
+    values = []
+    n = shift = 0
+    pending = False
+    for byte in data:
+        n |= (byte & 0x7F) << shift
+        if byte & 0x80:
+            shift += 7
+            pending = True
+            continue
+        values.append((n >> 1) ^ -(n & 1))
+        n = shift = 0
+        pending = False
+    if pending:
+        raise ValueError("truncated varint data")
+    return values
+
+
+def encode_week_raw_day(day: WeekRawDay) -> str:
+    """The compact text form of `day`."""
+    packed = base64.b64encode(pack_varints(day.day_raw)).decode("ascii")
+    rest = day.model_dump_json(by_alias=True, exclude={"day_raw"}, exclude_defaults=True)
+    return f"{COMPACT_PREFIX}{packed}:{rest}"
+
+
+def decode_week_raw_day(raw: str) -> WeekRawDay:
+    """Parse either encoding (compact or legacy JSON).
+
+    Raises:
+        ValueError: malformed compact value (pydantic's ValidationError for
+            malformed JSON is also a ValueError)
+    """
+    if not raw.startswith(COMPACT_PREFIX):
+        return WeekRawDay.model_validate_json(raw)
+    packed, sep, rest = raw[len(COMPACT_PREFIX):].partition(":")
+    if not sep:
+        raise ValueError("malformed compact weekly forecast")
+    doc = json.loads(rest)
+    doc["day_raw"] = unpack_varints(base64.b64decode(packed, validate=True))
+    return WeekRawDay.model_validate(doc)
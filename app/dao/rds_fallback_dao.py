@@ -0,0 +1,108 @@
+"""Serving DAO that can answer nearby queries from RDS.
+
+Writes already reach both stores: pipelines write RDS (the system of record)
+and the projector re-asserts the Redis projection from it every cycle. Reads
+are Redis-only, though, so a Redis outage or a flushed instance (empty until
+the next projection) leaves /v1/venues/nearby with nothing to serve.
+settings.venue_read_backend selects how nearby reads use RDS:
+
+- "redis": never (plain RedisVenueDAO; this class is not used);
+- "dual": Redis first; RDS when the geo query raises or the geo index is
+  empty;
+- "rds": always RDS.
+
+The RDS path reads the serving view (active + eligible venues, the same set
+the projector writes to Redis): a bounding-box query on venues.address, then
+the exact haversine radius. Deprecated venues are never returned from it.
+Forecast reads stay on Redis and degrade to "no crowd data" while it is down.
+"""
+from __future__ import annotations
+
+import logging
+import math
+from typing import Optional
+
+import redis
+
+from app.dao.read_cache import DaoReadCache
+from app.dao.redis_venue_dao import VENUES_GEO_KEY_V1, RedisVenueDAO
+from app.dao.venue_row import venue_from_row
+from app.db.geo_redis_client import GeoRedisClient, radius_to_km
+from app.metrics import VENUE_READ_FALLBACK_TOTAL
+from app.models import Venue
+from app.tenancy import tenant_key
+
+logger = logging.getLogger(__name__)
+
+VENUE_READ_BACKENDS = ("redis", "dual", "rds")
+# Kilometers per degree of latitude on the mean Earth radius.
+_KM_PER_DEG_LAT = 111.195
+
+
+class RdsFallbackVenueDAO(RedisVenueDAO):
+    """RedisVenueDAO whose get_nearby_venues can be served from RDS."""
+
+    def __init__(
+        self,
+        client: GeoRedisClient,
+        rds_store,
+        mode: str = "dual",
+        read_cache: Optional[DaoReadCache] = None,
+    ):
+        if mode not in ("dual", "rds"):
+            raise ValueError(f"RdsFallbackVenueDAO mode must be 'dual' or 'rds', got {mode!r}")
+        super().__init__(client, read_cache=read_cache)
+        self.rds_store = rds_store
+        self.mode = mode
+
+    def get_nearby_venues(
+        self,
+        lat: float,
+        lon: float,
+        radius: float,
+        include_deprecated: bool = False,
+        unit: str = "km",
+    ) -> list[Venue]:
+        if self.mode == "rds":
+            VENUE_READ_FALLBACK_TOTAL.labels(reason="rds_mode").inc()
+            return self._nearby_from_rds(lat, lon, radius_to_km(radius, unit))
+        try:
+            venues = super().get_nearby_venues(lat, lon, radius, include_deprecated, unit)
+        except redis.RedisError as e:
+            logger.warning(f"[RdsFallbackVenueDAO] Redis nearby query failed, reading RDS: {e}")
+            VENUE_READ_FALLBACK_TOTAL.labels(reason="redis_error").inc()
+            return self._nearby_from_rds(lat, lon, radius_to_km(radius, unit))
+        if not venues and self._geo_index_empty():
+            logger.warning("[RdsFallbackVenueDAO] Redis geo index is empty, reading RDS")
+            VENUE_READ_FALLBACK_TOTAL.labels(reason="empty_index").inc()
+            return self._nearby_from_rds(lat, lon, radius_to_km(radius, unit))
+        return venues
+
+    def _geo_index_empty(self) -> bool:
+        try:
+            return self.client.client.zcard(tenant_key(VENUES_GEO_KEY_V1)) == 0
+        except redis.RedisError:
+            return True
+
+    def _nearby_from_rds(self, lat: float, lon: float, radius_km: float) -> list[Venue]:
+        # Lazy import: app.services' package init imports the DAO package.
+        from app.services.venue_eligibility import haversine_km
+
+        dlat = radius_km / _KM_PER_DEG_LAT
+        dlng = dlat / max(math.cos(math.radians(lat)), 1e-6)
+        rows = self.rds_store.list_servable_venue_rows_in_box(
+            lat - dlat, lat + dlat, lon - dlng, lon + dlng
+        )
+        by_distance = []
+        for row in rows:
+            try:
+                venue = venue_from_row(row)
+            except Exception as e:
+                logger.error(f"[RdsFallbackVenueDAO] Bad venue row {row.get('venue_id')}: {e}")
+                continue
+            distance = haversine_km(lat, lon, venue.venue_lat, venue.venue_lng)
+            if distance <= radius_km:
+                by_distance.append((distance, venue))
+        by_distance.sort(key=lambda item: item[0])
+        logger.info(f"[RdsFallbackVenueDAO] Served {len(by_distance)} nearby venues from RDS")
+        return [venue for _, venue in by_distance]
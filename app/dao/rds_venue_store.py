@@ -22,6 +22,11 @@ from app.dao.venue_row import split_venue_for_storage
 
 logger = logging.getLogger(__name__)
 
+# Deprecation sources an active re-upsert reverses (_preserve_deprecation): a
+# geo-link undo (add_venue_handler.GEO_LINK_UNDO_SOURCE) and a stale eviction
+# (stale_eviction.STALE_SOURCE; the venue was rediscovered).
+REACTIVATING_SOURCES = frozenset({"admin_geo_link_undo", "stale_eviction"})
+
 
 def _coerce_dt(value):
     """Coerce a timestamp to a datetime (Postgres yields datetime; the fake/JSON
@@ -91,18 +96,19 @@ class RdsVenueStore:
         row = self.get_venue(venue.venue_id)
         if row is None:
             return
-        # A geo-link undo is reversible by design: an active re-add of a venue
-        # deprecated by an undo IS allowed to reactivate (clearing the deprecation
-        # fields). Every other deprecation source keeps the resurrect-block.
-        reactivating_undo = (
+        # A geo-link undo and a stale eviction are reversible by design: an
+        # active re-add of a venue they deprecated IS allowed to reactivate
+        # (clearing the deprecation fields). Every other deprecation source
+        # keeps the resurrect-block.
+        reactivating = (
             row.get("lifecycle_status") == "deprecated"
             and venue.is_active()
-            and row.get("deprecated_source") == "admin_geo_link_undo"
+            and row.get("deprecated_source") in REACTIVATING_SOURCES
         )
         if (
             row.get("lifecycle_status") == "deprecated"
             and venue.is_active()
-            and not reactivating_undo
+            and not reactivating
         ):
             venue.lifecycle_status = "deprecated"
             venue.deprecated_reason = row.get("deprecated_reason")
@@ -269,6 +275,23 @@ class RdsVenueStore:
         with self.engine.connect() as conn:
             return [dict(r) for r in conn.execute(text(_VENUE_SELECT)).mappings()]
 
+    def list_servable_venue_rows_in_box(
+        self, min_lat: float, max_lat: float, min_lng: float, max_lng: float
+    ) -> list[dict]:
+        """Servable venue rows (same shape as list_all_venue_rows) whose address
+        falls inside the lat/lng box — the candidate set for the serving
+        nearby fallback, which applies the exact radius itself."""
+        with self.engine.connect() as conn:
+            return [dict(r) for r in conn.execute(text(
+                _VENUE_SELECT
+                + " WHERE a.lat BETWEEN :min_lat AND :max_lat "
+                "AND a.lng BETWEEN :min_lng AND :max_lng "
+                "AND v.venue_id IN (SELECT venue_id FROM serving.eligible_venue)"
+            ), {
+                "min_lat": min_lat, "max_lat": max_lat,
+                "min_lng": min_lng, "max_lng": max_lng,
+            }).mappings()]
+
     # ── bulk per-table readers (projector rebuild, P1) ─────────────────────────
     # Replace the projector's former per-venue read loop (~18 SQL queries per
     # venue per cycle) with one query per table for the whole servable id set.
@@ -497,6 +520,23 @@ class RdsVenueStore:
                 "WHERE user_pseudo=:u AND venue_id=:v"
             ), {"u": user_pseudo, "v": venue_id})
 
+    def move_favorites(self, from_venue_id, to_venue_id) -> int:
+        """Move the active favorites of `from_venue_id` to `to_venue_id` (a
+        dedup merge): each becomes an active favorite of the kept venue and the
+        old row is soft-deleted. Returns how many moved."""
+        with self.engine.begin() as conn:
+            conn.execute(text(
+                "INSERT INTO engagement.favorite (user_pseudo, venue_id, deleted_at, updated_at) "
+                "SELECT user_pseudo, :to, NULL, now() FROM engagement.favorite "
+                "WHERE venue_id=:from AND deleted_at IS NULL "
+                "ON CONFLICT (user_pseudo, venue_id) DO UPDATE SET deleted_at=NULL, updated_at=now()"
+            ), {"from": from_venue_id, "to": to_venue_id})
+            result = conn.execute(text(
+                "UPDATE engagement.favorite SET deleted_at=now(), updated_at=now() "
+                "WHERE venue_id=:from AND deleted_at IS NULL"
+            ), {"from": from_venue_id})
+            return result.rowcount
+
     def add_hot_like_event(self, user_pseudo, venue_id, business_period) -> bool:
         """Idempotent per (user_pseudo, venue_id, business_period): the unique
         index (migration 0016) + ON CONFLICT DO NOTHING absorb a retried write
@@ -547,6 +587,31 @@ class RdsVenueStore:
                 "value=excluded.value, updated_by=excluded.updated_by, updated_at=now()"
             ), {"k": key, "v": json.dumps(value), "u": updated_by})
 
+    def update_admin_config(self, key, change, updated_by=None, mirror=None):
+        """Read-modify-write one key under its row lock (SELECT ... FOR
+        UPDATE), so concurrent updates apply one after the other. `change` gets
+        the current value (None when unset) and returns the new one; `mirror`
+        is called with it before the commit, while the lock is still held, so
+        mirrors land in commit order (an exception from either rolls back).
+        Returns the stored value."""
+        with self.engine.begin() as conn:
+            # Make sure a row exists to lock; a rollback removes it again.
+            conn.execute(text(
+                "INSERT INTO admin.admin_config (key, value, updated_by, updated_at) "
+                "VALUES (:k, CAST('null' AS jsonb), :u, now()) ON CONFLICT (key) DO NOTHING"
+            ), {"k": key, "u": updated_by})
+            current = conn.execute(text(
+                "SELECT value FROM admin.admin_config WHERE key=:k FOR UPDATE"
+            ), {"k": key}).scalar()
+            value = change(current)
+            conn.execute(text(
+                "UPDATE admin.admin_config SET value=CAST(:v AS jsonb), updated_by=:u, "
+                "updated_at=now() WHERE key=:k"
+            ), {"k": key, "v": json.dumps(value), "u": updated_by})
+            if mirror is not None:
+                mirror(value)
+            return value
+
     def get_admin_config(self, key) -> Optional[dict]:
         with self.engine.connect() as conn:
             row = conn.execute(text(
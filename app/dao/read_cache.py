@@ -0,0 +1,106 @@
+"""Optional in-process read-through cache for the serving DAO.
+
+A burst of /v1/venues/nearby calls for the same popular area repeats the same
+GEORADIUS, venue-document MGET and live-forecast MGET many times a second.
+`DaoReadCache` keeps the recent answers in small LRU maps with a short TTL so
+those bursts are served from memory:
+
+- nearby: the parsed venue list per (lat, lon, radius, include_deprecated);
+- venue: venue documents by id (get_venue);
+- live: live forecasts by id, including "no live forecast" (None).
+
+Writes through the same DAO invalidate what they touch (a venue write clears
+that venue and every cached nearby answer; a live write clears that venue's
+live entry). Writes made by another process (another replica's projection
+cycle) are only picked up when the TTL expires, so the TTL is the staleness
+bound and should stay a few seconds.
+"""
+from __future__ import annotations
+
+import threading
+import time
+from collections import OrderedDict
+from typing import Any, Callable, Hashable
+
+from app.metrics import REDIS_DAO_READ_CACHE_TOTAL
+from app.tenancy import current_tenant
+
+MISS = object()
+
+
+class TTLCache:
+    """Thread-safe LRU map whose entries also expire `ttl_seconds` after write.
+
+    Entries are kept per tenant (app/tenancy.py): a key cached for one tenant
+    is a miss for every other.
+    """
+
+    def __init__(
+        self,
+        name: str,
+        max_entries: int,
+        ttl_seconds: float,
+        clock: Callable[[], float] = time.monotonic,
+    ) -> None:
+        self.name = name
+        self.max_entries = max(1, max_entries)
+        self.ttl_seconds = ttl_seconds
+        self._clock = clock
+        self._entries: OrderedDict[Hashable, tuple[float, Any]] = OrderedDict()
+        self._lock = threading.Lock()
+
+    def get(self, key: Hashable) -> Any:
+        """The cached value, or MISS (None is a valid cached value)."""
+        key = (current_tenant(), key)
+        with self._lock:
+            entry = self._entries.get(key)
+            if entry is not None and entry[0] > self._clock():
+                self._entries.move_to_end(key)
+                REDIS_DAO_READ_CACHE_TOTAL.labels(cache=self.name, result="hit").inc()
+                return entry[1]
+            if entry is not None:
+                del self._entries[key]
+        REDIS_DAO_READ_CACHE_TOTAL.labels(cache=self.name, result="miss").inc()
+        return MISS
+
+    def set(self, key: Hashable, value: Any) -> None:
+        key = (current_tenant(), key)
+        with self._lock:
+            self._entries[key] = (self._clock() + self.ttl_seconds, value)
+            self._entries.move_to_end(key)
+            while len(self._entries) > self.max_entries:
+                self._entries.popitem(last=False)
+
+    def invalidate(self, key: Hashable) -> None:
+        with self._lock:
+            self._entries.pop((current_tenant(), key), None)
+
+    def clear(self) -> None:
+        with self._lock:
+            self._entries.clear()
+
+    def __len__(self) -> int:
+        return len(self._entries)
+
+
+class DaoReadCache:
+    """The three caches RedisVenueDAO consults when one is wired in."""
+
+    def __init__(
+        self,
+        max_entries: int,
+        ttl_seconds: float,
+        clock: Callable[[], float] = time.monotonic,
+    ) -> None:
+        self.nearby = TTLCache("nearby", max_entries, ttl_seconds, clock)
+        self.venue = TTLCache("venue", max_entries, ttl_seconds, clock)
+        self.live = TTLCache("live", max_entries, ttl_seconds, clock)
+
+    def invalidate_venue(self, venue_id: str) -> None:
+        """A venue document changed: drop it and every nearby answer (any of
+        them may include it, and the write may have moved it)."""
+        self.venue.invalidate(venue_id)
+        self.nearby.clear()
+
+    def invalidate_live(self, venue_id: str) -> None:
+        self.live.invalidate(venue_id)
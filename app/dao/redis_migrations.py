@@ -0,0 +1,191 @@
+"""Versioned Redis key-schema migrations.
+
+The Redis key layouts (`venues_geo_v1`, `venues_geo_place_v1:{id}`,
+`live_forecast_v1:{id}`, ...) are versioned in their names. Changing a layout
+(e.g. moving the geo index to a v2 key) used to mean either orphaning the old
+keys or a manual FLUSHDB. Instead, each layout change is a `Migration` appended
+to `MIGRATIONS`; `migrate()` applies the pending ones in order and stamps the
+applied version in `redis_schema_version`, so it is safe to run on every start
+and from the admin endpoint (already-applied migrations are skipped).
+
+Migrations run against the raw redis client and must be idempotent: a crash
+mid-migration leaves the version unstamped, and the next run re-applies it.
+Redis is a projection of RDS, so the worst case of a bad migration is a
+`rebuild_redis` from the system of record — never data loss.
+"""
+from __future__ import annotations
+
+import logging
+import uuid
+from dataclasses import dataclass
+from typing import Callable, Optional
+
+from redis.exceptions import WatchError
+
+from app.db.redis_factory import is_cluster
+
+logger = logging.getLogger(__name__)
+
+SCHEMA_VERSION_KEY = "redis_schema_version"
+# Held while migrations run so concurrent replicas starting together do not
+# interleave the same migration. Expires on its own if the holder dies. The
+# value is the holder's token, so a run that outlived the TTL never deletes
+# the lock another process has taken since.
+MIGRATION_LOCK_KEY = "redis_schema_migration_lock"
+MIGRATION_LOCK_TTL_SECONDS = 600
+# Compare-and-delete for cluster mode, where a WATCH transaction is not available.
+_RELEASE_LOCK_SCRIPT = (
+    "if redis.call('get', KEYS[1]) == ARGV[1] then return redis.call('del', KEYS[1]) end "
+    "return 0"
+)
+
+
+@dataclass(frozen=True)
+class Migration:
+    """One key-schema step. `apply(client)` returns the number of keys touched."""
+    version: int
+    name: str
+    apply: Callable[[object], int]
+
+
+class MigrationInProgressError(RuntimeError):
+    """Another process holds the migration lock."""
+
+
+# ── reusable steps ───────────────────────────────────────────────────────────
+def rename_key_family(client, old_prefix: str, new_prefix: str) -> int:
+    """Rename every `old_prefix*` key to the same suffix under `new_prefix`
+    (values and TTLs move with RENAME). A key whose target already exists is
+    left alone (the target was written by a newer writer and wins). Returns
+    the number of keys renamed."""
+    renamed = 0
+    for key in list(client.scan_iter(match=f"{old_prefix}*")):
+        target = f"{new_prefix}{key[len(old_prefix):]}"
+        if client.renamenx(key, target):
+            renamed += 1
+    return renamed
+
+
+def move_geo_index(
+    client,
+    old_geo_key: str,
+    new_geo_key: str,
+    old_member_prefix: str,
+    new_member_prefix: str,
+) -> int:
+    """Move a geo index to a new key, renaming members from `old_member_prefix`
+    to `new_member_prefix` and renaming each member's JSON key to match. The
+    old index is deleted once every member is re-added. Returns the number of
+    members moved."""
+    members = [m for m, _ in client.zscan_iter(old_geo_key)]
+    if not members:
+        return 0
+    positions = client.geopos(old_geo_key, *members)
+    moved = 0
+    # Cluster mode cannot MULTI across slots; the old index is only deleted
+    # after every GEOADD in the batch, so a plain pipeline is still safe.
+    pipe = client.pipeline() if is_cluster(client) else client.pipeline(transaction=True)
+    for member, pos in zip(members, positions):
+        if pos is None:
+            continue
+        new_member = member
+        if member.startswith(old_member_prefix):
+            new_member = f"{new_member_prefix}{member[len(old_member_prefix):]}"
+        lon, lat = pos
+        pipe.geoadd(new_geo_key, (lon, lat, new_member))
+        moved += 1
+    pipe.delete(old_geo_key)
+    pipe.execute()
+    if old_member_prefix != new_member_prefix:
+        rename_key_family(client, old_member_prefix, new_member_prefix)
+    return moved
+
+
+def _baseline(client) -> int:
+    """v1: the layouts in redis_venue_dao as of this subsystem's introduction.
+    Nothing to move; stamping the version marks existing data as v1."""
+    return 0
+
+
+# Append-only and strictly increasing. Never edit an applied migration; add a
+# new one (e.g. `Migration(2, "geo_index_v2", lambda c: move_geo_index(c,
+# "venues_geo_v1", "venues_geo_v2", "venues_geo_place_v1:",
+# "venues_geo_place_v2:"))`) alongside the DAO constant change.
+MIGRATIONS: list[Migration] = [
+    Migration(1, "baseline_v1_layouts", _baseline),
+]
+
+
+def latest_version(migrations: Optional[list[Migration]] = None) -> int:
+    migrations = MIGRATIONS if migrations is None else migrations
+    return max((m.version for m in migrations), default=0)
+
+
+def current_version(client) -> int:
+    """The applied schema version (0 when never migrated)."""
+    raw = client.get(SCHEMA_VERSION_KEY)
+    try:
+        return int(raw) if raw is not None else 0
+    except (TypeError, ValueError):
+        logger.warning(f"[RedisMigrations] Unparseable {SCHEMA_VERSION_KEY}={raw!r}; treating as 0")
+        return 0
+
+
+def _release_lock(client, token: str) -> None:
+    """Delete the migration lock only while it still holds `token`."""
+    if is_cluster(client):
+        client.eval(_RELEASE_LOCK_SCRIPT, 1, MIGRATION_LOCK_KEY, token)
+        return
+    with client.pipeline() as pipe:
+        try:
+            pipe.watch(MIGRATION_LOCK_KEY)
+            holder = pipe.get(MIGRATION_LOCK_KEY)
+            if isinstance(holder, bytes):
+                holder = holder.decode()
+            if holder != token:
+                pipe.unwatch()
+                logger.warning("[RedisMigrations] Lock expired during the run; not releasing it")
+                return
+            pipe.multi()
+            pipe.delete(MIGRATION_LOCK_KEY)
+            pipe.execute()
+        except WatchError:
+            # Changed hands between the read and the delete: not ours anymore.
+            pass
+
+
+def migrate(
+    client,
+    migrations: Optional[list[Migration]] = None,
+    target: Optional[int] = None,
+) -> dict:
+    """Apply every pending migration up to `target` (default: latest), in order.
+
+    Returns {"from": int, "to": int, "applied": [{"version", "name", "keys"}]}.
+    Raises MigrationInProgressError when another process holds the lock; a
+    failing migration raises after stamping every migration before it.
+    """
+    migrations = sorted(MIGRATIONS if migrations is None else migrations, key=lambda m: m.version)
+    target = latest_version(migrations) if target is None else target
+
+    token = uuid.uuid4().hex
+    if not client.set(MIGRATION_LOCK_KEY, token, nx=True, ex=MIGRATION_LOCK_TTL_SECONDS):
+        raise MigrationInProgressError("redis schema migration already in progress")
+    try:
+        start = current_version(client)
+        applied = []
+        for migration in migrations:
+            if migration.version <= start or migration.version > target:
+                continue
+            logger.info(
+                f"[RedisMigrations] Applying v{migration.version} ({migration.name})"
+            )
+            keys = migration.apply(client)
+            client.set(SCHEMA_VERSION_KEY, migration.version)
+            applied.append({"version": migration.version, "name": migration.name, "keys": keys})
+        end = current_version(client)
+        if applied:
+            logger.info(f"[RedisMigrations] Schema migrated v{start} -> v{end}")
+        return {"from": start, "to": end, "applied": applied}
+    finally:
+        _release_lock(client, token)
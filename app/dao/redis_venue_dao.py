@@ -1,13 +1,32 @@
 """Redis-based Data Access Object for venue operations."""
 import json
 import logging
+import time
+from contextlib import contextmanager
 from datetime import datetime, timezone
 from typing import Optional
 import redis
 
 from app.config import settings
-from app.db.geo_redis_client import GeoRedisClient
-from app.models import Venue, LiveForecastResponse, WeekRawDay
+from app.db.geo_redis_client import GeoRedisClient, radius_to_km
+from app.dao.change_events import (
+    LIVE_FORECAST_DELETED,
+    LIVE_FORECAST_SET,
+    VENUE_CHANGES_CHANNEL,
+    VENUE_UPSERTED,
+    VenueChangeEvent,
+)
+from app.dao.forecast_codec import decode_week_raw_day, encode_week_raw_day
+from app.dao.read_cache import MISS, DaoReadCache
+from app.dao.venue_codec import decode_venue, encode_venue
+from app.metrics import (
+    REDIS_DAO_CACHE_LOOKUPS_TOTAL,
+    REDIS_DAO_CHANGE_EVENTS_TOTAL,
+    REDIS_DAO_OPERATION_DURATION_SECONDS,
+)
+from app.models import Venue, LiveForecastResponse, LiveHistoryPoint, WeekRawDay
+from app.tenancy import tenant_key
+from app.tracing import tracer
 from app.models.vibe_attributes import VibeAttributes
 from app.models.opening_hours import OpeningHours
 from app.models.instagram import VenueInstagram, VenueInstagramPosts
@@ -17,10 +36,21 @@ from app.models.vibe_profile import VenueVibeProfile
 
 logger = logging.getLogger(__name__)
 
+# stale_eviction.STALE_SOURCE: a venue it deprecated reactivates when re-seen.
+STALE_EVICTION_SOURCE = "stale_eviction"
+
 # CRITICAL: These key formats must match exactly with Go implementation for backward compatibility
 VENUES_GEO_KEY_V1 = "venues_geo_v1"
 VENUES_GEO_PLACE_MEMBER_FORMAT_V1 = "venues_geo_place_v1:{}"
 LIVE_FORECAST_KEY_FORMAT = "live_forecast_v1:{}"
+# Sorted set of {"t": epoch, "busyness": n} samples scored by the payload's
+# own gmttime (so re-projecting the same forecast never duplicates a sample).
+LIVE_HISTORY_KEY_FORMAT = "live_history_v1:{}"
+# Hard cap per venue on top of the time window (a 1-min refresh for 24h is 1440).
+LIVE_HISTORY_MAX_POINTS = 2000
+# Latest partner-pushed occupancy reading per venue, stored in the live
+# forecast shape; expires once it is too old to be preferred over BestTime.
+PARTNER_LIVE_KEY_FORMAT = "partner_live_v1:{}"
 WEEKLY_FORECAST_KEY_FORMAT = "weekly_forecast_v1:{}_{}"
 VIBE_ATTRIBUTES_KEY_FORMAT = "vibe_attributes_v1:{}"
 VENUE_PHOTOS_KEY_FORMAT = "venue_photos_v1:{}"
@@ -44,20 +74,59 @@ VENUE_MENU_RAW_DATA_KEY_FORMAT = "venue_menu_raw_data_v1:{}"
 VENUE_IG_POSTS_KEY_FORMAT = "venue_ig_posts_v1:{}"
 VENUE_VIBE_PROFILE_KEY_FORMAT = "venue_vibe_profile_v2:{}"
 
+# Venues per pipelined round-trip in upsert_venues.
+UPSERT_CHUNK_SIZE = 500
+
+
+@contextmanager
+def _timed(operation: str):
+    """Observe the wrapped block's wall time under `operation` (success or not),
+    inside a `RedisVenueDAO.<operation>` trace span."""
+    start = time.perf_counter()
+    try:
+        with tracer.start_as_current_span(f"RedisVenueDAO.{operation}"):
+            yield
+    finally:
+        REDIS_DAO_OPERATION_DURATION_SECONDS.labels(operation=operation).observe(
+            time.perf_counter() - start
+        )
+
+
+def _history_points(rows) -> list[LiveHistoryPoint]:
+    """Decode live history (member, score) rows; unreadable members are skipped."""
+    points = []
+    for member, score in rows:
+        try:
+            busyness = int(json.loads(member)["busyness"])
+        except (TypeError, ValueError, KeyError):
+            continue
+        points.append(LiveHistoryPoint(
+            timestamp=datetime.fromtimestamp(score, tz=timezone.utc),
+            busyness=busyness,
+        ))
+    return points
+
 
 class RedisVenueDAO:
     """Data Access Object for venue operations using Redis."""
 
-    def __init__(self, client: GeoRedisClient):
+    def __init__(self, client: GeoRedisClient, read_cache: Optional[DaoReadCache] = None):
         """Initialize RedisVenueDAO.
 
         Args:
             client: GeoRedisClient instance
+            read_cache: Optional in-process cache for nearby queries, venue
+                documents and live forecasts (serving instance only; see
+                app/dao/read_cache.py)
         """
         self.client = client
+        self.read_cache = read_cache
 
     # ── bulk MGET helper (P2/P3/P4) ─────────────────────────────────────────
-    def _mget_parsed(self, key_fn, venue_ids: list[str], model_cls) -> dict:
+    def _mget_parsed(
+        self, key_fn, venue_ids: list[str], model_cls, metric_entity: Optional[str] = None,
+        parse=None,
+    ) -> dict:
         """MGET `key_fn(venue_id)` for every id, parsing each hit with
         `model_cls.model_validate_json`, keyed by venue_id.
 
@@ -67,25 +136,34 @@ class RedisVenueDAO:
         whole-request Redis failure logs and returns {} (every id degrades to
         "absent", the same aggregate effect a connection error has on N
         sequential single-item getters). Empty input short-circuits without a
-        round-trip.
+        round-trip. With `metric_entity`, every id counts one hit/miss/error
+        lookup under that entity. `parse` replaces
+        `model_cls.model_validate_json` for stored encodings other than JSON.
         """
         if not venue_ids:
             return {}
+        parse = parse or model_cls.model_validate_json
         keys = [key_fn(vid) for vid in venue_ids]
         try:
             raw_values = self.client.mget(keys)
         except redis.RedisError as e:
             logger.error(f"Bulk get failed for {model_cls.__name__} ({len(keys)} keys): {e}")
+            if metric_entity is not None:
+                REDIS_DAO_CACHE_LOOKUPS_TOTAL.labels(entity=metric_entity, result="error").inc(len(keys))
             return {}
         out = {}
         for vid, raw in zip(venue_ids, raw_values):
             if raw is None:
                 continue
             try:
-                out[vid] = model_cls.model_validate_json(raw)
+                out[vid] = parse(raw)
             except Exception as e:
                 logger.error(f"Failed to parse bulk {model_cls.__name__} for {vid}: {e}")
                 continue
+        if metric_entity is not None:
+            hits = sum(1 for raw in raw_values if raw is not None)
+            REDIS_DAO_CACHE_LOOKUPS_TOTAL.labels(entity=metric_entity, result="hit").inc(hits)
+            REDIS_DAO_CACHE_LOOKUPS_TOTAL.labels(entity=metric_entity, result="miss").inc(len(keys) - hits)
         return out
 
     # ── single-item accessor generics (spec-table-driven) ───────────────────
@@ -95,18 +173,24 @@ class RedisVenueDAO:
     # one-line delegates so VenueRepository's per-method overrides keep working
     # unchanged. Behavior (keys, by_alias serialization, error tolerance, and
     # log wording) is byte-identical to the hand-rolled accessors.
-    def _get_model(self, key: str, model_cls, log_name: str):
+    def _get_model(self, key: str, model_cls, log_name: str, metric_entity: Optional[str] = None):
         """GET `key` and parse it with `model_cls.model_validate_json`, or None
         on a cache miss / Redis error (logging ``Failed to get <log_name> from
-        Redis`` exactly as the original getters did)."""
+        Redis`` exactly as the original getters did). With `metric_entity`, the
+        lookup counts as a hit/miss/error under that entity."""
         try:
             json_str = self.client.get(key)
-            if json_str is None:
-                return None
-            return model_cls.model_validate_json(json_str)
         except redis.RedisError as e:
             logger.error(f"Failed to get {log_name} from Redis: {e}")
+            if metric_entity is not None:
+                REDIS_DAO_CACHE_LOOKUPS_TOTAL.labels(entity=metric_entity, result="error").inc()
+            return None
+        if metric_entity is not None:
+            result = "miss" if json_str is None else "hit"
+            REDIS_DAO_CACHE_LOOKUPS_TOTAL.labels(entity=metric_entity, result=result).inc()
+        if json_str is None:
             return None
+        return model_cls.model_validate_json(json_str)
 
     def _set_model(self, key: str, model) -> None:
         """SET `key` to ``model.model_dump_json(by_alias=True)`` — the shared
@@ -124,14 +208,18 @@ class RedisVenueDAO:
         keys = self.client.keys(f"{prefix}*")
         return [key.replace(prefix, "", 1) for key in keys]
 
-    def upsert_venue(self, venue: Venue) -> None:
-        """Store venue as a geolocation with JSON data.
-
-        Args:
-            venue: Venue object to store
-        """
-        existing = self.get_venue(venue.venue_id) if venue.venue_id else None
-        if existing is not None and existing.is_deprecated() and venue.is_active():
+    @staticmethod
+    def _preserve_lifecycle(venue: Venue, existing: Optional[Venue]) -> None:
+        """Carry the stored lifecycle onto an incoming upsert: a re-seen venue
+        never reactivates a deprecated one (unless stale eviction deprecated
+        it: being seen again is exactly what it lacked), and a missing Google
+        business status keeps the stored value."""
+        if (
+            existing is not None
+            and existing.is_deprecated()
+            and venue.is_active()
+            and existing.deprecated_source != STALE_EVICTION_SOURCE
+        ):
             venue.lifecycle_status = existing.lifecycle_status
             venue.deprecated_at = existing.deprecated_at
             venue.deprecated_reason = existing.deprecated_reason
@@ -140,14 +228,89 @@ class RedisVenueDAO:
         elif existing is not None and existing.google_business_status and not venue.google_business_status:
             venue.google_business_status = existing.google_business_status
 
+    def upsert_venue(self, venue: Venue) -> None:
+        """Store venue as a geolocation with JSON data.
+
+        Args:
+            venue: Venue object to store
+        """
+        # Read the stored copy past the cache; reading it re-caches that old
+        # copy, so drop it again once the new one is written.
+        self._invalidate_venue(venue.venue_id)
+        existing = self.get_venue(venue.venue_id) if venue.venue_id else None
+        self._preserve_lifecycle(venue, existing)
+
         venue_key = VENUES_GEO_PLACE_MEMBER_FORMAT_V1.format(venue.venue_id)
-        self.client.add_location_with_json(
-            geo_key=VENUES_GEO_KEY_V1,
-            member_key=venue_key,
-            lat=venue.venue_lat,
-            lon=venue.venue_lng,
-            data=venue,
+        try:
+            with _timed("upsert_venue"):
+                self.client.add_location_with_json(
+                    geo_key=VENUES_GEO_KEY_V1,
+                    member_key=venue_key,
+                    lat=venue.venue_lat,
+                    lon=venue.venue_lng,
+                    data=encode_venue(venue),
+                )
+        finally:
+            self._invalidate_venue(venue.venue_id)
+        self._publish_changes([venue.venue_id], VENUE_UPSERTED)
+
+    def upsert_venues(
+        self,
+        venues: list[Venue],
+        chunk_size: int = UPSERT_CHUNK_SIZE,
+        errors: Optional[dict[str, Exception]] = None,
+    ) -> int:
+        """Bulk `upsert_venue`: the same lifecycle preservation and keys, but
+        one MGET for the stored copies and one pipelined round-trip per chunk
+        (GEOADD + SETs) instead of a GET + GEOADD + SET per venue.
+
+        Args:
+            venues: Venue objects to store
+            chunk_size: Venues per pipeline round-trip
+            errors: when given, a failed chunk does not raise; each of its
+                venues is mapped venue_id -> exception here and the remaining
+                chunks are still written
+
+        Returns:
+            Number of venues written
+        """
+        if not venues:
+            return 0
+        existing_by_id = self._mget_parsed(
+            VENUES_GEO_PLACE_MEMBER_FORMAT_V1.format,
+            [v.venue_id for v in venues if v.venue_id],
+            Venue,
+            parse=decode_venue,
+        )
+        items = []
+        for venue in venues:
+            self._preserve_lifecycle(venue, existing_by_id.get(venue.venue_id))
+            items.append((
+                VENUES_GEO_PLACE_MEMBER_FORMAT_V1.format(venue.venue_id),
+                venue.venue_lat,
+                venue.venue_lng,
+                encode_venue(venue),
+            ))
+        member_errors: Optional[dict[str, Exception]] = None if errors is None else {}
+        try:
+            with _timed("upsert_venues"):
+                written = self.client.add_locations_with_json(
+                    VENUES_GEO_KEY_V1, items, chunk_size=chunk_size, errors=member_errors
+                )
+        finally:
+            for venue in venues:
+                self._invalidate_venue(venue.venue_id)
+        failed = set()
+        if member_errors:
+            for venue in venues:
+                e = member_errors.get(VENUES_GEO_PLACE_MEMBER_FORMAT_V1.format(venue.venue_id))
+                if e is not None:
+                    errors[venue.venue_id] = e
+                    failed.add(venue.venue_id)
+        self._publish_changes(
+            [venue.venue_id for venue in venues if venue.venue_id not in failed], VENUE_UPSERTED
         )
+        return written
 
     def get_venue(self, venue_id: str) -> Optional[Venue]:
         """Retrieve a venue by its ID.
@@ -158,15 +321,45 @@ class RedisVenueDAO:
         Returns:
             Venue object or None if not found
         """
+        if self.read_cache is not None:
+            cached = self.read_cache.venue.get(venue_id)
+            if cached is not MISS:
+                return cached.model_copy() if cached is not None else None
         venue_key = VENUES_GEO_PLACE_MEMBER_FORMAT_V1.format(venue_id)
         try:
             json_str = self.client.get(venue_key)
             if json_str is None:
                 return None
-            return Venue.model_validate_json(json_str)
+            venue = decode_venue(json_str)
         except Exception as e:
             logger.error(f"Failed to get venue {venue_id}: {e}")
             return None
+        if self.read_cache is not None:
+            self.read_cache.venue.set(venue_id, venue.model_copy())
+        return venue
+
+    def _publish_changes(self, venue_ids: list[str], change_type: str) -> None:
+        """Best-effort change notification (app/dao/change_events.py): a
+        failed publish is counted and logged, never raised to the writer."""
+        if not venue_ids or not settings.redis_change_events_enabled:
+            return
+        at = time.time()
+        messages = [VenueChangeEvent(vid, change_type, at).to_json() for vid in venue_ids]
+        try:
+            self.client.publish_many(VENUE_CHANGES_CHANNEL, messages)
+        except redis.RedisError as e:
+            REDIS_DAO_CHANGE_EVENTS_TOTAL.labels(change_type=change_type, result="error").inc(len(messages))
+            logger.warning(f"[RedisVenueDAO] Failed to publish {change_type} events: {e}")
+            return
+        REDIS_DAO_CHANGE_EVENTS_TOTAL.labels(change_type=change_type, result="published").inc(len(messages))
+
+    def _invalidate_venue(self, venue_id: str) -> None:
+        if self.read_cache is not None:
+            self.read_cache.invalidate_venue(venue_id)
+
+    def _invalidate_live(self, venue_id: str) -> None:
+        if self.read_cache is not None:
+            self.read_cache.invalidate_live(venue_id)
 
     def soft_delete_venue(
         self,
@@ -180,6 +373,7 @@ class RedisVenueDAO:
         The venue JSON and geo member stay in their existing v1 keys. Associated
         cache keys are intentionally left untouched for troubleshooting.
         """
+        self._invalidate_venue(venue_id)
         venue = self.get_venue(venue_id)
         if venue is None:
             logger.warning(f"[RedisVenueDAO] Venue {venue_id} not found, cannot soft-delete")
@@ -192,13 +386,15 @@ class RedisVenueDAO:
         venue.google_business_status = google_business_status
 
         venue_key = VENUES_GEO_PLACE_MEMBER_FORMAT_V1.format(venue.venue_id)
-        self.client.add_location_with_json(
-            geo_key=VENUES_GEO_KEY_V1,
-            member_key=venue_key,
-            lat=venue.venue_lat,
-            lon=venue.venue_lng,
-            data=venue,
-        )
+        with _timed("upsert_venue"):
+            self.client.add_location_with_json(
+                geo_key=VENUES_GEO_KEY_V1,
+                member_key=venue_key,
+                lat=venue.venue_lat,
+                lon=venue.venue_lng,
+                data=encode_venue(venue),
+            )
+        self._invalidate_venue(venue_id)
         logger.info(
             f"[RedisVenueDAO] Soft-deprecated venue {venue_id}: "
             f"reason={reason}, source={source}, google_business_status={google_business_status}"
@@ -222,7 +418,7 @@ class RedisVenueDAO:
         This removes:
         - The venue from the geo index
         - The venue JSON data
-        - Any cached live forecast
+        - Any cached live forecast (and its live history and partner reading)
         - Any cached weekly forecasts (all 7 days)
         - Any cached vibe attributes
         - Any cached photos
@@ -246,9 +442,12 @@ class RedisVenueDAO:
 
             # Remove venue JSON data
             self.client.del_(venue_key)
+            self._invalidate_venue(venue_id)
 
             # Remove associated data
             self.delete_live_forecast(venue_id)
+            self.client.del_(LIVE_HISTORY_KEY_FORMAT.format(venue_id))
+            self.client.del_(PARTNER_LIVE_KEY_FORMAT.format(venue_id))
             self.delete_vibe_attributes(venue_id)
 
             # Remove weekly forecasts for all 7 days
@@ -289,33 +488,49 @@ class RedisVenueDAO:
         lon: float,
         radius: float,
         include_deprecated: bool = False,
+        unit: str = "km",
     ) -> list[Venue]:
         """Retrieve nearby venues within a given radius.
 
         Args:
             lat: Center latitude
             lon: Center longitude
-            radius: Radius in kilometers
+            radius: Radius, in `unit`
+            unit: "m", "km", "mi" or "ft" (normalized to km for the geo query)
 
         Returns:
             List of Venue objects
+
+        Raises:
+            ValueError: unsupported unit or a negative radius
         """
         logger.info("Getting nearby venues")
+        radius_km = radius_to_km(radius, unit)
 
-        venues_json = self.client.get_locations_within_radius(
-            VENUES_GEO_KEY_V1, lat, lon, radius
-        )
+        cache_key = (round(lat, 6), round(lon, 6), round(radius_km, 6), include_deprecated)
+        if self.read_cache is not None:
+            cached = self.read_cache.nearby.get(cache_key)
+            if cached is not MISS:
+                return [venue.model_copy() for venue in cached]
+
+        with _timed("get_nearby_venues"):
+            venues_json = self.client.get_locations_within_radius(
+                VENUES_GEO_KEY_V1, lat, lon, radius_km
+            )
 
         venues = []
         for venue_json in venues_json:
             try:
-                venue = Venue.model_validate_json(venue_json)
+                venue = decode_venue(venue_json)
                 if include_deprecated or venue.is_active():
                     venues.append(venue)
             except Exception as e:
                 logger.error(f"Failed to unmarshal venue JSON: {e}")
                 continue
 
+        if self.read_cache is not None:
+            self.read_cache.nearby.set(cache_key, [venue.model_copy() for venue in venues])
+
         logger.info(f"Finished getting nearby venues: found {len(venues)}")
         return venues
 
@@ -333,7 +548,7 @@ class RedisVenueDAO:
             Number of venues within the radius
         """
         results = self.client.client.georadius(
-            VENUES_GEO_KEY_V1,
+            tenant_key(VENUES_GEO_KEY_V1),
             longitude=lon,
             latitude=lat,
             radius=radius_m / 1000.0,
@@ -353,9 +568,113 @@ class RedisVenueDAO:
             written, False when skipped because the venue is absent from
             venues.venue (see VenueRepository.set_live_forecast).
         """
-        self._set_model(LIVE_FORECAST_KEY_FORMAT.format(forecast.venue_info.venue_id), forecast)
+        with _timed("set_live_forecast"):
+            self._set_model(LIVE_FORECAST_KEY_FORMAT.format(forecast.venue_info.venue_id), forecast)
+            self._invalidate_live(forecast.venue_info.venue_id)
+            self._append_live_history(forecast)
+        self._publish_changes([forecast.venue_info.venue_id], LIVE_FORECAST_SET)
         return None
 
+    def _append_live_history(self, forecast: LiveForecastResponse) -> None:
+        """Record (gmttime, live busyness) in the venue's capped history.
+
+        Only available live values with a datable gmttime are recorded (an
+        un-datable sample cannot be placed on the timeline). Best-effort: a
+        history failure never fails the live forecast write.
+        """
+        # Lazy import: app.services' package init imports this module.
+        from app.services.live_freshness import parse_gmttime
+
+        if not forecast.analysis.venue_live_busyness_available:
+            return
+        sampled_at = parse_gmttime(forecast.venue_info.venue_current_gmttime)
+        if sampled_at is None:
+            return
+        window_seconds = settings.live_history_window_hours * 3600
+        score = sampled_at.timestamp()
+        member = json.dumps(
+            {"t": int(score), "busyness": forecast.analysis.venue_live_busyness},
+            separators=(",", ":"),
+        )
+        try:
+            self.client.add_capped_timeline_entry(
+                LIVE_HISTORY_KEY_FORMAT.format(forecast.venue_info.venue_id),
+                score,
+                member,
+                min_score=score - window_seconds,
+                max_members=LIVE_HISTORY_MAX_POINTS,
+                ttl_seconds=window_seconds,
+            )
+        except redis.RedisError as e:
+            logger.warning(
+                f"[RedisVenueDAO] Failed to append live history for "
+                f"{forecast.venue_info.venue_id}: {e}"
+            )
+
+    def get_live_history(
+        self, venue_id: str, since: Optional[datetime] = None
+    ) -> list[LiveHistoryPoint]:
+        """Return the venue's recorded live busyness samples, oldest first.
+
+        Args:
+            venue_id: Venue identifier
+            since: Only samples at or after this instant (all retained when None)
+
+        Returns:
+            LiveHistoryPoint list (empty when nothing is recorded)
+        """
+        min_score = since.timestamp() if since is not None else "-inf"
+        try:
+            rows = self.client.zrangebyscore(
+                LIVE_HISTORY_KEY_FORMAT.format(venue_id), min_score, "+inf"
+            )
+        except redis.RedisError as e:
+            logger.error(f"Failed to get live history from Redis: {e}")
+            return []
+        return _history_points(rows)
+
+    def get_live_history_bulk(
+        self, venue_ids: list[str], since: Optional[datetime] = None
+    ) -> dict[str, list[LiveHistoryPoint]]:
+        """`get_live_history` for an id set in one pipelined round-trip,
+        keyed by venue_id; venues without samples are absent."""
+        min_score = since.timestamp() if since is not None else "-inf"
+        with _timed("get_live_history_bulk"):
+            rows_per_venue = self.client.zrangebyscore_many(
+                [LIVE_HISTORY_KEY_FORMAT.format(vid) for vid in venue_ids], min_score, "+inf"
+            )
+        out = {}
+        for vid, rows in zip(venue_ids, rows_per_venue):
+            points = _history_points(rows)
+            if points:
+                out[vid] = points
+        return out
+
+    def set_partner_live(self, forecast: LiveForecastResponse, ttl_seconds: int) -> None:
+        """Store a partner occupancy reading (already converted to the live
+        forecast shape) and record it in the venue's live history series."""
+        key = PARTNER_LIVE_KEY_FORMAT.format(forecast.venue_info.venue_id)
+        self.client.setex(key, ttl_seconds, forecast.model_dump_json(by_alias=True))
+        self._append_live_history(forecast)
+        logger.debug(
+            f"[RedisVenueDAO] Stored partner live reading for "
+            f"{forecast.venue_info.venue_id} (TTL {ttl_seconds}s)"
+        )
+
+    def get_partner_live(self, venue_id: str) -> Optional[LiveForecastResponse]:
+        """The venue's unexpired partner reading, or None."""
+        return self._get_model(
+            PARTNER_LIVE_KEY_FORMAT.format(venue_id), LiveForecastResponse,
+            "partner live reading", metric_entity="partner_live",
+        )
+
+    def get_partner_live_bulk(self, venue_ids: list[str]) -> dict[str, LiveForecastResponse]:
+        """MGET partner readings for an id set; absent ids have none."""
+        return self._mget_parsed(
+            PARTNER_LIVE_KEY_FORMAT.format, venue_ids, LiveForecastResponse,
+            metric_entity="partner_live",
+        )
+
     def get_live_forecast(self, venue_id: str) -> Optional[LiveForecastResponse]:
         """Retrieve cached live forecast for a venue by its ID.
 
@@ -365,15 +684,52 @@ class RedisVenueDAO:
         Returns:
             LiveForecastResponse or None if not found
         """
-        return self._get_model(
-            LIVE_FORECAST_KEY_FORMAT.format(venue_id), LiveForecastResponse, "live forecast"
-        )
+        if self.read_cache is not None:
+            cached = self.read_cache.live.get(venue_id)
+            if cached is not MISS:
+                return cached
+        with _timed("get_live_forecast"):
+            forecast = self._get_model(
+                LIVE_FORECAST_KEY_FORMAT.format(venue_id), LiveForecastResponse,
+                "live forecast", metric_entity="live_forecast",
+            )
+        if self.read_cache is not None:
+            self.read_cache.live.set(venue_id, forecast)
+        return forecast
 
     def get_live_forecasts_bulk(self, venue_ids: list[str]) -> dict[str, LiveForecastResponse]:
         """MGET live forecasts for an id set, keyed by venue_id (P2/P3). The
         bulk counterpart of `get_live_forecast`; a missing/unparseable entry is
         simply absent from the result, matching the single getter's None."""
-        return self._mget_parsed(LIVE_FORECAST_KEY_FORMAT.format, venue_ids, LiveForecastResponse)
+        if self.read_cache is None:
+            with _timed("get_live_forecasts_bulk"):
+                return self._mget_parsed(
+                    LIVE_FORECAST_KEY_FORMAT.format, venue_ids, LiveForecastResponse,
+                    metric_entity="live_forecast",
+                )
+        out: dict[str, LiveForecastResponse] = {}
+        misses = []
+        for vid in venue_ids:
+            cached = self.read_cache.live.get(vid)
+            if cached is MISS:
+                misses.append(vid)
+            elif cached is not None:
+                out[vid] = cached
+        if misses:
+            with _timed("get_live_forecasts_bulk"):
+                fetched = self._mget_parsed(
+                    LIVE_FORECAST_KEY_FORMAT.format, misses, LiveForecastResponse,
+                    metric_entity="live_forecast",
+                )
+            # Absence is cached too (most venues have no live forecast), but
+            # not from an all-empty answer, which is also what a Redis error
+            # degrades to.
+            cache_absent = bool(fetched)
+            for vid in misses:
+                if vid in fetched or cache_absent:
+                    self.read_cache.live.set(vid, fetched.get(vid))
+            out.update(fetched)
+        return out
 
     def delete_live_forecast(self, venue_id: str) -> bool:
         """Delete cached live forecast for a venue.
@@ -386,6 +742,7 @@ class RedisVenueDAO:
         """
         key = LIVE_FORECAST_KEY_FORMAT.format(venue_id)
         removed = bool(self.client.del_(key))
+        self._invalidate_live(venue_id)
         # DEBUG + only-on-real-removal: the projector calls this every ~2-min
         # cycle for every servable venue that has no live row (~most of the
         # catalog), so an unconditional INFO here is misleading ("Deleted ..."
@@ -393,8 +750,18 @@ class RedisVenueDAO:
         # projection path.
         if removed:
             logger.debug(f"[RedisVenueDAO] Deleted live forecast cache for {venue_id}")
+            # Only a real removal is a change (see above for the no-op volume).
+            self._publish_changes([venue_id], LIVE_FORECAST_DELETED)
         return removed
 
+    def list_cached_live_forecast_venue_ids(self) -> list[str]:
+        """Return venue IDs that currently have a cached live forecast.
+
+        Returns:
+            List of venue IDs
+        """
+        return self._scan_venue_ids(LIVE_FORECAST_KEY_FORMAT.format(""))
+
     def list_active_venue_ids(self) -> list[str]:
         """Return venue IDs that are not deprecated."""
         return [venue.venue_id for venue in self.list_active_venues()]
@@ -444,7 +811,7 @@ class RedisVenueDAO:
             if not json_str:
                 continue
             try:
-                venues.append(Venue.model_validate_json(json_str))
+                venues.append(decode_venue(json_str))
             except Exception as e:
                 logger.error(f"Failed to parse venue from key {key}: {e}")
                 continue
@@ -470,7 +837,11 @@ class RedisVenueDAO:
             venue_id: Venue identifier
             day: WeekRawDay object containing forecast for one day
         """
-        self._set_model(WEEKLY_FORECAST_KEY_FORMAT.format(venue_id, day.day_int), day)
+        key = WEEKLY_FORECAST_KEY_FORMAT.format(venue_id, day.day_int)
+        if settings.redis_compact_forecasts:
+            self.client.set(key, encode_week_raw_day(day))
+        else:
+            self._set_model(key, day)
 
     def get_week_raw_forecast(self, venue_id: str, day_int: int) -> Optional[WeekRawDay]:
         """Retrieve cached raw weekly forecast for a venue and day.
@@ -483,26 +854,33 @@ class RedisVenueDAO:
             WeekRawDay or None if not found
         """
         key = WEEKLY_FORECAST_KEY_FORMAT.format(venue_id, day_int)
-        try:
-            json_str = self.client.get(key)
-            if json_str is None:
-                return None  # Cache miss
-            return WeekRawDay.model_validate_json(json_str)
-        except redis.RedisError as e:
-            # Check if it's a "key not found" error
-            if "nil" in str(e).lower():
+        with _timed("get_week_raw_forecast"):
+            try:
+                json_str = self.client.get(key)
+                if json_str is None:
+                    REDIS_DAO_CACHE_LOOKUPS_TOTAL.labels(entity="weekly_forecast", result="miss").inc()
+                    return None  # Cache miss
+                REDIS_DAO_CACHE_LOOKUPS_TOTAL.labels(entity="weekly_forecast", result="hit").inc()
+                return decode_week_raw_day(json_str)
+            except redis.RedisError as e:
+                # Check if it's a "key not found" error
+                if "nil" in str(e).lower():
+                    REDIS_DAO_CACHE_LOOKUPS_TOTAL.labels(entity="weekly_forecast", result="miss").inc()
+                    return None
+                REDIS_DAO_CACHE_LOOKUPS_TOTAL.labels(entity="weekly_forecast", result="error").inc()
+                logger.error(f"Failed to get weekly raw forecast from Redis: {e}")
                 return None
-            logger.error(f"Failed to get weekly raw forecast from Redis: {e}")
-            return None
 
     def get_week_raw_forecasts_bulk(
         self, venue_ids: list[str], day_int: int
     ) -> dict[str, WeekRawDay]:
         """MGET a single day's weekly forecast for an id set, keyed by venue_id
         (P2/P3/P4) — the bulk counterpart of `get_week_raw_forecast`."""
-        return self._mget_parsed(
-            lambda vid: WEEKLY_FORECAST_KEY_FORMAT.format(vid, day_int), venue_ids, WeekRawDay
-        )
+        with _timed("get_week_raw_forecasts_bulk"):
+            return self._mget_parsed(
+                lambda vid: WEEKLY_FORECAST_KEY_FORMAT.format(vid, day_int), venue_ids, WeekRawDay,
+                metric_entity="weekly_forecast", parse=decode_week_raw_day,
+            )
 
     def delete_week_raw_forecast(self, venue_id: str, day_int: int) -> bool:
         """Delete one cached weekly-forecast day for a venue.
@@ -0,0 +1,150 @@
+"""Last-known-good in-memory copy of the serving data, for Redis outages.
+
+A brief Redis outage makes every /v1/venues/nearby request fail. When
+settings.nearby_snapshot_enabled is on, a scheduled job copies what the nearby
+handler reads (venues, live and weekly forecasts, the bulk enrichment reads)
+from the serving DAO into a SnapshotVenueDAO every
+nearby_snapshot_refresh_minutes. VenueHandler answers from it, marking every
+venue `stale`, when the serving DAO raises a RedisError, as long as the
+snapshot is younger than nearby_snapshot_max_age_minutes.
+
+A refresh that reads no venues at all (Redis down or flushed) keeps the
+previous snapshot. Per-venue reads (reviews, menu data) are not copied, so
+stale responses omit them.
+"""
+from __future__ import annotations
+
+import logging
+import time
+from typing import Optional
+
+from app.db.geo_redis_client import radius_to_km
+from app.models import LiveForecastResponse, Venue, WeekRawDay
+
+logger = logging.getLogger(__name__)
+
+_BULK_ENRICHMENTS = (
+    "get_vibe_attributes_bulk",
+    "get_venue_photos_bulk",
+    "get_opening_hours_bulk",
+    "get_venue_instagram_bulk",
+    "get_venue_vibe_profile_bulk",
+)
+
+
+class _Snapshot:
+    def __init__(self, venues, live, partner_live, weekly, enrichments, taken_at):
+        self.venues: dict[str, Venue] = venues
+        self.live: dict[str, LiveForecastResponse] = live
+        self.partner_live: dict[str, LiveForecastResponse] = partner_live
+        self.weekly: dict[int, dict[str, WeekRawDay]] = weekly
+        self.enrichments: dict[str, dict] = enrichments
+        self.taken_at: float = taken_at
+
+
+class SnapshotVenueDAO:
+    """Read-only VenueDAO over the last snapshot of `source`."""
+
+    def __init__(self, source, max_age_seconds: float, clock=time.time) -> None:
+        self.source = source
+        self.max_age_seconds = max_age_seconds
+        self._clock = clock
+        self._snapshot: Optional[_Snapshot] = None
+
+    # ── lifecycle ───────────────────────────────────────────────────────────
+    def refresh(self) -> int:
+        """Take a new snapshot (blocking Redis reads); returns its venue count.
+
+        Raises:
+            Whatever the source raises while listing venues; the previous
+            snapshot is kept.
+        """
+        venues = {v.venue_id: v for v in self.source.list_all_venues() if v.is_active()}
+        if not venues and self._snapshot is not None and self._snapshot.venues:
+            logger.warning("[NearbySnapshot] Source returned no venues; keeping the previous snapshot")
+            return len(self._snapshot.venues)
+        ids = list(venues)
+        self._snapshot = _Snapshot(
+            venues=venues,
+            live=self.source.get_live_forecasts_bulk(ids),
+            partner_live=self.source.get_partner_live_bulk(ids),
+            weekly={day: self.source.get_week_raw_forecasts_bulk(ids, day) for day in range(7)},
+            enrichments={name: getattr(self.source, name)(ids) for name in _BULK_ENRICHMENTS},
+            taken_at=self._clock(),
+        )
+        logger.info(f"[NearbySnapshot] Snapshot refreshed with {len(ids)} venues")
+        return len(ids)
+
+    def age_seconds(self) -> Optional[float]:
+        """Seconds since the snapshot was taken, or None before the first one."""
+        if self._snapshot is None:
+            return None
+        return self._clock() - self._snapshot.taken_at
+
+    def usable(self) -> bool:
+        """Whether the snapshot exists and is recent enough to serve."""
+        age = self.age_seconds()
+        return age is not None and age <= self.max_age_seconds
+
+    # ── VenueDAO reads ──────────────────────────────────────────────────────
+    def _data(self) -> _Snapshot:
+        return self._snapshot or _Snapshot({}, {}, {}, {}, {}, 0.0)
+
+    def get_nearby_venues(
+        self,
+        lat: float,
+        lon: float,
+        radius: float,
+        include_deprecated: bool = False,
+        unit: str = "km",
+    ) -> list[Venue]:
+        # Lazy import: app.services' package init imports the DAO package.
+        from app.services.venue_eligibility import haversine_km
+
+        radius_km = radius_to_km(radius, unit)
+        return [
+            venue.model_copy()
+            for venue in self._data().venues.values()
+            if haversine_km(lat, lon, venue.venue_lat, venue.venue_lng) <= radius_km
+        ]
+
+    def get_venue(self, venue_id: str) -> Optional[Venue]:
+        venue = self._data().venues.get(venue_id)
+        return venue.model_copy() if venue is not None else None
+
+    def get_live_forecasts_bulk(self, venue_ids: list[str]) -> dict[str, LiveForecastResponse]:
+        live = self._data().live
+        return {vid: live[vid] for vid in venue_ids if vid in live}
+
+    def get_partner_live_bulk(self, venue_ids: list[str]) -> dict[str, LiveForecastResponse]:
+        live = self._data().partner_live
+        return {vid: live[vid] for vid in venue_ids if vid in live}
+
+    def get_week_raw_forecasts_bulk(self, venue_ids: list[str], day_int: int) -> dict[str, WeekRawDay]:
+        weekly = self._data().weekly.get(day_int, {})
+        return {vid: weekly[vid] for vid in venue_ids if vid in weekly}
+
+    def _enrichment(self, name: str, venue_ids: list[str]) -> dict:
+        values = self._data().enrichments.get(name, {})
+        return {vid: values[vid] for vid in venue_ids if vid in values}
+
+    def get_vibe_attributes_bulk(self, venue_ids: list[str]) -> dict:
+        return self._enrichment("get_vibe_attributes_bulk", venue_ids)
+
+    def get_venue_photos_bulk(self, venue_ids: list[str]) -> dict:
+        return self._enrichment("get_venue_photos_bulk", venue_ids)
+
+    def get_opening_hours_bulk(self, venue_ids: list[str]) -> dict:
+        return self._enrichment("get_opening_hours_bulk", venue_ids)
+
+    def get_venue_instagram_bulk(self, venue_ids: list[str]) -> dict:
+        return self._enrichment("get_venue_instagram_bulk", venue_ids)
+
+    def get_venue_vibe_profile_bulk(self, venue_ids: list[str]) -> dict:
+        return self._enrichment("get_venue_vibe_profile_bulk", venue_ids)
+
+    def get_venue_reviews(self, venue_id: str):
+        return None
+
+    def get_venue_menu_data(self, venue_id: str):
+        return None
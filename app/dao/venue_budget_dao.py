@@ -5,6 +5,9 @@ each time a venue_id that was not previously in our Redis geo index is
 added (either via the add-by-address path or via discovery). It is never
 explicitly reset — the calendar month rollover produces a fresh key,
 which implicitly starts at zero. Past months' counters are preserved.
+
+Tenants with their own quota (app/tenancy.py) also get a counter of their
+share: `venue_add_counter_v1:tenant:<tenant id>:YYYY-MM`.
 """
 from __future__ import annotations
 
@@ -15,6 +18,7 @@ from typing import Optional
 logger = logging.getLogger(__name__)
 
 VENUE_ADD_COUNTER_KEY_V1 = "venue_add_counter_v1:{year_month}"
+TENANT_VENUE_ADD_COUNTER_KEY_V1 = "venue_add_counter_v1:tenant:{tenant_id}:{year_month}"
 
 # Monthly ledger of distinct venue_ids touched against BestTime's unique-venue
 # cap (Redis set). The key naturally rolls over each calendar month; the TTL
@@ -36,13 +40,18 @@ class VenueBudgetDao:
         now = now or datetime.now(timezone.utc)
         return now.strftime("%Y-%m")
 
-    def _key(self, year_month: str) -> str:
+    def _key(self, year_month: str, tenant_id: Optional[str] = None) -> str:
+        if tenant_id:
+            return TENANT_VENUE_ADD_COUNTER_KEY_V1.format(
+                tenant_id=tenant_id, year_month=year_month
+            )
         return VENUE_ADD_COUNTER_KEY_V1.format(year_month=year_month)
 
-    def get_month_count(self, year_month: str) -> int:
-        """Read the current count for a given YYYY-MM. Returns 0 if unset."""
+    def get_month_count(self, year_month: str, tenant_id: Optional[str] = None) -> int:
+        """Read the current count for a given YYYY-MM (the tenant's share when
+        tenant_id is given). Returns 0 if unset."""
         try:
-            raw = self.redis.get(self._key(year_month))
+            raw = self.redis.get(self._key(year_month, tenant_id))
         except Exception as e:
             logger.error(
                 f"[VenueBudgetDao] get_month_count({year_month}) failed: {e}"
@@ -58,16 +67,18 @@ class VenueBudgetDao:
             )
             return 0
 
-    def increment_month(self, year_month: str, n: int = 1) -> int:
+    def increment_month(
+        self, year_month: str, n: int = 1, tenant_id: Optional[str] = None
+    ) -> int:
         """Atomically increment and return the new value.
 
         Uses Redis INCRBY so concurrent callers (manual add + discovery)
         cannot race on a check-then-set.
         """
         if n <= 0:
-            return self.get_month_count(year_month)
+            return self.get_month_count(year_month, tenant_id)
         try:
-            return int(self.redis.incrby(self._key(year_month), n))
+            return int(self.redis.incrby(self._key(year_month, tenant_id), n))
         except Exception as e:
             logger.error(
                 f"[VenueBudgetDao] increment_month({year_month}, {n}) failed: {e}"
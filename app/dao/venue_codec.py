@@ -0,0 +1,78 @@
+"""Schema-versioned Redis documents for venues (`venues_geo_place_v1:{id}`).
+
+Every venue document written carries a `schema_version`; documents written
+before it existed are version 1. `decode_venue` upgrades an older document in
+memory, one `VENUE_UPGRADES` step per version, before validating it as a
+Venue. A Venue model change therefore ships with an upgrade step here, and
+legacy records keep parsing on the nearby path instead of silently dropping
+out of it. The upgraded form reaches Redis the next time the venue is written
+(`encode_venue` always stamps the current version); nothing is rewritten on
+read.
+
+A document from a newer version than this build knows (mid rolling deploy) is
+parsed as is: Venue ignores fields it does not have.
+"""
+from __future__ import annotations
+
+import json
+from typing import Callable
+
+from app.metrics import VENUE_DOCUMENT_UPGRADES_TOTAL
+from app.models import Venue
+
+SCHEMA_VERSION_FIELD = "schema_version"
+VENUE_SCHEMA_VERSION = 2
+# Current documents start with this, so they skip the dict round-trip.
+_CURRENT_PREFIX = f'{{"{SCHEMA_VERSION_FIELD}":{VENUE_SCHEMA_VERSION},'
+
+
+def _v1_to_v2(doc: dict) -> dict:
+    """v2: longitude is always `venue_lng`, and an unknown price tier is null
+    rather than the legacy 0."""
+    if "venue_lng" not in doc and "venue_lon" in doc:
+        doc["venue_lng"] = doc.pop("venue_lon")
+    if doc.get("price_level") == 0:
+        doc["price_level"] = None
+    return doc
+
+
+# Version N -> the step that turns a version N document into version N + 1.
+# Append one entry (and bump VENUE_SCHEMA_VERSION) per stored-shape change.
+VENUE_UPGRADES: dict[int, Callable[[dict], dict]] = {
+    1: _v1_to_v2,
+}
+
+
+def encode_venue(venue: Venue) -> str:
+    """The stored JSON form of `venue`, stamped with the current version."""
+    body = venue.model_dump_json(by_alias=True)
+    return _CURRENT_PREFIX + body[1:]
+
+
+def upgrade_venue_document(doc: dict) -> dict:
+    """`doc` upgraded in place to the current version (without the version
+    field). Documents newer than this build are left alone."""
+    version = doc.pop(SCHEMA_VERSION_FIELD, 1)
+    if not isinstance(version, int) or version < 1:
+        raise ValueError(f"bad venue schema_version {version!r}")
+    if version < VENUE_SCHEMA_VERSION:
+        VENUE_DOCUMENT_UPGRADES_TOTAL.labels(from_version=str(version)).inc()
+    while version < VENUE_SCHEMA_VERSION:
+        doc = VENUE_UPGRADES[version](doc)
+        version += 1
+    return doc
+
+
+def decode_venue(raw: str) -> Venue:
+    """Parse a stored venue document of any version.
+
+    Raises:
+        ValueError: malformed document or version (pydantic's ValidationError
+            is also a ValueError)
+    """
+    if raw.startswith(_CURRENT_PREFIX):
+        return Venue.model_validate_json(raw)
+    doc = json.loads(raw)
+    if not isinstance(doc, dict):
+        raise ValueError("venue document is not a JSON object")
+    return Venue.model_validate(upgrade_venue_document(doc))
@@ -0,0 +1,99 @@
+"""The venue DAO interface handlers and services depend on.
+
+RedisVenueDAO (serving), VenueRepository (pipeline, RDS-backed) and
+RdsFallbackVenueDAO all satisfy it structurally, and so can a test double or
+an alternative backend (DemoVenueDAO covers the read side): nothing has to
+subclass VenueDAO, only provide the methods. Annotate new consumers with it
+rather than with a concrete DAO class.
+"""
+from __future__ import annotations
+
+from typing import Optional, Protocol
+
+from app.models import LiveForecastResponse, Venue, WeekRawDay
+from app.models.instagram import VenueInstagram
+from app.models.menu import VenueMenuData
+from app.models.opening_hours import OpeningHours
+from app.models.venue_review import VenueReviews
+from app.models.vibe_attributes import VibeAttributes
+from app.models.vibe_profile import VenueVibeProfile
+
+
+class VenueDAO(Protocol):
+    """Venue documents, geo lookups, live/weekly forecasts and listings.
+
+    Bulk getters return only the ids that have a value; single getters return
+    None for a missing (or unreadable) value rather than raising.
+    """
+
+    # ── venues ──────────────────────────────────────────────────────────────
+    def upsert_venue(self, venue: Venue) -> None: ...
+
+    def upsert_venues(
+        self,
+        venues: list[Venue],
+        chunk_size: int = ...,
+        errors: Optional[dict[str, Exception]] = None,
+    ) -> int: ...
+
+    def get_venue(self, venue_id: str) -> Optional[Venue]: ...
+
+    def get_nearby_venues(
+        self,
+        lat: float,
+        lon: float,
+        radius: float,
+        include_deprecated: bool = False,
+        unit: str = "km",
+    ) -> list[Venue]: ...
+
+    def count_venues_in_radius(self, lat: float, lon: float, radius_m: float) -> int: ...
+
+    # ── listings ────────────────────────────────────────────────────────────
+    def list_all_venues(self) -> list[Venue]: ...
+
+    def list_servable_venue_ids(self) -> list[str]: ...
+
+    # ── live forecasts ──────────────────────────────────────────────────────
+    def set_live_forecast(self, forecast: LiveForecastResponse) -> Optional[bool]: ...
+
+    def get_live_forecast(self, venue_id: str) -> Optional[LiveForecastResponse]: ...
+
+    def get_live_forecasts_bulk(self, venue_ids: list[str]) -> dict[str, LiveForecastResponse]: ...
+
+    def delete_live_forecast(self, venue_id: str) -> bool: ...
+
+    def get_partner_live_bulk(self, venue_ids: list[str]) -> dict[str, LiveForecastResponse]: ...
+
+    # ── weekly forecasts ────────────────────────────────────────────────────
+    def set_week_raw_forecast(self, venue_id: str, day: WeekRawDay) -> None: ...
+
+    def get_week_raw_forecast(self, venue_id: str, day_int: int) -> Optional[WeekRawDay]: ...
+
+    def get_week_raw_forecasts_bulk(
+        self, venue_ids: list[str], day_int: int
+    ) -> dict[str, WeekRawDay]: ...
+
+    # ── enrichment reads (nearby responses) ─────────────────────────────────
+    def get_vibe_attributes_bulk(self, venue_ids: list[str]) -> dict[str, VibeAttributes]: ...
+
+    def get_venue_photos_bulk(self, venue_ids: list[str]) -> dict[str, list[dict]]: ...
+
+    def get_opening_hours_bulk(self, venue_ids: list[str]) -> dict[str, OpeningHours]: ...
+
+    def get_venue_instagram_bulk(self, venue_ids: list[str]) -> dict[str, VenueInstagram]: ...
+
+    def get_venue_vibe_profile_bulk(self, venue_ids: list[str]) -> dict[str, VenueVibeProfile]: ...
+
+    def get_venue_reviews(self, venue_id: str) -> Optional[VenueReviews]: ...
+
+    def get_venue_menu_data(self, venue_id: str) -> Optional[VenueMenuData]: ...
+
+
+class PrioritizedVenueDAO(VenueDAO, Protocol):
+    """A VenueDAO backed by the catalog (RDS), which knows refresh priorities.
+
+    VenuesRefresherService needs this only when a budget service is wired.
+    """
+
+    def list_servable_venue_ids_by_priority(self, limit: int) -> list[str]: ...
@@ -26,6 +26,7 @@ from app.models.opening_hours import OpeningHours
 from app.models.venue_review import VenueReviews
 from app.models.vibe_attributes import VibeAttributes
 from app.models.vibe_profile import VenueVibeProfile
+from app.services.venue_validation import VenueRejectedError, VenueValidator
 
 logger = logging.getLogger(__name__)
 
@@ -42,6 +43,14 @@ class VenueRepository(RedisVenueDAO):
     def __init__(self, client, rds_store):
         super().__init__(client)  # geo reads (get_nearby_venues, etc.) stay on Redis
         self.rds_store = rds_store
+        # Every venue write is validated here, whoever writes it (add-venue,
+        # imports, restores, enrichments, approvals); rejected and flagged
+        # venues are only logged until set_venue_quarantine.
+        self.venue_validator = VenueValidator()
+
+    def set_venue_quarantine(self, quarantine) -> None:
+        """Wire the VenueQuarantine rejected and flagged venues are listed in."""
+        self.venue_validator.quarantine = quarantine
 
     # ── pipeline data reads from RDS ────────────────────────────────────────────
     def _rds_enrichment(self, table_key, model_cls, venue_id):
@@ -98,6 +107,17 @@ class VenueRepository(RedisVenueDAO):
         rec = self.rds_store.get_live_forecast(venue_id)
         return LiveForecastResponse.model_validate(rec["payload"]) if rec else None
 
+    def get_live_forecasts_bulk(self, venue_ids):
+        """From RDS like get_live_forecast, so the post-refresh consumers see
+        the values the refresh just wrote rather than the last projection."""
+        out = {}
+        for venue_id, rec in self.rds_store.get_live_bulk(venue_ids).items():
+            try:
+                out[venue_id] = LiveForecastResponse.model_validate(rec["payload"])
+            except Exception as e:
+                logger.warning(f"[VenueRepository] RDS live forecast skip for {venue_id}: {e}")
+        return out
+
     def list_active_venue_ids(self):
         return self.rds_store.list_active_venue_ids()
 
@@ -128,7 +148,39 @@ class VenueRepository(RedisVenueDAO):
     # ── writes: RDS-only — the projector is the sole Redis writer ────────────────
     # ── core venue ────────────────────────────────────────────────────────────
     def upsert_venue(self, venue) -> None:
-        self.rds_store.upsert_venue(venue)  # truth; projector projects to Redis + geo
+        """Validate, then write to RDS (truth; the projector projects to Redis + geo).
+
+        Raises:
+            VenueRejectedError: the venue failed validation and was not written
+        """
+        checked = self.venue_validator.check(venue, source="write")
+        if checked is None:
+            raise VenueRejectedError(venue)
+        self.rds_store.upsert_venue(checked)
+
+    def upsert_venues(self, venues, chunk_size=None, errors=None) -> int:
+        # RDS has no batch upsert; each row keeps its own address dual-write
+        # transaction. chunk_size is Redis pipelining only (signature parity).
+        # Rejected venues are skipped (and quarantined); with `errors`, they and
+        # any failed row are recorded there and the rest still run.
+        del chunk_size
+        written = 0
+        for venue in venues:
+            checked = self.venue_validator.check(venue, source="write")
+            if checked is None:
+                if errors is not None:
+                    errors[venue.venue_id] = VenueRejectedError(venue)
+                continue
+            if errors is None:
+                self.rds_store.upsert_venue(checked)
+            else:
+                try:
+                    self.rds_store.upsert_venue(checked)
+                except Exception as e:
+                    errors[venue.venue_id] = e
+                    continue
+            written += 1
+        return written
 
     def soft_delete_venue(self, venue_id, reason, source, google_business_status=None) -> bool:
         self.rds_store.soft_delete_venue(venue_id, reason, source, google_business_status)
@@ -53,6 +53,12 @@ RESIDUAL_FIELDS: tuple[str, ...] = (
     # metadata read only by undo_geo_link, not worth a promoted column.
     "geo_linked",
     "geo_linked_year_month",
+    # Refresher write time (Venue.refreshed_at) — pipeline metadata, no column.
+    "refreshed_at",
+    # OSM / Foursquare enrichment (Venue.open_data) — nested document.
+    "open_data",
+    # Photo / website / social links (Venue.media) — nested document.
+    "media",
 )
 
 # Invariant: columns ∪ residual == the full Venue field set, so reconstruction
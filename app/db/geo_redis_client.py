@@ -5,20 +5,54 @@ from typing import Any, Optional
 import redis
 from redis.commands.search.field import GeoField
 
+from app.db.redis_factory import is_cluster
+from app.db.value_compression import ValueCompressor
+from app.tenancy import strip_tenant_key, tenant_key
+
 logger = logging.getLogger(__name__)
 
+# Radius units GEORADIUS understands, with their size in kilometers.
+RADIUS_UNITS_KM = {"m": 0.001, "km": 1.0, "mi": 1.609344, "ft": 0.0003048}
+
+
+def radius_to_km(radius: float, unit: str) -> float:
+    """Convert a radius in `unit` to kilometers.
+
+    Raises:
+        ValueError: unsupported unit or a negative radius
+    """
+    if unit not in RADIUS_UNITS_KM:
+        raise ValueError(
+            f"unsupported radius unit {unit!r}; expected one of {sorted(RADIUS_UNITS_KM)}"
+        )
+    if radius < 0:
+        raise ValueError(f"radius must be non-negative, got {radius}")
+    return radius * RADIUS_UNITS_KM[unit]
+
 
 class GeoRedisClient:
-    """Redis client with geospatial indexing support."""
+    """Redis client with geospatial indexing support.
 
-    def __init__(self, client):
+    Every key and channel goes through `tenant_key`, so while a tenant is
+    current (app/tenancy.py) reads and writes stay in its keyspace. Geo
+    members are stored unprefixed and resolved through this client too.
+    """
+
+    def __init__(self, client, compressor: Optional[ValueCompressor] = None):
         """Initialize Redis client.
         
         Args:
             client: Redis client
+            compressor: Compression for large values written through set/
+                setex/add_location(s)_with_json (app/db/value_compression.py).
+                Reads always expand compressed values, whatever is configured.
         """
         logging.info("Passing redis client")
         self.client = client
+        self.compressor = compressor or ValueCompressor()
+        # Cluster mode: no MULTI/EXEC across slots and MGET must be split per
+        # slot, so _pipeline/_mget adapt (see app/db/redis_factory.py).
+        self.is_cluster = is_cluster(client)
 
         # Test connection
         try:
@@ -28,6 +62,33 @@ class GeoRedisClient:
             logger.error(f"Could not connect to Redis: {e}")
             raise
 
+    def _pipeline(self, transaction: bool):
+        """A pipeline, transactional when asked and the topology allows it.
+
+        In cluster mode the keys of one logical write (e.g. the geo set and a
+        member's JSON key) live in different slots, so the batch is sent as a
+        plain pipeline: commands still go out together but are not atomic.
+        """
+        if self.is_cluster:
+            return self.client.pipeline()
+        return self.client.pipeline(transaction=transaction)
+
+    def _mget(self, keys: list[str]) -> list[Optional[str]]:
+        scoped = [tenant_key(key) for key in keys]
+        if self.is_cluster:
+            values = self.client.mget_nonatomic(scoped)
+        else:
+            values = self.client.mget(scoped)
+        return [self._decode(key, value) for key, value in zip(keys, values)]
+
+    def _decode(self, key: str, value: Optional[str]) -> Optional[str]:
+        """Expand a stored value; a corrupt compressed value reads as absent."""
+        try:
+            return self.compressor.decode(value)
+        except ValueError as e:
+            logger.error(f"Failed to decompress value of {key}: {e}")
+            return None
+
     def set(self, key: str, value: str) -> None:
         """Set a key-value pair in Redis.
 
@@ -35,7 +96,7 @@ class GeoRedisClient:
             key: Redis key
             value: String value to store
         """
-        self.client.set(key, value)
+        self.client.set(tenant_key(key), self.compressor.encode(value))
 
     def get(self, key: str) -> Optional[str]:
         """Get value for a given key from Redis.
@@ -46,7 +107,7 @@ class GeoRedisClient:
         Returns:
             String value or None if key doesn't exist
         """
-        return self.client.get(key)
+        return self._decode(key, self.client.get(tenant_key(key)))
 
     def mget(self, keys: list[str]) -> list[Optional[str]]:
         """Get values for multiple keys in one round-trip (P2/P5).
@@ -61,7 +122,7 @@ class GeoRedisClient:
         """
         if not keys:
             return []
-        return self.client.mget(keys)
+        return self._mget(keys)
 
     def keys(self, pattern: str) -> list[str]:
         """Return all keys matching the given pattern.
@@ -78,7 +139,9 @@ class GeoRedisClient:
         Returns:
             List of matching keys (unique, order not guaranteed to match KEYS)
         """
-        return list(dict.fromkeys(self.client.scan_iter(match=pattern)))
+        return list(dict.fromkeys(
+            strip_tenant_key(key) for key in self.client.scan_iter(match=tenant_key(pattern))
+        ))
 
     def setex(self, key: str, ttl_seconds: int, value: str) -> None:
         """Set a key-value pair with expiration.
@@ -88,7 +151,7 @@ class GeoRedisClient:
             ttl_seconds: Time-to-live in seconds
             value: String value to store
         """
-        self.client.setex(key, ttl_seconds, value)
+        self.client.setex(tenant_key(key), ttl_seconds, self.compressor.encode(value))
 
     def del_(self, key: str) -> int:
         """Delete a key from Redis.
@@ -101,7 +164,7 @@ class GeoRedisClient:
             callers can distinguish a real removal from a no-op delete of an
             already-absent key.
         """
-        return self.client.delete(key)
+        return self.client.delete(tenant_key(key))
 
     def zrem(self, name: str, *values: str) -> int:
         """Remove members from a sorted set (including geo sets).
@@ -113,7 +176,75 @@ class GeoRedisClient:
         Returns:
             Number of members removed
         """
-        return self.client.zrem(name, *values)
+        return self.client.zrem(tenant_key(name), *values)
+
+    def publish_many(self, channel: str, messages: list[str]) -> None:
+        """PUBLISH every message on `channel` in one pipelined round-trip
+        (delivery order is preserved; nothing is stored if no one listens)."""
+        if not messages:
+            return
+        channel = tenant_key(channel)
+        pipe = self._pipeline(transaction=False)
+        for message in messages:
+            pipe.publish(channel, message)
+        pipe.execute()
+
+    def add_capped_timeline_entry(
+        self,
+        key: str,
+        score: float,
+        member: str,
+        min_score: float,
+        max_members: int,
+        ttl_seconds: int,
+    ) -> None:
+        """Append a member to a time-ordered sorted set and cap it, in one
+        round-trip.
+
+        Re-adding an identical (score, member) pair is a no-op, so replaying
+        the same sample never duplicates it.
+
+        Args:
+            key: Redis sorted set key
+            score: Sort score (e.g. epoch seconds)
+            member: Member value
+            min_score: Members scored below this are trimmed
+            max_members: Only the highest-scored members up to this count are kept
+            ttl_seconds: Expiry refreshed on every append
+        """
+        key = tenant_key(key)
+        pipe = self._pipeline(transaction=False)
+        pipe.zadd(key, {member: score})
+        pipe.zremrangebyscore(key, "-inf", f"({min_score}")
+        pipe.zremrangebyrank(key, 0, -(max_members + 1))
+        pipe.expire(key, ttl_seconds)
+        pipe.execute()
+
+    def zrangebyscore(self, key: str, min_score, max_score) -> list[tuple[str, float]]:
+        """Members of a sorted set within [min_score, max_score], ascending,
+        with their scores.
+
+        Args:
+            key: Redis sorted set key
+            min_score: Lower bound (inclusive; "-inf" for unbounded)
+            max_score: Upper bound (inclusive; "+inf" for unbounded)
+
+        Returns:
+            (member, score) pairs in ascending score order
+        """
+        return self.client.zrangebyscore(tenant_key(key), min_score, max_score, withscores=True)
+
+    def zrangebyscore_many(
+        self, keys: list[str], min_score, max_score
+    ) -> list[list[tuple[str, float]]]:
+        """`zrangebyscore` for several keys in one pipelined round-trip;
+        results in the order of `keys`."""
+        if not keys:
+            return []
+        pipe = self._pipeline(transaction=False)
+        for key in keys:
+            pipe.zrangebyscore(tenant_key(key), min_score, max_score, withscores=True)
+        return pipe.execute()
 
     def add_location_with_json(
         self,
@@ -129,35 +260,104 @@ class GeoRedisClient:
         1. Adds the location to a geospatial index using GEOADD
         2. Stores the JSON data separately using SET
 
+        Both commands go out in one MULTI/EXEC transaction, so a crash or
+        dropped connection can never leave a geo member whose JSON key is
+        missing (a dangling member that get_locations_within_radius would
+        silently skip).
+
         Args:
             geo_key: Redis geo set key (e.g., "venues_geo_v1")
             member_key: Member identifier in the geo set (e.g., "venues_geo_place_v1:venue_123")
             lat: Latitude
             lon: Longitude
-            data: Python object to serialize as JSON
+            data: Python object to serialize as JSON (a str is stored as is)
         """
         # Serialize data to JSON
-        if hasattr(data, "model_dump"):
+        if isinstance(data, str):
+            # Already encoded by the caller
+            json_data = data
+        elif hasattr(data, "model_dump"):
             # Pydantic model
             json_data = data.model_dump_json(by_alias=True)
         else:
             json_data = json.dumps(data)
 
+        pipe = self._pipeline(transaction=True)
         # Store geolocation using GEOADD
         # Note: Redis GEOADD expects (longitude, latitude) order
-        self.client.geoadd(geo_key, (lon, lat, member_key))
-
+        pipe.geoadd(tenant_key(geo_key), (lon, lat, member_key))
         # Store JSON data associated with the member
-        self.client.set(member_key, json_data)
+        pipe.set(tenant_key(member_key), self.compressor.encode(json_data))
+        pipe.execute()
 
         logger.debug(f"Added geolocation and JSON for member: {member_key}")
 
+    def add_locations_with_json(
+        self,
+        geo_key: str,
+        items: list[tuple[str, float, float, Any]],
+        chunk_size: int = 500,
+        errors: Optional[dict[str, Exception]] = None,
+    ) -> int:
+        """Bulk counterpart of `add_location_with_json`, pipelined per chunk.
+
+        Each chunk queues one GEOADD (all the chunk's members) plus one SET per
+        member on a pipeline and sends them in a single round-trip, so N
+        upserts cost ceil(N / chunk_size) round-trips instead of 2N. Each
+        chunk is its own MULTI/EXEC transaction (same atomicity as the
+        single-item path: no geo member without its JSON). A failed chunk
+        raises; chunks already sent stay written. When `errors` is given, a
+        failed chunk maps each of its member keys to the exception there
+        instead and the remaining chunks are still sent.
+
+        Args:
+            geo_key: Redis geo set key (e.g., "venues_geo_v1")
+            items: (member_key, lat, lon, data) tuples, same meaning as the
+                single-item arguments
+            chunk_size: Members per pipeline round-trip (values < 1 mean 1)
+            errors: Optional {member_key: exception} collector (see above)
+
+        Returns:
+            Number of members written
+        """
+        chunk_size = max(1, chunk_size)
+        written = 0
+        for start in range(0, len(items), chunk_size):
+            chunk = items[start:start + chunk_size]
+            pipe = self._pipeline(transaction=True)
+            geo_values: list = []
+            for member_key, lat, lon, _ in chunk:
+                # GEOADD expects (longitude, latitude, member) triples
+                geo_values.extend((lon, lat, member_key))
+            pipe.geoadd(tenant_key(geo_key), geo_values)
+            for member_key, _, _, data in chunk:
+                if isinstance(data, str):
+                    json_data = data
+                elif hasattr(data, "model_dump_json"):
+                    json_data = data.model_dump_json(by_alias=True)
+                else:
+                    json_data = json.dumps(data)
+                pipe.set(tenant_key(member_key), self.compressor.encode(json_data))
+            try:
+                pipe.execute()
+            except redis.RedisError as e:
+                if errors is None:
+                    raise
+                logger.error(f"Bulk geo write of {len(chunk)} members under {geo_key} failed: {e}")
+                errors.update((member_key, e) for member_key, _, _, _ in chunk)
+                continue
+            written += len(chunk)
+
+        logger.debug(f"Bulk-added {written} geolocations with JSON under {geo_key}")
+        return written
+
     def get_locations_within_radius(
         self,
         key: str,
         lat: float,
         lon: float,
         radius: float,
+        unit: str = "km",
     ) -> list[str]:
         """Find all locations within the given radius and return their JSON data.
 
@@ -165,21 +365,26 @@ class GeoRedisClient:
             key: Redis geo set key
             lat: Center latitude
             lon: Center longitude
-            radius: Radius in kilometers
+            radius: Radius, in `unit`
+            unit: One of RADIUS_UNITS_KM ("m", "km", "mi", "ft")
+
+        Raises:
+            ValueError: unsupported unit or a negative radius
 
         Returns:
             List of JSON strings for matching locations
         """
         logger.debug(f"Reading from radius with key: {key}")
 
+        radius_to_km(radius, unit)  # validate before the round-trip
+
         # GEORADIUS expects (longitude, latitude) order
-        # radius is in kilometers
         results = self.client.georadius(
-            key,
+            tenant_key(key),
             longitude=lon,
             latitude=lat,
             radius=radius,
-            unit="km",
+            unit=unit,
             withcoord=False,
             withdist=False,
             withhash=False,
@@ -193,7 +398,7 @@ class GeoRedisClient:
         # 500), the aggregate of what a connection error would have done to
         # every per-member GET in the old loop.
         try:
-            values = self.client.mget(results)
+            values = self._mget(results)
         except redis.RedisError as e:
             logger.warning(f"Bulk get for {len(results)} members failed: {e}")
             return []
@@ -0,0 +1,205 @@
+"""Build the raw redis client for the configured topology.
+
+settings.redis_mode selects it:
+
+- "standalone" (default): a single node at redis_host:redis_port.
+- "sentinel": a failover client for redis_sentinel_master, discovered through
+  redis_sentinel_nodes; reconnects to the new master after a failover.
+- "cluster": a RedisCluster seeded from redis_cluster_nodes.
+
+Every mode returns an object with the plain redis-py command surface, so
+GeoRedisClient and the raw-client users (admin config, job locks, migrations)
+work unchanged. The two cluster-mode differences (no MULTI across slots, MGET
+must be split per slot) are handled by `is_cluster` checks at the call sites.
+
+Every mode shares the same pool and timeout tuning (`connection_kwargs`). The
+socket timeout is the deadline of each individual command: a call that gets no
+reply within it raises TimeoutError (retried with backoff, then surfaced to the
+caller) instead of blocking the request, or a job, on a wedged connection.
+
+`build_async_redis_client` builds an asyncio client for the same topology, for
+pub/sub readers that wait on the event loop instead of holding a thread per
+subscription (the gRPC live-update stream).
+"""
+import logging
+
+import redis
+import redis.asyncio
+from redis.asyncio.sentinel import Sentinel as AsyncSentinel
+from redis.backoff import EqualJitterBackoff
+from redis.cluster import ClusterNode, RedisCluster
+from redis.exceptions import ConnectionError as RedisConnectionError
+from redis.exceptions import TimeoutError as RedisTimeoutError
+from redis.retry import Retry
+from redis.sentinel import Sentinel
+
+logger = logging.getLogger(__name__)
+
+REDIS_MODES = ("standalone", "sentinel", "cluster")
+
+
+def parse_nodes(nodes: list[str], default_port: int) -> list[tuple[str, int]]:
+    """["host:port", "host"] -> [(host, port), (host, default_port)].
+
+    Raises:
+        ValueError: empty list or an unparseable port
+    """
+    if not nodes:
+        raise ValueError("at least one node is required")
+    parsed = []
+    for node in nodes:
+        host, sep, port = node.strip().rpartition(":")
+        if not sep:
+            host, port = port, str(default_port)
+        if not host or not port.isdigit():
+            raise ValueError(f"invalid redis node {node!r}; expected host:port")
+        parsed.append((host, int(port)))
+    return parsed
+
+
+def is_cluster(client) -> bool:
+    """True for a RedisCluster (MULTI and cross-slot MGET are unavailable)."""
+    return isinstance(client, RedisCluster)
+
+
+def connection_kwargs(settings) -> dict:
+    """Pool size, timeouts and retry policy shared by every topology."""
+    return {
+        "max_connections": settings.redis_max_connections,
+        "socket_connect_timeout": settings.redis_connect_timeout_seconds,
+        "socket_timeout": settings.redis_socket_timeout_seconds,
+        "health_check_interval": settings.redis_health_check_interval_seconds,
+        "retry": Retry(
+            EqualJitterBackoff(
+                cap=settings.redis_retry_backoff_cap_seconds,
+                base=settings.redis_retry_backoff_base_seconds,
+            ),
+            settings.redis_retry_attempts,
+        ),
+        "retry_on_error": [RedisConnectionError, RedisTimeoutError],
+    }
+
+
+def prewarm_pool(client, count: int) -> int:
+    """Open `count` connections up front and return them to the pool idle, so
+    the first burst of requests after startup does not pay the connect cost.
+    Best-effort (a failure only logs); a no-op for cluster clients, whose
+    per-node pools are created lazily as slots are discovered. Returns the
+    number of connections opened."""
+    if count <= 0 or is_cluster(client):
+        return 0
+    pool = client.connection_pool
+    opened = []
+    try:
+        for _ in range(count):
+            # get_connection connects (and health-checks) before returning.
+            opened.append(pool.get_connection("PING"))
+    except redis.RedisError as e:
+        logger.warning(f"[RedisFactory] Pool prewarm stopped after {len(opened)}: {e}")
+    finally:
+        for conn in opened:
+            pool.release(conn)
+    return len(opened)
+
+
+def build_redis_client(settings):
+    """The raw redis client for settings.redis_mode (decode_responses=True).
+
+    Raises:
+        ValueError: unknown mode or missing/invalid node list for the mode
+    """
+    mode = settings.redis_mode
+    password = settings.redis_password or None
+    tuning = connection_kwargs(settings)
+    if mode == "standalone":
+        logger.info(
+            f"[RedisFactory] Standalone Redis at {settings.redis_host}:{settings.redis_port}"
+        )
+        return redis.Redis(
+            host=settings.redis_host,
+            port=settings.redis_port,
+            password=password,
+            db=settings.redis_db,
+            decode_responses=True,
+            **tuning,
+        )
+    if mode == "sentinel":
+        nodes = parse_nodes(settings.redis_sentinel_nodes, 26379)
+        logger.info(
+            f"[RedisFactory] Sentinel master {settings.redis_sentinel_master!r} via {nodes}"
+        )
+        sentinel = Sentinel(
+            nodes,
+            sentinel_kwargs={
+                "password": settings.redis_sentinel_password or None,
+                "socket_connect_timeout": settings.redis_connect_timeout_seconds,
+                "socket_timeout": settings.redis_socket_timeout_seconds,
+            },
+        )
+        return sentinel.master_for(
+            settings.redis_sentinel_master,
+            password=password,
+            db=settings.redis_db,
+            decode_responses=True,
+            **tuning,
+        )
+    if mode == "cluster":
+        nodes = parse_nodes(settings.redis_cluster_nodes, settings.redis_port)
+        logger.info(f"[RedisFactory] Redis Cluster seeded from {nodes}")
+        # Cluster mode has a single logical database; redis_db is ignored.
+        return RedisCluster(
+            startup_nodes=[ClusterNode(host, port) for host, port in nodes],
+            password=password,
+            decode_responses=True,
+            **tuning,
+        )
+    raise ValueError(f"unknown redis_mode {mode!r}; expected one of {REDIS_MODES}")
+
+
+def build_async_redis_client(settings):
+    """An asyncio client (decode_responses=True) for pub/sub subscribers.
+
+    It has no socket timeout: a subscriber waits for messages as long as it
+    needs. In cluster mode it connects to the first seed node, since PUBLISH
+    reaches the subscribers of every node.
+
+    Raises:
+        ValueError: unknown mode or missing/invalid node list for the mode
+    """
+    mode = settings.redis_mode
+    password = settings.redis_password or None
+    tuning = {
+        "socket_connect_timeout": settings.redis_connect_timeout_seconds,
+        "health_check_interval": settings.redis_health_check_interval_seconds,
+    }
+    if mode == "standalone":
+        return redis.asyncio.Redis(
+            host=settings.redis_host,
+            port=settings.redis_port,
+            password=password,
+            db=settings.redis_db,
+            decode_responses=True,
+            **tuning,
+        )
+    if mode == "sentinel":
+        sentinel = AsyncSentinel(
+            parse_nodes(settings.redis_sentinel_nodes, 26379),
+            sentinel_kwargs={
+                "password": settings.redis_sentinel_password or None,
+                "socket_connect_timeout": settings.redis_connect_timeout_seconds,
+                "socket_timeout": settings.redis_socket_timeout_seconds,
+            },
+        )
+        return sentinel.master_for(
+            settings.redis_sentinel_master,
+            password=password,
+            db=settings.redis_db,
+            decode_responses=True,
+            **tuning,
+        )
+    if mode == "cluster":
+        host, port = parse_nodes(settings.redis_cluster_nodes, settings.redis_port)[0]
+        return redis.asyncio.Redis(
+            host=host, port=port, password=password, decode_responses=True, **tuning
+        )
+    raise ValueError(f"unknown redis_mode {mode!r}; expected one of {REDIS_MODES}")
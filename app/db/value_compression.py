@@ -0,0 +1,98 @@
+"""Transparent compression of large string values written through GeoRedisClient.
+
+Verbose venue documents (and other cached JSON blobs) above
+settings.redis_compression_min_bytes are compressed with
+settings.redis_compression ("gzip", or "snappy" when python-snappy is
+installed) and stored as
+
+    <magic><base64 payload>
+
+The magic prefix starts with a control character (0x1F) that never begins a
+JSON document or any other value we store, so reads can tell compressed values
+apart without a flag. Base64 is needed because the clients run with
+decode_responses=True (every value must be UTF-8 text); a value that would not
+shrink despite it is stored uncompressed.
+
+Decoding does not depend on the configured algorithm: a reader always expands
+whatever it finds, so compression can be switched on, off or between algorithms
+without migrating existing keys. Raw-client readers (redis-cli, scripts) see
+the encoded form.
+"""
+from __future__ import annotations
+
+import base64
+import gzip
+
+from app.metrics import REDIS_COMPRESSION_SAVED_BYTES_TOTAL
+
+COMPRESSION_ALGORITHMS = ("none", "gzip", "snappy")
+_MAGIC = {"gzip": "\x1fGZ1:", "snappy": "\x1fSN1:"}
+_MAGIC_LEAD = "\x1f"
+
+
+def _snappy():
+    try:
+        import snappy
+    except ImportError as e:
+        raise RuntimeError("redis_compression=snappy requires python-snappy") from e
+    return snappy
+
+
+def _compress(algorithm: str, data: bytes) -> bytes:
+    if algorithm == "gzip":
+        return gzip.compress(data)
+    return _snappy().compress(data)
+
+
+def _decompress(algorithm: str, data: bytes) -> bytes:
+    if algorithm == "gzip":
+        return gzip.decompress(data)
+    return _snappy().decompress(data)
+
+
+class ValueCompressor:
+    """Encodes values on write and expands them on read (see module docstring)."""
+
+    def __init__(self, algorithm: str = "none", min_bytes: int = 1024) -> None:
+        if algorithm not in COMPRESSION_ALGORITHMS:
+            raise ValueError(
+                f"unknown redis_compression {algorithm!r}; expected one of {COMPRESSION_ALGORITHMS}"
+            )
+        if algorithm == "snappy":
+            _snappy()  # fail at startup when the library is missing
+        self.algorithm = algorithm
+        self.min_bytes = min_bytes
+
+    def encode(self, value: str) -> str:
+        """`value`, compressed when enabled and at least min_bytes long."""
+        if self.algorithm == "none":
+            return value
+        raw = value.encode("utf-8")
+        if len(raw) < self.min_bytes:
+            return value
+        encoded = _MAGIC[self.algorithm] + base64.b64encode(
+            _compress(self.algorithm, raw)
+        ).decode("ascii")
+        if len(encoded) >= len(raw):
+            return value
+        REDIS_COMPRESSION_SAVED_BYTES_TOTAL.labels(algorithm=self.algorithm).inc(
+            len(raw) - len(encoded)
+        )
+        return encoded
+
+    def decode(self, value):
+        """The original text of a stored value (None and plain values pass through).
+
+        Raises:
+            ValueError: a compressed value whose payload is corrupt
+        """
+        if not isinstance(value, str) or not value.startswith(_MAGIC_LEAD):
+            return value
+        for algorithm, magic in _MAGIC.items():
+            if value.startswith(magic):
+                try:
+                    payload = base64.b64decode(value[len(magic):], validate=True)
+                    return _decompress(algorithm, payload).decode("utf-8")
+                except (OSError, EOFError) as e:  # gzip.BadGzipFile is an OSError
+                    raise ValueError(f"corrupt {algorithm} value: {e}") from e
+        return value
@@ -0,0 +1,281 @@
+"""Runtime diagnostics on a separate internal port.
+
+Off unless settings.diagnostics_port is set. The port serves a small app next
+to the public one (same process, same event loop) that is never routed
+through the load balancer, so profiling a slow production refresh does not
+require exposing anything publicly:
+
+- GET /debug/vars: process, memory, GC, asyncio task and thread counts, and
+  the state of every scheduled job (the /admin/scheduler view);
+- GET /debug/pprof/profile?seconds=N: a cProfile of the event loop thread for
+  N seconds, as pstats text (sorted by `sort`, top `limit`) or, with
+  `format=pstats`, the raw stats file for snakeviz / `python -m pstats`;
+- GET /debug/pprof/tasks: the stack of every asyncio task and thread;
+- GET /debug/pprof/heap: the top allocation sites, when tracemalloc runs
+  (settings.diagnostics_tracemalloc_frames > 0; it costs memory and CPU).
+
+The app shares the main event loop: while CPU work blocks the loop these
+answers wait too, and the profile (started before) shows where the time went.
+"""
+from __future__ import annotations
+
+import asyncio
+import contextlib
+import cProfile
+import gc
+import io
+import logging
+import marshal
+import os
+import platform
+import pstats
+import resource
+import sys
+import threading
+import time
+import traceback
+import tracemalloc
+from datetime import datetime, timezone
+from typing import Callable, Optional
+
+from fastapi import FastAPI, HTTPException, Query
+from fastapi.responses import PlainTextResponse, Response
+
+from app import __version__
+
+logger = logging.getLogger(__name__)
+
+MAX_PROFILE_SECONDS = 120
+PROFILE_SORT_KEYS = ("cumulative", "tottime", "calls", "ncalls")
+
+_STARTED_AT = datetime.now(timezone.utc)
+_profile_lock = threading.Lock()
+
+
+class GcTimer:
+    """Collection counts and pause time per generation (gc.callbacks)."""
+
+    def __init__(self) -> None:
+        self.collections = [0, 0, 0]
+        self.pause_seconds = [0.0, 0.0, 0.0]
+        self._start: Optional[float] = None
+
+    def __call__(self, phase: str, info: dict) -> None:
+        if phase == "start":
+            self._start = time.perf_counter()
+        elif self._start is not None:
+            generation = info.get("generation", 0)
+            self.collections[generation] += 1
+            self.pause_seconds[generation] += time.perf_counter() - self._start
+            self._start = None
+
+    def install(self) -> None:
+        if self not in gc.callbacks:
+            gc.callbacks.append(self)
+
+    def uninstall(self) -> None:
+        if self in gc.callbacks:
+            gc.callbacks.remove(self)
+
+
+gc_timer = GcTimer()
+
+
+def _rss_bytes() -> Optional[int]:
+    """Current resident set size (Linux /proc; None elsewhere)."""
+    try:
+        with open("/proc/self/statm") as f:
+            return int(f.read().split()[1]) * os.sysconf("SC_PAGE_SIZE")
+    except (OSError, ValueError, IndexError):
+        return None
+
+
+def runtime_vars(jobs: Callable[[], list[dict]]) -> dict:
+    """The /debug/vars payload."""
+    max_rss = resource.getrusage(resource.RUSAGE_SELF).ru_maxrss
+    try:
+        tasks = len(asyncio.all_tasks())
+    except RuntimeError:  # no running loop
+        tasks = None
+    current, peak = tracemalloc.get_traced_memory() if tracemalloc.is_tracing() else (None, None)
+    try:
+        job_states = jobs()
+    except Exception as e:
+        job_states = {"error": str(e)}
+    return {
+        "process": {
+            "pid": os.getpid(),
+            "version": __version__,
+            "python": platform.python_version(),
+            "started_at": _STARTED_AT.isoformat(),
+            "uptime_seconds": round((datetime.now(timezone.utc) - _STARTED_AT).total_seconds()),
+        },
+        "asyncio_tasks": tasks,
+        "threads": threading.active_count(),
+        "memory": {
+            "rss_bytes": _rss_bytes(),
+            # ru_maxrss is KiB on Linux, bytes on macOS.
+            "max_rss_bytes": max_rss if sys.platform == "darwin" else max_rss * 1024,
+            "tracemalloc_current_bytes": current,
+            "tracemalloc_peak_bytes": peak,
+        },
+        "gc": {
+            "enabled": gc.isenabled(),
+            "counts": gc.get_count(),
+            "thresholds": gc.get_threshold(),
+            "collections": gc_timer.collections,
+            "pause_seconds": [round(s, 4) for s in gc_timer.pause_seconds],
+            "generations": gc.get_stats(),
+        },
+        "jobs": job_states,
+    }
+
+
+def stack_dump() -> str:
+    """Stacks of every asyncio task (on the running loop) and every thread."""
+    out = io.StringIO()
+    try:
+        tasks = sorted(asyncio.all_tasks(), key=lambda t: t.get_name())
+    except RuntimeError:
+        tasks = []
+    out.write(f"{len(tasks)} asyncio tasks\n\n")
+    for task in tasks:
+        task.print_stack(file=out)
+        out.write("\n")
+    frames = sys._current_frames()
+    out.write(f"{len(frames)} threads\n\n")
+    names = {t.ident: t.name for t in threading.enumerate()}
+    for ident, frame in frames.items():
+        out.write(f"Thread {names.get(ident, '?')} ({ident}):\n")
+        out.write("".join(traceback.format_stack(frame)))
+        out.write("\n")
+    return out.getvalue()
+
+
+def build_diagnostics_app(jobs: Callable[[], list[dict]] = list) -> FastAPI:
+    """The internal diagnostics app.
+
+    Args:
+        jobs: returns the scheduled jobs' states (SchedulerControl.list_jobs)
+    """
+    app = FastAPI(title="CS-Server diagnostics", docs_url=None, redoc_url=None, openapi_url=None)
+
+    @app.get("/debug/vars")
+    async def debug_vars():
+        return runtime_vars(jobs)
+
+    @app.get("/debug/pprof/profile")
+    async def profile(
+        seconds: float = Query(10, gt=0, le=MAX_PROFILE_SECONDS),
+        sort: str = Query("cumulative"),
+        limit: int = Query(50, ge=1, le=1000),
+        format: str = Query("text", pattern="^(text|pstats)$"),
+    ):
+        """cProfile the event loop thread for `seconds`."""
+        if sort not in PROFILE_SORT_KEYS:
+            raise HTTPException(400, f"sort must be one of {', '.join(PROFILE_SORT_KEYS)}")
+        if not _profile_lock.acquire(blocking=False):
+            raise HTTPException(409, "a profile is already running")
+        try:
+            profiler = cProfile.Profile()
+            try:
+                profiler.enable()
+            except ValueError as e:  # another profiler/tracer owns the hook
+                raise HTTPException(409, str(e))
+            try:
+                await asyncio.sleep(seconds)
+            finally:
+                profiler.disable()
+        finally:
+            _profile_lock.release()
+        logger.info(f"[Diagnostics] Profiled the event loop for {seconds:g}s")
+        if format == "pstats":
+            profiler.create_stats()
+            return Response(
+                content=marshal.dumps(profiler.stats),
+                media_type="application/octet-stream",
+                headers={"Content-Disposition": 'attachment; filename="cs-server.pstats"'},
+            )
+        out = io.StringIO()
+        pstats.Stats(profiler, stream=out).sort_stats(sort).print_stats(limit)
+        return PlainTextResponse(out.getvalue())
+
+    @app.get("/debug/pprof/tasks", response_class=PlainTextResponse)
+    async def tasks():
+        return PlainTextResponse(stack_dump())
+
+    @app.get("/debug/pprof/heap", response_class=PlainTextResponse)
+    async def heap(limit: int = Query(30, ge=1, le=500)):
+        """Top allocation sites by size (tracemalloc)."""
+        if not tracemalloc.is_tracing():
+            raise HTTPException(
+                409, "tracemalloc is off; set diagnostics_tracemalloc_frames > 0"
+            )
+        snapshot = tracemalloc.take_snapshot()
+        stats = snapshot.statistics("lineno")
+        current, peak = tracemalloc.get_traced_memory()
+        lines = [f"traced: {current} bytes (peak {peak}); top {limit} of {len(stats)} sites"]
+        lines += [str(stat) for stat in stats[:limit]]
+        return PlainTextResponse("\n".join(lines) + "\n")
+
+    return app
+
+
+class _EmbeddedServer:
+    """A uvicorn server that runs next to the main one: signal handling stays
+    with the main server."""
+
+    def __init__(self, app, host: str, port: int) -> None:
+        import uvicorn
+
+        from app.http_server import NoSignalsServer
+
+        self.server = NoSignalsServer(uvicorn.Config(app, host=host, port=port, log_level="warning"))
+        self.task: Optional[asyncio.Task] = None
+
+    def start(self) -> None:
+        self.task = asyncio.create_task(self.server.serve(), name="diagnostics-server")
+
+    async def stop(self) -> None:
+        if self.task is None:
+            return
+        self.server.should_exit = True
+        with contextlib.suppress(Exception):
+            await asyncio.wait_for(self.task, timeout=5)
+        self.task = None
+
+
+class DiagnosticsServer:
+    """Owns the diagnostics port, GC timing and tracemalloc for the process."""
+
+    def __init__(
+        self,
+        host: str,
+        port: int,
+        jobs: Callable[[], list[dict]] = list,
+        tracemalloc_frames: int = 0,
+    ) -> None:
+        """
+        Args:
+            host: interface to bind (keep it internal, e.g. 127.0.0.1)
+            port: diagnostics port
+            jobs: returns the scheduled jobs' states
+            tracemalloc_frames: frames kept per allocation; 0 leaves tracemalloc off
+        """
+        self.host = host
+        self.port = port
+        self.tracemalloc_frames = tracemalloc_frames
+        self._server = _EmbeddedServer(build_diagnostics_app(jobs), host, port)
+
+    def start(self) -> None:
+        gc_timer.install()
+        if self.tracemalloc_frames > 0 and not tracemalloc.is_tracing():
+            tracemalloc.start(self.tracemalloc_frames)
+        self._server.start()
+        logger.info(f"[Diagnostics] Serving /debug/vars and /debug/pprof on {self.host}:{self.port}")
+
+    async def stop(self) -> None:
+        await self._server.stop()
+        gc_timer.uninstall()
+        if self.tracemalloc_frames > 0 and tracemalloc.is_tracing():
+            tracemalloc.stop()
@@ -0,0 +1,2 @@
+"""gRPC VenueService (venue_service.proto), served next to HTTP when
+settings.grpc_enabled. The *_pb2*.py modules are generated by `make proto`."""
@@ -0,0 +1,283 @@
+"""gRPC VenueService on top of the HTTP API's VenueHandler.
+
+The servicer converts the handler's models to the proto messages; nearby
+results, venue lookups and live forecasts are therefore exactly what the
+HTTP routes return. StreamLiveUpdates follows the DAO's change events
+(app/dao/change_events.py) on Redis pub/sub, through an asyncio client so an
+open stream holds no thread, and reads each changed forecast back through the
+handler.
+
+Every call needs a key from settings.grpc_api_keys in the `x-api-key`
+metadata entry; anything else is refused with UNAUTHENTICATED.
+
+Runs on its own port (grpc_host:grpc_port) inside the server's event loop;
+with server_tls_certfile/keyfile set it uses the same certificate as HTTPS.
+"""
+from __future__ import annotations
+
+import asyncio
+import hmac
+import logging
+from datetime import datetime, timezone
+from pathlib import Path
+from typing import Optional
+
+import grpc
+from google.protobuf.timestamp_pb2 import Timestamp
+
+from app.dao.change_events import (
+    LIVE_FORECAST_DELETED,
+    LIVE_FORECAST_SET,
+    VENUE_CHANGES_CHANNEL,
+    VenueChangeEvent,
+)
+from app.db.geo_redis_client import RADIUS_UNITS_KM
+from app.grpc_api import venue_service_pb2 as pb
+from app.grpc_api import venue_service_pb2_grpc as pb_grpc
+from app.models import LiveForecastResponse, Venue, VenueWithLive
+
+logger = logging.getLogger(__name__)
+
+API_KEY_METADATA = "x-api-key"
+
+
+def _timestamp(value: datetime) -> Timestamp:
+    if value.tzinfo is None:
+        value = value.replace(tzinfo=timezone.utc)
+    ts = Timestamp()
+    ts.FromDatetime(value.astimezone(timezone.utc).replace(tzinfo=None))
+    return ts
+
+
+def venue_message(venue: Venue) -> pb.Venue:
+    optional = {
+        "price_level": venue.price_level,
+        "rating": venue.rating,
+        "reviews": venue.reviews,
+    }
+    return pb.Venue(
+        venue_id=venue.venue_id,
+        name=venue.venue_name,
+        address=venue.venue_address,
+        lat=venue.venue_lat,
+        lng=venue.venue_lng,
+        venue_type=venue.venue_type or "",
+        **{k: v for k, v in optional.items() if v is not None},
+    )
+
+
+def live_message(forecast: LiveForecastResponse, source: Optional[str]) -> pb.LiveForecast:
+    analysis = forecast.analysis
+    message = pb.LiveForecast(
+        venue_id=forecast.venue_info.venue_id,
+        live_busyness=analysis.venue_live_busyness,
+        live_busyness_available=analysis.venue_live_busyness_available,
+        forecasted_busyness=analysis.venue_forecasted_busyness,
+        forecast_busyness_available=analysis.venue_forecast_busyness_available,
+        live_forecasted_delta=analysis.venue_live_forecasted_delta,
+        venue_timezone=forecast.venue_info.venue_timezone,
+        source=source or "",
+    )
+    if forecast.refreshed_at is not None:
+        message.refreshed_at.CopyFrom(_timestamp(forecast.refreshed_at))
+    return message
+
+
+def nearby_message(item: VenueWithLive) -> pb.NearbyVenue:
+    message = pb.NearbyVenue(
+        venue=venue_message(item.venue),
+        weekly_forecast=getattr(item.weekly_forecast, "day_raw", None) or [],
+        stale=bool(item.stale),
+    )
+    if item.live_forecast is not None:
+        message.live.CopyFrom(live_message(item.live_forecast, item.live_source))
+    if item.data_age_seconds is not None:
+        message.data_age_seconds = item.data_age_seconds
+    return message
+
+
+class ApiKeyInterceptor(grpc.aio.ServerInterceptor):
+    """Refuses calls without a known `x-api-key` (settings.grpc_api_keys)."""
+
+    def __init__(self, api_keys: dict[str, str]):
+        """
+        Args:
+            api_keys: key -> client id
+        """
+        self.api_keys = api_keys
+
+    def client_for(self, api_key: Optional[str]) -> Optional[str]:
+        if api_key:
+            for known_key, client_id in self.api_keys.items():
+                if hmac.compare_digest(known_key.encode(), api_key.encode()):
+                    return client_id
+        return None
+
+    async def intercept_service(self, continuation, handler_call_details):
+        handler = await continuation(handler_call_details)
+        api_key = next(
+            (value for key, value in handler_call_details.invocation_metadata or ()
+             if key == API_KEY_METADATA),
+            None,
+        )
+        if handler is None or self.client_for(api_key) is not None:
+            return handler
+        return _refusing(handler)
+
+
+def _refusing(handler):
+    """A handler of the same shape that aborts with UNAUTHENTICATED."""
+    message = f"missing or unknown {API_KEY_METADATA}"
+
+    async def refuse(request, context):
+        await context.abort(grpc.StatusCode.UNAUTHENTICATED, message)
+
+    async def refuse_stream(request, context):
+        await context.abort(grpc.StatusCode.UNAUTHENTICATED, message)
+        yield  # never reached; makes this a response stream
+
+    if handler.unary_stream:
+        return grpc.unary_stream_rpc_method_handler(
+            refuse_stream,
+            request_deserializer=handler.request_deserializer,
+            response_serializer=handler.response_serializer,
+        )
+    return grpc.unary_unary_rpc_method_handler(
+        refuse,
+        request_deserializer=handler.request_deserializer,
+        response_serializer=handler.response_serializer,
+    )
+
+
+class VenueServicer(pb_grpc.VenueServiceServicer):
+    """VenueService backed by a VenueHandler and the Redis change feed."""
+
+    def __init__(self, handler, redis_client):
+        """
+        Args:
+            handler: the VenueHandler the HTTP routes use
+            redis_client: asyncio Redis client (decode_responses=True) for the
+                change-event subscription
+        """
+        self.handler = handler
+        self.redis = redis_client
+
+    async def GetVenuesNearby(self, request, context):
+        unit = request.unit or "km"
+        if not -90 <= request.lat <= 90 or not -180 <= request.lng <= 180:
+            await context.abort(grpc.StatusCode.INVALID_ARGUMENT, "lat/lng out of range")
+        if request.radius <= 0:
+            await context.abort(grpc.StatusCode.INVALID_ARGUMENT, "radius must be positive")
+        if unit not in RADIUS_UNITS_KM:
+            await context.abort(
+                grpc.StatusCode.INVALID_ARGUMENT,
+                f"unit must be one of {', '.join(sorted(RADIUS_UNITS_KM))}",
+            )
+        venues = await self._call(
+            context, self.handler.get_venues_nearby,
+            request.lat, request.lng, request.radius, verbose=True, unit=unit,
+        )
+        return pb.GetVenuesNearbyResponse(venues=[nearby_message(v) for v in venues])
+
+    async def GetVenue(self, request, context):
+        venue = await self._call(context, self.handler.get_venue, request.venue_id)
+        if venue is None:
+            await context.abort(grpc.StatusCode.NOT_FOUND, "venue not found")
+        return venue_message(venue)
+
+    async def GetLiveForecast(self, request, context):
+        forecast, source = await self._call(context, self.handler.get_live_forecast, request.venue_id)
+        if forecast is None:
+            await context.abort(grpc.StatusCode.NOT_FOUND, "no live forecast for this venue")
+        return live_message(forecast, source)
+
+    async def StreamLiveUpdates(self, request, context):
+        wanted = set(request.venue_ids)
+        pubsub = self.redis.pubsub(ignore_subscribe_messages=True)
+        await pubsub.subscribe(VENUE_CHANGES_CHANNEL)
+        try:
+            async for message in pubsub.listen():
+                try:
+                    event = VenueChangeEvent.from_json(message["data"])
+                except ValueError:
+                    continue
+                if event.change_type not in (LIVE_FORECAST_SET, LIVE_FORECAST_DELETED):
+                    continue
+                if wanted and event.venue_id not in wanted:
+                    continue
+                update = pb.LiveUpdate(
+                    venue_id=event.venue_id,
+                    change_type=event.change_type,
+                    at=_timestamp(datetime.fromtimestamp(event.at, timezone.utc)),
+                )
+                if event.change_type == LIVE_FORECAST_SET:
+                    forecast, source = await self._call(
+                        context, self.handler.get_live_forecast, event.venue_id
+                    )
+                    if forecast is not None:
+                        update.live.CopyFrom(live_message(forecast, source))
+                yield update
+        finally:
+            await pubsub.aclose()
+
+    async def _call(self, context, fn, *args, **kwargs):
+        """Run a blocking handler call off the loop; failures are INTERNAL."""
+        try:
+            return await asyncio.to_thread(fn, *args, **kwargs)
+        except Exception as e:
+            logger.error(f"[GrpcServer] {fn.__name__} failed: {e}")
+        await context.abort(grpc.StatusCode.INTERNAL, "internal error")
+
+
+class GrpcServer:
+    """The gRPC listener: start() binds and serves, stop() drains."""
+
+    def __init__(
+        self,
+        handler,
+        redis_client,
+        host: str,
+        port: int,
+        api_keys: dict[str, str],
+        tls_certfile: str = "",
+        tls_keyfile: str = "",
+    ):
+        """
+        Args:
+            handler: the VenueHandler the HTTP routes use
+            redis_client: asyncio Redis client for StreamLiveUpdates; closed
+                by stop()
+            host: interface to bind
+            port: port to bind; 0 = ephemeral (see .port)
+            api_keys: x-api-key -> client id; calls without one are refused
+            tls_certfile: PEM certificate; with tls_keyfile, serve TLS
+
+        Raises:
+            RuntimeError: the address could not be bound
+        """
+        self._server = grpc.aio.server(interceptors=[ApiKeyInterceptor(api_keys)])
+        pb_grpc.add_VenueServiceServicer_to_server(VenueServicer(handler, redis_client), self._server)
+        self._redis = redis_client
+        address = f"{host}:{port}"
+        if tls_certfile:
+            credentials = grpc.ssl_server_credentials([(
+                Path(tls_keyfile).read_bytes(),
+                Path(tls_certfile).read_bytes(),
+            )])
+            self.port = self._server.add_secure_port(address, credentials)
+        else:
+            self.port = self._server.add_insecure_port(address)
+        if not self.port:
+            raise RuntimeError(f"gRPC server could not bind {address}")
+        self.host = host
+
+    async def start(self) -> None:
+        await self._server.start()
+        logger.info(f"[GrpcServer] Serving VenueService on {self.host}:{self.port}")
+
+    async def stop(self, grace: float = 5.0) -> None:
+        """Stop accepting calls; in-flight ones get `grace` seconds (streams
+        are cancelled)."""
+        await self._server.stop(grace)
+        await self._redis.aclose()
+        logger.info("[GrpcServer] Stopped")
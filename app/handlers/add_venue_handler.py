@@ -21,19 +21,26 @@ from pydantic import BaseModel, ConfigDict, Field
 from app.api.besttime_client import (
     BestTimeInvalidResponseError,
     BestTimeRateLimitedError,
+    pinned_key_pair,
 )
-from app.dao.redis_venue_dao import RedisVenueDAO
+from app.dao.rds_venue_store import REACTIVATING_SOURCES
+from app.dao.venue_dao import VenueDAO
 from app.dao.venue_row import venue_from_row
 from app.metrics import (
     ADD_VENUE_BY_ADDRESS_TOTAL,
     VENUE_MONTHLY_NEW_COUNT,
 )
 from app.models import (
+    NewVenueResponse,
     Venue,
     VenueFilterParams,
+    venue_from_filter,
+    venue_from_inventory,
+    venue_from_new_venue,
 )
 from app.services.price_signal import derive_price_signal
 from app.services.venue_budget_service import VenueBudgetService
+from app.services.venue_validation import VenueRejectedError
 
 logger = logging.getLogger(__name__)
 
@@ -109,7 +116,7 @@ def _address_hash(venue_name: str, venue_address: str) -> str:
 class AddVenueHandler:
     def __init__(
         self,
-        venue_dao: RedisVenueDAO,
+        venue_dao: VenueDAO,
         besttime_api,
         budget_service: VenueBudgetService,
         redis_client,
@@ -174,7 +181,19 @@ class AddVenueHandler:
             hit = self._cached_hit_outcome(request)
             if hit is not None:
                 return hit
-            return await self._reserve_create_persist(request, radius_m)
+            # The new venue lives in the account the create ran on: its timeout
+            # recovery and geo fallback must read that same account.
+            with pinned_key_pair(self.besttime):
+                return await self._reserve_create_persist(request, radius_m)
+        except VenueRejectedError as e:
+            # BestTime created (and charged) the venue, but what it sent back
+            # fails validation: it is quarantined, not written.
+            ADD_VENUE_BY_ADDRESS_TOTAL.labels(result="rejected_by_validation").inc()
+            logger.warning(f"[AddVenueHandler] {e}")
+            return AddVenueOutcome(
+                status_code=502,
+                body={"detail": f"BestTime returned an invalid venue: {e}"},
+            )
         finally:
             self._release_add_lock(lock_key)
 
@@ -184,10 +203,11 @@ class AddVenueHandler:
         """The address-hash short-circuit shared by add()'s pre-lock step 1 and
         its post-lock re-check. Returns an already_exists outcome for a cached
         ACTIVE venue (or one deprecated for any reason OTHER than a geo-link
-        undo — falling through there would spend a create on a venue
-        _preserve_deprecation keeps hidden anyway; only admin_geo_link_undo
-        falls through to the BestTime path, which reactivates it). None when
-        there is no usable cached hit and the caller must proceed to create."""
+        undo or a stale eviction — falling through there would spend a create
+        on a venue _preserve_deprecation keeps hidden anyway; only those
+        REACTIVATING_SOURCES fall through to the BestTime path, which
+        reactivates the venue). None when there is no usable cached hit and
+        the caller must proceed to create."""
         existing_id = self._lookup_cached_venue_id(
             request.venue_name, request.venue_address
         )
@@ -196,7 +216,7 @@ class AddVenueHandler:
         persisted = self.venue_dao.get_venue(existing_id)
         if persisted is not None and (
             persisted.is_active()
-            or persisted.deprecated_source != GEO_LINK_UNDO_SOURCE
+            or persisted.deprecated_source not in REACTIVATING_SOURCES
         ):
             ADD_VENUE_BY_ADDRESS_TOTAL.labels(result="already_exists").inc()
             return AddVenueOutcome(
@@ -418,10 +438,9 @@ class AddVenueHandler:
             f"({match.venue_name!r}) found in the account inventory after a "
             f"{elapsed_seconds:.1f}s create timeout; completing the add"
         )
-        venue = Venue(
-            processed=True,
+        venue = venue_from_inventory(
+            match,
             forecast=True,
-            venue_id=match.venue_id,
             venue_name=match.venue_name or request.venue_name,
             venue_address=match.venue_address or request.venue_address,
             venue_lat=float(
@@ -582,7 +601,7 @@ class AddVenueHandler:
     ) -> Optional[Venue]:
         """Check the Redis geo index for a name-matching venue within radius."""
         try:
-            nearby = self.venue_dao.get_nearby_venues(lat, lng, radius_m / 1000.0)
+            nearby = self.venue_dao.get_nearby_venues(lat, lng, radius_m, unit="m")
         except Exception as e:
             logger.warning(f"[AddVenueHandler] geo lookup failed: {e}")
             return None
@@ -668,20 +687,8 @@ class AddVenueHandler:
             # undo after a month rollover still decrements the month that
             # was actually charged.
             link_year_month = self.budget.current_year_month()
-            venue = Venue(
-                processed=True,
-                forecast=True,
-                venue_id=match.venue_id,
-                venue_name=match.venue_name,
-                venue_address=match.venue_address,
-                venue_lat=match.venue_lat,
-                venue_lng=match.venue_lng,
-                venue_type=match.venue_type,
-                rating=match.rating,
-                reviews=match.reviews,
-                besttime_price_level=match.price_level,
-                geo_linked=True,
-                geo_linked_year_month=link_year_month,
+            venue = venue_from_filter(
+                match, geo_linked=True, geo_linked_year_month=link_year_month
             )
             await self._derive_and_set_price(venue, request.place_id)
             self.venue_dao.upsert_venue(venue)
@@ -844,7 +851,7 @@ class AddVenueHandler:
             "venue_lng": venue.venue_lng,
         }
 
-    async def _persist_new_venue(self, response, place_id: Optional[str]) -> Venue:
+    async def _persist_new_venue(self, response: NewVenueResponse, place_id: Optional[str]) -> Venue:
         """Build a Venue from a BestTime POST /forecasts response, derive its served
         price tier, and upsert it.
 
@@ -854,26 +861,7 @@ class AddVenueHandler:
         (``place_id=None`` — no Google call). Without an enrichment service (the
         legacy path), we keep the original behavior and re-source the Google price
         here from ``place_id``."""
-        info = response.venue_info if hasattr(response, "venue_info") else None
-        if info is None and isinstance(response, dict):
-            info = response.get("venue_info") or {}
-        venue_id = _field(info, "venue_id")
-        venue_lat = _field(info, "venue_lat") or 0.0
-        venue_lng = _field(info, "venue_lng")
-        if venue_lng is None:
-            venue_lng = _field(info, "venue_lon") or 0.0
-        venue = Venue(
-            processed=True,
-            forecast=True,
-            venue_id=venue_id,
-            venue_name=_field(info, "venue_name") or "",
-            venue_address=_field(info, "venue_address") or "",
-            venue_lat=float(venue_lat or 0.0),
-            venue_lng=float(venue_lng or 0.0),
-            rating=_field(info, "rating"),
-            reviews=_field(info, "reviews"),
-            besttime_price_level=_field(info, "price_level"),
-        )
+        venue = venue_from_new_venue(response)
         # When inline enrichment is wired, it owns the single Google Details fetch;
         # set only the BestTime baseline here (place_id=None -> no Google call) to
         # avoid a doubled paid call. Otherwise (legacy path) re-source Google price
@@ -4,11 +4,25 @@ from datetime import datetime, timedelta
 from typing import Optional
 
 import pytz
+import redis
 
 from app.config import settings
-from app.dao import RedisVenueDAO
+from app.dao import VenueDAO
+from app.latency_budget import StageTimer
+from app.db.geo_redis_client import radius_to_km
 from app.models.venue_category import resolve_venue_display
 from app.services.photo_category import TYPE_TO_CATEGORY
+from app.services.checkins import CHECKIN_SOURCE
+from app.services.partner_occupancy_service import PARTNER_SOURCE
+from app.services.holiday_calendar import holiday_on
+from app.services.venue_closures import load_closed_venue_ids
+from app.services.venue_notes import load_public_status_notes
+from app.services.venue_eligibility import haversine_km
+from app.services.venue_occupancy import estimate_occupancy
+from app.services.venue_open_hours import open_now_bulk
+from app.services.venue_score import score_venue
+from app.utils.venue_time import forecast_slot, slot_for_local_hour, venue_local_time, venue_tz
+from app.tracing import traced
 
 # BestTime day_int → Portuguese weekday name (BestTime: 0=Mon, 6=Sun)
 _BESTTIME_DAY_NAMES = [
@@ -21,15 +35,19 @@ _BESTTIME_DAY_NAMES = [
     "domingo",
 ]
 from app.models import (
+    FootTrafficForecast,
     Venue,
     VenueWithLive,
+    ForecastAtTime,
     MinifiedVenue,
     LiveForecastResponse,
+    VenueMedia,
     WeekRawDay,
 )
 from app.metrics import (
     VENUE_SERVE_LIVE_BUSYNESS_TOTAL,
     VENUE_SERVE_LIVE_FORECAST_AGE_MINUTES,
+    NEARBY_SNAPSHOT_FAILOVER_TOTAL,
 )
 from app.services.live_freshness import (
     classify_live_freshness,
@@ -41,21 +59,130 @@ from app.services.live_freshness import (
 
 logger = logging.getLogger(__name__)
 
+# settings.nearby_foot_traffic_forecast values (see app/config.py).
+NEARBY_FORECAST_POLICIES = ("full", "current_day", "omit", "link")
+FORECAST_URL_TEMPLATE = "/v1/venues/{venue_id}/forecast"
+# GET /v1/venues/nearby `sort` values: live busyness first (the default), or
+# the app/services/venue_score.py score.
+NEARBY_SORTS = ("busyness", "score")
+
+
+def _data_age_seconds(m: VenueWithLive, now_utc: datetime) -> Optional[int]:
+    """Seconds since the newest refreshed_at of the venue document and its
+    live forecast, or None when neither has one."""
+    stamps = [m.venue.refreshed_at]
+    if m.live_forecast is not None:
+        stamps.append(getattr(m.live_forecast, "refreshed_at", None))
+    stamps = [t for t in stamps if t is not None]
+    if not stamps:
+        return None
+    return max(0, int((now_utc - max(stamps)).total_seconds()))
+
+
+def _besttime_special_day(raw_day: Optional[WeekRawDay]) -> Optional[str]:
+    """BestTime's special_day text for a forecast day, when it gives one."""
+    v2 = raw_day.day_info.venue_open_close_v2 if raw_day and raw_day.day_info else None
+    special = v2.special_day if v2 is not None else None
+    return special if isinstance(special, str) and special else None
+
+
+def _venue_timezone(m: VenueWithLive) -> Optional[str]:
+    """BestTime's venue_timezone from the live forecast, when there is one."""
+    venue_info = getattr(m.live_forecast, "venue_info", None)
+    return getattr(venue_info, "venue_timezone", None) or None
+
+
+def _fresh_live_busyness(
+    m: VenueWithLive, now_utc: datetime, max_age: timedelta
+) -> Optional[int]:
+    """The venue's live busyness when available and fresh (the gate
+    venue_live_busyness is served under, without its metrics)."""
+    lf = m.live_forecast
+    if (
+        lf is not None
+        and lf.analysis.venue_live_busyness_available
+        and classify_live_freshness(lf, now_utc, max_age)[0] == FRESH
+    ):
+        return lf.analysis.venue_live_busyness
+    return None
+
+
+def forecast_url_enabled() -> bool:
+    """Whether the current settings can put a forecast_url on nearby venues."""
+    return (
+        settings.nearby_foot_traffic_forecast == "link"
+        or settings.nearby_forecast_max_venues > 0
+    )
+
+
+def nearby_response_exclude(sort: str = "busyness") -> set[str]:
+    """Fields stripped from nearby items under the current settings and the
+    request's `sort`.
+
+    Flag off: the handler never attaches weekly_forecast_prev (stays at its
+    model default of None), but a declared Optional field still serializes as
+    an explicit `null` by default. Stripping the key entirely keeps the
+    response byte-for-byte identical to the pre-flag shape (rollback path)
+    rather than merely null-valued. forecast_url, stale, special_day, trend,
+    open_now, tz, local_time and estimated_occupancy get the same treatment
+    while nothing can set them, the minified media while
+    nearby_media_enabled is off, and score unless the request sorts by it.
+    """
+    exclude = set()
+    if not settings.weekly_forecast_prev_day_enabled:
+        exclude.add("weekly_forecast_prev")
+    if not forecast_url_enabled():
+        exclude.add("forecast_url")
+    if not settings.nearby_snapshot_enabled:
+        exclude.add("stale")
+    if not settings.holiday_calendar_enabled:
+        exclude.add("special_day")
+    if not settings.busyness_trend_enabled:
+        exclude.add("trend")
+    if not settings.nearby_open_now_enabled:
+        exclude.add("open_now")
+    if not settings.nearby_local_time_enabled:
+        exclude.update({"tz", "local_time"})
+    if not settings.nearby_occupancy_enabled:
+        exclude.add("estimated_occupancy")
+    if not settings.nearby_media_enabled:
+        exclude.add("media")
+    if sort != "score":
+        exclude.add("score")
+    return exclude
+
 
 class VenueHandler:
     """Handler for venue-related HTTP requests."""
 
-    def __init__(self, venue_dao: RedisVenueDAO, admin_config_service=None):
+    def __init__(
+        self,
+        venue_dao: VenueDAO,
+        admin_config_service=None,
+        snapshot=None,
+        trend_service=None,
+        checkin_service=None,
+    ):
         """Initialize venue handler.
 
         Args:
-            venue_dao: Redis DAO for venue data access
+            venue_dao: venue DAO for data access (the serving RedisVenueDAO in
+                production)
             admin_config_service: optional admin-config reader used to resolve the
                 live-busyness freshness window at serve time; falls back to the
                 settings default when absent.
+            snapshot: optional SnapshotVenueDAO answering nearby requests
+                (flagged stale) when venue_dao raises a RedisError.
+            trend_service: optional TrendService attaching `trend` to venues
+                with live busyness (today's forecast only).
+            checkin_service: optional CheckinService whose recent check-ins
+                stand in for live busyness where BestTime has none.
         """
         self.venue_dao = venue_dao
         self.admin_config_service = admin_config_service
+        self.snapshot = snapshot
+        self.trend_service = trend_service
+        self.checkin_service = checkin_service
 
     def _derive_hours_from_forecast_bulk(
         self, venue_id: str, weekly_by_day: dict[int, Optional[WeekRawDay]]
@@ -104,6 +231,7 @@ class VenueHandler:
 
         return descriptions if any_data else None
 
+    @traced("VenueHandler.get_venues_nearby")
     def get_venues_nearby(
         self,
         lat: float,
@@ -111,6 +239,10 @@ class VenueHandler:
         radius: float,
         verbose: bool = False,
         target_day_offset: Optional[int] = None,
+        unit: str = "km",
+        timer: Optional[StageTimer] = None,
+        open_now: bool = False,
+        sort: str = "busyness",
     ) -> list[VenueWithLive] | list[MinifiedVenue]:
         """Get venues near a location with live and weekly forecasts.
 
@@ -125,46 +257,129 @@ class VenueHandler:
         Args:
             lat: Latitude
             lon: Longitude
-            radius: Radius in kilometers
+            radius: Radius, in `unit`
             verbose: If True, return full VenueWithLive; if False, return MinifiedVenue
             target_day_offset: Days forward from today (0=today) selecting which
                 weekly-forecast day to attach. Interpreted modulo 7 (the forecast
                 is weekly-periodic). None or 0 keeps today's forecast.
+            unit: Radius unit — "m", "km" (default), "mi" or "ft"
+            timer: records the geo_query / live_fetch / transform stages
+                (app/latency_budget.py); None = not timed
+            open_now: only venues known to be open right now
+            sort: "busyness" (live busyness first) or "score" (descending
+                app/services/venue_score.py score, stamped on each venue)
 
         Returns:
             List of VenueWithLive (verbose=True) or MinifiedVenue (verbose=False)
+
+        Raises:
+            ValueError: unsupported unit or sort, or a negative radius
         """
+        if sort not in NEARBY_SORTS:
+            raise ValueError(f"unsupported sort {sort!r}")
         logger.info(
             f"[VenueHandler] GetVenuesNearby: lat={lat:.6f}, lon={lon:.6f}, "
-            f"radius={radius:.2f}km, verbose={verbose}"
+            f"radius={radius:.2f}{unit}, verbose={verbose}"
         )
+        timer = timer or StageTimer()
 
         # 1. Load nearby venues. Eligibility is no longer applied here: the Redis
         # serving set is pre-filtered to the eligibility view (active AND eligible)
         # by the projector, so serving never re-evaluates the block-list. The
         # is_active() guard is a cheap defensive lifecycle check (deprecated venues
         # are already reconciled out of Redis).
-        venues = self._load_nearby(lat, lon, radius)
-        total = len(venues)
-        venues = [v for v in venues if v.is_active()]
-        deprecated = total - len(venues)
-        if deprecated:
-            logger.info(f"[VenueHandler] Filtered out {deprecated} deprecated venues")
+        try:
+            with timer.stage("geo_query"):
+                venues = self._load_nearby(lat, lon, radius, unit)
+        except redis.RedisError as e:
+            if self.snapshot is None:
+                raise
+            return self._nearby_from_snapshot(
+                e, lat, lon, radius, verbose, target_day_offset, unit, timer, open_now, sort
+            )
+        with timer.stage("geo_query"):
+            total = len(venues)
+            venues = [v for v in venues if v.is_active()]
+            deprecated = total - len(venues)
+            if deprecated:
+                logger.info(f"[VenueHandler] Filtered out {deprecated} deprecated venues")
+            closed = load_closed_venue_ids(self.admin_config_service)
+            if closed:
+                before = len(venues)
+                venues = [v for v in venues if v.venue_id not in closed]
+                if len(venues) != before:
+                    logger.info(
+                        f"[VenueHandler] Filtered out {before - len(venues)} temporarily closed venues"
+                    )
         logger.info(f"[VenueHandler] Found {len(venues)} nearby venues")
 
         # 2. Merge with live and weekly forecasts
-        merged = self._merge(venues, target_day_offset=target_day_offset)
+        with timer.stage("live_fetch"):
+            merged = self._merge(venues, target_day_offset=target_day_offset)
+            if open_now or settings.nearby_open_now_enabled:
+                self._stamp_open_now(merged, utc_now())
+            if settings.nearby_local_time_enabled:
+                self._stamp_local_time(merged, utc_now())
+        if open_now:
+            merged = [m for m in merged if m.open_now is True]
 
         # 3. Transform based on verbose flag. Resolve the live-busyness freshness
         # window once per request (admin override or settings default) and stamp a
         # single "now" so every venue is judged against the same instant.
-        now_utc = utc_now()
-        max_age = timedelta(minutes=resolve_max_age_minutes(self.admin_config_service))
-        result = self._transform(merged, verbose, now_utc, max_age)
+        with timer.stage("transform"):
+            now_utc = utc_now()
+            max_age = timedelta(minutes=resolve_max_age_minutes(self.admin_config_service))
+            status_notes = load_public_status_notes(self.admin_config_service)
+            trends = {}
+            if self.trend_service is not None and not target_day_offset:
+                trends = self.trend_service.trends({
+                    m.venue.venue_id: m.live_forecast
+                    for m in merged if m.live_forecast is not None
+                })
+            for m in merged:
+                m.data_age_seconds = _data_age_seconds(m, now_utc)
+                m.status_note = status_notes.get(m.venue.venue_id)
+                m.trend = trends.get(m.venue.venue_id)
+            if settings.nearby_occupancy_enabled:
+                self._stamp_occupancy(merged, now_utc, max_age)
+            if sort == "score":
+                self._sort_by_score(merged, lat, lon, radius_to_km(radius, unit), now_utc, max_age)
+            result = self._transform(merged, verbose, now_utc, max_age)
 
         logger.info(f"[VenueHandler] Returning {len(result)} venues")
         return result
 
+    def _nearby_from_snapshot(
+        self,
+        error: redis.RedisError,
+        lat: float,
+        lon: float,
+        radius: float,
+        verbose: bool,
+        target_day_offset: Optional[int],
+        unit: str,
+        timer: Optional[StageTimer] = None,
+        open_now: bool = False,
+        sort: str = "busyness",
+    ) -> list[VenueWithLive] | list[MinifiedVenue]:
+        """Answer a nearby request from the last-known-good snapshot, every
+        venue flagged stale; re-raise `error` when there is no usable one."""
+        if not self.snapshot.usable():
+            NEARBY_SNAPSHOT_FAILOVER_TOTAL.labels(result="unavailable").inc()
+            raise error
+        logger.warning(
+            f"[VenueHandler] Redis error ({error}); serving nearby from the "
+            f"{self.snapshot.age_seconds():.0f}s old snapshot"
+        )
+        NEARBY_SNAPSHOT_FAILOVER_TOTAL.labels(result="served").inc()
+        result = VenueHandler(self.snapshot, self.admin_config_service).get_venues_nearby(
+            lat, lon, radius, verbose, target_day_offset=target_day_offset, unit=unit,
+            timer=timer, open_now=open_now, sort=sort,
+        )
+        for item in result:
+            item.stale = True
+        return result
+
     def ping(self) -> dict[str, str]:
         """Health check endpoint.
 
@@ -174,18 +389,22 @@ class VenueHandler:
         logger.debug("[VenueHandler] Ping")
         return {"status": "pong"}
 
-    def _load_nearby(self, lat: float, lon: float, radius: float) -> list[Venue]:
+    def _load_nearby(
+        self, lat: float, lon: float, radius: float, unit: str = "km"
+    ) -> list[Venue]:
         """Load nearby venues from geo index.
 
         Args:
             lat: Latitude
             lon: Longitude
-            radius: Radius in kilometers
+            radius: Radius, in `unit`
+            unit: Radius unit
 
         Returns:
             List of nearby venues
         """
-        return self.venue_dao.get_nearby_venues(lat, lon, radius)
+        # Normalize to the DAO's default unit (km) at the boundary.
+        return self.venue_dao.get_nearby_venues(lat, lon, radius_to_km(radius, unit))
 
     def _merge(
         self, venues: list[Venue], target_day_offset: Optional[int] = None
@@ -231,6 +450,7 @@ class VenueHandler:
         # following calendar morning). Cheap to compute unconditionally so the
         # log line always shows it regardless of the flag.
         prev_day_int = (besttime_day_int - 1) % 7
+        holiday = holiday_on(recife_time.date() + timedelta(days=target_day_offset or 0))
 
         logger.info(
             f"[VenueHandler] Current Recife time: {recife_time.strftime('%Y-%m-%d %H:%M:%S %Z')}, "
@@ -248,6 +468,31 @@ class VenueHandler:
         except Exception as e:
             logger.debug(f"[VenueHandler] Bulk live forecast fetch failed: {e}")
             live_map = {}
+        # Unexpired partner occupancy readings win over the cached (BestTime)
+        # live forecast. Skipped entirely when no partner venues are configured.
+        partner_ids: set[str] = set()
+        if settings.partner_venues:
+            try:
+                partner_map = self.venue_dao.get_partner_live_bulk(ids)
+            except Exception as e:
+                logger.debug(f"[VenueHandler] Bulk partner live fetch failed: {e}")
+                partner_map = {}
+            live_map = {**live_map, **partner_map}
+            partner_ids = set(partner_map)
+        # Recent check-ins stand in where there is no live busyness at all.
+        checkin_ids: set[str] = set()
+        if self.checkin_service is not None:
+            missing = [
+                vid for vid in ids
+                if vid not in live_map or not live_map[vid].analysis.venue_live_busyness_available
+            ]
+            try:
+                checkin_map = self.checkin_service.live_bulk(missing)
+            except Exception as e:
+                logger.debug(f"[VenueHandler] Bulk check-in read failed: {e}")
+                checkin_map = {}
+            live_map = {**live_map, **checkin_map}
+            checkin_ids = set(checkin_map)
         try:
             weekly_map = self.venue_dao.get_week_raw_forecasts_bulk(ids, besttime_day_int)
         except Exception as e:
@@ -278,6 +523,17 @@ class VenueHandler:
                     live_forecast=lf,
                     weekly_forecast=raw_day,
                     weekly_forecast_prev=raw_prev_day,
+                    live_source=(
+                        None if lf is None
+                        else PARTNER_SOURCE if v.venue_id in partner_ids
+                        else CHECKIN_SOURCE if v.venue_id in checkin_ids
+                        else "besttime"
+                    ),
+                    special_day=(
+                        holiday.name if holiday is not None
+                        else _besttime_special_day(raw_day)
+                        if settings.holiday_calendar_enabled else None
+                    ),
                 )
             )
 
@@ -291,8 +547,200 @@ class VenueHandler:
 
         out.sort(key=sort_key)
 
+        self._apply_forecast_policy(out, besttime_day_int)
         return out
 
+    def _stamp_open_now(self, merged: list[VenueWithLive], now_utc: datetime) -> None:
+        """Set each venue's open_now from its stored hours in its own timezone
+        (two bulk reads for today and yesterday in the usual single-timezone
+        case). A failed read leaves every venue unknown."""
+        if not merged:
+            return
+        timezones = {m.venue.venue_id: _venue_timezone(m) for m in merged}
+        try:
+            open_now = open_now_bulk(timezones, now_utc, self.venue_dao.get_week_raw_forecasts_bulk)
+        except Exception as e:
+            logger.debug(f"[VenueHandler] Open-now hours read failed: {e}")
+            return
+        for m in merged:
+            m.open_now = open_now.get(m.venue.venue_id)
+
+    def _stamp_local_time(self, merged: list[VenueWithLive], now_utc: datetime) -> None:
+        """Set each venue's tz and local_time (no reads: the timezone comes
+        from the live forecast already merged)."""
+        for m in merged:
+            tz_name = _venue_timezone(m)
+            m.tz = venue_tz(tz_name).zone
+            m.local_time = venue_local_time(tz_name, now_utc)
+
+    def _stamp_occupancy(
+        self, merged: list[VenueWithLive], now_utc: datetime, max_age: timedelta
+    ) -> None:
+        """Set each venue's estimated_occupancy from its live busyness, under
+        the same freshness gate as venue_live_busyness."""
+        for m in merged:
+            busyness = _fresh_live_busyness(m, now_utc, max_age)
+            if busyness is not None:
+                m.estimated_occupancy = estimate_occupancy(m.venue, busyness)
+
+    def _sort_by_score(
+        self,
+        merged: list[VenueWithLive],
+        lat: float,
+        lon: float,
+        radius_km: float,
+        now_utc: datetime,
+        max_age: timedelta,
+    ) -> None:
+        """Stamp each venue's score and order by it, highest first (ties keep
+        the busyness order)."""
+        for m in merged:
+            m.score = score_venue(
+                m.venue,
+                _fresh_live_busyness(m, now_utc, max_age),
+                haversine_km(lat, lon, m.venue.venue_lat, m.venue.venue_lng),
+                radius_km,
+            )
+        merged.sort(key=lambda m: -m.score)
+
+    def _apply_forecast_policy(
+        self, merged: list[VenueWithLive], day_int: int
+    ) -> None:
+        """Trim each venue's embedded foot-traffic week per
+        settings.nearby_foot_traffic_forecast / nearby_forecast_max_venues.
+
+        Venues are replaced by trimmed copies, never edited in place: the DAO
+        (or its read cache) may hand the same Venue object to other requests.
+        """
+        policy = settings.nearby_foot_traffic_forecast
+        if policy not in NEARBY_FORECAST_POLICIES:
+            logger.warning(
+                f"[VenueHandler] Unknown nearby_foot_traffic_forecast {policy!r}; using 'full'"
+            )
+            policy = "full"
+        limit = settings.nearby_forecast_max_venues
+        if policy == "full" and limit <= 0:
+            return
+        for i, m in enumerate(merged):
+            venue_policy = "link" if 0 < limit <= i else policy
+            week = m.venue.venue_foot_traffic_forecast
+            if venue_policy == "full":
+                continue
+            if venue_policy == "current_day":
+                trimmed = [f for f in week or [] if f.day_int == day_int] or None
+            else:
+                trimmed = None
+            if week is not None:
+                m.venue = m.venue.model_copy(update={"venue_foot_traffic_forecast": trimmed})
+            if venue_policy == "link":
+                m.forecast_url = FORECAST_URL_TEMPLATE.format(venue_id=m.venue.venue_id)
+
+    @traced("VenueHandler.get_venue")
+    def get_venue(self, venue_id: str) -> Optional[Venue]:
+        """One active venue document, or None when unknown or deprecated."""
+        venue = self.venue_dao.get_venue(venue_id)
+        if venue is None or not venue.is_active():
+            return None
+        return venue
+
+    @traced("VenueHandler.get_live_forecast")
+    def get_live_forecast(self, venue_id: str) -> tuple[Optional[LiveForecastResponse], Optional[str]]:
+        """The live forecast nearby responses would show for one venue and its
+        source: an unexpired partner reading wins over the cached BestTime one,
+        and recent check-ins stand in when neither has live busyness.
+
+        Returns:
+            (forecast, "partner" | "besttime" | "checkins"), or (None, None)
+            when there is none
+        """
+        if settings.partner_venues:
+            partner = self.venue_dao.get_partner_live(venue_id)
+            if partner is not None:
+                return partner, PARTNER_SOURCE
+        forecast = self.venue_dao.get_live_forecast(venue_id)
+        if self.checkin_service is not None and (
+            forecast is None or not forecast.analysis.venue_live_busyness_available
+        ):
+            checkins = self.checkin_service.live(venue_id)
+            if checkins is not None:
+                return checkins, CHECKIN_SOURCE
+        return forecast, ("besttime" if forecast is not None else None)
+
+    @traced("VenueHandler.get_venue_forecast")
+    def get_venue_forecast(self, venue_id: str) -> Optional[list[FootTrafficForecast]]:
+        """The full embedded foot-traffic week of one venue (the target of
+        forecast_url).
+
+        Returns:
+            The venue's forecast days ([] when it has none), or None when the
+            venue is unknown or deprecated
+        """
+        venue = self.venue_dao.get_venue(venue_id)
+        if venue is None or not venue.is_active():
+            return None
+        return venue.venue_foot_traffic_forecast or []
+
+    def get_venue_media(self, venue_id: str) -> Optional[VenueMedia]:
+        """A venue's photos, website and social links (Venue.media); None
+        when the venue is unknown, deprecated or has none."""
+        venue = self.venue_dao.get_venue(venue_id)
+        if venue is None or not venue.is_active():
+            return None
+        return venue.media
+
+    def get_forecast_at(
+        self,
+        venue_id: str,
+        at: Optional[datetime] = None,
+        day_int: Optional[int] = None,
+        hour: Optional[int] = None,
+    ) -> Optional[ForecastAtTime]:
+        """A venue's weekly-forecast busyness at an absolute moment `at`
+        (converted to the venue's timezone), or at clock `hour` of weekday
+        `day_int` in venue-local time.
+
+        Returns:
+            The forecast slot, or None when the venue is unknown or deprecated
+
+        Raises:
+            ValueError: neither `at` nor both `day_int` and `hour` given
+        """
+        if at is None and (day_int is None or hour is None):
+            raise ValueError("give `at`, or both `day_int` and `hour`")
+        venue = self.venue_dao.get_venue(venue_id)
+        if venue is None or not venue.is_active():
+            return None
+        try:
+            live = self.venue_dao.get_live_forecast(venue_id)
+        except Exception as e:
+            logger.debug(f"[VenueHandler] No live forecast for the timezone of {venue_id}: {e}")
+            live = None
+        tz_name = getattr(getattr(live, "venue_info", None), "venue_timezone", None) or None
+        local_time = None
+        if at is not None:
+            local_time = venue_local_time(tz_name, at)
+            slot_day, index = forecast_slot(local_time)
+        else:
+            slot_day, index = slot_for_local_hour(day_int, hour)
+
+        day_raw: Optional[list[int]] = None
+        stored = self.venue_dao.get_week_raw_forecast(venue_id, slot_day)
+        if stored is not None:
+            day_raw = stored.day_raw
+        else:
+            embedded = next(
+                (f for f in venue.venue_foot_traffic_forecast or [] if f.day_int == slot_day), None
+            )
+            day_raw = embedded.day_raw if embedded is not None else None
+        return ForecastAtTime(
+            venue_id=venue_id,
+            tz=venue_tz(tz_name).zone,
+            local_time=local_time,
+            day_int=slot_day,
+            hour_index=index,
+            busyness=day_raw[index] if day_raw and index < len(day_raw) else None,
+        )
+
     def _transform(
         self,
         merged: list[VenueWithLive],
@@ -550,7 +998,18 @@ class VenueHandler:
                     processed=m.venue.processed,
                     venue_address=m.venue.venue_address,
                     venue_foot_traffic_forecast=m.venue.venue_foot_traffic_forecast,
+                    forecast_url=m.forecast_url,
+                    data_age_seconds=m.data_age_seconds,
+                    status_note=m.status_note,
+                    special_day=m.special_day,
+                    trend=m.trend if live_busyness is not None else None,
+                    open_now=m.open_now,
+                    tz=m.tz,
+                    local_time=m.local_time,
+                    estimated_occupancy=m.estimated_occupancy,
+                    score=m.score,
                     venue_live_busyness=live_busyness,
+                    live_source=m.live_source if live_busyness is not None else None,
                     venue_lat=m.venue.venue_lat,
                     venue_lng=m.venue.venue_lng,
                     venue_name=m.venue.venue_name,
@@ -573,6 +1032,7 @@ class VenueHandler:
                     hours_source=hours_source,
                     instagram_handle=instagram_handle,
                     instagram_url=instagram_url,
+                    media=m.venue.media,
                     venue_reviews=venue_reviews,
                     vibe_profile=vibe_profile_data,
                     venue_menu=venue_menu,
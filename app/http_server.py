@@ -0,0 +1,180 @@
+"""The public HTTP listener (`python main.py serve`).
+
+Address, TLS, timeouts and the trusted proxies come from settings
+(server_host, server_port, server_tls_*, server_*_timeout_seconds,
+server_forwarded_allow_ips) instead of uvicorn flags, so the same
+config file / env drives the container, systemd and local runs. The request
+timeout is enforced by RequestTimeoutMiddleware (app/middleware.py), which
+main.py adds when it is set; uvicorn has no per-request deadline of its own.
+
+HttpServer runs the listener as a task on the caller's event loop with
+start() / stop(), leaving signals to the caller: serve() drives it for
+`python main.py serve`, and tests start the real app on an ephemeral port
+(port=0, then `server.port`).
+"""
+from __future__ import annotations
+
+import asyncio
+import contextlib
+import logging
+import signal
+from typing import Optional, Union
+
+import uvicorn
+
+from app.config import Settings
+
+logger = logging.getLogger(__name__)
+
+
+def server_config(
+    settings: Settings,
+    app: Union[str, object] = "main:app",
+    port: Optional[int] = None,
+) -> uvicorn.Config:
+    """uvicorn config for the public listener; `port` overrides server_port
+    (0 = an ephemeral port).
+
+    Raises:
+        ConfigError: the settings fail Settings.config_errors() (bad port,
+            half-configured TLS, missing cert files, negative timeouts)
+    """
+    settings.check()
+    tls = {}
+    if settings.server_tls_certfile:
+        tls = {
+            "ssl_certfile": settings.server_tls_certfile,
+            "ssl_keyfile": settings.server_tls_keyfile,
+        }
+    return uvicorn.Config(
+        app,
+        host=settings.server_host,
+        port=settings.server_port if port is None else port,
+        log_level=settings.log_level.lower(),
+        timeout_keep_alive=settings.server_idle_timeout_seconds,
+        timeout_graceful_shutdown=settings.server_shutdown_timeout_seconds or None,
+        proxy_headers=True,
+        forwarded_allow_ips=settings.server_forwarded_allow_ips,
+        **tls,
+    )
+
+
+class NoSignalsServer(uvicorn.Server):
+    """uvicorn without its signal handlers: the owner of the server decides
+    when to stop. Used by HttpServer and the diagnostics listener
+    (app/diagnostics.py)."""
+
+    @contextlib.contextmanager
+    def capture_signals(self):
+        yield
+
+    def install_signal_handlers(self) -> None:
+        pass
+
+
+class HttpServer:
+    """The public listener with a non-blocking start() and a graceful stop()."""
+
+    def __init__(
+        self,
+        settings: Settings,
+        app: Union[str, object] = "main:app",
+        port: Optional[int] = None,
+    ):
+        """
+        Args:
+            settings: address, TLS and timeouts (see server_config)
+            app: the ASGI app or its import string
+            port: overrides server_port; 0 binds an ephemeral port
+
+        Raises:
+            ConfigError: see server_config()
+        """
+        self.settings = settings
+        self._server = NoSignalsServer(server_config(settings, app, port=port))
+        self._task: Optional[asyncio.Task] = None
+
+    @property
+    def port(self) -> Optional[int]:
+        """The bound port (the real one when started with port 0)."""
+        for server in self._server.servers:
+            for sock in server.sockets:
+                return sock.getsockname()[1]
+        return None
+
+    @property
+    def url(self) -> str:
+        scheme = "https" if self.settings.server_tls_certfile else "http"
+        return f"{scheme}://{self._server.config.host}:{self.port}"
+
+    async def _serve(self) -> None:
+        try:
+            await self._server.serve()
+        except SystemExit:
+            # uvicorn exits the process when it cannot bind; keep it a failure
+            # of this task.
+            config = self._server.config
+            raise RuntimeError(f"HTTP server could not bind {config.host}:{config.port}") from None
+
+    async def start(self) -> None:
+        """Bind and run the app's startup (lifespan); returns once requests
+        are being accepted.
+
+        Raises:
+            RuntimeError: already started, the address could not be bound, or
+                the app's startup failed
+        """
+        if self._task is not None:
+            raise RuntimeError("HTTP server already started")
+        self._task = asyncio.create_task(self._serve(), name="http-server")
+        while not self._server.started:
+            if self._task.done():
+                task, self._task = self._task, None
+                error = task.exception()
+                if error is not None:
+                    raise error
+                raise RuntimeError("HTTP server stopped during startup (see the log)")
+            await asyncio.sleep(0.01)
+        logger.info(f"[HttpServer] Listening on {self.url}")
+
+    async def wait(self) -> None:
+        """Until the server stops on its own (or stop() is called)."""
+        if self._task is not None:
+            await asyncio.shield(self._task)
+
+    async def stop(self) -> None:
+        """Stop accepting connections, let in-flight requests finish (up to
+        server_shutdown_timeout_seconds) and run the app's shutdown.
+        Idempotent."""
+        if self._task is None:
+            return
+        task, self._task = self._task, None
+        self._server.should_exit = True
+        with contextlib.suppress(Exception):
+            await task
+        logger.info("[HttpServer] Stopped")
+
+
+async def _serve_until_signal(server: HttpServer) -> None:
+    stop = asyncio.Event()
+    loop = asyncio.get_running_loop()
+    for sig in (signal.SIGINT, signal.SIGTERM):
+        loop.add_signal_handler(sig, stop.set)
+    await server.start()
+    stopped = asyncio.create_task(stop.wait())
+    running = asyncio.create_task(server.wait())
+    await asyncio.wait({stopped, running}, return_when=asyncio.FIRST_COMPLETED)
+    stopped.cancel()
+    await server.stop()
+    # Re-raise a failure of the server itself (a signal-driven stop is clean).
+    await running
+
+
+def serve(settings: Settings, app: Union[str, object] = "main:app") -> None:
+    """Run the listener until SIGINT/SIGTERM.
+
+    Raises:
+        ConfigError: see server_config(); nothing is bound in that case
+        RuntimeError: see HttpServer.start()
+    """
+    asyncio.run(_serve_until_signal(HttpServer(settings, app)))
@@ -0,0 +1,75 @@
+"""Per-stage latency of a /v1/venues/nearby request.
+
+A nearby request is timed in five stages:
+
+- parse: query-parameter validation, before the route body runs;
+- geo_query: the geo index lookup plus the lifecycle/closure filters;
+- live_fetch: the bulk live + weekly forecast reads (`_merge`);
+- transform: freshness stamping and the verbose/minified shaping;
+- encode: JSON encoding of the response body.
+
+Every stage is observed in NEARBY_STAGE_DURATION_SECONDS, set on the request's
+trace span as `nearby.stage.<stage>_ms` attributes (app/tracing.py) and the
+breakdown is logged at DEBUG (so a path or region debug target surfaces it for
+the requests being investigated). With settings.nearby_server_timing_enabled the response
+also carries a `Server-Timing` header, which browser dev tools render next to
+the network timing.
+"""
+from __future__ import annotations
+
+import time
+from contextlib import contextmanager
+from typing import Callable, Iterator
+
+from opentelemetry import trace
+
+from app.metrics import NEARBY_STAGE_DURATION_SECONDS
+
+NEARBY_STAGES = ("parse", "geo_query", "live_fetch", "transform", "encode")
+
+
+class StageTimer:
+    """Collects stage durations (seconds) for one request."""
+
+    def __init__(self, clock: Callable[[], float] = time.perf_counter) -> None:
+        self._clock = clock
+        self._started = clock()
+        self.durations: dict[str, float] = {}
+
+    def mark(self, stage: str) -> None:
+        """Record `stage` as the time since the timer was created."""
+        self.durations[stage] = self._clock() - self._started
+
+    @contextmanager
+    def stage(self, stage: str) -> Iterator[None]:
+        """Time the enclosed block as `stage` (added up if entered again)."""
+        start = self._clock()
+        try:
+            yield
+        finally:
+            self.durations[stage] = self.durations.get(stage, 0.0) + self._clock() - start
+
+    def observe(self) -> None:
+        """Feed the recorded stages to the stage histogram."""
+        for stage, seconds in self.durations.items():
+            NEARBY_STAGE_DURATION_SECONDS.labels(stage=stage).observe(seconds)
+
+    def annotate_span(self) -> None:
+        """Set the recorded stages on the active span, in milliseconds (a
+        no-op while tracing is off)."""
+        span = trace.get_current_span()
+        for stage, seconds in self.durations.items():
+            span.set_attribute(f"nearby.stage.{stage}_ms", round(seconds * 1000, 1))
+
+    def summary(self) -> str:
+        """e.g. "parse=0.1ms geo_query=2.3ms ..." for the log line."""
+        return " ".join(f"{s}={d * 1000:.1f}ms" for s, d in self._ordered())
+
+    def server_timing(self) -> str:
+        """The `Server-Timing` header value, durations in milliseconds."""
+        return ", ".join(f"{s};dur={d * 1000:.1f}" for s, d in self._ordered())
+
+    def _ordered(self) -> list[tuple[str, float]]:
+        known = [(s, self.durations[s]) for s in NEARBY_STAGES if s in self.durations]
+        extra = [(s, d) for s, d in self.durations.items() if s not in NEARBY_STAGES]
+        return known + extra
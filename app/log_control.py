@@ -0,0 +1,275 @@
+"""Runtime log level and targeted debug logging.
+
+The global level starts at settings.log_level and can be changed at runtime
+(PUT /admin/logging/level) without a redeploy, as can the level of a single
+module (`{"logger": "app.dao.redis_venue_dao", "level": "DEBUG"}`, which also
+covers its submodules; "NOTSET" removes the override). For tracing one production
+issue without drowning in DEBUG output, a debug target enables DEBUG records
+only when they concern:
+
+- a venue: the message mentions the venue id;
+- a request path: the record was logged while serving a request whose path
+  starts with the target;
+- a region: the record was logged while serving a request whose lat/lon
+  query parameters fall inside the target circle.
+
+Targets expire after a bounded window. While any is active the root logger
+runs at DEBUG and `TargetedDebugFilter` (on the root handlers, like the
+secret-redaction filter) drops every below-global-level record that matches
+no target. `RequestLogContextMiddleware` records the path and coordinates of
+the request being served.
+"""
+from __future__ import annotations
+
+import contextvars
+import logging
+import threading
+import time
+from dataclasses import dataclass
+from typing import Optional
+
+from starlette.middleware.base import BaseHTTPMiddleware
+from starlette.requests import Request
+from starlette.responses import Response
+
+from app.services.venue_eligibility import haversine_km
+
+logger = logging.getLogger(__name__)
+
+DEBUG_TARGET_KINDS = ("venue", "region", "path")
+MAX_DEBUG_WINDOW_MINUTES = 240
+
+_request_path: contextvars.ContextVar[Optional[str]] = contextvars.ContextVar(
+    "log_request_path", default=None
+)
+_request_point: contextvars.ContextVar[Optional[tuple[float, float]]] = contextvars.ContextVar(
+    "log_request_point", default=None
+)
+
+
+def request_path() -> Optional[str]:
+    """Path of the request being served, if any (RequestLogContextMiddleware)."""
+    return _request_path.get()
+
+
+@dataclass(frozen=True)
+class DebugTarget:
+    """One targeted-debug toggle. `value` is a venue id, a path prefix, or a
+    region as "lat,lng,radius_km"."""
+    kind: str
+    value: str
+    expires_at: float
+
+    def to_dict(self) -> dict:
+        return {"kind": self.kind, "value": self.value, "expires_at": self.expires_at}
+
+
+def _parse_region(value: str) -> tuple[float, float, float]:
+    lat, lng, radius_km = (float(part) for part in value.split(","))
+    if not (-90 <= lat <= 90 and -180 <= lng <= 180) or radius_km <= 0:
+        raise ValueError("region must be 'lat,lng,radius_km' with a positive radius")
+    return lat, lng, radius_km
+
+
+class LogControl:
+    """The global level plus the active debug targets (process-wide)."""
+
+    def __init__(self, clock=time.time) -> None:
+        self._clock = clock
+        self._lock = threading.Lock()
+        self._level = logging.INFO
+        self._targets: list[DebugTarget] = []
+        self._module_levels: dict[str, int] = {}
+
+    @property
+    def level(self) -> int:
+        return self._level
+
+    def set_level(self, level: str) -> str:
+        """Set the global level by name; returns the previous level's name.
+
+        Raises:
+            ValueError: unknown level name
+        """
+        value = logging.getLevelName(level.upper())
+        if not isinstance(value, int):
+            raise ValueError(f"unknown log level: {level!r}")
+        with self._lock:
+            previous = self._level
+            self._level = value
+        self._apply()
+        if value != previous:
+            logger.warning(
+                f"[LogControl] Log level {logging.getLevelName(previous)} -> "
+                f"{logging.getLevelName(value)}"
+            )
+        return logging.getLevelName(previous)
+
+    def set_module_level(self, name: str, level: str) -> Optional[str]:
+        """Override the level of logger `name` and its children; "NOTSET"
+        removes the override. Returns the previous override's name, if any.
+
+        Raises:
+            ValueError: unknown level name or empty logger name
+        """
+        name = name.strip()
+        if not name:
+            raise ValueError("logger name is required")
+        value = logging.getLevelName(level.upper())
+        if not isinstance(value, int):
+            raise ValueError(f"unknown log level: {level!r}")
+        with self._lock:
+            previous = self._module_levels.pop(name, None)
+            if value != logging.NOTSET:
+                self._module_levels[name] = value
+        logging.getLogger(name).setLevel(value)
+        logger.warning(
+            f"[LogControl] Log level of {name} -> {logging.getLevelName(value)}"
+        )
+        return logging.getLevelName(previous) if previous is not None else None
+
+    def module_levels(self) -> dict[str, str]:
+        with self._lock:
+            overrides = sorted(self._module_levels.items())
+        return {name: logging.getLevelName(value) for name, value in overrides}
+
+    def _threshold(self, logger_name: str) -> int:
+        """The module override of the closest configured ancestor of
+        `logger_name`, else the global level."""
+        with self._lock:
+            overrides = dict(self._module_levels)
+        if overrides:
+            name = logger_name
+            while name:
+                if name in overrides:
+                    return overrides[name]
+                name = name.rpartition(".")[0]
+        return self._level
+
+    def add_target(self, kind: str, value: str, minutes: float) -> DebugTarget:
+        """Enable debug logging for `kind`/`value` for `minutes`.
+
+        Raises:
+            ValueError: unknown kind, malformed value, or a window outside
+                (0, MAX_DEBUG_WINDOW_MINUTES]
+        """
+        if kind not in DEBUG_TARGET_KINDS:
+            raise ValueError(f"kind must be one of {', '.join(DEBUG_TARGET_KINDS)}")
+        if not value:
+            raise ValueError("value is required")
+        if kind == "region":
+            _parse_region(value)
+        if not 0 < minutes <= MAX_DEBUG_WINDOW_MINUTES:
+            raise ValueError(f"minutes must be in (0, {MAX_DEBUG_WINDOW_MINUTES}]")
+        target = DebugTarget(kind, value, self._clock() + minutes * 60)
+        with self._lock:
+            self._targets = [
+                t for t in self._targets if (t.kind, t.value) != (kind, value)
+            ] + [target]
+        self._apply()
+        logger.warning(f"[LogControl] Debug logging for {kind}={value!r} for {minutes:g} min")
+        return target
+
+    def clear_targets(self) -> int:
+        """Remove every debug target; returns how many were active."""
+        with self._lock:
+            count = len(self._targets)
+            self._targets = []
+        self._apply()
+        return count
+
+    def targets(self) -> list[DebugTarget]:
+        """The unexpired targets (expired ones are dropped here)."""
+        now = self._clock()
+        with self._lock:
+            live = [t for t in self._targets if t.expires_at > now]
+            expired = len(live) != len(self._targets)
+            self._targets = live
+        if expired:
+            self._apply()
+        return live
+
+    def _apply(self) -> None:
+        """Run the root logger at DEBUG while targets exist, else at the
+        global level."""
+        with self._lock:
+            level = logging.DEBUG if self._targets else self._level
+        logging.getLogger().setLevel(level)
+
+    def allows(self, record: logging.LogRecord) -> bool:
+        """Whether a record passes: at/above its module's (else the global)
+        level, or matching an active target."""
+        if record.levelno >= self._threshold(record.name):
+            return True
+        targets = self.targets()
+        if not targets:
+            return False
+        path = _request_path.get()
+        point = _request_point.get()
+        message = None
+        for t in targets:
+            if t.kind == "path":
+                if path is not None and path.startswith(t.value):
+                    return True
+            elif t.kind == "region":
+                if point is not None:
+                    lat, lng, radius_km = _parse_region(t.value)
+                    if haversine_km(lat, lng, *point) <= radius_km:
+                        return True
+            else:
+                if message is None:
+                    try:
+                        message = record.getMessage()
+                    except Exception:
+                        message = ""
+                if t.value in message:
+                    return True
+        return False
+
+
+log_control = LogControl()
+
+
+class TargetedDebugFilter(logging.Filter):
+    """Drop below-level records that match no debug target."""
+
+    def __init__(self, control: LogControl = log_control) -> None:
+        super().__init__()
+        self.control = control
+
+    def filter(self, record: logging.LogRecord) -> bool:
+        return self.control.allows(record)
+
+
+def install_log_control(level: str, logger_: logging.Logger | None = None) -> None:
+    """Set the initial global level and attach TargetedDebugFilter to every
+    handler of `logger_` (root by default). Idempotent."""
+    target = logger_ if logger_ is not None else logging.getLogger()
+    for handler in target.handlers:
+        if not any(isinstance(f, TargetedDebugFilter) for f in handler.filters):
+            handler.addFilter(TargetedDebugFilter())
+    try:
+        log_control.set_level(level)
+    except ValueError:
+        logger.warning(f"[LogControl] Unknown log_level {level!r}; keeping INFO")
+
+
+class RequestLogContextMiddleware(BaseHTTPMiddleware):
+    """Record the request path and lat/lon for path/region debug targets."""
+
+    async def dispatch(self, request: Request, call_next) -> Response:
+        point = None
+        try:
+            lat = request.query_params.get("lat")
+            lon = request.query_params.get("lon") or request.query_params.get("lng")
+            if lat is not None and lon is not None:
+                point = (float(lat), float(lon))
+        except ValueError:
+            point = None
+        path_token = _request_path.set(request.url.path)
+        point_token = _request_point.set(point)
+        try:
+            return await call_next(request)
+        finally:
+            _request_path.reset(path_token)
+            _request_point.reset(point_token)
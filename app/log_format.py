@@ -0,0 +1,86 @@
+"""Structured (JSON) log output.
+
+With settings.log_format = "json" every root handler formats records as one
+JSON object per line, for log shippers that index fields instead of grepping
+text:
+
+- `ts` (UTC, ISO 8601), `level`, `logger` (the module's logger name);
+- `component`: the `[VenuesRefresherService]`-style tag the codebase prefixes
+  its messages with, lifted out of `message`;
+- `path`: the request being served, when there is one (RequestLogContextMiddleware);
+- `trace_id` / `span_id` of the active OpenTelemetry span, when recorded;
+- `exception`: the formatted traceback;
+- any `extra={...}` fields passed to the logging call.
+
+The default "text" keeps the human-readable format of logging.basicConfig.
+Redaction and targeted-debug filters run before formatting either way.
+"""
+from __future__ import annotations
+
+import json
+import logging
+import re
+from datetime import datetime, timezone
+
+from opentelemetry import trace
+
+from app.log_control import request_path
+
+LOG_FORMATS = ("text", "json")
+
+_COMPONENT_RE = re.compile(r"^\[([A-Za-z][\w.:-]*)\]\s*")
+
+# Attributes every LogRecord has; anything else came in through `extra`.
+_RECORD_ATTRS = frozenset(
+    logging.LogRecord("", 0, "", 0, "", (), None).__dict__
+) | {"message", "asctime", "taskName"}
+
+
+class JsonLogFormatter(logging.Formatter):
+    """One JSON object per record (see module docstring for the fields)."""
+
+    def format(self, record: logging.LogRecord) -> str:
+        message = record.getMessage()
+        entry: dict = {
+            "ts": datetime.fromtimestamp(record.created, tz=timezone.utc).isoformat(
+                timespec="milliseconds"
+            ),
+            "level": record.levelname,
+            "logger": record.name,
+        }
+        match = _COMPONENT_RE.match(message)
+        if match:
+            entry["component"] = match.group(1)
+            message = message[match.end():]
+        entry["message"] = message
+        path = request_path()
+        if path is not None:
+            entry["path"] = path
+        span_context = trace.get_current_span().get_span_context()
+        if span_context.is_valid:
+            entry["trace_id"] = format(span_context.trace_id, "032x")
+            entry["span_id"] = format(span_context.span_id, "016x")
+        for key, value in record.__dict__.items():
+            if key not in _RECORD_ATTRS and not key.startswith("_") and key not in entry:
+                entry[key] = value
+        if record.exc_info:
+            entry["exception"] = self.formatException(record.exc_info)
+        elif record.exc_text:
+            entry["exception"] = record.exc_text
+        return json.dumps(entry, default=str, ensure_ascii=False)
+
+
+def install_log_format(log_format: str, logger_: logging.Logger | None = None) -> None:
+    """Give every handler of `logger_` (root by default) the formatter for
+    `log_format`; "text" leaves the handlers as configured.
+
+    Raises:
+        ValueError: unknown format
+    """
+    if log_format not in LOG_FORMATS:
+        raise ValueError(f"log_format must be one of {', '.join(LOG_FORMATS)}")
+    if log_format == "text":
+        return
+    target = logger_ if logger_ is not None else logging.getLogger()
+    for handler in target.handlers:
+        handler.setFormatter(JsonLogFormatter())
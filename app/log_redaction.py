@@ -19,7 +19,7 @@ import re
 _REDACTIONS: list[tuple[re.Pattern, str]] = [
     # Secret-bearing query params / kwargs: mask the value (stop at &, space, quote).
     (re.compile(
-        r'(api_key_private|api_key|apikey|access_token|token|password)=[^&\s"\'<>}]+',
+        r'(api_key_private|api_key_public|api_key|apikey|access_token|token|password)=[^&\s"\'<>}]+',
         re.IGNORECASE,
     ), r"\1=***REDACTED***"),
     # Google Maps/Places `key=` param — scoped to the AIza-prefixed key value so
@@ -32,6 +32,14 @@ _REDACTIONS: list[tuple[re.Pattern, str]] = [
 ]
 
 
+def redact_secrets(text: str) -> str:
+    """`text` with every known secret value masked (see _REDACTIONS). Also used
+    for data kept outside the logs, e.g. BestTime `_links` URLs."""
+    for pattern, repl in _REDACTIONS:
+        text = pattern.sub(repl, text)
+    return text
+
+
 class SecretRedactingFilter(logging.Filter):
     """Mask known secret values in the final log message. Never drops a record."""
 
@@ -40,9 +48,7 @@ class SecretRedactingFilter(logging.Filter):
             message = record.getMessage()
         except Exception:  # pragma: no cover - malformed record; never block logging
             return True
-        redacted = message
-        for pattern, repl in _REDACTIONS:
-            redacted = pattern.sub(repl, redacted)
+        redacted = redact_secrets(message)
         if redacted != message:
             # Replace the formatted message and drop args so the handler's
             # formatter re-emits the redacted text verbatim.
@@ -4,7 +4,8 @@ Exposes metrics for:
 1. HTTP API metrics (requests, latency, errors)
 2. BestTime API client metrics (calls, latency, errors)
 3. Background job metrics (runs, duration, errors)
-4. Data quality metrics (venues with various attributes)
+4. Redis DAO metrics (forecast cache hit/miss, operation latency)
+5. Data quality metrics (venues with various attributes)
 """
 from prometheus_client import Counter, Histogram, Gauge, Info
 
@@ -89,6 +90,46 @@ BESTTIME_SEARCH_RATE_LIMIT_TOTAL = Counter(
                             # rejected (wait budget exhausted)
 )
 
+# Seconds spent waiting on the client-wide BestTime token bucket.
+BESTTIME_THROTTLE_WAIT_SECONDS_TOTAL = Counter(
+    "besttime_throttle_wait_seconds_total",
+    "Seconds BestTime calls waited on the client-side token bucket",
+)
+
+# BestTime circuit breaker: 1 while open/half-open (failing fast), else 0, and
+# the calls it short-circuited.
+BESTTIME_CIRCUIT_STATE = Gauge(
+    "besttime_circuit_open",
+    "1 while the BestTime circuit breaker is open, else 0",
+)
+BESTTIME_CIRCUIT_SHORT_CIRCUITED_TOTAL = Counter(
+    "besttime_circuit_short_circuited_total",
+    "BestTime calls failed fast by the open circuit breaker",
+    ["endpoint"],
+)
+
+# Estimated BestTime credits drawn by successful calls
+# (app/services/besttime_quota.py).
+BESTTIME_CREDITS_USED_TOTAL = Counter(
+    "besttime_credits_used_total",
+    "Estimated BestTime credits used",
+    ["family"],  # family: search | filter | live | week | forecast | other
+)
+
+# BestTime key pairs taken out of rotation (app/api/besttime_client.py _KeyRing).
+BESTTIME_KEY_ROTATIONS_TOTAL = Counter(
+    "besttime_key_rotations_total",
+    "BestTime API key pairs rotated out",
+    ["reason"],  # reason: quota_exceeded
+)
+
+# Retries of transient BestTime answers (429/5xx) under the client RetryPolicy.
+BESTTIME_API_RETRIES_TOTAL = Counter(
+    "besttime_api_retries_total",
+    "BestTime API calls retried after a transient HTTP status",
+    ["endpoint", "status_code"],
+)
+
 # Analysis day entries dropped while parsing a POST /forecasts (create venue)
 # response. Analysis is best-effort on creates: a malformed day never fails
 # the envelope, but each drop is counted here (and WARNING-logged).
@@ -97,6 +138,20 @@ BESTTIME_ADD_VENUE_ANALYSIS_DROPPED_TOTAL = Counter(
     "Analysis day entries dropped while parsing BestTime POST /forecasts responses",
 )
 
+# Schema drift in BestTime bodies (app/api/besttime_decoding.py): fields our
+# models do not declare, by dotted path ("venues[].venue_score"), and bodies
+# rejected as invalid (any mode) or drifted (strict decoding).
+BESTTIME_UNKNOWN_FIELDS_TOTAL = Counter(
+    "besttime_unknown_fields_total",
+    "Fields in BestTime responses that the response models do not declare",
+    ["endpoint", "field"],
+)
+BESTTIME_SCHEMA_ERRORS_TOTAL = Counter(
+    "besttime_schema_errors_total",
+    "BestTime responses rejected for not matching their response model",
+    ["endpoint", "kind"],  # kind: invalid | drift
+)
+
 # =============================================================================
 # GOOGLE PLACES API CLIENT METRICS
 # =============================================================================
@@ -205,6 +260,70 @@ VENUES_SOFT_DELETED_TOTAL = Counter(
     ["reason", "source"],
 )
 
+# Records removed by the stale eviction job (app/services/stale_eviction.py);
+# kind: venue (soft-deleted) | live_forecast. Dry runs are not counted.
+STALE_EVICTIONS_TOTAL = Counter(
+    "stale_evictions_total",
+    "Venues and live forecasts evicted for not being refreshed",
+    ["kind"],
+)
+
+# Venues folded into a fuzzy duplicate (app/services/venue_dedup.py); stage:
+# upsert (a discovered venue skipped for an existing one) | cleanup (an
+# existing venue soft-deleted by the periodic pass). Dry runs are not counted.
+VENUE_DUPLICATES_MERGED_TOTAL = Counter(
+    "venue_duplicates_merged_total",
+    "Venues merged into a nearby venue with a similar name",
+    ["stage"],
+)
+
+# Retry queue of failed refresh work (app/services/retry_queue.py).
+# kind: upsert_venue | live_forecast | webhook_delivery; result: queued,
+# succeeded, retry_failed,
+# dead (moved to the dead-letter list after the last attempt).
+RETRY_QUEUE_ITEMS_TOTAL = Counter(
+    "retry_queue_items_total",
+    "Failed refresh work queued for retry, by kind and outcome",
+    ["kind", "result"],
+)
+
+# Busyness alert webhook POSTs (app/services/webhooks.py). result: delivered,
+# retrying (failed, queued again), failed (given up after the last attempt).
+WEBHOOK_DELIVERIES_TOTAL = Counter(
+    "webhook_deliveries_total",
+    "Busyness alert webhook delivery attempts, by outcome",
+    ["result"],
+)
+
+# Venue events for the message broker (app/services/event_publishing.py).
+# result: queued (written to the outbox), published, failed (the publish
+# failed; kept for the next run), dropped (oldest trimmed from a full outbox).
+EVENTS_OUTBOX_TOTAL = Counter(
+    "events_outbox_total",
+    "Venue events through the broker outbox, by outcome",
+    ["result"],
+)
+EVENTS_OUTBOX_PENDING = Gauge(
+    "events_outbox_pending",
+    "Venue events waiting in the outbox after the last publish run",
+)
+
+# Venue data snapshots (app/services/backup_service.py). operation: backup |
+# restore; result: success | error.
+BACKUP_RUNS_TOTAL = Counter(
+    "backup_runs_total",
+    "Venue snapshot backups and restores, by outcome",
+    ["operation", "result"],
+)
+
+# Favorite-venue push notifications (app/services/push_notifications.py), one
+# per device. result: sent, failed, unregistered (token dropped).
+PUSH_NOTIFICATIONS_TOTAL = Counter(
+    "push_notifications_total",
+    "Favorite-venue busyness pushes sent through FCM, by outcome",
+    ["result"],
+)
+
 # Current deprecated venue count
 VENUES_DEPRECATED_TOTAL = Gauge(
     "venues_deprecated_total",
@@ -252,6 +371,26 @@ APIFY_API_ERRORS_TOTAL = Counter(
     ["endpoint", "error_type"],
 )
 
+# OpenStreetMap / Foursquare directory calls and enrichment outcomes
+# (app/services/open_data_enrichment_service.py).
+OPEN_DATA_API_CALLS_TOTAL = Counter(
+    "open_data_api_calls_total",
+    "OpenStreetMap / Foursquare directory API calls",
+    ["source", "status"],  # source: osm | foursquare; status: success | error
+)
+OPEN_DATA_ENRICHMENT_RESULTS = Counter(
+    "open_data_enrichment_results_total",
+    "Results of open-data venue enrichment",
+    ["result"],  # result: matched | no_match | error | skipped
+)
+
+# Venue media enrichment outcomes (app/services/venue_media.py).
+VENUE_MEDIA_ENRICHMENT_RESULTS = Counter(
+    "venue_media_enrichment_results_total",
+    "Results of venue media (photos / links) enrichment",
+    ["result"],  # result: enriched | no_media | error | skipped
+)
+
 # Instagram enrichment results
 INSTAGRAM_ENRICHMENT_RESULTS = Counter(
     "instagram_enrichment_results_total",
@@ -412,7 +551,7 @@ VIBE_CLASSIFIER_CONFIDENCE = Histogram(
 BACKGROUND_JOB_RUNS_TOTAL = Counter(
     "background_job_runs_total",
     "Total number of background job runs",
-    ["job_name", "status"],  # status: success, error
+    ["job_name", "status"],  # status: success, error, cancelled, skipped_quota
 )
 
 # Job duration
@@ -441,6 +580,14 @@ JOB_LOCK_REJECTED_TOTAL = Counter(
     ["job_name", "source"],  # source: scheduler | admin
 )
 
+# A run's distributed job lock expired or changed hands while it was running
+# (app/services/job_lock.py); the run is cancelled.
+JOB_LOCK_LOST_TOTAL = Counter(
+    "job_lock_lost_total",
+    "Total job runs stopped because their distributed job lock was lost",
+    ["job_name"],
+)
+
 # Redis projection (RDS -> Redis off-loop projector).
 # Run counts/duration use BACKGROUND_JOB_* with job_name="redis_projection";
 # these add projection-specific observability.
@@ -503,6 +650,93 @@ VENUES_REACTIVATED_TOTAL = Counter(
     ["source"],
 )
 
+# =============================================================================
+# REDIS DAO METRICS
+# =============================================================================
+
+# Serving-cache lookups on the forecast keys, one count per venue looked up
+# (bulk MGETs count each id). A miss-dominated live ratio means the live
+# refresh cadence is too slow for the freshness window or too narrow for the
+# catalog; error = Redis unreachable.
+REDIS_DAO_CACHE_LOOKUPS_TOTAL = Counter(
+    "redis_dao_cache_lookups_total",
+    "Forecast cache lookups in the Redis DAO",
+    ["entity", "result"],  # entity: live_forecast | weekly_forecast | partner_live; result: hit | miss | error
+)
+
+# In-process read-through cache in front of the serving DAO (app/dao/read_cache.py),
+# only populated when settings.redis_read_cache_enabled.
+REDIS_DAO_READ_CACHE_TOTAL = Counter(
+    "redis_dao_read_cache_total",
+    "Serving DAO in-memory read cache lookups",
+    ["cache", "result"],  # cache: nearby | venue | live; result: hit | miss
+)
+
+# Change events published on the venue_changes_v1 channel
+# (app/dao/change_events.py); error = the publish failed and was dropped.
+REDIS_DAO_CHANGE_EVENTS_TOTAL = Counter(
+    "redis_dao_change_events_total",
+    "Venue change events published by the Redis DAO",
+    ["change_type", "result"],  # change_type: venue_upserted | live_forecast_set | live_forecast_deleted; result: published | error
+)
+
+# Venue documents read in an older schema_version and upgraded in memory
+# (app/dao/venue_codec.py). Stays flat once every venue has been rewritten.
+VENUE_DOCUMENT_UPGRADES_TOTAL = Counter(
+    "venue_document_upgrades_total",
+    "Stored venue documents upgraded from an older schema version on read",
+    ["from_version"],
+)
+
+# Bytes kept out of Redis by value compression (app/db/value_compression.py),
+# net of the base64 overhead.
+REDIS_COMPRESSION_SAVED_BYTES_TOTAL = Counter(
+    "redis_compression_saved_bytes_total",
+    "Bytes saved by compressing large Redis values",
+    ["algorithm"],  # algorithm: gzip | snappy
+)
+
+# Nearby reads served from RDS instead of the Redis geo index
+# (app/dao/rds_fallback_dao.py, settings.venue_read_backend).
+VENUE_READ_FALLBACK_TOTAL = Counter(
+    "venue_read_fallback_total",
+    "Nearby venue reads served from RDS",
+    ["reason"],  # reason: redis_error | empty_index | rds_mode
+)
+
+# Nearby requests that hit a Redis error with the last-known-good snapshot
+# enabled (app/dao/snapshot_venue_dao.py).
+NEARBY_SNAPSHOT_FAILOVER_TOTAL = Counter(
+    "nearby_snapshot_failover_total",
+    "Nearby requests that failed over to the in-memory snapshot",
+    ["result"],  # result: served | unavailable (no snapshot, or too old)
+)
+
+# Nearby requests at a precomputed radius (app/services/nearby_precompute.py):
+# hit = answered from the precomputed JSON, miss = fell through to the live path.
+NEARBY_PRECOMPUTED_TOTAL = Counter(
+    "nearby_precomputed_total",
+    "Nearby requests looked up in the precomputed responses",
+    ["result"],
+)
+
+# Per-stage latency of /v1/venues/nearby (app/latency_budget.py).
+NEARBY_STAGE_DURATION_SECONDS = Histogram(
+    "nearby_stage_duration_seconds",
+    "Nearby request latency per stage in seconds",
+    ["stage"],  # stage: parse | geo_query | live_fetch | transform | encode
+    buckets=(0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1.0, 2.5),
+)
+
+# Wall time of the hot DAO operations (including (de)serialization), so a slow
+# Redis shows up here before it shows up in HTTP latency.
+REDIS_DAO_OPERATION_DURATION_SECONDS = Histogram(
+    "redis_dao_operation_duration_seconds",
+    "Redis DAO operation latency in seconds",
+    ["operation"],
+    buckets=(0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1.0, 2.5),
+)
+
 # =============================================================================
 # VENUE DATA QUALITY METRICS
 # =============================================================================
@@ -545,6 +779,36 @@ VENUES_LIVE_FORECAST_AVAILABILITY_RATIO = Gauge(
     "Ratio of venues with live forecast data to total venues (0-1)",
 )
 
+# Busyness validation stage (app/services/busyness_validation.py), per input.
+# rejected: impossible value (negative / non-numeric / above the reject bound),
+# never cached; clamped: plausible value outside 0..100, cached as 100.
+BUSYNESS_VALIDATION_TOTAL = Counter(
+    "busyness_validation_total",
+    "Ingested busyness values by validation outcome",
+    ["source", "outcome"],  # source: live | weekly | partner; outcome: accepted | clamped | rejected
+)
+
+# Venue validation stage (app/services/venue_validation.py), per write path.
+# rejected: not written (no id/name, bad coordinates); flagged: written without
+# an out-of-bounds rating / price level / review count.
+VENUE_VALIDATION_TOTAL = Counter(
+    "venue_validation_total",
+    "Venues checked before upsert by validation outcome",
+    ["source", "outcome"],  # source: discovery | inventory | retry | write (any repository upsert); outcome: accepted | flagged | rejected
+)
+
+# Samples that completed a flip-flop pattern (large alternating swings between
+# consecutive refreshes), and how many venues are currently flagged for it.
+BUSYNESS_FLIPFLOP_TOTAL = Counter(
+    "busyness_flipflop_total",
+    "Live busyness samples that completed a flip-flop pattern",
+    ["source"],
+)
+VENUES_BUSYNESS_FLIPFLOP_FLAGGED = Gauge(
+    "venues_busyness_flipflop_flagged",
+    "Venues whose live busyness flip-flopped within the last 24h",
+)
+
 # Serve-time live-busyness freshness outcomes (nearby-serve minified path).
 # served: a fresh live value was served as-is.
 # suppressed_stale: a present live value was omitted because its payload age
@@ -591,7 +855,7 @@ REFRESH_VENUES_UPSERTED = Gauge(
 REFRESH_DUPLICATES_SKIPPED = Counter(
     "refresh_duplicates_skipped_total",
     "Total number of duplicate venues skipped during refresh",
-    ["reason"],  # reason: duplicate_id, duplicate_name, no_id_or_name
+    ["reason"],  # reason: duplicate_id, duplicate_name, no_id_or_name, fuzzy_duplicate
 )
 
 # Live forecast fetch results
@@ -601,7 +865,16 @@ LIVE_FORECAST_FETCH_RESULTS = Counter(
     # result: cached, deleted_not_ok, deleted_not_available, error,
     # skipped_venue_absent (benign: the live-forecast payload's venue_id has no
     # row in venues.venue — RdsVenueStore.upsert_live_forecast no-ops instead of
-    # raising ForeignKeyViolation; see venues_refresher_service.py)
+    # raising ForeignKeyViolation; see venues_refresher_service.py),
+    # skipped_no_provider (no CrowdDataProvider covers the venue),
+    # rejected_outlier (impossible live value; see busyness_validation.py),
+    # skipped_cooldown (refreshed within live_refresh_cooldown_seconds),
+    # skipped_closed (closed now per stored hours; live_refresh_skip_closed),
+    # skipped_circuit_open (run stopped: BestTime circuit breaker open),
+    # aborted (run stopped: BestTime quota exceeded or key rejected),
+    # skipped_invalid_venue (BestTime does not know the venue id),
+    # provider_stored (answered by a provider that keeps its own readings,
+    # e.g. a partner; not cached as BestTime live data)
     ["result"],
 )
 
@@ -609,7 +882,14 @@ LIVE_FORECAST_FETCH_RESULTS = Counter(
 WEEKLY_FORECAST_FETCH_RESULTS = Counter(
     "weekly_forecast_fetch_results_total",
     "Results of weekly forecast fetch operations",
-    ["result"],  # result: cached, skipped_not_ok, error
+    ["result"],  # result: cached, skipped_not_ok, skipped_no_provider, skipped_invalid_venue, error
+)
+
+# Thursday weekend prefetch, per selected venue
+WEEKEND_PREFETCH_VENUES_TOTAL = Counter(
+    "weekend_prefetch_venues_total",
+    "Venues handled by the weekend weekly-forecast prefetch",
+    ["result"],  # result: already_cached, fetched, failed, skipped_monthly_cap
 )
 
 # =============================================================================
@@ -681,14 +961,14 @@ ADD_VENUE_BY_ADDRESS_TOTAL = Counter(
                  # matched_via_geo_fallback | quota_exhausted |
                  # besttime_monthly_cap | besttime_error |
                  # besttime_bad_response | besttime_rejected_no_geo_match |
-                 # timeout_unconfirmed | validation_error |
+                 # timeout_unconfirmed | validation_error | rejected_by_validation |
                  # geo_link_undone | geo_link_undo_rejected
 )
 
 INVENTORY_SYNC_VENUES_TOTAL = Counter(
     "inventory_sync_venues_total",
     "Per-venue outcomes during the monthly BestTime inventory sync",
-    ["result"],  # seen | upserted | skipped | error
+    ["result"],  # seen | upserted | skipped | error | rejected
 )
 
 INVENTORY_SYNC_RUNS_TOTAL = Counter(
@@ -735,6 +1015,41 @@ ENGAGEMENT_HOT_LIKE_DEDUP_TOTAL = Counter(
     "(user, venue, business_period) row via ON CONFLICT DO NOTHING",
 )
 
+# =============================================================================
+# PARTNER OCCUPANCY METRICS
+# =============================================================================
+
+# Outcomes of partner occupancy pushes (POST /v1/partners/venues/{id}/occupancy).
+PARTNER_OCCUPANCY_READINGS_TOTAL = Counter(
+    "partner_occupancy_readings_total",
+    "Partner occupancy readings by outcome",
+    ["result"],  # result: stored, rejected, forbidden
+)
+
+# Venue check-ins (POST /v1/venues/{id}/checkin) by outcome.
+VENUE_CHECKINS_TOTAL = Counter(
+    "venue_checkins_total",
+    "Venue check-ins by outcome",
+    ["result"],  # result: counted, duplicate, ip_limited, too_far
+)
+
+# User venue suggestions (POST /v1/venues/suggest) and their moderation.
+VENUE_SUGGESTIONS_TOTAL = Counter(
+    "venue_suggestions_total",
+    "Venue suggestions by outcome",
+    ["result"],  # result: submitted, duplicate, queue_full, approved, rejected
+)
+
+# =============================================================================
+# HISTORY EXPORT METRICS
+# =============================================================================
+
+HISTORY_EXPORT_ROWS_TOTAL = Counter(
+    "history_export_rows_total",
+    "Rows written by the nightly analytics history export",
+    ["dataset"],  # dataset: busyness_history, venue_catalog_deltas
+)
+
 # =============================================================================
 # APPLICATION INFO
 # =============================================================================
@@ -1,8 +1,14 @@
-"""FastAPI middleware for Prometheus metrics instrumentation."""
+"""FastAPI middleware: Prometheus metrics instrumentation, OpenTelemetry server
+spans, the demo-mode rate limit, the request timeout and tenant selection."""
+import asyncio
+import logging
 import time
+
+from opentelemetry import propagate
+from opentelemetry.trace import SpanKind, Status, StatusCode
 from starlette.middleware.base import BaseHTTPMiddleware
 from starlette.requests import Request
-from starlette.responses import Response
+from starlette.responses import JSONResponse, Response
 
 from app.metrics import (
     HTTP_REQUESTS_TOTAL,
@@ -11,6 +17,10 @@ from app.metrics import (
     HTTP_REQUEST_SIZE_BYTES,
     HTTP_RESPONSE_SIZE_BYTES,
 )
+from app.tenancy import TenantRegistry, UnknownTenantError, tenant_scope
+from app.tracing import tracer
+
+logger = logging.getLogger(__name__)
 
 
 class PrometheusMiddleware(BaseHTTPMiddleware):
@@ -108,3 +118,129 @@ class PrometheusMiddleware(BaseHTTPMiddleware):
         if segment.isdigit() and len(segment) >= 5:
             return True
         return False
+
+
+class TracingMiddleware(BaseHTTPMiddleware):
+    """One OpenTelemetry server span per request (app/tracing.py), named after
+    the matched route and continuing an incoming `traceparent`."""
+
+    EXCLUDE_PATHS = PrometheusMiddleware.EXCLUDE_PATHS
+
+    async def dispatch(self, request: Request, call_next) -> Response:
+        if request.url.path in self.EXCLUDE_PATHS:
+            return await call_next(request)
+        with tracer.start_as_current_span(
+            f"{request.method} {request.url.path}",
+            context=propagate.extract(request.headers),
+            kind=SpanKind.SERVER,
+        ) as span:
+            span.set_attribute("http.request.method", request.method)
+            span.set_attribute("url.path", request.url.path)
+            response = await call_next(request)
+            route = request.scope.get("route")
+            if route is not None:
+                span.update_name(f"{request.method} {route.path}")
+                span.set_attribute("http.route", route.path)
+            span.set_attribute("http.response.status_code", response.status_code)
+            if response.status_code >= 500:
+                span.set_status(Status(StatusCode.ERROR))
+            return response
+
+
+class DemoRateLimitMiddleware(BaseHTTPMiddleware):
+    """Fixed-window per-client-IP request cap for the public demo mode.
+
+    In-process and per-replica by design: demo mode runs a single small
+    instance with no Redis, and the cap only has to keep a public URL from
+    being scraped or hammered. Over-limit requests get 429 with Retry-After.
+    """
+
+    EXCLUDE_PATHS = {"/metrics", "/health"}
+
+    def __init__(self, app, per_minute: int, clock=time.monotonic):
+        super().__init__(app)
+        self.per_minute = per_minute
+        self._clock = clock
+        # client -> (window_start, count)
+        self._windows: dict[str, tuple[float, int]] = {}
+
+    async def dispatch(self, request: Request, call_next) -> Response:
+        if self.per_minute <= 0 or request.url.path in self.EXCLUDE_PATHS:
+            return await call_next(request)
+
+        client = request.client.host if request.client else "unknown"
+        now = self._clock()
+        window_start, count = self._windows.get(client, (now, 0))
+        if now - window_start >= 60:
+            window_start, count = now, 0
+        if count >= self.per_minute:
+            retry_after = max(1, int(60 - (now - window_start)))
+            return JSONResponse(
+                status_code=429,
+                content={"detail": "demo rate limit exceeded"},
+                headers={"Retry-After": str(retry_after)},
+            )
+        self._windows[client] = (window_start, count + 1)
+        # Drop idle windows so the map stays bounded by active clients.
+        if len(self._windows) > 10_000:
+            self._windows = {
+                k: v for k, v in self._windows.items() if now - v[0] < 60
+            }
+        return await call_next(request)
+
+
+class RequestTimeoutMiddleware(BaseHTTPMiddleware):
+    """504 for a request whose handler has not produced a response within
+    `seconds` (settings.server_request_timeout_seconds); a response that has
+    started streaming is not cut off.
+
+    Only the wait is abandoned: an async handler is cancelled at its next
+    await, but a sync (`def`) route runs in the threadpool, which cannot be
+    interrupted, so it keeps running to completion after the 504 and holds
+    its worker thread until then."""
+
+    def __init__(self, app, seconds: float):
+        super().__init__(app)
+        self.seconds = seconds
+
+    async def dispatch(self, request: Request, call_next) -> Response:
+        try:
+            return await asyncio.wait_for(call_next(request), timeout=self.seconds)
+        except asyncio.TimeoutError:
+            logger.warning(
+                f"[HTTP] {request.method} {request.url.path} timed out after {self.seconds}s"
+            )
+            return JSONResponse(status_code=504, content={"detail": "request timed out"})
+
+
+class TenantMiddleware(BaseHTTPMiddleware):
+    """Runs each request as the tenant its X-API-Key (else the default tenant)
+    selects, so the serving DAO reads that tenant's Redis keyspace
+    (app/tenancy.py). Unknown keys, or an X-Tenant-Id other than the key's
+    tenant, get 401.
+
+    The admin, internal and partner routes authenticate on their own and work
+    on the unprefixed keyspace, so they are not scoped."""
+
+    EXCLUDE_PATHS = {"/metrics", "/health", "/ping", "/docs", "/openapi.json"}
+    EXCLUDE_PREFIXES = ("/admin/", "/internal/", "/v1/partners/", "/docs/")
+
+    def __init__(self, app, registry: TenantRegistry):
+        super().__init__(app)
+        self.registry = registry
+
+    async def dispatch(self, request: Request, call_next) -> Response:
+        path = request.url.path
+        if path in self.EXCLUDE_PATHS or path.startswith(self.EXCLUDE_PREFIXES):
+            return await call_next(request)
+        try:
+            tenant = self.registry.resolve(
+                api_key=request.headers.get("x-api-key"),
+                tenant_id=request.headers.get("x-tenant-id"),
+            )
+        except UnknownTenantError as e:
+            return JSONResponse(status_code=401, content={"detail": str(e)})
+        # call_next runs the route in a copy of this context, so the scope
+        # reaches the handler and its thread-pool calls.
+        with tenant_scope(tenant.id):
+            return await call_next(request)
@@ -8,11 +8,14 @@ from app.models.venue import (
     DayInfo,
     DayInfoV2,
     OpenCloseDetail,
+    ForecastAtTime,
 )
 from app.models.live_forecast import (
     LiveForecastResponse,
     VenueInfo,
     Analysis,
+    LiveHistoryPoint,
+    BusynessTrend,
 )
 from app.models.week_raw import (
     WeekRawResponse,
@@ -20,17 +23,34 @@ from app.models.week_raw import (
     WeekRawDay,
     RawWindow,
 )
+from app.models.day_forecast import (
+    DayForecastResponse,
+    DayForecastAnalysis,
+    HourForecastResponse,
+    HourForecastAnalysis,
+    HourAnalysis,
+)
 from app.models.venue_filter import (
     VenueFilterResponse,
     VenueFilterVenue,
     VenueFilterParams,
+    SearchParams,
     FilterWindow,
 )
+from app.models.open_data import OpenDataCandidate, OpenDataEnrichment
+from app.models.venue_media import VenueMedia
 from app.models.new_venue import (
     NewVenueResponse,
     NewVenueInfo,
     AccountInventoryVenue,
 )
+from app.models.besttime_mappers import (
+    forecast_days,
+    merge_forecast_days,
+    venue_from_filter,
+    venue_from_inventory,
+    venue_from_new_venue,
+)
 
 __all__ = [
     # Venue models
@@ -42,22 +62,41 @@ __all__ = [
     "DayInfo",
     "DayInfoV2",
     "OpenCloseDetail",
+    "ForecastAtTime",
     # Live forecast models
     "LiveForecastResponse",
     "VenueInfo",
     "Analysis",
+    "LiveHistoryPoint",
+    "BusynessTrend",
     # Weekly forecast models
     "WeekRawResponse",
     "WeekRawAnalysis",
     "WeekRawDay",
+    "DayForecastResponse",
+    "DayForecastAnalysis",
+    "HourForecastResponse",
+    "HourForecastAnalysis",
+    "HourAnalysis",
     "RawWindow",
     # Venue filter models
     "VenueFilterResponse",
     "VenueFilterVenue",
     "VenueFilterParams",
+    "SearchParams",
     "FilterWindow",
     # Add-venue / inventory models
     "NewVenueResponse",
     "NewVenueInfo",
     "AccountInventoryVenue",
+    # BestTime -> Venue mappers
+    "forecast_days",
+    "merge_forecast_days",
+    "venue_from_filter",
+    "venue_from_inventory",
+    "venue_from_new_venue",
+    # Open data enrichment models
+    "OpenDataCandidate",
+    "OpenDataEnrichment",
+    "VenueMedia",
 ]
@@ -0,0 +1,108 @@
+"""Mappers from BestTime's venue shapes to the canonical Venue.
+
+BestTime describes a venue differently per endpoint:
+- /venues/filter: flat rows with one day's forecast at the top level
+  (VenueFilterVenue: `day_int`, `day_raw`, `day_info`);
+- POST /forecasts: a `venue_info` block spelling longitude `venue_lon`, plus
+  the analysis days (NewVenueResponse);
+- GET /api/v1/venues: bare inventory rows without forecast (AccountInventoryVenue).
+
+Venue is the one model the rest of the code reads and writes. Every BestTime
+shape becomes one through these functions, which keep whatever forecast the
+response carries on `venue_foot_traffic_forecast` instead of dropping it.
+merge_forecast_days folds those days into the ones already stored, so
+discovery runs on different weekdays build up the week without forecast calls.
+Keyword arguments set or override Venue fields (provenance, refreshed_at, ...).
+The raw BestTime price goes to `besttime_price_level`; the served
+`price_level` is derived later (app/services/price_signal.py).
+"""
+from typing import Any, Iterable, Optional
+
+from app.models.new_venue import AccountInventoryVenue, NewVenueInfo, NewVenueResponse
+from app.models.venue import FootTrafficForecast, Venue
+from app.models.venue_filter import VenueFilterVenue
+from app.models.week_raw import WeekRawDay
+
+
+def forecast_days(days: Iterable[WeekRawDay]) -> Optional[list[FootTrafficForecast]]:
+    """Weekly forecast days as Venue forecast entries; None when there are none."""
+    forecast = [
+        FootTrafficForecast(day_int=d.day_int, day_raw=d.day_raw, day_info=d.day_info)
+        for d in days
+    ]
+    return forecast or None
+
+
+def merge_forecast_days(
+    stored: Optional[list[FootTrafficForecast]],
+    fresh: Optional[list[FootTrafficForecast]],
+) -> Optional[list[FootTrafficForecast]]:
+    """`stored` days updated with `fresh` ones, by day_int (Monday first).
+
+    A fresh day without busyness (`day_raw` empty, e.g. a Google-sourced row)
+    does not replace a stored curve.
+    """
+    days = {d.day_int: d for d in stored or []}
+    for day in fresh or []:
+        if day.day_raw or day.day_int not in days:
+            days[day.day_int] = day
+    return [days[d] for d in sorted(days)] or None
+
+
+def venue_from_filter(vf: VenueFilterVenue, **fields: Any) -> Venue:
+    """A /venues/filter row, with its one day of forecast."""
+    data = dict(
+        forecast=True,
+        processed=True,
+        venue_address=vf.venue_address,
+        venue_lat=vf.venue_lat,
+        venue_lng=vf.venue_lng,
+        venue_name=vf.venue_name,
+        venue_id=vf.venue_id,
+        venue_type=vf.venue_type,
+        venue_dwell_time_min=vf.venue_dwell_time_min,
+        venue_dwell_time_max=vf.venue_dwell_time_max,
+        rating=vf.rating,
+        reviews=vf.reviews,
+        besttime_price_level=vf.price_level,
+        venue_foot_traffic_forecast=[
+            FootTrafficForecast(day_int=vf.day_int, day_raw=vf.day_raw, day_info=vf.day_info)
+        ],
+    )
+    data.update(fields)
+    return Venue(**data)
+
+
+def venue_from_new_venue(response: NewVenueResponse, **fields: Any) -> Venue:
+    """A POST /forecasts create, with the analysis days that parsed."""
+    info = response.venue_info or NewVenueInfo()
+    data = dict(
+        forecast=True,
+        processed=True,
+        venue_id=info.venue_id or "",
+        venue_name=info.venue_name or "",
+        venue_address=info.venue_address or "",
+        venue_lat=float(info.venue_lat or 0.0),
+        venue_lng=float(info.venue_lng or 0.0),
+        rating=info.rating,
+        reviews=info.reviews,
+        besttime_price_level=info.price_level,
+        venue_foot_traffic_forecast=forecast_days(response.analysis),
+    )
+    data.update(fields)
+    return Venue(**data)
+
+
+def venue_from_inventory(inv: AccountInventoryVenue, **fields: Any) -> Venue:
+    """A GET /api/v1/venues row; inventory rows carry no forecast."""
+    data = dict(
+        forecast=bool(inv.venue_forecasted),
+        processed=True,
+        venue_id=inv.venue_id,
+        venue_name=inv.venue_name or "",
+        venue_address=inv.venue_address or "",
+        venue_lat=float(inv.venue_lat or 0.0),
+        venue_lng=float(inv.venue_lng or 0.0),
+    )
+    data.update(fields)
+    return Venue(**data)
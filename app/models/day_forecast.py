@@ -0,0 +1,46 @@
+"""Day and hour forecast data models using Pydantic."""
+from typing import Any, Optional
+from pydantic import BaseModel
+from app.models.live_forecast import VenueInfo
+from app.models.venue import DayInfo
+
+
+class HourAnalysis(BaseModel):
+    """Forecasted intensity of one hour.
+
+    intensity_nr runs from -2 (low) to 2 (high); 999 means closed.
+    """
+    hour: int
+    intensity_txt: str = ""
+    intensity_nr: Optional[int] = None
+
+
+class DayForecastAnalysis(BaseModel):
+    """Analysis block of a single-day forecast."""
+    day_info: Optional[DayInfo] = None
+    day_raw: list[int] = []  # 24 hourly values, starting at 6 AM
+    hour_analysis: list[HourAnalysis] = []
+    busy_hours: list[int] = []
+    quiet_hours: list[int] = []
+    peak_hours: list[Any] = []
+    surge_hours: Optional[Any] = None
+
+
+class DayForecastResponse(BaseModel):
+    """Response from GET /forecasts/day endpoint."""
+    status: str
+    analysis: DayForecastAnalysis
+    venue_info: Optional[VenueInfo] = None
+
+
+class HourForecastAnalysis(BaseModel):
+    """Analysis block of a single-hour forecast."""
+    hour_analysis: HourAnalysis
+    day_info: Optional[DayInfo] = None
+
+
+class HourForecastResponse(BaseModel):
+    """Response from GET /forecasts/hour endpoint."""
+    status: str
+    analysis: HourForecastAnalysis
+    venue_info: Optional[VenueInfo] = None
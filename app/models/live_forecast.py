@@ -1,4 +1,7 @@
 """Live forecast data models using Pydantic."""
+from datetime import datetime
+from typing import Optional
+
 from pydantic import BaseModel
 
 
@@ -28,3 +31,28 @@ class LiveForecastResponse(BaseModel):
     analysis: Analysis
     status: str
     venue_info: VenueInfo
+    # When our refresher fetched this forecast (stamped before caching; not
+    # part of the BestTime payload). None on legacy cached entries.
+    refreshed_at: Optional[datetime] = None
+
+
+class LiveHistoryPoint(BaseModel):
+    """One recorded live busyness sample (the live forecast history ring buffer)."""
+    timestamp: datetime
+    busyness: int
+
+
+class BusynessTrend(BaseModel):
+    """Where a venue's busyness is heading (app/services/busyness_trend.py)."""
+    # "rising", "falling" or "steady"; None when neither history nor the
+    # forecast curve can tell.
+    direction: Optional[str] = None
+    # What direction was judged on: "history" (delta_1h) or "forecast" (this
+    # hour vs the next on the weekly curve).
+    basis: Optional[str] = None
+    # Live busyness now minus the recorded sample nearest an hour ago.
+    delta_1h: Optional[int] = None
+    # Minutes until the highest forecast hour left in the business day (0 =
+    # this hour), and that hour's forecast busyness.
+    peak_in_minutes: Optional[int] = None
+    peak_busyness: Optional[int] = None
@@ -0,0 +1,45 @@
+"""Venue details from open / third-party place directories (OpenStreetMap, Foursquare)."""
+from datetime import datetime
+from typing import Optional
+
+from pydantic import BaseModel, Field
+
+
+class OpenDataCandidate(BaseModel):
+    """One place a directory returned near a venue, before name matching."""
+
+    source: str  # "osm" | "foursquare"
+    source_id: str  # e.g. "node/123" (OSM) or the Foursquare fsq_id
+    name: str
+    lat: float
+    lng: float
+    categories: list[str] = Field(default_factory=list)
+    website: Optional[str] = None
+    phone: Optional[str] = None
+    # OSM `opening_hours` syntax ("Mo-Fr 18:00-02:00") or Foursquare's display text.
+    opening_hours: Optional[str] = None
+    # Image URLs that do not expire: OSM `image` / `wikimedia_commons` tags,
+    # Foursquare photo CDN links.
+    photos: list[str] = Field(default_factory=list)
+
+
+class OpenDataEnrichment(BaseModel):
+    """The directory data matched to a venue (stored on Venue.open_data).
+
+    `source`/`source_id`/`matched_name` identify the primary match; fields it
+    lacks are filled from the next source that matched, and `sources` lists
+    every source that contributed.
+    """
+
+    source: str
+    source_id: str
+    matched_name: str
+    match_distance_m: float
+    match_score: float
+    sources: list[str] = Field(default_factory=list)
+    categories: list[str] = Field(default_factory=list)
+    website: Optional[str] = None
+    phone: Optional[str] = None
+    opening_hours: Optional[str] = None
+    photos: list[str] = Field(default_factory=list)
+    enriched_at: datetime
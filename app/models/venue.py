@@ -3,6 +3,11 @@ from datetime import datetime
 from typing import Optional, Any, Union
 from pydantic import BaseModel, Field, field_validator, ConfigDict
 
+from app.models.open_data import OpenDataEnrichment
+from app.models.venue_media import VenueMedia
+
+MINUTES_PER_DAY = 24 * 60
+
 
 class OpenCloseDetail(BaseModel):
     """Open/close time detail with hour and minute precision.
@@ -67,6 +72,32 @@ class DayInfo(BaseModel):
         else:
             return ""
 
+    def open_windows(self, day_raw: Optional[list[int]] = None) -> Optional[list[tuple[int, int]]]:
+        """The day's opening windows in minutes from its midnight (a window that
+        crosses midnight ends after 1440); [(0, 1440)] when open all day; []
+        when closed all day; None when unknown.
+
+        Args:
+            day_raw: the day's hourly busyness; all zeros also means closed
+        """
+        v2 = self.venue_open_close_v2
+        if v2 is not None and v2.open_24h:
+            return [(0, MINUTES_PER_DAY)]
+        periods = v2.h24 if v2 is not None else []
+        if not periods:
+            # BestTime marks a closed day with venue_open "Closed" and no busyness.
+            if self.venue_open.lower() == "closed" or (day_raw and not any(v > 0 for v in day_raw)):
+                return []
+            return None
+        windows = []
+        for p in periods:
+            opens = p.opens * 60 + (p.opens_minutes or 0)
+            closes = p.closes * 60 + (p.closes_minutes or 0)
+            if closes <= opens:
+                closes += MINUTES_PER_DAY
+            windows.append((opens, closes))
+        return windows
+
 
 class FootTrafficForecast(BaseModel):
     """Forecast data for a specific day with hourly busyness values."""
@@ -146,6 +177,18 @@ class Venue(BaseModel):
     geo_linked: bool = False
     geo_linked_year_month: Optional[str] = None
 
+    # When the refresher last wrote this document from BestTime (discovery or
+    # inventory sync). None for venues not written since it was introduced.
+    refreshed_at: Optional[datetime] = None
+
+    # Categories, website, phone and opening hours matched from OpenStreetMap /
+    # Foursquare by name + coordinates (app/services/open_data_enrichment_service.py).
+    open_data: Optional[OpenDataEnrichment] = None
+
+    # Photo URLs, website and social links, from the media provider or curated
+    # by an operator (app/services/venue_media.py).
+    media: Optional[VenueMedia] = None
+
     model_config = ConfigDict(populate_by_name=True)
 
     def is_deprecated(self) -> bool:
@@ -178,6 +221,43 @@ class VenueWithLive(BaseModel):
     # busyness for 00:00-05:59 under the BestTime 6 AM day anchor. Additive;
     # None when the flag is off or the previous day has no stored forecast.
     weekly_forecast_prev: Optional[Any] = None
+    # Where live_forecast came from: "partner" for a venue-pushed occupancy
+    # reading, "checkins" when recent check-ins stand in for missing live
+    # data, "besttime" otherwise; None when there is no live forecast.
+    live_source: Optional[str] = None
+    # Set instead of an embedded venue_foot_traffic_forecast when
+    # settings.nearby_foot_traffic_forecast is "link" (or past
+    # nearby_forecast_max_venues).
+    forecast_url: Optional[str] = None
+    # True when served from the last-known-good snapshot during a Redis
+    # outage (settings.nearby_snapshot_enabled).
+    stale: Optional[bool] = None
+    # Seconds since the newest refreshed_at of the live forecast and the venue
+    # document; None when neither carries one.
+    data_age_seconds: Optional[int] = None
+    # The venue's operator note when marked public (app/services/venue_notes.py).
+    status_note: Optional[str] = None
+    # Holiday name for the forecast day (settings.holiday_calendar_enabled), else
+    # BestTime's own special_day text for it; None on ordinary days.
+    special_day: Optional[str] = None
+    # BusynessTrend (live_forecast.py) when settings.busyness_trend_enabled and
+    # the venue has live busyness; None otherwise.
+    trend: Optional[Any] = None
+    # Whether the venue is open at request time by its stored BestTime hours,
+    # in its own timezone (app/services/venue_open_hours.py); None = unknown.
+    open_now: Optional[bool] = None
+    # The venue's timezone (BestTime venue_timezone, else America/Recife) and
+    # its local time at request time (settings.nearby_local_time_enabled), so
+    # clients elsewhere can read the local day_int / hour indexes.
+    tz: Optional[str] = None
+    local_time: Optional[datetime] = None
+    # Estimated people at the venue from its fresh live busyness
+    # (app/services/venue_occupancy.py, settings.nearby_occupancy_enabled);
+    # None without fresh live data.
+    estimated_occupancy: Optional[int] = None
+    # 0-100 "best places right now" score (app/services/venue_score.py); set
+    # only when the request sorts by it (?sort=score).
+    score: Optional[float] = None
 
     model_config = ConfigDict(populate_by_name=True)
 
@@ -205,7 +285,21 @@ class MinifiedVenue(BaseModel):
     rating: Optional[float] = None
     reviews: Optional[int] = None
     venue_foot_traffic_forecast: Optional[list[FootTrafficForecast]] = None
+    forecast_url: Optional[str] = None  # See VenueWithLive.forecast_url.
+    stale: Optional[bool] = None  # See VenueWithLive.stale.
+    data_age_seconds: Optional[int] = None  # See VenueWithLive.data_age_seconds.
+    status_note: Optional[str] = None  # See VenueWithLive.status_note.
+    special_day: Optional[str] = None  # See VenueWithLive.special_day.
+    trend: Optional[Any] = None  # See VenueWithLive.trend.
+    open_now: Optional[bool] = None  # See VenueWithLive.open_now.
+    tz: Optional[str] = None  # See VenueWithLive.tz.
+    local_time: Optional[datetime] = None  # See VenueWithLive.local_time.
+    estimated_occupancy: Optional[int] = None  # See VenueWithLive.estimated_occupancy.
+    score: Optional[float] = None  # See VenueWithLive.score.
+    # Venue.media, when settings.nearby_media_enabled.
+    media: Optional[VenueMedia] = None
     venue_live_busyness: Optional[int] = None
+    live_source: Optional[str] = None  # "partner", "checkins" or "besttime" when venue_live_busyness is set
     weekly_forecast: Optional[Any] = None
     # See VenueWithLive.weekly_forecast_prev.
     weekly_forecast_prev: Optional[Any] = None
@@ -239,3 +333,15 @@ class MinifiedVenue(BaseModel):
     venue_menu: Optional[dict] = None  # {sections: [...], currency_detected: str}
 
     model_config = ConfigDict(populate_by_name=True)
+
+
+class ForecastAtTime(BaseModel):
+    """A venue's weekly-forecast busyness at one moment
+    (GET /v1/venues/{venue_id}/forecast/at)."""
+
+    venue_id: str
+    tz: str  # the venue's timezone the moment was converted to
+    local_time: Optional[datetime] = None  # the moment in `tz`; None when asked by day/hour
+    day_int: int  # BestTime day (0=Monday) whose day_raw covers the moment
+    hour_index: int  # index into that day's day_raw (0 = 6 AM)
+    busyness: Optional[int] = None  # None when the day has no stored forecast
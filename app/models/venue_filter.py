@@ -1,6 +1,6 @@
 """Venue filter models for BestTime API."""
-from typing import Optional
-from pydantic import BaseModel
+from typing import Any, Optional
+from pydantic import BaseModel, ConfigDict, Field
 from app.models.venue import DayInfo
 
 
@@ -52,6 +52,11 @@ class VenueFilterResponse(BaseModel):
     venues: list[VenueFilterVenue]
     venues_n: int
     window: Optional[FilterWindow] = None
+    # BestTime's own tool links for this query (e.g. venue_search_progress,
+    # radar tool, filter API), keyed by link name. Absent on most responses.
+    links: Optional[dict[str, Any]] = Field(default=None, alias="_links")
+
+    model_config = ConfigDict(populate_by_name=True)
 
 
 class VenueFilterParams(BaseModel):
@@ -156,3 +161,36 @@ class VenueFilterParams(BaseModel):
             params["page"] = str(self.page)
 
         return params
+
+
+class SearchParams(BaseModel):
+    """What venue discovery asks /venues/filter for at every location.
+
+    Each location supplies lat/lng/radius/limit; these fields supply the rest,
+    so other cities and venue categories can be searched by configuration
+    (settings.discovery_search) instead of code edits. `types` None means the
+    refresher's default VENUE_TYPES.
+    """
+    types: Optional[list[str]] = None
+    busy_min: Optional[int] = 0
+    foot_traffic: Optional[str] = "both"
+    now: Optional[bool] = None  # only venues open now
+    live: Optional[bool] = None  # only venues with live data
+    own_venues_only: Optional[bool] = False
+
+    def to_filter_params(
+        self, lat: float, lng: float, radius: int, limit: int
+    ) -> VenueFilterParams:
+        """The VenueFilterParams for one location."""
+        return VenueFilterParams(
+            lat=lat,
+            lng=lng,
+            radius=radius,
+            limit=limit,
+            types=self.types,
+            busy_min=self.busy_min,
+            foot_traffic=self.foot_traffic,
+            now=self.now,
+            live=self.live,
+            own_venues_only=self.own_venues_only,
+        )
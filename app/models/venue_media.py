@@ -0,0 +1,23 @@
+"""Venue imagery and links (stored on Venue.media, see app/services/venue_media.py)."""
+from datetime import datetime
+from typing import Optional
+
+from pydantic import BaseModel, Field
+
+
+class VenueMedia(BaseModel):
+    """Photo URLs, website and social links of a venue.
+
+    `source` is "admin" for an operator-curated document, which the
+    enrichment provider never overwrites, else the provider's name.
+    """
+
+    photos: list[str] = Field(default_factory=list)
+    website: Optional[str] = None
+    # Network ("instagram", "facebook", "tiktok", ...) -> profile URL.
+    social_links: dict[str, str] = Field(default_factory=dict)
+    source: str
+    updated_at: datetime
+
+    def is_empty(self) -> bool:
+        return not (self.photos or self.website or self.social_links)
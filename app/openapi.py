@@ -0,0 +1,86 @@
+"""OpenAPI document for client teams.
+
+FastAPI builds the document from the routes' request/response models; this
+module adds the tag descriptions and the `python main.py openapi` export, so
+the spec can be generated in CI or handed to a client team without running
+the server. The running server serves it at /openapi.json with Swagger UI at
+/docs (settings.openapi_enabled).
+
+Swagger UI's scripts and styles come from the swagger-ui-bundle package and
+are served by this server under /docs/assets, so the page works without
+reaching a CDN (e.g. behind an egress-restricted network or a strict CSP).
+"""
+from __future__ import annotations
+
+import json
+import sys
+from typing import Optional
+
+from fastapi import FastAPI
+from fastapi.openapi.docs import get_swagger_ui_html
+from fastapi.responses import HTMLResponse
+from fastapi.staticfiles import StaticFiles
+from swagger_ui_bundle import swagger_ui_path
+
+DOCS_URL = "/docs"
+DOCS_ASSETS_URL = "/docs/assets"
+
+# Tag order is the section order in Swagger UI.
+OPENAPI_TAGS = [
+    {
+        "name": "venues",
+        "description": "Nearby venues with live and weekly busyness, per-venue "
+        "forecasts, area crowd indexes and public stats.",
+    },
+    {
+        "name": "engagement",
+        "description": "Favorites, hot likes, visits, app sessions and push "
+        "subscriptions written by the client apps, and the recommendations "
+        "built from them.",
+    },
+    {
+        "name": "webhooks",
+        "description": "Busyness alert webhooks; reads need the webhook's "
+        "`X-Webhook-Secret`.",
+    },
+    {
+        "name": "partners",
+        "description": "Occupancy pushed by venue partners, authenticated with "
+        "`X-Partner-Key`.",
+    },
+    {"name": "ops", "description": "Health check and Prometheus metrics."},
+    {
+        "name": "admin",
+        "description": "Operator endpoints. Network-restricted, not for clients.",
+    },
+    {"name": "internal", "description": "Service-to-service routes. Network-restricted."},
+    {"name": "debug", "description": "Venue data inspection. Network-restricted."},
+]
+
+
+def write_openapi(app, output: Optional[str] = None) -> dict:
+    """Write `app`'s OpenAPI document as JSON to `output` (stdout when None)."""
+    document = app.openapi()
+    text = json.dumps(document, indent=2, ensure_ascii=False) + "\n"
+    if output:
+        with open(output, "w", encoding="utf-8") as f:
+            f.write(text)
+    else:
+        sys.stdout.write(text)
+    return document
+
+
+def install_docs(app: FastAPI) -> None:
+    """Serve Swagger UI at /docs with its bundled assets. Build `app` with
+    docs_url=None and redoc_url=None so FastAPI's CDN pages are not added."""
+    app.mount(DOCS_ASSETS_URL, StaticFiles(directory=swagger_ui_path), name="swagger_ui_assets")
+
+    @app.get(DOCS_URL, include_in_schema=False)
+    async def swagger_ui() -> HTMLResponse:
+        return get_swagger_ui_html(
+            openapi_url=app.openapi_url,
+            title=f"{app.title} - Swagger UI",
+            swagger_js_url=f"{DOCS_ASSETS_URL}/swagger-ui-bundle.js",
+            swagger_css_url=f"{DOCS_ASSETS_URL}/swagger-ui.css",
+            swagger_favicon_url=f"{DOCS_ASSETS_URL}/favicon-32x32.png",
+        )
@@ -1,14 +1,18 @@
 """Routers package."""
-from app.routers.venue_router import router as venue_router, set_venue_handler
+from app.routers.venue_router import router as venue_router, set_venue_handler, set_public_stats_service, set_nearby_precompute, set_area_crowd_index, set_regions, set_checkin_service, set_venue_suggestion_service
 from app.routers.debug_router import router as debug_router, set_debug_dependencies
-from app.routers.admin_trigger_router import router as admin_trigger_router, set_container as set_admin_container
-from app.routers.engagement_router import router as engagement_router, set_engagement_service
+from app.routers.admin_trigger_router import router as admin_trigger_router, set_container as set_admin_container, running_admin_jobs
+from app.routers.engagement_router import router as engagement_router, set_engagement_service, set_push_notifier, set_recommendation_service
 from app.routers.internal_router import router as internal_router, set_container as set_internal_container
+from app.routers.partner_router import router as partner_router, set_partner_service
+from app.routers.webhook_router import router as webhook_router, set_webhook_service
 
 __all__ = [
-    "venue_router", "set_venue_handler",
+    "venue_router", "set_venue_handler", "set_public_stats_service", "set_nearby_precompute", "set_area_crowd_index", "set_regions", "set_checkin_service", "set_venue_suggestion_service",
     "debug_router", "set_debug_dependencies",
-    "admin_trigger_router", "set_admin_container",
-    "engagement_router", "set_engagement_service",
+    "admin_trigger_router", "set_admin_container", "running_admin_jobs",
+    "engagement_router", "set_engagement_service", "set_push_notifier", "set_recommendation_service",
     "internal_router", "set_internal_container",
+    "partner_router", "set_partner_service",
+    "webhook_router", "set_webhook_service",
 ]
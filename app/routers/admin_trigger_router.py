@@ -3,9 +3,11 @@ import asyncio
 import json
 import logging
 import time
+from dataclasses import asdict
+from datetime import date
 from typing import Optional, Union
 
-from fastapi import APIRouter, HTTPException, Body, Query, Response
+from fastapi import APIRouter, HTTPException, Body, Query, Request, Response
 from pydantic import BaseModel, Field
 
 from app.handlers.add_venue_handler import (
@@ -22,10 +24,30 @@ from app.services.venue_eligibility import (
     load_eligibility_config,
     validate_geo_fence,
 )
-from app.services.admin_config_service import AdminConfigService
+from app.services.admin_config_service import (
+    AdminConfigService,
+    AdminConfigVerificationError,
+)
 from app.services.eligibility_rules import EligibilityRuleService
+from app.services.venue_closures import VenueClosuresService
+from app.services.discovery_locations import (
+    DiscoveryLocationsService,
+    DiscoveryPointConflictError,
+)
+from app.services.venue_notes import MAX_NOTE_LENGTH, VenueNotesService
+from app.services.venue_media import VenueMediaService
+from app.services.venue_suggestions import SuggestionStateError
+from app.services.backup_service import BackupNotFoundError
+from app.services.venue_import import (
+    VenueImportBudgetError,
+    VenueImportError,
+    import_venues,
+    parse_import,
+)
 from app.services import job_lock
+from app.dao import redis_migrations
 from app.metrics import JOB_LOCK_REJECTED_TOTAL
+from app.log_control import log_control
 
 logger = logging.getLogger(__name__)
 
@@ -45,6 +67,11 @@ def set_container(container):
     logger.info("[AdminTriggerRouter] Container injected")
 
 
+def running_admin_jobs() -> list[asyncio.Task]:
+    """Admin-triggered job tasks still running (cancelled on shutdown)."""
+    return [task for task in _running_jobs.values() if not task.done()]
+
+
 def require(attr: Optional[str] = None, *, detail: Optional[str] = None):
     """Return a required container attribute (or the container itself when
     ``attr`` is None), raising the same 503s the pasted preambles did.
@@ -110,7 +137,10 @@ JOB_REGISTRY = {
     "live_forecast": {
         "label": "Live Forecast Refresh",
         "description": "Refresh live busyness forecasts for all cached venues",
-        "runner": lambda c, cfg: c.venues_refresher_service.refresh_live_forecasts_for_all_venues(),
+        "default_config": {"include_closed": False},
+        "runner": lambda c, cfg: c.venues_refresher_service.refresh_live_forecasts_for_all_venues(
+            include_closed=cfg.get("include_closed", False)
+        ),
     },
     "weekly_forecast": {
         "label": "Weekly Forecast Refresh",
@@ -142,6 +172,26 @@ JOB_REGISTRY = {
         "unavailable_detail": "Instagram enrichment not configured (missing Apify API token)",
         "runner": lambda c, cfg: c.instagram_enrichment_service.enrich_all_venues(),
     },
+    "open_data": {
+        "label": "Open Data Enrichment",
+        "description": "Match venues to OpenStreetMap / Foursquare places for categories, website, phone and opening hours",
+        "default_config": {"force_refresh": False},
+        "service_attr": "open_data_enrichment_service",
+        "unavailable_detail": "Open data enrichment not configured (no source)",
+        "runner": lambda c, cfg: c.open_data_enrichment_service.enrich_all_venues(
+            force_refresh=cfg.get("force_refresh", False)
+        ),
+    },
+    "venue_media": {
+        "label": "Venue Media Enrichment",
+        "description": "Fill venue photos, website and social links from the configured media provider",
+        "default_config": {"force_refresh": False},
+        "service_attr": "venue_media_service",
+        "unavailable_detail": "Venue media not enabled",
+        "runner": lambda c, cfg: c.venue_media_service.enrich_all_venues(
+            force_refresh=cfg.get("force_refresh", False)
+        ),
+    },
     "instagram_posts": {
         "label": "Instagram Posts Scraping",
         "description": "Scrape recent Instagram posts for venues with IG handles",
@@ -182,6 +232,47 @@ JOB_REGISTRY = {
         "description": "Pull every venue in our BestTime account inventory into Redis. Free — does not spend the monthly new-venue budget.",
         "runner": lambda c, cfg: c.venues_refresher_service.sync_account_inventory_to_redis(),
     },
+    "stale_eviction": {
+        "label": "Stale Data Eviction",
+        "description": "Soft-delete venues not refreshed for stale_venue_max_age_days and delete "
+        "live forecasts older than stale_live_forecast_max_age_minutes. dry_run only reports.",
+        "default_config": {"dry_run": True},
+        "runner": lambda c, cfg: asyncio.to_thread(
+            c.stale_eviction_service.run, dry_run=cfg.get("dry_run")
+        ),
+    },
+    "venue_dedup": {
+        "label": "Duplicate Venue Cleanup",
+        "description": "Soft-delete venues that duplicate a nearby venue with a similar name, "
+        "keeping the most reviewed one. dry_run only reports.",
+        "default_config": {"dry_run": True},
+        "runner": lambda c, cfg: asyncio.to_thread(
+            c.venue_deduplicator.run, dry_run=cfg.get("dry_run")
+        ),
+    },
+    "retry_queue": {
+        "label": "Retry Queue Worker",
+        "description": "Retry the due venue upserts and live forecast fetches that failed in earlier refreshes",
+        "service_attr": "retry_queue",
+        "unavailable_detail": "Retry queue not enabled",
+        "runner": lambda c, cfg: c.venues_refresher_service.process_retry_queue(
+            limit=c.settings.retry_queue_batch_size
+        ),
+    },
+    "backup": {
+        "label": "Venue Data Backup",
+        "description": "Write a snapshot of every venue and forecast in Redis to the backup bucket now",
+        "service_attr": "backup_service",
+        "unavailable_detail": "Backups not enabled",
+        "runner": lambda c, cfg: c.backup_service.backup(),
+    },
+    "event_outbox": {
+        "label": "Event Outbox Publisher",
+        "description": "Publish the venue events waiting in the outbox to the message broker now",
+        "service_attr": "event_relay",
+        "unavailable_detail": "Event broker not configured",
+        "runner": lambda c, cfg: c.event_relay.drain(),
+    },
     "rebuild_redis": {
         "label": "Rebuild Redis from RDS",
         "description": "Reconstruct the Redis serving projection (incl. the geo index and live busyness) from RDS. Disaster recovery / Redis warm.",
@@ -238,11 +329,114 @@ async def list_jobs():
             "available": available,
             "running": running,
             "default_config": info.get("default_config"),
+            "lock": job_lock.holder(name) if name in job_lock.LOCKED_JOB_NAMES else None,
         })
 
     return {"jobs": jobs}
 
 
+# ── scheduled background jobs (APScheduler) ──────────────────────────────────
+def _scheduler_control():
+    return require("scheduler_control", detail="scheduler not running")
+
+
+@router.get("/scheduler/jobs")
+async def list_scheduled_jobs():
+    """Every scheduled job with its trigger, next run (none while paused),
+    whether a run is in flight, and the last run's start, outcome, duration
+    and error."""
+    control = _scheduler_control()
+    return {"paused": control.paused, "jobs": control.list_jobs()}
+
+
+@router.get("/scheduler/jobs/{job_id}")
+async def get_scheduled_job(job_id: str):
+    job = _scheduler_control().get_job(job_id)
+    if job is None:
+        raise HTTPException(status_code=404, detail=f"no scheduled job {job_id}")
+    return job
+
+
+@router.post("/scheduler/jobs/{job_id}/pause")
+async def pause_scheduled_job(job_id: str):
+    """Stop scheduling a job until resumed (a run in flight finishes)."""
+    job = _scheduler_control().pause_job(job_id)
+    if job is None:
+        raise HTTPException(status_code=404, detail=f"no scheduled job {job_id}")
+    return job
+
+
+@router.post("/scheduler/jobs/{job_id}/resume")
+async def resume_scheduled_job(job_id: str):
+    job = _scheduler_control().resume_job(job_id)
+    if job is None:
+        raise HTTPException(status_code=404, detail=f"no scheduled job {job_id}")
+    return job
+
+
+@router.post("/scheduler/jobs/{job_id}/run")
+async def run_scheduled_job_now(job_id: str):
+    """Run a scheduled job now, outside its schedule (409 while one runs)."""
+    try:
+        job = _scheduler_control().run_now(job_id)
+    except RuntimeError as e:
+        raise HTTPException(status_code=409, detail=str(e))
+    if job is None:
+        raise HTTPException(status_code=404, detail=f"no scheduled job {job_id}")
+    return job
+
+
+@router.post("/scheduler/jobs/{job_id}/stop")
+async def stop_scheduled_job_run(job_id: str):
+    """Cancel the run of a job in flight (404 when none is running)."""
+    if not _scheduler_control().stop_run(job_id):
+        raise HTTPException(status_code=404, detail=f"{job_id} is not running")
+    return {"status": "cancelled", "job": job_id}
+
+
+@router.post("/scheduler/pause")
+async def pause_scheduler():
+    """Hold every scheduled job until /scheduler/resume (or a restart)."""
+    control = _scheduler_control()
+    control.pause_all()
+    return {"paused": control.paused}
+
+
+@router.post("/scheduler/resume")
+async def resume_scheduler():
+    control = _scheduler_control()
+    control.resume_all()
+    return {"paused": control.paused}
+
+
+@router.get("/jobs/venue_catalog/besttime-links")
+async def get_venue_catalog_besttime_links():
+    """BestTime `_links` (venue_search_progress, radar tool, filter API, ...)
+    captured from recent discovery calls, newest first, each with the query
+    that produced it — lets operators jump to BestTime's own tools when
+    debugging a specific refresh run."""
+    refresher = require("venues_refresher_service", detail="venues refresher not configured")
+    return {"job": "venue_catalog", "runs": refresher.get_besttime_links()}
+
+
+@router.get("/venues/filter-tuning")
+async def get_filter_tuning():
+    """Per-region filter-profile bandit statistics: pulls and mean score
+    (live-data venues per credit) of every radius/busy_min/limit arm tried,
+    and the arm each region currently favours."""
+    tuner = require("filter_tuner", detail="filter tuner not enabled")
+    return {"epsilon": tuner.epsilon, "regions": tuner.stats()}
+
+
+@router.delete("/venues/filter-tuning")
+async def reset_filter_tuning(region: Optional[str] = None):
+    """Forget the tuning statistics of one region (or all) so they are
+    re-learned from scratch."""
+    tuner = require("filter_tuner", detail="filter tuner not enabled")
+    tuner.reset(region)
+    return {"status": "reset", "region": region}
+
+
 @router.post("/trigger/{job_name}")
 async def trigger_job(job_name: str, config: Optional[dict] = None):
     """Trigger an enrichment job to run in the background.
@@ -298,6 +492,8 @@ async def trigger_job(job_name: str, config: Optional[dict] = None):
 
     task = asyncio.create_task(_wrapper())
     _running_jobs[job_name] = task
+    if locked:
+        job_lock.bind_task(job_name, task)
 
     return TriggerResponse(
         status="started",
@@ -307,16 +503,31 @@ async def trigger_job(job_name: str, config: Optional[dict] = None):
 
 
 @router.post("/venues/by-address")
-async def add_venue_by_address(request: AddVenueByAddressRequest, response: Response):
+async def add_venue_by_address(
+    request: AddVenueByAddressRequest,
+    response: Response,
+    verify: bool = Query(False),
+):
     """Register a venue in our BestTime account inventory by name + address.
 
     Body: AddVenueByAddressRequest. See app/handlers/add_venue_handler.py for
     the full status-code matrix.
+
+    ``?verify=true`` reads the venue back from RDS before a 200/201 is returned
+    (502 when it is missing). The venue reaches nearby results
+    with the next projection, like every RDS venue write.
     """
     handler: AddVenueHandler = require(
         "add_venue_handler", detail="add-venue handler not configured"
     )
     outcome = await handler.add(request)
+    venue_id = outcome.body.get("venue_id") if outcome.status_code in (200, 201) else None
+    if verify and venue_id:
+        venue = handler.venue_dao.get_venue(venue_id)
+        if venue is None:
+            logger.error(f"[AdminTrigger] read-back failed for added venue {venue_id}")
+            raise HTTPException(status_code=502, detail=f"venue {venue_id} not visible yet; retry")
+        outcome.body["verified"] = True
     response.status_code = outcome.status_code
     return outcome.body
 
@@ -331,6 +542,9 @@ async def batch_add_venues(request: BatchAddRequest, response: Response):
     pollable summary. Returns immediately with a job_id — poll
     GET /venues/batch-add/{job_id} for progress + the final per-row results.
     See app/services/batch_add_service.py.
+
+    There is no ``?verify``: nothing is written before the response, and the
+    per-row results in the job document are the read-back.
     """
     service = require("batch_add_service", detail="batch-add service not configured")
     accepted = service.start_job(request)
@@ -342,6 +556,167 @@ async def batch_add_venues(request: BatchAddRequest, response: Response):
     return accepted
 
 
+@router.get("/backups")
+async def list_backups():
+    """Venue data snapshots in the backup bucket, oldest first."""
+    service = require("backup_service", detail="Backups not enabled")
+    keys = await service.list_snapshots()
+    return {"count": len(keys), "snapshots": keys}
+
+
+class RestoreBackupRequest(BaseModel):
+    key: Optional[str] = Field(None, description="Snapshot key; the newest when omitted")
+    dry_run: bool = False
+
+
+@router.post("/backups/restore")
+async def restore_backup(request: Optional[RestoreBackupRequest] = None):
+    """Write a snapshot back to RDS (venues, live and weekly forecasts), then
+    project it into Redis.
+
+    The whole snapshot is validated before anything is written; dry_run stops
+    there and returns the counts. See app/services/backup_service.py.
+    """
+    service = require("backup_service", detail="Backups not enabled")
+    request = request or RestoreBackupRequest()
+    try:
+        return await service.restore(request.key, dry_run=request.dry_run)
+    except BackupNotFoundError as e:
+        raise HTTPException(status_code=404, detail=str(e))
+    except ValueError as e:
+        raise HTTPException(status_code=422, detail=str(e))
+
+
+# Largest POST /venues/import body read (bytes).
+MAX_IMPORT_BYTES = 20 * 1024 * 1024
+
+
+@router.post("/venues/import")
+async def import_venue_catalog(
+    request: Request,
+    dry_run: bool = Query(False, description="Validate only; write nothing"),
+):
+    """Bulk-upsert an external venue catalog without BestTime discovery.
+
+    Body: JSON (a list of venues, or {"venues", "live_forecasts",
+    "weekly_forecasts"}) or CSV with `Content-Type: text/csv`. The whole
+    payload is validated first; any problem is a 422 listing every error and
+    nothing is written. Venues new to the catalog are charged to the monthly
+    new-venue budget; an import that does not fit is a 429 and writes
+    nothing. See app/services/venue_import.py.
+    """
+    container = require()
+    body = await request.body()
+    if len(body) > MAX_IMPORT_BYTES:
+        raise HTTPException(status_code=413, detail=f"import is larger than {MAX_IMPORT_BYTES} bytes")
+    try:
+        fixture = parse_import(body, request.headers.get("content-type", ""))
+    except VenueImportError as e:
+        raise HTTPException(status_code=422, detail={"errors": e.errors})
+    if dry_run:
+        return {
+            "dry_run": True,
+            "venues": len(fixture.venues),
+            "live_forecasts": len(fixture.live_forecasts),
+            "weekly_forecasts": len(fixture.weekly_forecasts),
+        }
+    try:
+        summary = await asyncio.to_thread(
+            import_venues,
+            container.pipeline_repository,
+            fixture,
+            container.event_outbox,
+            budget_service=container.venue_budget_service,
+        )
+    except VenueImportBudgetError as e:
+        raise HTTPException(status_code=429, detail={"errors": e.errors})
+    return {"dry_run": False, **summary}
+
+
+@router.get("/venues/quarantine")
+async def list_venue_quarantine(
+    limit: int = Query(100, ge=1, le=1000),
+    outcome: Optional[str] = Query(None, pattern="^(rejected|flagged)$"),
+):
+    """Venues the refresher's validation rejected (not written) or flagged
+    (written without an out-of-bounds field), newest first, with their
+    problems and the record as received. See app/services/venue_validation.py."""
+    quarantine = require("venue_quarantine")
+    entries = await asyncio.to_thread(quarantine.entries, limit, outcome)
+    return {
+        "count": await asyncio.to_thread(quarantine.count),
+        "entries": [asdict(e) for e in entries],
+    }
+
+
+@router.delete("/venues/quarantine/{venue_id}")
+async def release_quarantined_venue(venue_id: str):
+    """Drop one quarantine entry (404 when there is none). The venue is not
+    written; the next refresh that returns it valid does that."""
+    quarantine = require("venue_quarantine")
+    if not await asyncio.to_thread(quarantine.remove, venue_id):
+        raise HTTPException(status_code=404, detail=f"{venue_id} is not quarantined")
+    return {"status": "ok", "venue_id": venue_id}
+
+
+@router.delete("/venues/quarantine")
+async def clear_venue_quarantine():
+    """Drop every quarantine entry."""
+    quarantine = require("venue_quarantine")
+    return {"status": "ok", "cleared": await asyncio.to_thread(quarantine.clear)}
+
+
+class SuggestionRejectRequest(BaseModel):
+    reason: Optional[str] = Field(None, max_length=1000)
+
+
+@router.get("/venues/suggestions")
+async def list_venue_suggestions(
+    status: Optional[str] = Query("pending", pattern="^(pending|approved|rejected)$"),
+    limit: int = Query(100, ge=1, le=1000),
+):
+    """User-submitted venue suggestions, oldest first (pending by default).
+    See app/services/venue_suggestions.py."""
+    service = require("venue_suggestion_service", detail="venue suggestions not enabled")
+    entries = await asyncio.to_thread(service.entries, status, limit)
+    return {
+        "pending": await asyncio.to_thread(service.pending_count),
+        "entries": [e.model_dump() for e in entries],
+    }
+
+
+@router.post("/venues/suggestions/{suggestion_id}/approve")
+async def approve_venue_suggestion(suggestion_id: str, response: Response):
+    """Add the suggested venue through the POST /venues/by-address flow. The
+    add's status code and body are returned as is, with the suggestion; it
+    stays pending unless the venue was created or already known."""
+    service = require("venue_suggestion_service", detail="venue suggestions not enabled")
+    try:
+        suggestion, outcome = await service.approve(suggestion_id)
+    except SuggestionStateError as e:
+        raise HTTPException(status_code=409, detail=str(e))
+    if suggestion is None:
+        raise HTTPException(status_code=404, detail=f"suggestion {suggestion_id} not found")
+    response.status_code = outcome.status_code
+    return {**outcome.body, "suggestion": suggestion.model_dump()}
+
+
+@router.post("/venues/suggestions/{suggestion_id}/reject")
+async def reject_venue_suggestion(
+    suggestion_id: str, request: Optional[SuggestionRejectRequest] = None
+):
+    """Reject a pending suggestion, with an optional reason."""
+    service = require("venue_suggestion_service", detail="venue suggestions not enabled")
+    reason = request.reason if request is not None else None
+    try:
+        suggestion = await asyncio.to_thread(service.reject, suggestion_id, reason)
+    except SuggestionStateError as e:
+        raise HTTPException(status_code=409, detail=str(e))
+    if suggestion is None:
+        raise HTTPException(status_code=404, detail=f"suggestion {suggestion_id} not found")
+    return {"status": "ok", "suggestion": suggestion.model_dump()}
+
+
 @router.get("/venues/batch-add/{job_id}")
 async def get_batch_add_job(job_id: str):
     """Poll a batch-add job: {status, processed, total, summary, results, budget}."""
@@ -417,7 +792,7 @@ async def get_eligibility_config():
 
 
 @router.post("/venues/eligibility-config")
-async def update_eligibility_config(config: dict = Body(...)):
+async def update_eligibility_config(config: dict = Body(...), verify: bool = Query(False)):
     """Update the venue-eligibility block-lists (admin-tunable, no redeploy).
 
     Validates that each provided field is a list of strings, persists the
@@ -427,6 +802,10 @@ async def update_eligibility_config(config: dict = Body(...)):
     active config is left unchanged. Falls back to direct Redis when no admin
     config service is wired (preserves today's behavior).
 
+    ``?verify=true`` reads the Redis mirror (and the RDS row, when one is
+    written) back before responding, as on PUT /config/{key}; a mismatch
+    returns 502. Not offered on the direct-Redis fallback.
+
     Note: tightening the blocked lists causes the next eligibility sweep to
     soft-delete more venues, which is one-way in V1 (no restore).
     """
@@ -440,7 +819,10 @@ async def update_eligibility_config(config: dict = Body(...)):
     rule_svc = _eligibility_rule_service()
     if rule_svc is not None:
         try:
-            rule_svc.set_full_config(config, updated_by="admin")
+            rule_svc.set_full_config(config, updated_by="admin", verify=verify)
+        except AdminConfigVerificationError as e:
+            logger.error(f"[AdminTrigger] eligibility config read-back failed: {e}")
+            raise HTTPException(status_code=502, detail="eligibility config not visible yet; retry")
         except Exception as e:
             logger.error(f"[AdminTrigger] Failed to persist eligibility rules to RDS: {e}")
             raise HTTPException(status_code=502, detail="failed to persist eligibility config; retry")
@@ -451,7 +833,10 @@ async def update_eligibility_config(config: dict = Body(...)):
         # RDS (truth) + Redis mirror. Input is already validated above, so any
         # exception from set() is an RDS/mirror failure -> 502 (retryable).
         try:
-            svc.set("venue_eligibility", config, updated_by="admin")
+            svc.set("venue_eligibility", config, updated_by="admin", verify=verify)
+        except AdminConfigVerificationError as e:
+            logger.error(f"[AdminTrigger] eligibility config read-back failed: {e}")
+            raise HTTPException(status_code=502, detail="eligibility config not visible yet; retry")
         except Exception as e:
             logger.error(f"[AdminTrigger] Failed to persist eligibility config to RDS: {e}")
             raise HTTPException(
@@ -481,14 +866,20 @@ def _require_eligibility_rule_service() -> EligibilityRuleService:
 
 
 @router.post("/venues/eligibility-rule")
-async def add_eligibility_rule(body: dict = Body(...)):
+async def add_eligibility_rule(body: dict = Body(...), verify: bool = Query(False)):
     """Add ONE eligibility rule (rule_type + value) as a single row, then
-    reassemble the Redis mirror. Returns the resulting active config."""
+    reassemble the Redis mirror. Returns the resulting active config.
+    ``?verify=true`` reads the mirror back first (502 on a mismatch)."""
     svc = _require_eligibility_rule_service()
     try:
-        cfg = svc.add_rule(body.get("rule_type"), body.get("value"), updated_by="admin")
+        cfg = svc.add_rule(
+            body.get("rule_type"), body.get("value"), updated_by="admin", verify=verify
+        )
     except (ValueError, TypeError) as e:
         raise HTTPException(status_code=400, detail=f"invalid eligibility rule: {e}")
+    except AdminConfigVerificationError as e:
+        logger.error(f"[AdminTrigger] eligibility rule read-back failed: {e}")
+        raise HTTPException(status_code=502, detail="eligibility rule not visible yet; retry")
     except Exception as e:
         logger.error(f"[AdminTrigger] Failed to add eligibility rule: {e}")
         raise HTTPException(status_code=502, detail="failed to persist eligibility rule; retry")
@@ -497,15 +888,19 @@ async def add_eligibility_rule(body: dict = Body(...)):
 
 @router.delete("/venues/eligibility-rule")
 async def remove_eligibility_rule(
-    rule_type: str = Query(...), value: str = Query(...)
+    rule_type: str = Query(...), value: str = Query(...), verify: bool = Query(False)
 ):
     """Remove ONE eligibility rule (rule_type + value), then reassemble the
-    mirror. Removing the last rule drops the override (readers use defaults)."""
+    mirror. Removing the last rule drops the override (readers use defaults).
+    ``?verify=true`` reads the mirror back first (502 on a mismatch)."""
     svc = _require_eligibility_rule_service()
     try:
-        cfg = svc.remove_rule(rule_type, value, updated_by="admin")
+        cfg = svc.remove_rule(rule_type, value, updated_by="admin", verify=verify)
     except (ValueError, TypeError) as e:
         raise HTTPException(status_code=400, detail=f"invalid eligibility rule: {e}")
+    except AdminConfigVerificationError as e:
+        logger.error(f"[AdminTrigger] eligibility rule read-back failed: {e}")
+        raise HTTPException(status_code=502, detail="eligibility rule not visible yet; retry")
     except Exception as e:
         logger.error(f"[AdminTrigger] Failed to remove eligibility rule: {e}")
         raise HTTPException(status_code=502, detail="failed to remove eligibility rule; retry")
@@ -574,20 +969,39 @@ async def list_geo_fence_capitals():
     )}
 
 
+def _same_geo_fence(a: dict, b: dict) -> bool:
+    """Whether two fences have the same enabled flag and circles."""
+    def circles(fence):
+        return sorted((c["slug"], float(c["radius_km"])) for c in fence.get("cities", []))
+    return bool(a.get("enabled")) == bool(b.get("enabled")) and circles(a) == circles(b)
+
+
 @router.put("/config/geofence")
-async def put_geo_fence(fence: dict = Body(...)):
+async def put_geo_fence(fence: dict = Body(...), verify: bool = Query(False)):
     """Replace the geo-fence (admin-tunable, no redeploy): full-list
     {"enabled": bool, "cities": [{"slug", "radius_km"}]}, slug resolved to
     catalog coordinates server-side. Rejects with HTTP 400 — fence unchanged —
     on an unknown/duplicate slug, an out-of-[1,200] radius, `enabled` true with
-    zero cities, or a legacy bounding-box payload. Writes the typed geo-fence
+    zero cities, a legacy bounding-box payload, or a fence that leaves a
+    configured region outside. Writes the typed geo-fence
     tables transactionally (the SQL serving view reads them), then mirrors
     admin_config:venue_geofence in Redis for admin/parity reads. The next
-    projection re-includes/excludes venues accordingly (reversible)."""
+    projection re-includes/excludes venues accordingly (reversible).
+
+    ``?verify=true`` reads the fence back from RDS before responding; a
+    mismatch returns 502."""
     try:
         validated = validate_geo_fence(fence)
     except (ValueError, TypeError) as e:
         raise HTTPException(status_code=400, detail=f"invalid geo-fence: {e}")
+    # Every region must stay inside the fence (app/services/regions.py).
+    regions = getattr(_container, "regions", None)
+    outside = regions.outside_fence(validated) if regions else []
+    if outside:
+        raise HTTPException(
+            status_code=400,
+            detail=f"invalid geo-fence: leaves regions {', '.join(outside)} outside",
+        )
 
     store = _geo_fence_store()
     try:
@@ -595,6 +1009,9 @@ async def put_geo_fence(fence: dict = Body(...)):
     except Exception as e:
         logger.error(f"[AdminGeoFence] persist to RDS failed: {e}")
         raise HTTPException(status_code=502, detail="failed to persist geo-fence; retry")
+    if verify and not _same_geo_fence(store.get_geo_fence(), validated):
+        logger.error("[AdminGeoFence] read-back did not match the written fence")
+        raise HTTPException(status_code=502, detail="geo-fence not visible yet; retry")
     logger.info(
         "[AdminGeoFence] fence updated by=admin enabled=%s cities=%s",
         validated["enabled"],
@@ -611,7 +1028,42 @@ async def put_geo_fence(fence: dict = Body(...)):
         except Exception as e:
             logger.warning(f"[AdminGeoFence] Redis mirror write failed: {e}")
 
-    return {**validated, "geo_excluded_active": _geo_excluded_active_count(store)}
+    body = {**validated, "geo_excluded_active": _geo_excluded_active_count(store)}
+    if verify:
+        body["verified"] = True
+    return body
+
+
+# ── reloadable settings (app/services/runtime_config.py) ─────────────────────
+def _runtime_config():
+    return require("runtime_config", detail="runtime config not available yet")
+
+
+@router.get("/runtime-config")
+async def get_runtime_config():
+    """The reloadable settings, their running values and sources (startup,
+    file or admin)."""
+    return _runtime_config().snapshot()
+
+
+@router.put("/runtime-config")
+async def put_runtime_config(changes: dict = Body(...)):
+    """Change reloadable settings on every replica (Redis overrides), applied
+    here at once and elsewhere within config_reload_seconds. All-or-nothing:
+    one invalid field rejects the request with 400."""
+    try:
+        return _runtime_config().update(changes)
+    except (ValueError, TypeError) as e:
+        raise HTTPException(status_code=400, detail=str(e))
+
+
+@router.delete("/runtime-config/{field}")
+async def reset_runtime_config(field: str):
+    """Drop the override of one field (back to its file or startup value)."""
+    try:
+        return _runtime_config().reset(field)
+    except ValueError as e:
+        raise HTTPException(status_code=404, detail=str(e))
 
 
 # ── generic admin config (RDS system of record, Redis mirror) ────────────────
@@ -635,7 +1087,11 @@ async def get_admin_config(key: str):
 
 
 @router.put("/config/{key}")
-async def put_admin_config(key: str, value: Union[dict, list] = Body(...)):
+async def put_admin_config(
+    key: str,
+    value: Union[dict, list] = Body(...),
+    verify: bool = Query(False),
+):
     """Write a config key to RDS (truth) then mirror Redis. Per-key validation
     runs before any write; a failed mirror after the RDS commit returns 502 so
     the caller retries (idempotent).
@@ -643,15 +1099,25 @@ async def put_admin_config(key: str, value: Union[dict, list] = Body(...)):
     Accepts a JSON object OR array: most config keys are objects, but a few are
     list-valued (notably ``vibe_modes``, an ordered array of mode configs). The
     storage layer (RDS ``jsonb`` + the ``json.dumps`` Redis mirror) handles both,
-    so the HTTP boundary must not reject a top-level array."""
+    so the HTTP boundary must not reject a top-level array.
+
+    ``?verify=true`` is the read-your-writes option: RDS and the Redis mirror
+    are read back before responding, so a 200 guarantees the very next read
+    (and every nearby query that consults the config) sees the new value. A
+    read-back mismatch returns 502 like any other failed write."""
     svc = _admin_config_service()
     try:
-        stored = svc.set(key, value, updated_by="admin")
+        stored = svc.set(key, value, updated_by="admin", verify=verify)
     except (ValueError, TypeError) as e:
         raise HTTPException(status_code=400, detail=f"invalid config for {key}: {e}")
+    except AdminConfigVerificationError as e:
+        logger.error(f"[AdminConfig] read-back failed for {key}: {e}")
+        raise HTTPException(status_code=502, detail=f"config write for {key} not visible yet; retry")
     except Exception as e:
         logger.error(f"[AdminConfig] write failed for {key}: {e}")
         raise HTTPException(status_code=502, detail=f"config write failed for {key}; retry")
+    if verify:
+        return {"key": key, "value": stored, "verified": True}
     return {"key": key, "value": stored}
 
 
@@ -753,6 +1219,7 @@ def list_venue_inventory(
         cache_flags_by_id = _venue_cache_flags_bulk(
             venue_dao, [venue.venue_id for venue in page]
         )
+        notes = _venue_notes_for_listing()
 
         return {
             "items": [
@@ -770,6 +1237,7 @@ def list_venue_inventory(
                     ),
                     "google_business_status": venue.google_business_status,
                     "cache_flags": cache_flags_by_id.get(venue.venue_id, {}),
+                    "note": notes.get(venue.venue_id),
                 }
                 for venue in page
             ],
@@ -788,6 +1256,189 @@ def list_venue_inventory(
         raise HTTPException(status_code=500, detail="venue inventory listing failed")
 
 
+def _venue_notes_for_listing() -> dict:
+    """All operator notes for the inventory page; {} when notes are not wired
+    or unreadable (the listing must not fail over them)."""
+    service = getattr(_container, "venue_notes_service", None)
+    if service is None:
+        return {}
+    try:
+        return service.list_notes()
+    except Exception as e:
+        logger.warning(f"[AdminTrigger] Venue notes unavailable for inventory: {e}")
+        return {}
+
+
+class VenueNoteRequest(BaseModel):
+    note: str = Field(..., min_length=1, max_length=MAX_NOTE_LENGTH)
+    public: bool = False
+
+
+def _venue_notes_service() -> VenueNotesService:
+    return require("venue_notes_service", detail="venue notes not configured")
+
+
+@router.get("/venues/notes")
+async def list_venue_notes():
+    """Every operator note, keyed by venue id."""
+    try:
+        return {"notes": _venue_notes_service().list_notes()}
+    except HTTPException:
+        raise
+    except Exception as e:
+        logger.error(f"[AdminTrigger] Venue notes listing failed: {e}")
+        raise HTTPException(status_code=502, detail="venue notes listing failed; retry")
+
+
+@router.put("/venues/{venue_id}/note")
+async def put_venue_note(venue_id: str, request: VenueNoteRequest, verify: bool = Query(False)):
+    """Create or replace a venue's operator note. `public` notes are also
+    served as the venue's `status_note` in nearby responses. ``?verify=true``
+    reads the notes back from RDS and the Redis mirror first, so the next
+    nearby response carries the note (502 on a mismatch)."""
+    service = _venue_notes_service()
+    try:
+        note = service.set_note(
+            venue_id, request.note, request.public, updated_by="admin", verify=verify
+        )
+    except (ValueError, TypeError) as e:
+        raise HTTPException(status_code=400, detail=f"invalid note: {e}")
+    except AdminConfigVerificationError as e:
+        logger.error(f"[AdminTrigger] Venue note read-back failed for {venue_id}: {e}")
+        raise HTTPException(status_code=502, detail=f"note for {venue_id} not visible yet; retry")
+    except Exception as e:
+        logger.error(f"[AdminTrigger] Venue note write failed for {venue_id}: {e}")
+        raise HTTPException(status_code=502, detail=f"note write failed for {venue_id}; retry")
+    if verify:
+        return {"venue_id": venue_id, **note, "verified": True}
+    return {"venue_id": venue_id, **note}
+
+
+@router.delete("/venues/{venue_id}/note")
+async def delete_venue_note(venue_id: str, verify: bool = Query(False)):
+    """Remove a venue's operator note (404 when it has none). ``?verify=true``
+    as on PUT."""
+    service = _venue_notes_service()
+    try:
+        deleted = service.delete_note(venue_id, verify=verify)
+    except AdminConfigVerificationError as e:
+        logger.error(f"[AdminTrigger] Venue note read-back failed for {venue_id}: {e}")
+        raise HTTPException(status_code=502, detail=f"note delete for {venue_id} not visible yet; retry")
+    except Exception as e:
+        logger.error(f"[AdminTrigger] Venue note delete failed for {venue_id}: {e}")
+        raise HTTPException(status_code=502, detail=f"note delete failed for {venue_id}; retry")
+    if not deleted:
+        raise HTTPException(status_code=404, detail=f"no note for {venue_id}")
+    return {"status": "ok", "venue_id": venue_id}
+
+
+class VenueMediaRequest(BaseModel):
+    photos: list[str] = Field(default_factory=list)
+    website: Optional[str] = None
+    social_links: dict[str, str] = Field(default_factory=dict)
+
+
+def _venue_media_service() -> VenueMediaService:
+    return require("venue_media_service", detail="venue media not enabled")
+
+
+@router.get("/venues/{venue_id}/media")
+async def get_venue_media(venue_id: str):
+    """A venue's photos, website and social links (`media` is null when it
+    has none yet)."""
+    service = _venue_media_service()
+    try:
+        found, media = service.get_media(venue_id)
+    except Exception as e:
+        logger.error(f"[AdminTrigger] Venue media read failed for {venue_id}: {e}")
+        raise HTTPException(status_code=502, detail=f"media read failed for {venue_id}; retry")
+    if not found:
+        raise HTTPException(status_code=404, detail=f"venue {venue_id} not found")
+    return {"venue_id": venue_id, "media": media}
+
+
+@router.put("/venues/{venue_id}/media")
+async def put_venue_media(venue_id: str, request: VenueMediaRequest):
+    """Replace a venue's media with a curated document; the media provider
+    never overwrites it (see app/services/venue_media.py)."""
+    service = _venue_media_service()
+    try:
+        media = service.set_media(venue_id, request.photos, request.website, request.social_links)
+    except ValueError as e:
+        raise HTTPException(status_code=400, detail=f"invalid media: {e}")
+    except Exception as e:
+        logger.error(f"[AdminTrigger] Venue media write failed for {venue_id}: {e}")
+        raise HTTPException(status_code=502, detail=f"media write failed for {venue_id}; retry")
+    if media is None:
+        raise HTTPException(status_code=404, detail=f"venue {venue_id} not found")
+    return {"venue_id": venue_id, "media": media}
+
+
+@router.delete("/venues/{venue_id}/media")
+async def delete_venue_media(venue_id: str):
+    """Drop a venue's media, handing it back to the media provider."""
+    service = _venue_media_service()
+    try:
+        found = service.clear_media(venue_id)
+    except Exception as e:
+        logger.error(f"[AdminTrigger] Venue media delete failed for {venue_id}: {e}")
+        raise HTTPException(status_code=502, detail=f"media delete failed for {venue_id}; retry")
+    if not found:
+        raise HTTPException(status_code=404, detail=f"venue {venue_id} not found")
+    return {"status": "ok", "venue_id": venue_id}
+
+
+class VenueClosureRequest(BaseModel):
+    start: date
+    end: date  # inclusive
+    reason: Optional[str] = Field(default=None, max_length=200)
+
+
+def _venue_closures_service() -> VenueClosuresService:
+    return require("venue_closures_service", detail="venue closures not configured")
+
+
+@router.get("/venues/closures")
+async def list_venue_closures():
+    """Every scheduled or current closure window, keyed by venue id."""
+    try:
+        return {"closures": _venue_closures_service().list_closures()}
+    except HTTPException:
+        raise
+    except Exception as e:
+        logger.error(f"[AdminTrigger] Venue closures listing failed: {e}")
+        raise HTTPException(status_code=502, detail="venue closures listing failed; retry")
+
+
+@router.put("/venues/{venue_id}/closure")
+async def put_venue_closure(venue_id: str, request: VenueClosureRequest):
+    """Mark a venue temporarily closed from `start` to `end` (inclusive, Recife
+    days): it leaves nearby results and refreshes for the window, then resumes."""
+    service = _venue_closures_service()
+    try:
+        closure = service.set_closure(venue_id, request.start, request.end, request.reason)
+    except (ValueError, TypeError) as e:
+        raise HTTPException(status_code=400, detail=f"invalid closure: {e}")
+    except Exception as e:
+        logger.error(f"[AdminTrigger] Venue closure write failed for {venue_id}: {e}")
+        raise HTTPException(status_code=502, detail=f"closure write failed for {venue_id}; retry")
+    return {"venue_id": venue_id, **closure}
+
+
+@router.delete("/venues/{venue_id}/closure")
+async def delete_venue_closure(venue_id: str):
+    """Reopen a venue before its closure window ends (404 when none)."""
+    service = _venue_closures_service()
+    try:
+        deleted = service.delete_closure(venue_id)
+    except Exception as e:
+        logger.error(f"[AdminTrigger] Venue closure delete failed for {venue_id}: {e}")
+        raise HTTPException(status_code=502, detail=f"closure delete failed for {venue_id}; retry")
+    if not deleted:
+        raise HTTPException(status_code=404, detail=f"no closure for {venue_id}")
+    return {"status": "ok", "venue_id": venue_id}
+
+
 @router.get("/users/activity-counts")
 async def user_activity_counts():
     """Distinct-user counts for the admin dashboard: total plus trailing 1d/7d/30d
@@ -801,6 +1452,38 @@ async def user_activity_counts():
         raise HTTPException(status_code=500, detail="user activity counts failed")
 
 
+# ── Redis key-schema migrations ──────────────────────────────────────────────
+def _raw_redis():
+    geo = require("redis_client", detail="redis client not configured")
+    return geo.client
+
+
+@router.get("/redis/schema")
+async def get_redis_schema():
+    """Applied vs latest Redis key-schema version."""
+    client = _raw_redis()
+    return {
+        "version": redis_migrations.current_version(client),
+        "latest": redis_migrations.latest_version(),
+    }
+
+
+@router.post("/redis/migrate")
+async def migrate_redis_schema():
+    """Apply pending Redis key-schema migrations (idempotent; 409 while another
+    run holds the migration lock). Runs off the event loop: a migration may
+    SCAN/RENAME the whole keyspace."""
+    client = _raw_redis()
+    loop = asyncio.get_event_loop()
+    try:
+        return await loop.run_in_executor(None, redis_migrations.migrate, client)
+    except redis_migrations.MigrationInProgressError as e:
+        raise HTTPException(status_code=409, detail=str(e))
+    except Exception as e:
+        logger.error(f"[AdminTrigger] Redis schema migration failed: {e}")
+        raise HTTPException(status_code=500, detail="redis schema migration failed")
+
+
 @router.post("/recount-discovery-points")
 async def recount_discovery_points():
     """Recount venues per discovery point using GEORADIUS and update counters."""
@@ -818,6 +1501,198 @@ async def recount_discovery_points():
         raise HTTPException(status_code=500, detail=str(e))
 
 
+class DiscoveryLocationRequest(BaseModel):
+    id: str = Field(min_length=1, max_length=80)
+    label: Optional[str] = None
+    lat: float
+    lng: float
+    radius: int = 15000  # meters
+    limit: int = 500
+    types: Optional[list[str]] = None  # BestTime venue types (None = default query)
+    live: Optional[bool] = None  # only venues with live data
+
+
+class DiscoveryLocationUpdate(BaseModel):
+    label: Optional[str] = None
+    lat: Optional[float] = None
+    lng: Optional[float] = None
+    radius: Optional[int] = None
+    limit: Optional[int] = None
+    current: Optional[int] = None
+    types: Optional[list[str]] = None
+    live: Optional[bool] = None
+
+
+def _discovery_locations_service() -> DiscoveryLocationsService:
+    return require("discovery_locations_service", detail="discovery locations not configured")
+
+
+@router.get("/locations")
+async def list_discovery_locations():
+    """The locations the next catalog discovery run will query and where they
+    come from: "admin" (the points managed here), "file"
+    (discovery_locations_file), "regions" (every region's points, whether due
+    or not) or "default" (built in)."""
+    refresher = require("venues_refresher_service")
+    source, locations = refresher.active_locations()
+    return {
+        "source": source,
+        "locations": [loc if isinstance(loc, dict) else asdict(loc) for loc in locations],
+    }
+
+
+@router.post("/locations", status_code=201)
+async def create_discovery_location(request: DiscoveryLocationRequest):
+    """Add a discovery point (409 when the id exists). The next discovery run
+    uses the admin points instead of the file / built-in locations."""
+    service = _discovery_locations_service()
+    try:
+        return service.create_point(request.model_dump(exclude_none=True))
+    except DiscoveryPointConflictError as e:
+        raise HTTPException(status_code=409, detail=str(e))
+    except (ValueError, TypeError) as e:
+        raise HTTPException(status_code=400, detail=f"invalid location: {e}")
+    except Exception as e:
+        logger.error(f"[AdminTrigger] Discovery location create failed: {e}")
+        raise HTTPException(status_code=502, detail="location write failed; retry")
+
+
+@router.get("/locations/{point_id}")
+async def get_discovery_location(point_id: str):
+    point = _discovery_locations_service().get_point(point_id)
+    if point is None:
+        raise HTTPException(status_code=404, detail=f"no location {point_id}")
+    return point
+
+
+@router.put("/locations/{point_id}")
+async def update_discovery_location(point_id: str, request: DiscoveryLocationUpdate):
+    """Change a discovery point's fields; omitted fields (and its venue
+    counter, unless `current` is given) are kept."""
+    service = _discovery_locations_service()
+    try:
+        point = service.update_point(point_id, request.model_dump(exclude_none=True))
+    except (ValueError, TypeError) as e:
+        raise HTTPException(status_code=400, detail=f"invalid location: {e}")
+    except Exception as e:
+        logger.error(f"[AdminTrigger] Discovery location update failed for {point_id}: {e}")
+        raise HTTPException(status_code=502, detail=f"location write failed for {point_id}; retry")
+    if point is None:
+        raise HTTPException(status_code=404, detail=f"no location {point_id}")
+    return point
+
+
+@router.delete("/locations/{point_id}")
+async def delete_discovery_location(point_id: str):
+    """Remove a discovery point (404 when none). With no points left, discovery
+    falls back to the file / built-in locations."""
+    service = _discovery_locations_service()
+    try:
+        deleted = service.delete_point(point_id)
+    except Exception as e:
+        logger.error(f"[AdminTrigger] Discovery location delete failed for {point_id}: {e}")
+        raise HTTPException(status_code=502, detail=f"location delete failed for {point_id}; retry")
+    if not deleted:
+        raise HTTPException(status_code=404, detail=f"no location {point_id}")
+    return {"status": "ok", "id": point_id}
+
+
+@router.get("/refresh/history")
+async def get_refresh_history(
+    job: Optional[str] = None, limit: int = Query(50, ge=1, le=500)
+):
+    """Reports of the latest refresh runs, newest first: start/end time,
+    status, locations queried, venues found / upserted, live and weekly
+    forecasts cached, stale forecasts deleted, and errors. `job` narrows to one
+    of venue_catalog, inventory_sync, live_forecast, weekly_forecast,
+    weekend_prefetch."""
+    refresher = require("venues_refresher_service")
+    if refresher.run_reports is None:
+        raise HTTPException(status_code=503, detail="refresh reports need Redis")
+    return {"reports": refresher.run_reports.history(job=job, limit=limit)}
+
+
+@router.get("/discovery/last-run")
+async def get_last_discovery_run():
+    """Per-location outcome of the latest discovery refresh in this process:
+    effective limit, venues fetched, venues new to the run, skip reason or
+    error, and duration."""
+    refresher = require("venues_refresher_service")
+    return {"locations": [asdict(s) for s in refresher.last_discovery_summaries]}
+
+
+@router.get("/stale-eviction/last-run")
+async def get_last_stale_eviction():
+    """Summary of the latest stale eviction in this process (dry run or not):
+    venues checked / evicted / of unknown age, live forecasts checked /
+    deleted, errors, and a sample of the evicted ids."""
+    service = require("stale_eviction_service")
+    summary = service.last_summary
+    return {"last_run": asdict(summary) if summary is not None else None}
+
+
+@router.get("/dedup/last-run")
+async def get_last_venue_dedup():
+    """Summary of the latest duplicate venue cleanup in this process (dry run
+    or not): venues checked, clusters found, venues merged, errors, and a
+    sample of the merged ids with the id each was merged into."""
+    service = require("venue_deduplicator")
+    summary = service.last_summary
+    return {"last_run": asdict(summary) if summary is not None else None}
+
+
+@router.get("/dedup/aliases")
+async def get_venue_dedup_aliases():
+    """Every merged venue id and the id of the venue it was merged into."""
+    service = require("venue_deduplicator")
+    return {"aliases": await asyncio.to_thread(service.aliases)}
+
+
+@router.get("/retry-queue")
+async def get_retry_queue(limit: int = Query(100, ge=1, le=1000)):
+    """Failed refresh work waiting for a retry (next due first) and the
+    dead letters (newest first), with their attempts and last error."""
+    queue = require("retry_queue", detail="Retry queue not enabled")
+    return {
+        **queue.stats(),
+        "items": queue.queued(limit),
+        "dead_letters": queue.dead_letters(limit),
+    }
+
+
+@router.post("/retry-queue/dead/requeue")
+async def requeue_dead_letters():
+    """Queue every dead letter again, due now, with its attempts reset."""
+    queue = require("retry_queue", detail="Retry queue not enabled")
+    return {"requeued": queue.requeue_dead()}
+
+
+@router.delete("/retry-queue/dead")
+async def clear_dead_letters():
+    queue = require("retry_queue", detail="Retry queue not enabled")
+    return {"cleared": queue.clear_dead()}
+
+
+@router.get("/quota")
+async def get_besttime_quota(days: int = Query(7, ge=1, le=35)):
+    """Estimated BestTime credits used today per endpoint family, against the
+    daily budget, plus the totals of the last `days` days and which API key
+    pairs are in rotation."""
+    body = require("besttime_quota").usage(days=days)
+    api = getattr(_container, "besttime_api", None)
+    if api is not None:
+        body["keys"] = api.keys.snapshot()
+    return body
+
+
+@router.get("/besttime/schema-drift")
+async def get_besttime_schema_drift():
+    """Fields BestTime sent that our response models do not declare, per
+    endpoint with how often, and the latest bodies rejected as not matching
+    their model (with every problem by field)."""
+    return require("besttime_api", detail="BestTime client not configured").decoder.snapshot()
+
+
 @router.get("/venue-type-breakdown")
 def venue_type_breakdown():
     """Get a breakdown of all venues by BestTime type and Google Places type."""
@@ -861,3 +1736,78 @@ def venue_type_breakdown():
     except Exception as e:
         logger.error(f"[AdminTrigger] Venue type breakdown failed: {e}")
         raise HTTPException(status_code=500, detail=str(e))
+
+
+class LogLevelRequest(BaseModel):
+    level: str = Field(..., min_length=1)
+    # Logger (module) name, e.g. "app.dao.redis_venue_dao"; omitted = global.
+    logger: Optional[str] = None
+
+
+class DebugTargetRequest(BaseModel):
+    kind: str  # "venue", "region" ("lat,lng,radius_km") or "path" (prefix)
+    value: str = Field(..., min_length=1)
+    minutes: float = 15
+
+
+def _logging_state() -> dict:
+    return {
+        "level": logging.getLevelName(log_control.level),
+        "modules": log_control.module_levels(),
+        "debug_targets": [t.to_dict() for t in log_control.targets()],
+    }
+
+
+@router.get("/logging")
+async def get_logging():
+    """The global log level and the active debug targets (this replica)."""
+    return _logging_state()
+
+
+@router.put("/logging/level")
+async def put_log_level(request: LogLevelRequest):
+    """Change the global log level of this replica, or one module's with
+    `logger` ("NOTSET" removes the override), until restart."""
+    try:
+        if request.logger is not None:
+            log_control.set_module_level(request.logger, request.level)
+        else:
+            log_control.set_level(request.level)
+    except ValueError as e:
+        raise HTTPException(status_code=400, detail=str(e))
+    return _logging_state()
+
+
+@router.post("/logging/debug")
+async def add_debug_target(request: DebugTargetRequest):
+    """Log DEBUG for one venue id, region or request path on this replica for
+    `minutes` (at most MAX_DEBUG_WINDOW_MINUTES)."""
+    try:
+        log_control.add_target(request.kind, request.value, request.minutes)
+    except ValueError as e:
+        raise HTTPException(status_code=400, detail=str(e))
+    return _logging_state()
+
+
+@router.delete("/logging/debug")
+async def clear_debug_targets():
+    """Remove every debug target on this replica."""
+    return {"status": "ok", "cleared": log_control.clear_targets()}
+
+
+@router.get("/routes")
+async def list_routes(request: Request):
+    """The app's route table: path, methods, handler name and whether it is in
+    the OpenAPI schema, sorted by path. For checking what a deployed build
+    actually serves."""
+    routes = []
+    for route in request.app.routes:
+        methods = getattr(route, "methods", None)
+        routes.append({
+            "path": route.path,
+            "methods": sorted(methods) if methods else [],
+            "name": route.name,
+            "in_schema": getattr(route, "include_in_schema", False),
+        })
+    routes.sort(key=lambda r: (r["path"], r["methods"]))
+    return {"count": len(routes), "routes": routes}
@@ -1,22 +1,26 @@
-"""Engagement API: vibes_bot writes favorites/hot_likes here (reads stay Redis).
+"""Engagement API: vibes_bot writes favorites/hot_likes here (reads stay Redis),
+and reads the recommendations built from favorites and visits.
 
 Write-through: the service commits RDS then projects Redis. If the projection
 fails after the RDS commit, the endpoint returns 5xx so vibes_bot retries
 (idempotent), keeping the user's read path consistent within seconds.
 """
 import logging
-from typing import Optional
+from typing import Literal, Optional
 
-from fastapi import APIRouter, HTTPException
-from pydantic import BaseModel
+from fastapi import APIRouter, HTTPException, Query
+from pydantic import BaseModel, Field
 
 from app.metrics import ENGAGEMENT_SESSION_TOTAL
+from app.services.recommendations import Recommendation
 
 logger = logging.getLogger(__name__)
 
 router = APIRouter(prefix="/v1", tags=["engagement"])
 
 _engagement_service = None
+_push_notifier = None
+_recommendation_service = None
 
 
 def set_engagement_service(service) -> None:
@@ -24,6 +28,16 @@ def set_engagement_service(service) -> None:
     _engagement_service = service
 
 
+def set_push_notifier(notifier) -> None:
+    global _push_notifier
+    _push_notifier = notifier
+
+
+def set_recommendation_service(service) -> None:
+    global _recommendation_service
+    _recommendation_service = service
+
+
 class EngagementRequest(BaseModel):
     user_id: str
     venue_id: str
@@ -38,13 +52,39 @@ class SessionRequest(BaseModel):
     user_id: str
 
 
+class PushSubscriptionRequest(BaseModel):
+    user_id: str
+    device_token: str
+    platform: Literal["android", "ios", "web"]
+    # Busyness (%) a favorite must reach for a push; omitted = keep the user's
+    # current one (the server default for a new subscriber).
+    threshold: Optional[int] = Field(None, ge=1, le=100)
+
+
+class PushUnsubscribeRequest(BaseModel):
+    user_id: str
+    # Omitted = every device of the user.
+    device_token: Optional[str] = None
+
+
+class StatusResponse(BaseModel):
+    status: Literal["ok"] = "ok"
+
+
+class PushSubscriptionResponse(StatusResponse):
+    # Devices now registered for the user, and the busyness (%) that triggers
+    # their pushes.
+    devices: int
+    threshold: Optional[int] = None
+
+
 def _svc():
     if _engagement_service is None:
         raise HTTPException(status_code=503, detail="engagement service not configured")
     return _engagement_service
 
 
-@router.post("/favorites")
+@router.post("/favorites", response_model=StatusResponse)
 def add_favorite(req: EngagementRequest):
     try:
         _svc().add_favorite(req.user_id, req.venue_id)
@@ -56,7 +96,7 @@ def add_favorite(req: EngagementRequest):
     return {"status": "ok"}
 
 
-@router.delete("/favorites")
+@router.delete("/favorites", response_model=StatusResponse)
 def remove_favorite(req: EngagementRequest):
     try:
         _svc().remove_favorite(req.user_id, req.venue_id)
@@ -68,7 +108,7 @@ def remove_favorite(req: EngagementRequest):
     return {"status": "ok"}
 
 
-@router.post("/hot-likes")
+@router.post("/hot-likes", response_model=StatusResponse)
 def add_hot_like(req: EngagementRequest):
     try:
         _svc().add_hot_like(req.user_id, req.venue_id, ttl_seconds=req.ttl_seconds)
@@ -80,7 +120,7 @@ def add_hot_like(req: EngagementRequest):
     return {"status": "ok"}
 
 
-@router.post("/sessions")
+@router.post("/sessions", response_model=StatusResponse)
 def record_session(req: SessionRequest):
     try:
         _svc().record_session(req.user_id)
@@ -96,7 +136,7 @@ def record_session(req: SessionRequest):
     return {"status": "ok"}
 
 
-@router.delete("/hot-likes")
+@router.delete("/hot-likes", response_model=StatusResponse)
 def remove_hot_like(req: EngagementRequest):
     try:
         _svc().remove_hot_like(req.user_id, req.venue_id)
@@ -106,3 +146,81 @@ def remove_hot_like(req: EngagementRequest):
         logger.error(f"[Engagement] remove_hot_like failed: {e}")
         raise HTTPException(status_code=502, detail="hot-like remove failed; retry")
     return {"status": "ok"}
+
+
+def _push():
+    if _push_notifier is None:
+        raise HTTPException(status_code=503, detail="push notifications not configured")
+    return _push_notifier
+
+
+@router.post("/push/subscriptions", response_model=PushSubscriptionResponse)
+def subscribe_push(req: PushSubscriptionRequest):
+    notifier = _push()
+    try:
+        sub = notifier.subscribe(req.user_id, req.device_token, req.platform, req.threshold)
+    except Exception as e:
+        logger.error(f"[Engagement] subscribe_push failed: {e}")
+        raise HTTPException(status_code=502, detail="push subscription write failed; retry")
+    return {
+        "status": "ok",
+        "devices": len(sub.devices),
+        "threshold": sub.threshold if sub.threshold is not None else notifier.default_threshold,
+    }
+
+
+@router.delete("/push/subscriptions", response_model=StatusResponse)
+def unsubscribe_push(req: PushUnsubscribeRequest):
+    try:
+        _push().unsubscribe(req.user_id, req.device_token)
+    except HTTPException:
+        raise
+    except Exception as e:
+        logger.error(f"[Engagement] unsubscribe_push failed: {e}")
+        raise HTTPException(status_code=502, detail="push unsubscribe failed; retry")
+    return {"status": "ok"}
+
+
+def _recs():
+    if _recommendation_service is None:
+        raise HTTPException(status_code=503, detail="recommendations not enabled")
+    return _recommendation_service
+
+
+@router.post("/visits", response_model=StatusResponse)
+def record_visit(req: EngagementRequest):
+    """Record that the user went to the venue (recommendation history)."""
+    svc = _recs()
+    try:
+        svc.history.record_visit(req.user_id, req.venue_id)
+    except Exception as e:
+        logger.error(f"[Engagement] record_visit failed: {e}")
+        raise HTTPException(status_code=502, detail="visit write failed; retry")
+    return {"status": "ok"}
+
+
+@router.get(
+    "/users/{user_id}/recommendations",
+    response_model=list[Recommendation],
+    summary="Venue recommendations for a user",
+    description=(
+        "Venues the user has not favorited or visited, ranked by similarity "
+        "(type, price, proximity) to their favorites and visits and by current "
+        "busyness. Searched around `lat`/`lon` when given, else around the "
+        "history venues. Empty for a user without history."
+    ),
+)
+def get_recommendations(
+    user_id: str,
+    lat: Optional[float] = Query(None, ge=-90, le=90),
+    lon: Optional[float] = Query(None, ge=-180, le=180),
+    limit: int = Query(10, ge=1, le=50),
+) -> list[Recommendation]:
+    svc = _recs()
+    if (lat is None) != (lon is None):
+        raise HTTPException(status_code=422, detail="lat and lon go together")
+    try:
+        return svc.recommend(user_id, lat=lat, lon=lon, limit=limit)
+    except Exception as e:
+        logger.error(f"[Engagement] recommendations failed: {e}")
+        raise HTTPException(status_code=500, detail="Internal server error")
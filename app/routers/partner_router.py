@@ -0,0 +1,90 @@
+"""Partner ingestion API: venue partners push real occupancy counts.
+
+Unlike /admin and /internal this surface is reachable by third parties, so it
+is authenticated at the app level: every request carries the partner's key in
+`X-Partner-Key` (settings.partner_api_keys), and a partner may only report for
+the venues assigned to it (settings.partner_venues).
+"""
+import logging
+from datetime import datetime
+from typing import Optional
+
+from fastapi import APIRouter, Header, HTTPException
+from pydantic import BaseModel, Field
+
+from app.services.partner_occupancy_service import (
+    PARTNER_SOURCE,
+    PartnerAuthError,
+    PartnerForbiddenError,
+    PartnerReadingError,
+)
+
+logger = logging.getLogger(__name__)
+
+router = APIRouter(prefix="/v1/partners", tags=["partners"])
+
+_partner_service = None
+
+
+def set_partner_service(service) -> None:
+    global _partner_service
+    _partner_service = service
+
+
+class OccupancyReading(BaseModel):
+    occupancy: int = Field(..., ge=0, description="People currently inside")
+    capacity: int = Field(..., gt=0, description="Venue capacity the count is relative to")
+    # When the count was taken; defaults to receipt time. Naive values are UTC.
+    observed_at: Optional[datetime] = None
+
+
+class OccupancyAccepted(BaseModel):
+    venue_id: str
+    busyness: int
+    observed_at: str
+    source: str = PARTNER_SOURCE
+
+
+def _svc():
+    if _partner_service is None:
+        raise HTTPException(status_code=503, detail="partner ingestion not configured")
+    return _partner_service
+
+
+@router.post(
+    "/venues/{venue_id}/occupancy",
+    response_model=OccupancyAccepted,
+    summary="Push a partner occupancy reading",
+    description=(
+        "Store a venue's current occupancy as reported by a partner (door "
+        "counter, WiFi analytics). The reading is converted to live busyness "
+        "(occupancy / capacity), recorded in the venue's live history, and "
+        "preferred over BestTime live data until it ages out."
+    ),
+)
+def push_occupancy(
+    venue_id: str,
+    reading: OccupancyReading,
+    x_partner_key: Optional[str] = Header(None),
+):
+    svc = _svc()
+    try:
+        partner = svc.authenticate(x_partner_key)
+    except PartnerAuthError:
+        raise HTTPException(status_code=401, detail="invalid or missing X-Partner-Key")
+    try:
+        stored = svc.ingest(
+            partner, venue_id, reading.occupancy, reading.capacity, reading.observed_at
+        )
+    except PartnerForbiddenError as e:
+        raise HTTPException(status_code=403, detail=str(e))
+    except PartnerReadingError as e:
+        raise HTTPException(status_code=422, detail=str(e))
+    except Exception as e:
+        logger.error(f"[Partner] ingest failed for {venue_id}: {e}")
+        raise HTTPException(status_code=502, detail="occupancy write failed; retry")
+    return OccupancyAccepted(
+        venue_id=venue_id,
+        busyness=stored.analysis.venue_live_busyness,
+        observed_at=stored.venue_info.venue_current_gmttime,
+    )
@@ -1,21 +1,35 @@
 """FastAPI routes for venue endpoints."""
 import logging
+from datetime import datetime
 from typing import Optional, Union
 
-from fastapi import APIRouter, HTTPException, Query
+from fastapi import APIRouter, Depends, HTTPException, Query, Request, Response
 from fastapi.encoders import jsonable_encoder
 from fastapi.responses import JSONResponse
+from pydantic import BaseModel, Field
 
 from app.config import settings
-from app.models import VenueWithLive, MinifiedVenue
+from app.handlers.venue_handler import nearby_response_exclude
+from app.latency_budget import StageTimer
+from app.metrics import VENUE_CHECKINS_TOTAL
+from app.models import FootTrafficForecast, ForecastAtTime, VenueMedia, VenueWithLive, MinifiedVenue
+from app.services.area_crowd_index import AreaCrowdIndex
+from app.services.regions import RegionCoverage
+from app.services.venue_suggestions import SuggestionQueueFullError
 
 logger = logging.getLogger(__name__)
 
 # Create router at module level
-router = APIRouter()
+router = APIRouter(tags=["venues"])
 
 # Global handler reference - set during startup
 _venue_handler = None
+_public_stats_service = None
+_nearby_precompute = None
+_area_crowd_index = None
+_regions = None
+_checkin_service = None
+_venue_suggestion_service = None
 
 
 def set_venue_handler(handler):
@@ -25,6 +39,42 @@ def set_venue_handler(handler):
     logger.info("[VenueRouter] Handler injected successfully")
 
 
+def set_nearby_precompute(service):
+    """Set the precomputed nearby responses (None = always render)."""
+    global _nearby_precompute
+    _nearby_precompute = service
+
+
+def set_area_crowd_index(service):
+    """Set the area crowd index service (None = areas not configured)."""
+    global _area_crowd_index
+    _area_crowd_index = service
+
+
+def set_regions(registry):
+    """Set the RegionRegistry listed by /v1/regions."""
+    global _regions
+    _regions = registry
+
+
+def set_checkin_service(service):
+    """Set the check-in service (None = check-ins disabled)."""
+    global _checkin_service
+    _checkin_service = service
+
+
+def set_venue_suggestion_service(service):
+    """Set the venue suggestion queue (None = suggestions disabled)."""
+    global _venue_suggestion_service
+    _venue_suggestion_service = service
+
+
+def set_public_stats_service(service):
+    """Set the public stats service (called during startup)."""
+    global _public_stats_service
+    _public_stats_service = service
+
+
 def get_handler():
     """Get the venue handler, raising error if not initialized."""
     if _venue_handler is None:
@@ -32,6 +82,12 @@ def get_handler():
     return _venue_handler
 
 
+def _start_stage_timer() -> StageTimer:
+    """Declared first on the nearby route so it runs before query validation:
+    the time up to the route body is the parse stage."""
+    return StageTimer()
+
+
 @router.get(
     "/v1/venues/nearby",
     response_model=Union[list[VenueWithLive], list[MinifiedVenue]],
@@ -39,9 +95,15 @@ def get_handler():
     description="Get venues within a radius of a location with live and weekly forecasts",
 )
 def get_venues_nearby(
+    timer: StageTimer = Depends(_start_stage_timer),
     lat: float = Query(..., description="Latitude", ge=-90, le=90),
     lon: float = Query(..., description="Longitude", ge=-180, le=180),
-    radius: float = Query(..., description="Radius in kilometers", gt=0),
+    radius: float = Query(..., description="Radius, in `unit` (kilometers by default)", gt=0),
+    unit: str = Query(
+        "km",
+        pattern="^(m|km|mi|ft)$",
+        description="Radius unit: m, km (default), mi or ft",
+    ),
     verbose: bool = Query(
         False,
         description="If true, return full VenueWithLive; if false, return MinifiedVenue",
@@ -55,26 +117,49 @@ def get_venues_nearby(
             "0 returns today's forecast (backward-compatible)."
         ),
     ),
+    open_now: bool = Query(
+        False,
+        description=(
+            "If true, only venues open right now by their stored opening hours "
+            "(in the venue's timezone); venues with unknown hours are left out"
+        ),
+    ),
+    sort: str = Query(
+        "busyness",
+        pattern="^(busyness|score)$",
+        description=(
+            "busyness (default): venues with live data first, busiest first. "
+            "score: highest `score` first, combining live busyness, rating, "
+            "reviews, price and distance"
+        ),
+    ),
 ) -> Union[list[VenueWithLive], list[MinifiedVenue]]:
     """Get nearby venues with live and weekly forecasts."""
+    timer.mark("parse")
     try:
         handler = get_handler()
+        if _nearby_precompute is not None and not open_now and sort == "busyness":
+            body = _nearby_precompute.lookup(lat, lon, radius, unit, verbose, target_day_offset)
+            if body is not None:
+                _record_timing(timer)
+                return Response(content=body, media_type="application/json")
         result = handler.get_venues_nearby(
-            lat, lon, radius, verbose, target_day_offset=target_day_offset
+            lat, lon, radius, verbose, target_day_offset=target_day_offset, unit=unit,
+            timer=timer, open_now=open_now, sort=sort,
         )
-        if settings.weekly_forecast_prev_day_enabled:
+        exclude = nearby_response_exclude(sort)
+        if not exclude and not settings.nearby_server_timing_enabled:
+            # FastAPI encodes this after the route returns, so no encode stage.
+            _record_timing(timer)
             return result
-        # Flag off: the handler never attaches weekly_forecast_prev (stays at
-        # its model default of None), but a declared Optional field still
-        # serializes as an explicit `null` by default. Strip the key entirely
-        # here so the response is byte-for-byte identical to the pre-flag
-        # shape (rollback path) rather than merely null-valued.
-        return JSONResponse(
-            content=[
-                jsonable_encoder(item, exclude={"weekly_forecast_prev"})
-                for item in result
-            ]
-        )
+        with timer.stage("encode"):
+            response = JSONResponse(
+                content=[jsonable_encoder(item, exclude=exclude) for item in result]
+            )
+        _record_timing(timer)
+        if settings.nearby_server_timing_enabled:
+            response.headers["Server-Timing"] = timer.server_timing()
+        return response
     except HTTPException:
         raise
     except Exception as e:
@@ -82,6 +167,255 @@ def get_venues_nearby(
         raise HTTPException(status_code=500, detail="Internal server error")
 
 
+def _record_timing(timer: StageTimer) -> None:
+    timer.observe()
+    timer.annotate_span()
+    logger.debug(f"[VenueRouter] nearby timing: {timer.summary()}")
+
+
+@router.get(
+    "/v1/venues/{venue_id}/forecast",
+    response_model=list[FootTrafficForecast],
+    summary="Get a venue's foot-traffic forecast",
+    description=(
+        "The full weekly foot-traffic forecast of one venue; the target of "
+        "`forecast_url` in nearby responses"
+    ),
+)
+def get_venue_forecast(venue_id: str) -> list[FootTrafficForecast]:
+    """Get one venue's weekly foot-traffic forecast."""
+    handler = get_handler()
+    try:
+        forecast = handler.get_venue_forecast(venue_id)
+    except Exception as e:
+        logger.error(f"[VenueRouter] Error in get_venue_forecast: {e}")
+        raise HTTPException(status_code=500, detail="Internal server error")
+    if forecast is None:
+        raise HTTPException(status_code=404, detail="Venue not found")
+    return forecast
+
+
+@router.get(
+    "/v1/venues/{venue_id}/media",
+    response_model=VenueMedia,
+    summary="Get a venue's photos and links",
+    description="Photo URLs, website and social links of one venue",
+)
+def get_venue_media(venue_id: str) -> VenueMedia:
+    """Get one venue's media document."""
+    handler = get_handler()
+    try:
+        media = handler.get_venue_media(venue_id)
+    except Exception as e:
+        logger.error(f"[VenueRouter] Error in get_venue_media: {e}")
+        raise HTTPException(status_code=500, detail="Internal server error")
+    if media is None:
+        raise HTTPException(status_code=404, detail="Venue media not found")
+    return media
+
+
+@router.get(
+    "/v1/venues/{venue_id}/forecast/at",
+    response_model=ForecastAtTime,
+    summary="A venue's forecast busyness at a given time",
+    description=(
+        "Weekly-forecast busyness at an absolute timestamp `at` (ISO 8601, "
+        "converted to the venue's timezone), or at `hour` of weekday `day_int` "
+        "(0=Monday) in venue-local time"
+    ),
+)
+def get_venue_forecast_at(
+    venue_id: str,
+    at: Optional[datetime] = Query(None, description="Absolute time (ISO 8601; UTC if no offset)"),
+    day_int: Optional[int] = Query(None, ge=0, le=6, description="Venue-local weekday, 0=Monday"),
+    hour: Optional[int] = Query(None, ge=0, le=23, description="Venue-local clock hour"),
+) -> ForecastAtTime:
+    """Forecast busyness at one moment, in the venue's timezone."""
+    by_timestamp = at is not None and day_int is None and hour is None
+    by_local_hour = at is None and day_int is not None and hour is not None
+    if not (by_timestamp or by_local_hour):
+        raise HTTPException(status_code=422, detail="Give either `at`, or `day_int` and `hour`")
+    handler = get_handler()
+    try:
+        forecast = handler.get_forecast_at(venue_id, at=at, day_int=day_int, hour=hour)
+    except Exception as e:
+        logger.error(f"[VenueRouter] Error in get_venue_forecast_at: {e}")
+        raise HTTPException(status_code=500, detail="Internal server error")
+    if forecast is None:
+        raise HTTPException(status_code=404, detail="Venue not found")
+    return forecast
+
+
+class CheckinRequest(BaseModel):
+    # Opaque per-install identifier; only its hash is stored, for dedup.
+    device_token: str = Field(..., min_length=8, max_length=512)
+    # Where the user is; must be within checkin_max_distance_m of the venue.
+    lat: float = Field(..., ge=-90.0, le=90.0)
+    lng: float = Field(..., ge=-180.0, le=180.0)
+
+
+class CheckinResponse(BaseModel):
+    # False when this device already checked in here within the dedup window,
+    # or its IP used up its check-ins here.
+    counted: bool
+
+
+@router.post(
+    "/v1/venues/{venue_id}/checkin",
+    response_model=CheckinResponse,
+    summary="Check in at a venue",
+    description=(
+        "Record an anonymous check-in from within `checkin_max_distance_m` of the "
+        "venue. A device counts once per venue per dedup window, and a client IP "
+        "a few times. Recent check-ins stand in for live busyness where BestTime "
+        "has none (`live_source: \"checkins\"`)."
+    ),
+)
+def checkin(venue_id: str, req: CheckinRequest, request: Request) -> CheckinResponse:
+    if _checkin_service is None:
+        raise HTTPException(status_code=503, detail="check-ins not enabled")
+    handler = get_handler()
+    try:
+        venue = handler.get_venue(venue_id)
+    except Exception as e:
+        logger.error(f"[VenueRouter] Error in checkin: {e}")
+        raise HTTPException(status_code=500, detail="Internal server error")
+    if venue is None:
+        raise HTTPException(status_code=404, detail="Venue not found")
+    if not _checkin_service.is_near(venue, req.lat, req.lng):
+        VENUE_CHECKINS_TOTAL.labels(result="too_far").inc()
+        raise HTTPException(status_code=403, detail="Too far from the venue to check in")
+    # The real client behind a trusted proxy (server_forwarded_allow_ips).
+    client_ip = request.client.host if request.client else "unknown"
+    try:
+        return CheckinResponse(
+            counted=_checkin_service.record(venue_id, req.device_token, client_ip)
+        )
+    except Exception as e:
+        logger.error(f"[VenueRouter] Error in checkin: {e}")
+        raise HTTPException(status_code=500, detail="Internal server error")
+
+
+class VenueSuggestionRequest(BaseModel):
+    venue_name: str = Field(..., min_length=1, max_length=256)
+    venue_address: str = Field(..., min_length=1, max_length=1024)
+    venue_lat: float = Field(..., ge=-90.0, le=90.0)
+    venue_lng: float = Field(..., ge=-180.0, le=180.0)
+    note: Optional[str] = Field(None, max_length=1000)
+
+
+class VenueSuggestionResponse(BaseModel):
+    id: str
+    status: str  # "pending" until an operator approves or rejects it
+
+
+@router.post(
+    "/v1/venues/suggest",
+    response_model=VenueSuggestionResponse,
+    status_code=202,
+    summary="Suggest a missing venue",
+    description=(
+        "Propose a venue the catalog does not have. It is added once an "
+        "operator approves it; the same name and address while still pending "
+        "return the existing suggestion."
+    ),
+)
+def suggest_venue(req: VenueSuggestionRequest) -> VenueSuggestionResponse:
+    if _venue_suggestion_service is None:
+        raise HTTPException(status_code=503, detail="venue suggestions not enabled")
+    try:
+        suggestion = _venue_suggestion_service.submit(
+            req.venue_name, req.venue_address, req.venue_lat, req.venue_lng, req.note
+        )
+    except SuggestionQueueFullError:
+        raise HTTPException(status_code=429, detail="too many suggestions pending review; try later")
+    except Exception as e:
+        logger.error(f"[VenueRouter] Error in suggest_venue: {e}")
+        raise HTTPException(status_code=500, detail="Internal server error")
+    return VenueSuggestionResponse(id=suggestion.id, status=suggestion.status)
+
+
+@router.get(
+    "/v1/stats/public",
+    summary="Public service stats",
+    description=(
+        "Coarse aggregates for a status page: venues tracked per city and the "
+        "overall freshness of live data. Cacheable."
+    ),
+)
+def get_public_stats(response: Response) -> dict:
+    """Coarse public aggregates, cached server-side and by clients/CDNs."""
+    if _public_stats_service is None:
+        raise HTTPException(status_code=503, detail="Service not ready")
+    try:
+        stats = _public_stats_service.get_stats()
+    except Exception as e:
+        logger.error(f"[VenueRouter] Error in get_public_stats: {e}")
+        raise HTTPException(status_code=500, detail="Internal server error")
+    response.headers["Cache-Control"] = f"public, max-age={_public_stats_service.ttl_seconds}"
+    return stats
+
+
+def _area_index_service():
+    if _area_crowd_index is None:
+        raise HTTPException(status_code=503, detail="Area crowd index not configured")
+    return _area_crowd_index
+
+
+@router.get(
+    "/v1/areas",
+    response_model=list[AreaCrowdIndex],
+    summary="Areas ranked by crowd index",
+    description=(
+        "Every configured neighborhood with its crowd index (capacity-weighted "
+        "live busyness of its venues), busiest first; areas without live data last"
+    ),
+)
+def list_areas() -> list[AreaCrowdIndex]:
+    service = _area_index_service()
+    try:
+        return service.ranked()
+    except Exception as e:
+        logger.error(f"[VenueRouter] Error in list_areas: {e}")
+        raise HTTPException(status_code=500, detail="Internal server error")
+
+
+@router.get(
+    "/v1/areas/{area_id}/index",
+    response_model=AreaCrowdIndex,
+    summary="An area's crowd index",
+    description="One neighborhood's crowd index as of the last live refresh",
+)
+def get_area_index(area_id: str) -> AreaCrowdIndex:
+    service = _area_index_service()
+    if not service.known(area_id):
+        raise HTTPException(status_code=404, detail="Area not found")
+    try:
+        index = service.get(area_id)
+    except Exception as e:
+        logger.error(f"[VenueRouter] Error in get_area_index: {e}")
+        raise HTTPException(status_code=500, detail="Internal server error")
+    if index is None:
+        raise HTTPException(status_code=404, detail="Area index not computed yet")
+    return index
+
+
+@router.get(
+    "/v1/regions",
+    response_model=list[RegionCoverage],
+    summary="Covered regions",
+    description=(
+        "Every region this server covers, with its bounding box "
+        "[min_lat, min_lng, max_lat, max_lng] and discovery circles; "
+        "empty when no regions are configured"
+    ),
+)
+def list_regions() -> list[RegionCoverage]:
+    if _regions is None:
+        return []
+    return _regions.coverage()
+
+
 @router.get(
     "/ping",
     summary="Health check",
@@ -0,0 +1,128 @@
+"""Busyness alert webhooks: clients register a URL and a threshold rule.
+
+Registering needs a client key in `X-Webhook-Key` (settings.webhooks_api_keys)
+and is bounded by settings.webhooks_max; reading, deleting and the delivery
+log need the webhook's own secret in `X-Webhook-Secret`, returned once at
+registration.
+"""
+import logging
+from datetime import datetime
+from typing import Optional
+
+from fastapi import APIRouter, Header, HTTPException, Query, Response
+from pydantic import BaseModel
+
+from app.services.webhooks import (
+    DeliveryAttempt,
+    WebhookAuthError,
+    WebhookError,
+    WebhookRule,
+)
+
+logger = logging.getLogger(__name__)
+
+router = APIRouter(prefix="/v1/webhooks", tags=["webhooks"])
+
+_webhook_service = None
+
+
+def set_webhook_service(service) -> None:
+    global _webhook_service
+    _webhook_service = service
+
+
+class WebhookRegistration(BaseModel):
+    url: str
+    rule: WebhookRule
+
+
+class WebhookInfo(BaseModel):
+    id: str
+    url: str
+    rule: WebhookRule
+    created_at: datetime
+
+
+class WebhookCreated(WebhookInfo):
+    # Signs every event (X-Webhook-Signature) and authorizes later calls
+    # (X-Webhook-Secret). Only ever returned here.
+    secret: str
+
+
+def _svc():
+    if _webhook_service is None:
+        raise HTTPException(status_code=503, detail="webhooks not enabled")
+    return _webhook_service
+
+
+def _authorized(webhook_id: str, secret: Optional[str]):
+    try:
+        return _svc().authorize(webhook_id, secret)
+    except WebhookAuthError:
+        raise HTTPException(status_code=404, detail="webhook not found")
+
+
+@router.post(
+    "",
+    response_model=WebhookCreated,
+    status_code=201,
+    summary="Register a busyness alert webhook",
+    description=(
+        "Receive a signed JSON POST whenever a venue (`rule.venue_id`), or any "
+        "venue within `rule.radius_km` of `rule.lat`/`rule.lon`, reaches "
+        "`rule.threshold` percent busy. Evaluated after every live refresh; "
+        "one event per crossing. Needs a client key in `X-Webhook-Key`; the "
+        "URL must resolve to a public address."
+    ),
+)
+def register_webhook(
+    registration: WebhookRegistration, x_webhook_key: Optional[str] = Header(None)
+) -> WebhookCreated:
+    svc = _svc()
+    try:
+        client_id = svc.authenticate(x_webhook_key)
+    except WebhookAuthError:
+        raise HTTPException(status_code=401, detail="invalid webhook key")
+    try:
+        hook = svc.register(registration.url, registration.rule, client_id=client_id)
+    except WebhookError as e:
+        raise HTTPException(status_code=422, detail=str(e))
+    except Exception as e:
+        logger.error(f"[Webhooks] register failed: {e}")
+        raise HTTPException(status_code=500, detail="Internal server error")
+    return WebhookCreated(**hook.model_dump())
+
+
+@router.get(
+    "/{webhook_id}",
+    response_model=WebhookInfo,
+    summary="Get a webhook",
+)
+def get_webhook(webhook_id: str, x_webhook_secret: Optional[str] = Header(None)) -> WebhookInfo:
+    return WebhookInfo(**_authorized(webhook_id, x_webhook_secret).model_dump())
+
+
+@router.delete(
+    "/{webhook_id}",
+    status_code=204,
+    summary="Delete a webhook",
+)
+def delete_webhook(webhook_id: str, x_webhook_secret: Optional[str] = Header(None)) -> Response:
+    _authorized(webhook_id, x_webhook_secret)
+    _svc().delete(webhook_id)
+    return Response(status_code=204)
+
+
+@router.get(
+    "/{webhook_id}/deliveries",
+    response_model=list[DeliveryAttempt],
+    summary="A webhook's delivery log",
+    description="Delivery attempts, newest first: delivered, retrying or failed (given up)",
+)
+def get_webhook_deliveries(
+    webhook_id: str,
+    limit: int = Query(50, ge=1, le=100),
+    x_webhook_secret: Optional[str] = Header(None),
+) -> list[DeliveryAttempt]:
+    _authorized(webhook_id, x_webhook_secret)
+    return _svc().deliveries(webhook_id, limit)
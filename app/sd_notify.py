@@ -0,0 +1,81 @@
+"""systemd service notification (sd_notify) for bare-metal deployments.
+
+With a `Type=notify` unit systemd sets NOTIFY_SOCKET, and the server reports:
+
+- READY=1 once essential init and the scheduler are up (systemd holds
+  dependent units and `systemctl start` until then);
+- STOPPING=1 when shutdown begins, so the unit shows "deactivating" while
+  in-flight work drains;
+- WATCHDOG=1 every WatchdogSec/2 while the internal health check passes. A
+  failing check withholds the ping, so a wedged process (event loop stalled,
+  Redis unreachable for the whole window) is restarted by systemd.
+
+Everything is a no-op without NOTIFY_SOCKET (Kubernetes, docker, dev), so no
+systemd library is needed. Disable with settings.systemd_notify_enabled.
+"""
+from __future__ import annotations
+
+import asyncio
+import logging
+import os
+import socket
+from typing import Callable, Optional
+
+logger = logging.getLogger(__name__)
+
+
+def notify(state: str) -> bool:
+    """Send `state` (newline-separated KEY=VALUE pairs) to systemd.
+
+    Returns:
+        True when sent; False when not running under systemd or on a socket
+        error (logged, never raised)
+    """
+    address = os.environ.get("NOTIFY_SOCKET")
+    if not address:
+        return False
+    if address.startswith("@"):  # abstract namespace socket
+        address = "\0" + address[1:]
+    try:
+        with socket.socket(socket.AF_UNIX, socket.SOCK_DGRAM) as sock:
+            sock.connect(address)
+            sock.sendall(state.encode("utf-8"))
+    except OSError as e:
+        logger.warning(f"[SdNotify] Failed to send {state!r}: {e}")
+        return False
+    return True
+
+
+def watchdog_interval_seconds() -> Optional[float]:
+    """Half of the unit's WatchdogSec, or None when the watchdog is off (or
+    meant for another process)."""
+    usec = os.environ.get("WATCHDOG_USEC")
+    pid = os.environ.get("WATCHDOG_PID")
+    if not usec or (pid and pid != str(os.getpid())):
+        return None
+    try:
+        return int(usec) / 1_000_000 / 2
+    except ValueError:
+        return None
+
+
+async def run_watchdog(interval: float, health_check: Callable[[], bool]) -> None:
+    """Ping the watchdog every `interval` seconds while `health_check` passes.
+
+    The check runs in a worker thread (it may do blocking I/O); raising counts
+    as failing. Runs until cancelled.
+    """
+    failing = False
+    while True:
+        try:
+            healthy = await asyncio.to_thread(health_check)
+        except Exception as e:
+            logger.warning(f"[SdNotify] Health check raised: {e}")
+            healthy = False
+        if healthy:
+            notify("WATCHDOG=1\nSTATUS=Serving" if failing else "WATCHDOG=1")
+        else:
+            logger.warning("[SdNotify] Health check failed; withholding watchdog ping")
+            notify("STATUS=Health check failing")
+        failing = not healthy
+        await asyncio.sleep(interval)
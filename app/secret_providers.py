@@ -0,0 +1,174 @@
+"""Credentials from secret stores instead of committed files.
+
+A SecretsProvider answers `get(name)` for a setting name (e.g.
+"besttime_private_key"); Settings asks the configured providers for every
+name in SECRET_SETTINGS after loading env and the config file:
+
+- FileSecretsProvider: one file per secret in a directory, the layout of
+  Docker secrets (/run/secrets) and Kubernetes secret volumes. The file is
+  named after the setting, lower or upper case; surrounding whitespace is
+  stripped.
+- VaultSecretsProvider: one HashiCorp Vault KV secret (v2 by default) whose
+  keys are the setting names, read once with a token (VAULT_TOKEN or a token
+  file, e.g. the one a Vault agent sidecar writes).
+
+Precedence per secret: an explicit environment variable, then the providers
+in order (files, then Vault), then the config file and the default. A
+provider that fails logs and answers nothing, so the startup check
+(Settings.check) reports the secrets that are still missing.
+"""
+from __future__ import annotations
+
+import logging
+import os
+from pathlib import Path
+from typing import Optional, Protocol
+
+import httpx
+
+logger = logging.getLogger(__name__)
+
+# Settings that hold credentials and may come from a secret store.
+SECRET_SETTINGS = (
+    "besttime_private_key",
+    "besttime_public_key",
+    "redis_password",
+    "redis_sentinel_password",
+    "rds_password",
+    "google_places_api_key",
+    "apify_api_token",
+    "foursquare_api_key",
+    "serpapi_api_key",
+    "s3_secret_access_key",
+    "openai_api_key",
+)
+
+
+class SecretsProvider(Protocol):
+    """Anything that maps a setting name to its secret value (None = unknown)."""
+
+    def get(self, name: str) -> Optional[str]:
+        ...
+
+
+class FileSecretsProvider:
+    """Secrets as files in a directory (Docker / Kubernetes secrets)."""
+
+    def __init__(self, directory: str):
+        self.directory = Path(directory)
+
+    def get(self, name: str) -> Optional[str]:
+        for candidate in (name, name.upper()):
+            path = self.directory / candidate
+            try:
+                value = path.read_text(encoding="utf-8").strip()
+            except FileNotFoundError:
+                continue
+            except OSError as e:
+                logger.error(f"[Secrets] Cannot read {path}: {e}")
+                return None
+            return value or None
+        return None
+
+
+class VaultSecretsProvider:
+    """One Vault KV secret, read on first use and kept for the process."""
+
+    def __init__(
+        self,
+        address: str,
+        path: str,
+        token: str,
+        mount: str = "secret",
+        kv_version: int = 2,
+        timeout: float = 5.0,
+        http_client: Optional[httpx.Client] = None,
+    ):
+        """
+        Args:
+            address: Vault address, e.g. https://vault.internal:8200
+            path: secret path under the mount, e.g. cs-server/prod
+            token: Vault token
+            mount: KV secrets engine mount
+            kv_version: 1 or 2 (v2 nests the data and adds /data/ to the URL)
+            timeout: request timeout
+            http_client: injected client (tests)
+        """
+        self.address = address.rstrip("/")
+        self.path = path.strip("/")
+        self.token = token
+        self.mount = mount.strip("/")
+        self.kv_version = kv_version
+        self.timeout = timeout
+        self.http_client = http_client
+        self._data: Optional[dict] = None
+
+    def _url(self) -> str:
+        if self.kv_version == 2:
+            return f"{self.address}/v1/{self.mount}/data/{self.path}"
+        return f"{self.address}/v1/{self.mount}/{self.path}"
+
+    def _load(self) -> dict:
+        if self._data is not None:
+            return self._data
+        self._data = {}
+        client = self.http_client or httpx.Client(timeout=self.timeout)
+        try:
+            response = client.get(self._url(), headers={"X-Vault-Token": self.token})
+            response.raise_for_status()
+            data = (response.json() or {}).get("data") or {}
+            if self.kv_version == 2:
+                data = data.get("data") or {}
+            self._data = {str(k): str(v) for k, v in data.items() if v is not None}
+            logger.info(f"[Secrets] Loaded {len(self._data)} secrets from Vault {self.mount}/{self.path}")
+        except (httpx.HTTPError, ValueError) as e:
+            logger.error(f"[Secrets] Vault read of {self.mount}/{self.path} failed: {e}")
+        finally:
+            if self.http_client is None:
+                client.close()
+        return self._data
+
+    def get(self, name: str) -> Optional[str]:
+        return self._load().get(name) or None
+
+
+def build_providers(settings) -> list[SecretsProvider]:
+    """The providers the settings configure, in precedence order."""
+    providers: list[SecretsProvider] = []
+    if settings.secrets_dir:
+        providers.append(FileSecretsProvider(settings.secrets_dir))
+    if settings.vault_addr and settings.vault_path:
+        token = os.getenv("VAULT_TOKEN", "")
+        if not token and settings.vault_token_file:
+            try:
+                token = Path(settings.vault_token_file).read_text(encoding="utf-8").strip()
+            except OSError as e:
+                logger.error(f"[Secrets] Cannot read vault_token_file: {e}")
+        if token:
+            providers.append(VaultSecretsProvider(
+                settings.vault_addr,
+                settings.vault_path,
+                token,
+                mount=settings.vault_mount,
+                kv_version=settings.vault_kv_version,
+            ))
+        else:
+            logger.error("[Secrets] vault_addr is set but there is no VAULT_TOKEN or vault_token_file")
+    return providers
+
+
+def resolve_secrets(
+    providers: list[SecretsProvider], skip: set[str] = frozenset()
+) -> dict[str, str]:
+    """Setting name -> value for every SECRET_SETTINGS name a provider knows,
+    except the names in `skip` (set explicitly)."""
+    resolved = {}
+    for name in SECRET_SETTINGS:
+        if name in skip:
+            continue
+        for provider in providers:
+            value = provider.get(name)
+            if value:
+                resolved[name] = value
+                break
+    return resolved
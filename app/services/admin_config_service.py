@@ -11,6 +11,7 @@ venue-keyed), need immediate read-back, and are single-row writes.
 """
 from __future__ import annotations
 
+import copy
 import json
 import logging
 from typing import Any, Callable, Optional
@@ -19,6 +20,9 @@ logger = logging.getLogger(__name__)
 
 ADMIN_CONFIG_PREFIX = "admin_config:"
 
+class AdminConfigVerificationError(RuntimeError):
+    """A verified write did not read back as written (RDS or the Redis mirror)."""
+
 
 class AdminConfigService:
     def __init__(
@@ -38,20 +42,88 @@ class AdminConfigService:
     def _redis_key(self, key: str) -> str:
         return f"{ADMIN_CONFIG_PREFIX}{key}"
 
-    def set(self, key: str, value: Any, updated_by: Optional[str] = None) -> Any:
+    def set(
+        self,
+        key: str,
+        value: Any,
+        updated_by: Optional[str] = None,
+        verify: bool = False,
+    ) -> Any:
         """Validate, write RDS (truth), then mirror Redis. Returns the stored value.
 
         Validation runs BEFORE any write (a malformed value never reaches RDS or
         Redis). If the Redis mirror fails after the RDS commit, the exception
         propagates so the caller returns a non-success and retries (the RDS upsert
         is idempotent, so a retry converges and restores the mirror).
+
+        With ``verify=True`` both copies are read back before returning (see
+        `verify`), so the caller only reports success once every runtime reader
+        will see the new value.
         """
         validator = self.validators.get(key)
         to_store = validator(value) if validator is not None else value
         self.rds_store.upsert_admin_config(key, to_store, updated_by)  # truth first
         self.redis.set(self._redis_key(key), json.dumps(to_store))  # mirror
+        if verify:
+            self.verify(key, to_store)
         return to_store
 
+    def update(
+        self,
+        key: str,
+        change: Callable[[Any], Any],
+        updated_by: Optional[str] = None,
+        verify: bool = False,
+    ) -> Any:
+        """Read-modify-write one key without losing a concurrent writer's
+        change (e.g. two operators editing different venues' notes in one
+        document). Returns the stored value.
+
+        `change` gets a copy of the current RDS value (None when unset) and
+        returns the new one. The RDS row stays locked across the read, the
+        write and the Redis mirror write (`rds_store.update_admin_config`), so
+        concurrent updates apply one after the other in RDS and the mirror
+        ends on the value RDS committed last. A stale mirror is never the base
+        of an update. ``verify`` reads both copies back as in `set`.
+        """
+        validator = self.validators.get(key)
+
+        def apply(current: Any) -> Any:
+            value = change(copy.deepcopy(current))
+            return validator(value) if validator is not None else value
+
+        def mirror(to_store: Any) -> None:
+            self.redis.set(self._redis_key(key), json.dumps(to_store))
+
+        stored = self.rds_store.update_admin_config(key, apply, updated_by, mirror=mirror)
+        if verify:
+            self.verify(key, stored)
+        return stored
+
+    def verify(self, key: str, expected: Any, check_rds: bool = True) -> None:
+        """Read-your-writes check: re-read the Redis mirror (and, unless
+        ``check_rds`` is False, the RDS row) straight from the stores and raise
+        AdminConfigVerificationError if either differs from ``expected``.
+
+        Deliberately bypasses `get`, whose RDS fallback would mask a missing
+        mirror — the mirror is what the runtime readers actually consume.
+        """
+        raw = self.redis.get(self._redis_key(key))
+        try:
+            mirrored = json.loads(raw) if raw is not None else None
+        except (TypeError, ValueError):
+            mirrored = raw
+        if mirrored != expected:
+            raise AdminConfigVerificationError(
+                f"redis mirror for {key} did not read back as written"
+            )
+        if check_rds:
+            row = self.rds_store.get_admin_config(key)
+            if row is None or row["value"] != expected:
+                raise AdminConfigVerificationError(
+                    f"rds row for {key} did not read back as written"
+                )
+
     def get(self, key: str) -> Any:
         """Return the live value from the Redis mirror (kept in sync with RDS).
         Falls back to the durable RDS value if the mirror is absent (e.g. if it
@@ -73,15 +145,21 @@ class AdminConfigService:
         self.rds_store.delete_admin_config(key)
         self.redis.delete(self._redis_key(key))
 
-    def set_mirror(self, key: str, value: Any) -> None:
+    def set_mirror(self, key: str, value: Any, verify: bool = False) -> None:
         """Write ONLY the Redis serving mirror (no RDS row). For configs whose
         durable truth lives elsewhere (e.g. eligibility = admin.eligibility_rule
-        rows), so RDS holds no redundant derived copy."""
+        rows), so RDS holds no redundant derived copy. ``verify`` reads the
+        mirror back before returning (no RDS check: there is no row)."""
         self.redis.set(self._redis_key(key), json.dumps(value))
+        if verify:
+            self.verify(key, value, check_rds=False)
 
-    def delete_mirror(self, key: str) -> None:
-        """Delete ONLY the Redis serving mirror (no RDS row)."""
+    def delete_mirror(self, key: str, verify: bool = False) -> None:
+        """Delete ONLY the Redis serving mirror (no RDS row). ``verify`` checks
+        the key is gone before returning."""
         self.redis.delete(self._redis_key(key))
+        if verify:
+            self.verify(key, None, check_rds=False)
 
     def list_keys(self) -> list[str]:
         return [row["key"] for row in self.rds_store.list_admin_config()]
@@ -0,0 +1,211 @@
+"""Neighborhood-level crowd index.
+
+A single busy bar says little about whether a neighborhood is lively tonight.
+AreaCrowdIndexService rolls member venues up into one 0-100 number per
+configured area (settings.crowd_index_areas):
+
+- an area is a polygon ([[lat, lng], ...], ray-cast) or a list of geohash
+  prefixes; a venue belongs to every area containing its coordinates;
+- the index is the capacity-weighted mean live busyness of the members with
+  fresh live data (partner readings win over BestTime, as in nearby). Weights
+  are settings.area_capacity_hints, else area_default_capacity, so a packed
+  arena moves the index more than a full ten-seat bar;
+- an area without fresh live data has index None (unknown, not empty).
+
+The refresher recomputes after every live refresh and stores the result in
+Redis (`area_crowd_index:v1`, area id -> JSON), so every replica serves the
+same numbers from GET /v1/areas and /v1/areas/{id}/index.
+"""
+from __future__ import annotations
+
+import json
+import logging
+from dataclasses import dataclass, field
+from datetime import datetime, timezone
+from typing import Optional
+
+from pydantic import BaseModel
+
+from app.services.live_freshness import fresh_live_busyness
+
+logger = logging.getLogger(__name__)
+
+INDEX_KEY = "area_crowd_index:v1"
+
+_GEOHASH_BASE32 = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+
+def geohash_encode(lat: float, lng: float, precision: int = 7) -> str:
+    """Standard base32 geohash of a point."""
+    lat_range, lng_range = [-90.0, 90.0], [-180.0, 180.0]
+    out, bits, ch, even = [], 0, 0, True
+    while len(out) < precision:
+        rng, value = (lng_range, lng) if even else (lat_range, lat)
+        mid = (rng[0] + rng[1]) / 2
+        if value >= mid:
+            ch = (ch << 1) | 1
+            rng[0] = mid
+        else:
+            ch <<= 1
+            rng[1] = mid
+        even = not even
+        bits += 1
+        if bits == 5:
+            out.append(_GEOHASH_BASE32[ch])
+            bits, ch = 0, 0
+    return "".join(out)
+
+
+@dataclass
+class Area:
+    id: str
+    name: str
+    polygon: list[tuple[float, float]] = field(default_factory=list)  # (lat, lng)
+    geohash_prefixes: list[str] = field(default_factory=list)
+
+    def contains(self, lat: float, lng: float) -> bool:
+        if self.geohash_prefixes:
+            code = geohash_encode(lat, lng, max(len(p) for p in self.geohash_prefixes))
+            if any(code.startswith(p) for p in self.geohash_prefixes):
+                return True
+        return bool(self.polygon) and _in_polygon(lat, lng, self.polygon)
+
+
+def _in_polygon(lat: float, lng: float, polygon: list[tuple[float, float]]) -> bool:
+    inside = False
+    j = len(polygon) - 1
+    for i in range(len(polygon)):
+        lat_i, lng_i = polygon[i]
+        lat_j, lng_j = polygon[j]
+        if (lng_i > lng) != (lng_j > lng) and (
+            lat < (lat_j - lat_i) * (lng - lng_i) / (lng_j - lng_i) + lat_i
+        ):
+            inside = not inside
+        j = i
+    return inside
+
+
+def parse_areas(raw: list[dict]) -> list[Area]:
+    """settings.crowd_index_areas -> Areas.
+
+    Raises:
+        ValueError: an entry without id, with neither a polygon (3+ points)
+            nor geohash prefixes, or with a duplicate id
+    """
+    areas, seen = [], set()
+    for entry in raw:
+        area_id = str(entry.get("id") or "").strip()
+        if not area_id:
+            raise ValueError(f"crowd index area without an id: {entry!r}")
+        if area_id in seen:
+            raise ValueError(f"duplicate crowd index area id {area_id!r}")
+        seen.add(area_id)
+        prefixes = entry.get("geohash") or []
+        if isinstance(prefixes, str):
+            prefixes = [prefixes]
+        polygon = [(float(p[0]), float(p[1])) for p in entry.get("polygon") or []]
+        if polygon and len(polygon) < 3:
+            raise ValueError(f"crowd index area {area_id!r}: a polygon needs 3+ points")
+        if not polygon and not prefixes:
+            raise ValueError(f"crowd index area {area_id!r}: needs a polygon or geohash prefixes")
+        areas.append(Area(
+            id=area_id,
+            name=str(entry.get("name") or area_id),
+            polygon=polygon,
+            geohash_prefixes=[str(p).lower() for p in prefixes],
+        ))
+    return areas
+
+
+class AreaCrowdIndex(BaseModel):
+    area_id: str
+    name: str
+    # 0-100, or None when no member venue has fresh live busyness.
+    index: Optional[int] = None
+    venues: int = 0  # member venues
+    venues_live: int = 0  # members that contributed fresh live busyness
+    computed_at: datetime
+
+
+class AreaCrowdIndexService:
+    """Computes, stores and serves the per-area crowd index."""
+
+    def __init__(
+        self,
+        venue_dao,
+        redis_client,
+        areas: list[Area],
+        capacity_hints: Optional[dict[str, int]] = None,
+        default_capacity: int = 100,
+    ):
+        """
+        Args:
+            venue_dao: the pipeline repository, whose live reads come from
+                RDS and so already hold this refresh's values
+            redis_client: raw Redis client the indexes are stored in
+            areas: parse_areas(settings.crowd_index_areas)
+            capacity_hints: venue_id -> capacity weight
+            default_capacity: weight of venues without a hint
+        """
+        self.venue_dao = venue_dao
+        self.redis = redis_client
+        self.areas = areas
+        self.capacity_hints = capacity_hints or {}
+        self.default_capacity = max(1, default_capacity)
+
+    def _weight(self, venue_id: str) -> int:
+        return max(1, int(self.capacity_hints.get(venue_id, self.default_capacity)))
+
+    def compute(self, now: Optional[datetime] = None) -> list[AreaCrowdIndex]:
+        """Indexes for every area from the DAO's current data, ranked."""
+        now = now or datetime.now(timezone.utc)
+        venues = [v for v in self.venue_dao.list_all_venues() if v.is_active()]
+        members = {
+            area.id: [v.venue_id for v in venues if area.contains(v.venue_lat, v.venue_lng)]
+            for area in self.areas
+        }
+        ids = sorted({vid for vids in members.values() for vid in vids})
+        fresh = fresh_live_busyness(self.venue_dao, ids, now)
+        out = []
+        for area in self.areas:
+            contributing = [vid for vid in members[area.id] if vid in fresh]
+            index = None
+            if contributing:
+                total = sum(self._weight(vid) for vid in contributing)
+                index = round(sum(fresh[vid] * self._weight(vid) for vid in contributing) / total)
+            out.append(AreaCrowdIndex(
+                area_id=area.id, name=area.name, index=index,
+                venues=len(members[area.id]), venues_live=len(contributing), computed_at=now,
+            ))
+        return rank(out)
+
+    def recompute(self, now: Optional[datetime] = None) -> list[AreaCrowdIndex]:
+        """compute() and store the result for the read endpoints. Blocking."""
+        indexes = self.compute(now)
+        pipe = self.redis.pipeline()
+        pipe.delete(INDEX_KEY)
+        if indexes:
+            pipe.hset(INDEX_KEY, mapping={i.area_id: i.model_dump_json() for i in indexes})
+        pipe.execute()
+        logger.info(
+            f"[AreaCrowdIndex] Recomputed {len(indexes)} areas "
+            f"({sum(1 for i in indexes if i.index is not None)} with live data)"
+        )
+        return indexes
+
+    def ranked(self) -> list[AreaCrowdIndex]:
+        """Stored indexes, busiest first (areas without live data last)."""
+        return rank([
+            AreaCrowdIndex(**json.loads(raw)) for raw in self.redis.hvals(INDEX_KEY)
+        ])
+
+    def get(self, area_id: str) -> Optional[AreaCrowdIndex]:
+        raw = self.redis.hget(INDEX_KEY, area_id)
+        return AreaCrowdIndex(**json.loads(raw)) if raw is not None else None
+
+    def known(self, area_id: str) -> bool:
+        return any(area.id == area_id for area in self.areas)
+
+
+def rank(indexes: list[AreaCrowdIndex]) -> list[AreaCrowdIndex]:
+    return sorted(indexes, key=lambda i: (i.index is None, -(i.index or 0), i.area_id))
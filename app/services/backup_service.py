@@ -0,0 +1,172 @@
+"""Snapshot backups of the venue data to S3 or GCS, and restore.
+
+A snapshot holds every active venue in RDS (the system of record), their live
+forecasts and their seven weekly forecast days, as one gzip-compressed JSON
+object in the seed fixture shape (app/services/fixture_seed.py):
+
+    {"version": 1, "created_at": "...", "venues": [...],
+     "live_forecasts": [...], "weekly_forecasts": [{"venue_id", "week_raw"}]}
+
+Snapshots are written under `prefix` as `<prefix><UTC timestamp>.json.gz`, so
+key order is time order and the newest snapshot is the last key. Old snapshots
+are not deleted here; use a bucket lifecycle rule.
+
+restore() reads a snapshot (the newest by default), validates all of it and
+writes it back to RDS through the pipeline repository, then runs the Redis
+projection (`project`), so serving picks it up at once. Live forecasts keep
+their `refreshed_at`, so the freshness gate still treats old ones as stale.
+"""
+from __future__ import annotations
+
+import asyncio
+import gzip
+import json
+import logging
+from datetime import datetime, timezone
+from typing import Optional
+
+from app.dao.venue_row import venue_from_row
+from app.metrics import BACKUP_RUNS_TOTAL
+from app.models import LiveForecastResponse, WeekRawDay
+from app.services.fixture_seed import parse_fixture, seed_redis
+
+logger = logging.getLogger(__name__)
+
+SNAPSHOT_VERSION = 1
+SNAPSHOT_SUFFIX = ".json.gz"
+
+
+class BackupNotFoundError(LookupError):
+    """No snapshot under the prefix, or not the requested key."""
+
+
+class BackupService:
+    """Writes and restores venue data snapshots in object storage."""
+
+    def __init__(self, venue_repository, storage, prefix: str = "backups/venues/", now_fn=None):
+        """
+        Args:
+            venue_repository: the pipeline VenueRepository (RDS; read for
+                backups, written on restore)
+            storage: S3Client or GcsClient (put_object / get_object / list_keys)
+            prefix: key prefix of the snapshots
+        """
+        self.venue_repository = venue_repository
+        self.storage = storage
+        self.prefix = prefix
+        self._now = now_fn or (lambda: datetime.now(timezone.utc))
+        # Optional: the Redis projection run after a restore, set by the
+        # container once the projector exists; returns its summary.
+        self.project = None
+
+    def _snapshot(self) -> dict:
+        # Bulk RDS reads, like the projector's (one query per table).
+        store = self.venue_repository.rds_store
+        ids = sorted(self.venue_repository.list_active_venue_ids())
+        rows = store.get_venues_by_ids(ids) if ids else {}
+        venues = [venue_from_row(rows[vid]) for vid in ids if vid in rows]
+        ids = [v.venue_id for v in venues]
+        live, weekly = {}, {}
+        if ids:
+            live = {
+                vid: LiveForecastResponse.model_validate(row["payload"])
+                for vid, row in store.get_live_bulk(ids).items()
+            }
+            for venue_id, days in store.get_weekly_bulk(ids).items():
+                weekly[venue_id] = [
+                    WeekRawDay.model_validate(days[day_int]["payload"]).model_dump(mode="json")
+                    for day_int in sorted(days)
+                ]
+        return {
+            "version": SNAPSHOT_VERSION,
+            "created_at": self._now().isoformat(),
+            "venues": [v.model_dump(mode="json", by_alias=True) for v in venues],
+            "live_forecasts": [live[vid].model_dump(mode="json") for vid in ids if vid in live],
+            "weekly_forecasts": [
+                {"venue_id": vid, "week_raw": weekly[vid]} for vid in ids if vid in weekly
+            ],
+        }
+
+    async def backup(self) -> dict:
+        """Write a snapshot; returns {key, uri, venues, live_forecasts, weekly_forecasts, bytes}."""
+        try:
+            # Blocking RDS reads; keep them off the event loop.
+            snapshot = await asyncio.to_thread(self._snapshot)
+            body = gzip.compress(json.dumps(snapshot, separators=(",", ":")).encode("utf-8"))
+            key = f"{self.prefix}{self._now().strftime('%Y%m%dT%H%M%SZ')}{SNAPSHOT_SUFFIX}"
+            uri = await self.storage.put_object(key, body, content_type="application/gzip")
+        except Exception:
+            BACKUP_RUNS_TOTAL.labels(operation="backup", result="error").inc()
+            raise
+        BACKUP_RUNS_TOTAL.labels(operation="backup", result="success").inc()
+        summary = {
+            "key": key,
+            "uri": uri,
+            "venues": len(snapshot["venues"]),
+            "live_forecasts": len(snapshot["live_forecasts"]),
+            "weekly_forecasts": sum(len(e["week_raw"]) for e in snapshot["weekly_forecasts"]),
+            "bytes": len(body),
+        }
+        logger.info(f"[BackupService] Wrote snapshot {uri}: {summary}")
+        return summary
+
+    async def list_snapshots(self) -> list[str]:
+        """Snapshot keys, oldest first."""
+        keys = await self.storage.list_keys(self.prefix)
+        return [k for k in keys if k.endswith(SNAPSHOT_SUFFIX)]
+
+    async def restore(self, key: Optional[str] = None, dry_run: bool = False) -> dict:
+        """Write a snapshot (the newest when `key` is None) back to RDS, then
+        project it into Redis.
+
+        Raises:
+            BackupNotFoundError: no snapshot to restore
+            ValueError: the snapshot is not readable or fails validation
+        """
+        if key is None:
+            keys = await self.list_snapshots()
+            if not keys:
+                raise BackupNotFoundError(f"no snapshots under {self.prefix}")
+            key = keys[-1]
+        elif not key.startswith(self.prefix):
+            raise BackupNotFoundError(f"{key} is not under {self.prefix}")
+        try:
+            body = await self.storage.get_object(key)
+        except Exception as e:
+            raise BackupNotFoundError(f"cannot read {key}: {e}") from e
+        try:
+            data = json.loads(gzip.decompress(body))
+        except (OSError, ValueError) as e:
+            raise ValueError(f"{key} is not a gzip JSON snapshot: {e}") from None
+        if not isinstance(data, dict) or data.get("version") != SNAPSHOT_VERSION:
+            raise ValueError(f"{key} is not a version {SNAPSHOT_VERSION} snapshot")
+        fixture = parse_fixture(data)
+        summary = {"key": key, "created_at": data.get("created_at"), "dry_run": dry_run}
+        if dry_run:
+            return {
+                **summary,
+                "venues": len(fixture.venues),
+                "live_forecasts": len(fixture.live_forecasts),
+                "weekly_forecasts": len(fixture.weekly_forecasts),
+            }
+        try:
+            # seed_redis only needs the DAO write surface, which the
+            # repository sends to RDS.
+            counts = await asyncio.to_thread(seed_redis, self.venue_repository, fixture)
+        except Exception:
+            BACKUP_RUNS_TOTAL.labels(operation="restore", result="error").inc()
+            raise
+        BACKUP_RUNS_TOTAL.labels(operation="restore", result="success").inc()
+        logger.info(f"[BackupService] Restored {key}: {counts}")
+        summary = {**summary, **counts}
+        if self.project is not None:
+            try:
+                projection = await asyncio.to_thread(self.project)
+            except Exception as e:
+                # The data is in RDS; the scheduled projection catches up.
+                logger.error(f"[BackupService] Projection after restoring {key} failed: {e}")
+                projection = {"errors": 1}
+            summary["projection"] = {
+                name: projection.get(name, 0) for name in ("venues", "live", "removed", "errors")
+            }
+        return summary
@@ -182,6 +182,7 @@ class BatchAddService:
             job_lock.release(BATCH_ADD_LOCK)
             raise
         self._tasks[job_id] = task
+        job_lock.bind_task(BATCH_ADD_LOCK, task)
         task.add_done_callback(lambda t: self._on_done(job_id, t))
         return {"job_id": job_id, "total": job["total"], "status": "running"}
 
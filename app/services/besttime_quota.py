@@ -0,0 +1,119 @@
+"""Daily BestTime credit usage per endpoint family, with an optional budget.
+
+Every successful BestTime call is counted in a Redis hash per Recife day
+(`besttime:credits:<YYYY-MM-DD>`, field = endpoint family), so usage is shared
+by replicas and rolls over at midnight without a reset job; day keys expire
+after CREDIT_USAGE_RETENTION_DAYS. Credits are estimated: one per call, except
+/venues/filter, which draws one per venue returned (as the filter tuner
+scores it).
+
+With settings.besttime_daily_credit_budget > 0, the non-critical scheduled
+jobs (catalog discovery, weekly refresh, weekend prefetch) are skipped once
+the day's total reaches it; the live refresh keeps running. GET /admin/quota
+shows today's usage and the last days.
+"""
+from __future__ import annotations
+
+import logging
+from datetime import date, timedelta
+from typing import Callable, Optional
+
+from app.metrics import BESTTIME_CREDITS_USED_TOTAL
+from app.utils.recife_time import recife_today
+
+logger = logging.getLogger(__name__)
+
+CREDIT_USAGE_KEY_PREFIX = "besttime:credits:"
+CREDIT_USAGE_RETENTION_DAYS = 35
+
+ENDPOINT_FAMILIES = ("search", "filter", "live", "week", "forecast", "other")
+
+
+def endpoint_family(endpoint: str) -> str:
+    """Group a BestTime endpoint path into the family its credits count under."""
+    if endpoint in ("/forecasts", "/venues/search", "/venues/progress"):
+        return "search"
+    if endpoint == "/venues/filter":
+        return "filter"
+    if endpoint == "/forecasts/live":
+        return "live"
+    if endpoint.startswith("/forecasts/week"):
+        return "week"
+    if endpoint.startswith("/forecasts/"):
+        return "forecast"
+    return "other"
+
+
+class BestTimeQuotaService:
+    """Counts BestTime credits per day and endpoint family."""
+
+    def __init__(
+        self,
+        redis_client,
+        daily_budget: int = 0,
+        today: Callable[[], date] = recife_today,
+    ) -> None:
+        """
+        Args:
+            redis_client: raw Redis client (decode_responses=True)
+            daily_budget: credits per day before non-critical jobs pause
+                (0 = no budget)
+        """
+        self.redis = redis_client
+        self.daily_budget = daily_budget
+        self._today = today
+
+    @staticmethod
+    def _key(day: date) -> str:
+        return f"{CREDIT_USAGE_KEY_PREFIX}{day.isoformat()}"
+
+    def record(self, endpoint: str, credits: int = 1) -> None:
+        """Count `credits` against today's usage of `endpoint`. Never raises."""
+        family = endpoint_family(endpoint)
+        BESTTIME_CREDITS_USED_TOTAL.labels(family=family).inc(credits)
+        key = self._key(self._today())
+        try:
+            pipe = self.redis.pipeline()
+            pipe.hincrby(key, family, credits)
+            pipe.expire(key, CREDIT_USAGE_RETENTION_DAYS * 86400)
+            pipe.execute()
+        except Exception as e:
+            logger.warning(f"[BestTimeQuota] Failed to record {credits} {family} credits: {e}")
+
+    def used_on(self, day: date) -> dict[str, int]:
+        """Credits per endpoint family on `day` ({} when unreadable)."""
+        try:
+            raw = self.redis.hgetall(self._key(day)) or {}
+        except Exception as e:
+            logger.warning(f"[BestTimeQuota] Failed to read usage for {day}: {e}")
+            return {}
+        return {family: int(n) for family, n in raw.items()}
+
+    def exceeded(self) -> bool:
+        """True when a daily budget is set and today's total has reached it."""
+        if self.daily_budget <= 0:
+            return False
+        return sum(self.used_on(self._today()).values()) >= self.daily_budget
+
+    def usage(self, days: int = 7) -> dict:
+        """Today's usage against the budget plus the totals of the last `days`."""
+        today = self._today()
+        by_family = self.used_on(today)
+        total = sum(by_family.values())
+        return {
+            "date": today.isoformat(),
+            "by_endpoint": {family: by_family.get(family, 0) for family in ENDPOINT_FAMILIES},
+            "total": total,
+            "daily_budget": self.daily_budget or None,
+            "remaining": max(0, self.daily_budget - total) if self.daily_budget > 0 else None,
+            "exceeded": self.exceeded(),
+            "history": [
+                {"date": day.isoformat(), "total": sum(self.used_on(day).values())}
+                for day in (today - timedelta(days=n) for n in range(1, days))
+            ],
+        }
+
+
+def quota_exceeded(service: Optional[BestTimeQuotaService]) -> bool:
+    """Whether non-critical BestTime work should pause (False without a service)."""
+    return service is not None and service.exceeded()
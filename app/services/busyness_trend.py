@@ -0,0 +1,141 @@
+"""Per-venue busyness trends for nearby responses.
+
+"Busy" alone does not tell a user whether to leave now or wait. TrendService
+combines the two signals we already store:
+
+- the live busyness history (live_history_v1:{id}, one sample per live
+  write): delta_1h is the current live value minus the sample nearest an hour
+  ago (within HISTORY_TOLERANCE), and a delta of at least `threshold` points
+  makes the venue rising or falling;
+- the weekly forecast curve for the current business day (BestTime anchors a
+  day at 6 AM, so 00:00-05:59 belongs to the previous day_int): it gives the
+  peak still ahead, and the direction when there is no usable history (this
+  hour's forecast vs the next one).
+
+Only venues with an available live value get a trend. Reads are two bulk
+round-trips per request (history, weekly day); a failing read leaves the
+trend to whatever the other signal can tell.
+"""
+from __future__ import annotations
+
+import logging
+from datetime import datetime, timedelta
+from typing import Optional
+
+from app.models import BusynessTrend, LiveForecastResponse, LiveHistoryPoint, WeekRawDay
+from app.utils.recife_time import recife_now
+from app.utils.venue_time import BESTTIME_DAY_START_HOUR, business_day_int
+
+logger = logging.getLogger(__name__)
+
+RISING = "rising"
+FALLING = "falling"
+STEADY = "steady"
+
+# The hour-ago sample may be this far from exactly an hour ago.
+HISTORY_TOLERANCE = timedelta(minutes=20)
+HISTORY_LOOKBACK = timedelta(hours=1) + HISTORY_TOLERANCE
+
+
+def _direction(delta: int, threshold: int) -> str:
+    if delta >= threshold:
+        return RISING
+    if delta <= -threshold:
+        return FALLING
+    return STEADY
+
+
+def _hour_ago(history: list[LiveHistoryPoint], now: datetime) -> Optional[LiveHistoryPoint]:
+    target = now - timedelta(hours=1)
+    candidates = [p for p in history if abs(p.timestamp - target) <= HISTORY_TOLERANCE]
+    return min(candidates, key=lambda p: abs(p.timestamp - target), default=None)
+
+
+def compute_trend(
+    current: int,
+    history: list[LiveHistoryPoint],
+    day_raw: Optional[list[int]],
+    now: datetime,
+    threshold: int = 10,
+) -> BusynessTrend:
+    """Trend for one venue.
+
+    Args:
+        current: live busyness now
+        history: recorded live samples (any order, timezone-aware)
+        day_raw: the business day's 24 hourly forecasts (index 0 = 6 AM), or None
+        now: local (America/Recife) time, timezone-aware
+        threshold: points of change that count as rising / falling
+    """
+    trend = BusynessTrend()
+    past = _hour_ago(history, now)
+    if past is not None:
+        trend.delta_1h = current - past.busyness
+        trend.direction = _direction(trend.delta_1h, threshold)
+        trend.basis = "history"
+    if not day_raw or len(day_raw) < 24:
+        return trend
+    index = (now.hour - BESTTIME_DAY_START_HOUR) % 24
+    if trend.direction is None and index + 1 < 24:
+        trend.direction = _direction(day_raw[index + 1] - day_raw[index], threshold)
+        trend.basis = "forecast"
+    ahead = day_raw[index:]
+    peak = max(ahead)
+    if peak > 0:
+        peak_index = index + ahead.index(peak)
+        trend.peak_busyness = peak
+        trend.peak_in_minutes = (
+            0 if peak_index == index else (peak_index - index) * 60 - now.minute
+        )
+    return trend
+
+
+class TrendService:
+    """Computes BusynessTrend for the venues of a nearby response."""
+
+    def __init__(self, venue_dao, threshold: int = 10):
+        """
+        Args:
+            venue_dao: serving DAO with get_live_history_bulk and
+                get_week_raw_forecasts_bulk
+            threshold: points of change that count as rising / falling
+        """
+        self.venue_dao = venue_dao
+        self.threshold = threshold
+
+    def trends(
+        self,
+        live_map: dict[str, LiveForecastResponse],
+        now: Optional[datetime] = None,
+    ) -> dict[str, BusynessTrend]:
+        """Trend per venue_id for the venues in `live_map` with a live value."""
+        ids = [
+            vid for vid, lf in live_map.items()
+            if lf is not None and lf.analysis.venue_live_busyness_available
+        ]
+        if not ids:
+            return {}
+        now = now or recife_now()
+        try:
+            history = self.venue_dao.get_live_history_bulk(ids, since=now - HISTORY_LOOKBACK)
+        except Exception as e:
+            logger.debug(f"[TrendService] Bulk live history fetch failed: {e}")
+            history = {}
+        try:
+            weekly: dict[str, WeekRawDay] = self.venue_dao.get_week_raw_forecasts_bulk(
+                ids, business_day_int(now)
+            )
+        except Exception as e:
+            logger.debug(f"[TrendService] Bulk weekly forecast fetch failed: {e}")
+            weekly = {}
+        out = {}
+        for vid in ids:
+            day = weekly.get(vid)
+            out[vid] = compute_trend(
+                live_map[vid].analysis.venue_live_busyness,
+                history.get(vid, []),
+                day.day_raw if day is not None else None,
+                now,
+                self.threshold,
+            )
+        return out
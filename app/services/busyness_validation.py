@@ -0,0 +1,156 @@
+"""Validation stage for every busyness value we ingest.
+
+Busyness arrives from BestTime (live and weekly), from CrowdDataProviders, and
+from partner occupancy pushes. Before any of it is cached:
+
+- impossible values (negative, non-numeric, or above
+  settings.busyness_reject_above) are rejected outright;
+- plausible values outside 0..100 (BestTime reports e.g. 110 for "busier than
+  the usual peak") are clamped to 100;
+- each venue's recent live values are kept, and a venue whose value swings by
+  at least busyness_flipflop_delta in alternating directions
+  busyness_flipflop_swings times in a row is flagged as flip-flopping. A flag
+  does not reject the value (one of the swings is real); it is surfaced in the
+  data-quality metrics so the source can be looked at.
+
+The flip-flop window is in-process: the live refresh runs on one replica at a
+time (job lock), so that replica sees the consecutive samples. A sample is
+keyed by its venue_current_gmttime, so a reading seen twice (a partner push
+later read back through the live map, a retried fetch) counts once.
+"""
+from __future__ import annotations
+
+import logging
+from collections import deque
+from datetime import datetime, timedelta, timezone
+from typing import Optional
+
+from app.config import settings
+from app.metrics import BUSYNESS_FLIPFLOP_TOTAL, BUSYNESS_VALIDATION_TOTAL
+from app.models import LiveForecastResponse, WeekRawDay
+
+logger = logging.getLogger(__name__)
+
+BUSYNESS_MIN = 0
+BUSYNESS_MAX = 100
+# A flip-flop flag is reported for this long after the venue last flip-flopped.
+FLAG_RETENTION = timedelta(hours=24)
+
+
+class BusynessValidator:
+    """Rejects, clamps and watches busyness values (see module docstring)."""
+
+    def __init__(
+        self,
+        reject_above: Optional[int] = None,
+        flipflop_delta: Optional[int] = None,
+        flipflop_swings: Optional[int] = None,
+        now_fn=None,
+    ) -> None:
+        self.reject_above = settings.busyness_reject_above if reject_above is None else reject_above
+        self.flipflop_delta = settings.busyness_flipflop_delta if flipflop_delta is None else flipflop_delta
+        self.flipflop_swings = settings.busyness_flipflop_swings if flipflop_swings is None else flipflop_swings
+        self._now = now_fn or (lambda: datetime.now(timezone.utc))
+        self._recent: dict[str, deque] = {}
+        # venue_id -> time of the last sample recorded for it.
+        self._last_sample: dict[str, str] = {}
+        self._flagged: dict[str, datetime] = {}
+
+    # ── single values ────────────────────────────────────────────────────────
+    def check_value(self, value, source: str) -> Optional[int]:
+        """The value clamped into 0..100, or None when it is impossible."""
+        if isinstance(value, bool) or not isinstance(value, (int, float)):
+            BUSYNESS_VALIDATION_TOTAL.labels(source=source, outcome="rejected").inc()
+            return None
+        if value < BUSYNESS_MIN or value > self.reject_above:
+            BUSYNESS_VALIDATION_TOTAL.labels(source=source, outcome="rejected").inc()
+            return None
+        if value > BUSYNESS_MAX:
+            BUSYNESS_VALIDATION_TOTAL.labels(source=source, outcome="clamped").inc()
+            return BUSYNESS_MAX
+        BUSYNESS_VALIDATION_TOTAL.labels(source=source, outcome="accepted").inc()
+        return int(round(value))
+
+    # ── payloads ─────────────────────────────────────────────────────────────
+    def validate_live(
+        self, forecast: LiveForecastResponse, source: str
+    ) -> Optional[LiveForecastResponse]:
+        """The forecast with its live value validated (a copy when clamped),
+        or None when the live value is impossible. Forecasts without an
+        available live value pass through untouched."""
+        if not forecast.analysis.venue_live_busyness_available:
+            return forecast
+        raw = forecast.analysis.venue_live_busyness
+        value = self.check_value(raw, source)
+        if value is None:
+            logger.warning(
+                f"[BusynessValidator] Rejected {source} live busyness {raw!r} "
+                f"for {forecast.venue_info.venue_id}"
+            )
+            return None
+        self.observe(
+            forecast.venue_info.venue_id, value, source,
+            sample_at=forecast.venue_info.venue_current_gmttime,
+        )
+        if value == raw:
+            return forecast
+        clamped = forecast.model_copy(deep=True)
+        clamped.analysis.venue_live_busyness = value
+        return clamped
+
+    def validate_week_day(self, day: WeekRawDay, source: str) -> Optional[WeekRawDay]:
+        """The day with every hour clamped, or None if any hour is impossible
+        (a curve with one impossible hour is not trusted as a whole)."""
+        values = []
+        for raw in day.day_raw:
+            value = self.check_value(raw, source)
+            if value is None:
+                logger.warning(
+                    f"[BusynessValidator] Rejected {source} weekly day {day.day_int} "
+                    f"(impossible hour value {raw!r})"
+                )
+                return None
+            values.append(value)
+        if values == list(day.day_raw):
+            return day
+        return day.model_copy(update={"day_raw": values})
+
+    # ── flip-flop detection ──────────────────────────────────────────────────
+    def observe(
+        self, venue_id: str, value: int, source: str, sample_at: Optional[str] = None
+    ) -> bool:
+        """Record a live sample; True when it completes a flip-flop pattern.
+
+        A sample with the same `sample_at` as the venue's previous one is the
+        same reading seen again and is not recorded.
+        """
+        if sample_at:
+            if self._last_sample.get(venue_id) == sample_at:
+                return False
+            self._last_sample[venue_id] = sample_at
+        # N swings need N+1 samples.
+        recent = self._recent.setdefault(venue_id, deque(maxlen=self.flipflop_swings + 1))
+        recent.append(value)
+        if not self._is_flip_flopping(list(recent)):
+            return False
+        if venue_id not in self._flagged:
+            logger.warning(
+                f"[BusynessValidator] {venue_id} busyness flip-flopping: {list(recent)}"
+            )
+        self._flagged[venue_id] = self._now()
+        BUSYNESS_FLIPFLOP_TOTAL.labels(source=source).inc()
+        return True
+
+    def _is_flip_flopping(self, values: list[int]) -> bool:
+        if self.flipflop_swings <= 0 or len(values) < self.flipflop_swings + 1:
+            return False
+        deltas = [b - a for a, b in zip(values, values[1:])]
+        if any(abs(d) < self.flipflop_delta for d in deltas):
+            return False
+        return all((a > 0) != (b > 0) for a, b in zip(deltas, deltas[1:]))
+
+    def flagged_venue_ids(self) -> set[str]:
+        """Venues that flip-flopped within FLAG_RETENTION."""
+        cutoff = self._now() - FLAG_RETENTION
+        self._flagged = {vid: at for vid, at in self._flagged.items() if at >= cutoff}
+        return set(self._flagged)
@@ -0,0 +1,161 @@
+"""Anonymous venue check-ins as a first-party crowd signal.
+
+Clients call POST /v1/venues/{venue_id}/checkin with an opaque device token
+and their coordinates when a user says they are at a venue. The token is
+made up by the client, so it only dedups honest clients; what bounds abuse
+is the rest:
+
+- the coordinates must lie within settings.checkin_max_distance_m of the
+  venue;
+- one client IP counts at most settings.checkin_max_per_ip times per venue
+  per settings.checkin_dedup_minutes (several people on the venue's Wi-Fi
+  share an IP, so this is not 1);
+- a device counts once per venue per checkin_dedup_minutes.
+
+Only hashes of the token and the IP are stored.
+
+Layout:
+- `checkins:v1:{venue_id}`: sorted set of check-in ids scored by epoch
+  seconds, trimmed to the last checkin_window_minutes on every write;
+- `checkin:seen:{venue_id}:{token hash}`: the dedup marker, expiring after
+  checkin_dedup_minutes;
+- `checkin:ip:{venue_id}:{ip hash}`: check-ins counted from that IP,
+  expiring checkin_dedup_minutes after the first.
+
+When BestTime has no live busyness for a venue, nearby and the venue's live
+forecast show one derived from its recent check-ins instead (flagged
+`live_source="checkins"`): checkin_full_count check-ins within the window
+read as 100% busy, and fewer than checkin_min_count show nothing (one person
+is not a crowd).
+"""
+from __future__ import annotations
+
+import hashlib
+import logging
+import time
+import uuid
+from datetime import datetime, timezone
+from typing import Optional
+
+from app.metrics import VENUE_CHECKINS_TOTAL
+from app.models import Analysis, LiveForecastResponse, Venue, VenueInfo
+from app.services.venue_eligibility import haversine_km
+
+logger = logging.getLogger(__name__)
+
+CHECKIN_SOURCE = "checkins"
+CHECKINS_KEY_FORMAT = "checkins:v1:{}"
+SEEN_KEY_FORMAT = "checkin:seen:{}:{}"
+IP_KEY_FORMAT = "checkin:ip:{}:{}"
+
+
+def checkins_to_busyness(count: int, full_count: int) -> int:
+    """Recent check-ins as a 0..100 percentage (full_count and more cap at 100)."""
+    return min(100, round(100 * count / full_count))
+
+
+def _hash(value: str) -> str:
+    return hashlib.sha256(value.encode()).hexdigest()[:32]
+
+
+class CheckinService:
+    """Records check-ins and turns recent ones into live readings."""
+
+    def __init__(
+        self,
+        redis_client,
+        window_minutes: int = 60,
+        dedup_minutes: int = 60,
+        full_count: int = 30,
+        min_count: int = 3,
+        max_distance_m: int = 200,
+        max_per_ip: int = 5,
+        now_fn=None,
+    ):
+        self.redis = redis_client
+        self.window_seconds = window_minutes * 60
+        self.dedup_seconds = dedup_minutes * 60
+        self.full_count = full_count
+        self.min_count = min_count
+        self.max_distance_m = max_distance_m
+        self.max_per_ip = max_per_ip
+        self._now = now_fn or time.time
+
+    def is_near(self, venue: Venue, lat: float, lng: float) -> bool:
+        """Whether (lat, lng) is within max_distance_m of the venue."""
+        if venue.venue_lat is None or venue.venue_lng is None:
+            return False
+        return haversine_km(lat, lng, venue.venue_lat, venue.venue_lng) * 1000 <= self.max_distance_m
+
+    def record(self, venue_id: str, device_token: str, client_ip: str) -> bool:
+        """Count a check-in; False when the device already checked in within
+        the dedup window, or its IP used up its max_per_ip check-ins there."""
+        seen_key = SEEN_KEY_FORMAT.format(venue_id, _hash(device_token))
+        if not self.redis.set(seen_key, 1, nx=True, ex=self.dedup_seconds):
+            VENUE_CHECKINS_TOTAL.labels(result="duplicate").inc()
+            return False
+        ip_key = IP_KEY_FORMAT.format(venue_id, _hash(client_ip))
+        ip_count = self.redis.incr(ip_key)
+        if ip_count == 1:
+            self.redis.expire(ip_key, self.dedup_seconds)
+        if ip_count > self.max_per_ip:
+            VENUE_CHECKINS_TOTAL.labels(result="ip_limited").inc()
+            return False
+        now = self._now()
+        key = CHECKINS_KEY_FORMAT.format(venue_id)
+        pipe = self.redis.pipeline()
+        pipe.zadd(key, {uuid.uuid4().hex: now})
+        pipe.zremrangebyscore(key, "-inf", now - self.window_seconds)
+        pipe.expire(key, self.window_seconds)
+        pipe.execute()
+        VENUE_CHECKINS_TOTAL.labels(result="counted").inc()
+        return True
+
+    def move_venue(self, from_venue_id: str, to_venue_id: str) -> None:
+        """Count `from_venue_id`'s recent check-ins for `to_venue_id` (a dedup
+        merge). The dedup and IP markers are left to expire.
+
+        Copied member by member rather than with ZUNIONSTORE: the two keys sit
+        in different cluster slots, which a multi-key command rejects."""
+        from_key = CHECKINS_KEY_FORMAT.format(from_venue_id)
+        to_key = CHECKINS_KEY_FORMAT.format(to_venue_id)
+        entries = self.redis.zrange(from_key, 0, -1, withscores=True)
+        if not entries:
+            return
+        pipe = self.redis.pipeline()
+        pipe.zadd(to_key, dict(entries))
+        pipe.expire(to_key, self.window_seconds)
+        pipe.delete(from_key)
+        pipe.execute()
+
+    def recent_counts(self, venue_ids: list[str]) -> dict[str, int]:
+        """Check-ins within the window per venue (one round-trip), venues
+        without any left out."""
+        if not venue_ids:
+            return {}
+        since = self._now() - self.window_seconds
+        pipe = self.redis.pipeline()
+        for venue_id in venue_ids:
+            pipe.zcount(CHECKINS_KEY_FORMAT.format(venue_id), since, "+inf")
+        return {vid: n for vid, n in zip(venue_ids, pipe.execute()) if n}
+
+    def live_bulk(self, venue_ids: list[str]) -> dict[str, LiveForecastResponse]:
+        """A live reading for each venue with at least min_count recent
+        check-ins, stamped now."""
+        counts = self.recent_counts(venue_ids)
+        gmttime = datetime.fromtimestamp(self._now(), timezone.utc).isoformat()
+        return {
+            vid: LiveForecastResponse(
+                status="OK",
+                analysis=Analysis(
+                    venue_live_busyness=checkins_to_busyness(n, self.full_count),
+                    venue_live_busyness_available=True,
+                ),
+                venue_info=VenueInfo(venue_id=vid, venue_current_gmttime=gmttime),
+            )
+            for vid, n in counts.items()
+            if n >= self.min_count
+        }
+
+    def live(self, venue_id: str) -> Optional[LiveForecastResponse]:
+        return self.live_bulk([venue_id]).get(venue_id)
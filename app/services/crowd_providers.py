@@ -0,0 +1,238 @@
+"""Pluggable busyness data providers.
+
+BestTime is the only source of live and weekly busyness today, but it is not
+the only one we want: partner turnstile/WiFi counters, Google Popular Times
+scrapers, or manual feeds can be better for the venues (or regions) they
+cover. Each source implements `CrowdDataProvider` and returns the same
+BestTime-shaped models the DAO already caches, so nothing downstream of the
+refresher changes. `CrowdProviderRegistry` picks, per venue, which covering
+providers to ask and how to merge their answers (`MERGE_POLICIES`).
+"""
+from __future__ import annotations
+
+import logging
+from dataclasses import dataclass, field
+from typing import Callable, Container, Optional, Protocol, Sequence
+
+from app.models import LiveForecastResponse, WeekRawResponse
+from app.services.live_freshness import parse_gmttime
+from app.services.venue_data_providers import GOOGLE_VENUE_ID_PREFIX
+from app.services.venue_eligibility import haversine_km
+
+logger = logging.getLogger(__name__)
+
+# priority: first covering provider (in registration order) with usable data wins.
+# freshest: among covering providers with usable data, the latest sample wins.
+MERGE_POLICIES = ("priority", "freshest")
+# Name of the provider whose reads count against the monthly BestTime ledger.
+BESTTIME_PROVIDER = "besttime"
+
+
+class CrowdDataProvider(Protocol):
+    """A source of live and weekly busyness for some set of venues.
+
+    Both getters return None when the provider has nothing for the venue
+    (not an error); exceptions are treated as a failed fetch. A provider with
+    `stores_live = True` already keeps its live readings (and serves them)
+    itself, so the refresher does not cache them as BestTime's.
+    """
+
+    name: str
+
+    def covers(self, venue_id: str, location: Optional[tuple[float, float]]) -> bool: ...
+
+    async def get_live_forecast(self, venue_id: str) -> Optional[LiveForecastResponse]: ...
+
+    async def get_week_raw_forecast(self, venue_id: str) -> Optional[WeekRawResponse]: ...
+
+
+class BestTimeCrowdProvider:
+    """BestTime as a CrowdDataProvider; covers every venue BestTime knows
+    (all but the ones discovered through Google Places)."""
+
+    name = BESTTIME_PROVIDER
+
+    def __init__(
+        self,
+        besttime_api,
+        live_timeout: Optional[float] = None,
+        weekly_timeout: Optional[float] = None,
+    ) -> None:
+        """live_timeout / weekly_timeout: per-call timeouts (seconds) for the
+        refresh reads; None keeps the client-wide timeout."""
+        self.besttime_api = besttime_api
+        self.live_timeout = live_timeout
+        self.weekly_timeout = weekly_timeout
+
+    def covers(self, venue_id: str, location: Optional[tuple[float, float]]) -> bool:
+        return not venue_id.startswith(GOOGLE_VENUE_ID_PREFIX)
+
+    async def get_live_forecast(self, venue_id: str) -> Optional[LiveForecastResponse]:
+        if self.live_timeout is None:
+            return await self.besttime_api.get_live_forecast(venue_id=venue_id)
+        return await self.besttime_api.get_live_forecast(
+            venue_id=venue_id, timeout=self.live_timeout
+        )
+
+    async def get_week_raw_forecast(self, venue_id: str) -> Optional[WeekRawResponse]:
+        if self.weekly_timeout is None:
+            return await self.besttime_api.get_week_raw_forecast(venue_id)
+        return await self.besttime_api.get_week_raw_forecast(
+            venue_id, timeout=self.weekly_timeout
+        )
+
+
+@dataclass(frozen=True)
+class Region:
+    """A circular coverage area."""
+    lat: float
+    lng: float
+    radius_km: float
+
+    def contains(self, lat: float, lng: float) -> bool:
+        return haversine_km(self.lat, self.lng, lat, lng) <= self.radius_km
+
+
+@dataclass
+class RegionalProvider:
+    """Restricts a provider to venues inside any of `regions` (and/or listed
+    explicitly in `venue_ids`). A venue with no known location is only covered
+    through `venue_ids`."""
+    provider: CrowdDataProvider
+    regions: Sequence[Region] = ()
+    venue_ids: Container[str] = field(default_factory=frozenset)
+
+    @property
+    def name(self) -> str:
+        return self.provider.name
+
+    @property
+    def stores_live(self) -> bool:
+        return getattr(self.provider, "stores_live", False)
+
+    def covers(self, venue_id: str, location: Optional[tuple[float, float]]) -> bool:
+        if venue_id in self.venue_ids:
+            return True
+        if location is None:
+            return False
+        return any(r.contains(*location) for r in self.regions)
+
+    async def get_live_forecast(self, venue_id: str) -> Optional[LiveForecastResponse]:
+        return await self.provider.get_live_forecast(venue_id)
+
+    async def get_week_raw_forecast(self, venue_id: str) -> Optional[WeekRawResponse]:
+        return await self.provider.get_week_raw_forecast(venue_id)
+
+
+def _live_usable(lf: Optional[LiveForecastResponse]) -> bool:
+    return (
+        lf is not None
+        and lf.status == "OK"
+        and lf.analysis is not None
+        and bool(lf.analysis.venue_live_busyness_available)
+    )
+
+
+def _sample_time(lf: LiveForecastResponse) -> str:
+    # venue_current_gmttime may be ISO or a BestTime display format; parse it the
+    # same way the history series does so ordering is by instant, not text.
+    raw = lf.venue_info.venue_current_gmttime if lf.venue_info else None
+    parsed = parse_gmttime(raw) if raw else None
+    return parsed.isoformat() if parsed else ""
+
+
+class CrowdProviderRegistry:
+    """Ordered providers plus the merge policy applied when several cover a venue.
+
+    Failure semantics mirror a single BestTime call so the refresher keeps its
+    metrics and delete-on-unavailable behaviour: if no provider has usable data
+    the last non-None answer is returned (e.g. a BestTime "closed" response);
+    if every covering provider raised, the last exception propagates.
+    """
+
+    def __init__(
+        self,
+        providers: Sequence[CrowdDataProvider],
+        policy: str = "priority",
+        locate: Optional[Callable[[str], Optional[tuple[float, float]]]] = None,
+    ) -> None:
+        if policy not in MERGE_POLICIES:
+            raise ValueError(f"unknown crowd merge policy {policy!r}; expected one of {MERGE_POLICIES}")
+        self.providers = list(providers)
+        self.policy = policy
+        self.locate = locate
+
+    def providers_for(self, venue_id: str) -> list[CrowdDataProvider]:
+        location = None
+        if self.locate is not None and any(isinstance(p, RegionalProvider) for p in self.providers):
+            try:
+                location = self.locate(venue_id)
+            except Exception as e:
+                logger.warning(f"[CrowdProviderRegistry] locate failed for {venue_id}: {e}")
+        return [p for p in self.providers if p.covers(venue_id, location)]
+
+    async def get_live_forecast(self, venue_id: str) -> Optional[LiveForecastResponse]:
+        return (await self.fetch_live(venue_id))[0]
+
+    async def fetch_live(
+        self,
+        venue_id: str,
+        allow: Optional[Callable[[CrowdDataProvider], bool]] = None,
+    ) -> tuple[Optional[LiveForecastResponse], Optional[CrowdDataProvider]]:
+        """The merged live answer and the provider it came from.
+
+        `allow(provider)` is asked right before each provider is called; a
+        provider it refuses is skipped as if it did not cover the venue (the
+        refresher's monthly ledger gate on BestTime reads).
+        """
+        usable: list[tuple[LiveForecastResponse, CrowdDataProvider]] = []
+        fallback: tuple[Optional[LiveForecastResponse], Optional[CrowdDataProvider]] = (None, None)
+        error: Optional[Exception] = None
+        answered = False
+        for provider in self.providers_for(venue_id):
+            if allow is not None and not allow(provider):
+                continue
+            try:
+                lf = await provider.get_live_forecast(venue_id)
+            except Exception as e:
+                logger.warning(
+                    f"[CrowdProviderRegistry] {provider.name} live fetch failed for {venue_id}: {e}"
+                )
+                error = e
+                continue
+            answered = True
+            if _live_usable(lf):
+                if self.policy == "priority":
+                    return lf, provider
+                usable.append((lf, provider))
+            elif lf is not None:
+                fallback = (lf, provider)
+        if usable:
+            return max(usable, key=lambda answer: _sample_time(answer[0]))
+        if not answered and error is not None:
+            raise error
+        return fallback
+
+    async def get_week_raw_forecast(self, venue_id: str) -> Optional[WeekRawResponse]:
+        """Weekly curves are not time-stamped samples, so every policy takes
+        the first covering provider with an OK answer."""
+        fallback: Optional[WeekRawResponse] = None
+        error: Optional[Exception] = None
+        answered = False
+        for provider in self.providers_for(venue_id):
+            try:
+                resp = await provider.get_week_raw_forecast(venue_id)
+            except Exception as e:
+                logger.warning(
+                    f"[CrowdProviderRegistry] {provider.name} weekly fetch failed for {venue_id}: {e}"
+                )
+                error = e
+                continue
+            answered = True
+            if resp is not None and resp.status == "OK":
+                return resp
+            if resp is not None:
+                fallback = resp
+        if not answered and error is not None:
+            raise error
+        return fallback
@@ -0,0 +1,187 @@
+"""Synthetic venue catalog for the public demo mode (settings.demo_mode).
+
+Demo mode serves /v1/venues/nearby from a deterministic, generated catalog
+instead of Redis/RDS, so the API can be shown publicly (e.g. to venue partners)
+without touching BestTime-derived data or needing any credentials. Everything
+here is derived from a seed: the same settings always produce the same venues,
+weekly curves, and (for a given minute) live busyness.
+
+`DemoVenueDAO` implements the read surface VenueHandler uses, so the real
+handler (merge, sort, freshness gate, minification) runs unchanged on top.
+"""
+from __future__ import annotations
+
+import math
+import random
+from datetime import datetime, timezone
+from typing import Optional
+
+from app.db.geo_redis_client import radius_to_km
+from app.models import (
+    Analysis,
+    LiveForecastResponse,
+    Venue,
+    VenueInfo,
+    WeekRawDay,
+)
+from app.services.venue_eligibility import haversine_km
+
+# Hourly shape (0..1) per demo venue type; day_raw index 0 = 06:00 (BestTime's
+# raw-day layout starts at 6 AM).
+_SHAPES = {
+    "BAR": [0, 0, 0, 0, 0, .05, .1, .15, .2, .25, .3, .35, .45, .6, .75, .9, 1, .95, .8, .6, .4, .2, .05, 0],
+    "CAFE": [.2, .5, .8, 1, .9, .7, .8, .7, .5, .4, .3, .2, .1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, .05],
+    "RESTAURANT": [0, 0, .1, .2, .4, .8, 1, .7, .4, .3, .4, .7, .95, 1, .7, .4, .2, .05, 0, 0, 0, 0, 0, 0],
+    "CLUBS": [0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, .1, .3, .5, .7, .9, 1, 1, .9, .6, .3, .1],
+}
+# Friday/Saturday busier, Monday quietest (index = BestTime day_int, 0=Mon).
+_DAY_FACTORS = [0.6, 0.65, 0.7, 0.8, 1.0, 1.0, 0.75]
+_NAME_PREFIXES = ["Demo", "Sample", "Example", "Showcase"]
+
+
+def _stable_int(*parts) -> int:
+    """Process-independent hash (Python's str hash is salted per process)."""
+    h = 2166136261
+    for ch in "|".join(str(p) for p in parts):
+        h = ((h ^ ord(ch)) * 16777619) & 0xFFFFFFFF
+    return h
+
+
+def generate_demo_catalog(
+    center_lat: float, center_lng: float, count: int, spread_km: float, seed: int
+) -> list[Venue]:
+    """`count` synthetic venues scattered within `spread_km` of the center."""
+    rng = random.Random(seed)
+    types = list(_SHAPES)
+    venues = []
+    for i in range(count):
+        venue_type = types[i % len(types)]
+        # uniform over the disc
+        dist_km = spread_km * math.sqrt(rng.random())
+        bearing = rng.random() * 2 * math.pi
+        lat = center_lat + (dist_km / 111.0) * math.cos(bearing)
+        lng = center_lng + (dist_km / (111.0 * math.cos(math.radians(center_lat)))) * math.sin(bearing)
+        venues.append(Venue(
+            venue_id=f"demo_{seed}_{i:03d}",
+            venue_name=f"{rng.choice(_NAME_PREFIXES)} {venue_type.title()} {i + 1:02d}",
+            venue_address=f"Rua Demonstração, {100 + i}",
+            venue_lat=round(lat, 6),
+            venue_lng=round(lng, 6),
+            venue_type=venue_type,
+            rating=round(rng.uniform(3.5, 4.9), 1),
+            reviews=rng.randint(20, 2000),
+            price_level=rng.randint(1, 4),
+            forecast=True,
+            processed=True,
+        ))
+    return venues
+
+
+def demo_day_raw(venue: Venue, day_int: int) -> list[int]:
+    """The venue's synthetic 24-value busyness curve for a weekday."""
+    shape = _SHAPES.get(venue.venue_type or "", _SHAPES["BAR"])
+    peak = 60 + _stable_int(venue.venue_id) % 40
+    factor = _DAY_FACTORS[day_int % 7]
+    return [int(round(v * peak * factor)) for v in shape]
+
+
+class DemoVenueDAO:
+    """Read-only, in-memory stand-in for RedisVenueDAO over a generated catalog."""
+
+    def __init__(
+        self,
+        center_lat: float,
+        center_lng: float,
+        count: int = 40,
+        spread_km: float = 5.0,
+        seed: int = 7,
+        now_fn=None,
+    ) -> None:
+        self.venues = {
+            v.venue_id: v
+            for v in generate_demo_catalog(center_lat, center_lng, count, spread_km, seed)
+        }
+        self._now = now_fn or (lambda: datetime.now(timezone.utc))
+
+    # ── geo ─────────────────────────────────────────────────────────────────
+    def get_nearby_venues(
+        self,
+        lat: float,
+        lon: float,
+        radius: float,
+        include_deprecated: bool = False,
+        unit: str = "km",
+    ) -> list[Venue]:
+        radius_km = radius_to_km(radius, unit)
+        out = []
+        for venue in self.venues.values():
+            if haversine_km(lat, lon, venue.venue_lat, venue.venue_lng) <= radius_km:
+                out.append(venue.model_copy())
+        return out
+
+    def get_venue(self, venue_id: str) -> Optional[Venue]:
+        venue = self.venues.get(venue_id)
+        return venue.model_copy() if venue is not None else None
+
+    # ── forecasts ───────────────────────────────────────────────────────────
+    def get_week_raw_forecasts_bulk(self, venue_ids: list[str], day_int: int) -> dict[str, WeekRawDay]:
+        return {
+            vid: WeekRawDay(day_int=day_int, day_raw=demo_day_raw(self.venues[vid], day_int))
+            for vid in venue_ids
+            if vid in self.venues
+        }
+
+    def get_live_forecasts_bulk(self, venue_ids: list[str]) -> dict[str, LiveForecastResponse]:
+        """Live = the weekly value for the current hour plus a small, per-venue
+        wobble that changes every 5 minutes (the "artificial live curve")."""
+        now = self._now()
+        day_int = now.weekday()
+        raw_index = (now.hour - 6) % 24
+        bucket = int(now.timestamp() // 300)
+        out = {}
+        for vid in venue_ids:
+            venue = self.venues.get(vid)
+            if venue is None:
+                continue
+            forecasted = demo_day_raw(venue, day_int)[raw_index]
+            wobble = _stable_int(vid, bucket) % 21 - 10
+            live = max(0, min(100, forecasted + wobble))
+            out[vid] = LiveForecastResponse(
+                status="OK",
+                analysis=Analysis(
+                    venue_forecasted_busyness=forecasted,
+                    venue_live_busyness=live,
+                    venue_live_busyness_available=True,
+                    venue_forecast_busyness_available=True,
+                    venue_live_forecasted_delta=live - forecasted,
+                ),
+                venue_info=VenueInfo(
+                    venue_id=vid,
+                    venue_name=venue.venue_name,
+                    venue_current_gmttime=now.isoformat(),
+                    venue_timezone="America/Recife",
+                ),
+            )
+        return out
+
+    # ── enrichment: the demo catalog carries none ───────────────────────────
+    def get_vibe_attributes_bulk(self, venue_ids: list[str]) -> dict:
+        return {}
+
+    def get_venue_photos_bulk(self, venue_ids: list[str]) -> dict:
+        return {}
+
+    def get_opening_hours_bulk(self, venue_ids: list[str]) -> dict:
+        return {}
+
+    def get_venue_instagram_bulk(self, venue_ids: list[str]) -> dict:
+        return {}
+
+    def get_venue_vibe_profile_bulk(self, venue_ids: list[str]) -> dict:
+        return {}
+
+    def get_venue_reviews(self, venue_id: str):
+        return None
+
+    def get_venue_menu_data(self, venue_id: str):
+        return None
@@ -0,0 +1,147 @@
+"""Runtime-managed discovery locations for the catalog refresh.
+
+The refresh picks its locations, on every run, from the first non-empty of:
+
+1. the `discovery_points` admin-config document (`{"points": [...]}`, RDS with
+   the Redis mirror the refresher reads), managed through /admin/locations;
+2. the JSON or YAML file at `discovery_locations_file` (re-read each run, so an
+   edited file applies without a restart);
+3. the compiled-in DEFAULT_LOCATIONS.
+
+Each point carries its own radius and limit, and optionally its own venue
+`types` and `live` flag, so a dense centre can be queried tightly and a sparse
+suburb broadly. A point's `current` counts the venues discovery has fetched
+around it; the point is skipped once `current` reaches `limit` (see
+recount-discovery-points).
+"""
+from __future__ import annotations
+
+import json
+import logging
+from pathlib import Path
+from typing import Any, Optional
+
+from pydantic import BaseModel, ConfigDict, Field, ValidationError
+
+logger = logging.getLogger(__name__)
+
+ADMIN_CONFIG_DISCOVERY_POINTS_KEY = "discovery_points"
+
+
+class DiscoveryPoint(BaseModel):
+    # Unknown keys (e.g. set by the admin dashboard) are kept on write.
+    model_config = ConfigDict(extra="allow")
+
+    id: str = Field(min_length=1, max_length=80)
+    label: Optional[str] = None
+    lat: float = Field(ge=-90, le=90)
+    lng: float = Field(ge=-180, le=180)
+    radius: int = Field(default=15000, gt=0, le=50000)  # meters
+    limit: int = Field(default=500, ge=0)
+    current: int = Field(default=0, ge=0)
+    # Query overrides for this point (None = the discovery_search defaults):
+    # BestTime venue types, and whether to ask only for venues with live data.
+    types: Optional[list[str]] = Field(default=None, min_length=1)
+    live: Optional[bool] = None
+
+
+class DiscoveryPointConflictError(ValueError):
+    """A point with that id already exists."""
+
+
+def _validate_points(raw_points: Any) -> list[dict]:
+    if not isinstance(raw_points, list):
+        raise TypeError("discovery points must be a list")
+    points, seen = [], set()
+    for raw in raw_points:
+        try:
+            point = DiscoveryPoint.model_validate(raw)
+        except ValidationError as e:
+            raise ValueError(f"invalid discovery point {raw!r}: {e}") from e
+        if point.id in seen:
+            raise ValueError(f"duplicate discovery point id {point.id!r}")
+        seen.add(point.id)
+        points.append(point.model_dump(exclude_none=True))
+    return points
+
+
+def validate_discovery_points_config(value: Any) -> dict:
+    """Admin-config validator: {"points": [DiscoveryPoint, ...]} with unique ids."""
+    if not isinstance(value, dict) or "points" not in value:
+        raise TypeError('discovery_points must be an object {"points": [...]}')
+    return {"points": _validate_points(value["points"])}
+
+
+def load_locations_file(path: str) -> list[dict]:
+    """Points from a JSON or YAML (.yaml/.yml) file holding either a list of
+    points or {"points": [...]}.
+
+    Raises:
+        OSError: the file cannot be read
+        ValueError: the content is not a valid point list
+    """
+    text = Path(path).read_text(encoding="utf-8")
+    if Path(path).suffix.lower() in (".yaml", ".yml"):
+        import yaml  # PyYAML ships with uvicorn[standard]
+
+        data = yaml.safe_load(text)
+    else:
+        data = json.loads(text)
+    if isinstance(data, dict):
+        data = data.get("points")
+    return _validate_points(data)
+
+
+class DiscoveryLocationsService:
+    """Per-point CRUD over the `discovery_points` admin-config document."""
+
+    def __init__(self, admin_config_service) -> None:
+        self.admin_config_service = admin_config_service
+
+    def list_points(self) -> list[dict]:
+        config = self.admin_config_service.get(ADMIN_CONFIG_DISCOVERY_POINTS_KEY) or {}
+        return list(config.get("points", []))
+
+    def get_point(self, point_id: str) -> Optional[dict]:
+        return next((p for p in self.list_points() if p.get("id") == point_id), None)
+
+    def _write(self, points: list[dict]) -> list[dict]:
+        stored = self.admin_config_service.set(
+            ADMIN_CONFIG_DISCOVERY_POINTS_KEY, {"points": points}, updated_by="admin"
+        )
+        return stored["points"]
+
+    def create_point(self, point: dict) -> dict:
+        """Add a point; returns it as stored.
+
+        Raises:
+            DiscoveryPointConflictError: the id is taken
+            ValueError: the point is invalid
+        """
+        points = self.list_points()
+        if any(p.get("id") == point.get("id") for p in points):
+            raise DiscoveryPointConflictError(f"discovery point {point.get('id')!r} already exists")
+        return self._write(points + [point])[-1]
+
+    def update_point(self, point_id: str, changes: dict) -> Optional[dict]:
+        """Apply `changes` to a point (its `current` counter is kept unless
+        given); returns it as stored, or None when there is no such point.
+
+        Raises:
+            ValueError: the result is invalid
+        """
+        points = self.list_points()
+        for i, existing in enumerate(points):
+            if existing.get("id") == point_id:
+                points[i] = {**existing, **changes, "id": point_id}
+                return self._write(points)[i]
+        return None
+
+    def delete_point(self, point_id: str) -> bool:
+        """Remove a point; False when there was none."""
+        points = self.list_points()
+        remaining = [p for p in points if p.get("id") != point_id]
+        if len(remaining) == len(points):
+            return False
+        self._write(remaining)
+        return True
@@ -40,25 +40,31 @@ class EligibilityRuleService:
         return eligibility_config_from_rules(self.rds_store.list_eligibility_rules())
 
     # ── writes (rows are truth; the mirror is reassembled from them) ───────────
-    def add_rule(self, rule_type: str, value: str, updated_by=None) -> EligibilityConfig:
+    # ``verify`` reads the re-assembled mirror back before returning
+    # (AdminConfigService.verify).
+    def add_rule(
+        self, rule_type: str, value: str, updated_by=None, verify=False
+    ) -> EligibilityConfig:
         rt, v = self._validate(rule_type, value)
         self.rds_store.add_eligibility_rule(rt, v, updated_by)
         logger.info("[eligibility] rule added %s=%r by %s", rt, v, updated_by)
-        return self._remirror(updated_by)
+        return self._remirror(updated_by, verify)
 
-    def remove_rule(self, rule_type: str, value: str, updated_by=None) -> EligibilityConfig:
+    def remove_rule(
+        self, rule_type: str, value: str, updated_by=None, verify=False
+    ) -> EligibilityConfig:
         rt, v = self._validate(rule_type, value)
         self.rds_store.remove_eligibility_rule(rt, v)
         logger.info("[eligibility] rule removed %s=%r by %s", rt, v, updated_by)
-        return self._remirror(updated_by)
+        return self._remirror(updated_by, verify)
 
-    def set_full_config(self, blob: dict, updated_by=None) -> EligibilityConfig:
+    def set_full_config(self, blob: dict, updated_by=None, verify=False) -> EligibilityConfig:
         """Replace all rows from a full override blob (validated), then re-mirror."""
         EligibilityConfig.from_dict(blob, from_admin_override=True)  # raises on invalid
         self.rds_store.replace_eligibility_rules(
             decompose_eligibility_blob(blob), updated_by
         )
-        return self._remirror(updated_by)
+        return self._remirror(updated_by, verify)
 
     # ── helpers ────────────────────────────────────────────────────────────────
     def _validate(self, rule_type: str, value: str) -> tuple[str, str]:
@@ -69,23 +75,23 @@ class EligibilityRuleService:
             raise ValueError("eligibility rule value must be non-empty")
         return rule_type, normalize_rule_value(rule_type, v)
 
-    def _project_mirror(self, rules) -> None:
+    def _project_mirror(self, rules, verify=False) -> None:
         """Write the Redis serving mirror directly from the rows (or clear it when
         no rows remain). Redis-only: the rows are the sole durable truth, so no
         redundant RDS admin_config blob is persisted."""
         if rules:
             self.admin_config_service.set_mirror(
-                _ELIGIBILITY_KEY, assemble_eligibility_blob(rules)
+                _ELIGIBILITY_KEY, assemble_eligibility_blob(rules), verify=verify
             )
         else:
             # No override left -> drop the key so readers fall back to defaults.
-            self.admin_config_service.delete_mirror(_ELIGIBILITY_KEY)
+            self.admin_config_service.delete_mirror(_ELIGIBILITY_KEY, verify=verify)
 
-    def _remirror(self, updated_by=None) -> EligibilityConfig:
+    def _remirror(self, updated_by=None, verify=False) -> EligibilityConfig:
         # Admin write path: propagate errors so the caller surfaces a retryable
         # failure (the router maps this to HTTP 502).
         rules = self.rds_store.list_eligibility_rules()
-        self._project_mirror(rules)
+        self._project_mirror(rules, verify)
         return eligibility_config_from_rules(rules)
 
     def rehydrate_mirror(self) -> None:
@@ -80,6 +80,22 @@ class EngagementService:
         # hot_like_event log is immutable history and is intentionally kept.
         self.redis.srem(self._hot_key(venue_id), user_id)
 
+    def move_venue(self, from_venue_id: str, to_venue_id: str) -> None:
+        # Dedup merge (VenueDeduplicator reference mover): RDS first, then the
+        # Redis sets. Favorite sets are keyed by raw user id, which RDS does not
+        # keep, so they are scanned. Idempotent: a retried merge finds nothing
+        # left under from_venue_id.
+        self.rds_store.move_favorites(from_venue_id, to_venue_id)
+        for key in self.redis.scan_iter(match=self._fav_key("*")):
+            if self.redis.srem(key, from_venue_id):
+                self.redis.sadd(key, to_venue_id)
+        from_key, to_key = self._hot_key(from_venue_id), self._hot_key(to_venue_id)
+        ttl = self.redis.ttl(from_key)
+        if ttl > 0:
+            self.redis.sunionstore(to_key, [to_key, from_key])
+            self.redis.expire(to_key, max(ttl, self.redis.ttl(to_key)))
+        self.redis.delete(from_key)
+
     # App-activity system-of-record. Unlike favorites/hot_likes this is RDS-only
     # (no Redis projection): the counts are read by the admin from RDS, never on
     # the serve path. One pseudonymized row per user per Recife day.
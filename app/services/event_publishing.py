@@ -0,0 +1,253 @@
+"""Venue events for a message broker (Kafka or NATS).
+
+The refresher records a `venue_upserted` event after every venue upsert and a
+`live_forecast_set` event after every live forecast it caches, so analytics
+pipelines can consume crowd data without polling the API:
+
+    {"event_id": "4f1c...", "type": "live_forecast_set", "venue_id": "ven_123",
+     "occurred_at": "2026-10-16T21:00:00+00:00", "data": {...}}
+
+`data` is the venue or the live forecast as the API serializes it. Events are
+not sent inline: they are appended to a Redis outbox (`events:outbox:v1`, a
+list, oldest first) and EventRelay.drain(), run by the `event_outbox` job,
+publishes them in order and removes them only once the broker has acked them.
+Acks remove the published entries by value (each carries a unique event_id),
+not by position, so the overflow trim in add() between a peek and its ack
+cannot make the ack remove unpublished events; the job runs under the
+`event_outbox` job lock, so two drains never publish the same batch.
+A failed publish leaves the batch in the outbox for the next run, so a broker
+outage delays events instead of losing them. Delivery is at-least-once;
+consumers dedupe on `event_id`. The outbox keeps at most `max_events`; past
+that the oldest are dropped (events_outbox_total{result="dropped"}).
+
+Kafka: one topic, keyed by venue id so a venue's events stay ordered within a
+partition. NATS: the subject is `<subject>.<type>`, flushed after each batch.
+The broker clients (aiokafka, nats-py) are imported only when used.
+"""
+from __future__ import annotations
+
+import json
+import logging
+import uuid
+from datetime import datetime, timezone
+from typing import Any, Optional, Protocol
+
+from app.dao.change_events import LIVE_FORECAST_SET, VENUE_UPSERTED
+from app.metrics import EVENTS_OUTBOX_PENDING, EVENTS_OUTBOX_TOTAL
+
+logger = logging.getLogger(__name__)
+
+OUTBOX_KEY = "events:outbox:v1"
+EVENT_TYPES = (VENUE_UPSERTED, LIVE_FORECAST_SET)
+
+
+def make_event(event_type: str, venue_id: str, data: Any, now: Optional[datetime] = None) -> dict:
+    """One event document (see the module docstring)."""
+    if event_type not in EVENT_TYPES:
+        raise ValueError(f"unknown event type {event_type!r}")
+    return {
+        "event_id": uuid.uuid4().hex,
+        "type": event_type,
+        "venue_id": venue_id,
+        "occurred_at": (now or datetime.now(timezone.utc)).isoformat(),
+        "data": data,
+    }
+
+
+def encode_event(event: dict) -> bytes:
+    return json.dumps(event, separators=(",", ":"), ensure_ascii=False).encode("utf-8")
+
+
+class EventOutbox:
+    """Events waiting for the broker, in a Redis list (oldest first)."""
+
+    def __init__(self, redis_client, max_events: int = 100_000, key: str = OUTBOX_KEY):
+        self.redis = redis_client
+        self.max_events = max(1, max_events)
+        self.key = key
+
+    def add(self, event: dict) -> None:
+        """Append an event, trimming the oldest past max_events.
+
+        Raises:
+            redis.RedisError
+        """
+        size = self.redis.rpush(self.key, json.dumps(event, separators=(",", ":")))
+        EVENTS_OUTBOX_TOTAL.labels(result="queued").inc()
+        if size > self.max_events:
+            self.redis.ltrim(self.key, -self.max_events, -1)
+            EVENTS_OUTBOX_TOTAL.labels(result="dropped").inc(size - self.max_events)
+            logger.warning(
+                f"[EventOutbox] Outbox full ({self.max_events}); dropped "
+                f"{size - self.max_events} oldest events"
+            )
+
+    def peek(self, limit: int) -> list[str]:
+        """The `limit` oldest events, still raw JSON."""
+        return self.redis.lrange(self.key, 0, limit - 1)
+
+    def ack(self, entries: list[str]) -> None:
+        """Remove these peeked entries (published or unreadable); any the
+        overflow trim already dropped are simply not found."""
+        if not entries:
+            return
+        pipe = self.redis.pipeline()
+        for entry in entries:
+            pipe.lrem(self.key, 1, entry)
+        pipe.execute()
+
+    def size(self) -> int:
+        return self.redis.llen(self.key)
+
+
+class EventPublisher(Protocol):
+    """A broker connection. publish() returns once the broker has every event
+    of the batch, or raises; nothing is partially acked."""
+
+    async def publish(self, events: list[dict]) -> None: ...
+
+    async def close(self) -> None: ...
+
+
+class KafkaEventPublisher:
+    def __init__(
+        self,
+        bootstrap_servers: list[str],
+        topic: str,
+        client_id: str = "cs-server",
+        timeout_seconds: float = 10.0,
+    ):
+        self.bootstrap_servers = bootstrap_servers
+        self.topic = topic
+        self.client_id = client_id
+        self.timeout_seconds = timeout_seconds
+        self._producer = None
+
+    async def _connect(self):
+        if self._producer is None:
+            from aiokafka import AIOKafkaProducer
+
+            producer = AIOKafkaProducer(
+                bootstrap_servers=self.bootstrap_servers,
+                client_id=self.client_id,
+                acks="all",
+                enable_idempotence=True,
+                request_timeout_ms=int(self.timeout_seconds * 1000),
+            )
+            try:
+                await producer.start()
+            except Exception:
+                await producer.stop()
+                raise
+            self._producer = producer
+        return self._producer
+
+    async def publish(self, events: list[dict]) -> None:
+        producer = await self._connect()
+        batch = [
+            await producer.send(self.topic, value=encode_event(e), key=e["venue_id"].encode("utf-8"))
+            for e in events
+        ]
+        for delivery in batch:
+            await delivery
+
+    async def close(self) -> None:
+        if self._producer is not None:
+            producer, self._producer = self._producer, None
+            await producer.stop()
+
+
+class NatsEventPublisher:
+    def __init__(self, servers: list[str], subject: str, timeout_seconds: float = 10.0):
+        self.servers = servers
+        self.subject = subject
+        self.timeout_seconds = timeout_seconds
+        self._nc = None
+
+    async def _connect(self):
+        if self._nc is None or self._nc.is_closed:
+            import nats
+
+            self._nc = await nats.connect(
+                servers=self.servers, connect_timeout=self.timeout_seconds
+            )
+        return self._nc
+
+    async def publish(self, events: list[dict]) -> None:
+        nc = await self._connect()
+        for event in events:
+            await nc.publish(f"{self.subject}.{event['type']}", encode_event(event))
+        await nc.flush(timeout=self.timeout_seconds)
+
+    async def close(self) -> None:
+        if self._nc is not None:
+            nc, self._nc = self._nc, None
+            await nc.drain()
+
+
+def build_event_publisher(settings) -> Optional[EventPublisher]:
+    """The publisher for settings.events_broker; None when it is empty."""
+    if settings.events_broker == "kafka":
+        return KafkaEventPublisher(
+            settings.events_kafka_bootstrap_servers,
+            settings.events_topic,
+            timeout_seconds=settings.events_publish_timeout_seconds,
+        )
+    if settings.events_broker == "nats":
+        return NatsEventPublisher(
+            settings.events_nats_servers,
+            settings.events_topic,
+            timeout_seconds=settings.events_publish_timeout_seconds,
+        )
+    return None
+
+
+class EventRelay:
+    """Moves events from the outbox to the broker."""
+
+    def __init__(self, outbox: EventOutbox, publisher: EventPublisher, batch_size: int = 500):
+        self.outbox = outbox
+        self.publisher = publisher
+        self.batch_size = max(1, batch_size)
+
+    async def drain(self) -> dict:
+        """Publish the outbox in batches until it is empty or a publish fails.
+
+        Returns:
+            {"published", "skipped" (unreadable entries removed), "pending",
+            "error" (the failed publish, or None)}
+        """
+        summary = {"published": 0, "skipped": 0, "pending": 0, "error": None}
+        while True:
+            raw = self.outbox.peek(self.batch_size)
+            if not raw:
+                break
+            events = []
+            for entry in raw:
+                try:
+                    events.append(json.loads(entry))
+                except ValueError:
+                    summary["skipped"] += 1
+                    logger.warning(f"[EventRelay] Dropping unreadable outbox entry: {entry[:200]!r}")
+            if events:
+                try:
+                    await self.publisher.publish(events)
+                except Exception as e:
+                    EVENTS_OUTBOX_TOTAL.labels(result="failed").inc(len(events))
+                    summary["error"] = str(e)
+                    logger.warning(
+                        f"[EventRelay] Publishing {len(events)} events failed; "
+                        f"kept for the next run: {e}"
+                    )
+                    break
+            self.outbox.ack(raw)
+            EVENTS_OUTBOX_TOTAL.labels(result="published").inc(len(events))
+            summary["published"] += len(events)
+            if len(raw) < self.batch_size:
+                break
+        summary["pending"] = self.outbox.size()
+        EVENTS_OUTBOX_PENDING.set(summary["pending"])
+        return summary
+
+    async def close(self) -> None:
+        await self.publisher.close()
@@ -0,0 +1,156 @@
+"""Per-region tuning of the discovery filter profile (multi-armed bandit).
+
+Each discovery region (a discovery point id, or the "lat,lng" label of a
+configured location) has its own epsilon-greedy bandit over a small grid of
+filter profiles ("arms"): a radius scale, a busy_min and a limit scale
+applied on top of the region's configured radius/limit and the base
+SearchParams. Every discovery call pulls one arm and is scored as
+
+    venues with live data / BestTime credits spent
+
+where credits are estimated as one per venue the filter returned (minimum one
+per call). Over successive refresh runs each region converges on the profile
+that yields the most live-data venues per credit, while `epsilon` of the
+calls keep exploring.
+
+Per-region arm statistics live in one Redis hash (`filter_tuner:stats`,
+field = region, value = JSON {arm_key: {pulls, reward_sum}}) so they survive
+restarts and are shared by replicas; GET /admin/venues/filter-tuning shows
+them. The whole feature is off unless settings.filter_tuner_enabled.
+"""
+from __future__ import annotations
+
+import json
+import logging
+import random
+from dataclasses import asdict, dataclass
+from typing import Callable, Optional
+
+logger = logging.getLogger(__name__)
+
+FILTER_TUNER_STATS_KEY = "filter_tuner:stats"
+
+
+@dataclass(frozen=True)
+class FilterArm:
+    """One filter profile the bandit can try."""
+
+    radius_scale: float
+    busy_min: int
+    limit_scale: float
+
+    @property
+    def key(self) -> str:
+        return f"r{self.radius_scale:g}-b{self.busy_min}-l{self.limit_scale:g}"
+
+    def apply(self, radius: int, limit: int) -> tuple[int, int]:
+        """The (radius, limit) this arm queries for a region's configured ones."""
+        return max(1, int(radius * self.radius_scale)), max(1, int(limit * self.limit_scale))
+
+
+# radius x busy_min x limit. The 1.0/0/1.0 arm is the untuned profile, so a
+# region can always fall back to the configured settings.
+DEFAULT_ARMS: tuple[FilterArm, ...] = tuple(
+    FilterArm(radius_scale, busy_min, limit_scale)
+    for radius_scale in (0.5, 1.0)
+    for busy_min in (0, 20)
+    for limit_scale in (0.5, 1.0)
+)
+
+
+def estimate_credits(venues_returned: int) -> int:
+    """BestTime credits one filter call is estimated to cost."""
+    return max(1, venues_returned)
+
+
+class FilterTuner:
+    """Epsilon-greedy arm selection and scoring, one bandit per region."""
+
+    def __init__(
+        self,
+        redis_client,
+        epsilon: float = 0.1,
+        arms: tuple[FilterArm, ...] = DEFAULT_ARMS,
+        rng: Optional[Callable[[], float]] = None,
+    ) -> None:
+        if not 0.0 <= epsilon <= 1.0:
+            raise ValueError("epsilon must be between 0 and 1")
+        self.redis = redis_client
+        self.epsilon = epsilon
+        self.arms = arms
+        self._random = random.Random()
+        self._rng = rng or self._random.random
+
+    def _load(self, region: str) -> dict[str, dict]:
+        try:
+            raw = self.redis.hget(FILTER_TUNER_STATS_KEY, region)
+            return json.loads(raw) if raw else {}
+        except Exception as e:
+            logger.warning(f"[FilterTuner] Failed to read stats for {region}: {e}")
+            return {}
+
+    @staticmethod
+    def _mean(stat: Optional[dict]) -> float:
+        if not stat or not stat.get("pulls"):
+            return 0.0
+        return stat["reward_sum"] / stat["pulls"]
+
+    def choose(self, region: str) -> FilterArm:
+        """The arm to query `region` with this run: untried arms first, then
+        the best mean reward, exploring at random `epsilon` of the time."""
+        stats = self._load(region)
+        untried = [arm for arm in self.arms if arm.key not in stats]
+        if untried:
+            return untried[0]
+        if self._rng() < self.epsilon:
+            return self._random.choice(self.arms)
+        return max(self.arms, key=lambda arm: self._mean(stats.get(arm.key)))
+
+    def record(self, region: str, arm: FilterArm, live_venues: int, credits: int) -> float:
+        """Score one pull of `arm` in `region`; returns the reward. Never raises."""
+        reward = live_venues / max(1, credits)
+        stats = self._load(region)
+        stat = stats.setdefault(arm.key, {"pulls": 0, "reward_sum": 0.0})
+        stat["pulls"] += 1
+        stat["reward_sum"] += reward
+        try:
+            self.redis.hset(FILTER_TUNER_STATS_KEY, region, json.dumps(stats))
+        except Exception as e:
+            logger.warning(f"[FilterTuner] Failed to save stats for {region}: {e}")
+        logger.info(
+            f"[FilterTuner] {region}: arm {arm.key} scored {reward:.3f} "
+            f"({live_venues} live / {credits} credits)"
+        )
+        return reward
+
+    def stats(self) -> dict[str, dict]:
+        """Per-region arm statistics and the current best arm, for the admin UI."""
+        try:
+            raw = self.redis.hgetall(FILTER_TUNER_STATS_KEY) or {}
+        except Exception as e:
+            logger.warning(f"[FilterTuner] Failed to read stats: {e}")
+            return {}
+        by_key = {arm.key: arm for arm in self.arms}
+        out = {}
+        for region, blob in sorted(raw.items()):
+            stats = json.loads(blob)
+            best = max(stats, key=lambda k: self._mean(stats[k])) if stats else None
+            out[region] = {
+                "best_arm": best,
+                "best_params": asdict(by_key[best]) if best in by_key else None,
+                "arms": {
+                    key: {
+                        "pulls": stat["pulls"],
+                        "mean_reward": round(self._mean(stat), 4),
+                    }
+                    for key, stat in stats.items()
+                },
+            }
+        return out
+
+    def reset(self, region: Optional[str] = None) -> None:
+        """Forget the statistics of one region (or all)."""
+        if region is None:
+            self.redis.delete(FILTER_TUNER_STATS_KEY)
+        else:
+            self.redis.hdel(FILTER_TUNER_STATS_KEY, region)
@@ -0,0 +1,141 @@
+"""Seed Redis from a JSON fixture (`python main.py seed --fixture <file>`).
+
+Populates a local/dev Redis with venues, live forecasts and weekly forecasts
+without calling BestTime. A fixture is a JSON object with any of:
+
+    {
+      "venues": [<Venue>, ...],
+      "live_forecasts": [<LiveForecastResponse>, ...],
+      "weekly_forecasts": [{"venue_id": "...", "week_raw": [<WeekRawDay>, ...]}, ...]
+    }
+
+The entries use the shapes the server stores (and BestTime returns): a venue
+may spell its longitude `venue_lon` as in BestTime's search responses, and a
+weekly entry may be a whole /forecasts/week/raw2 response (`venue_id` +
+`analysis.week_raw`). A bare venue object or a list of venues is read as
+`{"venues": [...]}`, so resources/venue_static.json seeds as is.
+
+The whole fixture is validated before anything is written. Live forecasts
+without `refreshed_at` are stamped now, so the serving freshness gate treats
+them as just fetched. Writes go through RedisVenueDAO (Redis only, never RDS).
+"""
+from __future__ import annotations
+
+import json
+import logging
+from dataclasses import dataclass
+from datetime import datetime, timezone
+from pathlib import Path
+from typing import Optional
+
+from pydantic import ValidationError
+
+from app.models import LiveForecastResponse, Venue, WeekRawDay
+
+logger = logging.getLogger(__name__)
+
+SECTIONS = ("venues", "live_forecasts", "weekly_forecasts")
+
+
+@dataclass
+class SeedFixture:
+    """A validated fixture, ready to write."""
+
+    venues: list[Venue]
+    live_forecasts: list[LiveForecastResponse]
+    # (venue_id, day) pairs
+    weekly_forecasts: list[tuple[str, WeekRawDay]]
+
+
+def parse_venue(raw: dict) -> Venue:
+    """A Venue from its stored or BestTime JSON (`venue_lon` is accepted)."""
+    if "venue_lng" not in raw and "venue_lon" in raw:
+        raw = {**raw, "venue_lng": raw["venue_lon"]}
+    return Venue.model_validate(raw)
+
+
+def _week_days(raw: dict) -> list[tuple[str, WeekRawDay]]:
+    venue_id = raw.get("venue_id")
+    if not venue_id:
+        raise ValueError("missing venue_id")
+    days = raw.get("week_raw")
+    if days is None:
+        days = (raw.get("analysis") or {}).get("week_raw")
+    if not isinstance(days, list):
+        raise ValueError("missing week_raw")
+    return [(venue_id, WeekRawDay.model_validate(day)) for day in days]
+
+
+def parse_fixture(data, errors: Optional[list[str]] = None) -> SeedFixture:
+    """Validate a decoded fixture.
+
+    Args:
+        errors: when given, a bad entry does not raise; its "section[i]: ..."
+            message is appended here and the entry is left out
+
+    Raises:
+        ValueError: wrong shape or an entry the models reject, naming the
+            section and index of the first bad entry
+    """
+    if isinstance(data, list) or (isinstance(data, dict) and "venue_id" in data):
+        data = {"venues": data if isinstance(data, list) else [data]}
+    if not isinstance(data, dict) or not any(section in data for section in SECTIONS):
+        raise ValueError(f"a fixture is an object with {', '.join(SECTIONS)}, or a list of venues")
+
+    parsers = {
+        "venues": lambda raw: [parse_venue(raw)],
+        "live_forecasts": lambda raw: [LiveForecastResponse.model_validate(raw)],
+        "weekly_forecasts": _week_days,
+    }
+    parsed = {}
+    for section, parse in parsers.items():
+        entries = data.get(section) or []
+        if not isinstance(entries, list):
+            raise ValueError(f"{section} must be a list")
+        parsed[section] = []
+        for index, raw in enumerate(entries):
+            try:
+                if not isinstance(raw, dict):
+                    raise ValueError("not an object")
+                parsed[section] += parse(raw)
+            except (ValidationError, ValueError) as e:
+                if errors is None:
+                    raise ValueError(f"{section}[{index}]: {e}") from None
+                errors.append(f"{section}[{index}]: {e}")
+    return SeedFixture(parsed["venues"], parsed["live_forecasts"], parsed["weekly_forecasts"])
+
+
+def load_fixture(path: str | Path) -> SeedFixture:
+    """Read and validate a fixture file.
+
+    Raises:
+        OSError: unreadable file
+        ValueError: not JSON, or see parse_fixture()
+    """
+    try:
+        data = json.loads(Path(path).read_text(encoding="utf-8"))
+    except json.JSONDecodeError as e:
+        raise ValueError(f"{path} is not valid JSON: {e}") from None
+    return parse_fixture(data)
+
+
+def seed_redis(venue_dao, fixture: SeedFixture, now=None) -> dict:
+    """Write the fixture through `venue_dao` (a RedisVenueDAO); returns counts."""
+    now = now or datetime.now(timezone.utc)
+    venues = venue_dao.upsert_venues(fixture.venues) if fixture.venues else 0
+    for forecast in fixture.live_forecasts:
+        if forecast.refreshed_at is None:
+            forecast.refreshed_at = now
+        venue_dao.set_live_forecast(forecast)
+    for venue_id, day in fixture.weekly_forecasts:
+        venue_dao.set_week_raw_forecast(venue_id, day)
+    summary = {
+        "venues": venues,
+        "live_forecasts": len(fixture.live_forecasts),
+        "weekly_forecasts": len(fixture.weekly_forecasts),
+    }
+    logger.info(
+        f"[Seed] Wrote {summary['venues']} venues, {summary['live_forecasts']} live "
+        f"forecasts, {summary['weekly_forecasts']} weekly forecast days"
+    )
+    return summary
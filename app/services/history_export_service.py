@@ -0,0 +1,178 @@
+"""Nightly export of busyness history and catalog changes for the data team.
+
+Each run exports one complete UTC day (yesterday, by default) as two datasets:
+
+- busyness_history: every recorded live busyness sample (the live history
+  series in Redis) of the active catalog, one row per sample;
+- venue_catalog_deltas: venues added to or deprecated in the catalog (RDS)
+  that day, one row per change.
+
+Each dataset is written as one object under
+settings.history_export_path_template, e.g.
+``analytics/busyness_history/date=2026-10-15/part-0000.ndjson.gz``, so the
+warehouse can mount the prefix as a date-partitioned external table. NDJSON is
+gzip-compressed; Parquet needs pyarrow installed. Re-running a day overwrites
+its objects, so a failed night can simply be re-exported.
+"""
+from __future__ import annotations
+
+import asyncio
+import gzip
+import io
+import json
+import logging
+from datetime import date, datetime, time, timedelta, timezone
+from typing import Optional
+
+from app.metrics import HISTORY_EXPORT_ROWS_TOTAL
+
+logger = logging.getLogger(__name__)
+
+EXPORT_FORMATS = ("ndjson", "parquet")
+HISTORY_DATASET = "busyness_history"
+CATALOG_DELTAS_DATASET = "venue_catalog_deltas"
+
+_EXTENSIONS = {"ndjson": "ndjson.gz", "parquet": "parquet"}
+_CONTENT_TYPES = {"ndjson": "application/x-ndjson", "parquet": "application/vnd.apache.parquet"}
+
+
+def render_path(template: str, dataset: str, day: date, fmt: str) -> str:
+    """Object key for one dataset/day. Template fields: {dataset}, {date}
+    (YYYY-MM-DD), {year}, {month}, {day} and {ext}.
+
+    Raises:
+        ValueError: the template uses an unknown field
+    """
+    try:
+        return template.format(
+            dataset=dataset,
+            date=day.isoformat(),
+            year=f"{day.year:04d}",
+            month=f"{day.month:02d}",
+            day=f"{day.day:02d}",
+            ext=_EXTENSIONS[fmt],
+        )
+    except (KeyError, IndexError) as e:
+        raise ValueError(f"invalid history export path template {template!r}: {e}") from e
+
+
+def encode_rows(rows: list[dict], fmt: str) -> bytes:
+    """Serialize rows as gzip NDJSON or Parquet.
+
+    Raises:
+        ValueError: unknown format
+        RuntimeError: parquet requested but pyarrow is not installed
+    """
+    if fmt == "ndjson":
+        lines = "".join(json.dumps(row, separators=(",", ":")) + "\n" for row in rows)
+        return gzip.compress(lines.encode("utf-8"))
+    if fmt == "parquet":
+        try:
+            import pyarrow as pa
+            import pyarrow.parquet as pq
+        except ImportError as e:
+            raise RuntimeError("history_export_format=parquet requires pyarrow") from e
+        buf = io.BytesIO()
+        pq.write_table(pa.Table.from_pylist(rows), buf)
+        return buf.getvalue()
+    raise ValueError(f"unknown export format {fmt!r}; expected one of {EXPORT_FORMATS}")
+
+
+def _as_utc(value) -> Optional[datetime]:
+    if isinstance(value, str):
+        try:
+            value = datetime.fromisoformat(value)
+        except ValueError:
+            return None
+    if not isinstance(value, datetime):
+        return None
+    return value if value.tzinfo else value.replace(tzinfo=timezone.utc)
+
+
+def _as_float(value) -> Optional[float]:
+    # RDS numeric columns come back as Decimal, which JSON cannot encode.
+    return float(value) if value is not None else None
+
+
+class HistoryExportService:
+    """Builds the two daily datasets and uploads them to object storage."""
+
+    def __init__(
+        self,
+        venue_dao,
+        rds_store,
+        storage,
+        path_template: str,
+        fmt: str = "ndjson",
+        now_fn=None,
+    ) -> None:
+        if fmt not in EXPORT_FORMATS:
+            raise ValueError(f"unknown export format {fmt!r}; expected one of {EXPORT_FORMATS}")
+        # Fail at startup, not at 00:30, on a bad template.
+        render_path(path_template, HISTORY_DATASET, date(2000, 1, 1), fmt)
+        self.venue_dao = venue_dao
+        self.rds_store = rds_store
+        self.storage = storage
+        self.path_template = path_template
+        self.fmt = fmt
+        self._now = now_fn or (lambda: datetime.now(timezone.utc))
+
+    async def export_day(self, day: Optional[date] = None) -> dict:
+        """Export `day` (default: yesterday UTC); returns {dataset: {rows, uri}}."""
+        if day is None:
+            day = (self._now() - timedelta(days=1)).date()
+        # RDS + Redis reads are blocking; keep them off the event loop.
+        datasets = await asyncio.to_thread(self._collect, day)
+        summary = {"date": day.isoformat()}
+        for dataset, rows in datasets.items():
+            key = render_path(self.path_template, dataset, day, self.fmt)
+            uri = await self.storage.put_object(
+                key,
+                encode_rows(rows, self.fmt),
+                content_type=_CONTENT_TYPES[self.fmt],
+            )
+            HISTORY_EXPORT_ROWS_TOTAL.labels(dataset=dataset).inc(len(rows))
+            summary[dataset] = {"rows": len(rows), "uri": uri}
+        logger.info(f"[HistoryExport] Exported {day}: {summary}")
+        return summary
+
+    def _collect(self, day: date) -> dict[str, list[dict]]:
+        start = datetime.combine(day, time.min, tzinfo=timezone.utc)
+        end = start + timedelta(days=1)
+        return {
+            HISTORY_DATASET: self._history_rows(start, end),
+            CATALOG_DELTAS_DATASET: self._catalog_delta_rows(start, end),
+        }
+
+    def _history_rows(self, start: datetime, end: datetime) -> list[dict]:
+        rows = []
+        for venue_id in sorted(self.rds_store.list_active_venue_ids()):
+            for point in self.venue_dao.get_live_history(venue_id, since=start):
+                if point.timestamp >= end:
+                    break
+                rows.append({
+                    "venue_id": venue_id,
+                    "timestamp": point.timestamp.isoformat(),
+                    "busyness": point.busyness,
+                })
+        return rows
+
+    def _catalog_delta_rows(self, start: datetime, end: datetime) -> list[dict]:
+        rows = []
+        for row in self.rds_store.list_all_venue_rows():
+            for change, column in (("added", "created_at"), ("deprecated", "deprecated_at")):
+                at = _as_utc(row.get(column))
+                if at is None or not (start <= at < end):
+                    continue
+                rows.append({
+                    "venue_id": row.get("venue_id"),
+                    "change": change,
+                    "at": at.isoformat(),
+                    "venue_name": row.get("venue_name"),
+                    "venue_type": row.get("venue_type"),
+                    "venue_lat": _as_float(row.get("venue_lat")),
+                    "venue_lng": _as_float(row.get("venue_lng")),
+                    "deprecated_reason": row.get("deprecated_reason") if change == "deprecated" else None,
+                })
+        rows.sort(key=lambda r: (r["at"], r["venue_id"] or ""))
+        return rows
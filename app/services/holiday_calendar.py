@@ -0,0 +1,162 @@
+"""Regional holiday / special-day calendar.
+
+BestTime marks some forecast days as special (DayInfoV2.special_day) but only
+for the days it knows about, and says nothing about our own schedules. This
+calendar answers "is this Recife date a holiday?" from:
+
+- a built-in regional calendar (settings.holiday_calendar_region): "BR"
+  national holidays, "BR-PE" adds Pernambuco's, "BR-PE-RECIFE" adds Recife's
+  (the default). Movable feasts (Carnaval, Good Friday, Corpus Christi) are
+  derived from Easter, so no yearly upkeep is needed;
+- an optional JSON file (settings.holiday_calendar_file) — a list of
+  {"date": "YYYY-MM-DD", "name": ..., "boost": bool} — for one-off days
+  (festivals, city events); a file entry replaces a built-in one on that date.
+
+When settings.holiday_calendar_enabled is on, nearby responses carry the
+name as `special_day`, and on `boost` days (Carnaval by default) the live
+refresh runs every settings.holiday_live_refresh_minutes unless an admin
+override of the interval is set.
+"""
+from __future__ import annotations
+
+import json
+import logging
+from dataclasses import dataclass
+from datetime import date, timedelta
+from typing import Optional
+
+from app.config import settings
+from app.utils.recife_time import recife_today
+
+logger = logging.getLogger(__name__)
+
+REGIONS = ("BR", "BR-PE", "BR-PE-RECIFE")
+
+
+@dataclass(frozen=True)
+class Holiday:
+    day: date
+    name: str
+    boost: bool = False  # refresh more aggressively on this day
+
+
+def easter_sunday(year: int) -> date:
+    """Gregorian Easter Sunday (anonymous Gregorian algorithm)."""
+    a = year % 19
+    b, c = divmod(year, 100)
+    d, e = divmod(b, 4)
+    f = (b + 8) // 25
+    g = (b - f + 1) // 3
+    h = (19 * a + b - d - g + 15) % 30
+    i, k = divmod(c, 4)
+    l = (32 + 2 * e + 2 * i - h - k) % 7  # noqa: E741
+    m = (a + 11 * h + 22 * l) // 451
+    month, day = divmod(h + l - 7 * m + 114, 31)
+    return date(year, month, day + 1)
+
+
+def builtin_holidays(region: str, year: int) -> list[Holiday]:
+    """The built-in holidays of `region` in `year`.
+
+    Raises:
+        ValueError: unknown region
+    """
+    if region not in REGIONS:
+        raise ValueError(f"unknown holiday region {region!r}; expected one of {REGIONS}")
+    easter = easter_sunday(year)
+    days = [
+        Holiday(date(year, 1, 1), "Confraternização Universal"),
+        # Recife/Olinda Carnaval runs Saturday through Tuesday.
+        *(Holiday(easter - timedelta(days=n), "Carnaval", boost=True) for n in (50, 49, 48, 47)),
+        Holiday(easter - timedelta(days=2), "Sexta-feira Santa"),
+        Holiday(date(year, 4, 21), "Tiradentes"),
+        Holiday(date(year, 5, 1), "Dia do Trabalho"),
+        Holiday(easter + timedelta(days=60), "Corpus Christi"),
+        Holiday(date(year, 9, 7), "Independência do Brasil"),
+        Holiday(date(year, 10, 12), "Nossa Senhora Aparecida"),
+        Holiday(date(year, 11, 2), "Finados"),
+        Holiday(date(year, 11, 15), "Proclamação da República"),
+        Holiday(date(year, 11, 20), "Dia da Consciência Negra"),
+        Holiday(date(year, 12, 25), "Natal"),
+    ]
+    if region in ("BR-PE", "BR-PE-RECIFE"):
+        days += [
+            Holiday(date(year, 3, 6), "Data Magna de Pernambuco"),
+            Holiday(date(year, 6, 24), "São João", boost=True),
+        ]
+    if region == "BR-PE-RECIFE":
+        days += [
+            Holiday(date(year, 7, 16), "Nossa Senhora do Carmo"),
+            Holiday(date(year, 12, 8), "Nossa Senhora da Conceição"),
+        ]
+    return days
+
+
+def load_holiday_file(path: str) -> list[Holiday]:
+    """Parse a holiday file.
+
+    Raises:
+        OSError / ValueError / KeyError: unreadable or malformed file
+    """
+    with open(path, encoding="utf-8") as f:
+        entries = json.load(f)
+    if not isinstance(entries, list):
+        raise ValueError("holiday file must be a JSON list")
+    return [
+        Holiday(date.fromisoformat(e["date"]), str(e["name"]), bool(e.get("boost", False)))
+        for e in entries
+    ]
+
+
+class HolidayCalendar:
+    """Built-in regional holidays plus file entries, looked up by date."""
+
+    def __init__(self, region: str, extra: Optional[list[Holiday]] = None) -> None:
+        self.region = region
+        self._extra = {h.day: h for h in extra or []}
+        self._years: dict[int, dict[date, Holiday]] = {}
+
+    def lookup(self, day: date) -> Optional[Holiday]:
+        if day in self._extra:
+            return self._extra[day]
+        if day.year not in self._years:
+            self._years[day.year] = {h.day: h for h in builtin_holidays(self.region, day.year)}
+        return self._years[day.year].get(day)
+
+
+_calendar: Optional[HolidayCalendar] = None
+
+
+def get_holiday_calendar() -> Optional[HolidayCalendar]:
+    """The calendar built from settings (once), or None when disabled or
+    misconfigured (logged)."""
+    global _calendar
+    if not settings.holiday_calendar_enabled:
+        return None
+    if _calendar is None:
+        try:
+            extra = (
+                load_holiday_file(settings.holiday_calendar_file)
+                if settings.holiday_calendar_file else []
+            )
+            builtin_holidays(settings.holiday_calendar_region, 2000)  # validate region
+            _calendar = HolidayCalendar(settings.holiday_calendar_region, extra)
+        except (OSError, ValueError, KeyError, TypeError) as e:
+            logger.error(f"[HolidayCalendar] Calendar disabled, bad configuration: {e}")
+            return None
+    return _calendar
+
+
+def holiday_on(day: date) -> Optional[Holiday]:
+    """The holiday on `day`, or None (also when the calendar is off)."""
+    calendar = get_holiday_calendar()
+    return calendar.lookup(day) if calendar is not None else None
+
+
+def holiday_live_refresh_minutes(today: Optional[date] = None) -> Optional[int]:
+    """The boosted live refresh interval when today is a boost day, else None."""
+    minutes = settings.holiday_live_refresh_minutes
+    if minutes <= 0:
+        return None
+    holiday = holiday_on(today or recife_today())
+    return minutes if holiday is not None and holiday.boost else None
@@ -54,6 +54,7 @@ class InstagramEnrichmentService:
         enrichment_limit: int = 0,
         cache_ttl_days: int = 30,
         not_found_ttl_days: int = 7,
+        regions=None,
     ):
         self.apify_client = apify_client
         self.venue_dao = venue_dao
@@ -62,6 +63,8 @@ class InstagramEnrichmentService:
         self.enrichment_limit = enrichment_limit  # 0 = unlimited
         self.cache_ttl_days = cache_ttl_days
         self.not_found_ttl_days = not_found_ttl_days
+        # Optional RegionRegistry: venues inside a region search with its city.
+        self.regions = regions
 
     async def discover_instagram_for_venue(
         self, venue_id: str, force_refresh: bool = False
@@ -91,7 +94,7 @@ class InstagramEnrichmentService:
             return None
 
         # Build search query
-        city = self._extract_city(venue.venue_address)
+        city = self._search_city(venue)
         query = SEARCH_QUERY_TEMPLATE.format(
             venue_name=venue.venue_name, city=city
         )
@@ -296,6 +299,14 @@ class InstagramEnrichmentService:
 
         return found_count
 
+    def _search_city(self, venue) -> str:
+        """City for the venue's search query: its region's, else the Recife
+        metro default."""
+        region = self.regions.region_for(venue.venue_lat, venue.venue_lng) if self.regions else None
+        if region is not None:
+            return region.city_for(venue.venue_address)
+        return self._extract_city(venue.venue_address)
+
     @staticmethod
     def _extract_city(address: str) -> str:
         """Extract city name from venue address."""
@@ -6,18 +6,39 @@ each other, and APScheduler's `max_instances=1` only guards a scheduled job
 against its own re-entrancy — neither stops an admin trigger from racing a
 scheduled run of the SAME job (or vice versa), doubling the paid BestTime/
 Google calls for that cycle. This module is the single shared lock namespace
-both call sites check before starting `live_forecast`, `weekly_forecast`,
-`rebuild_redis`, and `google_places`.
-
-In-process only: the scheduler and the FastAPI admin routes share one event
-loop/process in this deployment (see main.py / docker-compose.yml — one
-`cs-server` container, no worker-process fan-out), so a plain module-level set
-is sufficient; no cross-process/Redis lock is needed. `try_acquire`/`release`
-are synchronous with no `await` between a caller's check and acquire, so there
-is no race window within this process.
+both call sites check before starting `venue_catalog`, `live_forecast`,
+`weekly_forecast`, `rebuild_redis`, `google_places`, `event_outbox`,
+`backup` and `venue_dedup`.
+
+The in-process set always applies: `try_acquire`/`release` are synchronous
+with no `await` between a caller's check and acquire, so there is no race
+window within one process. With several cs-server replicas, `use_distributed`
+adds a RedisJobLock on top: a name is only acquired when this replica also
+wins the Redis key (SET NX with a TTL), which is renewed while the run lasts
+and released only by its owner, so a crashed replica's lock expires on its own.
+
+A run that loses its Redis key (another replica took it after a missed
+renewal, or renewals failed for a whole TTL) must not carry on as if it still
+held it: the run's task (`bind_task`) is cancelled, `lost` reports it and
+job_lock_lost_total counts it.
 """
 from __future__ import annotations
 
+import asyncio
+import logging
+import os
+import socket
+import time
+import uuid
+from typing import Optional
+
+from redis.exceptions import WatchError
+
+from app.db.redis_factory import is_cluster
+from app.metrics import JOB_LOCK_LOST_TOTAL
+
+logger = logging.getLogger(__name__)
+
 # The 4 jobs the plan names as requiring the shared guard (paid BestTime/Google
 # calls): the admin_trigger_router.JOB_REGISTRY key strings are canonical — the
 # scheduler side (main.py) passes the SAME strings as `lock_name` to make_job.
@@ -25,9 +46,151 @@ LIVE_FORECAST = "live_forecast"
 WEEKLY_FORECAST = "weekly_forecast"
 GOOGLE_PLACES = "google_places"
 REBUILD_REDIS = "rebuild_redis"
-LOCKED_JOB_NAMES = frozenset({LIVE_FORECAST, WEEKLY_FORECAST, GOOGLE_PLACES, REBUILD_REDIS})
+# Scheduler-only (discovery has no admin trigger); locked so two replicas
+# never spend the monthly new-venue budget twice in one cycle.
+VENUE_CATALOG = "venue_catalog"
+# Two overlapping outbox drains would publish the same batch twice.
+EVENT_OUTBOX = "event_outbox"
+# Every replica would otherwise write the same snapshot.
+BACKUP = "backup"
+# Two overlapping merges would move the same references twice and could each
+# keep a different venue of one cluster.
+VENUE_DEDUP = "venue_dedup"
+LOCKED_JOB_NAMES = frozenset({
+    LIVE_FORECAST, WEEKLY_FORECAST, GOOGLE_PLACES, REBUILD_REDIS, EVENT_OUTBOX, BACKUP,
+    VENUE_DEDUP,
+})
 
 _running: set[str] = set()
+_distributed: "Optional[RedisJobLock]" = None
+_renewals: dict[str, asyncio.Task] = {}
+# job name -> the task running it, cancelled when its Redis lock is lost.
+_tasks: dict[str, asyncio.Task] = {}
+_lost: set[str] = set()
+
+JOB_LOCK_KEY_PREFIX = "job_lock:"
+# Renewals run every ttl/3, but never more often than this.
+MIN_RENEW_INTERVAL_SECONDS = 1.0
+
+# RedisCluster has no WATCH/MULTI: there the owner check and the write run as
+# one script instead.
+_RENEW_SCRIPT = (
+    "if redis.call('get', KEYS[1]) == ARGV[1] then "
+    "return redis.call('pexpire', KEYS[1], ARGV[2]) end "
+    "return 0"
+)
+_RELEASE_SCRIPT = (
+    "if redis.call('get', KEYS[1]) == ARGV[1] then return redis.call('del', KEYS[1]) end "
+    "return 0"
+)
+
+
+def default_owner() -> str:
+    """This replica's lock token: host, pid and a random suffix."""
+    return f"{socket.gethostname()}:{os.getpid()}:{uuid.uuid4().hex[:8]}"
+
+
+class RedisJobLock:
+    """Cross-replica job lock: one Redis key per job name holding the owner's
+    token, with a TTL so a lock never outlives a dead replica for long."""
+
+    def __init__(self, redis_client, owner: Optional[str] = None, ttl_seconds: int = 300):
+        self.redis = redis_client
+        self.owner = owner or default_owner()
+        self.ttl_seconds = max(1, ttl_seconds)
+
+    def _key(self, job_name: str) -> str:
+        return f"{JOB_LOCK_KEY_PREFIX}{job_name}"
+
+    def try_acquire(self, job_name: str) -> bool:
+        return bool(self.redis.set(self._key(job_name), self.owner, nx=True, ex=self.ttl_seconds))
+
+    def _if_owner(self, job_name: str, action) -> bool:
+        """Run `action(pipe, key)` in a transaction only while the key still
+        holds our token (WATCH, so a key that changes hands meanwhile is left
+        alone)."""
+        key = self._key(job_name)
+        with self.redis.pipeline() as pipe:
+            try:
+                pipe.watch(key)
+                owner = pipe.get(key)
+                if isinstance(owner, bytes):
+                    owner = owner.decode()
+                if owner != self.owner:
+                    pipe.unwatch()
+                    return False
+                pipe.multi()
+                action(pipe, key)
+                pipe.execute()
+                return True
+            except WatchError:
+                return False
+
+    def renew(self, job_name: str) -> bool:
+        """Push the TTL out again; False when we no longer own the key."""
+        if is_cluster(self.redis):
+            ttl_ms = self.ttl_seconds * 1000
+            return bool(self.redis.eval(_RENEW_SCRIPT, 1, self._key(job_name), self.owner, ttl_ms))
+        return self._if_owner(job_name, lambda pipe, key: pipe.expire(key, self.ttl_seconds))
+
+    def release(self, job_name: str) -> None:
+        if is_cluster(self.redis):
+            self.redis.eval(_RELEASE_SCRIPT, 1, self._key(job_name), self.owner)
+            return
+        self._if_owner(job_name, lambda pipe, key: pipe.delete(key))
+
+    def holder(self, job_name: str) -> Optional[dict]:
+        """Who holds `job_name` and for how much longer (None when free)."""
+        key = self._key(job_name)
+        owner = self.redis.get(key)
+        if owner is None:
+            return None
+        if isinstance(owner, bytes):
+            owner = owner.decode()
+        return {"owner": owner, "ttl_seconds": self.redis.ttl(key), "ours": owner == self.owner}
+
+
+def use_distributed(lock: "Optional[RedisJobLock]") -> None:
+    """Also take every job lock in Redis (None = in-process only)."""
+    global _distributed
+    _distributed = lock
+
+
+async def _renew_loop(job_name: str, lock: RedisJobLock) -> None:
+    interval = max(MIN_RENEW_INTERVAL_SECONDS, lock.ttl_seconds / 3)
+    renewed_at = time.monotonic()
+    while True:
+        await asyncio.sleep(interval)
+        try:
+            if lock.renew(job_name):
+                renewed_at = time.monotonic()
+                continue
+            logger.warning(f"[JobLock] Lost the Redis lock for '{job_name}'")
+        except Exception as e:
+            logger.warning(f"[JobLock] Renewing the Redis lock for '{job_name}' failed: {e}")
+            if time.monotonic() - renewed_at < lock.ttl_seconds:
+                continue
+            # The key has expired by now; another replica may hold it.
+            logger.warning(f"[JobLock] No renewal of '{job_name}' for a whole TTL; lock lost")
+        _lock_lost(job_name)
+        return
+
+
+def _lock_lost(job_name: str) -> None:
+    _lost.add(job_name)
+    JOB_LOCK_LOST_TOTAL.labels(job_name=job_name).inc()
+    task = _tasks.get(job_name)
+    if task is not None and not task.done():
+        logger.warning(f"[JobLock] Stopping the '{job_name}' run that lost its lock")
+        task.cancel()
+
+
+def _start_renewal(job_name: str, lock: RedisJobLock) -> None:
+    try:
+        loop = asyncio.get_running_loop()
+    except RuntimeError:
+        return  # no loop (sync caller): the TTL alone bounds the lock
+    _renewals[job_name] = loop.create_task(_renew_loop(job_name, lock))
 
 
 def is_running(job_name: str) -> bool:
@@ -45,11 +208,58 @@ def try_acquire(job_name: str) -> bool:
     """
     if job_name in _running:
         return False
+    lock = _distributed
+    if lock is not None:
+        try:
+            if not lock.try_acquire(job_name):
+                return False
+        except Exception as e:
+            # Redis down: run anyway rather than stall every refresh (their
+            # writes need Redis too and will fail loudly on their own).
+            logger.warning(f"[JobLock] Redis lock for '{job_name}' unavailable, running unlocked: {e}")
+        else:
+            _start_renewal(job_name, lock)
     _running.add(job_name)
     return True
 
 
+def bind_task(job_name: str, task: asyncio.Task) -> None:
+    """Register the task running a held `job_name`, to be cancelled if its
+    Redis lock is lost."""
+    if job_name in _running:
+        _tasks[job_name] = task
+
+
+def lost(job_name: str) -> bool:
+    """True while a held `job_name` has lost its Redis lock."""
+    return job_name in _lost
+
+
 def release(job_name: str) -> None:
     """Release `job_name`. Idempotent — releasing a name that is not held is
     a no-op, so a defensive double-release never raises."""
+    if job_name not in _running:
+        return
     _running.discard(job_name)
+    _tasks.pop(job_name, None)
+    _lost.discard(job_name)
+    renewal = _renewals.pop(job_name, None)
+    if renewal is not None:
+        renewal.cancel()
+    if _distributed is not None:
+        try:
+            _distributed.release(job_name)
+        except Exception as e:
+            logger.warning(f"[JobLock] Releasing the Redis lock for '{job_name}' failed: {e}")
+
+
+def holder(job_name: str) -> Optional[dict]:
+    """Lock state of `job_name` for status pages: held here and, with the
+    Redis lock, which replica owns it and its remaining TTL."""
+    state = {"name": job_name, "held_here": job_name in _running, "lost": job_name in _lost}
+    if _distributed is not None:
+        try:
+            state["redis"] = _distributed.holder(job_name)
+        except Exception as e:
+            state["redis_error"] = str(e)
+    return state
@@ -26,6 +26,7 @@ from datetime import datetime, timezone, timedelta
 from typing import Optional
 
 from app.config import settings
+from app.services.holiday_calendar import holiday_live_refresh_minutes
 
 logger = logging.getLogger(__name__)
 
@@ -113,9 +114,10 @@ def _coerce_minutes(raw) -> Optional[int]:
 def resolve_refresh_minutes(admin_config_service=None) -> int:
     """Effective live refresh cadence in minutes: the admin override
     (``admin_config:live_refresh_minutes``) if present and in-bounds, else the
-    settings default. Mirrors refresh_interval_watch so the freshness window is
-    derived from the SAME interval the refresher runs on. Never raises."""
-    default = settings.venues_live_refresh_minutes
+    settings default (the holiday interval on holiday-calendar boost days).
+    Mirrors refresh_interval_watch so the freshness window is derived from the
+    SAME interval the refresher runs on. Never raises."""
+    default = holiday_live_refresh_minutes() or settings.venues_live_refresh_minutes
     if admin_config_service is None:
         return default
     try:
@@ -141,3 +143,23 @@ def resolve_max_age_minutes(admin_config_service=None) -> int:
     interval = resolve_refresh_minutes(admin_config_service)
     window = round(settings.live_freshness_refresh_factor * interval)
     return max(settings.live_freshness_min_minutes, window)
+
+
+def fresh_live_busyness(venue_dao, venue_ids: list[str], now_utc: datetime) -> dict[str, int]:
+    """Live busyness of the venues whose live value is available and fresh,
+    keyed by venue_id. Partner readings win over BestTime, as at serve time.
+    Used by the post-refresh consumers (area index, webhooks, pushes) with the
+    pipeline repository, which reads BestTime values from RDS; partner
+    readings only ever live in Redis."""
+    if not venue_ids:
+        return {}
+    live = venue_dao.get_live_forecasts_bulk(venue_ids)
+    if settings.partner_venues:
+        live = {**live, **venue_dao.get_partner_live_bulk(venue_ids)}
+    max_age = timedelta(minutes=resolve_max_age_minutes())
+    return {
+        vid: lf.analysis.venue_live_busyness
+        for vid, lf in live.items()
+        if lf.analysis.venue_live_busyness_available
+        and classify_live_freshness(lf, now_utc, max_age)[0] == FRESH
+    }
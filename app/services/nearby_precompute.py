@@ -0,0 +1,133 @@
+"""Precomputed /v1/venues/nearby responses for the hottest queries.
+
+Most nearby traffic asks about the same few places: the discovery locations
+the app opens on, at its preset radii. Rendering those means a geo search, a
+bulk read per forecast family, the merge and the transform on every request.
+NearbyPrecomputeService renders them once after each Redis projection (when
+the serving data changes) and stores the final JSON under one key each, so the
+route answers an exact match with a single GET.
+
+A query matches when its point rounds to a precomputed location (4 decimals,
+about 11 m), its radius converts to one of the configured radii in km, and it
+asks for today's forecast. Entries expire after `ttl_seconds`, so a stopped
+projector falls back to the live path rather than serving old data; the
+freshness flags inside an entry are as of its rendering.
+"""
+from __future__ import annotations
+
+import json
+import logging
+from typing import Iterable, Optional
+
+from fastapi.encoders import jsonable_encoder
+
+from app.db.geo_redis_client import radius_to_km
+from app.handlers.venue_handler import nearby_response_exclude
+from app.metrics import NEARBY_PRECOMPUTED_TOTAL
+from app.tenancy import tenant_key
+
+logger = logging.getLogger(__name__)
+
+KEY_PREFIX = "nearby:precomputed"
+
+
+def precompute_key(lat: float, lon: float, radius_km: float, verbose: bool) -> str:
+    # Per tenant, like the serving data the entries are rendered from.
+    return tenant_key(
+        f"{KEY_PREFIX}:{lat:.4f}:{lon:.4f}:{radius_km:g}:{'verbose' if verbose else 'min'}"
+    )
+
+
+def _point(location) -> tuple[float, float]:
+    """(lat, lng) of a discovery location or admin discovery point dict."""
+    if isinstance(location, dict):
+        return float(location["lat"]), float(location["lng"])
+    return location.lat, location.lng
+
+
+class NearbyPrecomputeService:
+    """Renders and serves nearby responses for the discovery locations."""
+
+    def __init__(
+        self,
+        handler,
+        redis_client,
+        locations_source,
+        radii_km: Iterable[float] = (1, 3, 5),
+        ttl_seconds: int = 900,
+        verbose_variants: bool = False,
+    ):
+        """
+        Args:
+            handler: the serving VenueHandler
+            redis_client: raw Redis client the entries are stored in
+            locations_source: callable returning the current discovery
+                locations (VenuesRefresherService.active_locations()[1])
+            radii_km: radii precomputed at every location
+            ttl_seconds: entry lifetime; keep above the projection interval
+            verbose_variants: also precompute the verbose=true responses
+        """
+        self.handler = handler
+        self.redis = redis_client
+        self.locations_source = locations_source
+        self.radii_km = [float(r) for r in radii_km]
+        self.ttl_seconds = ttl_seconds
+        self.verbose_variants = verbose_variants
+
+    def refresh(self) -> int:
+        """Re-render every precomputed response; returns how many were stored.
+
+        Blocking (Redis reads and writes); the projection job runs it in a
+        thread. A location that fails is logged and skipped.
+        """
+        exclude = nearby_response_exclude()
+        stored = 0
+        pipe = self.redis.pipeline()
+        for location in self.locations_source():
+            lat, lon = _point(location)
+            for radius_km in self.radii_km:
+                for verbose in ((False, True) if self.verbose_variants else (False,)):
+                    try:
+                        result = self.handler.get_venues_nearby(lat, lon, radius_km, verbose)
+                    except Exception as e:
+                        logger.warning(
+                            f"[NearbyPrecompute] Failed at ({lat:.4f}, {lon:.4f}) "
+                            f"r={radius_km:g}km: {e}"
+                        )
+                        continue
+                    body = json.dumps(
+                        [jsonable_encoder(item, exclude=exclude) for item in result],
+                        separators=(",", ":"),
+                    )
+                    pipe.set(precompute_key(lat, lon, radius_km, verbose), body, ex=self.ttl_seconds)
+                    stored += 1
+        pipe.execute()
+        logger.info(f"[NearbyPrecompute] Stored {stored} precomputed nearby responses")
+        return stored
+
+    def lookup(
+        self,
+        lat: float,
+        lon: float,
+        radius: float,
+        unit: str = "km",
+        verbose: bool = False,
+        target_day_offset: Optional[int] = None,
+    ) -> Optional[str]:
+        """The precomputed JSON body for this query, or None (not a
+        precomputed query, expired, or Redis unavailable)."""
+        if target_day_offset:
+            return None
+        try:
+            radius_km = round(radius_to_km(radius, unit), 6)
+        except ValueError:
+            return None
+        if radius_km not in self.radii_km:
+            return None
+        try:
+            body = self.redis.get(precompute_key(lat, lon, radius_km, verbose))
+        except Exception as e:
+            logger.warning(f"[NearbyPrecompute] Lookup failed: {e}")
+            return None
+        NEARBY_PRECOMPUTED_TOTAL.labels(result="hit" if body is not None else "miss").inc()
+        return body
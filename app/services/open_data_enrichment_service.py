@@ -0,0 +1,238 @@
+"""Enriches venues with OpenStreetMap / Foursquare directory data.
+
+For each servable venue without directory data, every configured source is
+asked for the places around the venue's coordinates, and the candidate whose
+name best matches the venue within `match_radius_m` is taken (name similarity
+>= `min_name_similarity` on accent- and punctuation-folded names). The first
+source that matches supplies the primary record; later matching sources fill
+the fields it lacks and add their categories and photos. The result is stored on the
+venue document (Venue.open_data), so the verbose nearby/detail responses carry
+it, and the discovery refresh keeps it across re-upserts.
+
+A venue no source matched is remembered for `miss_ttl_days`
+(`open_data:miss:<venue_id>`) so it is not searched again on every run.
+"""
+from __future__ import annotations
+
+import asyncio
+import logging
+import re
+import unicodedata
+from datetime import datetime, timezone
+from difflib import SequenceMatcher
+from typing import Optional, Protocol, Sequence
+
+from app.metrics import OPEN_DATA_ENRICHMENT_RESULTS
+from app.models import OpenDataCandidate, OpenDataEnrichment, Venue
+from app.services.venue_eligibility import haversine_km
+
+logger = logging.getLogger(__name__)
+
+OPEN_DATA_MISS_KEY_PREFIX = "open_data:miss:"
+
+# Pause between venues so a full run stays polite to the public Overpass servers.
+REQUEST_DELAY = 0.5
+
+
+class OpenDataSource(Protocol):
+    """A place directory searchable around a point (OverpassClient, FoursquareClient)."""
+
+    name: str
+
+    async def search_around(
+        self, lat: float, lng: float, radius_m: float, name: str
+    ) -> list[OpenDataCandidate]: ...
+
+
+def normalize_name(name: str) -> str:
+    """Lower-case, accent-free, punctuation-free, single-spaced."""
+    folded = unicodedata.normalize("NFKD", name or "")
+    folded = "".join(ch for ch in folded if not unicodedata.combining(ch)).casefold()
+    return " ".join(re.sub(r"[^\w\s]", " ", folded).split())
+
+
+def name_similarity(a: str, b: str) -> float:
+    """0..1 similarity of two venue names, ignoring word order; 1.0 when one
+    contains the other (e.g. "Bar do Geraldo" vs "Geraldo")."""
+    na, nb = normalize_name(a), normalize_name(b)
+    if not na or not nb:
+        return 0.0
+    if na in nb or nb in na:
+        return 1.0
+    sa, sb = " ".join(sorted(na.split())), " ".join(sorted(nb.split()))
+    return max(SequenceMatcher(None, na, nb).ratio(), SequenceMatcher(None, sa, sb).ratio())
+
+
+def best_match(
+    venue: Venue,
+    candidates: Sequence[OpenDataCandidate],
+    max_distance_m: float,
+    min_similarity: float,
+) -> Optional[tuple[OpenDataCandidate, float, float]]:
+    """The (candidate, distance_m, score) best matching `venue`, or None.
+
+    Among candidates within `max_distance_m` and at least `min_similarity`,
+    the highest name similarity wins, the nearest on ties.
+    """
+    best = None
+    for candidate in candidates:
+        distance_m = haversine_km(venue.venue_lat, venue.venue_lng, candidate.lat, candidate.lng) * 1000
+        if distance_m > max_distance_m:
+            continue
+        score = name_similarity(venue.venue_name, candidate.name)
+        if score < min_similarity:
+            continue
+        if best is None or (score, -distance_m) > (best[2], -best[1]):
+            best = (candidate, distance_m, score)
+    return best
+
+
+class OpenDataEnrichmentService:
+    """Matches venues to OSM / Foursquare places and stores the directory data."""
+
+    def __init__(
+        self,
+        sources: Sequence[OpenDataSource],
+        venue_dao,
+        redis_client=None,
+        match_radius_m: float = 150.0,
+        min_name_similarity: float = 0.75,
+        enrichment_limit: int = 0,
+        miss_ttl_days: int = 14,
+    ):
+        """
+        Args:
+            sources: directories in priority order
+            venue_dao: pipeline venue DAO (get_venue / upsert_venue)
+            redis_client: raw Redis client for the no-match memory (None = none)
+            enrichment_limit: max venues searched per run (0 = unlimited)
+        """
+        self.sources = list(sources)
+        self.venue_dao = venue_dao
+        self.redis_client = redis_client
+        self.match_radius_m = match_radius_m
+        self.min_name_similarity = min_name_similarity
+        self.enrichment_limit = enrichment_limit
+        self.miss_ttl_days = miss_ttl_days
+
+    async def match_venue(self, venue: Venue) -> Optional[OpenDataEnrichment]:
+        """Search every source for `venue` and merge the matches (None = no match).
+
+        Raises:
+            Exception: every source failed (a partial failure is logged and the
+                other sources still count)
+        """
+        primary: Optional[OpenDataEnrichment] = None
+        failures = 0
+        for source in self.sources:
+            try:
+                candidates = await source.search_around(
+                    venue.venue_lat, venue.venue_lng, self.match_radius_m, venue.venue_name
+                )
+            except Exception as e:
+                failures += 1
+                logger.warning(f"[OpenDataEnrichment] {source.name} failed for {venue.venue_id}: {e}")
+                if failures == len(self.sources):
+                    raise
+                continue
+            match = best_match(venue, candidates, self.match_radius_m, self.min_name_similarity)
+            if match is None:
+                continue
+            candidate, distance_m, score = match
+            if primary is None:
+                primary = OpenDataEnrichment(
+                    source=candidate.source,
+                    source_id=candidate.source_id,
+                    matched_name=candidate.name,
+                    match_distance_m=round(distance_m, 1),
+                    match_score=round(score, 3),
+                    sources=[candidate.source],
+                    categories=list(candidate.categories),
+                    website=candidate.website,
+                    phone=candidate.phone,
+                    opening_hours=candidate.opening_hours,
+                    photos=list(candidate.photos),
+                    enriched_at=datetime.now(timezone.utc),
+                )
+                continue
+            primary.sources.append(candidate.source)
+            primary.categories += [c for c in candidate.categories if c not in primary.categories]
+            primary.website = primary.website or candidate.website
+            primary.phone = primary.phone or candidate.phone
+            primary.opening_hours = primary.opening_hours or candidate.opening_hours
+            primary.photos += [p for p in candidate.photos if p not in primary.photos]
+        return primary
+
+    def _recently_missed(self, venue_id: str) -> bool:
+        if self.redis_client is None:
+            return False
+        try:
+            return bool(self.redis_client.exists(f"{OPEN_DATA_MISS_KEY_PREFIX}{venue_id}"))
+        except Exception as e:
+            logger.warning(f"[OpenDataEnrichment] Miss lookup failed for {venue_id}: {e}")
+            return False
+
+    def _remember_miss(self, venue_id: str) -> None:
+        if self.redis_client is None or self.miss_ttl_days <= 0:
+            return
+        try:
+            self.redis_client.set(
+                f"{OPEN_DATA_MISS_KEY_PREFIX}{venue_id}", "1", ex=self.miss_ttl_days * 86400
+            )
+        except Exception as e:
+            logger.warning(f"[OpenDataEnrichment] Failed to remember miss for {venue_id}: {e}")
+
+    async def enrich_all_venues(self, force_refresh: bool = False) -> dict:
+        """Enrich every servable venue that has no directory data yet.
+
+        Args:
+            force_refresh: also re-match enriched and recently missed venues
+
+        Returns:
+            Counts of matched / no_match / error / skipped venues
+        """
+        summary = {"matched": 0, "no_match": 0, "error": 0, "skipped": 0}
+        venue_ids = self.venue_dao.list_servable_venue_ids()
+        logger.info(
+            f"[OpenDataEnrichment] Starting for {len(venue_ids)} venues "
+            f"(sources={[s.name for s in self.sources]}, force_refresh={force_refresh})"
+        )
+        searched = 0
+        for venue_id in venue_ids:
+            venue = self.venue_dao.get_venue(venue_id)
+            if venue is None or (
+                not force_refresh and (venue.open_data is not None or self._recently_missed(venue_id))
+            ):
+                summary["skipped"] += 1
+                OPEN_DATA_ENRICHMENT_RESULTS.labels(result="skipped").inc()
+                continue
+            if self.enrichment_limit > 0 and searched >= self.enrichment_limit:
+                logger.info(
+                    f"[OpenDataEnrichment] Reached enrichment limit ({self.enrichment_limit})"
+                )
+                break
+            searched += 1
+            try:
+                enrichment = await self.match_venue(venue)
+            except Exception:
+                summary["error"] += 1
+                OPEN_DATA_ENRICHMENT_RESULTS.labels(result="error").inc()
+                continue
+            if enrichment is None:
+                summary["no_match"] += 1
+                OPEN_DATA_ENRICHMENT_RESULTS.labels(result="no_match").inc()
+                self._remember_miss(venue_id)
+            else:
+                venue.open_data = enrichment
+                self.venue_dao.upsert_venue(venue)
+                summary["matched"] += 1
+                OPEN_DATA_ENRICHMENT_RESULTS.labels(result="matched").inc()
+                logger.info(
+                    f"[OpenDataEnrichment] {venue_id} matched {enrichment.source} "
+                    f"{enrichment.matched_name!r} ({enrichment.match_distance_m}m, "
+                    f"score {enrichment.match_score})"
+                )
+            await asyncio.sleep(REQUEST_DELAY)
+
+        logger.info(f"[OpenDataEnrichment] Done: {summary}")
+        return summary
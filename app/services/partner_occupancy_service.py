@@ -0,0 +1,230 @@
+"""Partner-supplied occupancy counts (door counters, WiFi analytics).
+
+Partner venues push raw occupancy through POST
+/v1/partners/venues/{venue_id}/occupancy, authenticated with a per-partner key
+(settings.partner_api_keys) and limited to the venues assigned to that partner
+(settings.partner_venues). Each reading is converted to a live busyness
+percentage (occupancy / capacity), stored as the venue's partner reading for
+partner_reading_max_age_minutes, and recorded in the live history series.
+
+While a reading is unexpired it is preferred over BestTime: the serving path
+overlays it on the cached live forecast (flagged `live_source="partner"`), and
+`PartnerCrowdProvider` sits ahead of BestTime in the CrowdProviderRegistry so
+the live refresh neither overwrites it nor spends a BestTime read on the venue.
+
+A configured venue the dedup merged into another reports for the kept venue:
+readings are stored under, and the provider covers, the id the alias hash
+resolves it to (PartnerVenueIds).
+"""
+from __future__ import annotations
+
+import hmac
+import logging
+import time
+from datetime import datetime, timedelta, timezone
+from typing import Callable, Iterable, Optional
+
+from app.metrics import PARTNER_OCCUPANCY_READINGS_TOTAL
+from app.models import Analysis, LiveForecastResponse, VenueInfo, WeekRawResponse
+from app.services.busyness_validation import BusynessValidator
+
+logger = logging.getLogger(__name__)
+
+PARTNER_SOURCE = "partner"
+# Readings stamped further ahead of the server clock than this are rejected
+# (a partner clock running fast would otherwise pin "live" data in the future).
+MAX_CLOCK_SKEW = timedelta(minutes=5)
+# How long resolved dedup aliases are reused; a merge made by another process
+# is picked up within this long.
+ALIAS_REFRESH_SECONDS = 60
+
+
+class PartnerAuthError(Exception):
+    """Missing or unknown partner API key."""
+
+
+class PartnerForbiddenError(Exception):
+    """The partner is not assigned to the venue."""
+
+
+class PartnerReadingError(ValueError):
+    """The reading itself is unusable (bad counts or timestamp)."""
+
+
+def occupancy_to_busyness(occupancy: int, capacity: int) -> int:
+    """Occupancy as a 0..100 percentage of capacity (over-capacity caps at 100)."""
+    if capacity <= 0:
+        raise PartnerReadingError("capacity must be positive")
+    if occupancy < 0:
+        raise PartnerReadingError("occupancy must not be negative")
+    return min(100, round(100 * occupancy / capacity))
+
+
+class PartnerVenueIds:
+    """The configured partner venue ids, each also standing for the venue the
+    dedup merged it into (`alias_of`, e.g. VenueDeduplicator.alias_of).
+
+    Supports `in` (the RegionalProvider coverage check) for both the
+    configured and the kept ids. Lookup errors keep the last resolution.
+    """
+
+    def __init__(
+        self,
+        venue_ids: Iterable[str],
+        alias_of: Optional[Callable[[str], Optional[str]]] = None,
+        clock=time.monotonic,
+    ) -> None:
+        self.configured = frozenset(venue_ids)
+        self.alias_of = alias_of
+        self._clock = clock
+        self._aliases: dict[str, str] = {}
+        self._resolved_at: Optional[float] = None
+
+    def set_alias_resolver(self, alias_of: Callable[[str], Optional[str]]) -> None:
+        self.alias_of = alias_of
+        self._resolved_at = None
+
+    def _resolved(self) -> dict[str, str]:
+        if self.alias_of is None:
+            return {}
+        now = self._clock()
+        if self._resolved_at is None or now - self._resolved_at >= ALIAS_REFRESH_SECONDS:
+            try:
+                aliases = {vid: self.alias_of(vid) for vid in self.configured}
+                self._aliases = {vid: kept for vid, kept in aliases.items() if kept}
+            except Exception as e:
+                logger.warning(f"[PartnerOccupancyService] Alias lookup failed: {e}")
+            self._resolved_at = now
+        return self._aliases
+
+    def canonical(self, venue_id: str) -> str:
+        """The id `venue_id`'s readings are stored under."""
+        return self._resolved().get(venue_id, venue_id)
+
+    def __contains__(self, venue_id: object) -> bool:
+        return venue_id in self.configured or venue_id in self._resolved().values()
+
+    def __iter__(self):
+        return iter(self.configured | set(self._resolved().values()))
+
+
+class PartnerOccupancyService:
+    """Authenticates partners and stores their occupancy readings."""
+
+    def __init__(
+        self,
+        venue_dao,
+        api_keys: dict[str, str],
+        partner_venues: dict[str, list[str]],
+        max_age_minutes: int = 30,
+        now_fn=None,
+        validator: Optional[BusynessValidator] = None,
+    ) -> None:
+        self.venue_dao = venue_dao
+        # Shared with the refresher so partner and BestTime samples feed one
+        # flip-flop window per venue.
+        self.validator = validator or BusynessValidator()
+        self.api_keys = dict(api_keys)
+        self.partner_venues = {p: set(ids) for p, ids in partner_venues.items()}
+        self.served_venue_ids = PartnerVenueIds(
+            vid for ids in self.partner_venues.values() for vid in ids
+        )
+        self.max_age = timedelta(minutes=max_age_minutes)
+        self._now = now_fn or (lambda: datetime.now(timezone.utc))
+
+    def authenticate(self, api_key: Optional[str]) -> str:
+        """The partner id for `api_key`; raises PartnerAuthError otherwise."""
+        if api_key:
+            for known_key, partner in self.api_keys.items():
+                if hmac.compare_digest(known_key.encode(), api_key.encode()):
+                    return partner
+        raise PartnerAuthError("invalid partner key")
+
+    def venue_ids(self) -> PartnerVenueIds:
+        """Every venue some partner reports for (and the venues they were
+        merged into)."""
+        return self.served_venue_ids
+
+    def set_alias_resolver(self, alias_of: Callable[[str], Optional[str]]) -> None:
+        """Store readings under the venue the dedup merged a venue into."""
+        self.served_venue_ids.set_alias_resolver(alias_of)
+
+    def ingest(
+        self,
+        partner: str,
+        venue_id: str,
+        occupancy: int,
+        capacity: int,
+        observed_at: Optional[datetime] = None,
+    ) -> LiveForecastResponse:
+        """Validate and store one reading; returns it in the live forecast shape."""
+        if venue_id not in self.partner_venues.get(partner, ()):
+            PARTNER_OCCUPANCY_READINGS_TOTAL.labels(result="forbidden").inc()
+            raise PartnerForbiddenError(f"partner {partner!r} does not report for {venue_id}")
+
+        now = self._now()
+        if observed_at is None:
+            observed_at = now
+        elif observed_at.tzinfo is None:
+            observed_at = observed_at.replace(tzinfo=timezone.utc)
+        age = now - observed_at
+        try:
+            if age < -MAX_CLOCK_SKEW:
+                raise PartnerReadingError("observed_at is in the future")
+            if age > self.max_age:
+                raise PartnerReadingError(
+                    f"observed_at is older than {int(self.max_age.total_seconds() // 60)} minutes"
+                )
+            busyness = occupancy_to_busyness(occupancy, capacity)
+        except PartnerReadingError:
+            PARTNER_OCCUPANCY_READINGS_TOTAL.labels(result="rejected").inc()
+            raise
+
+        reading = LiveForecastResponse(
+            status="OK",
+            analysis=Analysis(
+                venue_live_busyness=busyness,
+                venue_live_busyness_available=True,
+            ),
+            venue_info=VenueInfo(
+                venue_id=self.served_venue_ids.canonical(venue_id),
+                venue_current_gmttime=observed_at.astimezone(timezone.utc).isoformat(),
+            ),
+        )
+        # Always in range (occupancy_to_busyness caps it); this counts the
+        # value and feeds flip-flop detection.
+        reading = self.validator.validate_live(reading, PARTNER_SOURCE)
+        # Expire when the reading stops being fresh enough to beat BestTime.
+        ttl_seconds = max(1, int((self.max_age - max(age, timedelta(0))).total_seconds()))
+        self.venue_dao.set_partner_live(reading, ttl_seconds)
+        PARTNER_OCCUPANCY_READINGS_TOTAL.labels(result="stored").inc()
+        logger.info(
+            f"[PartnerOccupancyService] {partner} reported {occupancy}/{capacity} "
+            f"({busyness}%) for {venue_id}"
+        )
+        return reading
+
+
+class PartnerCrowdProvider:
+    """CrowdDataProvider over the stored partner readings (live only).
+
+    Wrap it in a RegionalProvider restricted to the partner venue ids so the
+    registry does not probe Redis for every other venue."""
+
+    name = PARTNER_SOURCE
+    # Readings live under their own key with their own TTL and are overlaid at
+    # serve time; the refresher must not cache them as BestTime live data.
+    stores_live = True
+
+    def __init__(self, venue_dao) -> None:
+        self.venue_dao = venue_dao
+
+    def covers(self, venue_id: str, location) -> bool:
+        return True
+
+    async def get_live_forecast(self, venue_id: str) -> Optional[LiveForecastResponse]:
+        return self.venue_dao.get_partner_live(venue_id)
+
+    async def get_week_raw_forecast(self, venue_id: str) -> Optional[WeekRawResponse]:
+        # Partners push current counts only; weekly curves stay with BestTime.
+        return None
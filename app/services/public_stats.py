@@ -0,0 +1,115 @@
+"""Public, coarse aggregates for a status / transparency page.
+
+GET /v1/stats/public serves this: how many venues are tracked per geo-fence
+city and how fresh the live data is overall. It is deliberately separate from
+the admin and /debug stats (no ids, no inventory detail, no pipeline
+internals) and cheap to serve: the aggregate is computed from the serving
+Redis projection at most once per `ttl_seconds` per replica, and the route
+marks the response cacheable for the same time so CDNs can absorb the load.
+
+Counts are coarse: below 10 they are exact, above they are rounded to the
+nearest 10, so the page conveys scale without publishing the exact inventory.
+"""
+from __future__ import annotations
+
+import logging
+import statistics
+import threading
+import time
+from datetime import datetime, timezone
+from typing import Callable, Optional
+
+from app.services.venue_eligibility import haversine_km, load_geo_fence
+
+logger = logging.getLogger(__name__)
+
+OTHER_CITY = {"slug": "other", "name": "Other"}
+
+
+def coarse_count(n: int) -> int:
+    """Exact below 10, else rounded to the nearest 10."""
+    return n if n < 10 else int(round(n, -1))
+
+
+def _city_of(venue, cities: list[dict]) -> dict:
+    """The nearest fence city whose circle holds the venue, else OTHER_CITY."""
+    best, best_km = OTHER_CITY, None
+    for city in cities:
+        km = haversine_km(venue.venue_lat, venue.venue_lng, city["lat"], city["lng"])
+        if km <= city["radius_km"] and (best_km is None or km < best_km):
+            best, best_km = city, km
+    return best
+
+
+class PublicStatsService:
+    """Computes and caches the public stats document."""
+
+    def __init__(
+        self,
+        venue_dao,
+        redis_client=None,
+        ttl_seconds: int = 300,
+        clock: Callable[[], datetime] = lambda: datetime.now(timezone.utc),
+        monotonic: Callable[[], float] = time.monotonic,
+    ) -> None:
+        """
+        Args:
+            venue_dao: the serving DAO (the Redis projection nearby reads)
+            redis_client: raw Redis client for the geo-fence mirror (None = the
+                default fence)
+            ttl_seconds: how long a computed document is reused
+        """
+        self.venue_dao = venue_dao
+        self.redis_client = redis_client
+        self.ttl_seconds = ttl_seconds
+        self._clock = clock
+        self._monotonic = monotonic
+        self._lock = threading.Lock()
+        self._cached: Optional[dict] = None
+        self._cached_at = 0.0
+
+    def get_stats(self) -> dict:
+        """The public stats document, recomputed at most once per TTL."""
+        with self._lock:
+            now = self._monotonic()
+            if self._cached is None or now - self._cached_at >= self.ttl_seconds:
+                self._cached = self._compute()
+                self._cached_at = now
+            return self._cached
+
+    def _compute(self) -> dict:
+        now = self._clock()
+        venues = [v for v in self.venue_dao.list_all_venues() if v.is_active()]
+        cities = load_geo_fence(self.redis_client).get("cities", [])
+
+        per_city: dict[str, dict] = {}
+        for venue in venues:
+            city = _city_of(venue, cities)
+            entry = per_city.setdefault(
+                city["slug"], {"slug": city["slug"], "name": city["name"], "venues": 0}
+            )
+            entry["venues"] += 1
+
+        live = self.venue_dao.get_live_forecasts_bulk([v.venue_id for v in venues]) if venues else {}
+        ages = [
+            (now - lf.refreshed_at).total_seconds() / 60
+            for lf in live.values()
+            if lf.refreshed_at is not None
+        ]
+
+        logger.info(
+            f"[PublicStats] Computed: {len(venues)} venues, {len(per_city)} cities, "
+            f"{len(live)} with live data"
+        )
+        return {
+            "generated_at": now.replace(microsecond=0).isoformat(),
+            "venues_tracked": coarse_count(len(venues)),
+            "cities": [
+                {**entry, "venues": coarse_count(entry["venues"])}
+                for entry in sorted(per_city.values(), key=lambda e: -e["venues"])
+            ],
+            "live_data": {
+                "venues_with_live_data": coarse_count(len(live)),
+                "median_age_minutes": round(statistics.median(ages)) if ages else None,
+            },
+        }
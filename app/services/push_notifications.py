@@ -0,0 +1,160 @@
+"""Push notifications when a favorite venue gets busy.
+
+A user subscribes a device (POST /v1/push/subscriptions, called by vibes_bot
+like the other engagement writes) with an optional threshold. After each live
+refresh PushNotifier.evaluate() looks at every subscriber's favorites
+(`user_favorites:{user_id}`, the engagement projection) and sends an FCM push
+to each of their devices for every favorite that newly reached the threshold.
+Like the webhooks it is edge-triggered: `push:above:{user_id}` holds the
+favorites already announced until they drop below again.
+
+Layout:
+- `push:subscriptions:v1`: hash user_id -> PushSubscription JSON;
+- `push:above:{user_id}`: set of venue ids at or over the threshold last time.
+
+A token FCM reports as unregistered is removed from its subscription; other
+send failures are counted and logged, and the venue is announced again at the
+next crossing (no retry: a late "it's busy now" push is worse than none).
+"""
+from __future__ import annotations
+
+import logging
+from datetime import datetime, timezone
+from typing import Optional
+
+from pydantic import BaseModel, Field
+
+from app.api.fcm_client import FcmUnregisteredError
+from app.metrics import PUSH_NOTIFICATIONS_TOTAL
+from app.services.live_freshness import fresh_live_busyness
+
+logger = logging.getLogger(__name__)
+
+SUBSCRIPTIONS_KEY = "push:subscriptions:v1"
+ABOVE_KEY_FORMAT = "push:above:{}"
+# Same key the engagement service projects favorites into.
+FAVORITES_KEY_FORMAT = "user_favorites:{}"
+MAX_DEVICES_PER_USER = 10
+
+
+class PushSubscription(BaseModel):
+    user_id: str
+    devices: dict[str, str] = Field(default_factory=dict)  # FCM token -> platform
+    threshold: Optional[int] = None  # None = the configured default
+
+
+class PushNotifier:
+    """Subscriptions and favorite-venue busyness pushes."""
+
+    def __init__(self, venue_dao, redis_client, sender, default_threshold: int = 80):
+        """
+        Args:
+            venue_dao: venue and live-busyness reads (the pipeline repository)
+            redis_client: raw Redis client (decode_responses=True)
+            sender: FcmClient (anything with `async send(token, title, body, data)`)
+            default_threshold: busyness for subscriptions without their own
+        """
+        self.venue_dao = venue_dao
+        self.redis = redis_client
+        self.sender = sender
+        self.default_threshold = default_threshold
+
+    # ── subscriptions ───────────────────────────────────────────────────────
+    def get(self, user_id: str) -> Optional[PushSubscription]:
+        raw = self.redis.hget(SUBSCRIPTIONS_KEY, user_id)
+        return PushSubscription.model_validate_json(raw) if raw is not None else None
+
+    def _save(self, sub: PushSubscription) -> None:
+        if sub.devices:
+            self.redis.hset(SUBSCRIPTIONS_KEY, sub.user_id, sub.model_dump_json())
+        else:
+            self.redis.hdel(SUBSCRIPTIONS_KEY, sub.user_id)
+            self.redis.delete(ABOVE_KEY_FORMAT.format(sub.user_id))
+
+    def subscribe(
+        self, user_id: str, device_token: str, platform: str, threshold: Optional[int] = None
+    ) -> PushSubscription:
+        """Add (or refresh) a device; `threshold` replaces the user's when set.
+        Past MAX_DEVICES_PER_USER the oldest device is dropped."""
+        sub = self.get(user_id) or PushSubscription(user_id=user_id)
+        sub.devices.pop(device_token, None)
+        sub.devices[device_token] = platform
+        while len(sub.devices) > MAX_DEVICES_PER_USER:
+            sub.devices.pop(next(iter(sub.devices)))
+        if threshold is not None:
+            sub.threshold = threshold
+        self._save(sub)
+        return sub
+
+    def unsubscribe(self, user_id: str, device_token: Optional[str] = None) -> None:
+        """Remove one device, or every device of the user."""
+        sub = self.get(user_id)
+        if sub is None:
+            return
+        if device_token is None:
+            sub.devices.clear()
+        else:
+            sub.devices.pop(device_token, None)
+        self._save(sub)
+
+    # ── evaluation ──────────────────────────────────────────────────────────
+    async def evaluate(self, now: Optional[datetime] = None) -> dict:
+        """Push every favorite that newly reached its subscriber's threshold.
+        Returns counts: subscribers, events, sent, failed, unregistered."""
+        now = now or datetime.now(timezone.utc)
+        summary = {"subscribers": 0, "events": 0, "sent": 0, "failed": 0, "unregistered": 0}
+        subs = [
+            PushSubscription.model_validate_json(raw)
+            for raw in self.redis.hvals(SUBSCRIPTIONS_KEY)
+        ]
+        summary["subscribers"] = len(subs)
+        if not subs:
+            return summary
+        favorites = {
+            sub.user_id: set(self.redis.smembers(FAVORITES_KEY_FORMAT.format(sub.user_id)))
+            for sub in subs
+        }
+        busyness = fresh_live_busyness(
+            self.venue_dao, sorted(set().union(*favorites.values())), now
+        )
+        names: dict[str, str] = {}
+        for sub in subs:
+            threshold = sub.threshold if sub.threshold is not None else self.default_threshold
+            above = {vid for vid in favorites[sub.user_id] if busyness.get(vid, -1) >= threshold}
+            key = ABOVE_KEY_FORMAT.format(sub.user_id)
+            announced = set(self.redis.smembers(key))
+            for vid in sorted(above - announced):
+                if vid not in names:
+                    venue = self.venue_dao.get_venue(vid)
+                    names[vid] = venue.venue_name if venue is not None else vid
+                summary["events"] += 1
+                await self._push(sub, vid, names[vid], busyness[vid], summary)
+            pipe = self.redis.pipeline()
+            pipe.delete(key)
+            if above:
+                pipe.sadd(key, *above)
+            pipe.execute()
+        if summary["events"]:
+            logger.info(f"[PushNotifier] Evaluation: {summary}")
+        return summary
+
+    async def _push(self, sub: PushSubscription, venue_id: str, venue_name: str,
+                    busyness: int, summary: dict) -> None:
+        data = {"event": "favorite_busy", "venue_id": venue_id, "venue_busyness": str(busyness)}
+        body = f"{busyness}% de movimento agora"
+        for token in list(sub.devices):
+            try:
+                await self.sender.send(token, venue_name, body, data)
+            except FcmUnregisteredError:
+                # Re-read so a concurrent subscribe is not overwritten.
+                self.unsubscribe(sub.user_id, token)
+                summary["unregistered"] += 1
+                PUSH_NOTIFICATIONS_TOTAL.labels(result="unregistered").inc()
+            except Exception as e:
+                summary["failed"] += 1
+                PUSH_NOTIFICATIONS_TOTAL.labels(result="failed").inc()
+                # Never log the raw user_id.
+                logger.warning(f"[PushNotifier] Push for {venue_id} failed: {e}")
+            else:
+                summary["sent"] += 1
+                PUSH_NOTIFICATIONS_TOTAL.labels(result="sent").inc()
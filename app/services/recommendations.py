@@ -0,0 +1,190 @@
+"""Per-user venue recommendations (GET /v1/users/{user_id}/recommendations).
+
+A user's history is their favorites (`user_favorites:{user_id}`, the
+engagement projection) and their visits, recorded through POST /v1/visits
+into `user_visits:v1:{user_id}` (sorted set venue_id -> last visit epoch
+seconds; at most settings.recommendation_max_visits, expiring
+recommendation_history_days after the latest visit).
+
+Recommending: the most recent recommendation_max_seeds history venues are the
+seeds. Candidates are the servable venues within recommendation_radius_km of
+the request's lat/lon, or of each seed when the request has none, minus the
+history itself (recommendations are places the user has not been). Each
+candidate scores 0-100, with settings.recommendation_weights:
+
+- type: 1 when it shares the seed's BestTime venue_type;
+- price: 1 - |price tier difference| / 3 (0 when either tier is unknown);
+- proximity: 1 at the search point (the seed without lat/lon), 0 at the
+  radius;
+- busyness: fresh live busyness / 100, so lively places rank up right now.
+
+Type, price and proximity are taken against the candidate's most similar
+seed, reported as `similar_to`. A user without history gets no
+recommendations.
+"""
+from __future__ import annotations
+
+import logging
+import time
+from typing import Optional
+
+from pydantic import BaseModel
+
+from app.models import Venue
+from app.services.live_freshness import fresh_live_busyness, utc_now
+from app.services.venue_eligibility import haversine_km
+
+logger = logging.getLogger(__name__)
+
+VISITS_KEY_FORMAT = "user_visits:v1:{}"
+# Same key the engagement service projects favorites into.
+FAVORITES_KEY_FORMAT = "user_favorites:{}"
+RECOMMENDATION_SIGNALS = ("type", "price", "proximity", "busyness")
+
+
+class Recommendation(BaseModel):
+    venue_id: str
+    venue_name: str
+    venue_type: Optional[str] = None
+    venue_lat: float
+    venue_lng: float
+    score: float
+    venue_live_busyness: Optional[int] = None
+    # The history venue this one is most like.
+    similar_to: Optional[str] = None
+
+
+def weight_errors(weights: dict[str, float]) -> list[str]:
+    """Problems with a settings.recommendation_weights value (empty = OK)."""
+    errors = [
+        f"recommendation_weights: unknown signal {name!r}"
+        for name in weights if name not in RECOMMENDATION_SIGNALS
+    ]
+    errors += [
+        f"recommendation_weights: {name} must not be negative"
+        for name, w in weights.items() if w < 0
+    ]
+    if not errors and sum(weights.values()) <= 0:
+        errors.append("recommendation_weights: at least one weight must be positive")
+    return errors
+
+
+class UserHistoryStore:
+    """Favorites (read-only here) and recorded visits of each user."""
+
+    def __init__(self, redis_client, max_visits: int = 200, history_days: int = 90):
+        self.redis = redis_client
+        self.max_visits = max_visits
+        self.ttl_seconds = history_days * 86400
+
+    def record_visit(self, user_id: str, venue_id: str, at: Optional[float] = None) -> None:
+        key = VISITS_KEY_FORMAT.format(user_id)
+        pipe = self.redis.pipeline()
+        pipe.zadd(key, {venue_id: at if at is not None else time.time()})
+        # Keep the newest max_visits.
+        pipe.zremrangebyrank(key, 0, -self.max_visits - 1)
+        pipe.expire(key, self.ttl_seconds)
+        pipe.execute()
+
+    def visits(self, user_id: str) -> list[str]:
+        """Visited venue ids, most recent first."""
+        return list(self.redis.zrevrange(VISITS_KEY_FORMAT.format(user_id), 0, -1))
+
+    def favorites(self, user_id: str) -> set[str]:
+        return set(self.redis.smembers(FAVORITES_KEY_FORMAT.format(user_id)))
+
+    def history(self, user_id: str) -> list[str]:
+        """Favorites and visits, most recent visit first (favorites never
+        visited go last, by id)."""
+        visits = self.visits(user_id)
+        seen = set(visits)
+        return visits + sorted(f for f in self.favorites(user_id) if f not in seen)
+
+
+class RecommendationService:
+    """Ranks venues for a user from their history (module docstring)."""
+
+    def __init__(
+        self,
+        venue_dao,
+        history: UserHistoryStore,
+        weights: dict[str, float],
+        radius_km: float = 5.0,
+        max_seeds: int = 20,
+    ):
+        self.venue_dao = venue_dao
+        self.history = history
+        self.weights = weights
+        self.radius_km = radius_km
+        self.max_seeds = max_seeds
+
+    def recommend(
+        self,
+        user_id: str,
+        lat: Optional[float] = None,
+        lon: Optional[float] = None,
+        limit: int = 10,
+    ) -> list[Recommendation]:
+        """Up to `limit` recommendations, best first."""
+        history = self.history.history(user_id)
+        seeds = [
+            v for v in (self.venue_dao.get_venue(vid) for vid in history[: self.max_seeds])
+            if v is not None
+        ]
+        if not seeds:
+            return []
+        candidates = self._candidates(seeds, lat, lon, exclude=set(history))
+        if not candidates:
+            return []
+        busyness = fresh_live_busyness(self.venue_dao, list(candidates), utc_now())
+        ranked = []
+        for venue in candidates.values():
+            similarity, seed = max(
+                ((self._similarity(venue, s, lat, lon), s.venue_id) for s in seeds),
+                key=lambda pair: pair[0],
+            )
+            live = busyness.get(venue.venue_id)
+            value = similarity + self.weights.get("busyness", 0) * (live or 0) / 100
+            ranked.append(Recommendation(
+                venue_id=venue.venue_id,
+                venue_name=venue.venue_name,
+                venue_type=venue.venue_type,
+                venue_lat=venue.venue_lat,
+                venue_lng=venue.venue_lng,
+                score=round(100 * value / sum(self.weights.values()), 1),
+                venue_live_busyness=live,
+                similar_to=seed,
+            ))
+        ranked.sort(key=lambda r: (-r.score, r.venue_id))
+        return ranked[:limit]
+
+    def _candidates(
+        self, seeds: list[Venue], lat: Optional[float], lon: Optional[float], exclude: set[str]
+    ) -> dict[str, Venue]:
+        centers = [(lat, lon)] if lat is not None and lon is not None else [
+            (s.venue_lat, s.venue_lng) for s in seeds
+        ]
+        found: dict[str, Venue] = {}
+        for c_lat, c_lon in centers:
+            for venue in self.venue_dao.get_nearby_venues(c_lat, c_lon, self.radius_km):
+                if venue.venue_id not in exclude and venue.is_active():
+                    found.setdefault(venue.venue_id, venue)
+        return found
+
+    def _similarity(
+        self, venue: Venue, seed: Venue, lat: Optional[float], lon: Optional[float]
+    ) -> float:
+        """Weighted type + price + proximity similarity of `venue` to `seed`."""
+        same_type = bool(venue.venue_type) and (
+            (venue.venue_type or "").upper() == (seed.venue_type or "").upper()
+        )
+        price = 0.0
+        if venue.price_level is not None and seed.price_level is not None:
+            price = max(0.0, 1 - abs(venue.price_level - seed.price_level) / 3)
+        from_lat, from_lon = (lat, lon) if lat is not None and lon is not None else (
+            seed.venue_lat, seed.venue_lng
+        )
+        distance = haversine_km(from_lat, from_lon, venue.venue_lat, venue.venue_lng)
+        proximity = max(0.0, 1 - distance / self.radius_km)
+        w = self.weights
+        return w.get("type", 0) * same_type + w.get("price", 0) * price + w.get("proximity", 0) * proximity
@@ -3,7 +3,8 @@
 rebuild_redis_from_rds(): RDS -> Redis projection for every active venue,
 INCLUDING the geo index (via redis_only_dao.upsert_venue -> GEOADD) and live
 busyness. This is the scheduled projector body (and manual disaster recovery /
-Redis warm). Photos are projected with their remaining TTL so expired Google
+Redis warm). rebuild_redis_for_tenants() runs it once per tenant
+(app/tenancy.py), each tenant getting the venues inside its bbox. Photos are projected with their remaining TTL so expired Google
 URLs refetch instead of serving stale.
 """
 from __future__ import annotations
@@ -30,6 +31,7 @@ from app.models.instagram import VenueInstagram, VenueInstagramPosts
 from app.models.menu import VenueMenuData, VenueMenuPhotos
 from app.models.venue_review import VenueReviews
 from app.models.vibe_profile import VenueVibeProfile
+from app.tenancy import tenant_scope
 
 logger = logging.getLogger(__name__)
 
@@ -84,7 +86,10 @@ class RedisProjectionService:
         self.eligibility_rule_service = eligibility_rule_service
 
     # ── rebuild: RDS -> Redis (incl. geo index + live busyness) ───────────────
-    def rebuild_redis_from_rds(self) -> dict:
+    def rebuild_redis_from_rds(self, tenant=None) -> dict:
+        """Project the serving view into Redis. With `tenant` (app/tenancy.py)
+        only the venues inside its bbox are projected, into the keyspace of
+        whichever tenant is current (see rebuild_redis_for_tenants)."""
         summary = {
             "venues": 0, "enrichment": 0, "live": 0, "removed": 0, "errors": 0,
             # Venue ids that hit an isolated per-venue exception this cycle
@@ -120,6 +125,15 @@ class RedisProjectionService:
         # logic below is unchanged; only the source of each row/rec moves from a
         # per-call SELECT to a dict lookup on these prefetched maps.
         venue_rows = self.rds_store.get_venues_by_ids(servable_ids)
+        if tenant is not None:
+            # Venues outside the tenant count as not servable for it, so the
+            # reconcile pass below also removes any that moved out.
+            servable_ids = [
+                vid for vid in servable_ids
+                if vid in venue_rows
+                and tenant.contains(venue_rows[vid]["venue_lat"], venue_rows[vid]["venue_lng"])
+            ]
+            servable_set = set(servable_ids)
         enrichment_maps = {
             table_key: self.rds_store.get_enrichment_bulk(table_key, servable_ids)
             for table_key in _REBUILD_MODELS
@@ -130,7 +144,13 @@ class RedisProjectionService:
         weekly_map = self.rds_store.get_weekly_bulk(servable_ids)
         live_map = self.rds_store.get_live_bulk(servable_ids)
 
-        for venue_id in servable_ids:
+        # Core venue JSON + geo members go out in bulk (pipelined GEOADD/SET per
+        # chunk) ahead of the per-venue loop. Reconstruction stays per-venue so a
+        # poisoned row is still isolated at stage=venue; a failed bulk write
+        # falls back to per-venue upserts so one bad member cannot sink the rest.
+        projected_ids = self._project_venues(servable_ids, venue_rows, summary)
+
+        for venue_id in projected_ids:
             # Isolation boundary: any exception while reading/projecting this ONE
             # venue's row, enrichment, photos, weekly, or live data must not abort
             # the run for other venues or skip the reconcile/removal pass below.
@@ -139,14 +159,8 @@ class RedisProjectionService:
             # single poisoned row (e.g. a payload that fails Pydantic validation)
             # degrades to "this venue's remaining stages wait for next cycle"
             # instead of killing the whole projection run.
-            stage = "venue"
+            stage = "enrichment"
             try:
-                row = venue_rows.get(venue_id)
-                venue = venue_from_row(row)  # Ex1: columns + residual, not payload
-                self.redis_only_dao.upsert_venue(venue)  # GEOADD + JSON
-                summary["venues"] += 1
-
-                stage = "enrichment"
                 for table_key, (model_cls, setter, deleter) in _REBUILD_MODELS.items():
                     rec = enrichment_maps[table_key].get(venue_id)
                     if rec is not None:
@@ -214,6 +228,84 @@ class RedisProjectionService:
         logger.info(f"[Rebuild] {summary}")
         return summary
 
+    def rebuild_redis_for_tenants(self, tenants) -> dict:
+        """rebuild_redis_from_rds once per tenant, each into its own keyspace,
+        then once unscoped.
+
+        The unprefixed keyspace keeps every servable venue: gRPC, the area
+        crowd index, webhooks, push, backups and stale eviction read it
+        outside any tenant. Returns the summed tenant counts, with every
+        tenant's own summary under "tenants" and the unscoped one under
+        "unscoped" (its errors are counted too). One tenant failing does not
+        stop the others.
+        """
+        total = {"venues": 0, "enrichment": 0, "live": 0, "removed": 0, "errors": 0,
+                 "error_venues": [], "tenants": {}}
+        try:
+            unscoped = self.rebuild_redis_from_rds()
+        except Exception as e:
+            logger.error(f"[Rebuild] unscoped keyspace failed: {e}")
+            unscoped = {"errors": 1}
+        total["unscoped"] = unscoped
+        total["errors"] += unscoped.get("errors", 0)
+        total["error_venues"].extend(unscoped.get("error_venues", []))
+        for tenant in tenants:
+            with tenant_scope(tenant.id):
+                try:
+                    summary = self.rebuild_redis_from_rds(tenant=tenant)
+                except Exception as e:
+                    logger.error(f"[Rebuild] tenant {tenant.id} failed: {e}")
+                    summary = {"errors": 1}
+            total["tenants"][tenant.id] = summary
+            for name in ("venues", "enrichment", "live", "removed", "errors"):
+                total[name] += summary.get(name, 0)
+            total["error_venues"].extend(summary.get("error_venues", []))
+        REDIS_PROJECTION_VENUES.set(total["venues"])
+        return total
+
+    def _project_venues(self, servable_ids, venue_rows: dict, summary: dict) -> list[str]:
+        """Reconstruct every servable venue and write them with one bulk upsert.
+
+        Returns the ids actually projected, in serving order; the enrichment
+        stages only run for those. Parse and write failures are counted per
+        venue with stage=venue, exactly as the single-venue path logged them.
+        """
+        venues = []
+        for venue_id in servable_ids:
+            try:
+                venues.append(venue_from_row(venue_rows.get(venue_id)))  # Ex1: columns + residual
+            except Exception as e:
+                summary["errors"] += 1
+                summary["error_venues"].append(venue_id)
+                logger.warning(f"[Rebuild] venue {venue_id} failed at stage=venue: {e}")
+        # GEOADD + JSON, pipelined. A failed chunk's venues land in `failed`
+        # and are retried one by one; anything else retries every venue.
+        failed: dict[str, Exception] = {}
+        try:
+            self.redis_only_dao.upsert_venues(venues, errors=failed)
+            retry = [v for v in venues if v.venue_id in failed]
+        except Exception as e:
+            failed = {v.venue_id: e for v in venues}
+            retry = venues
+        if retry:
+            logger.warning(
+                f"[Rebuild] bulk venue upsert failed for {len(retry)} venues; "
+                f"retrying per venue: {next(iter(failed.values()))}"
+            )
+        for venue in retry:
+            try:
+                self.redis_only_dao.upsert_venue(venue)
+                del failed[venue.venue_id]
+            except Exception as e:
+                summary["errors"] += 1
+                summary["error_venues"].append(venue.venue_id)
+                logger.warning(
+                    f"[Rebuild] venue {venue.venue_id} failed at stage=venue: {e}"
+                )
+        projected = [v.venue_id for v in venues if v.venue_id not in failed]
+        summary["venues"] += len(projected)
+        return projected
+
     def _project_photos(self, venue_id: str, rec: Optional[dict]) -> None:
         """B2: project photos with the REMAINING TTL (full − age) so repeated
         runs count the TTL down instead of re-stamping a fresh full TTL; drop
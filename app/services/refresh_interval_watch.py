@@ -4,8 +4,9 @@ A lightweight watcher job reads `admin_config:live_refresh_minutes` from
 Redis (written by the vibesadmin panel, mirroring the
 `admin_config:venue_photos_cache_ttl_days` pattern) and reschedules the
 `live_forecast_refresh` job on the running scheduler when the effective
-value changes. Absent key -> settings default; invalid value -> keep the
-current schedule. Bad input can never stall or kill the refresh.
+value changes. Absent key -> settings default (or the holiday interval on
+holiday-calendar boost days); invalid value -> keep the current schedule.
+Bad input can never stall or kill the refresh.
 """
 
 from __future__ import annotations
@@ -13,7 +14,7 @@ from __future__ import annotations
 import json
 import logging
 import time
-from typing import Optional
+from typing import Callable, Optional
 
 from apscheduler.triggers.interval import IntervalTrigger
 
@@ -39,13 +40,21 @@ class RefreshIntervalWatcher:
 
     `redis_client` needs only `.get(key)` (GeoRedisClient and raw
     redis-py/fakeredis clients all qualify). `scheduler` needs only
-    `.reschedule_job(job_id, trigger=...)`.
+    `.reschedule_job(job_id, trigger=...)`. `holiday_minutes` returns the
+    boosted interval on holiday boost days, else None.
     """
 
-    def __init__(self, redis_client, scheduler, default_minutes: int):
+    def __init__(
+        self,
+        redis_client,
+        scheduler,
+        default_minutes: int,
+        holiday_minutes: Callable[[], Optional[int]] = lambda: None,
+    ):
         self._redis = redis_client
         self._scheduler = scheduler
         self._default = int(default_minutes)
+        self._holiday_minutes = holiday_minutes
         self._applied = int(default_minutes)
         # Warn once per distinct rejected raw value, not on every tick.
         self._last_rejected: Optional[str] = None
@@ -55,6 +64,11 @@ class RefreshIntervalWatcher:
     def applied_minutes(self) -> int:
         return self._applied
 
+    def set_default(self, minutes: int) -> None:
+        """Change the interval used without an admin key (settings hot
+        reload); the next check_once applies it."""
+        self._default = int(minutes)
+
     def _parse_minutes(self, raw: str) -> Optional[int]:
         """A valid value is a JSON integer within [MIN, MAX] — bare or
         wrapped as {"minutes": N} (the shape the vibesadmin → cs-server
@@ -79,7 +93,11 @@ class RefreshIntervalWatcher:
         """
         raw = self._redis.get(ADMIN_LIVE_REFRESH_MINUTES_KEY)
         if raw is None:
-            effective, source = self._default, "default"
+            holiday = self._holiday_minutes()
+            if holiday:
+                effective, source = int(holiday), "holiday"
+            else:
+                effective, source = self._default, "default"
             self._last_rejected = None
         else:
             if isinstance(raw, bytes):
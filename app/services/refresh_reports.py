@@ -0,0 +1,134 @@
+"""Per-run reports of the BestTime refresh jobs, kept in Redis for auditing.
+
+Each decorated refresher run (`@reported("live_forecast")`, ...) opens a
+RefreshReport that the code it calls fills through `note` / `note_error`
+(locations queried, venues found and upserted, live forecasts cached, stale
+ones deleted, errors). When the run ends the report is pushed onto a capped
+Redis list (`admin:refresh_reports`, newest first) and served by
+GET /admin/refresh/history, so data freshness can be checked without the logs.
+
+The open report lives in a ContextVar: concurrent runs of different jobs each
+fill their own, and the workers a run gathers share it.
+"""
+from __future__ import annotations
+
+import functools
+import json
+import logging
+import time
+from contextvars import ContextVar
+from dataclasses import asdict, dataclass, field
+from datetime import datetime, timezone
+from typing import Optional
+
+logger = logging.getLogger(__name__)
+
+REFRESH_REPORTS_KEY = "admin:refresh_reports"
+MAX_ERROR_SAMPLES = 10
+
+
+@dataclass
+class RefreshReport:
+    job: str
+    started_at: datetime
+    finished_at: Optional[datetime] = None
+    duration_seconds: float = 0.0
+    status: str = "ok"  # ok | partial (some errors) | failed (the run raised)
+    locations: int = 0  # discovery locations / points queried
+    venues_found: int = 0  # venues the sources returned (or were selected)
+    upserted: int = 0
+    live_cached: int = 0
+    weekly_cached: int = 0
+    deleted: int = 0  # stale live forecasts removed
+    errors: int = 0
+    error_samples: list[str] = field(default_factory=list)
+
+    def to_dict(self) -> dict:
+        data = asdict(self)
+        data["started_at"] = self.started_at.isoformat()
+        data["finished_at"] = self.finished_at.isoformat() if self.finished_at else None
+        return data
+
+
+_current: ContextVar[Optional[RefreshReport]] = ContextVar("refresh_report", default=None)
+
+
+def current_report() -> Optional[RefreshReport]:
+    return _current.get()
+
+
+def note(**counts: int) -> None:
+    """Add `counts` to the open report's counters (no-op outside a run)."""
+    report = _current.get()
+    if report is None:
+        return
+    for name, n in counts.items():
+        setattr(report, name, getattr(report, name) + n)
+
+
+def note_error(message: str, n: int = 1) -> None:
+    """Count `n` errors on the open report, keeping the first few messages."""
+    report = _current.get()
+    if report is None:
+        return
+    report.errors += n
+    if len(report.error_samples) < MAX_ERROR_SAMPLES:
+        report.error_samples.append(message[:300])
+
+
+class RefreshReportStore:
+    """Capped newest-first Redis list of finished run reports."""
+
+    def __init__(self, redis_client, max_entries: int = 200):
+        self.redis = redis_client
+        self.max_entries = max(1, max_entries)
+
+    def record(self, report: RefreshReport) -> None:
+        """Best-effort: a Redis failure is logged, never raised."""
+        try:
+            self.redis.lpush(REFRESH_REPORTS_KEY, json.dumps(report.to_dict()))
+            self.redis.ltrim(REFRESH_REPORTS_KEY, 0, self.max_entries - 1)
+        except Exception as e:
+            logger.warning(f"[RefreshReports] Failed to record {report.job} report: {e}")
+
+    def history(self, job: Optional[str] = None, limit: int = 50) -> list[dict]:
+        """Newest-first reports, optionally of one job only."""
+        reports = []
+        for raw in self.redis.lrange(REFRESH_REPORTS_KEY, 0, -1):
+            try:
+                entry = json.loads(raw)
+            except (TypeError, ValueError):
+                continue
+            if job is None or entry.get("job") == job:
+                reports.append(entry)
+                if len(reports) >= limit:
+                    break
+        return reports
+
+
+def reported(job: str):
+    """Decorate an async refresher method so each call produces a report,
+    recorded through the instance's `run_reports` store (None = not kept)."""
+    def decorate(fn):
+        @functools.wraps(fn)
+        async def run(self, *args, **kwargs):
+            report = RefreshReport(job=job, started_at=datetime.now(timezone.utc))
+            token = _current.set(report)
+            started = time.perf_counter()
+            try:
+                return await fn(self, *args, **kwargs)
+            except BaseException as e:
+                report.status = "failed"
+                report.error_samples.insert(0, f"{type(e).__name__}: {e}"[:300])
+                raise
+            finally:
+                _current.reset(token)
+                report.finished_at = datetime.now(timezone.utc)
+                report.duration_seconds = round(time.perf_counter() - started, 3)
+                if report.status == "ok" and report.errors:
+                    report.status = "partial"
+                store = getattr(self, "run_reports", None)
+                if store is not None:
+                    store.record(report)
+        return run
+    return decorate
@@ -0,0 +1,181 @@
+"""Region profiles: the cities one server covers.
+
+A region (settings.regions) groups what used to be Recife-only configuration:
+
+- `points`: its discovery points (the same shape as /admin/locations), queried
+  by the catalog refresh when neither admin points nor discovery_locations_file
+  are set;
+- `catalog_refresh_minutes`: how often its points are queried. The catalog job
+  still runs every venues_catalog_refresh_minutes and takes the regions that
+  are due (0 = every run). A region counts as discovered only once every one
+  of its points was queried without error; the time is kept in Redis
+  (`regions:last_discovery`, region id -> epoch seconds), so every replica
+  and a restart see it;
+- `search_city` and `cities`: the city text appended to venue searches (e.g.
+  the Instagram handle search) for venues inside the region, `cities` being
+  names recognised in addresses before falling back to `search_city`;
+- `bbox`: [min_lat, min_lng, max_lat, max_lng], or the box around its points.
+
+Several regions run side by side; GET /v1/regions lists them with their
+coverage. Every region must lie inside the geo-fence (PUT
+/admin/config/geofence): venues found outside it would be paid for but never
+served, so the server refuses to start, and the fence refuses an update, that
+leaves a region out. With no regions configured discovery uses DEFAULT_LOCATIONS and
+searches say "Recife", as before.
+"""
+from __future__ import annotations
+
+import math
+import time
+from typing import Callable, Iterator, Optional
+
+from pydantic import BaseModel, Field, model_validator
+
+from app.services.discovery_locations import DiscoveryPoint
+from app.services.venue_eligibility import geo_excluded
+
+# Meters per degree of latitude.
+_M_PER_DEG = 111_320.0
+
+LAST_DISCOVERY_KEY = "regions:last_discovery"
+
+
+class RegionProfile(BaseModel):
+    """One entry of settings.regions."""
+
+    id: str = Field(pattern=r"^[A-Za-z0-9_-]+$")
+    name: str
+    bbox: Optional[list[float]] = None
+    points: list[DiscoveryPoint] = []
+    search_city: str = ""
+    cities: list[str] = []
+    catalog_refresh_minutes: int = Field(default=0, ge=0)
+
+    @model_validator(mode="after")
+    def _check_coverage(self):
+        if self.bbox is None:
+            if not self.points:
+                raise ValueError(f"region {self.id} needs a bbox or points")
+        elif len(self.bbox) != 4 or self.bbox[0] > self.bbox[2] or self.bbox[1] > self.bbox[3]:
+            raise ValueError(f"region {self.id}: bbox must be [min_lat, min_lng, max_lat, max_lng]")
+        return self
+
+    def bounds(self) -> list[float]:
+        """The bbox, or the box around every point's search circle."""
+        if self.bbox is not None:
+            return list(self.bbox)
+        lats, lngs = [], []
+        for p in self.points:
+            dlat = p.radius / _M_PER_DEG
+            dlng = p.radius / (_M_PER_DEG * max(math.cos(math.radians(p.lat)), 1e-6))
+            lats += [p.lat - dlat, p.lat + dlat]
+            lngs += [p.lng - dlng, p.lng + dlng]
+        return [round(min(lats), 5), round(min(lngs), 5), round(max(lats), 5), round(max(lngs), 5)]
+
+    def contains(self, lat: Optional[float], lng: Optional[float]) -> bool:
+        if lat is None or lng is None:
+            return False
+        min_lat, min_lng, max_lat, max_lng = self.bounds()
+        return min_lat <= lat <= max_lat and min_lng <= lng <= max_lng
+
+    def center(self) -> tuple[float, float]:
+        min_lat, min_lng, max_lat, max_lng = self.bounds()
+        return (min_lat + max_lat) / 2, (min_lng + max_lng) / 2
+
+    def city_for(self, address: str) -> str:
+        """The city to put in a search for a venue at `address` in this region."""
+        address_lower = (address or "").lower()
+        for city in self.cities:
+            if city.lower() in address_lower:
+                return city.title()
+        return self.search_city or self.name
+
+
+class RegionCoverage(BaseModel):
+    """A region as listed by GET /v1/regions."""
+
+    id: str
+    name: str
+    bbox: list[float] = Field(description="[min_lat, min_lng, max_lat, max_lng]")
+    points: list[dict] = Field(description="discovery circles: lat, lng, radius (meters)")
+
+
+class RegionRegistry:
+    """The configured regions and when each was last discovered."""
+
+    def __init__(self, regions: list[dict], clock: Callable[[], float] = time.time):
+        """
+        Raises:
+            ValueError: an invalid region or a duplicate id
+        """
+        self.regions: dict[str, RegionProfile] = {}
+        for raw in regions:
+            region = RegionProfile.model_validate(raw)
+            if region.id in self.regions:
+                raise ValueError(f"duplicate region id {region.id}")
+            self.regions[region.id] = region
+        self._clock = clock
+        # Last discovery per region; kept in memory until set_redis.
+        self.redis = None
+        self._last_run: dict[str, float] = {}
+
+    def set_redis(self, redis_client) -> None:
+        """Keep the last discovery times in Redis, shared by every replica."""
+        self.redis = redis_client
+
+    def __bool__(self) -> bool:
+        return bool(self.regions)
+
+    def __iter__(self) -> Iterator[RegionProfile]:
+        return iter(self.regions.values())
+
+    def region_for(self, lat: Optional[float], lng: Optional[float]) -> Optional[RegionProfile]:
+        """The first region whose box contains the point."""
+        return next((r for r in self if r.contains(lat, lng)), None)
+
+    def _last_runs(self) -> dict[str, float]:
+        if self.redis is None:
+            return dict(self._last_run)
+        return {k: float(v) for k, v in self.redis.hgetall(LAST_DISCOVERY_KEY).items()}
+
+    def due(self) -> list[RegionProfile]:
+        """Regions whose catalog refresh is due (see mark_discovered)."""
+        now = self._clock()
+        last_runs = self._last_runs()
+        return [
+            region for region in self
+            if region.id not in last_runs
+            or now - last_runs[region.id] >= region.catalog_refresh_minutes * 60
+        ]
+
+    def mark_discovered(self, region_ids: list[str]) -> None:
+        """Record a successful discovery of these regions now."""
+        if not region_ids:
+            return
+        now = self._clock()
+        if self.redis is None:
+            self._last_run.update({region_id: now for region_id in region_ids})
+        else:
+            self.redis.hset(LAST_DISCOVERY_KEY, mapping={region_id: now for region_id in region_ids})
+
+    def outside_fence(self, fence: Optional[dict]) -> list[str]:
+        """Ids of regions whose center or a discovery point is outside the
+        geo-fence."""
+        return [
+            region.id for region in self
+            if any(
+                geo_excluded(lat, lng, fence)
+                for lat, lng in [region.center(), *((p.lat, p.lng) for p in region.points)]
+            )
+        ]
+
+    def coverage(self) -> list[RegionCoverage]:
+        return [
+            RegionCoverage(
+                id=r.id,
+                name=r.name,
+                bbox=r.bounds(),
+                points=[{"lat": p.lat, "lng": p.lng, "radius": p.radius} for p in r.points],
+            )
+            for r in self
+        ]
@@ -0,0 +1,217 @@
+"""Redis-backed retry queue for refresh work that failed.
+
+A venue upsert or live-forecast fetch that fails during a refresh used to be
+logged and lost until the next full run (a month away for catalog upserts).
+The refresher now pushes it here instead, and the scheduled retry worker
+(VenuesRefresherService.process_retry_queue) tries it again with exponential
+backoff. After `max_attempts` failed retries an item moves to the dead-letter
+list, where it stays for inspection (GET /admin/retry-queue) until requeued or
+cleared.
+
+Layout:
+- `retry_queue:items`: hash "<kind>:<item_id>" -> RetryItem JSON;
+- `retry_queue:due`: sorted set of the same keys, scored by next attempt time;
+- `retry_queue:dead`: capped newest-first list of dead RetryItem JSON.
+
+Another queue can live beside it under its own `namespace` (the key prefix);
+the webhook deliveries (app/services/webhooks.py) use one.
+
+Pushing an item that is already queued only refreshes its error and payload:
+its attempts and schedule stand, so a failure in every refresh does not keep
+resetting the backoff.
+"""
+from __future__ import annotations
+
+import json
+import logging
+import time
+from dataclasses import asdict, dataclass
+from typing import Any, Optional
+
+from app.metrics import RETRY_QUEUE_ITEMS_TOTAL
+
+logger = logging.getLogger(__name__)
+
+ITEMS_KEY = "retry_queue:items"
+DUE_KEY = "retry_queue:due"
+DEAD_KEY = "retry_queue:dead"
+DEAD_MAX = 1000
+
+UPSERT_VENUE = "upsert_venue"
+LIVE_FORECAST = "live_forecast"
+
+
+@dataclass
+class RetryItem:
+    kind: str  # UPSERT_VENUE | LIVE_FORECAST
+    item_id: str  # venue id
+    payload: Optional[Any] = None  # e.g. the venue JSON to upsert again
+    attempts: int = 0  # retries made so far
+    last_error: str = ""
+    first_failed_at: float = 0.0  # epoch seconds
+    last_failed_at: float = 0.0
+    next_attempt_at: float = 0.0
+
+    @property
+    def key(self) -> str:
+        return f"{self.kind}:{self.item_id}"
+
+
+def _decode(raw) -> Optional[RetryItem]:
+    try:
+        return RetryItem(**json.loads(raw))
+    except (TypeError, ValueError) as e:
+        logger.warning(f"[RetryQueue] Dropping unreadable item: {e}")
+        return None
+
+
+class RetryQueue:
+    """Failed refresh work waiting for another attempt."""
+
+    def __init__(
+        self,
+        redis_client,
+        max_attempts: int = 5,
+        backoff_seconds: float = 60.0,
+        max_backoff_seconds: float = 3600.0,
+        namespace: str = "retry_queue",
+    ):
+        """
+        Args:
+            redis_client: Redis client (decode_responses=True)
+            max_attempts: failed retries before an item is dead-lettered
+            backoff_seconds: delay before the first retry; doubles per attempt
+            max_backoff_seconds: cap on the delay
+            namespace: key prefix; the default is the refresher's queue
+        """
+        self.redis = redis_client
+        self.max_attempts = max(1, max_attempts)
+        self.backoff_seconds = backoff_seconds
+        self.max_backoff_seconds = max_backoff_seconds
+        self.items_key = f"{namespace}:items"
+        self.due_key = f"{namespace}:due"
+        self.dead_key = f"{namespace}:dead"
+
+    def backoff(self, attempts: int) -> float:
+        """Delay before the retry following `attempts` retries."""
+        return min(self.backoff_seconds * (2 ** attempts), self.max_backoff_seconds)
+
+    def _save(self, item: RetryItem) -> None:
+        pipe = self.redis.pipeline()
+        pipe.hset(self.items_key, item.key, json.dumps(asdict(item)))
+        pipe.zadd(self.due_key, {item.key: item.next_attempt_at})
+        pipe.execute()
+
+    def push(self, kind: str, item_id: str, error: str, payload: Any = None) -> None:
+        """Queue failed work. Best-effort: a Redis failure is logged, never raised."""
+        now = time.time()
+        try:
+            raw = self.redis.hget(self.items_key, f"{kind}:{item_id}")
+            item = _decode(raw) if raw is not None else None
+            if item is None:
+                item = RetryItem(
+                    kind=kind, item_id=item_id, first_failed_at=now,
+                    next_attempt_at=now + self.backoff(0),
+                )
+                RETRY_QUEUE_ITEMS_TOTAL.labels(kind=kind, result="queued").inc()
+            item.payload = payload if payload is not None else item.payload
+            item.last_error = error[:500]
+            item.last_failed_at = now
+            self._save(item)
+        except Exception as e:
+            logger.warning(f"[RetryQueue] Failed to queue {kind} {item_id}: {e}")
+
+    def due(self, limit: int = 100, now: Optional[float] = None) -> list[RetryItem]:
+        """Up to `limit` items whose next attempt time has come, oldest first."""
+        now = time.time() if now is None else now
+        keys = self.redis.zrangebyscore(self.due_key, "-inf", now, start=0, num=limit)
+        if not keys:
+            return []
+        items = []
+        for key, raw in zip(keys, self.redis.hmget(self.items_key, keys)):
+            item = _decode(raw) if raw is not None else None
+            if item is None:
+                self.redis.zrem(self.due_key, key)
+                self.redis.hdel(self.items_key, key)
+                continue
+            items.append(item)
+        return items
+
+    def succeeded(self, item: RetryItem) -> None:
+        """The retry worked (or is no longer needed): forget the item."""
+        pipe = self.redis.pipeline()
+        pipe.zrem(self.due_key, item.key)
+        pipe.hdel(self.items_key, item.key)
+        pipe.execute()
+        RETRY_QUEUE_ITEMS_TOTAL.labels(kind=item.kind, result="succeeded").inc()
+
+    def failed(self, item: RetryItem, error: str, now: Optional[float] = None) -> bool:
+        """Count a failed retry and reschedule it, or dead-letter it after
+        max_attempts. Returns True when the item was dead-lettered."""
+        now = time.time() if now is None else now
+        item.attempts += 1
+        item.last_error = error[:500]
+        item.last_failed_at = now
+        if item.attempts >= self.max_attempts:
+            pipe = self.redis.pipeline()
+            pipe.zrem(self.due_key, item.key)
+            pipe.hdel(self.items_key, item.key)
+            pipe.lpush(self.dead_key, json.dumps(asdict(item)))
+            pipe.ltrim(self.dead_key, 0, DEAD_MAX - 1)
+            pipe.execute()
+            RETRY_QUEUE_ITEMS_TOTAL.labels(kind=item.kind, result="dead").inc()
+            logger.warning(
+                f"[RetryQueue] Giving up on {item.key} after {item.attempts} retries: {error}"
+            )
+            return True
+        item.next_attempt_at = now + self.backoff(item.attempts)
+        self._save(item)
+        RETRY_QUEUE_ITEMS_TOTAL.labels(kind=item.kind, result="retry_failed").inc()
+        return False
+
+    def postpone(self, item: RetryItem, seconds: float) -> None:
+        """Try again in `seconds` without counting an attempt (the retry could
+        not run, e.g. BestTime's circuit is open)."""
+        item.next_attempt_at = time.time() + seconds
+        self._save(item)
+
+    def stats(self, now: Optional[float] = None) -> dict:
+        now = time.time() if now is None else now
+        return {
+            "queued": self.redis.zcard(self.due_key),
+            "due": self.redis.zcount(self.due_key, "-inf", now),
+            "dead": self.redis.llen(self.dead_key),
+        }
+
+    def queued(self, limit: int = 100) -> list[dict]:
+        """Queued items, next due first."""
+        keys = self.redis.zrange(self.due_key, 0, limit - 1)
+        raws = self.redis.hmget(self.items_key, keys) if keys else []
+        return [asdict(item) for item in map(_decode, filter(None, raws)) if item is not None]
+
+    def dead_letters(self, limit: int = 100) -> list[dict]:
+        """Dead-lettered items, newest first."""
+        return [
+            asdict(item) for item in map(_decode, self.redis.lrange(self.dead_key, 0, limit - 1))
+            if item is not None
+        ]
+
+    def requeue_dead(self) -> int:
+        """Queue every dead-lettered item again with fresh attempts, due now."""
+        raws = self.redis.lrange(self.dead_key, 0, -1)
+        self.redis.delete(self.dead_key)
+        requeued = 0
+        for item in map(_decode, raws):
+            if item is None:
+                continue
+            item.attempts = 0
+            item.next_attempt_at = time.time()
+            self._save(item)
+            requeued += 1
+        return requeued
+
+    def clear_dead(self) -> int:
+        """Drop the dead letters; returns how many there were."""
+        n = self.redis.llen(self.dead_key)
+        self.redis.delete(self.dead_key)
+        return n
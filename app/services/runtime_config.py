@@ -0,0 +1,233 @@
+"""Hot reload of the runtime-tunable settings.
+
+A handful of settings can change without a restart (RELOADABLE_FIELDS): the
+refresh schedules, BestTime pacing, refresh concurrency/limits, the discovery
+locations file and the log level. Each has an applier that pushes the new value
+into the running scheduler / client / refresher; the settings objects are
+updated too, so readers of `settings.<field>` see it.
+
+Values come from, in increasing precedence:
+
+- the startup settings (env, config file, defaults);
+- the config file (CONFIG_FILE) when it changes on disk: its reloadable keys are
+  applied, unless an env var pins them; other changed keys are only logged
+  (they need a restart);
+- overrides in the Redis hash `runtime_config:v1` (field -> JSON value),
+  written by PUT /admin/runtime-config and shared by every replica.
+
+check_once() (scheduled every config_reload_seconds) reconciles the three and
+applies what changed; PUT applies on the receiving replica immediately.
+Values are validated against the Settings field type and config_errors()
+before anything is applied or stored.
+"""
+from __future__ import annotations
+
+import json
+import logging
+import os
+from pathlib import Path
+from typing import Any, Callable, Optional
+
+from pydantic import TypeAdapter, ValidationError
+
+from app.config import Settings, load_json_config
+
+logger = logging.getLogger(__name__)
+
+OVERRIDES_KEY = "runtime_config:v1"
+
+RELOADABLE_FIELDS = (
+    "venues_live_refresh_minutes",
+    "venues_catalog_refresh_minutes",
+    "weekly_forecast_cron",
+    "besttime_rate_per_second",
+    "besttime_rate_burst",
+    "live_fetch_concurrency",
+    "discovery_concurrency",
+    "fetch_venue_total_limit",
+    "discovery_locations_file",
+    "log_level",
+)
+
+
+class RuntimeConfigService:
+    """Applies and reports the reloadable settings."""
+
+    def __init__(
+        self,
+        settings: Settings,
+        redis_client,
+        appliers: dict[str, Callable[[Any], None]],
+        config_file: Optional[str] = None,
+        mirrors: tuple = (),
+    ):
+        """
+        Args:
+            settings: the settings the server runs with
+            redis_client: raw Redis client (decode_responses=True)
+            appliers: field -> callable applying a validated value to the
+                running components (fields without one are not reloadable)
+            config_file: file to watch (default: CONFIG_FILE)
+            mirrors: other Settings instances to keep in step (the module-level
+                app.config.settings readers use)
+        """
+        self.settings = settings
+        self.redis = redis_client
+        self.appliers = {k: v for k, v in appliers.items() if k in RELOADABLE_FIELDS}
+        self.config_file = config_file if config_file is not None else os.getenv("CONFIG_FILE", "")
+        self.mirrors = [m for m in mirrors if m is not settings]
+        self._baseline = {name: getattr(settings, name) for name in self.appliers}
+        self._sources = {name: "startup" for name in self.appliers}
+        self._file_mtime = self._mtime()
+        self._file_values = load_json_config(self.config_file) if self.config_file else {}
+
+    # ── validation ──────────────────────────────────────────────────────────
+    def validate(self, changes: dict[str, Any]) -> dict[str, Any]:
+        """Coerce `changes` to the field types and check the result.
+
+        Raises:
+            ValueError: unknown or non-reloadable field, wrong type, or a value
+                Settings.config_errors() rejects
+        """
+        validated = {}
+        for name, value in changes.items():
+            if name not in self.appliers:
+                raise ValueError(f"{name} is not a reloadable setting")
+            annotation = Settings.model_fields[name].annotation
+            try:
+                validated[name] = TypeAdapter(annotation).validate_python(value)
+            except ValidationError as e:
+                raise ValueError(f"{name}: {e.errors()[0]['msg']}") from None
+        candidate = self.settings.model_copy(update=validated)
+        new_errors = set(candidate.config_errors()) - set(self.settings.config_errors())
+        if new_errors:
+            raise ValueError("; ".join(sorted(new_errors)))
+        if "weekly_forecast_cron" in validated:
+            from apscheduler.triggers.cron import CronTrigger
+
+            CronTrigger.from_crontab(validated["weekly_forecast_cron"])
+        return validated
+
+    # ── applying ────────────────────────────────────────────────────────────
+    def _apply(self, values: dict[str, Any], source: str) -> list[str]:
+        """Apply the values that differ from the running ones; returns the
+        fields applied. A failing applier leaves its field unchanged."""
+        applied = []
+        for name, value in values.items():
+            if getattr(self.settings, name) == value:
+                self._sources[name] = source
+                continue
+            previous = getattr(self.settings, name)
+            try:
+                self.appliers[name](value)
+            except Exception as e:
+                logger.error(f"[RuntimeConfig] Applying {name}={value!r} failed: {e}")
+                continue
+            for target in (self.settings, *self.mirrors):
+                setattr(target, name, value)
+            self._sources[name] = source
+            applied.append(name)
+            logger.warning(f"[RuntimeConfig] {name}: {previous!r} -> {value!r} (source={source})")
+        return applied
+
+    def _overrides(self) -> dict[str, Any]:
+        """Valid Redis overrides; bad entries are logged and skipped."""
+        out = {}
+        for name, raw in self.redis.hgetall(OVERRIDES_KEY).items():
+            try:
+                out.update(self.validate({name: json.loads(raw)}))
+            except (ValueError, TypeError) as e:
+                logger.warning(f"[RuntimeConfig] Ignoring override {name}={raw!r}: {e}")
+        return out
+
+    def update(self, changes: dict[str, Any]) -> dict:
+        """Validate, store as overrides for every replica, apply here.
+
+        Raises:
+            ValueError: see validate()
+        """
+        validated = self.validate(changes)
+        if validated:
+            self.redis.hset(
+                OVERRIDES_KEY, mapping={k: json.dumps(v) for k, v in validated.items()}
+            )
+            self._apply(validated, "admin")
+        return self.snapshot()
+
+    def reset(self, name: str) -> dict:
+        """Drop the override of `name` and go back to its file/startup value.
+
+        Raises:
+            ValueError: not a reloadable field
+        """
+        if name not in self.appliers:
+            raise ValueError(f"{name} is not a reloadable setting")
+        self.redis.hdel(OVERRIDES_KEY, name)
+        self._apply({name: self._baseline[name]}, self._baseline_source(name))
+        return self.snapshot()
+
+    def _baseline_source(self, name: str) -> str:
+        return "file" if name in self._file_values and not _env_pinned(name) else "startup"
+
+    # ── watching ────────────────────────────────────────────────────────────
+    def _mtime(self) -> Optional[float]:
+        if not self.config_file:
+            return None
+        try:
+            return Path(self.config_file).stat().st_mtime
+        except OSError:
+            return None
+
+    def _reload_file(self) -> None:
+        values = load_json_config(self.config_file)
+        for name, value in values.items():
+            if name in self.appliers:
+                if _env_pinned(name):
+                    continue
+                try:
+                    self._baseline.update(self.validate({name: value}))
+                except (ValueError, TypeError) as e:
+                    logger.warning(f"[RuntimeConfig] Ignoring {name} from the config file: {e}")
+            elif name in Settings.model_fields and self._file_values.get(name) != value:
+                logger.warning(
+                    f"[RuntimeConfig] {name} changed in the config file; restart to apply"
+                )
+        self._file_values = values
+        logger.info(f"[RuntimeConfig] Reloaded {self.config_file}")
+
+    def check_once(self) -> list[str]:
+        """Reload the config file if it changed, then apply file and Redis
+        values that differ from the running ones. Returns the fields applied."""
+        mtime = self._mtime()
+        if mtime is not None and mtime != self._file_mtime:
+            self._file_mtime = mtime
+            self._reload_file()
+        overrides = self._overrides()
+        applied = []
+        for name in self.appliers:
+            if name in overrides:
+                applied += self._apply({name: overrides[name]}, "admin")
+            else:
+                applied += self._apply({name: self._baseline[name]}, self._baseline_source(name))
+        return applied
+
+    async def run(self) -> None:
+        """Scheduled entry point; errors are logged, never raised."""
+        try:
+            self.check_once()
+        except Exception as e:
+            logger.error(f"[RuntimeConfig] Reload check failed: {e}")
+
+    def snapshot(self) -> dict:
+        """Every reloadable field with its running value and where it came from."""
+        return {
+            "config_file": self.config_file or None,
+            "fields": {
+                name: {"value": getattr(self.settings, name), "source": self._sources[name]}
+                for name in sorted(self.appliers)
+            },
+        }
+
+
+def _env_pinned(name: str) -> bool:
+    return os.getenv(name.upper()) is not None
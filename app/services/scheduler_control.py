@@ -0,0 +1,180 @@
+"""Inspect and steer the APScheduler background jobs at runtime.
+
+make_job (main.py) reports every run here (`run_started` / `run_finished`),
+so each job's last start, outcome, duration and error are known alongside the
+scheduler's own next-run time. SchedulerControl adds the operator actions
+behind /admin/scheduler: pause or resume one job or the whole scheduler, run a
+job now, and stop (cancel) a run in flight.
+
+A job that takes a job_lock also reports who holds it, including the owning
+replica when the Redis lock is on. State is in-process: a restart resumes
+every job and forgets the run history.
+"""
+from __future__ import annotations
+
+import asyncio
+import inspect
+import logging
+import time
+from dataclasses import asdict, dataclass
+from datetime import datetime, timezone
+from typing import Optional
+
+from apscheduler.schedulers.base import STATE_PAUSED
+
+from app.services import job_lock
+
+logger = logging.getLogger(__name__)
+
+
+@dataclass
+class JobRun:
+    """What is known about a job's runs in this process."""
+    last_started_at: Optional[datetime] = None
+    last_finished_at: Optional[datetime] = None
+    # success | error | cancelled | skipped | skipped_quota
+    last_status: Optional[str] = None
+    last_error: Optional[str] = None
+    last_duration_seconds: Optional[float] = None
+    runs: int = 0
+
+
+_runs: dict[str, JobRun] = {}
+_tasks: dict[str, asyncio.Task] = {}
+_started: dict[str, float] = {}
+# job id -> the job_lock name its runs take (see main.make_job's lock_name).
+_lock_names: dict[str, str] = {}
+
+
+def register_lock(job_id: str, lock_name: str) -> None:
+    """Report `lock_name`'s holder in `job_id`'s status."""
+    _lock_names[job_id] = lock_name
+
+
+def run_started(job_id: str, task: Optional[asyncio.Task]) -> None:
+    """Record that `job_id` started running as `task`."""
+    run = _runs.setdefault(job_id, JobRun())
+    run.last_started_at = datetime.now(timezone.utc)
+    _started[job_id] = time.perf_counter()
+    if task is not None:
+        _tasks[job_id] = task
+
+
+def run_finished(job_id: str, status: str, error: Optional[str] = None) -> None:
+    """Record how the run of `job_id` started last ended."""
+    run = _runs.setdefault(job_id, JobRun())
+    run.last_finished_at = datetime.now(timezone.utc)
+    run.last_status = status
+    run.last_error = error
+    started = _started.pop(job_id, None)
+    run.last_duration_seconds = (
+        round(time.perf_counter() - started, 3) if started is not None else None
+    )
+    run.runs += 1
+    _tasks.pop(job_id, None)
+
+
+def running_task(job_id: str) -> Optional[asyncio.Task]:
+    """The in-flight run of `job_id`, if any."""
+    task = _tasks.get(job_id)
+    return task if task is not None and not task.done() else None
+
+
+def last_run(job_id: str) -> JobRun:
+    return _runs.get(job_id) or JobRun()
+
+
+def reset() -> None:
+    """Forget all run history (tests)."""
+    _runs.clear()
+    _tasks.clear()
+    _started.clear()
+
+
+def _iso(value: Optional[datetime]) -> Optional[str]:
+    return value.isoformat() if value is not None else None
+
+
+class SchedulerControl:
+    """Operator view and actions over one running AsyncIOScheduler."""
+
+    def __init__(self, scheduler) -> None:
+        self.scheduler = scheduler
+
+    @property
+    def paused(self) -> bool:
+        return self.scheduler.state == STATE_PAUSED
+
+    def _status(self, job) -> dict:
+        run = last_run(job.id)
+        return {
+            "id": job.id,
+            "name": job.name,
+            "trigger": str(job.trigger),
+            # APScheduler keeps no next run time for a paused job.
+            "paused": job.next_run_time is None,
+            "next_run_at": _iso(job.next_run_time),
+            "running": running_task(job.id) is not None,
+            "lock": job_lock.holder(_lock_names[job.id]) if job.id in _lock_names else None,
+            **{k: _iso(v) if isinstance(v, datetime) else v for k, v in asdict(run).items()},
+        }
+
+    def list_jobs(self) -> list[dict]:
+        return [self._status(job) for job in self.scheduler.get_jobs()]
+
+    def get_job(self, job_id: str) -> Optional[dict]:
+        job = self.scheduler.get_job(job_id)
+        return self._status(job) if job is not None else None
+
+    def pause_job(self, job_id: str) -> Optional[dict]:
+        """Stop scheduling `job_id` until resumed (a run in flight continues);
+        None when there is no such job."""
+        if self.scheduler.get_job(job_id) is None:
+            return None
+        self.scheduler.pause_job(job_id)
+        logger.info(f"[SchedulerControl] Paused {job_id}")
+        return self.get_job(job_id)
+
+    def resume_job(self, job_id: str) -> Optional[dict]:
+        """Schedule `job_id` again from its trigger; None when there is no such job."""
+        if self.scheduler.get_job(job_id) is None:
+            return None
+        self.scheduler.resume_job(job_id)
+        logger.info(f"[SchedulerControl] Resumed {job_id}")
+        return self.get_job(job_id)
+
+    def run_now(self, job_id: str) -> Optional[dict]:
+        """Start a run of `job_id` outside its schedule (the schedule is left
+        as is); None when there is no such job.
+
+        Raises:
+            RuntimeError: a run of the job is already in flight
+        """
+        job = self.scheduler.get_job(job_id)
+        if job is None:
+            return None
+        if running_task(job_id) is not None:
+            raise RuntimeError(f"{job_id} is already running")
+        result = job.func(*job.args, **job.kwargs)
+        if inspect.isawaitable(result):
+            asyncio.ensure_future(result)
+        logger.info(f"[SchedulerControl] Triggered {job_id} now")
+        return self.get_job(job_id)
+
+    def stop_run(self, job_id: str) -> bool:
+        """Cancel the in-flight run of `job_id`; False when none is running."""
+        task = running_task(job_id)
+        if task is None:
+            return False
+        task.cancel()
+        logger.info(f"[SchedulerControl] Cancelled the running {job_id}")
+        return True
+
+    def pause_all(self) -> None:
+        """Hold every job; runs in flight continue."""
+        self.scheduler.pause()
+        logger.info("[SchedulerControl] Scheduler paused")
+
+    def resume_all(self) -> None:
+        self.scheduler.resume()
+        logger.info("[SchedulerControl] Scheduler resumed")
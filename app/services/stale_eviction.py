@@ -0,0 +1,147 @@
+"""Evict venues and live forecasts that stopped being refreshed.
+
+Discovery only ever adds to the catalog: a venue that closed or fell out of
+BestTime's results keeps its geo entry forever. This job ages both out using
+the refresher's own write times:
+
+- venues whose `refreshed_at` (stamped on every catalog / inventory upsert)
+  is older than `venue_max_age_days` are soft-deleted (lifecycle
+  "deprecated", reason "stale_not_refreshed"), which takes them out of the
+  serving view and, through the projector, out of the Redis geo index (a
+  later rediscovery upserts it active again);
+- live forecasts whose `refreshed_at` is older than `live_max_age_minutes`
+  are deleted, so nearby stops showing hours-old "live" busyness.
+
+Records without `refreshed_at` (written before it existed, or added by hand)
+have no known age and are never evicted. A dry run reports what would go
+without changing anything.
+"""
+from __future__ import annotations
+
+import logging
+from dataclasses import dataclass, field
+from datetime import datetime, timedelta, timezone
+from typing import Optional
+
+from app.metrics import STALE_EVICTIONS_TOTAL, VENUES_SOFT_DELETED_TOTAL
+
+logger = logging.getLogger(__name__)
+
+STALE_REASON = "stale_not_refreshed"
+STALE_SOURCE = "stale_eviction"
+# Evicted ids listed in the summary; the counts are always complete.
+MAX_ID_SAMPLES = 50
+
+
+@dataclass
+class StaleEvictionSummary:
+    dry_run: bool
+    started_at: datetime
+    finished_at: Optional[datetime] = None
+    venues_checked: int = 0
+    venues_without_refresh_time: int = 0
+    venues_evicted: int = 0
+    live_checked: int = 0
+    live_deleted: int = 0
+    errors: int = 0
+    evicted_venue_ids: list[str] = field(default_factory=list)
+    deleted_live_ids: list[str] = field(default_factory=list)
+
+
+def _older_than(ts: Optional[datetime], cutoff: datetime) -> bool:
+    if ts is None:
+        return False
+    if ts.tzinfo is None:
+        ts = ts.replace(tzinfo=timezone.utc)
+    return ts < cutoff
+
+
+class StaleEvictionService:
+    """Soft-deletes stale venues and deletes stale live forecasts."""
+
+    def __init__(
+        self,
+        venue_dao,
+        venue_max_age_days: int = 90,
+        live_max_age_minutes: int = 180,
+        dry_run: bool = False,
+    ):
+        """
+        Args:
+            venue_dao: the pipeline DAO (soft deletes go to the source of truth)
+            venue_max_age_days: evict venues not refreshed for this long (0 = keep all)
+            live_max_age_minutes: delete live forecasts older than this (0 = keep all)
+            dry_run: default for runs that do not say; only report
+        """
+        self.venue_dao = venue_dao
+        self.venue_max_age_days = venue_max_age_days
+        self.live_max_age_minutes = live_max_age_minutes
+        self.dry_run = dry_run
+        self.last_summary: Optional[StaleEvictionSummary] = None
+
+    def run(self, dry_run: Optional[bool] = None, now: Optional[datetime] = None) -> StaleEvictionSummary:
+        """Evict what is stale at `now` (default: the current time).
+
+        Blocking (DAO reads and writes); the scheduled job runs it in a thread.
+        """
+        dry_run = self.dry_run if dry_run is None else dry_run
+        now = now or datetime.now(timezone.utc)
+        summary = StaleEvictionSummary(dry_run=dry_run, started_at=now)
+        if self.venue_max_age_days > 0:
+            self._evict_venues(summary, now - timedelta(days=self.venue_max_age_days))
+        if self.live_max_age_minutes > 0:
+            self._evict_live_forecasts(summary, now - timedelta(minutes=self.live_max_age_minutes))
+        summary.finished_at = datetime.now(timezone.utc)
+        self.last_summary = summary
+        logger.info(
+            f"[StaleEviction] {'Dry run: would evict' if dry_run else 'Evicted'} "
+            f"{summary.venues_evicted}/{summary.venues_checked} venues and "
+            f"{summary.live_deleted}/{summary.live_checked} live forecasts "
+            f"({summary.venues_without_refresh_time} venues of unknown age kept, "
+            f"{summary.errors} errors)"
+        )
+        return summary
+
+    def _evict_venues(self, summary: StaleEvictionSummary, cutoff: datetime) -> None:
+        for venue in self.venue_dao.list_all_venues():
+            if venue.is_deprecated():
+                continue
+            summary.venues_checked += 1
+            if venue.refreshed_at is None:
+                summary.venues_without_refresh_time += 1
+                continue
+            if not _older_than(venue.refreshed_at, cutoff):
+                continue
+            if not summary.dry_run:
+                try:
+                    self.venue_dao.soft_delete_venue(
+                        venue_id=venue.venue_id, reason=STALE_REASON, source=STALE_SOURCE,
+                    )
+                except Exception as e:
+                    summary.errors += 1
+                    logger.warning(f"[StaleEviction] Failed to evict venue {venue.venue_id}: {e}")
+                    continue
+                VENUES_SOFT_DELETED_TOTAL.labels(reason=STALE_REASON, source=STALE_SOURCE).inc()
+                STALE_EVICTIONS_TOTAL.labels(kind="venue").inc()
+            summary.venues_evicted += 1
+            if len(summary.evicted_venue_ids) < MAX_ID_SAMPLES:
+                summary.evicted_venue_ids.append(venue.venue_id)
+
+    def _evict_live_forecasts(self, summary: StaleEvictionSummary, cutoff: datetime) -> None:
+        ids = self.venue_dao.list_cached_live_forecast_venue_ids()
+        cached = self.venue_dao.get_live_forecasts_bulk(ids) if ids else {}
+        summary.live_checked = len(cached)
+        for venue_id, forecast in cached.items():
+            if not _older_than(forecast.refreshed_at, cutoff):
+                continue
+            if not summary.dry_run:
+                try:
+                    self.venue_dao.delete_live_forecast(venue_id)
+                except Exception as e:
+                    summary.errors += 1
+                    logger.warning(f"[StaleEviction] Failed to delete live forecast of {venue_id}: {e}")
+                    continue
+                STALE_EVICTIONS_TOTAL.labels(kind="live_forecast").inc()
+            summary.live_deleted += 1
+            if len(summary.deleted_live_ids) < MAX_ID_SAMPLES:
+                summary.deleted_live_ids.append(venue_id)
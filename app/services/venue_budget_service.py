@@ -12,6 +12,8 @@ Concurrency model:
 - Discovery polls `discovery_effective_cap_remaining()` before each
   refresh batch. The reserve guarantees discovery stops short so manual
   adds can still use the last `reserve` slots.
+- Catalog imports reserve all their new venues in one INCRBY, with the
+  same overshoot rollback as manual add (all or nothing).
 """
 from __future__ import annotations
 
@@ -169,6 +171,12 @@ class VenueBudgetService:
         snap = self.get_snapshot()
         return snap.discovery_effective_cap_remaining
 
+    def tenant_cap_remaining(self, tenant_id: str, quota: int) -> int:
+        """New venues tenant_id's discovery may still add this month under its
+        own `quota` (app/tenancy.py); the shared cap applies on top."""
+        count = self.dao.get_month_count(self._year_month_provider(), tenant_id)
+        return max(0, quota - count)
+
     # ----- manual-add side ------------------------------------------------
 
     def can_manual_add(self) -> bool:
@@ -218,11 +226,38 @@ class VenueBudgetService:
         ym = year_month or self._year_month_provider()
         self.dao.decrement_month(ym, 1)
 
+    # ----- import side ---------------------------------------------------
+
+    def reserve_import_slots(self, count: int) -> tuple[bool, int]:
+        """Atomically reserve `count` slots for the new venues of a catalog
+        import (app/services/venue_import.py). Like a manual add, an import
+        may use the manual reserve.
+
+        Returns (granted, slots left); when granted is False nothing was
+        reserved and the slots left are those before the attempt.
+        """
+        settings = self.get_quota_settings()
+        year_month = self._year_month_provider()
+        new_value = self.dao.increment_month(year_month, count)
+        if new_value > settings.monthly_quota:
+            self.dao.decrement_month(year_month, count)
+            return False, max(0, settings.monthly_quota - (new_value - count))
+        return True, settings.monthly_quota - new_value
+
+    def release_import_slots(self, count: int) -> None:
+        """Return slots reserved by reserve_import_slots for venues the
+        import did not write. Clamped at the floor by the DAO."""
+        self.dao.decrement_month(self._year_month_provider(), count)
+
     # ----- discovery counter recording -----------------------------------
 
-    def record_new_venue_from_discovery(self) -> int:
-        """Increment the counter for a new venue discovered via /venues/filter."""
+    def record_new_venue_from_discovery(self, tenant_id: Optional[str] = None) -> int:
+        """Increment the counter for a new venue discovered via /venues/filter,
+        and tenant_id's share of it when discovery ran for a tenant. Returns
+        the shared count."""
         year_month = self._year_month_provider()
+        if tenant_id:
+            self.dao.increment_month(year_month, 1, tenant_id=tenant_id)
         return self.dao.increment_month(year_month, 1)
 
     def release_discovery_slot(self, year_month: Optional[str] = None) -> None:
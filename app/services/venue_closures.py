@@ -0,0 +1,142 @@
+"""Temporary venue closures (renovation, private events, seasonal breaks).
+
+An operator marks a venue closed for an inclusive date range (America/Recife
+calendar days). While a window covers today the venue is left out of
+/v1/venues/nearby and out of every refresh selection (live, weekly, weekend
+prefetch), so no BestTime credits are spent on it; the day after `end` it is
+served and refreshed again with no further action.
+
+Closures are one admin-config document (`venue_closures`: venue_id ->
+{start, end, reason}), RDS-backed with the Redis mirror the serving and
+refresh paths read. Windows that have ended are pruned on the next write.
+"""
+from __future__ import annotations
+
+import json
+import logging
+from datetime import date
+from typing import Any, Optional
+
+from pydantic import BaseModel, Field, ValidationError, model_validator
+
+from app.services.admin_config_service import ADMIN_CONFIG_PREFIX
+from app.utils.recife_time import recife_today
+
+logger = logging.getLogger(__name__)
+
+ADMIN_CONFIG_VENUE_CLOSURES_KEY = "venue_closures"
+
+
+class VenueClosure(BaseModel):
+    start: date
+    end: date  # inclusive
+    reason: Optional[str] = Field(default=None, max_length=200)
+
+    @model_validator(mode="after")
+    def _end_not_before_start(self) -> "VenueClosure":
+        if self.end < self.start:
+            raise ValueError("end must not be before start")
+        return self
+
+    def covers(self, day: date) -> bool:
+        return self.start <= day <= self.end
+
+
+def validate_venue_closures_config(value: Any) -> dict:
+    """Admin-config validator: a {venue_id: VenueClosure} object."""
+    if not isinstance(value, dict):
+        raise TypeError("venue_closures must be an object of venue_id -> closure")
+    out = {}
+    for venue_id, raw in value.items():
+        if not venue_id:
+            raise ValueError("venue_closures keys must be non-empty venue ids")
+        try:
+            out[venue_id] = VenueClosure.model_validate(raw).model_dump(mode="json")
+        except ValidationError as e:
+            raise ValueError(f"invalid closure for {venue_id}: {e}") from e
+    return out
+
+
+def closed_venue_ids(closures: Optional[dict], today: Optional[date] = None) -> set[str]:
+    """Ids whose closure window covers `today` (Recife). Malformed entries are
+    ignored."""
+    today = today or recife_today()
+    closed = set()
+    for venue_id, raw in (closures or {}).items():
+        try:
+            if VenueClosure.model_validate(raw).covers(today):
+                closed.add(venue_id)
+        except ValidationError:
+            continue
+    return closed
+
+
+def load_closed_venue_ids(admin_config_service, today: Optional[date] = None) -> set[str]:
+    """Currently closed ids via the admin-config service. Never raises."""
+    if admin_config_service is None:
+        return set()
+    try:
+        return closed_venue_ids(
+            admin_config_service.get(ADMIN_CONFIG_VENUE_CLOSURES_KEY), today
+        )
+    except Exception as e:
+        logger.warning(f"[VenueClosures] Failed to read closures: {e}")
+        return set()
+
+
+def load_closed_venue_ids_from_redis(redis_client, today: Optional[date] = None) -> set[str]:
+    """Currently closed ids straight from the Redis mirror (for services that
+    hold a raw client). Never raises."""
+    if redis_client is None:
+        return set()
+    try:
+        raw = redis_client.get(f"{ADMIN_CONFIG_PREFIX}{ADMIN_CONFIG_VENUE_CLOSURES_KEY}")
+        return closed_venue_ids(json.loads(raw) if raw else None, today)
+    except Exception as e:
+        logger.warning(f"[VenueClosures] Failed to read closures: {e}")
+        return set()
+
+
+class VenueClosuresService:
+    """Per-venue closure CRUD over the `venue_closures` admin-config document."""
+
+    def __init__(self, admin_config_service, today=recife_today) -> None:
+        self.admin_config_service = admin_config_service
+        self._today = today
+
+    def list_closures(self) -> dict[str, dict]:
+        return self.admin_config_service.get(ADMIN_CONFIG_VENUE_CLOSURES_KEY) or {}
+
+    def _write(self, closures: dict) -> dict:
+        today = self._today()
+        live = {
+            venue_id: c for venue_id, c in closures.items()
+            if date.fromisoformat(c["end"]) >= today
+        }
+        return self.admin_config_service.set(
+            ADMIN_CONFIG_VENUE_CLOSURES_KEY, live, updated_by="admin"
+        )
+
+    def set_closure(
+        self, venue_id: str, start: date, end: date, reason: Optional[str] = None
+    ) -> dict:
+        """Create or replace a venue's closure window; returns it.
+
+        Raises:
+            ValueError: end before start, or a window that has already ended
+        """
+        closure = VenueClosure(start=start, end=end, reason=reason)
+        if closure.end < self._today():
+            raise ValueError("closure window has already ended")
+        closures = self.list_closures()
+        closures[venue_id] = closure.model_dump(mode="json")
+        return self._write(closures)[venue_id]
+
+    def delete_closure(self, venue_id: str) -> bool:
+        """Reopen a venue early; False when it had no closure."""
+        closures = self.list_closures()
+        if venue_id not in closures:
+            return False
+        del closures[venue_id]
+        self._write(closures)
+        return True
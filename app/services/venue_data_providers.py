@@ -0,0 +1,129 @@
+"""Pluggable venue discovery sources.
+
+Catalog discovery asks one `VenueDataProvider` for the venues around each
+location. Providers answer with the BestTime /venues/filter models
+(VenueFilterResponse), so the dedup/upsert/budget path of the refresher is the
+same whichever upstream found the venues. settings.venue_data_provider picks
+the provider:
+
+- "besttime" (default): BestTime /venues/filter, with its one-day foot
+  traffic curve per venue;
+- "google_places": Google Places Nearby Search (New). Venue ids are the Google
+  place id prefixed with GOOGLE_VENUE_ID_PREFIX so they never collide with
+  BestTime ids. The Places API exposes no popular times, so these venues carry
+  no foot-traffic curve and BestTime is not asked for their live/weekly data
+  (see BestTimeCrowdProvider.covers); a CrowdDataProvider that has busyness
+  for them can still serve it.
+
+Live and weekly busyness stay with the CrowdDataProviders
+(app/services/crowd_providers.py).
+"""
+from __future__ import annotations
+
+import logging
+from typing import Optional, Protocol
+
+from app.models import VenueFilterParams, VenueFilterResponse, VenueFilterVenue
+from app.services.price_signal import PRICE_LEVEL_ENUM_TO_INT
+from app.utils.recife_time import recife_now
+
+logger = logging.getLogger(__name__)
+
+VENUE_DATA_PROVIDERS = ("besttime", "google_places")
+
+GOOGLE_VENUE_ID_PREFIX = "gplaces_"
+
+
+class VenueDataProvider(Protocol):
+    """A source of venues around a point."""
+
+    name: str
+
+    async def search_nearby(self, params: VenueFilterParams) -> VenueFilterResponse: ...
+
+
+class BestTimeVenueDataProvider:
+    """BestTime /venues/filter as a VenueDataProvider."""
+
+    name = "besttime"
+
+    def __init__(self, besttime_api) -> None:
+        self.besttime_api = besttime_api
+
+    async def search_nearby(self, params: VenueFilterParams) -> VenueFilterResponse:
+        return await self.besttime_api.venue_filter(params)
+
+
+class GooglePlacesVenueDataProvider:
+    """Google Places Nearby Search as a VenueDataProvider.
+
+    Only the circle (lat/lng/radius) and limit of the params are used; the
+    BestTime busyness/type filters have no Places equivalent, and
+    `included_types` (Google place types) narrows the search instead. One call
+    returns at most 20 places.
+    """
+
+    name = "google_places"
+
+    def __init__(self, google_places_api, included_types: Optional[list[str]] = None) -> None:
+        self.google_places_api = google_places_api
+        self.included_types = list(included_types or [])
+
+    async def search_nearby(self, params: VenueFilterParams) -> VenueFilterResponse:
+        if params.lat is None or params.lng is None or params.radius is None:
+            raise ValueError("Google Places discovery needs lat, lng and radius")
+        places = await self.google_places_api.search_nearby(
+            params.lat,
+            params.lng,
+            params.radius,
+            max_results=params.limit or 20,
+            included_types=self.included_types or None,
+        )
+        day_int = recife_now().weekday()
+        venues = [v for v in (_place_to_venue(p, day_int) for p in places) if v is not None]
+        logger.info(
+            f"[GooglePlacesVenueDataProvider] {len(venues)} places at "
+            f"{params.lat:.4f},{params.lng:.4f} r={params.radius}"
+        )
+        return VenueFilterResponse(status="OK", venues=venues, venues_n=len(venues))
+
+
+def _place_to_venue(place: dict, day_int: int) -> Optional[VenueFilterVenue]:
+    """A Nearby Search place as a filter venue (None without id or location)."""
+    location = place.get("location") or {}
+    lat, lng = location.get("latitude"), location.get("longitude")
+    if not place.get("id") or lat is None or lng is None:
+        return None
+    primary_type = place.get("primaryType")
+    return VenueFilterVenue(
+        day_int=day_int,
+        day_raw=[],
+        venue_id=f"{GOOGLE_VENUE_ID_PREFIX}{place['id']}",
+        venue_name=(place.get("displayName") or {}).get("text") or "",
+        venue_address=place.get("formattedAddress") or "",
+        venue_lat=float(lat),
+        venue_lng=float(lng),
+        venue_type=primary_type.upper() if primary_type else None,
+        price_level=PRICE_LEVEL_ENUM_TO_INT.get(place.get("priceLevel") or ""),
+        rating=place.get("rating"),
+        reviews=place.get("userRatingCount"),
+    )
+
+
+def build_venue_data_provider(
+    name: str, besttime_api, google_places_api=None, google_included_types=None
+) -> VenueDataProvider:
+    """The provider settings.venue_data_provider names.
+
+    Raises:
+        ValueError: unknown name, or google_places without a Places client
+    """
+    if name not in VENUE_DATA_PROVIDERS:
+        raise ValueError(
+            f"unknown venue_data_provider {name!r}; expected one of {VENUE_DATA_PROVIDERS}"
+        )
+    if name == "google_places":
+        if google_places_api is None:
+            raise ValueError("venue_data_provider google_places needs google_places_api_key")
+        return GooglePlacesVenueDataProvider(google_places_api, google_included_types)
+    return BestTimeVenueDataProvider(besttime_api)
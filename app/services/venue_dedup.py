@@ -0,0 +1,275 @@
+"""Merge venues that are the same place under a different id.
+
+Discovery dedupes on exact ids and names within one run only, so "Bar do Zé"
+and "Bar do Ze - Recife" a few meters apart end up as two catalog entries
+across runs. Two venues are taken for the same place when they are within
+`max_distance_m` of each other and their names, folded (accents, case,
+punctuation) and without city words ("Recife", the regions' cities), have a
+similarity of at least `min_similarity`. Unlike the open-data matcher, one
+name merely containing the other is not enough ("Bar" vs "Bar do Zé").
+
+- at upsert time (VenuesRefresherService.set_deduplicator) a venue new to the
+  catalog that matches an existing nearby venue is not written; the refresh
+  carries on with the existing venue instead;
+- the periodic cleanup pass groups the active catalog into clusters of
+  matching venues, keeps the one with the most reviews (the smallest id on
+  ties) and soft-deletes the others (reason "duplicate").
+
+Either way the duplicate's id is recorded as an alias of the kept venue
+(`venue_dedup:aliases`), so a later discovery of the same duplicate skips the
+geo lookup. Before the cleanup pass soft-deletes a duplicate, the reference
+movers (`add_reference_mover`) move what other services keep under its id
+(favorites, hot likes, check-ins, operator notes) to the kept venue; a failed
+move leaves the duplicate active, so the next run tries again. Partner venue
+ids resolve through the alias hash instead (PartnerVenueIds). A venue merged
+at upsert time was never written, so nothing refers to it yet. The nearby
+lookup reads the Redis geo index, so duplicates discovered in the same run
+(not projected yet) are left to the cleanup pass.
+"""
+from __future__ import annotations
+
+import logging
+import math
+from dataclasses import dataclass, field
+from datetime import datetime, timezone
+from difflib import SequenceMatcher
+from typing import Callable, Iterable, Optional
+
+from app.metrics import VENUE_DUPLICATES_MERGED_TOTAL, VENUES_SOFT_DELETED_TOTAL
+from app.models import Venue
+from app.services.instagram_enrichment_service import KNOWN_CITIES
+from app.services.open_data_enrichment_service import normalize_name
+from app.services.venue_eligibility import haversine_km
+
+logger = logging.getLogger(__name__)
+
+DUPLICATE_REASON = "duplicate"
+DEDUP_SOURCE = "venue_dedup"
+# Hash of duplicate venue id -> id of the venue it was merged into.
+ALIASES_KEY = "venue_dedup:aliases"
+# Merged ids listed in the summary; the counts are always complete.
+MAX_ID_SAMPLES = 50
+# Meters per degree of latitude.
+_M_PER_DEG = 111_320.0
+
+
+@dataclass
+class VenueDedupSummary:
+    dry_run: bool
+    started_at: datetime
+    finished_at: Optional[datetime] = None
+    venues_checked: int = 0
+    clusters: int = 0
+    venues_merged: int = 0
+    errors: int = 0
+    # duplicate id -> kept id
+    merged: dict[str, str] = field(default_factory=dict)
+
+
+def dedup_name(name: str, drop_words: Iterable[str] = ()) -> str:
+    """`name` folded, without the words in `drop_words` (already folded)."""
+    drop = set(drop_words)
+    return " ".join(w for w in normalize_name(name).split() if w not in drop)
+
+
+def dedup_similarity(a: str, b: str) -> float:
+    """0..1 similarity of two folded names, ignoring word order."""
+    if not a or not b:
+        return 0.0
+    sa, sb = " ".join(sorted(a.split())), " ".join(sorted(b.split()))
+    return max(SequenceMatcher(None, a, b).ratio(), SequenceMatcher(None, sa, sb).ratio())
+
+
+class VenueDeduplicator:
+    """Finds and merges venues with similar names at (nearly) the same spot."""
+
+    def __init__(
+        self,
+        venue_dao,
+        redis_client,
+        max_distance_m: float = 75.0,
+        min_similarity: float = 0.9,
+        drop_words: Iterable[str] = (),
+        dry_run: bool = False,
+    ):
+        """
+        Args:
+            venue_dao: the pipeline DAO (soft deletes go to the source of truth)
+            redis_client: raw client holding the alias hash
+            max_distance_m: farthest apart two venues can be and still match
+            min_similarity: lowest name similarity (0..1) that matches
+            drop_words: extra words ignored in names (region and city names);
+                KNOWN_CITIES always are
+            dry_run: default for cleanup runs that do not say; only report
+        """
+        self.venue_dao = venue_dao
+        self.redis = redis_client
+        self.max_distance_m = max_distance_m
+        self.min_similarity = min_similarity
+        words = list(KNOWN_CITIES) + list(drop_words)
+        self.drop_words = {w for word in words for w in normalize_name(word).split()}
+        self.dry_run = dry_run
+        self.last_summary: Optional[VenueDedupSummary] = None
+        self.reference_movers: list[Callable[[str, str], None]] = []
+
+    def add_reference_mover(self, mover: Callable[[str, str], None]) -> None:
+        """Register `mover(duplicate_id, kept_id)`, called before a duplicate
+        is soft-deleted. Movers must be idempotent: a run that fails part-way
+        calls them all again."""
+        self.reference_movers.append(mover)
+
+    def _name(self, venue: Venue) -> str:
+        return dedup_name(venue.venue_name, self.drop_words)
+
+    def _matches(self, a: Venue, b: Venue, name_a: str, name_b: str) -> bool:
+        distance_m = haversine_km(a.venue_lat, a.venue_lng, b.venue_lat, b.venue_lng) * 1000
+        return (
+            distance_m <= self.max_distance_m
+            and dedup_similarity(name_a, name_b) >= self.min_similarity
+        )
+
+    def find_duplicate(self, venue: Venue) -> Optional[Venue]:
+        """The active venue with another id that `venue` duplicates, most
+        similar name first, or None."""
+        name = self._name(venue)
+        if not name:
+            return None
+        best, best_score = None, 0.0
+        nearby = self.venue_dao.get_nearby_venues(
+            venue.venue_lat, venue.venue_lng, self.max_distance_m, unit="m"
+        )
+        for other in nearby:
+            if other.venue_id == venue.venue_id or other.is_deprecated():
+                continue
+            other_name = self._name(other)
+            if not self._matches(venue, other, name, other_name):
+                continue
+            score = dedup_similarity(name, other_name)
+            if score > best_score:
+                best, best_score = other, score
+        return best
+
+    def alias_of(self, venue_id: str) -> Optional[str]:
+        """The venue `venue_id` was merged into, if it was."""
+        if not venue_id:
+            return None
+        return self.redis.hget(ALIASES_KEY, venue_id)
+
+    def aliases(self) -> dict[str, str]:
+        return self.redis.hgetall(ALIASES_KEY)
+
+    def canonical_for(self, venue: Venue) -> Optional[str]:
+        """Id of the existing venue a newly discovered `venue` duplicates (and
+        record the alias), or None when it is a venue of its own.
+
+        Lookup errors count as no duplicate: a missed merge is left to the
+        cleanup pass, a wrong skip would lose the venue.
+        """
+        try:
+            canonical = self.alias_of(venue.venue_id)
+            if canonical:
+                return canonical
+            existing = self.find_duplicate(venue)
+        except Exception as e:
+            logger.warning(f"[VenueDedup] Duplicate lookup failed for {venue.venue_id}: {e}")
+            return None
+        if existing is None:
+            return None
+        self._record_alias(venue.venue_id, existing.venue_id)
+        VENUE_DUPLICATES_MERGED_TOTAL.labels(stage="upsert").inc()
+        logger.info(
+            f"[VenueDedup] {venue.venue_id} ({venue.venue_name!r}) duplicates "
+            f"{existing.venue_id} ({existing.venue_name!r})"
+        )
+        return existing.venue_id
+
+    def _record_alias(self, duplicate_id: str, canonical_id: str) -> None:
+        # Re-point what was merged into the duplicate, so aliases stay one hop.
+        for alias, target in self.aliases().items():
+            if target == duplicate_id:
+                self.redis.hset(ALIASES_KEY, alias, canonical_id)
+        self.redis.hset(ALIASES_KEY, duplicate_id, canonical_id)
+
+    def run(self, dry_run: Optional[bool] = None) -> VenueDedupSummary:
+        """Merge every cluster of duplicates in the active catalog.
+
+        Blocking (DAO reads and writes); the scheduled job runs it in a thread.
+        """
+        dry_run = self.dry_run if dry_run is None else dry_run
+        summary = VenueDedupSummary(dry_run=dry_run, started_at=datetime.now(timezone.utc))
+        venues = [v for v in self.venue_dao.list_all_venues() if v.is_active() and v.venue_id]
+        summary.venues_checked = len(venues)
+        for cluster in self._clusters(venues):
+            summary.clusters += 1
+            keep = min(cluster, key=lambda v: (-(v.reviews or 0), v.venue_id))
+            for venue in cluster:
+                if venue is keep:
+                    continue
+                if not dry_run:
+                    try:
+                        for mover in self.reference_movers:
+                            mover(venue.venue_id, keep.venue_id)
+                        self.venue_dao.soft_delete_venue(
+                            venue_id=venue.venue_id, reason=DUPLICATE_REASON, source=DEDUP_SOURCE,
+                        )
+                        self._record_alias(venue.venue_id, keep.venue_id)
+                    except Exception as e:
+                        summary.errors += 1
+                        logger.warning(f"[VenueDedup] Failed to merge {venue.venue_id}: {e}")
+                        continue
+                    VENUES_SOFT_DELETED_TOTAL.labels(reason=DUPLICATE_REASON, source=DEDUP_SOURCE).inc()
+                    VENUE_DUPLICATES_MERGED_TOTAL.labels(stage="cleanup").inc()
+                summary.venues_merged += 1
+                if len(summary.merged) < MAX_ID_SAMPLES:
+                    summary.merged[venue.venue_id] = keep.venue_id
+        summary.finished_at = datetime.now(timezone.utc)
+        self.last_summary = summary
+        logger.info(
+            f"[VenueDedup] {'Dry run: would merge' if dry_run else 'Merged'} "
+            f"{summary.venues_merged} of {summary.venues_checked} venues in "
+            f"{summary.clusters} clusters ({summary.errors} errors)"
+        )
+        return summary
+
+    def _clusters(self, venues: list[Venue]) -> list[list[Venue]]:
+        """Groups of two or more venues linked by matches (transitively).
+
+        Venues are bucketed into grid cells of max_distance_m, so each one is
+        only compared with those in its own and the 8 neighbouring cells.
+        """
+        cell_deg = max(self.max_distance_m, 1.0) / _M_PER_DEG
+        names = [self._name(v) for v in venues]
+        cells: dict[tuple[int, int], list[int]] = {}
+        for i, v in enumerate(venues):
+            lng_scale = max(math.cos(math.radians(v.venue_lat)), 1e-6)
+            cell = (math.floor(v.venue_lat / cell_deg), math.floor(v.venue_lng * lng_scale / cell_deg))
+            cells.setdefault(cell, []).append(i)
+
+        parent = list(range(len(venues)))
+
+        def find(i: int) -> int:
+            while parent[i] != i:
+                parent[i] = parent[parent[i]]
+                i = parent[i]
+            return i
+
+        for (row, col), members in cells.items():
+            neighbours = [
+                j
+                for dr in (-1, 0, 1)
+                for dc in (-1, 0, 1)
+                for j in cells.get((row + dr, col + dc), [])
+            ]
+            for i in members:
+                if not names[i]:
+                    continue
+                for j in neighbours:
+                    if j <= i or not names[j]:
+                        continue
+                    if self._matches(venues[i], venues[j], names[i], names[j]):
+                        parent[find(i)] = find(j)
+
+        groups: dict[int, list[Venue]] = {}
+        for i, v in enumerate(venues):
+            groups.setdefault(find(i), []).append(v)
+        return [g for g in groups.values() if len(g) > 1]
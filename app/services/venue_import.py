@@ -0,0 +1,212 @@
+"""Bulk import of an external venue catalog (POST /admin/venues/import).
+
+Loads a curated venue list from a partner without BestTime discovery. The
+payload is either
+
+- JSON in the seed fixture shape (app/services/fixture_seed.py): a list of
+  venues, or an object with `venues` and optionally `live_forecasts` and
+  `weekly_forecasts`; or
+- CSV with a header row naming Venue fields (CSV_COLUMNS), one venue per row.
+  Empty cells are left unset; `venue_lon` is read as `venue_lng`. CSV carries
+  venues only.
+
+Every entry is validated before anything is written, with the catalog's
+venue check (app/services/venue_validation.py: an id, a name, non-zero
+coordinates in range) plus a unique id per import. Any problem rejects the
+whole import with every message listed, so a partner can fix the file in one
+go; out-of-bounds optional fields (rating, price level, ...) are dropped on
+write as for every other writer. Valid imports go through the pipeline DAO's
+bulk upsert (RDS, projected to Redis by the next projector run); a row the
+store refuses is reported without stopping the rest.
+
+Venues not yet in the catalog count against the monthly new-venue budget
+(app/services/venue_budget_service.py) like any other addition: the import
+reserves them all up front and is refused when they do not fit.
+"""
+from __future__ import annotations
+
+import csv
+import io
+import json
+import logging
+from datetime import datetime, timezone
+from typing import Optional
+
+from pydantic import ValidationError
+
+from app.dao.change_events import VENUE_UPSERTED
+from app.metrics import VENUE_MONTHLY_NEW_COUNT
+from app.models import Venue
+from app.services.event_publishing import make_event
+from app.services.fixture_seed import SeedFixture, parse_fixture, parse_venue
+from app.services.venue_validation import venue_problems
+
+logger = logging.getLogger(__name__)
+
+MAX_VENUES = 5000
+MAX_ERRORS = 100
+CSV_COLUMNS = (
+    "venue_id", "venue_name", "venue_address", "venue_lat", "venue_lng",
+    "venue_type", "price_level", "rating", "reviews", "priority",
+)
+
+
+class VenueImportError(ValueError):
+    """The payload cannot be imported; `errors` lists every problem found."""
+
+    def __init__(self, errors: list[str]):
+        self.errors = errors[:MAX_ERRORS]
+        super().__init__("; ".join(self.errors))
+
+
+class VenueImportBudgetError(VenueImportError):
+    """The import adds more new venues than this month's budget has left."""
+
+
+def _csv_venues(text: str, errors: list[str]) -> list[Venue]:
+    reader = csv.DictReader(io.StringIO(text))
+    columns = [c.strip() for c in reader.fieldnames or []]
+    unknown = [c for c in columns if c not in CSV_COLUMNS and c != "venue_lon"]
+    if unknown:
+        raise VenueImportError([
+            f"unknown CSV columns {', '.join(unknown)}; expected {', '.join(CSV_COLUMNS)}"
+        ])
+    venues = []
+    for index, row in enumerate(reader):
+        raw = {k.strip(): v.strip() for k, v in row.items() if k and v and v.strip()}
+        try:
+            venues.append(parse_venue(raw))
+        except ValidationError as e:
+            errors.append(f"venues[{index}]: {e}")
+    return venues
+
+
+def _check_venues(venues: list[Venue], errors: list[str]) -> None:
+    seen = set()
+    for venue in venues:
+        label = venue.venue_id or repr(venue.venue_name)
+        rejects, _ = venue_problems(venue)
+        errors.extend(f"venue {label}: {problem}" for problem in rejects)
+        if venue.venue_id and venue.venue_id in seen:
+            errors.append(f"venue {label}: duplicate venue_id")
+        seen.add(venue.venue_id)
+
+
+def parse_import(body: bytes, content_type: str = "") -> SeedFixture:
+    """Decode and validate an import payload; CSV when the content type says so.
+
+    Raises:
+        VenueImportError: anything that would stop the import
+    """
+    try:
+        text = body.decode("utf-8-sig")
+    except UnicodeDecodeError:
+        raise VenueImportError(["payload is not UTF-8"]) from None
+    errors: list[str] = []
+    if "csv" in content_type.lower():
+        fixture = SeedFixture(_csv_venues(text, errors), [], [])
+    else:
+        try:
+            data = json.loads(text)
+        except json.JSONDecodeError as e:
+            raise VenueImportError([f"payload is not valid JSON: {e}"]) from None
+        try:
+            fixture = parse_fixture(data, errors)
+        except ValueError as e:
+            raise VenueImportError([str(e)]) from None
+    if not fixture.venues and not errors:
+        errors.append("no venues to import")
+    if len(fixture.venues) > MAX_VENUES:
+        errors.append(f"at most {MAX_VENUES} venues per import, got {len(fixture.venues)}")
+    _check_venues(fixture.venues, errors)
+    if errors:
+        raise VenueImportError(errors)
+    return fixture
+
+
+def import_venues(
+    venue_dao,
+    fixture: SeedFixture,
+    event_outbox=None,
+    now: Optional[datetime] = None,
+    budget_service=None,
+) -> dict:
+    """Bulk-upsert a validated import, then its forecasts; returns the counts.
+
+    Forecasts are written only for venues this import wrote. When an
+    EventOutbox is given, each written venue is recorded as venue_upserted.
+    When a VenueBudgetService is given, the venues new to the catalog are
+    charged to this month's budget.
+
+    Raises:
+        VenueImportBudgetError: the new venues do not fit the budget (nothing
+            is written)
+    """
+    now = now or datetime.now(timezone.utc)
+    new_ids = {v.venue_id for v in fixture.venues if venue_dao.get_venue(v.venue_id) is None}
+    if budget_service is not None and new_ids:
+        granted, left = budget_service.reserve_import_slots(len(new_ids))
+        if not granted:
+            raise VenueImportBudgetError([
+                f"import adds {len(new_ids)} new venues but only {left} remain "
+                f"in this month's new-venue budget"
+            ])
+    for venue in fixture.venues:
+        venue.refreshed_at = now
+    failed: dict[str, Exception] = {}
+    try:
+        written = venue_dao.upsert_venues(fixture.venues, errors=failed)
+    except Exception:
+        if budget_service is not None and new_ids:
+            budget_service.release_import_slots(len(new_ids))
+        raise
+    imported = {v.venue_id for v in fixture.venues} - set(failed)
+    new_venues = len(new_ids & imported)
+    if budget_service is not None and new_ids:
+        if new_venues < len(new_ids):
+            budget_service.release_import_slots(len(new_ids) - new_venues)
+        VENUE_MONTHLY_NEW_COUNT.set(budget_service.get_snapshot().month_counter)
+
+    live = 0
+    for forecast in fixture.live_forecasts:
+        if forecast.venue_info.venue_id not in imported:
+            continue
+        if forecast.refreshed_at is None:
+            forecast.refreshed_at = now
+        try:
+            if venue_dao.set_live_forecast(forecast) is not False:
+                live += 1
+        except Exception as e:
+            failed[forecast.venue_info.venue_id] = e
+    weekly = 0
+    for venue_id, day in fixture.weekly_forecasts:
+        if venue_id not in imported:
+            continue
+        try:
+            venue_dao.set_week_raw_forecast(venue_id, day)
+            weekly += 1
+        except Exception as e:
+            failed[venue_id] = e
+
+    if event_outbox is not None:
+        for venue in fixture.venues:
+            if venue.venue_id in imported:
+                try:
+                    event_outbox.add(make_event(
+                        VENUE_UPSERTED, venue.venue_id, venue.model_dump(mode="json", by_alias=True)
+                    ))
+                except Exception as e:
+                    logger.warning(f"[VenueImport] Failed to record event for {venue.venue_id}: {e}")
+
+    summary = {
+        "venues": written,
+        "new_venues": new_venues,
+        "live_forecasts": live,
+        "weekly_forecasts": weekly,
+        "failed": {venue_id: str(e) for venue_id, e in failed.items()},
+    }
+    logger.info(
+        f"[VenueImport] Imported {written}/{len(fixture.venues)} venues ({new_venues} new), "
+        f"{live} live forecasts, {weekly} weekly forecast days; {len(failed)} failed"
+    )
+    return summary
@@ -0,0 +1,278 @@
+"""Venue photos, website and social links (Venue.media).
+
+The client app shows imagery and "visit" links the rest of the venue model
+has no room for. Each venue carries at most one VenueMedia document, set
+either by
+
+- the media provider (settings.venue_media_provider), run by the
+  venue_media_enrichment job over servable venues without media:
+  - "enrichment" (default): assembled from data this server already
+    enriches, with no external calls: the open-data photos and website (a
+    website that is really a social profile becomes that social link) and the
+    Instagram profile found by the Instagram enrichment. Open-data photo URLs
+    (OSM image and Wikimedia Commons tags, the Foursquare CDN) do not expire;
+    Google photo URLs rotate and are already served as venue_photos, so they
+    are not copied here;
+  - "none": media is curated by operators only;
+- or an operator, via PUT /admin/venues/{venue_id}/media. Curated media
+  (source "admin") is never overwritten by the provider;
+  DELETE /admin/venues/{venue_id}/media hands the venue back to it.
+
+The document lives on the venue (like Venue.open_data), so the discovery
+refresh keeps it across re-upserts and GET /v1/venues/{venue_id}/media and
+the nearby responses (settings.nearby_media_enabled) serve it without extra
+reads.
+"""
+from __future__ import annotations
+
+import asyncio
+import logging
+from datetime import datetime, timezone
+from typing import Optional, Protocol
+from urllib.parse import urlparse
+
+from app.metrics import VENUE_MEDIA_ENRICHMENT_RESULTS
+from app.models import Venue, VenueMedia
+
+logger = logging.getLogger(__name__)
+
+VENUE_MEDIA_PROVIDERS = ("enrichment", "none")
+
+ADMIN_SOURCE = "admin"
+
+# Social network -> the hosts its profile URLs live on.
+SOCIAL_NETWORK_HOSTS = {
+    "instagram": ("instagram.com", "instagr.am"),
+    "facebook": ("facebook.com", "fb.com"),
+    "tiktok": ("tiktok.com",),
+    "x": ("x.com", "twitter.com"),
+    "youtube": ("youtube.com", "youtu.be"),
+}
+
+# Pause between venues; the "enrichment" provider only reads Redis, but a
+# provider calling out should stay polite.
+REQUEST_DELAY = 0.1
+
+
+def _host(url: str) -> str:
+    host = (urlparse(url).hostname or "").lower()
+    return host[4:] if host.startswith("www.") else host
+
+
+def social_network_for(url: Optional[str]) -> Optional[str]:
+    """The network a profile URL belongs to ("instagram", ...), None for any
+    other site."""
+    if not url:
+        return None
+    host = _host(url)
+    for network, hosts in SOCIAL_NETWORK_HOSTS.items():
+        if any(host == h or host.endswith(f".{h}") for h in hosts):
+            return network
+    return None
+
+
+def _is_http_url(url: str) -> bool:
+    parsed = urlparse(url)
+    return parsed.scheme in ("http", "https") and bool(parsed.hostname)
+
+
+def media_errors(
+    photos: list[str], website: Optional[str], social_links: dict[str, str], max_photos: int
+) -> list[str]:
+    """Problems with an operator's media document, one message each (empty = OK)."""
+    errors = []
+    if len(photos) > max_photos:
+        errors.append(f"at most {max_photos} photos")
+    errors += [f"photo {url!r} is not an http(s) URL" for url in photos if not _is_http_url(url)]
+    if website is not None and not _is_http_url(website):
+        errors.append(f"website {website!r} is not an http(s) URL")
+    for network, url in social_links.items():
+        if network not in SOCIAL_NETWORK_HOSTS:
+            errors.append(
+                f"unknown social network {network!r}; expected one of "
+                f"{', '.join(SOCIAL_NETWORK_HOSTS)}"
+            )
+        elif not _is_http_url(url):
+            errors.append(f"{network} link {url!r} is not an http(s) URL")
+    return errors
+
+
+class VenueMediaProvider(Protocol):
+    """A source of venue media."""
+
+    name: str
+
+    async def fetch(self, venue: Venue) -> Optional[VenueMedia]: ...
+
+
+class EnrichmentMediaProvider:
+    """Media from the open-data and Instagram enrichments (see module docstring)."""
+
+    name = "enrichment"
+
+    def __init__(self, venue_dao, max_photos: int = 10) -> None:
+        self.venue_dao = venue_dao
+        self.max_photos = max_photos
+
+    async def fetch(self, venue: Venue) -> Optional[VenueMedia]:
+        photos = venue.open_data.photos[:self.max_photos] if venue.open_data else []
+        website = venue.open_data.website if venue.open_data else None
+        social_links = {}
+        network = social_network_for(website)
+        if network is not None:
+            social_links[network] = website
+            website = None
+        instagram = self.venue_dao.get_venue_instagram(venue.venue_id)
+        if instagram is not None and instagram.has_instagram() and instagram.instagram_url:
+            social_links["instagram"] = instagram.instagram_url
+        media = VenueMedia(
+            photos=photos,
+            website=website,
+            social_links=social_links,
+            source=self.name,
+            updated_at=datetime.now(timezone.utc),
+        )
+        return None if media.is_empty() else media
+
+
+def build_media_provider(
+    name: str, venue_dao, max_photos: int = 10
+) -> Optional[VenueMediaProvider]:
+    """The provider settings.venue_media_provider names (None for "none").
+
+    Raises:
+        ValueError: unknown name
+    """
+    if name not in VENUE_MEDIA_PROVIDERS:
+        raise ValueError(
+            f"unknown venue_media_provider {name!r}; expected one of {VENUE_MEDIA_PROVIDERS}"
+        )
+    if name == "enrichment":
+        return EnrichmentMediaProvider(venue_dao, max_photos=max_photos)
+    return None
+
+
+class VenueMediaService:
+    """Operator edits and provider enrichment of Venue.media."""
+
+    def __init__(
+        self,
+        venue_dao,
+        provider: Optional[VenueMediaProvider] = None,
+        enrichment_limit: int = 0,
+        max_photos: int = 10,
+    ):
+        """
+        Args:
+            venue_dao: pipeline venue DAO (get_venue / upsert_venue)
+            provider: media source for enrich_all_venues (None = operators only)
+            enrichment_limit: max venues fetched per run (0 = unlimited)
+            max_photos: most photos an operator may set on a venue
+        """
+        self.venue_dao = venue_dao
+        self.provider = provider
+        self.enrichment_limit = enrichment_limit
+        self.max_photos = max_photos
+
+    def get_media(self, venue_id: str) -> tuple[bool, Optional[VenueMedia]]:
+        """(venue known, its media)."""
+        venue = self.venue_dao.get_venue(venue_id)
+        if venue is None:
+            return False, None
+        return True, venue.media
+
+    def set_media(
+        self,
+        venue_id: str,
+        photos: list[str],
+        website: Optional[str] = None,
+        social_links: Optional[dict[str, str]] = None,
+    ) -> Optional[VenueMedia]:
+        """Replace a venue's media with an operator-curated document; None
+        when the venue is unknown.
+
+        Raises:
+            ValueError: invalid URLs, networks or too many photos
+        """
+        social_links = social_links or {}
+        errors = media_errors(photos, website, social_links, self.max_photos)
+        if errors:
+            raise ValueError("; ".join(errors))
+        venue = self.venue_dao.get_venue(venue_id)
+        if venue is None:
+            return None
+        venue.media = VenueMedia(
+            photos=photos,
+            website=website,
+            social_links=social_links,
+            source=ADMIN_SOURCE,
+            updated_at=datetime.now(timezone.utc),
+        )
+        self.venue_dao.upsert_venue(venue)
+        logger.info(f"[VenueMedia] Media for {venue_id} set by an operator")
+        return venue.media
+
+    def clear_media(self, venue_id: str) -> bool:
+        """Drop a venue's media (the next enrichment run may fill it again);
+        False when the venue is unknown."""
+        venue = self.venue_dao.get_venue(venue_id)
+        if venue is None:
+            return False
+        if venue.media is not None:
+            venue.media = None
+            self.venue_dao.upsert_venue(venue)
+            logger.info(f"[VenueMedia] Media for {venue_id} cleared")
+        return True
+
+    async def enrich_all_venues(self, force_refresh: bool = False) -> dict:
+        """Fetch media for every servable venue without any.
+
+        Args:
+            force_refresh: also re-fetch provider media (curated media is
+                always kept)
+
+        Returns:
+            Counts of enriched / no_media / error / skipped venues
+        """
+        summary = {"enriched": 0, "no_media": 0, "error": 0, "skipped": 0}
+        if self.provider is None:
+            logger.info("[VenueMedia] No media provider configured; nothing to enrich")
+            return summary
+        venue_ids = self.venue_dao.list_servable_venue_ids()
+        logger.info(
+            f"[VenueMedia] Starting for {len(venue_ids)} venues "
+            f"(provider={self.provider.name}, force_refresh={force_refresh})"
+        )
+        fetched = 0
+        for venue_id in venue_ids:
+            venue = self.venue_dao.get_venue(venue_id)
+            if venue is None or (
+                venue.media is not None
+                and (venue.media.source == ADMIN_SOURCE or not force_refresh)
+            ):
+                summary["skipped"] += 1
+                VENUE_MEDIA_ENRICHMENT_RESULTS.labels(result="skipped").inc()
+                continue
+            if self.enrichment_limit > 0 and fetched >= self.enrichment_limit:
+                logger.info(f"[VenueMedia] Reached enrichment limit ({self.enrichment_limit})")
+                break
+            fetched += 1
+            try:
+                media = await self.provider.fetch(venue)
+            except Exception as e:
+                logger.warning(f"[VenueMedia] {self.provider.name} failed for {venue_id}: {e}")
+                summary["error"] += 1
+                VENUE_MEDIA_ENRICHMENT_RESULTS.labels(result="error").inc()
+                continue
+            if media is None:
+                summary["no_media"] += 1
+                VENUE_MEDIA_ENRICHMENT_RESULTS.labels(result="no_media").inc()
+            else:
+                venue.media = media
+                self.venue_dao.upsert_venue(venue)
+                summary["enriched"] += 1
+                VENUE_MEDIA_ENRICHMENT_RESULTS.labels(result="enriched").inc()
+            await asyncio.sleep(REQUEST_DELAY)
+
+        logger.info(f"[VenueMedia] Done: {summary}")
+        return summary
@@ -0,0 +1,142 @@
+"""Operator notes per venue ("BestTime data unreliable here", "closed for
+renovation until July").
+
+Notes are one admin-config document (`venue_notes`: venue_id -> note), so
+they live in RDS with the usual Redis mirror and survive every refresh and
+projection (the venue document never carries them). Every edit goes through
+AdminConfigService.update, so concurrent edits of different venues' notes
+do not overwrite each other. Admin views list them;
+a note marked `public` is also served as `status_note` on the venue in
+/v1/venues/nearby responses.
+"""
+from __future__ import annotations
+
+import logging
+from datetime import datetime, timezone
+from typing import Any, Optional
+
+from pydantic import BaseModel, Field, ValidationError
+
+logger = logging.getLogger(__name__)
+
+ADMIN_CONFIG_VENUE_NOTES_KEY = "venue_notes"
+MAX_NOTE_LENGTH = 500
+
+
+class VenueNote(BaseModel):
+    note: str = Field(..., min_length=1, max_length=MAX_NOTE_LENGTH)
+    public: bool = False
+    updated_at: Optional[str] = None
+    updated_by: Optional[str] = None
+
+
+def validate_venue_notes_config(value: Any) -> dict:
+    """Admin-config validator: a {venue_id: VenueNote} object."""
+    if not isinstance(value, dict):
+        raise TypeError("venue_notes must be an object of venue_id -> note")
+    out = {}
+    for venue_id, raw in value.items():
+        if not venue_id:
+            raise ValueError("venue_notes keys must be non-empty venue ids")
+        try:
+            out[venue_id] = VenueNote.model_validate(raw).model_dump()
+        except ValidationError as e:
+            raise ValueError(f"invalid note for {venue_id}: {e}") from e
+    return out
+
+
+def load_public_status_notes(admin_config_service) -> dict[str, str]:
+    """venue_id -> note text for the public notes (one mirror read). Never
+    raises: serving must not fail over notes."""
+    if admin_config_service is None:
+        return {}
+    try:
+        notes = admin_config_service.get(ADMIN_CONFIG_VENUE_NOTES_KEY) or {}
+        return {
+            venue_id: note["note"]
+            for venue_id, note in notes.items()
+            if note.get("public") and note.get("note")
+        }
+    except Exception as e:
+        logger.warning(f"[VenueNotes] Failed to read public notes: {e}")
+        return {}
+
+
+class VenueNotesService:
+    """Per-venue note CRUD over the `venue_notes` admin-config document."""
+
+    def __init__(self, admin_config_service, clock=lambda: datetime.now(timezone.utc)) -> None:
+        self.admin_config_service = admin_config_service
+        self._clock = clock
+
+    def list_notes(self) -> dict[str, dict]:
+        return self.admin_config_service.get(ADMIN_CONFIG_VENUE_NOTES_KEY) or {}
+
+    def get_note(self, venue_id: str) -> Optional[dict]:
+        return self.list_notes().get(venue_id)
+
+    def set_note(
+        self,
+        venue_id: str,
+        note: str,
+        public: bool = False,
+        updated_by: Optional[str] = None,
+        verify: bool = False,
+    ) -> dict:
+        """Create or replace a venue's note; returns the stored note. ``verify``
+        reads the document back (AdminConfigService.verify) before returning.
+
+        Raises:
+            ValueError: empty or over-long note
+        """
+        entry = {
+            "note": note,
+            "public": public,
+            "updated_at": self._clock().isoformat(),
+            "updated_by": updated_by,
+        }
+
+        def change(notes):
+            notes = notes or {}
+            notes[venue_id] = entry
+            return notes
+
+        stored = self.admin_config_service.update(
+            ADMIN_CONFIG_VENUE_NOTES_KEY, change, updated_by=updated_by, verify=verify
+        )
+        return stored[venue_id]
+
+    def move_venue(self, from_venue_id: str, to_venue_id: str) -> None:
+        """Hand `from_venue_id`'s note to `to_venue_id` (a dedup merge); a note
+        the kept venue already has wins."""
+        if from_venue_id not in self.list_notes():
+            return
+
+        def change(notes):
+            notes = notes or {}
+            note = notes.pop(from_venue_id, None)
+            if note is not None:
+                notes.setdefault(to_venue_id, note)
+            return notes
+
+        self.admin_config_service.update(
+            ADMIN_CONFIG_VENUE_NOTES_KEY, change, updated_by="venue_dedup"
+        )
+
+    def delete_note(self, venue_id: str, verify: bool = False) -> bool:
+        """Remove a venue's note; False when it had none. ``verify`` as in
+        `set_note`."""
+        if venue_id not in self.list_notes():
+            return False
+        removed = False
+
+        def change(notes):
+            nonlocal removed
+            notes = notes or {}
+            removed = notes.pop(venue_id, None) is not None
+            return notes
+
+        self.admin_config_service.update(
+            ADMIN_CONFIG_VENUE_NOTES_KEY, change, updated_by="admin", verify=verify
+        )
+        return removed
@@ -0,0 +1,64 @@
+"""Estimated headcount from busyness.
+
+BestTime busyness is relative: 100 is the venue's own busiest hour, so "60%"
+at a ten-seat bar and at an arena mean very different crowds. For clients that
+want an absolute number, a venue's estimated occupancy is
+
+    busyness / 100 * capacity * dwell factor
+
+- capacity: the people the venue holds at its busiest hour. A known per-venue
+  figure (settings.area_capacity_hints, shared with the area crowd index) wins;
+  otherwise settings.occupancy_capacity_by_type for the BestTime venue_type,
+  else occupancy_default_capacity;
+- dwell factor: the type capacities assume visitors stay at least
+  occupancy_reference_dwell_minutes. People inside = arrivals per hour x hours
+  stayed (Little's law), so where BestTime reports a shorter average dwell
+  (VenueDwellTimeMin/Max) the same traffic fills the venue proportionally
+  less. Longer stays do not push the estimate past capacity; no dwell data
+  means factor 1.
+
+This is an order-of-magnitude figure, not a count: it is only as good as the
+configured capacities.
+"""
+from __future__ import annotations
+
+from typing import Optional
+
+from app.config import settings
+from app.models import Venue
+
+
+def venue_capacity(venue: Venue) -> int:
+    """People `venue` holds at its busiest hour (module docstring)."""
+    hint = settings.area_capacity_hints.get(venue.venue_id)
+    if hint:
+        return hint
+    return settings.occupancy_capacity_by_type.get(
+        (venue.venue_type or "").upper(), settings.occupancy_default_capacity
+    )
+
+
+def average_dwell_minutes(venue: Venue) -> Optional[float]:
+    """Midpoint of BestTime's dwell range; one bound alone when only one is
+    known; None without dwell data."""
+    bounds = [m for m in (venue.venue_dwell_time_min, venue.venue_dwell_time_max) if m]
+    if not bounds:
+        return None
+    return sum(bounds) / len(bounds)
+
+
+def dwell_factor(venue: Venue) -> float:
+    """Share of capacity a full hour of traffic keeps inside (module docstring)."""
+    dwell = average_dwell_minutes(venue)
+    if dwell is None:
+        return 1.0
+    return min(1.0, dwell / settings.occupancy_reference_dwell_minutes)
+
+
+def estimate_occupancy(venue: Venue, busyness: Optional[int]) -> Optional[int]:
+    """Estimated people at `venue` at `busyness` (0-100); None when the
+    busyness is unknown."""
+    if busyness is None:
+        return None
+    busyness = max(0, min(100, busyness))
+    return round(busyness / 100 * venue_capacity(venue) * dwell_factor(venue))
@@ -0,0 +1,96 @@
+"""Is a venue open right now, judging by the BestTime hours we already store?
+
+The weekly refresh caches each venue's days (WeekRawDay) with BestTime's
+`venue_open_close_v2` windows. The live refresh uses them to skip venues that
+are closed at refresh time: a closed venue has no live busyness to fetch, and
+asking costs a credit anyway.
+
+The answer is deliberately conservative. A venue counts as closed only when
+both today's and yesterday's hours are known (yesterday's may run past
+midnight) and no window, widened by a margin on both sides, covers the current
+minute. Anything unknown answers None, and the caller fetches as before.
+
+Nearby responses carry the same answer as `open_now`, judged in each venue's
+own timezone (BestTime's venue_timezone from its live forecast; Recife when
+unknown) with no margin, and `open_now=true` keeps only venues known to be
+open.
+"""
+from __future__ import annotations
+
+from datetime import datetime
+from typing import Callable, Optional
+
+from app.models.venue import MINUTES_PER_DAY
+from app.models.week_raw import WeekRawDay
+from app.utils.venue_time import venue_local_time
+
+
+def _day_windows(day: Optional[WeekRawDay]) -> Optional[list[tuple[int, int]]]:
+    """DayInfo.open_windows of a stored day; None when unknown."""
+    if day is None or day.day_info is None:
+        return None
+    return day.day_info.open_windows(day.day_raw)
+
+
+def open_at(
+    today: Optional[WeekRawDay],
+    yesterday: Optional[WeekRawDay],
+    minute_of_day: int,
+    margin_minutes: int = 0,
+) -> Optional[bool]:
+    """Whether the venue is open at `minute_of_day` (local time) today.
+
+    Args:
+        today: the venue's stored day for today's BestTime day_int
+        yesterday: the stored day before it
+        minute_of_day: minutes since local midnight
+        margin_minutes: treat the venue as open this long before opening and
+            after closing
+
+    Returns:
+        True / False, or None when the stored hours cannot tell
+    """
+    today_windows = _day_windows(today)
+    yesterday_windows = _day_windows(yesterday)
+    if today_windows is None or yesterday_windows is None:
+        return None
+    for opens, closes in today_windows:
+        if opens - margin_minutes <= minute_of_day <= closes + margin_minutes:
+            return True
+    shifted = minute_of_day + MINUTES_PER_DAY
+    for opens, closes in yesterday_windows:
+        if opens - margin_minutes <= shifted <= closes + margin_minutes:
+            return True
+    return False
+
+
+def open_now_bulk(
+    timezones: dict[str, Optional[str]],
+    now_utc: datetime,
+    load_day: Callable[[list[str], int], dict[str, WeekRawDay]],
+) -> dict[str, Optional[bool]]:
+    """open_at for every venue at `now_utc` in its own timezone.
+
+    Args:
+        timezones: venue id -> timezone name (None = Recife)
+        now_utc: the instant to judge
+        load_day: (venue ids, day_int) -> stored days, e.g. the DAO's
+            get_week_raw_forecasts_bulk; called once per distinct day needed
+
+    Returns:
+        venue id -> True / False / None (unknown)
+    """
+    local_times = {vid: venue_local_time(tz, now_utc) for vid, tz in timezones.items()}
+    wanted: dict[int, list[str]] = {}
+    for vid, local in local_times.items():
+        # BestTime day_int and datetime.weekday() both count 0=Monday.
+        wanted.setdefault(local.weekday(), []).append(vid)
+        wanted.setdefault((local.weekday() - 1) % 7, []).append(vid)
+    days = {day_int: load_day(ids, day_int) for day_int, ids in wanted.items()}
+    result = {}
+    for vid, local in local_times.items():
+        today = local.weekday()
+        result[vid] = open_at(
+            days[today].get(vid), days[(today - 1) % 7].get(vid), local.hour * 60 + local.minute
+        )
+    return result
@@ -0,0 +1,63 @@
+"""Nearby venue score: the "best places right now" ordering of
+GET /v1/venues/nearby?sort=score.
+
+A venue's score (0-100) is the weighted mean of five signals, each scaled to
+0-1, with weights from settings.score_weights:
+
+- busyness: fresh live busyness / 100 (livelier is better right now);
+- rating: Google rating / 5;
+- reviews: log-scaled review count, reaching 1 at
+  settings.score_reviews_saturation (many reviews make a rating trustworthy);
+- price: cheaper is better, tier 1 -> 1 and tier 4 -> 0;
+- distance: 1 at the search point, 0 at the edge of the search radius.
+
+A signal the venue has no value for (no fresh live data, no rating, unknown
+price tier) contributes 0 while its weight still counts, so a venue is not
+ranked up for missing data.
+"""
+from __future__ import annotations
+
+import math
+from typing import Optional
+
+from app.config import settings
+from app.models import Venue
+
+SCORE_SIGNALS = ("busyness", "rating", "reviews", "price", "distance")
+
+
+def score_errors(weights: dict[str, float]) -> list[str]:
+    """Problems with a settings.score_weights value (empty = OK)."""
+    errors = [f"score_weights: unknown signal {name!r}" for name in weights if name not in SCORE_SIGNALS]
+    errors += [f"score_weights: {name} must not be negative" for name, w in weights.items() if w < 0]
+    if not errors and sum(weights.values()) <= 0:
+        errors.append("score_weights: at least one weight must be positive")
+    return errors
+
+
+def score_signals(
+    venue: Venue, busyness: Optional[int], distance_km: float, radius_km: float
+) -> dict[str, float]:
+    """Each signal of `venue`, scaled to 0-1 (module docstring)."""
+    saturation = settings.score_reviews_saturation
+    return {
+        "busyness": max(0, min(100, busyness)) / 100 if busyness is not None else 0.0,
+        "rating": max(0.0, min(5.0, venue.rating)) / 5 if venue.rating is not None else 0.0,
+        "reviews": (
+            min(1.0, math.log1p(venue.reviews) / math.log1p(saturation))
+            if venue.reviews else 0.0
+        ),
+        "price": (4 - venue.price_level) / 3 if venue.price_level in (1, 2, 3, 4) else 0.0,
+        "distance": max(0.0, 1 - distance_km / radius_km) if radius_km > 0 else 0.0,
+    }
+
+
+def score_venue(
+    venue: Venue, busyness: Optional[int], distance_km: float, radius_km: float
+) -> float:
+    """`venue`'s 0-100 score, rounded to one decimal."""
+    weights = settings.score_weights
+    total = sum(weights.values())
+    signals = score_signals(venue, busyness, distance_km, radius_km)
+    value = sum(w * signals[name] for name, w in weights.items()) / total
+    return round(100 * value, 1)
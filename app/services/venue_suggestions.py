@@ -0,0 +1,222 @@
+"""User-submitted venue suggestions and their moderation.
+
+Users propose a venue the catalog is missing with POST /v1/venues/suggest
+(name, address, coordinates). Suggestions wait in a pending queue (at most
+settings.venue_suggestions_max_pending; the same name + address while still
+pending is the same suggestion) until an operator decides:
+
+- approve (POST /admin/venues/suggestions/{id}/approve): the suggestion goes
+  through AddVenueHandler.add exactly like POST /admin/venues/by-address
+  (quota, dedupe, BestTime create, upsert, enrichment). A created or already
+  known venue approves it with that venue_id; any other outcome leaves it
+  pending and is returned as is, so the operator can retry;
+- reject (POST /admin/venues/suggestions/{id}/reject) with an optional reason.
+
+Decided suggestions are kept for GET /admin/venues/suggestions, the oldest
+dropped beyond venue_suggestions_max_entries.
+
+Layout:
+- `venue_suggestions:items`: hash suggestion id -> VenueSuggestion JSON;
+- `venue_suggestions:order`: sorted set of the same ids by submission time;
+- `venue_suggestions:pending`: the pending ids, by name + address hash.
+"""
+from __future__ import annotations
+
+import hashlib
+import logging
+import time
+import uuid
+from typing import Optional
+
+from pydantic import BaseModel
+
+from app.handlers.add_venue_handler import AddVenueByAddressRequest, AddVenueOutcome
+from app.metrics import VENUE_SUGGESTIONS_TOTAL
+
+logger = logging.getLogger(__name__)
+
+ITEMS_KEY = "venue_suggestions:items"
+ORDER_KEY = "venue_suggestions:order"
+PENDING_KEY = "venue_suggestions:pending"
+
+PENDING = "pending"
+APPROVED = "approved"
+REJECTED = "rejected"
+
+
+class SuggestionQueueFullError(Exception):
+    """Too many suggestions are already waiting for review."""
+
+
+class SuggestionStateError(Exception):
+    """The suggestion was already approved or rejected."""
+
+
+class VenueSuggestion(BaseModel):
+    id: str
+    venue_name: str
+    venue_address: str
+    venue_lat: float
+    venue_lng: float
+    note: Optional[str] = None
+    status: str = PENDING
+    submitted_at: float
+    decided_at: Optional[float] = None
+    # The catalog venue an approval created or matched.
+    venue_id: Optional[str] = None
+    reason: Optional[str] = None
+
+
+def _pending_hash(venue_name: str, venue_address: str) -> str:
+    return hashlib.sha1(
+        f"{venue_name.strip().lower()}|{venue_address.strip().lower()}".encode("utf-8")
+    ).hexdigest()
+
+
+class VenueSuggestionService:
+    """The suggestion queue (see module docstring)."""
+
+    def __init__(self, redis_client, add_venue_handler, max_pending: int = 500, max_entries: int = 5000):
+        self.redis = redis_client
+        self.add_venue_handler = add_venue_handler
+        self.max_pending = max_pending
+        self.max_entries = max(1, max_entries)
+
+    def submit(
+        self,
+        venue_name: str,
+        venue_address: str,
+        venue_lat: float,
+        venue_lng: float,
+        note: Optional[str] = None,
+    ) -> VenueSuggestion:
+        """Queue a suggestion, or return the pending one for the same name
+        and address.
+
+        Raises:
+            SuggestionQueueFullError: max_pending suggestions are waiting
+        """
+        key = _pending_hash(venue_name, venue_address)
+        existing_id = self.redis.hget(PENDING_KEY, key)
+        if existing_id:
+            existing = self.get(existing_id)
+            if existing is not None and existing.status == PENDING:
+                VENUE_SUGGESTIONS_TOTAL.labels(result="duplicate").inc()
+                return existing
+        if self.redis.hlen(PENDING_KEY) >= self.max_pending:
+            VENUE_SUGGESTIONS_TOTAL.labels(result="queue_full").inc()
+            raise SuggestionQueueFullError(f"{self.max_pending} suggestions are pending review")
+        suggestion = VenueSuggestion(
+            id=uuid.uuid4().hex[:12],
+            venue_name=venue_name.strip(),
+            venue_address=venue_address.strip(),
+            venue_lat=venue_lat,
+            venue_lng=venue_lng,
+            note=note,
+            submitted_at=time.time(),
+        )
+        pipe = self.redis.pipeline()
+        pipe.hset(ITEMS_KEY, suggestion.id, suggestion.model_dump_json())
+        pipe.zadd(ORDER_KEY, {suggestion.id: suggestion.submitted_at})
+        pipe.hset(PENDING_KEY, key, suggestion.id)
+        pipe.execute()
+        self._trim()
+        VENUE_SUGGESTIONS_TOTAL.labels(result="submitted").inc()
+        logger.info(f"[VenueSuggestions] New suggestion {suggestion.id}: {suggestion.venue_name}")
+        return suggestion
+
+    def get(self, suggestion_id: str) -> Optional[VenueSuggestion]:
+        raw = self.redis.hget(ITEMS_KEY, suggestion_id)
+        if raw is None:
+            return None
+        try:
+            return VenueSuggestion.model_validate_json(raw)
+        except ValueError as e:
+            logger.warning(f"[VenueSuggestions] Dropping unreadable suggestion {suggestion_id}: {e}")
+            return None
+
+    def entries(self, status: Optional[str] = None, limit: int = 100) -> list[VenueSuggestion]:
+        """Oldest first, so the review queue reads in submission order; only
+        `status` when given."""
+        result = []
+        for suggestion_id in self.redis.zrange(ORDER_KEY, 0, -1):
+            suggestion = self.get(suggestion_id)
+            if suggestion is None or (status and suggestion.status != status):
+                continue
+            result.append(suggestion)
+            if len(result) >= limit:
+                break
+        return result
+
+    def pending_count(self) -> int:
+        return self.redis.hlen(PENDING_KEY)
+
+    async def approve(
+        self, suggestion_id: str
+    ) -> tuple[Optional[VenueSuggestion], Optional[AddVenueOutcome]]:
+        """Add the suggested venue; (suggestion, add outcome), (None, None)
+        when unknown. The suggestion stays pending unless the add created or
+        matched a venue.
+
+        Raises:
+            SuggestionStateError: already decided
+        """
+        suggestion = self._pending(suggestion_id)
+        if suggestion is None:
+            return None, None
+        outcome = await self.add_venue_handler.add(AddVenueByAddressRequest(
+            venue_name=suggestion.venue_name,
+            venue_address=suggestion.venue_address,
+            venue_lat=suggestion.venue_lat,
+            venue_lng=suggestion.venue_lng,
+        ))
+        if outcome.status_code in (200, 201) and outcome.body.get("venue_id"):
+            suggestion = self._decide(suggestion, APPROVED, venue_id=outcome.body["venue_id"])
+        else:
+            logger.warning(
+                f"[VenueSuggestions] Approving {suggestion_id} failed ({outcome.status_code}); "
+                "left pending"
+            )
+        return suggestion, outcome
+
+    def reject(self, suggestion_id: str, reason: Optional[str] = None) -> Optional[VenueSuggestion]:
+        """Reject a pending suggestion; None when unknown.
+
+        Raises:
+            SuggestionStateError: already decided
+        """
+        suggestion = self._pending(suggestion_id)
+        if suggestion is None:
+            return None
+        return self._decide(suggestion, REJECTED, reason=reason)
+
+    def _pending(self, suggestion_id: str) -> Optional[VenueSuggestion]:
+        suggestion = self.get(suggestion_id)
+        if suggestion is not None and suggestion.status != PENDING:
+            raise SuggestionStateError(f"suggestion {suggestion_id} is already {suggestion.status}")
+        return suggestion
+
+    def _decide(self, suggestion: VenueSuggestion, status: str, **fields) -> VenueSuggestion:
+        decided = suggestion.model_copy(update={"status": status, "decided_at": time.time(), **fields})
+        pipe = self.redis.pipeline()
+        pipe.hset(ITEMS_KEY, decided.id, decided.model_dump_json())
+        pipe.hdel(PENDING_KEY, _pending_hash(decided.venue_name, decided.venue_address))
+        pipe.execute()
+        VENUE_SUGGESTIONS_TOTAL.labels(result=status).inc()
+        logger.info(f"[VenueSuggestions] Suggestion {decided.id} {status}")
+        return decided
+
+    def _trim(self) -> None:
+        """Drop the oldest decided suggestions beyond max_entries."""
+        excess = self.redis.zcard(ORDER_KEY) - self.max_entries
+        if excess <= 0:
+            return
+        for suggestion_id in self.redis.zrange(ORDER_KEY, 0, -1):
+            if excess <= 0:
+                break
+            suggestion = self.get(suggestion_id)
+            if suggestion is not None and suggestion.status == PENDING:
+                continue
+            self.redis.zrem(ORDER_KEY, suggestion_id)
+            self.redis.hdel(ITEMS_KEY, suggestion_id)
+            excess -= 1
@@ -0,0 +1,226 @@
+"""Validation stage for every venue written to the catalog.
+
+Discovery, the inventory sync and the retry worker write what BestTime sends
+straight into the catalog (and, through the projector, the geo index); they
+check each venue first, so their summaries can count rejections. Every other
+writer (add-venue, imports, restores, enrichments, approvals) is checked by
+VenueRepository.upsert_venue / upsert_venues, which run the same check on
+every write. A venue is:
+
+- rejected, not written: a missing id or name, coordinates out of range, not
+  numbers, or zero (the inventory reports a missing coordinate as 0, which
+  would put the venue at the equator or the prime meridian);
+- flagged, written without the bad field: a rating outside 0..5, a price level
+  outside 1..4, a negative review count or dwell time.
+
+Rejected and flagged venues are kept in a quarantine list (latest problems per
+venue, newest first, at most `max_entries`) for GET /admin/venues/quarantine,
+so the upstream data can be looked at. An entry is dropped when the venue
+later passes the check of the same source, or by hand.
+
+Layout:
+- `venue_quarantine:items`: hash venue id -> QuarantineEntry JSON;
+- `venue_quarantine:order`: sorted set of the same ids by last quarantine time.
+"""
+from __future__ import annotations
+
+import json
+import logging
+import math
+import time
+from dataclasses import asdict, dataclass, field
+from typing import Any, Optional
+
+from app.metrics import VENUE_VALIDATION_TOTAL
+from app.models import Venue
+
+logger = logging.getLogger(__name__)
+
+ITEMS_KEY = "venue_quarantine:items"
+ORDER_KEY = "venue_quarantine:order"
+
+REJECTED = "rejected"
+FLAGGED = "flagged"
+
+RATING_RANGE = (0.0, 5.0)
+PRICE_LEVEL_RANGE = (1, 4)
+
+
+@dataclass
+class QuarantineEntry:
+    venue_id: str
+    venue_name: str
+    source: str  # discovery | inventory | retry | write
+    outcome: str  # REJECTED | FLAGGED
+    problems: list[str] = field(default_factory=list)
+    record: Optional[Any] = None  # the venue JSON as received
+    first_seen_at: float = 0.0  # epoch seconds
+    last_seen_at: float = 0.0
+    occurrences: int = 0
+
+
+def _is_number(value) -> bool:
+    return isinstance(value, (int, float)) and not isinstance(value, bool) and math.isfinite(value)
+
+
+def venue_problems(venue: Venue) -> tuple[list[str], list[str]]:
+    """(problems that reject the venue, fields to drop as out of bounds)."""
+    rejects: list[str] = []
+    if not venue.venue_id:
+        rejects.append("missing venue_id")
+    if not (venue.venue_name or "").strip():
+        rejects.append("empty venue_name")
+    lat, lng = venue.venue_lat, venue.venue_lng
+    if not _is_number(lat) or not _is_number(lng):
+        rejects.append("coordinates are not numbers")
+    else:
+        if not (-90 <= lat <= 90 and -180 <= lng <= 180):
+            rejects.append(f"coordinates out of range ({lat}, {lng})")
+        if lat == 0 or lng == 0:
+            rejects.append(f"zero coordinate ({lat}, {lng})")
+
+    flagged: list[str] = []
+    if venue.rating is not None and not (
+        _is_number(venue.rating) and RATING_RANGE[0] <= venue.rating <= RATING_RANGE[1]
+    ):
+        flagged.append("rating")
+    if venue.price_level is not None and not (
+        PRICE_LEVEL_RANGE[0] <= venue.price_level <= PRICE_LEVEL_RANGE[1]
+    ):
+        flagged.append("price_level")
+    for name in ("reviews", "venue_dwell_time_min", "venue_dwell_time_max"):
+        value = getattr(venue, name)
+        if value is not None and value < 0:
+            flagged.append(name)
+    return rejects, flagged
+
+
+class VenueQuarantine:
+    """Venues that failed or were corrected by validation, newest first."""
+
+    def __init__(self, redis_client, max_entries: int = 1000):
+        """
+        Args:
+            redis_client: Redis client (decode_responses=True)
+            max_entries: oldest entries beyond this are dropped
+        """
+        self.redis = redis_client
+        self.max_entries = max(1, max_entries)
+
+    def add(self, venue: Venue, source: str, outcome: str, problems: list[str]) -> None:
+        """Record a venue's latest problems. Best-effort: a Redis failure is
+        logged, never raised."""
+        now = time.time()
+        venue_id = venue.venue_id or f"name:{venue.venue_name}"
+        try:
+            previous = self.get(venue_id)
+            entry = QuarantineEntry(
+                venue_id=venue_id,
+                venue_name=venue.venue_name,
+                source=source,
+                outcome=outcome,
+                problems=problems,
+                record=venue.model_dump(mode="json", by_alias=True),
+                first_seen_at=previous.first_seen_at if previous else now,
+                last_seen_at=now,
+                occurrences=(previous.occurrences if previous else 0) + 1,
+            )
+            pipe = self.redis.pipeline()
+            pipe.hset(ITEMS_KEY, venue_id, json.dumps(asdict(entry)))
+            pipe.zadd(ORDER_KEY, {venue_id: now})
+            pipe.execute()
+            excess = self.redis.zcard(ORDER_KEY) - self.max_entries
+            if excess > 0:
+                oldest = self.redis.zrange(ORDER_KEY, 0, excess - 1)
+                self.redis.zrem(ORDER_KEY, *oldest)
+                self.redis.hdel(ITEMS_KEY, *oldest)
+        except Exception as e:
+            logger.warning(f"[VenueQuarantine] Failed to quarantine {venue_id}: {e}")
+
+    def get(self, venue_id: str) -> Optional[QuarantineEntry]:
+        raw = self.redis.hget(ITEMS_KEY, venue_id)
+        if raw is None:
+            return None
+        try:
+            return QuarantineEntry(**json.loads(raw))
+        except (TypeError, ValueError) as e:
+            logger.warning(f"[VenueQuarantine] Dropping unreadable entry {venue_id}: {e}")
+            return None
+
+    def entries(self, limit: int = 100, outcome: Optional[str] = None) -> list[QuarantineEntry]:
+        """Newest first; only `outcome` (rejected / flagged) when given."""
+        ids = self.redis.zrevrange(ORDER_KEY, 0, -1)
+        result = []
+        for venue_id in ids:
+            entry = self.get(venue_id)
+            if entry is None or (outcome and entry.outcome != outcome):
+                continue
+            result.append(entry)
+            if len(result) >= limit:
+                break
+        return result
+
+    def count(self) -> int:
+        return self.redis.zcard(ORDER_KEY)
+
+    def remove(self, venue_id: str) -> bool:
+        """Drop one entry; False when there was none."""
+        self.redis.zrem(ORDER_KEY, venue_id)
+        return bool(self.redis.hdel(ITEMS_KEY, venue_id))
+
+    def clear(self) -> int:
+        """Drop every entry; returns how many there were."""
+        count = self.count()
+        self.redis.delete(ITEMS_KEY, ORDER_KEY)
+        return count
+
+
+class VenueRejectedError(ValueError):
+    """A venue failed validation and was not written."""
+
+    def __init__(self, venue: Venue):
+        rejects, _ = venue_problems(venue)
+        super().__init__(
+            f"venue {venue.venue_id or '?'} rejected: {'; '.join(rejects)}"
+        )
+
+
+class VenueValidator:
+    """Rejects or corrects venues before they are written (see module docstring)."""
+
+    def __init__(self, quarantine: Optional[VenueQuarantine] = None):
+        self.quarantine = quarantine
+
+    def check(self, venue: Venue, source: str) -> Optional[Venue]:
+        """The venue to write (a copy without the out-of-bounds fields when
+        flagged), or None when it is rejected."""
+        rejects, flagged = venue_problems(venue)
+        if rejects:
+            VENUE_VALIDATION_TOTAL.labels(source=source, outcome=REJECTED).inc()
+            logger.warning(
+                f"[VenueValidator] Rejected {source} venue {venue.venue_id or '?'} "
+                f"({venue.venue_name!r}): {'; '.join(rejects)}"
+            )
+            if self.quarantine is not None:
+                self.quarantine.add(venue, source, REJECTED, rejects)
+            return None
+        if flagged:
+            VENUE_VALIDATION_TOTAL.labels(source=source, outcome=FLAGGED).inc()
+            problems = [f"{name} out of bounds ({getattr(venue, name)!r}), dropped" for name in flagged]
+            logger.warning(
+                f"[VenueValidator] Flagged {source} venue {venue.venue_id}: {'; '.join(problems)}"
+            )
+            if self.quarantine is not None:
+                self.quarantine.add(venue, source, FLAGGED, problems)
+            return venue.model_copy(update={name: None for name in flagged})
+        VENUE_VALIDATION_TOTAL.labels(source=source, outcome="accepted").inc()
+        if self.quarantine is not None:
+            try:
+                # Only the stage that quarantined a venue releases it: the
+                # repository re-checks what the refresher already corrected.
+                entry = self.quarantine.get(venue.venue_id)
+                if entry is not None and entry.source == source:
+                    self.quarantine.remove(venue.venue_id)
+            except Exception as e:
+                logger.warning(f"[VenueQuarantine] Failed to release {venue.venue_id}: {e}")
+        return venue
@@ -1,18 +1,46 @@
 """Venues refresher service with background job orchestration."""
+import asyncio
 import json
 import logging
+import time
 from dataclasses import dataclass
+from datetime import datetime, timezone
 from collections import defaultdict
+from typing import Optional
 
-from app.api import BestTimeAPIClient
-from app.dao import RedisVenueDAO
+from app.api import BestTimeAPIClient, BestTimeAPIError, BestTimeCircuitOpenError
+from app.dao import VenueDAO
+from app.dao.change_events import LIVE_FORECAST_SET, VENUE_UPSERTED
 from app.models import (
     Venue,
-    FootTrafficForecast,
+    SearchParams,
     VenueFilterParams,
     VenueFilterVenue,
+    merge_forecast_days,
+    venue_from_filter,
+    venue_from_inventory,
 )
+from app.log_redaction import redact_secrets
+from app.services.busyness_validation import BusynessValidator
+from app.services.venue_validation import VenueValidator
+from app.services.crowd_providers import (
+    BESTTIME_PROVIDER,
+    BestTimeCrowdProvider,
+    CrowdProviderRegistry,
+    Region,
+)
+from app.services.venue_data_providers import BestTimeVenueDataProvider
+from app.services.filter_tuner import estimate_credits
 from app.services.price_signal import GOOGLE_SOURCES, derive_price_signal
+from app.services.venue_closures import load_closed_venue_ids_from_redis
+from app.services.discovery_locations import load_locations_file
+from app.services.event_publishing import make_event
+from app.services.refresh_reports import RefreshReportStore, note, note_error, reported
+from app.services.retry_queue import LIVE_FORECAST, UPSERT_VENUE, RetryItem
+from app.services.venue_open_hours import open_at
+from app.tenancy import current_tenant, tenant_scope
+from app.tracing import traced
+from app.utils.recife_time import recife_now
 from app.metrics import (
     VENUES_TOTAL,
     VENUES_WITH_ATTRIBUTE,
@@ -20,11 +48,13 @@ from app.metrics import (
     VENUES_WITH_LIVE_FORECAST,
     VENUES_WITH_WEEKLY_FORECAST,
     VENUES_LIVE_FORECAST_AVAILABILITY_RATIO,
+    VENUES_BUSYNESS_FLIPFLOP_FLAGGED,
     REFRESH_VENUES_DISCOVERED,
     REFRESH_VENUES_UPSERTED,
     REFRESH_DUPLICATES_SKIPPED,
     LIVE_FORECAST_FETCH_RESULTS,
     WEEKLY_FORECAST_FETCH_RESULTS,
+    WEEKEND_PREFETCH_VENUES_TOTAL,
     VENUES_AVERAGE_RATING,
     VENUES_AVERAGE_REVIEWS,
     VENUES_BY_PRICE_LEVEL,
@@ -51,6 +81,38 @@ class Location:
     lng: float
     radius: int  # Meters
     limit: int   # Max venues to fetch
+    # Per-location overrides of the discovery query (None = search_params').
+    types: Optional[list[str]] = None
+    live: Optional[bool] = None
+    # Summary label (e.g. "<region>/<point id>"); None = "lat,lng".
+    label: Optional[str] = None
+
+
+@dataclass
+class LocationRefreshSummary:
+    """Outcome of one location (or discovery point) in a discovery run."""
+    region: str  # discovery point id, or "lat,lng" for a location
+    lat: float
+    lng: float
+    radius: int
+    limit: int  # effective limit queried (0 when not queried)
+    fetched: int = 0  # venues the filter returned and we upserted
+    new_unique: int = 0  # of those, not already returned by another location this run
+    skipped: Optional[str] = None  # "budget" when the global budget ran out first
+    error: Optional[str] = None
+    duration_seconds: float = 0.0
+
+
+@dataclass
+class _DiscoveryJob:
+    name: str  # log prefix
+    region: str
+    lat: float
+    lng: float
+    radius: int
+    limit: int  # per-location cap before the global budget
+    point: Optional[dict] = None  # the discovery point whose counter to bump
+    search_params: Optional[SearchParams] = None  # None = the refresher's
 
 
 # Default locations for venue discovery (radius in meters)
@@ -92,14 +154,28 @@ from app.services.venue_eligibility import (  # noqa: E402,F401
 )
 
 
+def _stop_result(error: Exception) -> str:
+    """LIVE_FORECAST_FETCH_RESULTS label for venues left by a stopped run."""
+    return "skipped_circuit_open" if isinstance(error, BestTimeCircuitOpenError) else "aborted"
+
+
+class _RetryLater(Exception):
+    """A retry that could not run now but should not count as an attempt
+    (e.g. the monthly ledger refused the BestTime read)."""
+
+
 class VenuesRefresherService:
     """Service for refreshing venue data from BestTime API."""
 
     ADMIN_CONFIG_DISCOVERY_POINTS_KEY = "admin_config:discovery_points"
+    # Newest-first list of the BestTime `_links` seen by recent discovery calls,
+    # surfaced by GET /admin/jobs/venue_catalog/besttime-links.
+    BESTTIME_LINKS_KEY = "admin:besttime_links:venue_catalog"
+    BESTTIME_LINKS_MAX = 50
 
     def __init__(
         self,
-        venue_dao: RedisVenueDAO,
+        venue_dao: VenueDAO,
         besttime_api: BestTimeAPIClient,
         redis_client=None,
         fetch_venue_limit_override: int = 0,
@@ -108,11 +184,21 @@ class VenuesRefresherService:
         dev_lat: float = 0.0,
         dev_lng: float = 0.0,
         dev_radius: int = 6000,
+        live_refresh_cooldown_seconds: int = 0,
+        search_params: Optional[SearchParams] = None,
+        filter_tuner=None,
+        live_fetch_concurrency: int = 1,
+        discovery_concurrency: int = 1,
+        locations_file: str = "",
+        report_history_size: int = 200,
+        live_skip_closed: bool = False,
+        live_open_margin_minutes: int = 30,
     ):
         """Initialize refresher service.
 
         Args:
-            venue_dao: Redis DAO for venue persistence
+            venue_dao: venue DAO for persistence; must be a PrioritizedVenueDAO
+                (the pipeline VenueRepository) when a budget service is wired
             besttime_api: BestTime API client
             redis_client: Raw Redis client for reading admin config
             fetch_venue_limit_override: If > 0, overrides the limit for each location when fetching from BestTime API
@@ -121,6 +207,24 @@ class VenuesRefresherService:
             dev_lat: Dev mode latitude
             dev_lng: Dev mode longitude
             dev_radius: Dev mode radius in meters
+            live_refresh_cooldown_seconds: Live refreshes skip venues whose cached
+                live forecast is younger than this (0 = no cooldown)
+            search_params: What discovery queries at each location; None means
+                the standard query (busy_min=0, foot_traffic=both, VENUE_TYPES)
+            filter_tuner: Optional FilterTuner that varies radius/busy_min/limit
+                per discovery region and scores them by live venues per credit
+            live_fetch_concurrency: Live forecasts fetched at once during a
+                live refresh (1 = one venue at a time)
+            discovery_concurrency: Locations / discovery points queried at
+                once during a discovery refresh (1 = one at a time)
+            locations_file: JSON/YAML file of discovery points used when the
+                admin config has none; re-read every run ("" = none)
+            report_history_size: run reports kept in Redis for
+                GET /admin/refresh/history (needs redis_client)
+            live_skip_closed: Live refreshes skip venues whose stored BestTime
+                hours say they are closed now (Recife time)
+            live_open_margin_minutes: with live_skip_closed, a venue counts as
+                open this long before opening and after closing
         """
         self.venue_dao = venue_dao
         self.besttime_api = besttime_api
@@ -131,14 +235,136 @@ class VenuesRefresherService:
         self.dev_lat = dev_lat
         self.dev_lng = dev_lng
         self.dev_radius = dev_radius
+        self.live_refresh_cooldown_seconds = live_refresh_cooldown_seconds
+        search_params = search_params or SearchParams()
+        if search_params.types is None:
+            search_params = search_params.model_copy(update={"types": VENUE_TYPES})
+        self.search_params = search_params
+        self.filter_tuner = filter_tuner
+        self.live_fetch_concurrency = max(1, live_fetch_concurrency)
+        self.discovery_concurrency = max(1, discovery_concurrency)
+        self.locations_file = locations_file
+        self.live_skip_closed = live_skip_closed
+        self.live_open_margin_minutes = max(0, live_open_margin_minutes)
+        self.run_reports = (
+            RefreshReportStore(redis_client, report_history_size)
+            if redis_client is not None else None
+        )
+        # Per-location outcome of the latest discovery run (admin / debugging).
+        self.last_discovery_summaries: list[LocationRefreshSummary] = []
         # Optional: set later via set_budget_service so the container can wire
         # this up after construction (avoids a circular import).
         self.budget_service = None
+        # Optional CrowdProviderRegistry for live/weekly busyness; None means
+        # BestTime only (see _crowd_registry).
+        self.crowd_providers = None
+        # Optional VenueDataProvider for discovery; None means BestTime
+        # /venues/filter (see _venue_source).
+        self.venue_data_provider = None
+        # Validation stage for every live/weekly value before it is cached.
+        self.busyness_validator = BusynessValidator()
+        # Validation stage for every venue before it is upserted; rejected and
+        # flagged venues are only logged until set_venue_quarantine.
+        self.venue_validator = VenueValidator()
+        # Optional RetryQueue that failed upserts and live fetches are pushed
+        # onto; None means they are only logged (see process_retry_queue).
+        self.retry_queue = None
+        # Optional AreaCrowdIndexService recomputed after each live refresh.
+        self.area_index = None
+        # Optional WebhookService whose rules are evaluated after each live refresh.
+        self.webhooks = None
+        # Optional PushNotifier evaluated after each live refresh.
+        self.push_notifier = None
+        # Optional EventOutbox that venue upserts and cached live forecasts are
+        # recorded in for the message broker (app/services/event_publishing.py).
+        self.event_outbox = None
+        # Optional RegionRegistry whose points are discovered when neither
+        # admin points nor a locations file are set (app/services/regions.py).
+        self.regions = None
+        # Optional VenueDeduplicator consulted before writing a venue new to the
+        # catalog (app/services/venue_dedup.py).
+        self.deduplicator = None
+        # Monthly new-venue quota of the tenant discovery currently runs for
+        # (-1 = none); set by refresh_venues_for_tenants.
+        self.tenant_new_venue_quota = -1
 
     def set_budget_service(self, budget_service) -> None:
         """Wire the VenueBudgetService used to enforce the monthly cap."""
         self.budget_service = budget_service
 
+    def set_crowd_providers(self, registry) -> None:
+        """Wire the CrowdProviderRegistry live/weekly fetches go through."""
+        self.crowd_providers = registry
+
+    def set_venue_data_provider(self, provider) -> None:
+        """Wire the VenueDataProvider discovery searches go through."""
+        self.venue_data_provider = provider
+
+    def set_retry_queue(self, queue) -> None:
+        """Wire the RetryQueue failed upserts and live fetches are pushed onto."""
+        self.retry_queue = queue
+
+    def set_area_index(self, service) -> None:
+        """Wire the AreaCrowdIndexService recomputed after each live refresh."""
+        self.area_index = service
+
+    def set_webhooks(self, service) -> None:
+        """Wire the WebhookService evaluated after each live refresh."""
+        self.webhooks = service
+
+    def set_push_notifier(self, notifier) -> None:
+        """Wire the PushNotifier evaluated after each live refresh."""
+        self.push_notifier = notifier
+
+    def set_regions(self, regions) -> None:
+        """Wire the RegionRegistry discovery falls back to before DEFAULT_LOCATIONS."""
+        self.regions = regions
+
+    def set_deduplicator(self, deduplicator) -> None:
+        """Wire the VenueDeduplicator new discovered venues are checked against."""
+        self.deduplicator = deduplicator
+
+    def set_venue_quarantine(self, quarantine) -> None:
+        """Wire the VenueQuarantine rejected and flagged venues are listed in."""
+        self.venue_validator.quarantine = quarantine
+
+    def set_event_outbox(self, outbox) -> None:
+        """Wire the EventOutbox venue and live forecast writes are recorded in."""
+        self.event_outbox = outbox
+
+    def _record_event(self, event_type: str, venue_id: str, data) -> None:
+        """Best-effort: a failed outbox write is logged, never fails the refresh."""
+        if self.event_outbox is None or not venue_id:
+            return
+        try:
+            self.event_outbox.add(make_event(event_type, venue_id, data))
+        except Exception as e:
+            logger.warning(
+                f"[VenuesRefresherService] Failed to record {event_type} event for {venue_id}: {e}"
+            )
+
+    def _record_venue_upserted(self, venue: Venue) -> None:
+        if self.event_outbox is not None:
+            self._record_event(
+                VENUE_UPSERTED, venue.venue_id, venue.model_dump(mode="json", by_alias=True)
+            )
+
+    def _queue_retry(self, kind: str, venue_id: str, error: Exception, payload=None) -> None:
+        if self.retry_queue is not None and venue_id:
+            self.retry_queue.push(kind, venue_id, str(error), payload)
+
+    def _venue_source(self):
+        if self.venue_data_provider is not None:
+            return self.venue_data_provider
+        return BestTimeVenueDataProvider(self.besttime_api)
+
+    def _crowd_registry(self):
+        if self.crowd_providers is not None:
+            return self.crowd_providers
+        # Built per call so a besttime_api swapped after construction (tests,
+        # BDD harness) is still the one used.
+        return CrowdProviderRegistry([BestTimeCrowdProvider(self.besttime_api)])
+
     # ── priority-bounded refresh selection + monthly ledger gate ─────────────
     def _select_refresh_venue_ids(self, job: str) -> list[str]:
         """The top-X served venues by priority for bounded refresh — the
@@ -148,7 +374,8 @@ class VenuesRefresherService:
         X = monthly_quota − manual_reserve. A serving-view read failure propagates
         and aborts the cycle (fail-safe) — it never falls back to an active-scoped
         refresh. Falls back to the full servable set only when no budget service
-        is wired (keeps standalone use working)."""
+        is wired (keeps standalone use working). Temporarily closed venues
+        (app/services/venue_closures.py) are dropped from either selection."""
         if self.budget_service is not None:
             limit = self.budget_service.get_refresh_budget()
             ids = self.venue_dao.list_servable_venue_ids_by_priority(limit)
@@ -162,6 +389,15 @@ class VenuesRefresherService:
                 f"[VenuesRefresherService] {job}: no budget service wired; "
                 f"refreshing all {len(ids)} servable venues (unbounded)"
             )
+        closed = load_closed_venue_ids_from_redis(self.redis_client)
+        if closed:
+            selected = len(ids)
+            ids = [vid for vid in ids if vid not in closed]
+            if len(ids) != selected:
+                logger.info(
+                    f"[VenuesRefresherService] {job}: skipping {selected - len(ids)} "
+                    "temporarily closed venues"
+                )
         REFRESH_SELECTED_TOTAL.labels(job=job).inc(len(ids))
         return ids
 
@@ -217,6 +453,8 @@ class VenuesRefresherService:
             logger.error(f"[VenuesRefresherService] Failed to list venues for metrics: {e}")
             return
 
+        VENUES_BUSYNESS_FLIPFLOP_FLAGGED.set(len(self.busyness_validator.flagged_venue_ids()))
+
         venues = [venue for venue in all_venues if venue.is_active()]
         deprecated_count = len(all_venues) - len(venues)
         total = len(venues)
@@ -377,9 +615,7 @@ class VenuesRefresherService:
         )
 
     def _map_venue_filter_venue_to_venue(self, vf: VenueFilterVenue) -> Venue:
-        """Convert VenueFilterVenue to Venue model.
-
-        Preserves exact mapping from Go implementation (lines 432-469).
+        """Convert VenueFilterVenue to Venue model, keeping its day of forecast.
 
         Args:
             vf: VenueFilterVenue from API response
@@ -387,37 +623,7 @@ class VenuesRefresherService:
         Returns:
             Venue object ready for persistence
         """
-        # Build one-day FootTrafficForecast from filter result
-        foot_traffic = [
-            FootTrafficForecast(
-                day_info=vf.day_info,
-                day_int=vf.day_int,
-                day_raw=vf.day_raw,
-            )
-        ]
-
-        venue = Venue(
-            forecast=True,
-            processed=True,
-            venue_address=vf.venue_address,
-            venue_lat=vf.venue_lat,
-            venue_lng=vf.venue_lng,
-            venue_name=vf.venue_name,
-            venue_id=vf.venue_id,
-            venue_type=vf.venue_type,
-            venue_dwell_time_min=vf.venue_dwell_time_min,
-            venue_dwell_time_max=vf.venue_dwell_time_max,
-            rating=vf.rating,
-            reviews=vf.reviews,
-            # BestTime's raw price is kept in its own column and fed to the shared
-            # derivation as step 3; the served `price_level` is derived (never the
-            # raw int directly), preserving any Google-derived tier (see
-            # _apply_besttime_refresh_price).
-            besttime_price_level=vf.price_level,
-            venue_foot_traffic_forecast=foot_traffic,
-        )
-
-        return venue
+        return venue_from_filter(vf)
 
     def _apply_besttime_refresh_price(self, venue: Venue, existing: "Venue | None") -> None:
         """Set the served price tier on a refreshed venue from its BestTime price,
@@ -460,11 +666,12 @@ class VenuesRefresherService:
         """
         logger.info(f"[VenuesRefresherService] VenueFilter start: params={params}")
 
-        response = await self.besttime_api.venue_filter(params)
+        response = await self._venue_source().search_nearby(params)
         logger.info(
             f"[VenuesRefresherService] VenueFilter status={response.status}, "
             f"venues_n={response.venues_n}"
         )
+        self._record_besttime_links(params, response.links)
 
         if response.status != "OK":
             logger.warning(
@@ -472,6 +679,7 @@ class VenuesRefresherService:
                 "aborting upsert."
             )
             return []
+        note(venues_found=len(response.venues))
 
         # CRITICAL: Deduplication algorithm (lines 374-417)
         seen_ids = set()
@@ -507,7 +715,11 @@ class VenuesRefresherService:
 
             # Map and upsert. Ineligible venues are upserted active and simply
             # excluded by the serving view (no born-deprecate / soft-delete).
-            venue = self._map_venue_filter_venue_to_venue(vf)
+            venue = self.venue_validator.check(
+                self._map_venue_filter_venue_to_venue(vf), source="discovery"
+            )
+            if venue is None:
+                continue
 
             logger.info(
                 f"[VenuesRefresherService] Upserting venue id={venue.venue_id}, "
@@ -527,7 +739,28 @@ class VenuesRefresherService:
                     # counter drift, BestTime is the source of truth).
                     existing_venue = None
                 was_new_to_redis = existing_venue is None
+            # A new id for a venue we already have (e.g. "Bar do Ze - Recife"
+            # next to "Bar do Zé"): carry on with the existing venue.
+            if was_new_to_redis and self.deduplicator is not None:
+                canonical_id = self.deduplicator.canonical_for(venue)
+                if canonical_id:
+                    REFRESH_DUPLICATES_SKIPPED.labels(reason="fuzzy_duplicate").inc()
+                    seen_ids.add(venue.venue_id)
+                    if canonical_id not in unique_ids:
+                        unique_ids.append(canonical_id)
+                    continue
             self._apply_besttime_refresh_price(venue, existing_venue)
+            # Directory data and media come from enrichment, not BestTime.
+            if existing_venue is not None:
+                venue.open_data = existing_venue.open_data
+                venue.media = existing_venue.media
+                # A filter row carries one day's curve; keep the other stored days.
+                stored_days = existing_venue.venue_foot_traffic_forecast
+                if isinstance(stored_days, list):
+                    venue.venue_foot_traffic_forecast = merge_forecast_days(
+                        stored_days, venue.venue_foot_traffic_forecast
+                    )
+            venue.refreshed_at = datetime.now(timezone.utc)
 
             try:
                 self.venue_dao.upsert_venue(venue)
@@ -535,11 +768,19 @@ class VenuesRefresherService:
                 logger.error(
                     f"[VenuesRefresherService] Upsert failed for {venue.venue_id}: {e}"
                 )
+                note_error(f"upsert {venue.venue_id}: {e}")
+                self._queue_retry(
+                    UPSERT_VENUE, venue.venue_id, e,
+                    payload=venue.model_dump(mode="json", by_alias=True),
+                )
                 continue
+            self._record_venue_upserted(venue)
 
             if was_new_to_redis and self.budget_service is not None:
                 try:
-                    new_count = self.budget_service.record_new_venue_from_discovery()
+                    new_count = self.budget_service.record_new_venue_from_discovery(
+                        tenant_id=current_tenant()
+                    )
                     VENUE_MONTHLY_NEW_COUNT.set(new_count)
                 except Exception as e:
                     logger.warning(
@@ -550,7 +791,8 @@ class VenuesRefresherService:
             # Track as seen
             if venue.venue_id:
                 seen_ids.add(venue.venue_id)
-                unique_ids.append(venue.venue_id)
+                if venue.venue_id not in unique_ids:  # already there as a duplicate's target
+                    unique_ids.append(venue.venue_id)
             if venue.venue_name:
                 seen_names.add(venue.venue_name)
 
@@ -560,6 +802,7 @@ class VenuesRefresherService:
 
         # Update metrics
         REFRESH_VENUES_UPSERTED.labels(operation="venue_filter").set(len(unique_ids))
+        note(upserted=len(unique_ids))
 
         # Optionally fetch and cache live forecasts
         if fetch_and_cache_live and unique_ids:
@@ -575,98 +818,314 @@ class VenuesRefresherService:
 
         return unique_ids
 
-    async def _fetch_and_cache_live_forecasts(self, venue_ids: list[str]) -> None:
+    async def _fetch_and_cache_live_forecasts(self, venue_ids: list[str]) -> dict:
         """Fetch and cache live forecasts for given venue IDs.
 
         CRITICAL: Implements exact filtering logic from Go (lines 243-274).
 
+        Up to `live_fetch_concurrency` venues are fetched at once; the client's
+        token bucket and search limiter still pace the actual BestTime sends.
+        An abort-class error (circuit open, quota exceeded, key rejected) stops
+        the workers from starting new venues; the ones not started are counted
+        as skipped_circuit_open / aborted.
+
         Args:
             venue_ids: List of venue IDs to fetch forecasts for
+
+        Returns:
+            Per-result counts for the run (LIVE_FORECAST_FETCH_RESULTS labels)
+            and `errors`: venue_id -> message of each failed venue
         """
         logger.info(
-            f"[VenuesRefresherService] Fetching live forecasts for {len(venue_ids)} venues"
+            f"[VenuesRefresherService] Fetching live forecasts for {len(venue_ids)} venues "
+            f"(concurrency {self.live_fetch_concurrency})"
         )
 
-        for vid in venue_ids:
-            if not self._ledger_allows_read(vid, "live_forecast"):
-                continue
-            logger.debug(
-                f"[VenuesRefresherService] Fetching live forecast for venue_id={vid}"
+        registry = self._crowd_registry()
+        # One iterator shared by the workers: each venue is taken exactly once.
+        pending = iter(venue_ids)
+        counts: dict[str, int] = {}
+        errors: dict[str, str] = {}
+        stopped_by: list[Exception] = []
+
+        def count(result: str, n: int = 1) -> None:
+            LIVE_FORECAST_FETCH_RESULTS.labels(result=result).inc(n)
+            counts[result] = counts.get(result, 0) + n
+
+        async def worker() -> None:
+            for vid in pending:
+                try:
+                    result = await self._fetch_and_cache_live_one(vid, registry, errors)
+                except (BestTimeCircuitOpenError, BestTimeAPIError) as e:
+                    stopped_by.append(e)
+                    count(_stop_result(e))
+                    return
+                if result is not None:
+                    count(result)
+                if stopped_by:
+                    return
+
+        workers = max(1, min(self.live_fetch_concurrency, len(venue_ids)))
+        await asyncio.gather(*(worker() for _ in range(workers)))
+
+        if stopped_by:
+            not_started = sum(1 for _ in pending)
+            error = stopped_by[0]
+            count(_stop_result(error), not_started)
+            remaining = len(stopped_by) + not_started
+            if isinstance(error, BestTimeCircuitOpenError):
+                # BestTime is down: one line for the run instead of one error
+                # per remaining venue; the next tick tries again.
+                logger.warning(
+                    f"[VenuesRefresherService] Stopping live refresh, "
+                    f"{remaining} venues left: {error}"
+                )
+            else:
+                # Out of credits or a rejected key: every remaining call
+                # would fail the same way.
+                logger.error(
+                    f"[VenuesRefresherService] Aborting live refresh, "
+                    f"{remaining} venues left: {error}"
+                )
+        if errors:
+            logger.warning(
+                f"[VenuesRefresherService] Live refresh finished with {len(errors)} "
+                f"failed venues: {sorted(errors)[:10]}"
+            )
+        note(
+            live_cached=counts.get("cached", 0),
+            deleted=counts.get("deleted_not_ok", 0) + counts.get("deleted_not_available", 0),
+        )
+        if stopped_by:
+            note_error(f"live refresh stopped: {stopped_by[0]}")
+        for vid, message in errors.items():
+            note_error(f"{vid}: {message}")
+        return {**counts, "errors": errors}
+
+    async def _fetch_and_cache_live_one(
+        self, vid: str, registry, errors: dict[str, str]
+    ) -> Optional[str]:
+        """Fetch and cache one venue's live forecast.
+
+        The monthly ledger is only consulted when BestTime is about to be
+        asked, so a venue a partner reading answers spends no read.
+
+        Returns:
+            The LIVE_FORECAST_FETCH_RESULTS result, or None when the monthly
+            ledger denied the read. Failures are also recorded in `errors`.
+
+        Raises:
+            BestTimeCircuitOpenError / BestTimeAPIError (abort-class): stop the run
+        """
+        logger.debug(
+            f"[VenuesRefresherService] Fetching live forecast for venue_id={vid}"
+        )
+        denied = []
+
+        def allow(provider) -> bool:
+            if provider.name != BESTTIME_PROVIDER or self._ledger_allows_read(vid, "live_forecast"):
+                return True
+            denied.append(provider)
+            return False
+
+        try:
+            lf, provider = await registry.fetch_live(vid, allow=allow)
+        except BestTimeCircuitOpenError:
+            raise
+        except BestTimeAPIError as e:
+            if e.action == BestTimeAPIError.ABORT:
+                raise
+            logger.error(
+                f"[VenuesRefresherService] GetLiveForecast failed for {vid}: {e}"
+            )
+            errors[vid] = str(e)
+            if e.kind == BestTimeAPIError.INVALID_VENUE:
+                return "skipped_invalid_venue"
+            self._queue_retry(LIVE_FORECAST, vid, e)
+            return "error"
+        except Exception as e:
+            logger.error(
+                f"[VenuesRefresherService] GetLiveForecast failed for {vid}: {e}"
             )
+            errors[vid] = str(e)
+            self._queue_retry(LIVE_FORECAST, vid, e)
+            return "error"
+
+        if lf is None:
+            if denied:
+                return None
+            logger.info(
+                f"[VenuesRefresherService] No crowd provider covers {vid}; skipping"
+            )
+            return "skipped_no_provider"
+        if getattr(provider, "stores_live", False):
+            # e.g. a partner reading: already stored and served under its own
+            # key, so it is neither cached nor allowed to clear BestTime's.
+            return "provider_stored"
+
+        # CRITICAL: Live forecast filtering logic (lines 254-265)
+        # Only cache if status OK AND live data available
+        # If status not OK or live data not available (perhaps venue is closed),
+        # delete stale cache entry
+        if lf.status != "OK" or not lf.analysis.venue_live_busyness_available:
+            if lf.status != "OK":
+                logger.warning(
+                    f"[VenuesRefresherService] Error LiveForecast status={lf.status!r} "
+                    f"for {vid}, removing cache"
+                )
+                result = "deleted_not_ok"
+            else:
+                logger.info(
+                    f"[VenuesRefresherService] No error but LiveForecast not available, "
+                    f"maybe venue is closed, for {vid}, removing cache"
+                )
+                result = "deleted_not_available"
 
             try:
-                lf = await self.besttime_api.get_live_forecast(venue_id=vid)
+                self.venue_dao.delete_live_forecast(vid)
             except Exception as e:
                 logger.error(
-                    f"[VenuesRefresherService] GetLiveForecast failed for {vid}: {e}"
+                    f"[VenuesRefresherService] Failed to delete stale live forecast "
+                    f"for {vid}: {e}"
                 )
-                LIVE_FORECAST_FETCH_RESULTS.labels(result="error").inc()
-                continue
+            return result
 
-            # CRITICAL: Live forecast filtering logic (lines 254-265)
-            # Only cache if status OK AND live data available
-            # If status not OK or live data not available (perhaps venue is closed),
-            # delete stale cache entry
-            if lf.status != "OK" or not lf.analysis.venue_live_busyness_available:
-                if lf.status != "OK":
-                    logger.warning(
-                        f"[VenuesRefresherService] Error LiveForecast status={lf.status!r} "
-                        f"for {vid}, removing cache"
-                    )
-                    LIVE_FORECAST_FETCH_RESULTS.labels(result="deleted_not_ok").inc()
-                else:
-                    logger.info(
-                        f"[VenuesRefresherService] No error but LiveForecast not available, "
-                        f"maybe venue is closed, for {vid}, removing cache"
-                    )
-                    LIVE_FORECAST_FETCH_RESULTS.labels(result="deleted_not_available").inc()
+        validated = self.busyness_validator.validate_live(lf, "live")
+        if validated is None:
+            return "rejected_outlier"
+        lf = validated
+        lf.refreshed_at = datetime.now(timezone.utc)
 
-                try:
-                    self.venue_dao.delete_live_forecast(vid)
-                except Exception as e:
-                    logger.error(
-                        f"[VenuesRefresherService] Failed to delete stale live forecast "
-                        f"for {vid}: {e}"
-                    )
-                continue
+        # Cache the live forecast
+        logger.debug(
+            f"[VenuesRefresherService] Caching live forecast for venue_id={vid}"
+        )
+        try:
+            cached = self.venue_dao.set_live_forecast(lf)
+        except Exception as e:
+            logger.error(
+                f"[VenuesRefresherService] SetLiveForecast failed for {vid}: {e}"
+            )
+            errors[vid] = str(e)
+            self._queue_retry(LIVE_FORECAST, vid, e)
+            return "error"
 
-            # Cache the live forecast
+        if cached:
             logger.debug(
-                f"[VenuesRefresherService] Caching live forecast for venue_id={vid}"
+                f"[VenuesRefresherService] Live forecast cached for venue_id={vid}"
+            )
+            self._record_event(
+                LIVE_FORECAST_SET, lf.venue_info.venue_id, lf.model_dump(mode="json")
+            )
+            return "cached"
+        else:
+            # Benign, non-error outcome: the write is keyed off the BestTime
+            # payload's own venue_info.venue_id (not necessarily == vid), and
+            # RdsVenueStore.upsert_live_forecast no-ops instead of raising
+            # ForeignKeyViolation when that id has no row in venues.venue.
+            # Log both ids — equal means the requested venue itself is no
+            # longer in the catalog; different means BestTime echoed back a
+            # venue_id that never matched ours — to tell the two apart in prod.
+            # INFO (not DEBUG): this is the only signal that tells the two
+            # possible causes apart in prod (equal ids -> requested venue
+            # itself left the catalog; different ids -> BestTime echoed a
+            # venue_id that never matched ours), so it must survive at the
+            # log level the refresher normally runs at.
+            logger.info(
+                f"[VenuesRefresherService] Live forecast skipped for "
+                f"requested vid={vid}, payload venue_id="
+                f"{lf.venue_info.venue_id!r}: not present in venues catalog"
             )
+            return "skipped_venue_absent"
+
+    # ---- Retry worker for failed upserts / live fetches ----
+
+    @traced("VenuesRefresherService.process_retry_queue")
+    async def process_retry_queue(self, limit: int = 100) -> dict:
+        """Retry the due items of the retry queue (see retry_queue.py).
+
+        An item a regular refresh already redid since it failed is dropped
+        without a call. When BestTime cannot be used at all (circuit open,
+        quota exceeded, key rejected) the run stops and the item is postponed
+        without counting an attempt. An item the monthly ledger refuses is
+        postponed by its current backoff, also without counting an attempt,
+        and the run goes on with the others.
+
+        Returns:
+            Counts: retried, succeeded, failed, dead, obsolete, postponed
+        """
+        summary = {
+            "retried": 0, "succeeded": 0, "failed": 0, "dead": 0, "obsolete": 0, "postponed": 0,
+        }
+        if self.retry_queue is None:
+            return summary
+        registry = self._crowd_registry()
+        for item in self.retry_queue.due(limit):
+            if self._retry_obsolete(item):
+                self.retry_queue.succeeded(item)
+                summary["obsolete"] += 1
+                continue
+            summary["retried"] += 1
             try:
-                cached = self.venue_dao.set_live_forecast(lf)
-            except Exception as e:
-                logger.error(
-                    f"[VenuesRefresherService] SetLiveForecast failed for {vid}: {e}"
-                )
-                LIVE_FORECAST_FETCH_RESULTS.labels(result="error").inc()
+                error = await self._retry_item(item, registry)
+            except (BestTimeCircuitOpenError, BestTimeAPIError) as e:
+                self.retry_queue.postpone(item, self.retry_queue.backoff(0))
+                logger.warning(f"[VenuesRefresherService] Stopping retries: {e}")
+                break
+            except _RetryLater:
+                self.retry_queue.postpone(item, self.retry_queue.backoff(item.attempts))
+                summary["postponed"] += 1
                 continue
+            if error is None:
+                self.retry_queue.succeeded(item)
+                summary["succeeded"] += 1
+            elif self.retry_queue.failed(item, error):
+                summary["dead"] += 1
+            else:
+                summary["failed"] += 1
+        if summary["retried"] or summary["obsolete"]:
+            logger.info(f"[VenuesRefresherService] Retry queue run: {summary}")
+        return summary
 
-            if cached:
-                LIVE_FORECAST_FETCH_RESULTS.labels(result="cached").inc()
-                logger.debug(
-                    f"[VenuesRefresherService] Live forecast cached for venue_id={vid}"
-                )
+    def _retry_obsolete(self, item: RetryItem) -> bool:
+        """Whether a regular refresh has redone the item since it last failed."""
+        try:
+            if item.kind == UPSERT_VENUE:
+                record = self.venue_dao.get_venue(item.item_id)
+            elif item.kind == LIVE_FORECAST:
+                record = self.venue_dao.get_live_forecast(item.item_id)
             else:
-                # Benign, non-error outcome: the write is keyed off the BestTime
-                # payload's own venue_info.venue_id (not necessarily == vid), and
-                # RdsVenueStore.upsert_live_forecast no-ops instead of raising
-                # ForeignKeyViolation when that id has no row in venues.venue.
-                # Log both ids — equal means the requested venue itself is no
-                # longer in the catalog; different means BestTime echoed back a
-                # venue_id that never matched ours — to tell the two apart in prod.
-                LIVE_FORECAST_FETCH_RESULTS.labels(result="skipped_venue_absent").inc()
-                # INFO (not DEBUG): this is the only signal that tells the two
-                # possible causes apart in prod (equal ids -> requested venue
-                # itself left the catalog; different ids -> BestTime echoed a
-                # venue_id that never matched ours), so it must survive at the
-                # log level the refresher normally runs at.
-                logger.info(
-                    f"[VenuesRefresherService] Live forecast skipped for "
-                    f"requested vid={vid}, payload venue_id="
-                    f"{lf.venue_info.venue_id!r}: not present in venues catalog"
-                )
+                return False
+        except Exception:
+            return False
+        refreshed_at = getattr(record, "refreshed_at", None)
+        return refreshed_at is not None and refreshed_at.timestamp() > item.last_failed_at
+
+    async def _retry_item(self, item: RetryItem, registry) -> Optional[str]:
+        """Redo one item; the error message, or None when it worked.
+
+        Raises:
+            BestTimeCircuitOpenError / BestTimeAPIError (abort-class)
+            _RetryLater: the monthly ledger refused the read
+        """
+        if item.kind == UPSERT_VENUE:
+            try:
+                venue = self.venue_validator.check(Venue.model_validate(item.payload), source="retry")
+                if venue is None:
+                    return None  # quarantined; retrying cannot fix it
+                self.venue_dao.upsert_venue(venue)
+            except Exception as e:
+                return str(e)
+            self._record_venue_upserted(venue)
+            return None
+        if item.kind == LIVE_FORECAST:
+            errors: dict[str, str] = {}
+            result = await self._fetch_and_cache_live_one(item.item_id, registry, errors)
+            if result is None:
+                raise _RetryLater(f"ledger refused the live read for {item.item_id}")
+            LIVE_FORECAST_FETCH_RESULTS.labels(result=result).inc()
+            return errors.get(item.item_id, "failed") if result == "error" else None
+        return f"unknown retry kind {item.kind!r}"
 
     # ---- Discovery Points (admin-configurable locations) ----
 
@@ -699,6 +1158,51 @@ class VenuesRefresherService:
         except Exception as e:
             logger.error(f"[VenuesRefresherService] Failed to save discovery points: {e}")
 
+    def _get_file_locations(self) -> list[Location]:
+        """Locations from `locations_file`, read fresh so edits apply on the
+        next run. Returns an empty list when unset or unreadable."""
+        if not self.locations_file:
+            return []
+        try:
+            points = load_locations_file(self.locations_file)
+        except Exception as e:
+            logger.error(
+                f"[VenuesRefresherService] Failed to load locations file "
+                f"{self.locations_file}: {e}"
+            )
+            return []
+        return [
+            Location(
+                lat=p["lat"], lng=p["lng"], radius=p["radius"], limit=p["limit"],
+                types=p.get("types"), live=p.get("live"),
+            )
+            for p in points
+        ]
+
+    def active_locations(self) -> tuple[str, list]:
+        """Where the next production discovery run takes its locations from
+        ("admin", "file" or "default") and those locations."""
+        points = self._get_discovery_points()
+        if points:
+            return "admin", points
+        file_locations = self._get_file_locations()
+        if file_locations:
+            return "file", file_locations
+        if self.regions:
+            return "regions", self._region_locations(self.regions)
+        return "default", DEFAULT_LOCATIONS
+
+    @staticmethod
+    def _region_locations(regions) -> list[Location]:
+        return [
+            Location(
+                lat=p.lat, lng=p.lng, radius=p.radius, limit=p.limit,
+                types=p.types, live=p.live, label=f"{region.id}/{p.id}",
+            )
+            for region in regions
+            for p in region.points
+        ]
+
     def recount_discovery_points(self) -> list[dict]:
         """Recount venues for each discovery point using GEORADIUS.
 
@@ -728,29 +1232,169 @@ class VenuesRefresherService:
         logger.info(f"[VenuesRefresherService] Recount complete for {len(points)} points")
         return points
 
+    # ---- BestTime `_links` (operator debugging) ----
+
+    def _record_besttime_links(self, params: VenueFilterParams, links) -> None:
+        """Keep the `_links` a discovery call returned, with the query that
+        produced them, so operators can open BestTime's own tools for a
+        specific refresh run. The URLs carry the API keys as query params;
+        those are masked before storing. Best-effort: never fails the refresh."""
+        if not links or self.redis_client is None:
+            return
+        entry = {
+            "captured_at": datetime.now(timezone.utc).isoformat(),
+            "lat": params.lat,
+            "lng": params.lng,
+            "radius": params.radius,
+            "collection_id": params.collection_id,
+            "links": links,
+        }
+        try:
+            self.redis_client.lpush(self.BESTTIME_LINKS_KEY, redact_secrets(json.dumps(entry)))
+            self.redis_client.ltrim(self.BESTTIME_LINKS_KEY, 0, self.BESTTIME_LINKS_MAX - 1)
+        except Exception as e:
+            logger.warning(f"[VenuesRefresherService] Failed to record BestTime links: {e}")
+
+    def get_besttime_links(self) -> list[dict]:
+        """Recorded discovery `_links`, newest first (empty when none). Masked
+        again on read, so entries stored before masking never leak a key."""
+        if self.redis_client is None:
+            return []
+        entries = []
+        for raw in self.redis_client.lrange(self.BESTTIME_LINKS_KEY, 0, -1):
+            try:
+                entries.append(json.loads(redact_secrets(raw)))
+            except (TypeError, ValueError):
+                continue
+        return entries
+
+    def _location_search_params(
+        self, types: Optional[list[str]] = None, live: Optional[bool] = None
+    ) -> SearchParams:
+        """self.search_params with a location's own venue types / live flag."""
+        update = {}
+        if types:
+            update["types"] = types
+        if live is not None:
+            update["live"] = live
+        return self.search_params.model_copy(update=update) if update else self.search_params
+
     async def _discover_venues_at(
-        self, lat, lng, radius, effective_limit: int, fetch_and_cache_live: bool
-    ) -> int:
+        self, lat, lng, radius, effective_limit: int, fetch_and_cache_live: bool,
+        region: Optional[str] = None, search_params: Optional[SearchParams] = None,
+    ) -> list[str]:
         """Upsert the venues one VenueFilter discovery call returns at a point,
-        returning the count. Builds the standard discovery params (busy_min=0,
-        foot_traffic=both, own_venues_only=False, VENUE_TYPES). Raises on failure
-        so the caller records its own zero gauge + context-specific error log.
+        returning their ids. The query comes from `search_params`, else
+        self.search_params (by default busy_min=0, foot_traffic=both,
+        own_venues_only=False, VENUE_TYPES). Raises
+        on failure so the caller records its own zero gauge + context-specific
+        error log.
+
+        With a filter tuner wired, the tuner's arm for `region` rescales the
+        radius/limit (never above effective_limit) and sets busy_min, and the
+        call is scored by how many returned venues have live data.
 
         Shared inner body of the discovery-point and location refresh loops; each
         caller keeps its distinct budget bookkeeping and log wording.
         """
-        params = VenueFilterParams(
-            busy_min=0,
-            lat=lat,
-            lng=lng,
-            radius=radius,
-            foot_traffic="both",
-            limit=effective_limit,
-            own_venues_only=False,
-            types=VENUE_TYPES,
-        )
+        search_params = search_params or self.search_params
+        if self.filter_tuner is None or region is None:
+            params = search_params.to_filter_params(lat, lng, radius, effective_limit)
+            return await self.discover_and_upsert_venues_via_filter(params, fetch_and_cache_live)
+
+        arm = self.filter_tuner.choose(region)
+        radius, limit = arm.apply(radius, effective_limit)
+        params = search_params.model_copy(
+            update={"busy_min": arm.busy_min}
+        ).to_filter_params(lat, lng, radius, min(limit, effective_limit))
         ids = await self.discover_and_upsert_venues_via_filter(params, fetch_and_cache_live)
-        return len(ids)
+        try:
+            live_venues = len(self.venue_dao.get_live_forecasts_bulk(ids)) if ids else 0
+        except Exception as e:
+            logger.warning(f"[VenuesRefresherService] Live count for tuning failed: {e}")
+            live_venues = 0
+        self.filter_tuner.record(region, arm, live_venues, estimate_credits(len(ids)))
+        return ids
+
+    async def _run_discovery_jobs(
+        self,
+        jobs: list["_DiscoveryJob"],
+        remaining_budget: int,
+        fetch_and_cache_live: bool,
+    ) -> list[LocationRefreshSummary]:
+        """Run one discovery call per job, up to `discovery_concurrency` at once.
+
+        The global budget (-1 = unlimited) is shared: a job reserves its
+        effective limit when it starts and gives back what it did not fetch
+        when it ends, so with concurrency 1 this is the plain sequential
+        countdown. Jobs that find the budget used up are skipped. Venue ids
+        are merged across jobs so `new_unique` counts each venue once per run.
+
+        Returns:
+            One summary per job, in job order
+        """
+        summaries = [
+            LocationRefreshSummary(
+                region=job.region, lat=job.lat, lng=job.lng, radius=job.radius, limit=0
+            )
+            for job in jobs
+        ]
+        budget = {"remaining": remaining_budget}
+        seen_ids: set[str] = set()
+        pending = iter(enumerate(jobs))
+
+        async def run(index: int, job: _DiscoveryJob) -> None:
+            summary = summaries[index]
+            effective_limit = job.limit
+            if budget["remaining"] >= 0:
+                effective_limit = min(effective_limit, budget["remaining"])
+                if effective_limit <= 0:
+                    summary.skipped = "budget"
+                    return
+                budget["remaining"] -= effective_limit
+            summary.limit = effective_limit
+            note(locations=1)
+            logger.info(
+                f"[VenuesRefresherService] {job.name}: lat={job.lat:.6f}, lng={job.lng:.6f}, "
+                f"radius={job.radius}, fetching up to {effective_limit}"
+            )
+            location_label = f"{job.lat:.4f},{job.lng:.4f}"
+            started = time.perf_counter()
+            try:
+                ids = await self._discover_venues_at(
+                    job.lat, job.lng, job.radius, effective_limit, fetch_and_cache_live,
+                    region=job.region, search_params=job.search_params,
+                )
+            except Exception as e:
+                logger.error(f"[VenuesRefresherService] {job.name} failed: {e}")
+                note_error(f"{job.name}: {e}")
+                REFRESH_VENUES_DISCOVERED.labels(location=location_label).set(0)
+                summary.error = str(e)
+                ids = []
+            finally:
+                summary.duration_seconds = round(time.perf_counter() - started, 3)
+            if budget["remaining"] >= 0:
+                budget["remaining"] += effective_limit - len(ids)
+            if summary.error is not None:
+                return
+            summary.fetched = len(ids)
+            summary.new_unique = len(set(ids) - seen_ids)
+            seen_ids.update(ids)
+            REFRESH_VENUES_DISCOVERED.labels(location=location_label).set(len(ids))
+            logger.info(f"[VenuesRefresherService] {job.name}: upserted {len(ids)} venues")
+
+        async def worker() -> None:
+            for index, job in pending:
+                await run(index, job)
+
+        workers = max(1, min(self.discovery_concurrency, len(jobs)))
+        await asyncio.gather(*(worker() for _ in range(workers)))
+        skipped = sum(1 for summary in summaries if summary.skipped == "budget")
+        if skipped:
+            logger.info(
+                f"[VenuesRefresherService] Global budget reached, skipped {skipped} locations"
+            )
+        return summaries
 
     async def _refresh_with_discovery_points(
         self,
@@ -759,16 +1403,12 @@ class VenuesRefresherService:
         fetch_and_cache_live: bool,
     ) -> int:
         """Refresh using admin-configured discovery points with per-point counters."""
-        total_inserted = 0
-        points_updated = False
-
+        self.last_discovery_summaries = []
+        jobs = []
         for point in points:
             point_id = point.get("id", "unknown")
             current = point.get("current", 0)
             limit = point.get("limit", 500)
-            lat = point.get("lat", 0)
-            lng = point.get("lng", 0)
-            radius = point.get("radius", 15000)
 
             headroom = limit - current
             if headroom <= 0:
@@ -781,46 +1421,30 @@ class VenuesRefresherService:
             effective_limit = headroom
             if self.fetch_venue_limit_override > 0:
                 effective_limit = min(effective_limit, self.fetch_venue_limit_override)
-            if remaining_budget >= 0:
-                effective_limit = min(effective_limit, remaining_budget)
-                if effective_limit <= 0:
-                    logger.info("[VenuesRefresherService] Global budget reached, skipping remaining")
-                    break
-
-            logger.info(
-                f"[VenuesRefresherService] Discovery point '{point_id}': "
-                f"lat={lat:.6f}, lng={lng:.6f}, radius={radius}, "
-                f"current={current}/{limit}, fetching up to {effective_limit}"
-            )
-
-            location_label = f"{lat:.4f},{lng:.4f}"
-            try:
-                fetched_count = await self._discover_venues_at(
-                    lat, lng, radius, effective_limit, fetch_and_cache_live
-                )
-                logger.info(
-                    f"[VenuesRefresherService] Discovery point '{point_id}': "
-                    f"upserted {fetched_count} venues"
-                )
-                REFRESH_VENUES_DISCOVERED.labels(location=location_label).set(fetched_count)
+            jobs.append(_DiscoveryJob(
+                name=f"Discovery point '{point_id}' ({current}/{limit})",
+                region=point_id,
+                lat=point.get("lat", 0),
+                lng=point.get("lng", 0),
+                radius=point.get("radius", 15000),
+                limit=effective_limit,
+                point=point,
+                search_params=self._location_search_params(point.get("types"), point.get("live")),
+            ))
+
+        summaries = await self._run_discovery_jobs(jobs, remaining_budget, fetch_and_cache_live)
+        self.last_discovery_summaries = summaries
 
-                point["current"] = current + fetched_count
+        points_updated = False
+        for job, summary in zip(jobs, summaries):
+            if summary.skipped is None and summary.error is None:
+                job.point["current"] = job.point.get("current", 0) + summary.fetched
                 points_updated = True
-                total_inserted += fetched_count
-                if remaining_budget >= 0:
-                    remaining_budget -= fetched_count
-            except Exception as e:
-                logger.error(
-                    f"[VenuesRefresherService] Discovery point '{point_id}' failed: {e}"
-                )
-                REFRESH_VENUES_DISCOVERED.labels(location=location_label).set(0)
-                continue
-
         if points_updated:
             self._save_discovery_points(points)
             logger.info("[VenuesRefresherService] Updated discovery point counters in Redis")
 
-        return total_inserted
+        return sum(summary.fetched for summary in summaries)
 
     async def _refresh_with_locations(
         self,
@@ -829,50 +1453,26 @@ class VenuesRefresherService:
         fetch_and_cache_live: bool,
     ) -> int:
         """Refresh using Location objects (legacy/dev mode path)."""
-        total_inserted = 0
-
+        jobs = []
         for loc in locations:
-            effective_limit = (
-                self.fetch_venue_limit_override if self.fetch_venue_limit_override > 0 else loc.limit
-            )
-            if remaining_budget >= 0:
-                effective_limit = min(effective_limit, remaining_budget)
-                if effective_limit <= 0:
-                    logger.info(
-                        f"[VenuesRefresherService] Global fetch_venue_total_limit "
-                        f"({self.fetch_venue_total_limit}) reached, skipping remaining"
-                    )
-                    break
-
-            logger.info(
-                f"[VenuesRefresherService] VenueFilter refresh at "
-                f"lat={loc.lat:.6f}, lng={loc.lng:.6f} "
-                f"(Radius={loc.radius}, Limit={effective_limit})"
-            )
-
-            location_label = f"{loc.lat:.4f},{loc.lng:.4f}"
-            try:
-                fetched_count = await self._discover_venues_at(
-                    loc.lat, loc.lng, loc.radius, effective_limit, fetch_and_cache_live
-                )
-                logger.info(
-                    f"[VenuesRefresherService] Successfully upserted {fetched_count} venues "
-                    f"for lat={loc.lat:.6f}, lng={loc.lng:.6f}"
-                )
-                REFRESH_VENUES_DISCOVERED.labels(location=location_label).set(fetched_count)
-                total_inserted += fetched_count
-                if remaining_budget >= 0:
-                    remaining_budget -= fetched_count
-            except Exception as e:
-                logger.error(
-                    f"[VenuesRefresherService] VenueFilter refresh failed for "
-                    f"lat={loc.lat:.6f}, lng={loc.lng:.6f}: {e}"
-                )
-                REFRESH_VENUES_DISCOVERED.labels(location=location_label).set(0)
-                continue
-
-        return total_inserted
-
+            location_label = loc.label or f"{loc.lat:.4f},{loc.lng:.4f}"
+            jobs.append(_DiscoveryJob(
+                name=f"VenueFilter refresh at {location_label}",
+                region=location_label,
+                lat=loc.lat,
+                lng=loc.lng,
+                radius=loc.radius,
+                limit=(
+                    self.fetch_venue_limit_override
+                    if self.fetch_venue_limit_override > 0 else loc.limit
+                ),
+                search_params=self._location_search_params(loc.types, loc.live),
+            ))
+        summaries = await self._run_discovery_jobs(jobs, remaining_budget, fetch_and_cache_live)
+        self.last_discovery_summaries = summaries
+        return sum(summary.fetched for summary in summaries)
+
+    @reported("inventory_sync")
     async def sync_account_inventory_to_redis(self) -> dict:
         """Pull every venue from BestTime /api/v1/venues into Redis.
 
@@ -880,9 +1480,10 @@ class VenuesRefresherService:
         Never increments the monthly new-venue counter — these venues are
         already in the BestTime account inventory and cost no credits.
 
-        Returns a summary dict with seen/upserted/skipped/errors counts.
+        Returns a summary dict with seen/upserted/skipped/errors counts;
+        `rejected` counts venues that failed validation (quarantined).
         """
-        summary = {"seen": 0, "upserted": 0, "skipped": 0, "errors": 0, "deprecated": 0}
+        summary = {"seen": 0, "upserted": 0, "skipped": 0, "errors": 0, "deprecated": 0, "rejected": 0}
         try:
             iterator = self.besttime_api.list_account_inventory()
         except Exception as e:
@@ -890,6 +1491,7 @@ class VenuesRefresherService:
                 f"[VenuesRefresherService] inventory list failed to start: {e}"
             )
             INVENTORY_SYNC_RUNS_TOTAL.labels(outcome="failed").inc()
+            note_error(f"inventory sync: {e}")
             return summary
 
         try:
@@ -906,18 +1508,16 @@ class VenuesRefresherService:
                         summary["skipped"] += 1
                         INVENTORY_SYNC_VENUES_TOTAL.labels(result="skipped").inc()
                         continue
-                    venue = Venue(
-                        processed=True,
-                        forecast=bool(inv.venue_forecasted),
-                        venue_id=inv.venue_id,
-                        venue_name=inv.venue_name or "",
-                        venue_address=inv.venue_address or "",
-                        venue_lat=float(inv.venue_lat or 0.0),
-                        venue_lng=float(inv.venue_lng or 0.0),
-                    )
+                    venue = venue_from_inventory(inv, refreshed_at=datetime.now(timezone.utc))
+                    venue = self.venue_validator.check(venue, source="inventory")
+                    if venue is None:
+                        summary["rejected"] += 1
+                        INVENTORY_SYNC_VENUES_TOTAL.labels(result="rejected").inc()
+                        continue
                     # Upserted active; ineligible venues are excluded by the
                     # serving view, not soft-deleted at write time.
                     self.venue_dao.upsert_venue(venue)
+                    self._record_venue_upserted(venue)
                     summary["upserted"] += 1
                     INVENTORY_SYNC_VENUES_TOTAL.labels(result="upserted").inc()
                 except Exception as e:
@@ -932,10 +1532,14 @@ class VenuesRefresherService:
                 f"[VenuesRefresherService] inventory sync iteration failed: {e}"
             )
             INVENTORY_SYNC_RUNS_TOTAL.labels(outcome="partial").inc()
+            note_error(f"inventory sync: {e}")
             return summary
 
         outcome = "ok" if summary["errors"] == 0 else "partial"
         INVENTORY_SYNC_RUNS_TOTAL.labels(outcome=outcome).inc()
+        note(venues_found=summary["seen"], upserted=summary["upserted"])
+        if summary["errors"]:
+            note_error(f"{summary['errors']} inventory venues failed", n=summary["errors"])
         logger.info(
             f"[VenuesRefresherService] inventory sync: seen={summary['seen']} "
             f"upserted={summary['upserted']} skipped={summary['skipped']} "
@@ -943,16 +1547,57 @@ class VenuesRefresherService:
         )
         return summary
 
+    @reported("venue_catalog")
+    async def refresh_venues_for_tenants(
+        self, tenants, fetch_and_cache_live: bool = False
+    ) -> list[LocationRefreshSummary]:
+        """Catalog refresh once per tenant (app/tenancy.py), or once when no
+        tenants are configured.
+
+        Each run uses the tenant's discovery_locations_file and
+        fetch_venue_total_limit where set, instead of the global ones, and
+        stops adding venues at the tenant's monthly_new_venue_quota. The
+        monthly BestTime budget is shared, so later tenants get what earlier
+        ones left.
+        """
+        if not tenants:
+            return await self.refresh_venues_by_filter_for_default_locations(fetch_and_cache_live)
+        saved = (self.locations_file, self.fetch_venue_total_limit, self.tenant_new_venue_quota)
+        summaries: list[LocationRefreshSummary] = []
+        try:
+            for tenant in tenants:
+                self.locations_file = tenant.discovery_locations_file or saved[0]
+                self.fetch_venue_total_limit = (
+                    tenant.fetch_venue_total_limit
+                    if tenant.fetch_venue_total_limit >= 0 else saved[1]
+                )
+                self.tenant_new_venue_quota = tenant.monthly_new_venue_quota
+                logger.info(f"[VenuesRefresherService] Catalog refresh for tenant {tenant.id}")
+                with tenant_scope(tenant.id):
+                    summaries.extend(
+                        await self.refresh_venues_by_filter_for_default_locations(fetch_and_cache_live)
+                    )
+        finally:
+            self.locations_file, self.fetch_venue_total_limit, self.tenant_new_venue_quota = saved
+        self.last_discovery_summaries = summaries
+        return summaries
+
+    @traced("VenuesRefresherService.refresh_venues_by_filter_for_default_locations")
     async def refresh_venues_by_filter_for_default_locations(
         self, fetch_and_cache_live: bool = False
-    ) -> None:
+    ) -> list[LocationRefreshSummary]:
         """Refresh venues for configured discovery points or default locations.
 
         Step 1: sync the full BestTime account inventory into Redis (no
                 credit cost; failure is logged but does not abort step 2).
         Step 2: discovery refresh via /venues/filter, respecting the
                 monthly new-venue cap and manual-add reserve.
+
+        Returns:
+            One summary per queried location / discovery point (empty when
+            discovery was skipped)
         """
+        self.last_discovery_summaries = []
         # Step 1: inventory sync (skip in dev_mode to keep per-iteration
         # latency low for local development).
         if not self.dev_mode:
@@ -969,7 +1614,7 @@ class VenuesRefresherService:
             logger.info(
                 "[VenuesRefresherService] fetch_venue_total_limit=0, skipping venue fetch"
             )
-            return
+            return []
 
         # Step 2: apply monthly cap on top of fetch_venue_total_limit.
         remaining_budget = self.fetch_venue_total_limit  # -1 means unlimited
@@ -984,11 +1629,24 @@ class VenuesRefresherService:
                     f"(discovery_effective_cap_remaining=0); skipping discovery"
                 )
                 DISCOVERY_SKIPPED_DUE_TO_MONTHLY_CAP_TOTAL.inc()
-                return
+                return []
             if remaining_budget < 0:
                 remaining_budget = monthly_remaining
             else:
                 remaining_budget = min(remaining_budget, monthly_remaining)
+            tenant_id = current_tenant()
+            if tenant_id and self.tenant_new_venue_quota >= 0:
+                tenant_remaining = self.budget_service.tenant_cap_remaining(
+                    tenant_id, self.tenant_new_venue_quota
+                )
+                if tenant_remaining <= 0:
+                    logger.warning(
+                        f"[VenuesRefresherService] tenant {tenant_id} monthly "
+                        f"new-venue quota reached; skipping discovery"
+                    )
+                    DISCOVERY_SKIPPED_DUE_TO_MONTHLY_CAP_TOTAL.inc()
+                    return []
+                remaining_budget = min(remaining_budget, tenant_remaining)
 
         # Dev mode: single location, no discovery points
         if self.dev_mode:
@@ -1007,37 +1665,66 @@ class VenuesRefresherService:
             total = await self._refresh_with_locations(locations, remaining_budget, fetch_and_cache_live)
             logger.info(f"[VenuesRefresherService] DEV MODE refresh done; total={total}")
             self.update_data_quality_metrics()
-            return
-
-        # Production: try discovery points from Redis, fall back to DEFAULT_LOCATIONS
-        discovery_points = self._get_discovery_points()
+            return self.last_discovery_summaries
+
+        # Production: discovery points from Redis, else the locations file,
+        # else the regions, else DEFAULT_LOCATIONS. Resolved every run, so
+        # changes need no restart.
+        source, locations = self.active_locations()
+        due = []
+        if source == "regions":
+            due = self.regions.due()
+            if not due:
+                logger.info("[VenuesRefresherService] No region due for discovery")
+                return []
+            logger.info(
+                f"[VenuesRefresherService] Regions due for discovery: "
+                f"{', '.join(r.id for r in due)}"
+            )
+            locations = self._region_locations(due)
 
-        if discovery_points:
+        if source == "admin":
             logger.info(
-                f"[VenuesRefresherService] Using {len(discovery_points)} discovery points from admin config"
+                f"[VenuesRefresherService] Using {len(locations)} discovery points from admin config"
             )
             total = await self._refresh_with_discovery_points(
-                discovery_points, remaining_budget, fetch_and_cache_live
+                locations, remaining_budget, fetch_and_cache_live
             )
         else:
             logger.info(
-                f"[VenuesRefresherService] No discovery points in admin config, "
-                f"falling back to {len(DEFAULT_LOCATIONS)} hardcoded locations"
+                f"[VenuesRefresherService] No discovery points in admin config, using "
+                f"{len(locations)} {'hardcoded' if source == 'default' else source} locations"
             )
             total = await self._refresh_with_locations(
-                DEFAULT_LOCATIONS, remaining_budget, fetch_and_cache_live
+                locations, remaining_budget, fetch_and_cache_live
             )
+        if due:
+            # Only regions whose every point was queried; the rest stay due.
+            failed = {
+                summary.region.split("/", 1)[0] for summary in self.last_discovery_summaries
+                if summary.error is not None or summary.skipped is not None
+            }
+            self.regions.mark_discovered([r.id for r in due if r.id not in failed])
 
         logger.info(
             f"[VenuesRefresherService] Finished VenueFilter refresh; "
-            f"total venues upserted={total}"
+            f"total venues upserted={total}, unique="
+            f"{sum(s.new_unique for s in self.last_discovery_summaries)}, failed locations="
+            f"{sum(1 for s in self.last_discovery_summaries if s.error is not None)}"
         )
         self.update_data_quality_metrics()
+        return self.last_discovery_summaries
 
-    async def refresh_live_forecasts_for_all_venues(self) -> None:
+    @reported("live_forecast")
+    @traced("VenuesRefresherService.refresh_live_forecasts_for_all_venues")
+    async def refresh_live_forecasts_for_all_venues(self, include_closed: bool = False) -> None:
         """Refresh live forecasts for all known venues.
 
         Implements logic from Go (lines 305-315).
+
+        Args:
+            include_closed: fetch venues closed right now too, overriding
+                live_skip_closed for this run
         """
         try:
             ids = self._select_refresh_venue_ids("live_forecast")
@@ -1045,6 +1732,11 @@ class VenuesRefresherService:
             logger.error(f"[VenuesRefresherService] live refresh selection failed: {e}")
             raise
 
+        ids = self._skip_recently_refreshed_live(ids)
+        if not include_closed:
+            ids = self._skip_closed_venues(ids)
+        note(venues_found=len(ids))
+
         logger.info(
             f"[VenuesRefresherService] Selected {len(ids)} venues; "
             "refreshing live forecasts."
@@ -1056,6 +1748,85 @@ class VenuesRefresherService:
         # Update data quality metrics after live refresh
         self.update_data_quality_metrics()
 
+        # Area crowd indexes follow the live values just written.
+        if self.area_index is not None:
+            try:
+                self.area_index.recompute()
+            except Exception as e:
+                logger.warning(f"[VenuesRefresherService] Area crowd index recompute failed: {e}")
+        # Deliveries run as a task of their own, not inside the refresh.
+        if self.webhooks is not None:
+            try:
+                self.webhooks.evaluate_in_background()
+            except Exception as e:
+                logger.warning(f"[VenuesRefresherService] Webhook evaluation failed: {e}")
+        if self.push_notifier is not None:
+            try:
+                await self.push_notifier.evaluate()
+            except Exception as e:
+                logger.warning(f"[VenuesRefresherService] Push notification evaluation failed: {e}")
+
+    def _skip_recently_refreshed_live(self, ids: list[str]) -> list[str]:
+        """Drop venues whose cached live forecast was refreshed within
+        live_refresh_cooldown_seconds. Cached entries without refreshed_at
+        (legacy) are never skipped; a failed read skips nothing."""
+        if self.live_refresh_cooldown_seconds <= 0 or not ids:
+            return ids
+        try:
+            cached = self.venue_dao.get_live_forecasts_bulk(ids)
+        except Exception as e:
+            logger.warning(
+                f"[VenuesRefresherService] Live cooldown read failed, refreshing all: {e}"
+            )
+            return ids
+        cutoff = datetime.now(timezone.utc).timestamp() - self.live_refresh_cooldown_seconds
+        recent = {
+            vid for vid, lf in cached.items()
+            if lf.refreshed_at is not None and lf.refreshed_at.timestamp() > cutoff
+        }
+        if recent:
+            LIVE_FORECAST_FETCH_RESULTS.labels(result="skipped_cooldown").inc(len(recent))
+            logger.info(
+                f"[VenuesRefresherService] Skipping {len(recent)} venues refreshed "
+                f"within the last {self.live_refresh_cooldown_seconds}s"
+            )
+        return [vid for vid in ids if vid not in recent]
+
+    def _skip_closed_venues(self, ids: list[str], now: Optional[datetime] = None) -> list[str]:
+        """Drop venues that are closed now according to their stored weekly
+        hours (venue_open_close_v2 of today and yesterday, Recife time).
+        Venues whose hours are unknown are kept; a failed read skips nothing."""
+        if not self.live_skip_closed or not ids:
+            return ids
+        now = now or recife_now()
+        # BestTime day_int and datetime.weekday() both count 0=Monday.
+        today = now.weekday()
+        try:
+            today_days = self.venue_dao.get_week_raw_forecasts_bulk(ids, today)
+            yesterday_days = self.venue_dao.get_week_raw_forecasts_bulk(ids, (today - 1) % 7)
+        except Exception as e:
+            logger.warning(
+                f"[VenuesRefresherService] Opening hours read failed, refreshing all: {e}"
+            )
+            return ids
+        minute = now.hour * 60 + now.minute
+        closed = {
+            vid for vid in ids
+            if open_at(
+                today_days.get(vid), yesterday_days.get(vid),
+                minute, self.live_open_margin_minutes,
+            ) is False
+        }
+        if closed:
+            LIVE_FORECAST_FETCH_RESULTS.labels(result="skipped_closed").inc(len(closed))
+            logger.info(
+                f"[VenuesRefresherService] Skipping {len(closed)} venues closed "
+                f"at {now:%a %H:%M}"
+            )
+        return [vid for vid in ids if vid not in closed]
+
+    @reported("weekly_forecast")
+    @traced("VenuesRefresherService.refresh_weekly_forecasts_for_all_venues")
     async def refresh_weekly_forecasts_for_all_venues(self) -> None:
         """Refresh weekly forecasts for all known venues.
 
@@ -1074,55 +1845,161 @@ class VenuesRefresherService:
         )
 
         total_cached = 0
-        for vid in ids:
-            if not self._ledger_allows_read(vid, "weekly_forecast"):
-                continue
-            logger.debug(
-                f"[VenuesRefresherService] Fetching weekly raw forecast for venue_id={vid}"
-            )
+        registry = self._crowd_registry()
+        try:
+            for vid in ids:
+                if not self._ledger_allows_read(vid, "weekly_forecast"):
+                    continue
+                if await self._fetch_and_cache_weekly(vid, registry):
+                    total_cached += 1
+        except (BestTimeCircuitOpenError, BestTimeAPIError) as e:
+            logger.warning(f"[VenuesRefresherService] Stopping weekly refresh: {e}")
+            note_error(f"weekly refresh stopped: {e}")
 
-            try:
-                resp = await self.besttime_api.get_week_raw_forecast(vid)
-            except Exception as e:
-                logger.error(
-                    f"[VenuesRefresherService] GetWeekRawForecast failed for {vid}: {e}"
-                )
-                WEEKLY_FORECAST_FETCH_RESULTS.labels(result="error").inc()
-                continue
+        REFRESH_VENUES_UPSERTED.labels(operation="weekly_forecast").set(total_cached)
+        note(venues_found=len(ids), weekly_cached=total_cached)
+        logger.info("[VenuesRefresherService] Finished weekly raw forecast refresh.")
 
-            if resp.status != "OK":
-                logger.warning(
-                    f"[VenuesRefresherService] Weekly raw forecast status non-OK "
-                    f"({resp.status}) for {vid}. Skipping cache."
-                )
-                WEEKLY_FORECAST_FETCH_RESULTS.labels(result="skipped_not_ok").inc()
-                continue
+        self._update_touched_gauge()
 
-            # Cache each day's raw forecast
-            cached_count = 0
-            for day in resp.analysis.week_raw:
-                try:
-                    self.venue_dao.set_week_raw_forecast(vid, day)
-                    cached_count += 1
-                except Exception as e:
-                    logger.error(
-                        f"[VenuesRefresherService] Failed to cache weekly raw forecast "
-                        f"for {vid} day {day.day_int}: {e}"
-                    )
+        # Update data quality metrics after weekly refresh
+        self.update_data_quality_metrics()
 
-            if cached_count > 0:
-                WEEKLY_FORECAST_FETCH_RESULTS.labels(result="cached").inc()
-                total_cached += 1
+    @reported("weekend_prefetch")
+    async def prefetch_weekend_forecasts(
+        self, days: list[int], regions: "list[Region] | tuple" = ()
+    ) -> dict:
+        """Make sure the weekend's weekly-forecast days are cached before it starts.
+
+        Scheduled on Thursdays: Friday and Saturday are the highest-traffic
+        product days, and a venue whose stored week misses either of them
+        (expired, failed Sunday refresh, added mid-week) would serve no
+        forecast all weekend. Selects venues with the same priority planner
+        as the live/weekly refresh, keeps those inside any of `regions`
+        (nightlife areas; all selected venues when none are given), and
+        fetches the full week only for venues missing one of `days`.
+        BestTime reads go through the monthly ledger like any other refresh.
 
-            logger.info(
-                f"[VenuesRefresherService] Successfully cached {cached_count} of "
-                f"{len(resp.analysis.week_raw)} raw days for {vid}"
-            )
+        Args:
+            days: BestTime day_ints to require (Fri, Sat = [4, 5])
+            regions: nightlife regions; empty keeps every selected venue
 
-        REFRESH_VENUES_UPSERTED.labels(operation="weekly_forecast").set(total_cached)
-        logger.info("[VenuesRefresherService] Finished weekly raw forecast refresh.")
+        Returns:
+            Counts: selected, in_regions, already_cached, fetched, failed,
+            skipped_monthly_cap
+        """
+        ids = self._select_refresh_venue_ids("weekend_prefetch")
+        summary = {"selected": len(ids)}
+        if regions:
+            locations = {
+                v.venue_id: (v.venue_lat, v.venue_lng)
+                for v in self.venue_dao.list_all_venues()
+            }
+            ids = [
+                vid for vid in ids
+                if vid in locations and any(r.contains(*locations[vid]) for r in regions)
+            ]
+        summary["in_regions"] = len(ids)
 
+        missing = set()
+        for day_int in days:
+            cached = self.venue_dao.get_week_raw_forecasts_bulk(ids, day_int)
+            missing.update(vid for vid in ids if vid not in cached)
+        counts = {"already_cached": len(ids) - len(missing), "fetched": 0, "failed": 0, "skipped_monthly_cap": 0}
+
+        registry = self._crowd_registry()
+        try:
+            for vid in ids:
+                if vid not in missing:
+                    continue
+                if not self._ledger_allows_read(vid, "weekend_prefetch"):
+                    counts["skipped_monthly_cap"] += 1
+                elif await self._fetch_and_cache_weekly(vid, registry):
+                    counts["fetched"] += 1
+                else:
+                    counts["failed"] += 1
+        except (BestTimeCircuitOpenError, BestTimeAPIError) as e:
+            logger.warning(f"[VenuesRefresherService] Stopping weekend prefetch: {e}")
+            note_error(f"weekend prefetch stopped: {e}")
+        note(venues_found=len(missing), weekly_cached=counts["fetched"])
+        for result, n in counts.items():
+            WEEKEND_PREFETCH_VENUES_TOTAL.labels(result=result).inc(n)
+        summary.update(counts)
+
+        logger.info(f"[VenuesRefresherService] Weekend prefetch for days {days}: {summary}")
         self._update_touched_gauge()
+        return summary
 
-        # Update data quality metrics after weekly refresh
-        self.update_data_quality_metrics()
+    async def _fetch_and_cache_weekly(self, vid: str, registry) -> bool:
+        """Fetch one venue's weekly raw forecast and cache every valid day.
+
+        Returns:
+            True when at least one day was cached
+
+        Raises:
+            BestTimeCircuitOpenError: BestTime is failing fast; stop the run
+            BestTimeAPIError: an abort-class error (quota, key); stop the run
+        """
+        logger.debug(
+            f"[VenuesRefresherService] Fetching weekly raw forecast for venue_id={vid}"
+        )
+
+        try:
+            resp = await registry.get_week_raw_forecast(vid)
+        except BestTimeCircuitOpenError:
+            raise  # the caller stops the whole run
+        except BestTimeAPIError as e:
+            if e.action == BestTimeAPIError.ABORT:
+                raise
+            logger.error(
+                f"[VenuesRefresherService] GetWeekRawForecast failed for {vid}: {e}"
+            )
+            note_error(f"{vid}: {e}")
+            WEEKLY_FORECAST_FETCH_RESULTS.labels(
+                result="skipped_invalid_venue"
+                if e.kind == BestTimeAPIError.INVALID_VENUE else "error"
+            ).inc()
+            return False
+        except Exception as e:
+            logger.error(
+                f"[VenuesRefresherService] GetWeekRawForecast failed for {vid}: {e}"
+            )
+            note_error(f"{vid}: {e}")
+            WEEKLY_FORECAST_FETCH_RESULTS.labels(result="error").inc()
+            return False
+
+        if resp is None:
+            WEEKLY_FORECAST_FETCH_RESULTS.labels(result="skipped_no_provider").inc()
+            return False
+
+        if resp.status != "OK":
+            logger.warning(
+                f"[VenuesRefresherService] Weekly raw forecast status non-OK "
+                f"({resp.status}) for {vid}. Skipping cache."
+            )
+            WEEKLY_FORECAST_FETCH_RESULTS.labels(result="skipped_not_ok").inc()
+            return False
+
+        # Cache each day's raw forecast
+        cached_count = 0
+        for day in resp.analysis.week_raw:
+            day = self.busyness_validator.validate_week_day(day, "weekly")
+            if day is None:
+                continue
+            try:
+                self.venue_dao.set_week_raw_forecast(vid, day)
+                cached_count += 1
+            except Exception as e:
+                logger.error(
+                    f"[VenuesRefresherService] Failed to cache weekly raw forecast "
+                    f"for {vid} day {day.day_int}: {e}"
+                )
+
+        if cached_count > 0:
+            WEEKLY_FORECAST_FETCH_RESULTS.labels(result="cached").inc()
+
+        logger.info(
+            f"[VenuesRefresherService] Successfully cached {cached_count} of "
+            f"{len(resp.analysis.week_raw)} raw days for {vid}"
+        )
+        return cached_count > 0
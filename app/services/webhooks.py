@@ -0,0 +1,445 @@
+"""Busyness alert webhooks.
+
+Clients holding a webhook API key (settings.webhooks_api_keys, sent as
+`X-Webhook-Key`) register a URL with a rule (POST /v1/webhooks): one venue, or
+every venue within a radius of a point, reaching a busyness threshold. After
+each live refresh the refresher starts WebhookService.evaluate() as a task of
+its own (evaluate_in_background), which checks every rule against the live
+values and POSTs a JSON event for each venue that newly crossed its
+threshold, at most max_concurrency POSTs at a time. Rules are edge-triggered: a venue stays "above" (webhooks:above:{id})
+until it drops below again, so a busy evening sends one event, not one per
+refresh.
+
+Each event body is signed with the secret returned at registration
+(`X-Webhook-Signature: sha256=<hex HMAC of the body>`), which is also what the
+client presents (`X-Webhook-Secret`) to read or delete its webhook. A delivery
+that fails (network error or non-2xx) is queued on a RetryQueue of its own
+(namespace "webhooks:retry") and retried with exponential backoff at the next
+evaluations; every attempt is logged in webhooks:deliveries:{id}, newest first.
+
+The server POSTs to client-chosen URLs, so a URL whose host resolves to a
+loopback, private, link-local, reserved or cloud-metadata address is refused,
+both at registration and again before every POST (DNS may have changed since),
+and redirects are never followed. The POST connects to an address that check
+passed (the Host header and TLS SNI keep the URL's hostname), so a second DNS
+answer cannot swap in another one, and ignores proxies set in the environment.
+
+Layout:
+- `webhooks:v1`: hash webhook id -> Webhook JSON;
+- `webhooks:above:{id}`: set of venue ids at or over the threshold last time;
+- `webhooks:deliveries:{id}`: capped list of DeliveryAttempt JSON.
+"""
+from __future__ import annotations
+
+import asyncio
+import hashlib
+import hmac
+import ipaddress
+import json
+import logging
+import secrets
+import socket
+import uuid
+from datetime import datetime, timezone
+from typing import Callable, Optional
+from urllib.parse import urlparse
+
+import httpx
+from pydantic import BaseModel, Field, model_validator
+
+from app.metrics import WEBHOOK_DELIVERIES_TOTAL
+from app.services.live_freshness import fresh_live_busyness
+from app.services.retry_queue import RetryQueue
+
+logger = logging.getLogger(__name__)
+
+WEBHOOKS_KEY = "webhooks:v1"
+ABOVE_KEY_FORMAT = "webhooks:above:{}"
+DELIVERIES_KEY_FORMAT = "webhooks:deliveries:{}"
+DELIVERIES_MAX = 100
+RETRY_NAMESPACE = "webhooks:retry"
+WEBHOOK_DELIVERY = "webhook_delivery"
+EVENT_THRESHOLD_EXCEEDED = "busyness_threshold_exceeded"
+
+# Instance-metadata endpoints that are not caught as non-global addresses
+# (169.254.169.254 is link-local anyway; listed for clarity).
+METADATA_ADDRESSES = frozenset({"169.254.169.254", "fd00:ec2::254", "100.100.100.200"})
+
+
+class WebhookError(ValueError):
+    """A registration the service refuses (bad URL, limit reached)."""
+
+
+class WebhookAuthError(Exception):
+    """Unknown webhook or wrong X-Webhook-Secret, or a registration without a
+    valid X-Webhook-Key."""
+
+
+def resolve_host(host: str) -> list[str]:
+    """Every address `host` resolves to (blocking DNS lookup)."""
+    return sorted({info[4][0] for info in socket.getaddrinfo(host, None)})
+
+
+def is_blocked_address(address: str) -> bool:
+    """Whether the server must not connect to `address`: anything that is not
+    a global unicast address (loopback, private, link-local, shared, reserved,
+    unspecified), multicast, or a metadata endpoint."""
+    ip = ipaddress.ip_address(address.split("%", 1)[0])
+    if isinstance(ip, ipaddress.IPv6Address) and ip.ipv4_mapped is not None:
+        ip = ip.ipv4_mapped
+    return not ip.is_global or ip.is_multicast or str(ip) in METADATA_ADDRESSES
+
+
+class WebhookRule(BaseModel):
+    """Fire when a venue reaches `threshold`: one venue (`venue_id`) or any
+    venue within `radius_km` of (`lat`, `lon`)."""
+    threshold: int = Field(..., ge=1, le=100, description="Busyness percentage (1-100)")
+    venue_id: Optional[str] = None
+    lat: Optional[float] = Field(None, ge=-90, le=90)
+    lon: Optional[float] = Field(None, ge=-180, le=180)
+    radius_km: Optional[float] = Field(None, gt=0, le=50)
+
+    @model_validator(mode="after")
+    def _one_target(self):
+        point = (self.lat, self.lon, self.radius_km)
+        has_point = all(v is not None for v in point)
+        if any(v is not None for v in point) and not has_point:
+            raise ValueError("lat, lon and radius_km go together")
+        if bool(self.venue_id) == has_point:
+            raise ValueError("set either venue_id or lat/lon/radius_km")
+        return self
+
+
+class Webhook(BaseModel):
+    id: str
+    url: str
+    rule: WebhookRule
+    secret: str
+    created_at: datetime
+    # The client whose X-Webhook-Key registered it (settings.webhooks_api_keys).
+    client_id: Optional[str] = None
+
+
+class DeliveryAttempt(BaseModel):
+    delivery_id: str
+    venue_id: str
+    attempt: int  # 1 = the first POST
+    # "delivered", "retrying" (queued for another attempt) or "failed" (gave up)
+    status: str
+    status_code: Optional[int] = None
+    error: Optional[str] = None
+    at: datetime
+
+
+def sign(secret: str, body: bytes) -> str:
+    return "sha256=" + hmac.new(secret.encode(), body, hashlib.sha256).hexdigest()
+
+
+class WebhookService:
+    """Registers webhooks, evaluates their rules and delivers the events."""
+
+    def __init__(
+        self,
+        venue_dao,
+        redis_client,
+        max_webhooks: int = 1000,
+        require_https: bool = True,
+        timeout_seconds: float = 5.0,
+        max_attempts: int = 5,
+        backoff_seconds: float = 60.0,
+        api_keys: Optional[dict[str, str]] = None,
+        max_concurrency: int = 10,
+        http_client: Optional[httpx.AsyncClient] = None,
+        resolver: Callable[[str], list[str]] = resolve_host,
+    ):
+        """
+        Args:
+            venue_dao: the pipeline DAO, read for venues and live values
+            redis_client: raw Redis client (decode_responses=True)
+            max_webhooks: registrations accepted in total
+            require_https: refuse plain http:// URLs
+            timeout_seconds: per-POST timeout
+            max_attempts: failed retries before a delivery is given up
+            backoff_seconds: delay before the first retry; doubles per attempt
+            api_keys: X-Webhook-Key -> client id allowed to register (empty =
+                registration refused)
+            max_concurrency: POSTs in flight at once
+            http_client: injected client (tests); one is created otherwise
+            resolver: host -> addresses, for the destination check (tests)
+        """
+        self.venue_dao = venue_dao
+        self.redis = redis_client
+        self.max_webhooks = max_webhooks
+        self.require_https = require_https
+        self.api_keys = api_keys or {}
+        self.max_concurrency = max(1, max_concurrency)
+        self.resolver = resolver
+        self._evaluation: Optional[asyncio.Task] = None
+        self.retry_queue = RetryQueue(
+            redis_client, max_attempts=max_attempts, backoff_seconds=backoff_seconds,
+            max_backoff_seconds=backoff_seconds * 2 ** max_attempts, namespace=RETRY_NAMESPACE,
+        )
+        self.http = http_client or httpx.AsyncClient(timeout=timeout_seconds, trust_env=False)
+
+    async def close(self):
+        """Wait for a running evaluation, then close the HTTP client."""
+        if self._evaluation is not None and not self._evaluation.done():
+            await asyncio.gather(self._evaluation, return_exceptions=True)
+        await self.http.aclose()
+
+    # ── registrations ───────────────────────────────────────────────────────
+    def authenticate(self, api_key: Optional[str]) -> str:
+        """The client id for an X-Webhook-Key.
+
+        Raises:
+            WebhookAuthError: missing or unknown key
+        """
+        if api_key:
+            for known_key, client_id in self.api_keys.items():
+                if hmac.compare_digest(known_key.encode(), api_key.encode()):
+                    return client_id
+        raise WebhookAuthError("invalid webhook key")
+
+    def check_destination(self, url: str) -> list[str]:
+        """Refuse a URL the server must not POST to (see module docstring).
+        Returns the host's addresses, all of them public.
+
+        Raises:
+            WebhookError: bad scheme, unresolvable host or a blocked address
+        """
+        parsed = urlparse(url)
+        allowed = ("https",) if self.require_https else ("https", "http")
+        if parsed.scheme not in allowed or not parsed.hostname:
+            raise WebhookError(f"url must be an absolute {' or '.join(allowed)} URL")
+        host = parsed.hostname
+        try:
+            addresses = [str(ipaddress.ip_address(host))]
+        except ValueError:
+            try:
+                addresses = self.resolver(host)
+            except (OSError, UnicodeError) as e:
+                raise WebhookError(f"cannot resolve {host}: {e}") from e
+        if not addresses:
+            raise WebhookError(f"cannot resolve {host}")
+        if any(is_blocked_address(address) for address in addresses):
+            raise WebhookError(f"{host} resolves to a non-public address")
+        return addresses
+
+    def register(self, url: str, rule: WebhookRule, client_id: Optional[str] = None) -> Webhook:
+        """Store a new webhook; the returned one carries its secret. Resolves
+        the URL's host (blocking).
+
+        Raises:
+            WebhookError: bad or non-public URL, or max_webhooks reached
+        """
+        self.check_destination(url)
+        parsed = urlparse(url)
+        if self.redis.hlen(WEBHOOKS_KEY) >= self.max_webhooks:
+            raise WebhookError("webhook limit reached")
+        hook = Webhook(
+            id=uuid.uuid4().hex, url=url, rule=rule, client_id=client_id,
+            secret=secrets.token_urlsafe(24), created_at=datetime.now(timezone.utc),
+        )
+        self.redis.hset(WEBHOOKS_KEY, hook.id, hook.model_dump_json())
+        logger.info(f"[Webhooks] Registered {hook.id} -> {parsed.netloc}")
+        return hook
+
+    def get(self, webhook_id: str) -> Optional[Webhook]:
+        raw = self.redis.hget(WEBHOOKS_KEY, webhook_id)
+        return Webhook.model_validate_json(raw) if raw is not None else None
+
+    def list_all(self) -> list[Webhook]:
+        return [Webhook.model_validate_json(raw) for raw in self.redis.hvals(WEBHOOKS_KEY)]
+
+    def authorize(self, webhook_id: str, secret: Optional[str]) -> Webhook:
+        """The webhook when `secret` is its secret.
+
+        Raises:
+            WebhookAuthError: unknown id or wrong secret (not told apart)
+        """
+        hook = self.get(webhook_id)
+        if hook is None or not secret or not hmac.compare_digest(hook.secret, secret):
+            raise WebhookAuthError(webhook_id)
+        return hook
+
+    def delete(self, webhook_id: str) -> None:
+        """Forget the webhook, its state and its delivery log; queued retries
+        are dropped when they come due."""
+        pipe = self.redis.pipeline()
+        pipe.hdel(WEBHOOKS_KEY, webhook_id)
+        pipe.delete(ABOVE_KEY_FORMAT.format(webhook_id), DELIVERIES_KEY_FORMAT.format(webhook_id))
+        pipe.execute()
+
+    def deliveries(self, webhook_id: str, limit: int = 50) -> list[DeliveryAttempt]:
+        raws = self.redis.lrange(DELIVERIES_KEY_FORMAT.format(webhook_id), 0, limit - 1)
+        return [DeliveryAttempt.model_validate_json(raw) for raw in raws]
+
+    # ── evaluation ──────────────────────────────────────────────────────────
+    def evaluate_in_background(self) -> bool:
+        """Start evaluate() as its own task so deliveries never hold up the
+        live refresh; False (and nothing started) while the previous one is
+        still delivering."""
+        if self._evaluation is not None and not self._evaluation.done():
+            logger.warning("[Webhooks] Previous evaluation still running; skipped")
+            return False
+        self._evaluation = asyncio.create_task(self._evaluate_logged())
+        return True
+
+    async def _evaluate_logged(self) -> None:
+        try:
+            await self.evaluate()
+        except Exception as e:
+            logger.warning(f"[Webhooks] Evaluation failed: {e}")
+
+    async def evaluate(self, now: Optional[datetime] = None) -> dict:
+        """Check every rule and deliver the new events concurrently, then
+        retry the due failed deliveries. Returns counts: webhooks, events,
+        delivered, failed."""
+        # Lazy import: app.services' package init imports the DAO package.
+        from app.services.venue_eligibility import haversine_km
+
+        now = now or datetime.now(timezone.utc)
+        summary = {"webhooks": 0, "events": 0, "delivered": 0, "failed": 0}
+        hooks = self.list_all()
+        summary["webhooks"] = len(hooks)
+        events: list[tuple[Webhook, dict]] = []
+        if hooks:
+            venues = {v.venue_id: v for v in self.venue_dao.list_all_venues() if v.is_active()}
+            busyness = fresh_live_busyness(self.venue_dao, list(venues), now)
+            for hook in hooks:
+                rule = hook.rule
+                if rule.venue_id:
+                    candidates = [rule.venue_id]
+                else:
+                    candidates = [
+                        vid for vid in busyness
+                        if haversine_km(rule.lat, rule.lon, venues[vid].venue_lat, venues[vid].venue_lng)
+                        <= rule.radius_km
+                    ]
+                above = {vid for vid in candidates if busyness.get(vid, -1) >= rule.threshold}
+                for vid in sorted(above - self._above(hook.id)):
+                    venue = venues.get(vid)
+                    body = {
+                        "event": EVENT_THRESHOLD_EXCEEDED,
+                        "webhook_id": hook.id,
+                        "delivery_id": uuid.uuid4().hex,
+                        "venue_id": vid,
+                        "venue_name": venue.venue_name if venue is not None else None,
+                        "venue_busyness": busyness[vid],
+                        "threshold": rule.threshold,
+                        "observed_at": now.isoformat(),
+                    }
+                    events.append((hook, body))
+                self._set_above(hook.id, above)
+        summary["events"] = len(events)
+        semaphore = asyncio.Semaphore(self.max_concurrency)
+
+        async def deliver(hook: Webhook, body: dict) -> bool:
+            async with semaphore:
+                return await self._deliver(hook, body, attempt=1)
+
+        results = await asyncio.gather(*(deliver(hook, body) for hook, body in events))
+        summary["delivered"] += sum(results)
+        summary["failed"] += len(results) - sum(results)
+        retried = await self.process_retries()
+        summary["delivered"] += retried["delivered"]
+        summary["failed"] += retried["failed"]
+        if summary["events"] or retried["retried"]:
+            logger.info(f"[Webhooks] Evaluation: {summary}")
+        return summary
+
+    def _above(self, webhook_id: str) -> set[str]:
+        return set(self.redis.smembers(ABOVE_KEY_FORMAT.format(webhook_id)))
+
+    def _set_above(self, webhook_id: str, venue_ids: set[str]) -> None:
+        key = ABOVE_KEY_FORMAT.format(webhook_id)
+        pipe = self.redis.pipeline()
+        pipe.delete(key)
+        if venue_ids:
+            pipe.sadd(key, *venue_ids)
+        pipe.execute()
+
+    # ── delivery ────────────────────────────────────────────────────────────
+    async def _post(self, hook: Webhook, body: dict) -> tuple[Optional[int], Optional[str]]:
+        """POST the event; (status_code, error) with error None on a 2xx."""
+        try:
+            addresses = await asyncio.to_thread(self.check_destination, hook.url)
+        except WebhookError as e:
+            return None, f"refused: {e}"
+        url = httpx.URL(hook.url)
+        raw = json.dumps(body, separators=(",", ":")).encode()
+        try:
+            response = await self.http.post(
+                # Connect to the checked address, not whatever DNS says next.
+                url.copy_with(host=addresses[0]),
+                content=raw,
+                headers={
+                    "Host": url.netloc.decode(),
+                    "Content-Type": "application/json",
+                    "X-Webhook-Id": hook.id,
+                    "X-Webhook-Signature": sign(hook.secret, raw),
+                },
+                # The certificate is still verified against the hostname.
+                extensions={"sni_hostname": url.host},
+                # A redirect could point anywhere, including internal hosts.
+                follow_redirects=False,
+            )
+        except httpx.HTTPError as e:
+            return None, f"{type(e).__name__}: {e}"
+        if 200 <= response.status_code < 300:
+            return response.status_code, None
+        return response.status_code, f"HTTP {response.status_code}"
+
+    def _log_attempt(self, hook: Webhook, body: dict, attempt: int, status: str,
+                     status_code: Optional[int], error: Optional[str]) -> None:
+        record = DeliveryAttempt(
+            delivery_id=body["delivery_id"], venue_id=body["venue_id"], attempt=attempt,
+            status=status, status_code=status_code, error=error, at=datetime.now(timezone.utc),
+        )
+        key = DELIVERIES_KEY_FORMAT.format(hook.id)
+        pipe = self.redis.pipeline()
+        pipe.lpush(key, record.model_dump_json())
+        pipe.ltrim(key, 0, DELIVERIES_MAX - 1)
+        pipe.execute()
+        WEBHOOK_DELIVERIES_TOTAL.labels(result=status).inc()
+
+    async def _deliver(self, hook: Webhook, body: dict, attempt: int) -> bool:
+        """First delivery of an event; queued for retry when it fails."""
+        status_code, error = await self._post(hook, body)
+        if error is None:
+            self._log_attempt(hook, body, attempt, "delivered", status_code, None)
+            return True
+        self._log_attempt(hook, body, attempt, "retrying", status_code, error)
+        self.retry_queue.push(
+            WEBHOOK_DELIVERY, body["delivery_id"], error, {"webhook_id": hook.id, "body": body},
+        )
+        return False
+
+    async def process_retries(self, limit: int = 100) -> dict:
+        """Retry the due failed deliveries. Returns counts: retried,
+        delivered, failed (rescheduled or given up), dropped (webhook gone)."""
+        summary = {"retried": 0, "delivered": 0, "failed": 0, "dropped": 0}
+        semaphore = asyncio.Semaphore(self.max_concurrency)
+
+        async def retry(item) -> None:
+            hook = self.get((item.payload or {}).get("webhook_id", ""))
+            if hook is None:
+                self.retry_queue.succeeded(item)
+                summary["dropped"] += 1
+                return
+            summary["retried"] += 1
+            body = item.payload["body"]
+            attempt = item.attempts + 2
+            async with semaphore:
+                status_code, error = await self._post(hook, body)
+            if error is None:
+                self.retry_queue.succeeded(item)
+                self._log_attempt(hook, body, attempt, "delivered", status_code, None)
+                summary["delivered"] += 1
+                return
+            dead = self.retry_queue.failed(item, error)
+            self._log_attempt(hook, body, attempt, "failed" if dead else "retrying", status_code, error)
+            summary["failed"] += 1
+
+        await asyncio.gather(*(retry(item) for item in self.retry_queue.due(limit)))
+        return summary
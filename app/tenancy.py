@@ -0,0 +1,151 @@
+"""Tenants: separate cities or client apps served by one deployment.
+
+Each tenant (settings.tenants) gets its own Redis serving keyspace: while a
+tenant is current, GeoRedisClient prefixes every key and channel it touches
+with `t:<tenant id>:` (geo members stay unprefixed, they are resolved through
+the same client). The current tenant lives in a ContextVar, so it follows a
+request into its thread-pool and asyncio.to_thread calls:
+
+- HTTP: TenantMiddleware (app/middleware.py) picks it from the client's
+  X-API-Key; a request without a key gets settings.default_tenant. An
+  X-Tenant-Id header is only checked against the key's tenant, it never
+  selects one by itself. The admin, internal and partner routes run
+  unscoped: they authenticate on their own;
+- jobs: the catalog refresh runs once per tenant with that tenant's discovery
+  locations file, venue limit and monthly new-venue quota, and the Redis
+  projection writes each tenant's keyspace with only the venues inside its
+  `bbox`. The unprefixed keyspace is projected too, with every venue: gRPC,
+  the area crowd index, webhooks, push, backups and stale eviction read it.
+
+What is not partitioned: RDS (the system of record is shared; tenants are
+carved out of it by bbox), the BestTime account and its monthly budget (each
+tenant's quota only caps its share of it), and the services that keep their
+own keys on the raw Redis client (retry queue, refresh reports, admin config,
+webhooks, push, job locks, ...).
+
+With no tenants configured nothing is prefixed and the key layout is exactly
+the single-tenant one.
+"""
+from __future__ import annotations
+
+import re
+from contextlib import contextmanager
+from contextvars import ContextVar
+from typing import Iterator, Optional
+
+from pydantic import BaseModel, field_validator
+
+# Tenant ids end up inside Redis keys, so no ":" or spaces.
+TENANT_ID = re.compile(r"[A-Za-z0-9_-]+")
+
+_current: ContextVar[Optional[str]] = ContextVar("tenant", default=None)
+
+
+class Tenant(BaseModel):
+    """One entry of settings.tenants (the id is the mapping key)."""
+
+    id: str
+    # Client API keys (X-API-Key) that select this tenant.
+    api_keys: list[str] = []
+    # Discovery points for this tenant's catalog refresh; "" = the global ones.
+    discovery_locations_file: str = ""
+    # Cap on venues fetched per catalog refresh for this tenant; -1 = the
+    # global fetch_venue_total_limit.
+    fetch_venue_total_limit: int = -1
+    # New venues this tenant's discovery may add per calendar month, on top of
+    # the shared monthly budget; -1 = only the shared budget applies.
+    monthly_new_venue_quota: int = -1
+    # [min_lat, min_lng, max_lat, max_lng] of the venues projected into this
+    # tenant's keyspace; None = every servable venue.
+    bbox: Optional[list[float]] = None
+
+    @field_validator("bbox")
+    @classmethod
+    def _check_bbox(cls, bbox):
+        if bbox is not None and (
+            len(bbox) != 4 or bbox[0] > bbox[2] or bbox[1] > bbox[3]
+        ):
+            raise ValueError("bbox must be [min_lat, min_lng, max_lat, max_lng]")
+        return bbox
+
+    def contains(self, lat, lng) -> bool:
+        """Whether a venue at (lat, lng) belongs to this tenant."""
+        if self.bbox is None:
+            return True
+        if lat is None or lng is None:
+            return False
+        return self.bbox[0] <= lat <= self.bbox[2] and self.bbox[1] <= lng <= self.bbox[3]
+
+
+class UnknownTenantError(LookupError):
+    """The API key or tenant id names no configured tenant."""
+
+
+def current_tenant() -> Optional[str]:
+    """Id of the tenant the current request or job runs for, if any."""
+    return _current.get()
+
+
+@contextmanager
+def tenant_scope(tenant_id: Optional[str]) -> Iterator[None]:
+    """Run a block as `tenant_id` (None = unscoped)."""
+    token = _current.set(tenant_id)
+    try:
+        yield
+    finally:
+        _current.reset(token)
+
+
+def tenant_key(key: str) -> str:
+    """`key` in the current tenant's keyspace."""
+    tenant_id = _current.get()
+    return f"t:{tenant_id}:{key}" if tenant_id else key
+
+
+def strip_tenant_key(key: str) -> str:
+    """Inverse of tenant_key for keys read back from a SCAN."""
+    tenant_id = _current.get()
+    prefix = f"t:{tenant_id}:" if tenant_id else ""
+    return key[len(prefix):] if prefix and key.startswith(prefix) else key
+
+
+class TenantRegistry:
+    """The configured tenants, looked up by id or client API key."""
+
+    def __init__(self, tenants: dict[str, dict], default_tenant: str = ""):
+        self.tenants = {
+            tenant_id: Tenant(id=tenant_id, **config) for tenant_id, config in tenants.items()
+        }
+        self.default_tenant = default_tenant
+        self._by_api_key = {
+            key: tenant for tenant in self.tenants.values() for key in tenant.api_keys
+        }
+
+    def __bool__(self) -> bool:
+        return bool(self.tenants)
+
+    def __iter__(self) -> Iterator[Tenant]:
+        return iter(self.tenants.values())
+
+    def resolve(self, api_key: Optional[str] = None, tenant_id: Optional[str] = None) -> Tenant:
+        """The tenant of a request: the one its API key belongs to, else the
+        default. An explicit tenant id must name that same tenant; it never
+        selects another one without its key.
+
+        Raises:
+            UnknownTenantError: unknown API key, a tenant id other than the
+                key's (or the default's), or no key and no default tenant
+        """
+        if api_key:
+            tenant = self._by_api_key.get(api_key)
+            if tenant is None:
+                raise UnknownTenantError("unknown API key")
+        else:
+            tenant = self.tenants.get(self.default_tenant)
+            if tenant is None:
+                raise UnknownTenantError("no tenant selected (X-API-Key)")
+        if tenant_id and tenant_id != tenant.id:
+            if tenant_id not in self.tenants:
+                raise UnknownTenantError(f"unknown tenant {tenant_id}")
+            raise UnknownTenantError(f"tenant {tenant_id} requires its API key")
+        return tenant
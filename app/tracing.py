@@ -0,0 +1,95 @@
+"""OpenTelemetry tracing across the serving and refresh layers.
+
+Off by default (settings.tracing_enabled). When on, setup_tracing() installs
+an SDK TracerProvider that batches spans to an OTLP/HTTP exporter configured
+the standard OpenTelemetry way, through the environment:
+OTEL_EXPORTER_OTLP_ENDPOINT (or OTEL_EXPORTER_OTLP_TRACES_ENDPOINT),
+OTEL_EXPORTER_OTLP_HEADERS, OTEL_SERVICE_NAME (default "cs-server") and
+OTEL_RESOURCE_ATTRIBUTES.
+
+Spans:
+- one server span per HTTP request (app.middleware.TracingMiddleware),
+  continuing the caller's W3C `traceparent` when it sends one;
+- VenueHandler and VenuesRefresherService entry points (`@traced`);
+- every timed RedisVenueDAO operation (its `_timed` block);
+- every BestTime API call (BestTimeAPIClient._request).
+
+The active span travels in contextvars, so it follows awaits and
+asyncio.to_thread into the layers below without a context argument (note that
+loop.run_in_executor does not copy it). While tracing is off, the API's
+no-op tracer makes all of this nearly free.
+"""
+from __future__ import annotations
+
+import functools
+import inspect
+import logging
+import os
+from typing import Callable, Optional
+
+from opentelemetry import trace
+
+logger = logging.getLogger(__name__)
+
+tracer = trace.get_tracer("cs-server")
+
+_provider = None
+
+
+def setup_tracing(sample_ratio: float = 1.0) -> None:
+    """Install the SDK provider and OTLP exporter (once per process).
+
+    Args:
+        sample_ratio: share of new traces recorded (0-1); requests that arrive
+            with a sampled parent follow the parent's decision
+    """
+    global _provider
+    if _provider is not None:
+        return
+    from opentelemetry.exporter.otlp.proto.http.trace_exporter import OTLPSpanExporter
+    from opentelemetry.sdk.resources import Resource
+    from opentelemetry.sdk.trace import TracerProvider
+    from opentelemetry.sdk.trace.export import BatchSpanProcessor
+    from opentelemetry.sdk.trace.sampling import ParentBased, TraceIdRatioBased
+
+    resource = Resource.create(
+        {"service.name": os.environ.get("OTEL_SERVICE_NAME", "cs-server")}
+    )
+    _provider = TracerProvider(
+        resource=resource, sampler=ParentBased(TraceIdRatioBased(sample_ratio))
+    )
+    _provider.add_span_processor(BatchSpanProcessor(OTLPSpanExporter()))
+    trace.set_tracer_provider(_provider)
+    logger.info(f"[Tracing] OpenTelemetry tracing enabled (sample ratio {sample_ratio})")
+
+
+def shutdown_tracing() -> None:
+    """Flush buffered spans and stop the exporter (shutdown sequence)."""
+    global _provider
+    if _provider is not None:
+        _provider.shutdown()
+        _provider = None
+
+
+def traced(name: Optional[str] = None) -> Callable:
+    """Run the decorated function (sync or async) in a span named `name`
+    (default: its qualified name). Exceptions are recorded on the span."""
+
+    def decorator(func):
+        span_name = name or func.__qualname__
+        if inspect.iscoroutinefunction(func):
+            @functools.wraps(func)
+            async def async_wrapper(*args, **kwargs):
+                with tracer.start_as_current_span(span_name):
+                    return await func(*args, **kwargs)
+
+            return async_wrapper
+
+        @functools.wraps(func)
+        def wrapper(*args, **kwargs):
+            with tracer.start_as_current_span(span_name):
+                return func(*args, **kwargs)
+
+        return wrapper
+
+    return decorator
@@ -0,0 +1,57 @@
+"""Venue-local time and BestTime forecast indexes.
+
+Forecasts are indexed in the venue's local time: a weekly day_int (0=Monday)
+whose 24 `day_raw` values start at 6 AM, so 00:00-05:59 belongs to the
+previous day_int (indexes 18-23). A venue's timezone is BestTime's
+`venue_timezone` (e.g. "America/Recife"); venues without one are taken to be
+in Recife, the deployment's home timezone.
+
+These helpers convert an absolute instant to a venue's local time and to the
+(day_int, hour index) that covers it, so clients in other timezones can ask
+with timestamps instead of doing the conversion themselves.
+"""
+from __future__ import annotations
+
+from datetime import datetime, timedelta, timezone, tzinfo
+from typing import Optional
+
+import pytz
+
+from app.utils.recife_time import RECIFE_TZ
+
+BESTTIME_DAY_START_HOUR = 6
+
+
+def venue_tz(timezone_name: Optional[str]) -> tzinfo:
+    """The venue's timezone; Recife when the name is empty or unknown."""
+    if timezone_name:
+        try:
+            return pytz.timezone(timezone_name)
+        except pytz.UnknownTimeZoneError:
+            pass
+    return RECIFE_TZ
+
+
+def venue_local_time(timezone_name: Optional[str], instant: datetime) -> datetime:
+    """`instant` in the venue's timezone; a naive instant is taken as UTC."""
+    if instant.tzinfo is None:
+        instant = instant.replace(tzinfo=timezone.utc)
+    return instant.astimezone(venue_tz(timezone_name))
+
+
+def business_day_int(local: datetime) -> int:
+    """BestTime day_int whose day_raw covers `local` (venue-local time)."""
+    return (local - timedelta(hours=BESTTIME_DAY_START_HOUR)).weekday()
+
+
+def forecast_slot(local: datetime) -> tuple[int, int]:
+    """(day_int, day_raw index) covering `local` (venue-local time)."""
+    return business_day_int(local), (local.hour - BESTTIME_DAY_START_HOUR) % 24
+
+
+def slot_for_local_hour(day_int: int, hour: int) -> tuple[int, int]:
+    """(day_int, day_raw index) of clock hour `hour` (0-23) on calendar weekday
+    `day_int`: hours before 6 AM live at the end of the previous day_int."""
+    if hour < BESTTIME_DAY_START_HOUR:
+        return (day_int - 1) % 7, hour + 24 - BESTTIME_DAY_START_HOUR
+    return day_int, hour - BESTTIME_DAY_START_HOUR
@@ -8,8 +8,10 @@ Startup is split into two phases for zero-downtime deploys:
 """
 import asyncio
 import logging
+import os
 import time
 from contextlib import asynccontextmanager
+from datetime import datetime, timezone
 
 from fastapi import FastAPI
 from fastapi.responses import PlainTextResponse
@@ -18,10 +20,21 @@ from apscheduler.triggers.interval import IntervalTrigger
 from apscheduler.triggers.cron import CronTrigger
 from prometheus_client import generate_latest, CONTENT_TYPE_LATEST
 
-from app.config import Settings
+from app import __version__
+from app.config import ConfigError, Settings
 from app.container import Container
-from app.routers import venue_router, set_venue_handler, debug_router, set_debug_dependencies, admin_trigger_router, set_admin_container, engagement_router, set_engagement_service, internal_router, set_internal_container
-from app.middleware import PrometheusMiddleware
+from app.dao import redis_migrations
+from app.routers import venue_router, set_venue_handler, set_public_stats_service, set_nearby_precompute, set_area_crowd_index, set_regions, set_checkin_service, set_venue_suggestion_service, debug_router, set_debug_dependencies, admin_trigger_router, set_admin_container, running_admin_jobs, engagement_router, set_engagement_service, set_push_notifier, set_recommendation_service, internal_router, set_internal_container, partner_router, set_partner_service, webhook_router, set_webhook_service
+from app.middleware import DemoRateLimitMiddleware, PrometheusMiddleware, RequestTimeoutMiddleware, TenantMiddleware, TracingMiddleware
+from app.log_control import RequestLogContextMiddleware, install_log_control, log_control
+from app.log_format import install_log_format
+from app.diagnostics import DiagnosticsServer
+from app.openapi import OPENAPI_TAGS, install_docs
+from app.tenancy import TenantRegistry, tenant_scope
+from app.tracing import setup_tracing, shutdown_tracing
+from app import config as app_config
+from app.services.holiday_calendar import holiday_live_refresh_minutes
+from app.services.runtime_config import RuntimeConfigService
 from app.services.refresh_interval_watch import (
     WATCH_INTERVAL_SECONDS,
     RefreshIntervalWatcher,
@@ -34,7 +47,10 @@ from app.metrics import (
     REDIS_PROJECTION_VENUES,
     REDIS_PROJECTION_DEPRECATED_REMOVED_TOTAL,
 )
-from app.services import job_lock
+from app.services import job_lock, scheduler_control
+from app.services.besttime_quota import quota_exceeded
+from app import sd_notify
+from app.services.crowd_providers import Region
 
 # Configure logging
 logging.basicConfig(
@@ -51,6 +67,12 @@ logger = logging.getLogger(__name__)
 # Global container and scheduler
 container: Container = None
 scheduler: AsyncIOScheduler = None
+watchdog_task: "asyncio.Task | None" = None
+diagnostics: "DiagnosticsServer | None" = None
+grpc_server = None
+# Scheduled job runs in flight, cancelled on shutdown so a long refresh aborts
+# its BestTime calls instead of outliving the process's resources.
+running_job_tasks: "set[asyncio.Task]" = set()
 
 
 def make_job(
@@ -65,6 +87,7 @@ def make_job(
     require_container: bool = False,
     on_success=None,
     lock_name: "str | None" = None,
+    quota_gated: bool = False,
 ):
     """Build a scheduler-job coroutine with the shared instrumentation skeleton.
 
@@ -97,27 +120,52 @@ def make_job(
             (app/services/job_lock.py) key for this job. An admin trigger of
             the same job_name currently running holds this same name; if held,
             this run is skipped entirely (no log-start, no metrics) instead of
-            doubling the paid BestTime/Google calls for the cycle. Released in
-            a finally so a failed/disabled run never leaves it stuck.
+            doubling the paid BestTime/Google calls for the cycle; with
+            distributed_job_lock_enabled the same holds across replicas.
+            Released in a finally so a failed/disabled run never leaves it stuck.
+        quota_gated: when True, skip (warn + ``skipped_quota`` run metric) while
+            the day's BestTime credit budget is spent
+            (app/services/besttime_quota.py) — for jobs that can wait a day.
+
+    Every run that gets past the lock is reported to
+    app/services/scheduler_control.py (start, outcome, error) under
+    ``job_name``, which is also the job's APScheduler id.
     """
+    if lock_name is not None:
+        scheduler_control.register_lock(job_name, lock_name)
+
     async def _job():
         if require_container and container is None:
             return
         if lock_name is not None and not job_lock.try_acquire(lock_name):
             logger.warning(
                 f"[Scheduler] {error_label} skipped: '{lock_name}' already "
-                "running (admin trigger in progress)"
+                "running (admin trigger or another replica)"
             )
             JOB_LOCK_REJECTED_TOTAL.labels(job_name=lock_name, source="scheduler").inc()
             return
+        task = asyncio.current_task()
+        running_job_tasks.add(task)
+        if lock_name is not None:
+            job_lock.bind_task(lock_name, task)
+        scheduler_control.run_started(job_name, task)
+        outcome, error = "skipped", None
         try:
             logger.info(start_log)
             start_time = time.perf_counter()
             if service_attr is not None and getattr(container, service_attr) is None:
                 logger.warning(disabled_log)
                 return
+            if quota_gated and quota_exceeded(getattr(container, "besttime_quota", None)):
+                logger.warning(
+                    f"[Scheduler] {error_label} skipped: daily BestTime credit budget spent"
+                )
+                BACKGROUND_JOB_RUNS_TOTAL.labels(job_name=job_name, status="skipped_quota").inc()
+                outcome = "skipped_quota"
+                return
             try:
                 result = await run(container)
+                outcome = "success"
                 duration = time.perf_counter() - start_time
                 BACKGROUND_JOB_DURATION_SECONDS.labels(job_name=job_name).observe(duration)
                 BACKGROUND_JOB_RUNS_TOTAL.labels(job_name=job_name, status="success").inc()
@@ -125,12 +173,20 @@ def make_job(
                 if on_success is not None:
                     on_success(result)
                 logger.info(done_log(result) if callable(done_log) else done_log)
+            except asyncio.CancelledError:
+                outcome = "cancelled"
+                BACKGROUND_JOB_RUNS_TOTAL.labels(job_name=job_name, status="cancelled").inc()
+                logger.warning(f"[Scheduler] {error_label} cancelled")
+                raise
             except Exception as e:
+                outcome, error = "error", str(e)
                 duration = time.perf_counter() - start_time
                 BACKGROUND_JOB_DURATION_SECONDS.labels(job_name=job_name).observe(duration)
                 BACKGROUND_JOB_RUNS_TOTAL.labels(job_name=job_name, status="error").inc()
                 logger.error(f"[Scheduler] {error_label} failed: {e}")
         finally:
+            running_job_tasks.discard(task)
+            scheduler_control.run_finished(job_name, outcome, error)
             if lock_name is not None:
                 job_lock.release(lock_name)
 
@@ -147,13 +203,18 @@ def schedule(
     name: str,
     enabled_log: str,
     disabled_log: "str | None" = None,
+    run_now: bool = False,
 ) -> None:
     """Add a scheduled job or log why it stayed off — the add-or-log-disabled
     block repeated for every optional pipeline. Always-on jobs pass
     ``enabled=True`` and omit ``disabled_log`` (it is never emitted). The job
-    id/name and log messages are unchanged from the inline blocks."""
+    id/name and log messages are unchanged from the inline blocks.
+    ``run_now`` also fires the job once right after the scheduler starts."""
     if enabled:
-        scheduler.add_job(func, trigger=trigger, id=id, name=name, replace_existing=True)
+        extra = {"next_run_time": datetime.now(timezone.utc)} if run_now else {}
+        scheduler.add_job(
+            func, trigger=trigger, id=id, name=name, replace_existing=True, **extra
+        )
         logger.info(enabled_log)
     else:
         logger.info(disabled_log)
@@ -164,9 +225,11 @@ run_venue_catalog_refresh_job = make_job(
     start_log="[Scheduler] Running VenueFilterMultiLocationJob",
     done_log="[Scheduler] VenueFilterMultiLocationJob completed",
     error_label="VenueFilterMultiLocationJob",
-    run=lambda c: c.venues_refresher_service.refresh_venues_by_filter_for_default_locations(
-        fetch_and_cache_live=True
+    run=lambda c: c.venues_refresher_service.refresh_venues_for_tenants(
+        c.tenants, fetch_and_cache_live=True
     ),
+    lock_name=job_lock.VENUE_CATALOG,
+    quota_gated=True,
 )
 
 
@@ -187,6 +250,7 @@ run_weekly_forecast_refresh_job = make_job(
     error_label="WeeklyForecastRefreshJob",
     run=lambda c: c.venues_refresher_service.refresh_weekly_forecasts_for_all_venues(),
     lock_name=job_lock.WEEKLY_FORECAST,
+    quota_gated=True,
 )
 
 
@@ -241,6 +305,28 @@ run_ig_posts_enrichment_job = make_job(
 )
 
 
+run_open_data_enrichment_job = make_job(
+    "open_data_enrichment",
+    start_log="[Scheduler] Running OpenDataEnrichmentJob",
+    done_log=lambda summary: f"[Scheduler] OpenDataEnrichmentJob completed: {summary}",
+    error_label="OpenDataEnrichmentJob",
+    service_attr="open_data_enrichment_service",
+    disabled_log="[Scheduler] OpenDataEnrichmentJob skipped: no open data source configured",
+    run=lambda c: c.open_data_enrichment_service.enrich_all_venues(),
+)
+
+
+run_venue_media_enrichment_job = make_job(
+    "venue_media_enrichment",
+    start_log="[Scheduler] Running VenueMediaEnrichmentJob",
+    done_log=lambda summary: f"[Scheduler] VenueMediaEnrichmentJob completed: {summary}",
+    error_label="VenueMediaEnrichmentJob",
+    service_attr="venue_media_service",
+    disabled_log="[Scheduler] VenueMediaEnrichmentJob skipped: venue media not enabled",
+    run=lambda c: c.venue_media_service.enrich_all_venues(),
+)
+
+
 run_menu_photo_enrichment_job = make_job(
     "menu_photo_enrichment",
     start_log="[Scheduler] Running MenuPhotoEnrichmentJob",
@@ -277,6 +363,106 @@ run_vibe_classifier_job = make_job(
 )
 
 
+run_history_export_job = make_job(
+    "history_export",
+    start_log="[Scheduler] Running HistoryExportJob",
+    done_log=lambda summary: f"[Scheduler] HistoryExportJob completed: {summary}",
+    error_label="HistoryExportJob",
+    service_attr="history_export_service",
+    disabled_log="[Scheduler] HistoryExportJob skipped: history export not configured",
+    run=lambda c: c.history_export_service.export_day(),
+)
+
+
+run_backup_job = make_job(
+    "backup",
+    start_log="[Scheduler] Running BackupJob",
+    done_log=lambda summary: f"[Scheduler] BackupJob completed: {summary}",
+    error_label="BackupJob",
+    service_attr="backup_service",
+    disabled_log="[Scheduler] BackupJob skipped: backups not configured",
+    run=lambda c: c.backup_service.backup(),
+    lock_name=job_lock.BACKUP,
+)
+
+
+run_weekend_prefetch_job = make_job(
+    "weekend_prefetch",
+    start_log="[Scheduler] Running WeekendPrefetchJob",
+    done_log=lambda summary: f"[Scheduler] WeekendPrefetchJob completed: {summary}",
+    error_label="WeekendPrefetchJob",
+    run=lambda c: c.venues_refresher_service.prefetch_weekend_forecasts(
+        days=c.settings.weekend_prefetch_days,
+        regions=[Region(**r) for r in c.settings.weekend_prefetch_regions],
+    ),
+    # Shares the weekly refresh's guard: both write the same weekly keys.
+    lock_name=job_lock.WEEKLY_FORECAST,
+    quota_gated=True,
+)
+
+
+run_nearby_snapshot_job = make_job(
+    "nearby_snapshot",
+    start_log="[Scheduler] Running NearbySnapshotJob",
+    done_log=lambda n: f"[Scheduler] NearbySnapshotJob completed: {n} venues",
+    error_label="NearbySnapshotJob",
+    service_attr="nearby_snapshot",
+    disabled_log="[Scheduler] NearbySnapshotJob skipped: snapshot disabled",
+    # Blocking Redis reads; keep them off the serving event loop.
+    run=lambda c: asyncio.to_thread(c.nearby_snapshot.refresh),
+)
+
+
+run_stale_eviction_job = make_job(
+    "stale_eviction",
+    start_log="[Scheduler] Running StaleEvictionJob",
+    done_log=lambda summary: f"[Scheduler] StaleEvictionJob completed: "
+    f"{summary.venues_evicted} venues, {summary.live_deleted} live forecasts"
+    f"{' (dry run)' if summary.dry_run else ''}",
+    error_label="StaleEvictionJob",
+    # Blocking DAO reads/writes; keep them off the serving event loop.
+    run=lambda c: asyncio.to_thread(c.stale_eviction_service.run),
+)
+
+
+run_venue_dedup_job = make_job(
+    "venue_dedup",
+    start_log="[Scheduler] Running VenueDedupJob",
+    done_log=lambda summary: f"[Scheduler] VenueDedupJob completed: "
+    f"{summary.venues_merged} venues merged in {summary.clusters} clusters"
+    f"{' (dry run)' if summary.dry_run else ''}",
+    error_label="VenueDedupJob",
+    # Blocking DAO reads/writes; keep them off the serving event loop.
+    run=lambda c: asyncio.to_thread(c.venue_deduplicator.run),
+    lock_name=job_lock.VENUE_DEDUP,
+)
+
+
+run_retry_queue_job = make_job(
+    "retry_queue",
+    start_log="[Scheduler] Running RetryQueueJob",
+    done_log=lambda summary: f"[Scheduler] RetryQueueJob completed: {summary}",
+    error_label="RetryQueueJob",
+    service_attr="retry_queue",
+    disabled_log="[Scheduler] RetryQueueJob skipped: retry queue disabled",
+    run=lambda c: c.venues_refresher_service.process_retry_queue(
+        limit=c.settings.retry_queue_batch_size
+    ),
+)
+
+
+run_event_outbox_job = make_job(
+    "event_outbox",
+    start_log="[Scheduler] Running EventOutboxJob",
+    done_log=lambda summary: f"[Scheduler] EventOutboxJob completed: {summary}",
+    error_label="EventOutboxJob",
+    service_attr="event_relay",
+    disabled_log="[Scheduler] EventOutboxJob skipped: event broker not configured",
+    run=lambda c: c.event_relay.drain(),
+    lock_name=job_lock.EVENT_OUTBOX,
+)
+
+
 async def _project_redis_from_rds(c) -> dict:
     """Run the projection body OFF the serving event loop (B0): it is synchronous
     + blocking (SQLAlchemy + Redis); running it inline on the AsyncIOScheduler
@@ -284,9 +470,30 @@ async def _project_redis_from_rds(c) -> dict:
     projector removes venues deprecated in RDS (B1) and counts the photo cache
     TTL down (B2). It is the sole Redis writer for pipeline data."""
     loop = asyncio.get_event_loop()
-    return await loop.run_in_executor(
-        None, c.redis_projection_service.rebuild_redis_from_rds
-    )
+    if c.tenants:
+        # One projection per tenant, each into its own keyspace, plus the
+        # unprefixed one (app/tenancy.py).
+        summary = await loop.run_in_executor(
+            None, c.redis_projection_service.rebuild_redis_for_tenants, c.tenants
+        )
+    else:
+        summary = await loop.run_in_executor(
+            None, c.redis_projection_service.rebuild_redis_from_rds
+        )
+    # The serving data just changed: re-render the precomputed nearby answers.
+    if c.nearby_precompute is not None:
+        for tenant_id in [t.id for t in c.tenants] + [None]:
+            try:
+                await loop.run_in_executor(None, _in_tenant, tenant_id, c.nearby_precompute.refresh)
+            except Exception as e:
+                logger.warning(f"[Scheduler] Nearby precompute failed: {e}")
+    return summary
+
+
+def _in_tenant(tenant_id, fn):
+    """Call fn as `tenant_id`; run_in_executor does not carry the context over."""
+    with tenant_scope(tenant_id):
+        return fn()
 
 
 def _record_projection_metrics(summary: dict) -> None:
@@ -378,6 +585,7 @@ def start_background_jobs(settings: Settings):
         redis_client=container.redis_client,
         scheduler=scheduler,
         default_minutes=settings.venues_live_refresh_minutes,
+        holiday_minutes=holiday_live_refresh_minutes,
     )
     schedule(
         scheduler,
@@ -457,6 +665,36 @@ def start_background_jobs(settings: Settings):
         ),
     )
 
+    # OpenStreetMap / Foursquare enrichment (only if enabled)
+    schedule(
+        scheduler,
+        enabled=settings.open_data_enrichment_enabled,
+        func=run_open_data_enrichment_job,
+        trigger=CronTrigger.from_crontab(settings.open_data_enrichment_cron),
+        id="open_data_enrichment",
+        name="Open Data Enrichment (Weekly)",
+        enabled_log=(
+            f"[Scheduler] Scheduled open data enrichment with cron: "
+            f"{settings.open_data_enrichment_cron}"
+        ),
+        disabled_log="[Scheduler] Open data enrichment disabled (OPEN_DATA_ENRICHMENT_ENABLED=false)",
+    )
+
+    # Venue media enrichment (only if enabled)
+    schedule(
+        scheduler,
+        enabled=settings.venue_media_enabled,
+        func=run_venue_media_enrichment_job,
+        trigger=CronTrigger.from_crontab(settings.venue_media_enrichment_cron),
+        id="venue_media_enrichment",
+        name="Venue Media Enrichment (Weekly)",
+        enabled_log=(
+            f"[Scheduler] Scheduled venue media enrichment with cron: "
+            f"{settings.venue_media_enrichment_cron}"
+        ),
+        disabled_log="[Scheduler] Venue media enrichment disabled (VENUE_MEDIA_ENABLED=false)",
+    )
+
     # Job 7: Menu photo enrichment (only if enabled and configured)
     schedule(
         scheduler,
@@ -537,11 +775,196 @@ def start_background_jobs(settings: Settings):
         ),
     )
 
+    # Job 12: Nightly analytics export of the previous day's busyness history
+    # and catalog deltas (only if enabled and S3 is configured)
+    schedule(
+        scheduler,
+        enabled=container.history_export_service is not None,
+        func=run_history_export_job,
+        trigger=CronTrigger.from_crontab(settings.history_export_cron),
+        id="history_export",
+        name="Analytics History Export (Nightly)",
+        enabled_log=(
+            f"[Scheduler] Scheduled history export with cron: "
+            f"{settings.history_export_cron}"
+        ),
+        disabled_log=(
+            "[Scheduler] History export disabled "
+            "(HISTORY_EXPORT_ENABLED=false or missing S3 credentials)"
+        ),
+    )
+
+    # Job 13: Thursday prefetch of the weekend's weekly-forecast days for
+    # nightlife regions (only if enabled)
+    schedule(
+        scheduler,
+        enabled=settings.weekend_prefetch_enabled,
+        func=run_weekend_prefetch_job,
+        trigger=CronTrigger.from_crontab(settings.weekend_prefetch_cron),
+        id="weekend_prefetch",
+        name="Weekend Forecast Prefetch (Thursday)",
+        enabled_log=(
+            f"[Scheduler] Scheduled weekend forecast prefetch with cron: "
+            f"{settings.weekend_prefetch_cron}"
+        ),
+        disabled_log="[Scheduler] Weekend forecast prefetch disabled (WEEKEND_PREFETCH_ENABLED=false)",
+    )
+
+    # Job 14: Last-known-good nearby snapshot for Redis outages (only if
+    # enabled); first filled right at startup.
+    schedule(
+        scheduler,
+        enabled=container.nearby_snapshot is not None,
+        func=run_nearby_snapshot_job,
+        trigger=IntervalTrigger(minutes=settings.nearby_snapshot_refresh_minutes),
+        id="nearby_snapshot",
+        name="Nearby Snapshot Refresh",
+        enabled_log=(
+            f"[Scheduler] Scheduled nearby snapshot refresh every "
+            f"{settings.nearby_snapshot_refresh_minutes} minutes"
+        ),
+        disabled_log="[Scheduler] Nearby snapshot disabled (NEARBY_SNAPSHOT_ENABLED=false)",
+        run_now=True,
+    )
+
+    # Job 15: Eviction of venues and live forecasts no refresh touches any
+    # more (only if enabled)
+    schedule(
+        scheduler,
+        enabled=settings.stale_eviction_enabled,
+        func=run_stale_eviction_job,
+        trigger=CronTrigger.from_crontab(settings.stale_eviction_cron),
+        id="stale_eviction",
+        name="Stale Venue / Live Forecast Eviction",
+        enabled_log=(
+            f"[Scheduler] Scheduled stale eviction with cron: "
+            f"{settings.stale_eviction_cron}"
+            f"{' (dry run)' if settings.stale_eviction_dry_run else ''}"
+        ),
+        disabled_log="[Scheduler] Stale eviction disabled (STALE_EVICTION_ENABLED=false)",
+    )
+
+    # Job 16: Retries of failed venue upserts / live fetches (only if enabled)
+    schedule(
+        scheduler,
+        enabled=container.retry_queue is not None,
+        func=run_retry_queue_job,
+        trigger=IntervalTrigger(minutes=settings.retry_queue_interval_minutes),
+        id="retry_queue",
+        name="Retry Queue Worker",
+        enabled_log=(
+            f"[Scheduler] Scheduled retry queue worker every "
+            f"{settings.retry_queue_interval_minutes} minutes"
+        ),
+        disabled_log="[Scheduler] Retry queue disabled (RETRY_QUEUE_ENABLED=false)",
+    )
+
+    # Job 17: Venue events from the outbox to the broker (only if configured)
+    schedule(
+        scheduler,
+        enabled=container.event_relay is not None,
+        func=run_event_outbox_job,
+        trigger=IntervalTrigger(seconds=settings.events_publish_interval_seconds),
+        id="event_outbox",
+        name="Event Outbox Publisher",
+        enabled_log=(
+            f"[Scheduler] Scheduled {settings.events_broker} event publishing every "
+            f"{settings.events_publish_interval_seconds} seconds"
+        ),
+        disabled_log="[Scheduler] Event publishing disabled (EVENTS_BROKER empty)",
+    )
+
+    # Job 18: Venue data snapshots to S3/GCS (only if enabled)
+    schedule(
+        scheduler,
+        enabled=container.backup_service is not None,
+        func=run_backup_job,
+        trigger=CronTrigger.from_crontab(settings.backup_cron),
+        id="backup",
+        name="Venue Data Backup",
+        enabled_log=f"[Scheduler] Scheduled venue data backups with cron: {settings.backup_cron}",
+        disabled_log="[Scheduler] Venue data backups disabled (BACKUP_ENABLED=false)",
+    )
+
+    # Job 19: Cleanup of fuzzy duplicate venues (only if enabled)
+    schedule(
+        scheduler,
+        enabled=settings.dedup_enabled,
+        func=run_venue_dedup_job,
+        trigger=CronTrigger.from_crontab(settings.dedup_cleanup_cron),
+        id="venue_dedup",
+        name="Duplicate Venue Cleanup",
+        enabled_log=(
+            f"[Scheduler] Scheduled duplicate venue cleanup with cron: "
+            f"{settings.dedup_cleanup_cron}"
+            f"{' (dry run)' if settings.dedup_cleanup_dry_run else ''}"
+        ),
+        disabled_log="[Scheduler] Duplicate venue cleanup disabled (DEDUP_ENABLED=false)",
+    )
+
     # Start scheduler
     scheduler.start()
+    # Pause/resume/run-now/stop and run status via /admin/scheduler.
+    container.scheduler_control = scheduler_control.SchedulerControl(scheduler)
+    start_runtime_config(settings, refresh_interval_watcher)
     logger.info("[Scheduler] Background jobs started")
 
 
+def start_runtime_config(settings: Settings, refresh_interval_watcher: RefreshIntervalWatcher):
+    """Hot reload of the reloadable settings (GET/PUT /admin/runtime-config),
+    checked every config_reload_seconds."""
+    refresher = container.venues_refresher_service
+
+    def reschedule(job_id: str, trigger) -> None:
+        if scheduler.get_job(job_id) is not None:
+            scheduler.reschedule_job(job_id, trigger=trigger)
+
+    def live_minutes(minutes: int) -> None:
+        refresh_interval_watcher.set_default(minutes)
+        refresh_interval_watcher.check_once()
+
+    container.runtime_config = RuntimeConfigService(
+        settings,
+        container.redis_client.client,
+        appliers={
+            "venues_live_refresh_minutes": live_minutes,
+            "venues_catalog_refresh_minutes": lambda v: reschedule(
+                "venue_catalog_refresh", IntervalTrigger(minutes=v)
+            ),
+            "weekly_forecast_cron": lambda v: reschedule(
+                "weekly_forecast_refresh", CronTrigger.from_crontab(v)
+            ),
+            "besttime_rate_per_second": lambda v: container.besttime_api.set_rate_limit(
+                rate_per_second=v
+            ),
+            "besttime_rate_burst": lambda v: container.besttime_api.set_rate_limit(burst=v),
+            "live_fetch_concurrency": lambda v: setattr(
+                refresher, "live_fetch_concurrency", max(1, v)
+            ),
+            "discovery_concurrency": lambda v: setattr(
+                refresher, "discovery_concurrency", max(1, v)
+            ),
+            "fetch_venue_total_limit": lambda v: setattr(refresher, "fetch_venue_total_limit", v),
+            "discovery_locations_file": lambda v: setattr(refresher, "locations_file", v),
+            "log_level": log_control.set_level,
+        },
+        mirrors=(globals()["settings"], app_config.settings),
+    )
+    schedule(
+        scheduler,
+        enabled=settings.config_reload_seconds > 0,
+        func=container.runtime_config.run,
+        trigger=IntervalTrigger(seconds=max(1, settings.config_reload_seconds)),
+        id="runtime_config_reload",
+        name="Runtime Config Reload",
+        enabled_log=(
+            f"[Scheduler] Scheduled runtime config reload every "
+            f"{settings.config_reload_seconds} seconds"
+        ),
+        disabled_log="[Scheduler] Runtime config reload disabled (CONFIG_RELOAD_SECONDS=0)",
+    )
+
+
 async def startup_essential(settings: Settings):
     """Essential initialization — must complete before serving requests.
 
@@ -559,6 +982,12 @@ async def startup_essential(settings: Settings):
     # Inject handler into router (routes already registered at app creation)
     logger.info("[Main] Injecting handler into router")
     set_venue_handler(container.venue_handler)
+    set_public_stats_service(container.public_stats_service)
+    set_nearby_precompute(container.nearby_precompute)
+    set_area_crowd_index(container.area_crowd_index)
+    set_regions(container.regions)
+    set_checkin_service(container.checkin_service)
+    set_venue_suggestion_service(container.venue_suggestion_service)
     logger.info("[Main] Handler injected successfully")
 
     # Inject dependencies for debug router
@@ -569,16 +998,33 @@ async def startup_essential(settings: Settings):
 
     # Inject engagement service (favorites/hot_likes write-through API)
     set_engagement_service(container.engagement_service)
+    set_push_notifier(container.push_notifier)
+    set_recommendation_service(container.recommendation_service)
 
     # Inject container for the internal on-demand photo-resolve router.
     set_internal_container(container)
 
+    # Partner occupancy ingestion (503 until partner keys are configured).
+    set_partner_service(container.partner_occupancy_service)
+
+    # Busyness alert webhooks (503 until webhooks_enabled).
+    set_webhook_service(container.webhook_service)
+
+    # Bring the Redis key layouts up to the current schema version before any
+    # reader touches them (no-op when already current). A failure is logged and
+    # serving continues on whatever layout is present.
+    loop = asyncio.get_event_loop()
+    if settings.redis_migrate_on_startup:
+        try:
+            await loop.run_in_executor(None, redis_migrations.migrate, container.redis_client.client)
+        except Exception as e:
+            logger.error(f"[Main] Redis schema migration failed: {e}")
+
     # Rebuild the eligibility serving mirror from its rows so a Redis flush before
     # this start does not leave filtering on the hardcoded defaults. Runs OFF the
     # event loop (blocking SQLAlchemy read, same pattern as the projector) so it
     # cannot stall the loop, and is degrade-safe; the periodic projector re-asserts
     # it thereafter.
-    loop = asyncio.get_event_loop()
     await loop.run_in_executor(None, container.eligibility_rule_service.rehydrate_mirror)
 
     logger.info("[Main] Essential startup completed — server is ready to serve")
@@ -601,22 +1047,116 @@ async def startup_background_pipelines(settings: Settings):
     )
 
 
+def _internal_health_check() -> bool:
+    """The systemd watchdog condition: the scheduler runs and Redis answers."""
+    if scheduler is None or not scheduler.running:
+        return False
+    return bool(container.redis_client.client.ping())
+
+
+def notify_systemd_ready(settings: Settings) -> None:
+    """Tell systemd we are up (READY=1) and start the watchdog pings when the
+    unit asks for them. No-op outside a Type=notify unit."""
+    global watchdog_task
+    if not settings.systemd_notify_enabled:
+        return
+    if not sd_notify.notify(f"READY=1\nSTATUS=Serving\nMAINPID={os.getpid()}"):
+        return
+    logger.info("[Main] Notified systemd: READY")
+    interval = sd_notify.watchdog_interval_seconds()
+    if interval:
+        watchdog_task = asyncio.create_task(
+            sd_notify.run_watchdog(interval, _internal_health_check)
+        )
+        logger.info(f"[Main] systemd watchdog pings every {interval:.1f}s")
+
+
+def start_diagnostics(settings: Settings):
+    """Serve /debug/vars and /debug/pprof on the internal diagnostics port."""
+    global diagnostics
+    if settings.diagnostics_port <= 0:
+        return
+
+    def jobs() -> list[dict]:
+        if container is None or container.scheduler_control is None:
+            return []
+        return container.scheduler_control.list_jobs()
+
+    diagnostics = DiagnosticsServer(
+        settings.diagnostics_host,
+        settings.diagnostics_port,
+        jobs=jobs,
+        tracemalloc_frames=settings.diagnostics_tracemalloc_frames,
+    )
+    diagnostics.start()
+
+
+async def start_grpc(settings: Settings):
+    """Serve the gRPC VenueService next to HTTP (settings.grpc_enabled). A
+    failure is logged and HTTP keeps serving."""
+    global grpc_server
+    if not settings.grpc_enabled:
+        return
+    try:
+        from app.db.redis_factory import build_async_redis_client
+        from app.grpc_api.server import GrpcServer
+
+        grpc_server = GrpcServer(
+            container.venue_handler,
+            build_async_redis_client(settings),
+            settings.grpc_host,
+            settings.grpc_port,
+            settings.grpc_api_keys,
+            tls_certfile=settings.server_tls_certfile,
+            tls_keyfile=settings.server_tls_keyfile,
+        )
+        await grpc_server.start()
+    except Exception as e:
+        grpc_server = None
+        logger.error(f"[Main] gRPC server not started: {e}")
+
+
 async def shutdown_sequence():
     """Clean up resources on shutdown."""
-    global container, scheduler
+    global container, scheduler, watchdog_task, diagnostics, grpc_server
 
     logger.info("[Main] Starting shutdown sequence")
+    if settings.systemd_notify_enabled:
+        sd_notify.notify("STOPPING=1\nSTATUS=Draining")
+    if watchdog_task is not None:
+        watchdog_task.cancel()
+        watchdog_task = None
 
     if scheduler:
         logger.info("[Main] Stopping scheduler")
         scheduler.shutdown(wait=False)
         logger.info("[Main] Scheduler stopped")
 
+    # Cancel refreshes still in flight (scheduled or admin-triggered) before
+    # the container closes the clients they are using.
+    in_flight = [t for t in running_job_tasks | set(running_admin_jobs()) if not t.done()]
+    if in_flight:
+        logger.info(f"[Main] Cancelling {len(in_flight)} running job(s)")
+        for task in in_flight:
+            task.cancel()
+        await asyncio.wait(in_flight, timeout=settings.shutdown_job_grace_seconds)
+
+    if grpc_server is not None:
+        await grpc_server.stop()
+        grpc_server = None
+
+    if diagnostics is not None:
+        await diagnostics.stop()
+        diagnostics = None
+
     if container:
         logger.info("[Main] Shutting down container")
         await container.shutdown()
         logger.info("[Main] Container shut down")
 
+    # Flush the spans still buffered by the batch exporter.
+    shutdown_tracing()
+
     logger.info("[Main] Shutdown sequence completed")
 
 
@@ -629,6 +1169,29 @@ async def lifespan(app: FastAPI):
     Redis data can be served without delay.
     """
     settings = Settings()
+    # Fail fast on settings the server cannot run with (missing BestTime keys,
+    # bad ports or enum values), naming every problem at once.
+    try:
+        settings.check()
+    except ConfigError as e:
+        logger.critical(f"[Main] {e}")
+        raise
+
+    if settings.demo_mode:
+        # Demo: synthetic catalog only — no container (Redis/RDS), no jobs.
+        from app.handlers import VenueHandler
+        from app.services.demo_data import DemoVenueDAO
+
+        logger.info("[Main] DEMO MODE: serving the synthetic catalog; no jobs, no stores")
+        set_venue_handler(VenueHandler(DemoVenueDAO(
+            settings.demo_center_lat,
+            settings.demo_center_lng,
+            count=settings.demo_venue_count,
+            spread_km=settings.demo_spread_km,
+        )))
+        yield
+        await shutdown_sequence()
+        return
 
     # Phase 1: Essential init (blocking) — server won't accept requests until done
     await startup_essential(settings)
@@ -637,11 +1200,16 @@ async def lifespan(app: FastAPI):
     # stays gated off). This is the ONLY on-start scheduling path.
     logger.info("[Main] Starting periodic jobs")
     start_background_jobs(settings)
+    start_diagnostics(settings)
+    await start_grpc(settings)
 
     # Phase 3: No pipeline runs on startup by design (log-only no-op). Refresh and
     # enrichment happen via the scheduled cron jobs above or admin-panel triggers.
     await startup_background_pipelines(settings)
 
+    # Phase 4: Warm-up done — report readiness to systemd (bare-metal only).
+    notify_systemd_ready(settings)
+
     yield  # ← Server is now accepting requests
 
     # Shutdown
@@ -650,33 +1218,83 @@ async def lifespan(app: FastAPI):
 
 # Create FastAPI app
 settings = Settings()
+# Global level from settings, changeable at runtime (per module, and with
+# targeted debug) via the /admin/logging endpoints; text or JSON output.
+install_log_control(settings.log_level)
+try:
+    install_log_format(settings.log_format)
+except ValueError as e:
+    logger.warning(f"[Main] {e}; keeping text logs")
+if settings.tracing_enabled:
+    setup_tracing(settings.tracing_sample_ratio)
+# OpenAPI document at /openapi.json and Swagger UI at /docs, with its assets
+# served from this process (app/openapi.py); openapi_enabled=false serves
+# neither.
 app = FastAPI(
     title="CS-Server API",
     description="Venue discovery and crowd tracking service",
-    version="1.0.0",
+    version=__version__,
     lifespan=lifespan,
+    openapi_tags=OPENAPI_TAGS,
+    openapi_url="/openapi.json" if settings.openapi_enabled else None,
+    docs_url=None,
+    redoc_url=None,
 )
+if settings.openapi_enabled:
+    install_docs(app)
+
+# Tenant selection (app/tenancy.py), innermost so every other middleware
+# still sees the 401s it returns.
+if settings.tenants:
+    app.add_middleware(
+        TenantMiddleware, registry=TenantRegistry(settings.tenants, settings.default_tenant)
+    )
+
+# Demo-mode rate limit, added first so the metrics middleware (outermost) still
+# counts the 429s it returns.
+if settings.demo_mode:
+    app.add_middleware(DemoRateLimitMiddleware, per_minute=settings.demo_rate_limit_per_minute)
+
+# 504 for handlers slower than server_request_timeout_seconds (0 = no limit),
+# inside the metrics and tracing middleware so they record the 504.
+if settings.server_request_timeout_seconds > 0:
+    app.add_middleware(RequestTimeoutMiddleware, seconds=settings.server_request_timeout_seconds)
+
+# Request path/coordinates for path- and region-targeted debug logging.
+app.add_middleware(RequestLogContextMiddleware)
+
+# One server span per request, continuing the caller's traceparent.
+if settings.tracing_enabled:
+    app.add_middleware(TracingMiddleware)
 
 # Add Prometheus metrics middleware
 app.add_middleware(PrometheusMiddleware)
 
-# Register routers at app creation time (before uvicorn starts)
+# Register routers at app creation time (before uvicorn starts). Demo mode only
+# exposes the public venue routes.
 app.include_router(venue_router)
-app.include_router(debug_router)
-app.include_router(admin_trigger_router)
-app.include_router(engagement_router)
-app.include_router(internal_router)
+if not settings.demo_mode:
+    app.include_router(debug_router)
+    app.include_router(admin_trigger_router)
+    app.include_router(engagement_router)
+    app.include_router(internal_router)
+    app.include_router(partner_router)
+    app.include_router(webhook_router)
 
 
 # Health check endpoint
-@app.get("/health")
+@app.get("/health", tags=["ops"])
 def health():
-    """Health check endpoint."""
-    return {"status": "healthy"}
+    """Health check endpoint. Stays "healthy" while BestTime is down (serving
+    only reads Redis); the BestTime circuit breaker state is reported alongside."""
+    body = {"status": "healthy", "environment": settings.environment}
+    if container is not None:
+        body["besttime_circuit"] = container.besttime_api.circuit.snapshot()
+    return body
 
 
 # Prometheus metrics endpoint
-@app.get("/metrics", response_class=PlainTextResponse)
+@app.get("/metrics", response_class=PlainTextResponse, tags=["ops"])
 def metrics():
     """Prometheus metrics endpoint for scraping."""
     return PlainTextResponse(
@@ -686,12 +1304,8 @@ def metrics():
 
 
 if __name__ == "__main__":
-    import uvicorn
-
-    logger.info("[Main] Starting CS-Server")
-    uvicorn.run(
-        "main:app",
-        host="0.0.0.0",
-        port=settings.server_port,
-        log_level=settings.log_level.lower(),
-    )
+    import sys
+
+    from app.cli import main as cli_main
+
+    sys.exit(cli_main())
@@ -11,6 +11,7 @@ Location: Recife, Brazil (-8.060090, -34.889501)
 
 import requests
 import json
+import os
 import time
 from typing import List, Dict, Optional
 from dataclasses import dataclass, asdict
@@ -19,8 +20,8 @@ import pandas as pd
 
 # Configuration
 BESTTIME_ENDPOINT_BASE_V1 = "https://besttime.app/api/v1"
-BESTTIME_PRIVATE_KEY = "pri_aff50a71a038456db88864b16d9d6800"
-BESTTIME_PUBLIC_KEY = "pub_4f4f184e1a5f4f50a48e945fde7ab2ea"
+BESTTIME_PRIVATE_KEY = os.environ["BESTTIME_PRIVATE_KEY"]
+BESTTIME_PUBLIC_KEY = os.environ["BESTTIME_PUBLIC_KEY"]
 
 # Location: Recife, Brazil
 LOCATION_LAT = -8.060090
@@ -0,0 +1,37 @@
+Feature: Read-your-writes for admin writes
+  An operator who passes ?verify=true on an admin write gets a success only
+  once the change reads back from where it is served: the Redis mirror (and
+  the RDS row behind it) for config, notes and eligibility rules, and RDS for
+  the geo-fence. A write that does not read back answers 502 so the operator
+  retries instead of trusting a change nobody will see.
+
+  Scenario: A verified config write reports that it was read back
+    When an operator writes the config "scoring_weights" with verification
+    Then the admin write answers 200
+    And the admin write is reported as verified
+
+  Scenario: A verified note write is in the serving mirror before the response
+    When an operator writes a public note "Closed until July" on "v1" with verification
+    Then the admin write answers 200
+    And the admin write is reported as verified
+    And the Redis mirror serves the note "Closed until July" on "v1"
+
+  Scenario: A verified note write fails when the Redis mirror drops it
+    Given the Redis mirror acknowledges writes without storing them
+    When an operator writes a public note "Closed until July" on "v1" with verification
+    Then the admin write answers 502
+
+  Scenario: A verified eligibility rule is in the serving mirror before the response
+    When an operator adds the eligibility rule "blocked_google_type" "casino" with verification
+    Then the admin write answers 200
+    And the Redis eligibility mirror blocks the google type "casino"
+
+  Scenario: A verified geo-fence write reads the fence back from RDS
+    When an operator sets the geo-fence to "recife" at 30 km with verification
+    Then the admin write answers 200
+    And the admin write is reported as verified
+
+  Scenario: A verified geo-fence write fails when RDS does not keep it
+    Given RDS acknowledges geo-fence writes without storing them
+    When an operator sets the geo-fence to "recife" at 45 km with verification
+    Then the admin write answers 502
@@ -0,0 +1,41 @@
+Feature: Busyness alert webhooks
+  A client holding a webhook key registers a URL with a threshold rule and
+  receives a signed JSON event when a venue reaches that busyness. The server
+  must only ever POST to public addresses, must send one event per crossing
+  rather than one per refresh, and must keep each webhook private to the
+  holder of its secret.
+
+  Background:
+    Given a webhook client key "client-key" for client "app"
+    And a venue "bar" whose live busyness is 40
+
+  Scenario: A venue crossing the threshold sends one signed event
+    Given a webhook is registered with key "client-key" for venue "bar" at threshold 80
+    When the live busyness of "bar" becomes 85 and webhooks are evaluated twice
+    Then the webhook receiver gets 1 event for venue "bar"
+    And the event is signed with the webhook's secret
+    And the webhook's delivery log shows 1 "delivered" attempt
+
+  Scenario: Registration without a valid webhook key is refused
+    When a webhook is registered with key "wrong-key" for venue "bar" at threshold 80
+    Then the webhook API answers 401
+
+  Scenario: A URL that resolves to a private address is refused
+    Given webhook hosts resolve to "10.0.0.5"
+    When a webhook is registered with key "client-key" for venue "bar" at threshold 80
+    Then the webhook API answers 422
+
+  Scenario: A webhook whose host is rebound to a private address receives nothing
+    Given a webhook is registered with key "client-key" for venue "bar" at threshold 80
+    And webhook hosts resolve to "127.0.0.1"
+    When the live busyness of "bar" becomes 85 and webhooks are evaluated
+    Then the webhook receiver gets 0 events for venue "bar"
+    And the webhook's latest delivery is refused
+
+  Scenario: Only the holder of the secret can read or delete a webhook
+    Given a webhook is registered with key "client-key" for venue "bar" at threshold 80
+    When the webhook is read without its secret
+    Then the webhook API answers 404
+    When the webhook is deleted with its secret
+    Then the webhook API answers 204
+    And reading the webhook with its secret answers 404
@@ -0,0 +1,24 @@
+Feature: Covered regions
+  Clients ask which regions the server covers before they query nearby venues
+  there. GET /v1/regions lists every configured region with its bounding box
+  [min_lat, min_lng, max_lat, max_lng] and its discovery circles, and never
+  exposes the internal discovery settings.
+
+  Scenario: No configured regions lists nothing
+    Given no regions are configured
+    When a client lists the covered regions
+    Then the regions API answers 200
+    And 0 regions are listed
+
+  Scenario: A region with an explicit bounding box is listed as configured
+    Given the region "natal" named "Natal" with bounding box -5.9, -35.3, -5.7, -35.1 and a 5000 m circle at -5.88, -35.17
+    When a client lists the covered regions
+    Then 1 regions are listed
+    And the region "natal" has the bounding box -5.9, -35.3, -5.7, -35.1
+    And the region "natal" has 1 discovery circle of 5000 m at -5.88, -35.17
+    And no region exposes its discovery settings
+
+  Scenario: A region given only by its circles is bounded by them
+    Given the region "recife" named "Recife" with a 10000 m circle at -8.06, -34.88
+    When a client lists the covered regions
+    Then the region "recife" has a bounding box that contains -8.06, -34.88 and spans about 0.18 degrees of latitude
@@ -0,0 +1,60 @@
+Feature: Nearby radius units, sort order and open-now filter
+  GET /v1/venues/nearby takes its radius in a chosen unit, can rank venues by
+  a combined score instead of by live busyness, and can leave out venues that
+  are closed right now. Requests that use none of these options must get the
+  response they always got.
+
+  Background:
+    Given a nearby venue "near" about 0.5 km from the search point
+    And a nearby venue "far" about 2.2 km from the search point
+
+  Scenario Outline: The radius is read in the requested unit
+    When nearby venues are requested within <radius> <unit>
+    Then the nearby response lists <venues>
+
+    Examples:
+      | radius | unit | venues    |
+      | 1500   | m    | near      |
+      | 1.5    | km   | near      |
+      | 1      | mi   | near      |
+      | 2      | mi   | near, far |
+      | 8000   | ft   | near, far |
+
+  Scenario: The radius unit defaults to kilometers
+    When nearby venues are requested within 1.5 without a unit
+    Then the nearby response lists near
+
+  Scenario: An unknown radius unit is rejected
+    When nearby venues are requested within 10 yards
+    Then the nearby API answers 422
+
+  Scenario: Sorting by score ranks the better venue above the busier one
+    Given "far" has live busyness 70 and a 3.0 rating
+    And "near" has no live data and a 4.8 rating from 400 reviews
+    When nearby venues within 3 km are requested sorted by "score"
+    Then the nearby response ranks near, far
+    And every listed venue carries a score, highest first
+
+  Scenario: The default sort puts live venues first and carries no score
+    Given "far" has live busyness 70 and a 3.0 rating
+    And "near" has no live data and a 4.8 rating from 400 reviews
+    When nearby venues are requested within 3 km
+    Then the nearby response ranks far, near
+    And no listed venue carries a score
+
+  Scenario: An unknown sort order is rejected
+    When nearby venues within 3 km are requested sorted by "rating"
+    Then the nearby API answers 422
+
+  Scenario: The open-now filter keeps only venues open at request time
+    Given "near" opens from 18:00 to 02:00 every day
+    And "far" opens from 07:00 to 14:00 every day
+    And a venue "unknown-hours" without opening hours next to the search point
+    When nearby venues open now within 3 km are requested on a Wednesday at 22:00 in Recife
+    Then the nearby response lists near
+
+  Scenario: Without the open-now filter closed venues are still listed
+    Given "near" opens from 18:00 to 02:00 every day
+    And "far" opens from 07:00 to 14:00 every day
+    When nearby venues are requested within 3 km
+    Then the nearby response lists near, far
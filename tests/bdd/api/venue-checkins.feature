@@ -0,0 +1,39 @@
+Feature: Venue check-ins
+  A user at a venue checks in from the app. Recent check-ins stand in for live
+  busyness where BestTime has none, so they must come from people who are
+  really there: one counted check-in per device per dedup window, only from
+  within the allowed distance of the venue, and a bounded number per client
+  address.
+
+  Background:
+    Given check-ins are enabled with 3 check-ins needed for a live reading and 10 for a full venue
+    And a catalog venue "quiet" without live data
+
+  Scenario: A device's check-in is counted once per dedup window
+    When device "device-token-1" checks in at "quiet" from the venue
+    Then the check-in is counted
+    When device "device-token-1" checks in at "quiet" from the venue
+    Then the check-in is not counted
+
+  Scenario: A check-in from too far away is refused
+    When device "device-token-1" checks in at "quiet" from 1 km away
+    Then the check-in API answers 403
+
+  Scenario: A check-in at an unknown venue is refused
+    When device "device-token-1" checks in at "nope" from the venue
+    Then the check-in API answers 404
+
+  Scenario: Check-ins from one client address are capped per venue
+    Given check-ins allow 2 per client address
+    When 5 devices check in at "quiet" from the same client address
+    Then 2 of those check-ins are counted
+
+  Scenario: Enough recent check-ins stand in for live busyness
+    When 3 devices check in at "quiet" from the venue
+    And the nearby venues around "quiet" are requested
+    Then the nearby venue "quiet" shows live busyness 30 from "checkins"
+
+  Scenario: Too few check-ins leave the venue without a live reading
+    When 2 devices check in at "quiet" from the venue
+    And the nearby venues around "quiet" are requested
+    Then the nearby venue "quiet" shows no live busyness
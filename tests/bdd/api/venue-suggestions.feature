@@ -0,0 +1,41 @@
+Feature: User venue suggestions
+  A user proposes a venue the catalog does not have. Suggestions wait in a
+  bounded review queue; an operator approves one (which adds the venue through
+  the add-by-address flow) or rejects it. Nothing is added, and nothing is
+  spent, until an operator approves.
+
+  Background:
+    Given venue suggestions are enabled with room for 2 pending suggestions
+
+  Scenario: A suggestion is queued for review
+    When a user suggests "Bar do Zé" at "Rua X, 10"
+    Then the suggestion API answers 202
+    And the suggestion is "pending"
+    And the review queue lists 1 pending suggestion
+
+  Scenario: Suggesting the same venue again returns the pending suggestion
+    When a user suggests "Bar do Zé" at "Rua X, 10"
+    And a user suggests " bar do zé" at "RUA X, 10 "
+    Then both suggestions have the same id
+    And the review queue lists 1 pending suggestion
+
+  Scenario: A full review queue refuses new suggestions
+    Given the review queue holds 2 pending suggestions
+    When a user suggests "Tasca" at "Rua Y, 20"
+    Then the suggestion API answers 429
+
+  Scenario: Approving a suggestion adds the venue once
+    Given a user suggested "Bar do Zé" at "Rua X, 10"
+    When an operator approves the suggestion
+    Then the suggestion API answers 201
+    And the approved suggestion points at the new venue
+    And the suggested venue was added once by address
+    When an operator approves the suggestion
+    Then the suggestion API answers 409
+
+  Scenario: A rejected suggestion leaves the queue without adding a venue
+    Given a user suggested "Bar do Zé" at "Rua X, 10"
+    When an operator rejects the suggestion as "duplicate of ven_1"
+    Then the suggestion API answers 200
+    And the review queue lists 0 pending suggestions
+    And no venue was added by address
@@ -21,6 +21,7 @@ from app.config import settings
 from app.dao import RedisVenueDAO, VenueBudgetDao
 from app.db.geo_redis_client import GeoRedisClient
 from app.handlers import AddVenueHandler
+from app.services.regions import RegionRegistry
 from app.services.venue_budget_service import VenueBudgetService
 
 
@@ -164,6 +165,9 @@ def _build_test_app(context) -> None:
         container.add_venue_handler = context.add_venue_handler
         container.venue_budget_service = context.budget_service
         container.batch_add_service = context.batch_add_service
+        # No configured regions, as in the real container by default (a bare
+        # MagicMock attribute would make every geo-fence PUT leave one outside).
+        container.regions = RegionRegistry([])
         try:
             set_admin_container(container)
         except Exception:
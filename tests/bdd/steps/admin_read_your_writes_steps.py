@@ -0,0 +1,83 @@
+"""Behave steps for tests/bdd/api/admin-read-your-writes.feature.
+
+Drives the admin routes on context.client with ?verify=true. The real
+AdminConfigService, VenueNotesService and EligibilityRuleService run over
+context.fake_redis and context.rds_store (environment.py); the failure
+scenarios make the Redis mirror or the RDS geo-fence write acknowledge
+without storing anything, which only a read-back notices.
+"""
+from __future__ import annotations
+
+import json
+from unittest.mock import MagicMock
+
+from behave import given, when, then  # type: ignore[import-untyped]
+
+from app.services.venue_eligibility import ADMIN_CONFIG_ELIGIBILITY_KEY
+from app.services.venue_notes import VenueNotesService
+
+
+# ── Given ─────────────────────────────────────────────────────────────────────
+@given("the Redis mirror acknowledges writes without storing them")
+def step_mirror_drops_writes(context):
+    context.admin_config_service.redis = MagicMock(wraps=context.fake_redis)
+    context.admin_config_service.redis.set.return_value = True
+
+
+@given("RDS acknowledges geo-fence writes without storing them")
+def step_rds_drops_geo_fence(context):
+    context.rds_store.set_geo_fence = lambda fence, updated_by=None: None
+
+
+# ── When ──────────────────────────────────────────────────────────────────────
+@when('an operator writes the config "{key}" with verification')
+def step_write_config(context, key):
+    context.response = context.client.put(f"/admin/config/{key}?verify=true", json={"a": 1})
+
+
+@when('an operator writes a public note "{note}" on "{venue_id}" with verification')
+def step_write_note(context, note, venue_id):
+    context.container.venue_notes_service = VenueNotesService(context.admin_config_service)
+    context.response = context.client.put(
+        f"/admin/venues/{venue_id}/note?verify=true", json={"note": note, "public": True}
+    )
+
+
+@when('an operator adds the eligibility rule "{rule_type}" "{value}" with verification')
+def step_add_rule(context, rule_type, value):
+    context.container.eligibility_rule_service = context.eligibility_rule_service
+    context.response = context.client.post(
+        "/admin/venues/eligibility-rule?verify=true",
+        json={"rule_type": rule_type, "value": value},
+    )
+
+
+@when('an operator sets the geo-fence to "{slug}" at {radius_km:d} km with verification')
+def step_set_geo_fence(context, slug, radius_km):
+    context.response = context.client.put(
+        "/admin/config/geofence?verify=true",
+        json={"enabled": True, "cities": [{"slug": slug, "radius_km": radius_km}]},
+    )
+
+
+# ── Then ──────────────────────────────────────────────────────────────────────
+@then("the admin write answers {status:d}")
+def step_admin_write_status(context, status):
+    assert context.response.status_code == status, context.response.text
+
+
+@then("the admin write is reported as verified")
+def step_admin_write_verified(context):
+    assert context.response.json().get("verified") is True, context.response.text
+
+
+@then('the Redis mirror serves the note "{note}" on "{venue_id}"')
+def step_mirror_serves_note(context, note, venue_id):
+    notes = json.loads(context.fake_redis.get("admin_config:venue_notes"))
+    assert notes[venue_id]["note"] == note and notes[venue_id]["public"] is True, notes
+
+
+@then('the Redis eligibility mirror blocks the google type "{google_type}"')
+def step_mirror_blocks_google_type(context, google_type):
+    mirrored = json.loads(context.fake_redis.get(ADMIN_CONFIG_ELIGIBILITY_KEY))
+    assert google_type in mirrored["blocked_google_types"], mirrored
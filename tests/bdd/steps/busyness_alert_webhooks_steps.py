@@ -0,0 +1,172 @@
+"""Behave steps for tests/bdd/api/busyness-alert-webhooks.feature.
+
+Drives the real WebhookService over the fakeredis DAO built in environment.py
+(context.venue_dao) through POST/GET/DELETE /v1/webhooks. The two network edges
+are deterministic fakes: DNS is a programmable resolver (every host resolves to
+context.webhook_addresses) and the receiver is an httpx MockTransport that
+records each POST, so no scenario leaves the process.
+"""
+from __future__ import annotations
+
+import asyncio
+import json
+from datetime import datetime, timezone
+
+import httpx
+from behave import given, when, then  # type: ignore[import-untyped]
+from fastapi import FastAPI
+from fastapi.testclient import TestClient
+
+from app.models import Analysis, LiveForecastResponse, Venue, VenueInfo
+from app.routers.webhook_router import router as webhook_router, set_webhook_service
+from app.services.webhooks import WebhookService, sign
+
+_HOOK_URL = "https://alerts.example.com/hook"
+
+
+def _set_live(context, venue_id, busyness):
+    context.venue_dao.set_live_forecast(LiveForecastResponse(
+        status="OK",
+        venue_info=VenueInfo(
+            venue_id=venue_id, venue_current_gmttime=datetime.now(timezone.utc).isoformat()
+        ),
+        analysis=Analysis(venue_live_busyness=busyness, venue_live_busyness_available=True),
+    ))
+
+
+def _service(context) -> WebhookService:
+    if getattr(context, "webhook_service", None) is None:
+        context.webhook_addresses = getattr(context, "webhook_addresses", ["93.184.216.34"])
+        context.webhook_requests = []
+
+        def receive(request: httpx.Request) -> httpx.Response:
+            context.webhook_requests.append(request)
+            return httpx.Response(200)
+
+        context.webhook_service = WebhookService(
+            context.venue_dao,
+            context.fake_redis,
+            api_keys=context.webhook_api_keys,
+            backoff_seconds=0,
+            http_client=httpx.AsyncClient(transport=httpx.MockTransport(receive)),
+            resolver=lambda host: context.webhook_addresses,
+        )
+        set_webhook_service(context.webhook_service)
+        context.add_cleanup(set_webhook_service, None)
+        app = FastAPI()
+        app.include_router(webhook_router)
+        context.webhook_client = TestClient(app)
+    return context.webhook_service
+
+
+def _register(context, api_key, venue_id, threshold):
+    _service(context)
+    context.response = context.webhook_client.post(
+        "/v1/webhooks",
+        json={"url": _HOOK_URL, "rule": {"threshold": threshold, "venue_id": venue_id}},
+        headers={"X-Webhook-Key": api_key},
+    )
+    if context.response.status_code == 201:
+        context.webhook = context.response.json()
+
+
+# ── Given ─────────────────────────────────────────────────────────────────────
+@given('a webhook client key "{api_key}" for client "{client_id}"')
+def step_webhook_client_key(context, api_key, client_id):
+    context.webhook_api_keys = {api_key: client_id}
+
+
+@given('a venue "{venue_id}" whose live busyness is {busyness:d}')
+def step_venue_with_live_busyness(context, venue_id, busyness):
+    context.venue_dao.upsert_venue(Venue(
+        venue_id=venue_id, venue_name=venue_id.title(), venue_address="Rua X, 10",
+        venue_lat=-8.06, venue_lng=-34.87,
+    ))
+    _set_live(context, venue_id, busyness)
+
+
+@given('webhook hosts resolve to "{address}"')
+def step_webhook_hosts_resolve_to(context, address):
+    context.webhook_addresses = [address]
+
+
+@given('a webhook is registered with key "{api_key}" for venue "{venue_id}" at threshold {threshold:d}')
+def step_webhook_registered(context, api_key, venue_id, threshold):
+    _register(context, api_key, venue_id, threshold)
+    assert context.response.status_code == 201, context.response.text
+
+
+# ── When ──────────────────────────────────────────────────────────────────────
+@when('a webhook is registered with key "{api_key}" for venue "{venue_id}" at threshold {threshold:d}')
+def step_register_webhook(context, api_key, venue_id, threshold):
+    _register(context, api_key, venue_id, threshold)
+
+
+@when('the live busyness of "{venue_id}" becomes {busyness:d} and webhooks are evaluated')
+def step_live_crosses_once(context, venue_id, busyness):
+    _set_live(context, venue_id, busyness)
+    asyncio.run(_service(context).evaluate())
+
+
+@when('the live busyness of "{venue_id}" becomes {busyness:d} and webhooks are evaluated twice')
+def step_live_crosses_and_stays(context, venue_id, busyness):
+    _set_live(context, venue_id, busyness)
+    asyncio.run(_service(context).evaluate())
+    asyncio.run(_service(context).evaluate())  # still above: no new crossing
+
+
+@when("the webhook is read without its secret")
+def step_read_without_secret(context):
+    context.response = context.webhook_client.get(f"/v1/webhooks/{context.webhook['id']}")
+
+
+@when("the webhook is deleted with its secret")
+def step_delete_with_secret(context):
+    context.response = context.webhook_client.delete(
+        f"/v1/webhooks/{context.webhook['id']}",
+        headers={"X-Webhook-Secret": context.webhook["secret"]},
+    )
+
+
+# ── Then ──────────────────────────────────────────────────────────────────────
+@then("the webhook API answers {status:d}")
+def step_webhook_api_status(context, status):
+    assert context.response.status_code == status, context.response.text
+
+
+@then('the webhook receiver gets {count:d} event for venue "{venue_id}"')
+@then('the webhook receiver gets {count:d} events for venue "{venue_id}"')
+def step_receiver_events(context, count, venue_id):
+    bodies = [json.loads(r.content) for r in context.webhook_requests]
+    assert [b["venue_id"] for b in bodies] == [venue_id] * count, bodies
+
+
+@then("the event is signed with the webhook's secret")
+def step_event_signed(context):
+    request = context.webhook_requests[-1]
+    expected = sign(context.webhook["secret"], request.content)
+    assert request.headers["X-Webhook-Signature"] == expected
+
+
+@then('the webhook\'s delivery log shows {count:d} "{status}" attempt')
+def step_delivery_log(context, count, status):
+    response = context.webhook_client.get(
+        f"/v1/webhooks/{context.webhook['id']}/deliveries",
+        headers={"X-Webhook-Secret": context.webhook["secret"]},
+    )
+    assert [d["status"] for d in response.json()] == [status] * count, response.text
+
+
+@then("the webhook's latest delivery is refused")
+def step_latest_delivery_refused(context):
+    latest = _service(context).deliveries(context.webhook["id"])[0]
+    assert latest.error.startswith("refused"), latest
+
+
+@then("reading the webhook with its secret answers {status:d}")
+def step_read_with_secret(context, status):
+    response = context.webhook_client.get(
+        f"/v1/webhooks/{context.webhook['id']}",
+        headers={"X-Webhook-Secret": context.webhook["secret"]},
+    )
+    assert response.status_code == status, response.text
@@ -0,0 +1,103 @@
+"""Behave steps for tests/bdd/api/covered-regions.feature.
+
+Configures a real RegionRegistry and reads it back through GET /v1/regions on a
+fresh app mounting the venue router. Regions carry their discovery settings
+(point ids and limits, catalog refresh cadence, search cities) so the
+scenarios can check none of them leak into the public listing.
+"""
+from __future__ import annotations
+
+from behave import given, when, then  # type: ignore[import-untyped]
+from fastapi import FastAPI
+from fastapi.testclient import TestClient
+
+from app.routers.venue_router import router as venue_router, set_regions
+from app.services.regions import RegionRegistry
+
+
+def _configure(context, region: dict) -> None:
+    region = {**region, "catalog_refresh_minutes": 60, "cities": [region["name"]]}
+    context.regions = getattr(context, "regions", []) + [region]
+    set_regions(RegionRegistry(context.regions))
+    context.add_cleanup(set_regions, None)
+
+
+def _listed(context, region_id) -> dict:
+    for region in context.response.json():
+        if region["id"] == region_id:
+            return region
+    raise AssertionError(f"region {region_id!r} not listed: {context.response.text}")
+
+
+# ── Given ─────────────────────────────────────────────────────────────────────
+@given("no regions are configured")
+def step_no_regions(context):
+    set_regions(None)
+
+
+@given(
+    'the region "{region_id}" named "{name}" with bounding box {min_lat:f}, {min_lng:f}, '
+    "{max_lat:f}, {max_lng:f} and a {radius:d} m circle at {lat:f}, {lng:f}"
+)
+def step_region_with_bbox(context, region_id, name, min_lat, min_lng, max_lat, max_lng, radius, lat, lng):
+    _configure(context, {
+        "id": region_id,
+        "name": name,
+        "bbox": [min_lat, min_lng, max_lat, max_lng],
+        "points": [{"id": f"{region_id}-1", "lat": lat, "lng": lng, "radius": radius, "limit": 100}],
+    })
+
+
+@given('the region "{region_id}" named "{name}" with a {radius:d} m circle at {lat:f}, {lng:f}')
+def step_region_with_circle(context, region_id, name, radius, lat, lng):
+    _configure(context, {
+        "id": region_id,
+        "name": name,
+        "points": [{"id": f"{region_id}-1", "lat": lat, "lng": lng, "radius": radius, "limit": 100}],
+    })
+
+
+# ── When ──────────────────────────────────────────────────────────────────────
+@when("a client lists the covered regions")
+def step_list_regions(context):
+    app = FastAPI()
+    app.include_router(venue_router)
+    context.response = TestClient(app).get("/v1/regions")
+
+
+# ── Then ──────────────────────────────────────────────────────────────────────
+@then("the regions API answers {status:d}")
+def step_regions_api_status(context, status):
+    assert context.response.status_code == status, context.response.text
+
+
+@then("{count:d} regions are listed")
+def step_regions_listed(context, count):
+    assert len(context.response.json()) == count, context.response.text
+
+
+@then('the region "{region_id}" has the bounding box {min_lat:f}, {min_lng:f}, {max_lat:f}, {max_lng:f}')
+def step_region_bbox(context, region_id, min_lat, min_lng, max_lat, max_lng):
+    assert _listed(context, region_id)["bbox"] == [min_lat, min_lng, max_lat, max_lng]
+
+
+@then('the region "{region_id}" has {count:d} discovery circle of {radius:d} m at {lat:f}, {lng:f}')
+def step_region_circles(context, region_id, count, radius, lat, lng):
+    points = _listed(context, region_id)["points"]
+    assert points == [{"lat": lat, "lng": lng, "radius": radius}] * count, points
+
+
+@then("no region exposes its discovery settings")
+def step_no_discovery_settings(context):
+    for region in context.response.json():
+        assert set(region) == {"id", "name", "bbox", "points"}, region
+
+
+@then(
+    'the region "{region_id}" has a bounding box that contains {lat:f}, {lng:f} and spans '
+    "about {span:f} degrees of latitude"
+)
+def step_region_bbox_around(context, region_id, lat, lng, span):
+    min_lat, min_lng, max_lat, max_lng = _listed(context, region_id)["bbox"]
+    assert min_lat < lat < max_lat and min_lng < lng < max_lng
+    assert round(max_lat - min_lat, 2) == span
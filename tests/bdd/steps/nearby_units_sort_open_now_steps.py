@@ -0,0 +1,154 @@
+"""Behave steps for tests/bdd/api/nearby-units-sort-open-now.feature.
+
+Issues real GET /v1/venues/nearby requests against a fresh app mounting the
+venue router, with the real VenueHandler over the fakeredis DAO built in
+environment.py (context.venue_dao). Venues sit due north of the search point at
+the stated distance. Opening hours are stored as BestTime weekly forecasts, and
+"now" is pinned through the handler's clock for the open-now scenarios.
+"""
+from __future__ import annotations
+
+from datetime import datetime, timezone
+from unittest.mock import patch
+
+from behave import given, when, then  # type: ignore[import-untyped]
+from fastapi import FastAPI
+from fastapi.testclient import TestClient
+
+from app.handlers import VenueHandler
+from app.models import (
+    Analysis,
+    DayInfo,
+    DayInfoV2,
+    LiveForecastResponse,
+    OpenCloseDetail,
+    Venue,
+    VenueInfo,
+    WeekRawDay,
+)
+from app.routers.venue_router import router as venue_router, set_venue_handler
+
+_LAT, _LNG = -8.05, -34.88
+_KM_PER_DEGREE_LAT = 111.2
+# Wednesday 2026-10-14 22:00 in Recife (UTC-3).
+_WEDNESDAY_NIGHT_UTC = datetime(2026, 10, 15, 1, 0, tzinfo=timezone.utc)
+
+
+def _venue(venue_id, km=0.0, **fields):
+    return Venue(
+        venue_id=venue_id, venue_name=venue_id.title(), venue_address="Rua X, 10",
+        venue_lat=_LAT + km / _KM_PER_DEGREE_LAT, venue_lng=_LNG, **fields,
+    )
+
+
+def _update(context, venue_id, **fields):
+    venue = context.venue_dao.get_venue(venue_id)
+    context.venue_dao.upsert_venue(venue.model_copy(update=fields))
+
+
+def _request(context, params):
+    set_venue_handler(VenueHandler(context.venue_dao))
+    app = FastAPI()
+    app.include_router(venue_router)
+    context.response = TestClient(app).get(
+        "/v1/venues/nearby", params={"lat": _LAT, "lon": _LNG, **params}
+    )
+
+
+def _listed(context) -> list[str]:
+    assert context.response.status_code == 200, context.response.text
+    return [v["venue_id"] for v in context.response.json()]
+
+
+def _ids(venue_ids: str) -> list[str]:
+    return [vid.strip() for vid in venue_ids.split(",")]
+
+
+# ── Given ─────────────────────────────────────────────────────────────────────
+@given('a nearby venue "{venue_id}" about {km:f} km from the search point')
+def step_venue_at_distance(context, venue_id, km):
+    context.venue_dao.upsert_venue(_venue(venue_id, km))
+
+
+@given('a venue "{venue_id}" without opening hours next to the search point')
+def step_venue_without_hours(context, venue_id):
+    context.venue_dao.upsert_venue(_venue(venue_id))
+
+
+@given('"{venue_id}" has live busyness {busyness:d} and a {rating:f} rating')
+def step_live_and_rating(context, venue_id, busyness, rating):
+    _update(context, venue_id, rating=rating)
+    context.venue_dao.set_live_forecast(LiveForecastResponse(
+        status="OK",
+        venue_info=VenueInfo(
+            venue_id=venue_id, venue_current_gmttime=datetime.now(timezone.utc).isoformat()
+        ),
+        analysis=Analysis(venue_live_busyness=busyness, venue_live_busyness_available=True),
+    ))
+
+
+@given('"{venue_id}" has no live data and a {rating:f} rating from {reviews:d} reviews')
+def step_rating_and_reviews(context, venue_id, rating, reviews):
+    _update(context, venue_id, rating=rating, reviews=reviews)
+
+
+@given('"{venue_id}" opens from {opens:d}:00 to {closes:d}:00 every day')
+def step_opening_hours(context, venue_id, opens, closes):
+    for day_int in range(7):
+        context.venue_dao.set_week_raw_forecast(venue_id, WeekRawDay(
+            day_int=day_int,
+            day_raw=[40] * 24,
+            day_info=DayInfo(
+                day_int=day_int,
+                venue_open_close_v2=DayInfoV2(h24=[OpenCloseDetail(opens=opens, closes=closes)]),
+            ),
+        ))
+
+
+# ── When ──────────────────────────────────────────────────────────────────────
+@when("nearby venues are requested within {radius:g} {unit:w}")
+def step_request_in_unit(context, radius, unit):
+    _request(context, {"radius": radius, "unit": unit})
+
+
+@when("nearby venues are requested within {radius:g} without a unit")
+def step_request_default_unit(context, radius):
+    _request(context, {"radius": radius})
+
+
+@when('nearby venues within {radius:g} km are requested sorted by "{sort}"')
+def step_request_sorted(context, radius, sort):
+    _request(context, {"radius": radius, "sort": sort})
+
+
+@when("nearby venues open now within {radius:g} km are requested on a Wednesday at 22:00 in Recife")
+def step_request_open_now(context, radius):
+    with patch("app.handlers.venue_handler.utc_now", return_value=_WEDNESDAY_NIGHT_UTC):
+        _request(context, {"radius": radius, "open_now": True})
+
+
+# ── Then ──────────────────────────────────────────────────────────────────────
+@then("the nearby API answers {status:d}")
+def step_nearby_api_status(context, status):
+    assert context.response.status_code == status, context.response.text
+
+
+@then("the nearby response lists {venue_ids}")
+def step_nearby_lists(context, venue_ids):
+    assert sorted(_listed(context)) == sorted(_ids(venue_ids)), context.response.text
+
+
+@then("the nearby response ranks {venue_ids}")
+def step_nearby_ranks(context, venue_ids):
+    assert _listed(context) == _ids(venue_ids), context.response.text
+
+
+@then("every listed venue carries a score, highest first")
+def step_scores_descending(context):
+    scores = [v["score"] for v in context.response.json()]
+    assert all(s is not None for s in scores) and scores == sorted(scores, reverse=True), scores
+
+
+@then("no listed venue carries a score")
+def step_no_scores(context):
+    assert all("score" not in v for v in context.response.json()), context.response.text
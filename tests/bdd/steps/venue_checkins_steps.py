@@ -0,0 +1,121 @@
+"""Behave steps for tests/bdd/api/venue-checkins.feature.
+
+Drives POST /v1/venues/{id}/checkin and GET /v1/venues/nearby on a fresh app
+mounting the venue router, with the real CheckinService over the fakeredis
+built in environment.py (context.fake_redis) and the real VenueHandler over
+context.venue_dao. Every TestClient request comes from the same client address
+("testclient"), which is what the per-address cap scenario relies on.
+"""
+from __future__ import annotations
+
+from behave import given, when, then  # type: ignore[import-untyped]
+from fastapi import FastAPI
+from fastapi.testclient import TestClient
+
+from app.handlers import VenueHandler
+from app.models import Venue
+from app.routers.venue_router import router as venue_router, set_checkin_service, set_venue_handler
+from app.services.checkins import CheckinService
+
+_LAT, _LNG = -8.05, -34.88
+# ~1.1 km north of the venue.
+_FAR_LAT = -8.04
+
+
+def _client(context) -> TestClient:
+    set_venue_handler(VenueHandler(context.venue_dao, checkin_service=context.checkin_service))
+    set_checkin_service(context.checkin_service)
+    context.add_cleanup(set_checkin_service, None)
+    app = FastAPI()
+    app.include_router(venue_router)
+    return TestClient(app)
+
+
+def _check_in(context, venue_id, device_token, lat=_LAT):
+    context.response = _client(context).post(
+        f"/v1/venues/{venue_id}/checkin",
+        json={"device_token": device_token, "lat": lat, "lng": _LNG},
+    )
+    return context.response
+
+
+# ── Given ─────────────────────────────────────────────────────────────────────
+@given("check-ins are enabled with {min_count:d} check-ins needed for a live reading and {full_count:d} for a full venue")
+def step_checkins_enabled(context, min_count, full_count):
+    context.checkin_service = CheckinService(
+        context.fake_redis, min_count=min_count, full_count=full_count, max_distance_m=200,
+    )
+
+
+@given('a catalog venue "{venue_id}" without live data')
+def step_catalog_venue(context, venue_id):
+    context.venue_dao.upsert_venue(Venue(
+        venue_id=venue_id, venue_name=venue_id.title(), venue_address="Rua X, 10",
+        venue_lat=_LAT, venue_lng=_LNG,
+    ))
+
+
+@given("check-ins allow {max_per_ip:d} per client address")
+def step_checkins_per_address(context, max_per_ip):
+    context.checkin_service.max_per_ip = max_per_ip
+
+
+# ── When ──────────────────────────────────────────────────────────────────────
+@when('device "{device_token}" checks in at "{venue_id}" from the venue')
+def step_device_checks_in(context, device_token, venue_id):
+    _check_in(context, venue_id, device_token)
+
+
+@when('device "{device_token}" checks in at "{venue_id}" from 1 km away')
+def step_device_checks_in_far(context, device_token, venue_id):
+    _check_in(context, venue_id, device_token, lat=_FAR_LAT)
+
+
+@when('{count:d} devices check in at "{venue_id}" from the venue')
+@when('{count:d} devices check in at "{venue_id}" from the same client address')
+def step_devices_check_in(context, count, venue_id):
+    context.checkin_responses = [
+        _check_in(context, venue_id, f"device-token-{n}") for n in range(count)
+    ]
+
+
+@when('the nearby venues around "{venue_id}" are requested')
+def step_nearby_around(context, venue_id):
+    context.response = _client(context).get(
+        "/v1/venues/nearby", params={"lat": _LAT, "lon": _LNG, "radius": 1},
+    )
+    assert context.response.status_code == 200, context.response.text
+    context.nearby = {v["venue_id"]: v for v in context.response.json()}
+
+
+# ── Then ──────────────────────────────────────────────────────────────────────
+@then("the check-in is counted")
+def step_checkin_counted(context):
+    assert context.response.json() == {"counted": True}, context.response.text
+
+
+@then("the check-in is not counted")
+def step_checkin_not_counted(context):
+    assert context.response.json() == {"counted": False}, context.response.text
+
+
+@then("the check-in API answers {status:d}")
+def step_checkin_api_status(context, status):
+    assert context.response.status_code == status, context.response.text
+
+
+@then("{count:d} of those check-ins are counted")
+def step_checkins_counted(context, count):
+    counted = [r.json()["counted"] for r in context.checkin_responses]
+    assert counted.count(True) == count, counted
+
+
+@then('the nearby venue "{venue_id}" shows live busyness {busyness:d} from "{source}"')
+def step_nearby_live_from(context, venue_id, busyness, source):
+    venue = context.nearby[venue_id]
+    assert (venue["venue_live_busyness"], venue["live_source"]) == (busyness, source), venue
+
+
+@then('the nearby venue "{venue_id}" shows no live busyness')
+def step_nearby_no_live(context, venue_id):
+    assert context.nearby[venue_id]["venue_live_busyness"] is None, context.nearby[venue_id]
@@ -0,0 +1,124 @@
+"""Behave steps for tests/bdd/api/venue-suggestions.feature.
+
+Users submit through POST /v1/venues/suggest (a fresh app mounting the venue
+router); operators review through the admin routes on context.client, which
+read the service off the shared container built in environment.py. The real
+VenueSuggestionService runs over context.fake_redis; the add-by-address flow an
+approval calls is a deterministic fake that reports the venue as created, so
+no scenario reaches BestTime.
+"""
+from __future__ import annotations
+
+from unittest.mock import AsyncMock, Mock
+
+from behave import given, when, then  # type: ignore[import-untyped]
+from fastapi import FastAPI
+from fastapi.testclient import TestClient
+
+from app.handlers.add_venue_handler import AddVenueOutcome
+from app.routers.venue_router import router as venue_router, set_venue_suggestion_service
+from app.services.venue_suggestions import VenueSuggestionService
+
+_NEW_VENUE_ID = "ven_new"
+
+
+def _suggest(context, name, address):
+    app = FastAPI()
+    app.include_router(venue_router)
+    context.response = TestClient(app).post("/v1/venues/suggest", json={
+        "venue_name": name, "venue_address": address, "venue_lat": -8.05, "venue_lng": -34.88,
+    })
+    if context.response.status_code == 202:
+        context.suggestion_ids.append(context.response.json()["id"])
+
+
+# ── Given ─────────────────────────────────────────────────────────────────────
+@given("venue suggestions are enabled with room for {max_pending:d} pending suggestions")
+def step_suggestions_enabled(context, max_pending):
+    context.suggestion_add_handler = Mock()
+    context.suggestion_add_handler.add = AsyncMock(return_value=AddVenueOutcome(
+        status_code=201, body={"status": "created", "venue_id": _NEW_VENUE_ID},
+    ))
+    context.suggestion_service = VenueSuggestionService(
+        context.fake_redis, context.suggestion_add_handler, max_pending=max_pending,
+    )
+    context.suggestion_ids = []
+    set_venue_suggestion_service(context.suggestion_service)
+    context.add_cleanup(set_venue_suggestion_service, None)
+    context.container.venue_suggestion_service = context.suggestion_service
+
+
+@given("the review queue holds {count:d} pending suggestions")
+def step_queue_holds(context, count):
+    for n in range(count):
+        context.suggestion_service.submit(f"Bar {n}", f"Rua {n}", -8.05, -34.88)
+
+
+@given('a user suggested "{name}" at "{address}"')
+def step_user_suggested(context, name, address):
+    _suggest(context, name, address)
+    assert context.response.status_code == 202, context.response.text
+
+
+# ── When ──────────────────────────────────────────────────────────────────────
+@when('a user suggests "{name}" at "{address}"')
+def step_user_suggests(context, name, address):
+    _suggest(context, name, address)
+
+
+@when("an operator approves the suggestion")
+def step_operator_approves(context):
+    context.response = context.client.post(
+        f"/admin/venues/suggestions/{context.suggestion_ids[-1]}/approve"
+    )
+
+
+@when('an operator rejects the suggestion as "{reason}"')
+def step_operator_rejects(context, reason):
+    context.response = context.client.post(
+        f"/admin/venues/suggestions/{context.suggestion_ids[-1]}/reject",
+        json={"reason": reason},
+    )
+
+
+# ── Then ──────────────────────────────────────────────────────────────────────
+@then("the suggestion API answers {status:d}")
+def step_suggestion_api_status(context, status):
+    assert context.response.status_code == status, context.response.text
+
+
+@then('the suggestion is "{status}"')
+def step_suggestion_status(context, status):
+    assert context.response.json()["status"] == status, context.response.text
+
+
+@then("both suggestions have the same id")
+def step_same_suggestion_id(context):
+    assert len(set(context.suggestion_ids)) == 1, context.suggestion_ids
+
+
+@then("the review queue lists {count:d} pending suggestion")
+@then("the review queue lists {count:d} pending suggestions")
+def step_review_queue(context, count):
+    listed = context.client.get("/admin/venues/suggestions").json()
+    assert listed["pending"] == count, listed
+    assert len(listed["entries"]) == count, listed
+
+
+@then("the approved suggestion points at the new venue")
+def step_approved_points_at_venue(context):
+    suggestion = context.response.json()["suggestion"]
+    assert (suggestion["status"], suggestion["venue_id"]) == ("approved", _NEW_VENUE_ID), suggestion
+
+
+@then("the suggested venue was added once by address")
+def step_added_once(context):
+    add = context.suggestion_add_handler.add
+    assert add.await_count == 1, add.await_args_list
+    request = add.await_args.args[0]
+    assert (request.venue_name, request.venue_address) == ("Bar do Zé", "Rua X, 10")
+
+
+@then("no venue was added by address")
+def step_nothing_added(context):
+    assert context.suggestion_add_handler.add.await_count == 0
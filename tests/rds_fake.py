@@ -12,6 +12,7 @@ import copy
 from datetime import datetime, timezone
 from typing import Optional
 
+from app.dao.rds_venue_store import REACTIVATING_SOURCES
 from app.dao.venue_row import split_venue_for_storage
 
 # venues.venue address columns dropped by the batched contract — address lives
@@ -86,18 +87,18 @@ class InMemoryRdsVenueStore:
         if row is None:
             return
         gbs = row.get("google_business_status")
-        # Parity with RdsVenueStore: a geo-link undo is reversible — an active
-        # re-add of an undo-deprecated venue reactivates it; any other source
-        # keeps the resurrect-block.
-        reactivating_undo = (
+        # Parity with RdsVenueStore: a geo-link undo or a stale eviction is
+        # reversible — an active re-add of a venue they deprecated reactivates
+        # it; any other source keeps the resurrect-block.
+        reactivating = (
             row.get("lifecycle_status") == "deprecated"
             and venue.is_active()
-            and row.get("deprecated_source") == "admin_geo_link_undo"
+            and row.get("deprecated_source") in REACTIVATING_SOURCES
         )
         if (
             row.get("lifecycle_status") == "deprecated"
             and venue.is_active()
-            and not reactivating_undo
+            and not reactivating
         ):
             venue.lifecycle_status = "deprecated"
             venue.deprecated_reason = row.get("deprecated_reason")
@@ -278,6 +279,19 @@ class InMemoryRdsVenueStore:
     def list_all_venue_rows(self) -> list[dict]:
         return [self._row_with_address(row) for row in self.venues.values()]
 
+    def list_servable_venue_rows_in_box(
+        self, min_lat: float, max_lat: float, min_lng: float, max_lng: float
+    ) -> list[dict]:
+        servable = set(self.list_servable_venue_ids())
+        out = []
+        for vid, row in self.venues.items():
+            addr = self.addresses.get(vid)
+            if vid not in servable or addr is None:
+                continue
+            if min_lat <= addr["lat"] <= max_lat and min_lng <= addr["lng"] <= max_lng:
+                out.append(self._row_with_address(row))
+        return out
+
     # ── bulk per-table readers (projector rebuild, P1) ─────────────────────────
     # Mirrors RdsVenueStore's bulk readers so the fake stays the behaviour
     # contract for the projector (pinned by test_rds_store_contract.py).
@@ -425,6 +439,17 @@ class InMemoryRdsVenueStore:
             if up == user_pseudo and row.get("deleted_at") is None
         ]
 
+    def move_favorites(self, from_venue_id, to_venue_id) -> int:
+        self._guard()
+        moved = [
+            up for (up, vid), row in self.favorites.items()
+            if vid == from_venue_id and row.get("deleted_at") is None
+        ]
+        for up in moved:
+            self.favorites[(up, to_venue_id)] = {"deleted_at": None, "updated_at": _now()}
+            self.favorites[(up, from_venue_id)]["deleted_at"] = _now()
+        return len(moved)
+
     def add_hot_like_event(self, user_pseudo, venue_id, business_period) -> bool:
         """Mirrors the real store's unique index + ON CONFLICT DO NOTHING:
         returns True when this (user, venue, day) tuple is new, False when
@@ -462,6 +487,15 @@ class InMemoryRdsVenueStore:
             "updated_by": updated_by, "updated_at": _now(),
         }
 
+    def update_admin_config(self, key, change, updated_by=None, mirror=None):
+        self._guard()
+        row = self.admin_config.get(key)
+        value = change(copy.deepcopy(row["value"]) if row is not None else None)
+        if mirror is not None:
+            mirror(value)  # before the "commit": a failing mirror leaves the row as it was
+        self.upsert_admin_config(key, value, updated_by)
+        return value
+
     def get_admin_config(self, key) -> Optional[dict]:
         return self.admin_config.get(key)
 
@@ -1272,3 +1272,31 @@ async def test_readd_of_otherwise_deprecated_venue_still_short_circuits(
     assert (
         rds_fake_store.get_venue(vid)["deprecated_source"] == "google_places"
     )
+
+
+def test_verified_add_reads_the_venue_back(handler, besttime, venue_dao):
+    from types import SimpleNamespace
+
+    from fastapi import FastAPI
+    from fastapi.testclient import TestClient
+
+    from app.routers.admin_trigger_router import router, set_container
+
+    besttime.add_venue_to_account.return_value = _ok_response("ven_verified")
+    besttime.get_live_forecast.return_value = _live_unavailable("ven_verified")
+    set_container(SimpleNamespace(add_venue_handler=handler))
+    app = FastAPI()
+    app.include_router(router)
+    client = TestClient(app)
+
+    resp = client.post("/admin/venues/by-address?verify=true", json=_req().model_dump())
+    assert resp.status_code == 201 and resp.json()["verified"] is True
+
+    venue_dao.get_venue = lambda venue_id: None  # the write never landed
+    resp = client.post(
+        "/admin/venues/by-address?verify=true",
+        json=_req(
+            venue_name="Bar do Ze", venue_address="Rua Nova 5, Recife - PE", venue_lat=-8.2
+        ).model_dump(),
+    )
+    assert resp.status_code == 502
@@ -12,7 +12,10 @@ from fastapi import FastAPI
 from fastapi.testclient import TestClient
 
 from app.routers.admin_trigger_router import router, set_container
-from app.services.admin_config_service import AdminConfigService
+from app.services.admin_config_service import (
+    AdminConfigService,
+    AdminConfigVerificationError,
+)
 from app.services.venue_eligibility import EligibilityConfig, load_eligibility_config
 from tests.rds_fake import InMemoryRdsVenueStore, RdsUnavailable
 
@@ -149,6 +152,40 @@ def test_put_returns_502_when_mirror_fails():
     assert store.get_admin_config("scoring_weights")["value"] == {"a": 1}  # RDS committed
 
 
+# ── read-your-writes (verify=true) ───────────────────────────────────────────
+def test_verified_set_passes_when_both_copies_read_back():
+    svc, r, store = _svc()
+    assert svc.set("scoring_weights", {"a": 1}, verify=True) == {"a": 1}
+
+
+def test_verified_set_raises_when_mirror_write_is_lost():
+    svc, r, store = _svc()
+    r.set = lambda *a, **k: True  # acknowledged but never landed
+
+    with pytest.raises(AdminConfigVerificationError):
+        svc.set("scoring_weights", {"a": 1}, verify=True)
+    # unverified writes keep today's fire-and-return behaviour
+    assert svc.set("scoring_weights", {"a": 1}) == {"a": 1}
+
+
+def test_verified_set_mirror_skips_rds_check():
+    svc, r, store = _svc()
+    svc.set_mirror("venue_eligibility", {"blocked_venue_types": []}, verify=True)
+    assert store.get_admin_config("venue_eligibility") is None
+
+
+def test_put_verify_reports_verified_and_502s_on_mismatch():
+    svc, r, store = _svc()
+    client = _client(svc)
+    resp = client.put("/admin/config/scoring_weights?verify=true", json={"a": 1})
+    assert resp.status_code == 200 and resp.json()["verified"] is True
+    assert "verified" not in client.put("/admin/config/scoring_weights", json={"a": 2}).json()
+
+    r.set = lambda *a, **k: True
+    resp = client.put("/admin/config/scoring_weights?verify=true", json={"a": 3})
+    assert resp.status_code == 502
+
+
 # ── geo-fence endpoints (typed geo-fence tables, NOT the generic admin_config) ─
 def test_geofence_get_returns_default_fence():
     svc, _, _ = _svc()
@@ -194,6 +231,18 @@ def test_geofence_put_response_carries_the_count():
     assert "geo_excluded_active" not in mirrored
 
 
+def test_geofence_put_verify_reads_the_fence_back():
+    svc, r, store = _svc()
+    client = _client_with_redis(svc, r)
+    fence = {"enabled": True, "cities": [{"slug": "recife", "radius_km": 30}]}
+    resp = client.put("/admin/config/geofence?verify=true", json=fence)
+    assert resp.status_code == 200 and resp.json()["verified"] is True
+
+    store.set_geo_fence = lambda *a, **k: None  # acknowledged but never committed
+    fence["cities"][0]["radius_km"] = 45
+    assert client.put("/admin/config/geofence?verify=true", json=fence).status_code == 502
+
+
 def test_geofence_capitals_catalog_route():
     # /config/geofence/capitals must resolve to the dedicated handler (the
     # generic /config/{key} matches a single segment only).
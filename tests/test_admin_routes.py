@@ -0,0 +1,34 @@
+"""/ping and the GET /admin/routes route table (app/routers/)."""
+from fastapi import FastAPI
+from fastapi.testclient import TestClient
+
+from app.handlers import VenueHandler
+from app.routers import admin_trigger_router, set_venue_handler, venue_router
+from app.services.demo_data import DemoVenueDAO
+
+
+def _client():
+    app = FastAPI()
+    app.include_router(venue_router)
+    app.include_router(admin_trigger_router)
+    set_venue_handler(VenueHandler(DemoVenueDAO(-8.05, -34.9, count=3)))
+    return TestClient(app)
+
+
+def test_ping_answers_pong():
+    assert _client().get("/ping").json() == {"status": "pong"}
+
+
+def test_route_table_lists_paths_with_methods():
+    body = _client().get("/admin/routes").json()
+    by_path = {}
+    for route in body["routes"]:
+        by_path.setdefault(route["path"], []).append(route)
+
+    assert body["count"] == len(body["routes"])
+    assert by_path["/ping"] == [
+        {"path": "/ping", "methods": ["GET"], "name": "ping", "in_schema": True}
+    ]
+    assert by_path["/v1/venues/nearby"][0]["name"] == "get_venues_nearby"
+    assert ["GET"] in [r["methods"] for r in by_path["/admin/routes"]]
+    assert [r["path"] for r in body["routes"]] == sorted(r["path"] for r in body["routes"])
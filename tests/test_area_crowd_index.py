@@ -0,0 +1,123 @@
+"""Unit tests for the area crowd index (app/services/area_crowd_index.py)."""
+from datetime import datetime, timedelta, timezone
+
+import fakeredis
+import pytest
+from fastapi import FastAPI
+from fastapi.testclient import TestClient
+
+from app.dao.redis_venue_dao import RedisVenueDAO
+from app.dao.venue_repository import VenueRepository
+from app.db.geo_redis_client import GeoRedisClient
+from app.models import Analysis, LiveForecastResponse, Venue, VenueInfo
+from app.routers.venue_router import router as venue_router, set_area_crowd_index
+from app.services.area_crowd_index import (
+    AreaCrowdIndexService,
+    geohash_encode,
+    parse_areas,
+)
+from tests.rds_fake import InMemoryRdsVenueStore
+
+_NOW = datetime.now(timezone.utc)
+# A square around Recife Antigo and a geohash cell around Boa Viagem.
+_AREAS = [
+    {"id": "recife-antigo", "name": "Recife Antigo",
+     "polygon": [[-8.07, -34.88], [-8.07, -34.86], [-8.05, -34.86], [-8.05, -34.88]]},
+    {"id": "boa-viagem", "geohash": [geohash_encode(-8.12, -34.90, 5)]},
+    {"id": "empty", "polygon": [[10, 10], [10, 11], [11, 11]]},
+]
+
+
+def _venue(vid, lat, lng):
+    return Venue(venue_id=vid, venue_name=vid, venue_address="a", venue_lat=lat, venue_lng=lng)
+
+
+def _live(vid, busyness, minutes_ago=0):
+    return LiveForecastResponse(
+        status="OK",
+        venue_info=VenueInfo(
+            venue_id=vid,
+            venue_current_gmttime=(_NOW - timedelta(minutes=minutes_ago)).isoformat(),
+        ),
+        analysis=Analysis(venue_live_busyness=busyness, venue_live_busyness_available=True),
+    )
+
+
+def _service(hints=None):
+    redis = fakeredis.FakeRedis(decode_responses=True)
+    dao = RedisVenueDAO(GeoRedisClient(redis))
+    dao.upsert_venues([
+        _venue("bar", -8.06, -34.87),
+        _venue("arena", -8.061, -34.871),
+        _venue("quiet", -8.062, -34.872),
+        _venue("beach", -8.12, -34.90),
+    ])
+    dao.set_live_forecast(_live("bar", 100))
+    dao.set_live_forecast(_live("arena", 20))
+    dao.set_live_forecast(_live("beach", 60, minutes_ago=600))  # stale
+    return AreaCrowdIndexService(dao, redis, parse_areas(_AREAS), capacity_hints=hints)
+
+
+def test_geohash_encode_known_value():
+    assert geohash_encode(57.64911, 10.40744, 11) == "u4pruydqqvj"
+
+
+def test_parse_areas_rejects_bad_entries():
+    with pytest.raises(ValueError):
+        parse_areas([{"name": "no id", "geohash": "7nr"}])
+    with pytest.raises(ValueError):
+        parse_areas([{"id": "a"}])
+    with pytest.raises(ValueError):
+        parse_areas([{"id": "a", "polygon": [[0, 0], [1, 1]]}])
+    with pytest.raises(ValueError):
+        parse_areas([{"id": "a", "geohash": "7nr"}, {"id": "a", "geohash": "7ns"}])
+
+
+def test_index_is_capacity_weighted_mean_of_fresh_live():
+    indexes = {i.area_id: i for i in _service(hints={"arena": 300}).compute(_NOW)}
+
+    antigo = indexes["recife-antigo"]
+    assert antigo.venues == 3
+    assert antigo.venues_live == 2
+    assert antigo.index == round((100 * 100 + 20 * 300) / 400)
+    # Boa Viagem's only venue has stale live data: unknown, not zero.
+    assert indexes["boa-viagem"].venues == 1
+    assert indexes["boa-viagem"].index is None
+    assert indexes["empty"].venues == 0
+
+
+def test_recompute_stores_ranked_indexes():
+    service = _service()
+    service.recompute(_NOW)
+
+    ranked = service.ranked()
+
+    assert [i.area_id for i in ranked] == ["recife-antigo", "boa-viagem", "empty"]
+    assert service.get("recife-antigo").index == 60
+
+
+def test_index_reads_the_live_values_the_refresh_just_wrote():
+    # The pipeline repository writes RDS; the Redis projection runs later.
+    redis = fakeredis.FakeRedis(decode_responses=True)
+    repo = VenueRepository(GeoRedisClient(redis), rds_store=InMemoryRdsVenueStore())
+    repo.upsert_venue(_venue("bar", -8.06, -34.87))
+    repo.set_live_forecast(_live("bar", 80))
+    service = AreaCrowdIndexService(repo, redis, parse_areas(_AREAS))
+
+    assert {i.area_id: i.index for i in service.compute(_NOW)}["recife-antigo"] == 80
+
+
+def test_area_routes():
+    service = _service()
+    service.recompute(_NOW)
+    app = FastAPI()
+    app.include_router(venue_router)
+    set_area_crowd_index(service)
+    try:
+        client = TestClient(app)
+        assert client.get("/v1/areas").json()[0]["area_id"] == "recife-antigo"
+        assert client.get("/v1/areas/recife-antigo/index").json()["index"] == 60
+        assert client.get("/v1/areas/nowhere/index").status_code == 404
+    finally:
+        set_area_crowd_index(None)
+    assert TestClient(app).get("/v1/areas").status_code == 503
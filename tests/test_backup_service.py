@@ -0,0 +1,106 @@
+"""Venue data snapshots and restore (app/services/backup_service.py)."""
+import gzip
+import json
+from datetime import datetime, timedelta, timezone
+
+import fakeredis
+import pytest
+
+from app.config import settings
+from app.dao.redis_venue_dao import RedisVenueDAO
+from app.dao.venue_repository import VenueRepository
+from app.db.geo_redis_client import GeoRedisClient
+from app.services.backup_service import BackupNotFoundError, BackupService
+from app.services.fixture_seed import load_fixture, seed_redis
+from app.services.redis_projection_service import RedisProjectionService
+from tests.rds_fake import InMemoryRdsVenueStore
+
+_BAR = "ven_seed_bar_recife_antigo"
+
+
+class _MemoryStorage:
+    """The put_object / get_object / list_keys surface of S3Client."""
+
+    def __init__(self):
+        self.objects = {}
+
+    async def put_object(self, key, body, content_type="application/octet-stream", content_encoding=None):
+        self.objects[key] = body
+        return f"mem://{key}"
+
+    async def get_object(self, key):
+        return self.objects[key]
+
+    async def list_keys(self, prefix):
+        return sorted(k for k in self.objects if k.startswith(prefix))
+
+
+def _repository():
+    return VenueRepository(
+        GeoRedisClient(fakeredis.FakeRedis(decode_responses=True)), InMemoryRdsVenueStore()
+    )
+
+
+_NOON = datetime(2026, 10, 16, 12, 0, tzinfo=timezone.utc)
+
+
+async def test_backup_then_restore_into_an_empty_rds_and_project_it():
+    source = _repository()
+    seed_redis(source, load_fixture(settings.get_resource_path("seed_fixture.json")))
+    storage = _MemoryStorage()
+
+    written = await BackupService(source, storage, now_fn=lambda: _NOON).backup()
+
+    assert written["key"] == "backups/venues/20261016T120000Z.json.gz"
+    assert (written["venues"], written["live_forecasts"], written["weekly_forecasts"]) == (3, 3, 21)
+    snapshot = json.loads(gzip.decompress(storage.objects[written["key"]]))
+    assert snapshot["version"] == 1 and len(snapshot["venues"]) == 3
+
+    target = _repository()
+    serving = RedisVenueDAO(GeoRedisClient(fakeredis.FakeRedis(decode_responses=True)))
+    restorer = BackupService(target, storage)
+    restorer.project = RedisProjectionService(serving, target.rds_store).rebuild_redis_from_rds
+    restored = await restorer.restore()
+
+    assert restored["key"] == written["key"]
+    assert (restored["venues"], restored["live_forecasts"], restored["weekly_forecasts"]) == (3, 3, 21)
+    # RDS (the repository reads it) holds the snapshot...
+    assert target.get_venue(_BAR) == source.get_venue(_BAR)
+    assert target.get_live_forecast(_BAR).refreshed_at == source.get_live_forecast(_BAR).refreshed_at
+    assert target.get_week_raw_forecast(_BAR, 0) == source.get_week_raw_forecast(_BAR, 0)
+    # ...and the projection served it.
+    assert restored["projection"]["venues"] == 3
+    assert serving.get_venue(_BAR).venue_name == source.get_venue(_BAR).venue_name
+
+
+async def test_restore_picks_the_newest_snapshot_and_dry_run_writes_nothing():
+    source = _repository()
+    storage = _MemoryStorage()
+    clock = [_NOON]
+    service = BackupService(source, storage, now_fn=lambda: clock[0])
+    await service.backup()  # empty
+    seed_redis(source, load_fixture(settings.get_resource_path("seed_fixture.json")))
+    clock[0] += timedelta(hours=6)
+    newest = await service.backup()
+
+    target = _repository()
+    dry = await BackupService(target, storage).restore(dry_run=True)
+
+    assert await service.list_snapshots() == [
+        "backups/venues/20261016T120000Z.json.gz", newest["key"],
+    ]
+    assert dry["key"] == newest["key"] and dry["venues"] == 3
+    assert target.get_venue(_BAR) is None
+
+
+async def test_restore_errors():
+    storage = _MemoryStorage()
+    service = BackupService(_repository(), storage)
+
+    with pytest.raises(BackupNotFoundError):
+        await service.restore()
+    with pytest.raises(BackupNotFoundError):
+        await service.restore("elsewhere/x.json.gz")
+    storage.objects["backups/venues/bad.json.gz"] = b"not gzip"
+    with pytest.raises(ValueError, match="not a gzip JSON snapshot"):
+        await service.restore("backups/venues/bad.json.gz")
@@ -1,14 +1,19 @@
 """Unit tests for BestTime API client."""
+import asyncio
+
 import pytest
 from unittest.mock import AsyncMock, Mock, patch
 import httpx
 
-from app.api import BestTimeAPIClient
+from app.api import BestTimeAPIClient, BestTimeAPIError, RetryPolicy
+from app.api.besttime_client import pinned_key_pair
 from app.models import (
     VenueFilterParams,
     VenueFilterResponse,
     LiveForecastResponse,
     WeekRawResponse,
+    DayForecastResponse,
+    HourForecastResponse,
 )
 
 
@@ -214,6 +219,76 @@ class TestBestTimeAPIClient:
         with pytest.raises(ValueError, match="venue_id must be provided"):
             await api_client.get_week_raw_forecast("")
 
+    @pytest.mark.asyncio
+    async def test_get_day_forecast(self, api_client):
+        """Test get_day_forecast."""
+        mock_response_data = {
+            "status": "OK",
+            "venue_info": {"venue_id": "ven-123", "venue_name": "Test Venue"},
+            "analysis": {
+                "day_info": {"day_int": 4, "day_max": 90, "day_text": "Friday"},
+                "day_raw": [10] * 24,
+                "hour_analysis": [
+                    {"hour": 6, "intensity_txt": "Low", "intensity_nr": -1},
+                ],
+                "busy_hours": [21, 22],
+                "quiet_hours": [6, 7],
+            },
+        }
+
+        with patch.object(api_client.client, "request", new_callable=AsyncMock) as mock_request:
+            mock_response = Mock()
+            mock_response.status_code = 200
+            mock_response.json.return_value = mock_response_data
+            mock_request.return_value = mock_response
+
+            response = await api_client.get_day_forecast("ven-123", 4)
+
+            assert isinstance(response, DayForecastResponse)
+            assert response.analysis.day_info.day_max == 90
+            assert response.analysis.hour_analysis[0].intensity_nr == -1
+            assert response.analysis.busy_hours == [21, 22]
+
+            call_args = mock_request.call_args
+            assert call_args.kwargs["url"].endswith("/forecasts/day")
+            assert call_args.kwargs["params"]["api_key_public"] == "test_public_key"
+            assert call_args.kwargs["params"]["day_int"] == "4"
+
+    @pytest.mark.asyncio
+    async def test_get_hour_forecast(self, api_client):
+        """Test get_hour_forecast."""
+        mock_response_data = {
+            "status": "OK",
+            "analysis": {
+                "hour_analysis": {"hour": 22, "intensity_txt": "High", "intensity_nr": 1},
+            },
+        }
+
+        with patch.object(api_client.client, "request", new_callable=AsyncMock) as mock_request:
+            mock_response = Mock()
+            mock_response.status_code = 200
+            mock_response.json.return_value = mock_response_data
+            mock_request.return_value = mock_response
+
+            response = await api_client.get_hour_forecast("ven-123", 5, 22)
+
+            assert isinstance(response, HourForecastResponse)
+            assert response.analysis.hour_analysis.intensity_txt == "High"
+
+            call_args = mock_request.call_args
+            assert call_args.kwargs["url"].endswith("/forecasts/hour")
+            assert call_args.kwargs["params"]["hour"] == "22"
+
+    @pytest.mark.asyncio
+    async def test_day_and_hour_forecast_validate_arguments(self, api_client):
+        """Out-of-range day/hour and empty ids raise ValueError before any request."""
+        with pytest.raises(ValueError, match="venue_id must be provided"):
+            await api_client.get_day_forecast("", 0)
+        with pytest.raises(ValueError, match="day_int"):
+            await api_client.get_day_forecast("ven-123", 7)
+        with pytest.raises(ValueError, match="hour"):
+            await api_client.get_hour_forecast("ven-123", 0, 24)
+
     @pytest.mark.asyncio
     async def test_http_error_handling(self, api_client):
         """Test that HTTP errors are properly raised."""
@@ -651,3 +726,454 @@ class TestVenueFilter404Empty:
             with pytest.raises(httpx.HTTPStatusError):
                 await api_client.venue_filter(
                     VenueFilterParams(lat=-9.67, lng=-35.72, radius=50, limit=25))
+
+
+class TestOutboundIdentity:
+    """User-Agent, extra headers, and the egress proxy are client-wide."""
+
+    def _client(self, **kwargs):
+        return BestTimeAPIClient(
+            base_url="https://besttime.app/api/v1",
+            api_key_public="pub",
+            api_key_private="priv",
+            **kwargs,
+        )
+
+    def test_default_user_agent_carries_version(self):
+        from app import __version__
+
+        client = self._client()
+        assert client.client.headers["User-Agent"] == f"cs-server/{__version__}"
+
+    def test_custom_user_agent_and_extra_headers(self):
+        client = self._client(
+            user_agent="cs-server-staging/2",
+            extra_headers={"X-Egress-Tag": "cs", "User-Agent": "ignored"},
+        )
+        assert client.client.headers["User-Agent"] == "cs-server-staging/2"
+        assert client.client.headers["X-Egress-Tag"] == "cs"
+
+    def test_proxy_passed_through_and_empty_means_direct(self):
+        with patch("app.api.besttime_client.httpx.AsyncClient") as mock_cls:
+            self._client(proxy_url="http://proxy.internal:3128")
+            assert mock_cls.call_args.kwargs["proxy"] == "http://proxy.internal:3128"
+            self._client(proxy_url="")
+            assert mock_cls.call_args.kwargs["proxy"] is None
+
+
+class TestRetryPolicy:
+    """Retry of transient 429/5xx answers with backoff and jitter."""
+
+    def _response(self, status, body=None, headers=None, method="GET"):
+        return httpx.Response(
+            status, json=body or {"status": "Error"}, headers=headers or {},
+            request=httpx.Request(method, "https://besttime.app/api/v1/forecasts/week/raw"),
+        )
+
+    def _week_raw_body(self):
+        return {
+            "status": "OK",
+            "venue_id": "ven-123",
+            "venue_name": "Test Venue",
+            "venue_address": "123 Main St",
+            "window": {
+                "time_window_start": 0,
+                "time_window_end": 23,
+                "day_window_start_int": 0,
+                "day_window_end_int": 6,
+                "week_window": "This week",
+            },
+            "analysis": {"week_raw": [{"day_int": i, "day_raw": [50] * 24} for i in range(7)]},
+        }
+
+    def test_backoff_is_exponential_capped_and_jittered(self):
+        policy = RetryPolicy(backoff_base_seconds=1.0, backoff_max_seconds=5.0, jitter_ratio=0.2)
+        plain = self._response(503)
+
+        assert policy.wait_seconds(plain, 1, rand=lambda: 0.5) == pytest.approx(2.0)
+        assert policy.wait_seconds(plain, 10, rand=lambda: 0.5) == pytest.approx(5.0)
+        assert policy.wait_seconds(plain, 0, rand=lambda: 0.0) == pytest.approx(0.8)
+        assert policy.wait_seconds(plain, 0, rand=lambda: 1.0) == pytest.approx(1.2)
+
+    def test_retry_after_is_honored_unless_disabled(self):
+        limited = self._response(429, headers={"Retry-After": "7"})
+
+        assert RetryPolicy().wait_seconds(limited, 0) == 7.0
+        assert RetryPolicy(
+            honor_retry_after=False, jitter_ratio=0.0
+        ).wait_seconds(limited, 0) == 1.0
+
+    @pytest.mark.asyncio
+    async def test_read_retries_5xx_then_succeeds(self, api_client):
+        responses = [
+            self._response(503), self._response(502), self._response(200, self._week_raw_body()),
+        ]
+        with patch.object(api_client.client, "request", new_callable=AsyncMock) as mock_request, \
+                patch("app.api.besttime_client.asyncio.sleep", new_callable=AsyncMock) as mock_sleep:
+            mock_request.side_effect = responses
+            result = await api_client.get_week_raw_forecast("ven-123")
+
+        assert result.status == "OK"
+        assert mock_request.await_count == 3
+        assert mock_sleep.await_count == 2
+
+    @pytest.mark.asyncio
+    async def test_persistent_5xx_raises_after_max_attempts(self):
+        client = BestTimeAPIClient(
+            base_url="https://besttime.app/api/v1", api_key_public="pub", api_key_private="priv",
+            retry_policy=RetryPolicy(max_attempts=4),
+        )
+        with patch.object(client.client, "request", new_callable=AsyncMock) as mock_request, \
+                patch("app.api.besttime_client.asyncio.sleep", new_callable=AsyncMock):
+            mock_request.return_value = self._response(500)
+            with pytest.raises(httpx.HTTPStatusError):
+                await client.get_week_raw_forecast("ven-123")
+
+        assert mock_request.await_count == 4
+
+    @pytest.mark.asyncio
+    async def test_status_outside_the_set_is_not_retried(self, api_client):
+        with patch.object(api_client.client, "request", new_callable=AsyncMock) as mock_request:
+            mock_request.return_value = self._response(400)
+            with pytest.raises(httpx.HTTPStatusError):
+                await api_client.get_week_raw_forecast("ven-123")
+
+        assert mock_request.await_count == 1
+
+    @pytest.mark.asyncio
+    async def test_create_does_not_retry_5xx(self, api_client):
+        with patch.object(api_client.client, "request", new_callable=AsyncMock) as mock_request:
+            mock_request.return_value = self._response(503, method="POST")
+            with pytest.raises(httpx.HTTPStatusError):
+                await api_client.add_venue_to_account("Bar", "Rua 1")
+
+        assert mock_request.await_count == 1
+
+    @pytest.mark.asyncio
+    async def test_live_forecast_does_not_retry_5xx(self, api_client):
+        with patch.object(api_client.client, "request", new_callable=AsyncMock) as mock_request:
+            mock_request.return_value = self._response(503, method="POST")
+            with pytest.raises(httpx.HTTPStatusError):
+                await api_client.get_live_forecast(venue_id="ven-123")
+
+        assert mock_request.await_count == 1
+
+    @pytest.mark.asyncio
+    async def test_read_does_not_retry_a_monthly_cap_429(self, api_client):
+        cap = self._response(429, {"status": "Error", "message": "You have reached the max of 100 monthly venues"})
+        with patch.object(api_client.client, "request", new_callable=AsyncMock) as mock_request:
+            mock_request.return_value = cap
+            with pytest.raises(BestTimeAPIError) as excinfo:
+                await api_client.get_week_raw_forecast("ven-123")
+
+        assert excinfo.value.kind == "quota_exceeded"
+        assert mock_request.await_count == 1
+
+
+class TestPerCallTimeoutAndCancellation:
+    """Per-call deadlines and task cancellation reach the HTTP request."""
+
+    @pytest.mark.asyncio
+    async def test_per_call_timeout_overrides_the_client_default(self, api_client):
+        with patch.object(api_client.client, "request", new_callable=AsyncMock) as mock_request:
+            mock_response = Mock()
+            mock_response.status_code = 200
+            mock_response.json.return_value = TestRetryPolicy()._week_raw_body()
+            mock_request.return_value = mock_response
+
+            await api_client.get_week_raw_forecast("ven-1", timeout=2.5)
+            assert mock_request.call_args.kwargs["timeout"] == 2.5
+
+            await api_client.get_week_raw_forecast("ven-1")
+            assert "timeout" not in mock_request.call_args.kwargs
+
+    @pytest.mark.asyncio
+    async def test_cancelling_the_caller_aborts_the_request(self, api_client):
+        started = asyncio.Event()
+
+        async def hang(**kwargs):
+            started.set()
+            await asyncio.Event().wait()
+
+        with patch.object(api_client.client, "request", side_effect=hang):
+            task = asyncio.create_task(api_client.get_week_raw_forecast("ven-1"))
+            await started.wait()
+            task.cancel()
+            with pytest.raises(asyncio.CancelledError):
+                await task
+
+
+class TestTokenBucket:
+    """Client-wide token bucket pacing every BestTime send."""
+
+    def _bucket(self, rate, burst):
+        from app.api.besttime_client import _TokenBucket
+
+        clock = {"now": 1000.0}
+        sleeps: list[float] = []
+
+        async def fake_sleep(seconds: float) -> None:
+            sleeps.append(seconds)
+            clock["now"] += seconds
+
+        bucket = _TokenBucket(rate, burst, time_func=lambda: clock["now"], sleep_func=fake_sleep)
+        return bucket, clock, sleeps
+
+    @pytest.mark.asyncio
+    async def test_burst_passes_then_paces_at_the_rate(self):
+        bucket, _, sleeps = self._bucket(rate=2.0, burst=3)
+
+        for _ in range(5):
+            await bucket.acquire()
+
+        assert sleeps == [pytest.approx(0.5), pytest.approx(0.5)]
+
+    @pytest.mark.asyncio
+    async def test_tokens_refill_while_idle(self):
+        bucket, clock, sleeps = self._bucket(rate=1.0, burst=2)
+        await bucket.acquire()
+        await bucket.acquire()
+        clock["now"] += 5.0  # refills to the burst cap, not beyond
+
+        await bucket.acquire()
+        await bucket.acquire()
+        assert sleeps == []
+        await bucket.acquire()
+        assert sleeps == [pytest.approx(1.0)]
+
+    @pytest.mark.asyncio
+    async def test_disabled_rate_never_waits(self):
+        bucket, _, sleeps = self._bucket(rate=0, burst=1)
+
+        for _ in range(10):
+            assert await bucket.acquire() == 0.0
+        assert sleeps == []
+
+    @pytest.mark.asyncio
+    async def test_every_send_takes_a_token(self, api_client):
+        api_client._throttle = Mock(acquire=AsyncMock(return_value=0.0))
+        with patch.object(api_client.client, "request", new_callable=AsyncMock) as mock_request:
+            mock_response = Mock()
+            mock_response.status_code = 200
+            mock_response.json.return_value = TestRetryPolicy()._week_raw_body()
+            mock_request.return_value = mock_response
+            await api_client.get_week_raw_forecast("ven-1")
+
+        assert api_client._throttle.acquire.await_count == 1
+
+
+class TestCircuitBreaker:
+    """Consecutive BestTime failures open the circuit; calls then fail fast."""
+
+    def _client(self, clock):
+        client = BestTimeAPIClient(
+            base_url="https://besttime.app/api/v1", api_key_public="pub", api_key_private="priv",
+            retry_policy=RetryPolicy(max_attempts=1),
+            circuit_failure_threshold=2,
+            circuit_cooldown_seconds=30.0,
+        )
+        client.circuit._time = lambda: clock["now"]
+        return client
+
+    def _ok(self):
+        helper = TestRetryPolicy()
+        return helper._response(200, helper._week_raw_body())
+
+    @pytest.mark.asyncio
+    async def test_opens_after_consecutive_failures_and_short_circuits(self):
+        from app.api import BestTimeCircuitOpenError
+
+        clock = {"now": 0.0}
+        client = self._client(clock)
+        with patch.object(client.client, "request", new_callable=AsyncMock) as mock_request:
+            mock_request.side_effect = httpx.ConnectError("down")
+            for _ in range(2):
+                with pytest.raises(httpx.ConnectError):
+                    await client.get_week_raw_forecast("ven-1")
+            assert client.circuit.snapshot() == {"state": "open", "consecutive_failures": 2}
+
+            with pytest.raises(BestTimeCircuitOpenError):
+                await client.get_week_raw_forecast("ven-1")
+        assert mock_request.await_count == 2  # the short-circuited call was never sent
+
+    @pytest.mark.asyncio
+    async def test_half_open_trial_closes_on_success_or_reopens_on_failure(self):
+        from app.api import BestTimeCircuitOpenError
+
+        clock = {"now": 0.0}
+        client = self._client(clock)
+        client.circuit.record_failure()
+        client.circuit.record_failure()
+        clock["now"] = 31.0
+        assert client.circuit.state == "half_open"
+
+        with patch.object(client.client, "request", new_callable=AsyncMock) as mock_request:
+            mock_request.return_value = TestRetryPolicy()._response(503)
+            with pytest.raises(httpx.HTTPStatusError):
+                await client.get_week_raw_forecast("ven-1")
+            assert client.circuit.state == "open"
+            with pytest.raises(BestTimeCircuitOpenError):
+                await client.get_week_raw_forecast("ven-1")
+
+            clock["now"] = 62.0
+            mock_request.return_value = self._ok()
+            await client.get_week_raw_forecast("ven-1")
+        assert client.circuit.snapshot() == {"state": "closed", "consecutive_failures": 0}
+
+    @pytest.mark.asyncio
+    async def test_client_errors_do_not_count(self):
+        clock = {"now": 0.0}
+        client = self._client(clock)
+        with patch.object(client.client, "request", new_callable=AsyncMock) as mock_request:
+            mock_request.return_value = TestRetryPolicy()._response(400)
+            for _ in range(3):
+                with pytest.raises(httpx.HTTPStatusError):
+                    await client.get_week_raw_forecast("ven-1")
+        assert client.circuit.state == "closed"
+
+
+class TestTypedErrors:
+    """Non-2xx BestTime answers surface as classified BestTimeAPIError."""
+
+    def _error(self, status, body):
+        request = httpx.Request("GET", "https://besttime.app/api/v1/forecasts/week/raw")
+        response = httpx.Response(status, json=body, request=request)
+        return BestTimeAPIError.from_status_error(
+            httpx.HTTPStatusError("boom", request=request, response=response),
+            "/forecasts/week/raw",
+        )
+
+    @pytest.mark.parametrize(
+        "status, message, kind, action",
+        [
+            (400, "Not enough credits left on the account", "quota_exceeded", "abort"),
+            (429, "You have reached the max of 100 monthly venues", "quota_exceeded", "abort"),
+            (401, "Invalid private API key", "unauthorized", "abort"),
+            (404, "Venue not found", "invalid_venue", "skip"),
+            (400, "Could not find venue_id", "invalid_venue", "skip"),
+            (503, "Service unavailable", "transient", "retry"),
+            (400, "Missing parameter lat", "rejected", "skip"),
+        ],
+    )
+    def test_classification(self, status, message, kind, action):
+        error = self._error(status, {"status": "Error", "message": message})
+
+        assert (error.kind, error.action) == (kind, action)
+        assert error.status_code == status
+        assert error.message == message
+        assert isinstance(error, httpx.HTTPStatusError)
+
+    def test_quota_fields_and_unparseable_body(self):
+        error = self._error(400, {"message": "Not enough credits", "credits_forecast": 0})
+        assert error.quota == {"credits_forecast": 0}
+
+        request = httpx.Request("GET", "https://besttime.app/api/v1/venues/filter")
+        response = httpx.Response(502, text="<html>Bad gateway</html>", request=request)
+        error = BestTimeAPIError.from_status_error(
+            httpx.HTTPStatusError("boom", request=request, response=response), "/venues/filter"
+        )
+        assert (error.kind, error.message, error.quota) == ("transient", None, {})
+
+    @pytest.mark.asyncio
+    async def test_client_raises_the_typed_error(self, api_client):
+        api_client.retry_policy = RetryPolicy(max_attempts=1)
+        with patch.object(api_client.client, "request", new_callable=AsyncMock) as mock_request:
+            mock_request.return_value = httpx.Response(
+                404, json={"status": "Error", "message": "Venue not found"},
+                request=httpx.Request("GET", "https://besttime.app/api/v1/forecasts/week/raw"),
+            )
+            with pytest.raises(BestTimeAPIError) as excinfo:
+                await api_client.get_week_raw_forecast("ven-404")
+
+        assert excinfo.value.kind == "invalid_venue"
+        assert "Venue not found" in str(excinfo.value)
+
+
+class TestKeyRotation:
+    """Several BestTime key pairs, rotated round-robin or on quota answers."""
+
+    def _client(self, rotation="failover"):
+        return BestTimeAPIClient(
+            base_url="https://besttime.app/api/v1", api_key_public="pub_a", api_key_private="pri_a",
+            extra_key_pairs=[("pub_b", "pri_b")], key_rotation=rotation,
+        )
+
+    @staticmethod
+    def _sent_keys(mock_request):
+        return [call.kwargs["params"]["api_key_public"] for call in mock_request.await_args_list]
+
+    @pytest.mark.asyncio
+    async def test_round_robin_alternates_pairs(self):
+        client = self._client("round_robin")
+        ok = TestRetryPolicy()._response(200, TestRetryPolicy()._week_raw_body())
+        with patch.object(client.client, "request", new_callable=AsyncMock) as mock_request:
+            mock_request.return_value = ok
+            for _ in range(3):
+                await client.get_week_raw_forecast("ven-123")
+
+        assert self._sent_keys(mock_request) == ["pub_a", "pub_b", "pub_a"]
+
+    @pytest.mark.asyncio
+    async def test_quota_answer_fails_over_to_the_next_pair(self):
+        client = self._client()
+        policy = TestRetryPolicy()
+        out_of_credits = policy._response(402, {"status": "Error", "message": "Not enough credits"})
+        ok = policy._response(200, policy._week_raw_body())
+        with patch.object(client.client, "request", new_callable=AsyncMock) as mock_request:
+            mock_request.side_effect = [out_of_credits, ok, ok]
+            await client.get_week_raw_forecast("ven-123")
+            await client.get_week_raw_forecast("ven-123")
+
+        # The exhausted pair stays out of rotation for the following calls.
+        assert self._sent_keys(mock_request) == ["pub_a", "pub_b", "pub_b"]
+        assert client.keys.snapshot() == [
+            {"key": "...ub_a", "available": False},
+            {"key": "...ub_b", "available": True},
+        ]
+
+    @pytest.mark.asyncio
+    async def test_every_pair_exhausted_surfaces_the_quota_error(self):
+        client = self._client()
+        policy = TestRetryPolicy()
+        out_of_credits = policy._response(402, {"status": "Error", "message": "Not enough credits"})
+        with patch.object(client.client, "request", new_callable=AsyncMock) as mock_request:
+            mock_request.return_value = out_of_credits
+            with pytest.raises(BestTimeAPIError) as excinfo:
+                await client.get_week_raw_forecast("ven-123")
+
+        assert excinfo.value.kind == "quota_exceeded"
+        assert mock_request.await_count == 2
+
+    @pytest.mark.asyncio
+    async def test_inventory_pages_stay_on_one_pair(self):
+        client = self._client("round_robin")
+        policy = TestRetryPolicy()
+        page = policy._response(200, [{"venue_id": "ven-1"}])
+        with patch.object(client.client, "request", new_callable=AsyncMock) as mock_request:
+            mock_request.side_effect = [page, page, policy._response(200, [])]
+            rows = [row async for row in client.list_account_inventory(page_size=1)]
+
+        assert len(rows) == 2
+        assert self._sent_keys(mock_request) == ["pub_a", "pub_a", "pub_a"]
+
+    @pytest.mark.asyncio
+    async def test_pinned_calls_stay_on_one_pair_even_out_of_credits(self):
+        client = self._client("round_robin")
+        policy = TestRetryPolicy()
+        out_of_credits = policy._response(402, {"status": "Error", "message": "Not enough credits"})
+        ok = policy._response(200, policy._week_raw_body())
+        with patch.object(client.client, "request", new_callable=AsyncMock) as mock_request:
+            mock_request.side_effect = [ok, out_of_credits, ok]
+            with pinned_key_pair(client):
+                await client.get_week_raw_forecast("ven-123")
+                with pytest.raises(BestTimeAPIError):
+                    await client.get_week_raw_forecast("ven-123")
+            await client.get_week_raw_forecast("ven-123")
+
+        # No resend on pub_b inside the block; rotation resumes after it.
+        assert self._sent_keys(mock_request) == ["pub_a", "pub_a", "pub_b"]
+        assert pinned_key_pair(AsyncMock()).__enter__() is None  # stand-ins pin nothing
+
+    def test_unknown_rotation_is_rejected(self):
+        with pytest.raises(ValueError):
+            self._client("random")
@@ -0,0 +1,110 @@
+"""Unit tests for BestTime body decoding and drift reporting
+(app/api/besttime_decoding.py)."""
+import importlib
+from types import SimpleNamespace
+from unittest.mock import AsyncMock, Mock, patch
+
+import pytest
+from fastapi import FastAPI
+from fastapi.testclient import TestClient
+
+from app.api import BestTimeAPIClient
+from app.api.besttime_decoding import BestTimeDecoder, BestTimeSchemaError, inspect_payload
+from app.models import LiveForecastResponse, VenueFilterParams, VenueFilterResponse
+
+admin_trigger_router = importlib.import_module("app.routers.admin_trigger_router")
+
+_LIVE = {
+    "status": "OK",
+    "analysis": {
+        "venue_forecasted_busyness": 40, "venue_live_busyness": 55,
+        "venue_live_busyness_available": True, "venue_forecast_busyness_available": True,
+        "venue_live_forecasted_delta": 15,
+    },
+    "venue_info": {
+        "venue_current_gmttime": "", "venue_current_localtime": "", "venue_id": "v1",
+        "venue_name": "Bar", "venue_timezone": "America/Recife", "venue_dwell_time_min": 0,
+        "venue_dwell_time_max": 0, "venue_dwell_time_avg": 0,
+    },
+}
+
+
+def _filter_body(**venue_changes):
+    venue = {
+        "venue_id": "v1", "venue_name": "Bar", "venue_address": "Rua X",
+        "venue_lat": -8.0, "venue_lng": -34.9, "day_int": 0, "day_raw": [10] * 24,
+    }
+    venue.update(venue_changes)
+    return {"status": "OK", "venues_n": 1, "venues": [venue], "_links": {"radar": "x"}}
+
+
+def test_inspect_payload_finds_unknown_and_missing_fields():
+    body = _filter_body(venue_score=9, day_info={"day_int": 0, "day_text": "Monday", "extra": 1})
+    del body["venues"][0]["venue_address"]
+
+    unknown, missing = inspect_payload(VenueFilterResponse, body)
+
+    assert unknown == ["venues[].day_info.extra", "venues[].venue_score"]
+    # Zero-defaulted DayInfo fields count; None-defaulted ones do not.
+    assert "venues[].venue_address" in missing
+    assert "venues[].day_info.venue_open" in missing
+    assert "venues[].rating" not in missing and "window" not in missing
+    assert inspect_payload(LiveForecastResponse, _LIVE) == ([], [])
+
+
+def test_lenient_mode_counts_drift_and_still_decodes():
+    decoder = BestTimeDecoder()
+    body = _filter_body(venue_score=9)
+
+    response = decoder.decode(VenueFilterResponse, body, "/venues/filter")
+    decoder.decode(VenueFilterResponse, body, "/venues/filter")
+
+    assert response.venues[0].venue_id == "v1"
+    assert decoder.snapshot()["unknown_fields"] == {"/venues/filter": {"venues[].venue_score": 2}}
+
+
+def test_strict_mode_rejects_drift_with_every_field_listed():
+    decoder = BestTimeDecoder(strict=True)
+    body = _filter_body(venue_lon=-34.9)
+    del body["venues"][0]["venue_lng"]
+
+    with pytest.raises(BestTimeSchemaError) as raised:
+        decoder.decode(VenueFilterResponse, body, "/venues/filter")
+
+    assert raised.value.problems == [
+        "venues[].venue_lon: unknown field", "venues[].venue_lng: missing",
+    ]
+    assert decoder.snapshot()["recent_errors"][0]["endpoint"] == "/venues/filter"
+    assert decoder.decode(LiveForecastResponse, _LIVE, "/forecasts/live").analysis.venue_live_busyness == 55
+
+
+def test_invalid_bodies_report_each_field():
+    with pytest.raises(BestTimeSchemaError) as raised:
+        BestTimeDecoder().decode(VenueFilterResponse, _filter_body(venue_lat="north", day_raw=None), "/venues/filter")
+
+    assert [p.split(":")[0] for p in raised.value.problems] == [
+        "venues[0].day_raw", "venues[0].venue_lat",
+    ]
+    # Still a ValueError, as pydantic's errors were.
+    assert isinstance(raised.value, ValueError)
+
+
+@pytest.mark.asyncio
+async def test_client_decodes_through_the_decoder():
+    client = BestTimeAPIClient(
+        base_url="https://besttime.app/api/v1", api_key_public="pub", api_key_private="pri",
+        strict_decoding=True,
+    )
+    response = Mock(status_code=200)
+    response.json.return_value = _filter_body(venue_score=9)
+    with patch.object(client.client, "request", new_callable=AsyncMock, return_value=response):
+        with pytest.raises(BestTimeSchemaError):
+            await client.venue_filter(VenueFilterParams(lat=-8.0, lng=-34.9, radius=1000))
+
+    admin_trigger_router.set_container(SimpleNamespace(besttime_api=client))
+    app = FastAPI()
+    app.include_router(admin_trigger_router.router)
+    body = TestClient(app).get("/admin/besttime/schema-drift").json()
+    assert body["strict"] is True
+    assert body["unknown_fields"] == {"/venues/filter": {"venues[].venue_score": 1}}
+    await client.close()
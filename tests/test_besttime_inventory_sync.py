@@ -274,6 +274,7 @@ async def test_sync_account_inventory_skips_existing_and_upserts_missing(refresh
         "skipped": 1,
         "errors": 0,
         "deprecated": 0,
+        "rejected": 0,
     }
     assert fake.get("venues_geo_place_v1:v_missing") is not None
 
@@ -327,8 +328,8 @@ async def test_sync_continues_on_per_venue_error(refresher_pair):
                 venue_id="v_ok",
                 venue_name="ok",
                 venue_address="a",
-                venue_lat=0.0,
-                venue_lng=0.0,
+                venue_lat=-8.0,
+                venue_lng=-34.9,
             ),
         ]
     ]
@@ -0,0 +1,91 @@
+"""Unit tests for capturing BestTime `_links` from discovery responses and
+surfacing them on the admin jobs API. fakeredis only."""
+from types import SimpleNamespace
+
+import fakeredis
+import pytest
+from fastapi import FastAPI
+from fastapi.testclient import TestClient
+
+from app.dao.redis_venue_dao import RedisVenueDAO
+from app.db.geo_redis_client import GeoRedisClient
+from app.models import VenueFilterParams, VenueFilterResponse
+from app.routers.admin_trigger_router import router, set_container
+from app.services.venues_refresher_service import VenuesRefresherService
+
+_LINKS = {
+    "venue_search_progress": "https://besttime.app/api/v1/venues/progress?job_id=j1",
+    "radar_tool": "https://besttime.app/radar?collection_id=c1",
+}
+
+
+class _FilterStub:
+    def __init__(self, response):
+        self.response = response
+
+    async def venue_filter(self, params):
+        return self.response
+
+
+def _refresher(response):
+    raw = fakeredis.FakeRedis(decode_responses=True)
+    dao = RedisVenueDAO(GeoRedisClient(raw))
+    return VenuesRefresherService(dao, _FilterStub(response), redis_client=raw)
+
+
+def test_response_parses_links_alias():
+    resp = VenueFilterResponse(**{"status": "OK", "venues": [], "venues_n": 0, "_links": _LINKS})
+    assert resp.links == _LINKS
+
+
+@pytest.mark.asyncio
+async def test_links_recorded_newest_first_with_query():
+    resp = VenueFilterResponse(status="OK", venues=[], venues_n=0, links=_LINKS)
+    refresher = _refresher(resp)
+    await refresher.discover_and_upsert_venues_via_filter(VenueFilterParams(lat=-8.0, lng=-34.9, radius=1000))
+    await refresher.discover_and_upsert_venues_via_filter(VenueFilterParams(lat=-7.9, lng=-34.8, radius=2000))
+
+    runs = refresher.get_besttime_links()
+    assert [r["lat"] for r in runs] == [-7.9, -8.0]
+    assert runs[0]["links"] == _LINKS and runs[0]["captured_at"]
+
+
+@pytest.mark.asyncio
+async def test_api_keys_in_links_are_never_stored_or_served():
+    links = {
+        "venue_search_progress": (
+            "https://besttime.app/api/v1/venues/progress?job_id=j1"
+            "&api_key_private=pri_0123456789abcdef0123456789abcdef&api_key_public=pub_abc"
+        ),
+    }
+    refresher = _refresher(VenueFilterResponse(status="OK", venues=[], venues_n=0, links=links))
+    await refresher.discover_and_upsert_venues_via_filter(VenueFilterParams(lat=-8.0, lng=-34.9, radius=1000))
+
+    stored = refresher.redis_client.lrange(refresher.BESTTIME_LINKS_KEY, 0, -1)
+    served = refresher.get_besttime_links()[0]["links"]["venue_search_progress"]
+    assert "pri_0123" not in stored[0] and "pub_abc" not in stored[0]
+    assert served == (
+        "https://besttime.app/api/v1/venues/progress?job_id=j1"
+        "&api_key_private=***REDACTED***&api_key_public=***REDACTED***"
+    )
+
+
+@pytest.mark.asyncio
+async def test_response_without_links_records_nothing():
+    refresher = _refresher(VenueFilterResponse(status="OK", venues=[], venues_n=0))
+    await refresher.discover_and_upsert_venues_via_filter(VenueFilterParams(lat=-8.0, lng=-34.9, radius=1000))
+    assert refresher.get_besttime_links() == []
+
+
+@pytest.mark.asyncio
+async def test_admin_endpoint_surfaces_links():
+    resp = VenueFilterResponse(status="OK", venues=[], venues_n=0, links=_LINKS)
+    refresher = _refresher(resp)
+    await refresher.discover_and_upsert_venues_via_filter(VenueFilterParams(lat=-8.0, lng=-34.9, radius=1000))
+
+    app = FastAPI()
+    app.include_router(router)
+    set_container(SimpleNamespace(venues_refresher_service=refresher))
+    body = TestClient(app).get("/admin/jobs/venue_catalog/besttime-links").json()
+    assert body["job"] == "venue_catalog"
+    assert body["runs"][0]["links"] == _LINKS
@@ -0,0 +1,123 @@
+"""Unit tests for the BestTime -> Venue mappers (app/models/besttime_mappers.py)
+and the forecast days discovery stores through them."""
+from unittest.mock import AsyncMock, Mock
+
+import fakeredis
+import pytest
+
+from app.dao.redis_venue_dao import RedisVenueDAO
+from app.db.geo_redis_client import GeoRedisClient
+from app.models import (
+    AccountInventoryVenue,
+    FootTrafficForecast,
+    NewVenueResponse,
+    VenueFilterParams,
+    VenueFilterResponse,
+    merge_forecast_days,
+    venue_from_filter,
+    venue_from_inventory,
+    venue_from_new_venue,
+)
+from app.services.venues_refresher_service import VenuesRefresherService
+
+_DAY_INFO = {"day_int": 4, "day_max": 90, "venue_open": 18, "venue_closed": 2}
+
+
+def test_filter_row_keeps_its_day_of_forecast():
+    row = VenueFilterResponse.model_validate({
+        "status": "OK",
+        "venues_n": 1,
+        "venues": [{
+            "venue_id": "ven_1", "venue_name": "Bar do Zé", "venue_address": "Rua X",
+            "venue_lat": -8.06, "venue_lng": -34.87, "price_level": 2,
+            "day_int": 4, "day_raw": [10] * 24, "day_info": _DAY_INFO,
+        }],
+    }).venues[0]
+
+    venue = venue_from_filter(row, geo_linked=True)
+
+    assert (venue.venue_id, venue.venue_lng, venue.forecast, venue.geo_linked) == (
+        "ven_1", -34.87, True, True,
+    )
+    assert (venue.besttime_price_level, venue.price_level) == (2, None)
+    [day] = venue.venue_foot_traffic_forecast
+    assert (day.day_int, day.day_raw, day.day_info.venue_open) == (4, [10] * 24, "18")
+
+
+def test_create_response_keeps_the_analysis_days():
+    response = NewVenueResponse.model_validate({
+        "status": "OK",
+        "venue_info": {
+            "venue_id": "ven_2", "venue_name": "Tasca", "venue_address": "Rua Y",
+            "venue_lat": 38.71, "venue_lon": -9.14, "rating": 4.4,
+        },
+        "analysis": [
+            {"day_info": _DAY_INFO, "day_raw": [30] * 24},
+            {"day_int": 5, "day_raw": [40] * 24},
+        ],
+    })
+
+    venue = venue_from_new_venue(response)
+
+    assert (venue.venue_id, venue.venue_lat, venue.venue_lng, venue.rating) == (
+        "ven_2", 38.71, -9.14, 4.4,
+    )
+    assert [(d.day_int, d.day_raw[0]) for d in venue.venue_foot_traffic_forecast] == [
+        (4, 30), (5, 40),
+    ]
+    assert venue.venue_foot_traffic_forecast[0].day_info.day_max == 90
+    # No analysis yet (fresh create): no forecast rather than an empty one.
+    response.analysis = []
+    assert venue_from_new_venue(response).venue_foot_traffic_forecast is None
+
+
+def test_inventory_row():
+    inv = AccountInventoryVenue(venue_id="ven_3", venue_name=None, venue_lat=-8.0, venue_lng=-34.9)
+
+    venue = venue_from_inventory(inv, venue_name="Fallback")
+
+    assert (venue.venue_id, venue.venue_name, venue.venue_address, venue.forecast) == (
+        "ven_3", "Fallback", "", False,
+    )
+    assert venue.venue_foot_traffic_forecast is None
+
+
+def test_merge_forecast_days():
+    stored = [FootTrafficForecast(day_int=d, day_raw=[d] * 24) for d in (0, 4)]
+    fresh = [
+        FootTrafficForecast(day_int=4, day_raw=[99] * 24),
+        FootTrafficForecast(day_int=2, day_raw=[7] * 24),
+    ]
+
+    merged = merge_forecast_days(stored, fresh)
+
+    assert [(d.day_int, d.day_raw[0]) for d in merged] == [(0, 0), (2, 7), (4, 99)]
+    # A day without busyness keeps the stored curve.
+    assert merge_forecast_days(stored, [FootTrafficForecast(day_int=0, day_raw=[])])[0].day_raw == [0] * 24
+    assert merge_forecast_days(None, None) is None
+
+
+def _filter_response(day_int, day_raw):
+    return VenueFilterResponse.model_validate({
+        "status": "OK",
+        "venues_n": 1,
+        "venues": [{
+            "venue_id": "ven_1", "venue_name": "Bar do Zé", "venue_address": "Rua X",
+            "venue_lat": -8.06, "venue_lng": -34.87, "day_int": day_int, "day_raw": day_raw,
+        }],
+    })
+
+
+@pytest.mark.asyncio
+async def test_discovery_builds_up_the_stored_week():
+    dao = RedisVenueDAO(GeoRedisClient(fakeredis.FakeRedis(decode_responses=True)))
+    api = Mock()
+    refresher = VenuesRefresherService(dao, api)
+    params = VenueFilterParams(lat=-8.06, lng=-34.87, radius=1000)
+
+    for day_int, day_raw in ((0, [20] * 24), (5, [80] * 24), (0, [])):
+        api.venue_filter = AsyncMock(return_value=_filter_response(day_int, day_raw))
+        await refresher.discover_and_upsert_venues_via_filter(params)
+
+    days = dao.get_venue("ven_1").venue_foot_traffic_forecast
+    assert [(d.day_int, d.day_raw[0]) for d in days] == [(0, 20), (5, 80)]
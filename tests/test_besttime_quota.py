@@ -0,0 +1,119 @@
+"""Unit tests for BestTime credit usage tracking (app/services/besttime_quota.py)."""
+from datetime import date
+from types import SimpleNamespace
+from unittest.mock import AsyncMock, patch
+
+import fakeredis
+import pytest
+from fastapi import FastAPI
+from fastapi.testclient import TestClient
+
+from app.api import BestTimeAPIClient
+from app.routers.admin_trigger_router import router, set_container
+from app.services.besttime_quota import (
+    CREDIT_USAGE_KEY_PREFIX,
+    BestTimeQuotaService,
+    endpoint_family,
+    quota_exceeded,
+)
+from tests.test_besttime_client import TestRetryPolicy
+
+
+def _quota(budget=0, day=date(2026, 3, 14)):
+    clock = {"today": day}
+    service = BestTimeQuotaService(
+        fakeredis.FakeRedis(decode_responses=True),
+        daily_budget=budget,
+        today=lambda: clock["today"],
+    )
+    return service, clock
+
+
+def test_endpoint_families():
+    assert endpoint_family("/forecasts") == "search"
+    assert endpoint_family("/venues/filter") == "filter"
+    assert endpoint_family("/forecasts/live") == "live"
+    assert endpoint_family("/forecasts/week/raw2") == "week"
+    assert endpoint_family("/forecasts/hour/raw") == "forecast"
+    assert endpoint_family("/keys") == "other"
+
+
+def test_usage_is_counted_per_family_and_rolls_over_daily():
+    quota, clock = _quota()
+    quota.record("/forecasts/live")
+    quota.record("/forecasts/live")
+    quota.record("/venues/filter", credits=12)
+
+    usage = quota.usage()
+    assert usage["date"] == "2026-03-14"
+    assert usage["by_endpoint"]["live"] == 2
+    assert usage["by_endpoint"]["filter"] == 12
+    assert usage["total"] == 14
+    assert usage["daily_budget"] is None and usage["remaining"] is None
+
+    clock["today"] = date(2026, 3, 15)
+    usage = quota.usage(days=3)
+    assert usage["total"] == 0
+    assert usage["history"] == [
+        {"date": "2026-03-14", "total": 14},
+        {"date": "2026-03-13", "total": 0},
+    ]
+    assert quota.redis.ttl(f"{CREDIT_USAGE_KEY_PREFIX}2026-03-14") > 0
+
+
+def test_budget_is_exceeded_once_reached():
+    quota, _ = _quota(budget=3)
+    quota.record("/forecasts/live", credits=2)
+    assert not quota_exceeded(quota)
+    assert quota.usage()["remaining"] == 1
+
+    quota.record("/forecasts/week/raw")
+    assert quota_exceeded(quota)
+    assert quota.usage()["remaining"] == 0
+    assert not quota_exceeded(None)
+
+
+def test_record_never_raises_when_redis_fails():
+    class BrokenRedis:
+        def pipeline(self):
+            raise ConnectionError("down")
+
+        def hgetall(self, key):
+            raise ConnectionError("down")
+
+    quota = BestTimeQuotaService(BrokenRedis(), daily_budget=1)
+    quota.record("/forecasts/live")
+    assert not quota.exceeded()
+
+
+@pytest.mark.asyncio
+async def test_client_records_credits_for_successful_calls_only():
+    quota, _ = _quota()
+    client = BestTimeAPIClient(
+        base_url="https://besttime.app/api/v1", api_key_public="pub", api_key_private="priv",
+    )
+    client.set_usage_recorder(quota.record)
+    responses = TestRetryPolicy()
+    with patch.object(client.client, "request", new_callable=AsyncMock) as mock_request:
+        mock_request.return_value = responses._response(200, responses._week_raw_body())
+        await client.get_week_raw_forecast("ven-123")
+        mock_request.return_value = responses._response(400, {"status": "Error", "message": "bad"})
+        with pytest.raises(Exception):
+            await client.get_week_raw_forecast("ven-123")
+
+    assert quota.usage()["by_endpoint"]["week"] == 1
+
+
+def test_admin_quota_endpoint():
+    quota, _ = _quota(budget=10)
+    quota.record("/forecasts/live", credits=4)
+    set_container(SimpleNamespace(besttime_quota=quota))
+    app = FastAPI()
+    app.include_router(router)
+    client = TestClient(app)
+
+    body = client.get("/admin/quota").json()
+
+    assert body["total"] == 4
+    assert body["remaining"] == 6
+    assert body["exceeded"] is False
@@ -0,0 +1,91 @@
+"""Unit tests for BestTime record / replay (app/api/besttime_recorder.py)."""
+import gzip
+import json
+
+import httpx
+import pytest
+
+from app.api import BestTimeAPIClient, BestTimeAPIError, ReplayFixtureMissingError
+from app.api.besttime_recorder import RecordingTransport, build_transport, fixture_name
+
+_BASE = "https://besttime.app/api/v1"
+
+
+def _live_body(venue_id):
+    return {
+        "status": "OK",
+        "venue_info": {"venue_id": venue_id, "venue_name": "Bar"},
+        "analysis": {"venue_live_busyness": 60, "venue_live_busyness_available": True},
+    }
+
+
+def _fake_besttime(request: httpx.Request) -> httpx.Response:
+    venue_id = request.url.params.get("venue_id")
+    if venue_id == "missing":
+        return httpx.Response(404, json={"status": "Error", "message": "Venue not found"})
+    return httpx.Response(200, json=_live_body(venue_id))
+
+
+def _client(mode, fixtures_dir, key="secret-key"):
+    return BestTimeAPIClient(
+        base_url=_BASE, api_key_public="pub", api_key_private=key,
+        mode=mode, fixtures_dir=str(fixtures_dir),
+    )
+
+
+def test_fixture_name_ignores_keys_and_param_order():
+    a = httpx.Request("POST", f"{_BASE}/forecasts/live?venue_id=v1&api_key_private=k1")
+    b = httpx.Request("POST", f"{_BASE}/forecasts/live?api_key_private=k2&venue_id=v1")
+    c = httpx.Request("POST", f"{_BASE}/forecasts/live?venue_id=v2&api_key_private=k1")
+
+    assert fixture_name(a) == fixture_name(b)
+    assert fixture_name(a) != fixture_name(c)
+    assert fixture_name(a).startswith("forecasts_live_")
+
+
+@pytest.mark.asyncio
+async def test_recorded_responses_replay_without_the_network(tmp_path):
+    recorder = _client("record", tmp_path)
+    recorder.client._transport = RecordingTransport(httpx.MockTransport(_fake_besttime), str(tmp_path))
+    live = await recorder.get_live_forecast("v1")
+    with pytest.raises(BestTimeAPIError):
+        await recorder.get_live_forecast("missing")
+    await recorder.close()
+
+    files = sorted(p.name for p in tmp_path.iterdir())
+    assert len(files) == 2
+    assert all("secret-key" not in (tmp_path / f).read_text() for f in files)
+
+    replay = _client("replay", tmp_path, key="another-key")
+    assert (await replay.get_live_forecast("v1")) == live
+    with pytest.raises(BestTimeAPIError) as excinfo:
+        await replay.get_live_forecast("missing")
+    assert excinfo.value.kind == "invalid_venue"
+    with pytest.raises(ReplayFixtureMissingError):
+        await replay.get_live_forecast("never-recorded")
+    await replay.close()
+
+
+@pytest.mark.asyncio
+async def test_gzip_responses_are_recorded_and_passed_on_decoded(tmp_path):
+    def gzipped(request):
+        return httpx.Response(
+            200,
+            headers={"content-type": "application/json", "content-encoding": "gzip"},
+            content=gzip.compress(json.dumps(_live_body("v1")).encode()),
+        )
+
+    recorder = _client("record", tmp_path)
+    recorder.client._transport = RecordingTransport(httpx.MockTransport(gzipped), str(tmp_path))
+    live = await recorder.get_live_forecast("v1")
+    await recorder.close()
+
+    assert live.analysis.venue_live_busyness == 60
+    (recorded,) = tmp_path.iterdir()
+    assert json.loads(recorded.read_text())["body"] == _live_body("v1")
+
+
+def test_unknown_mode_is_rejected(tmp_path):
+    assert build_transport("live", str(tmp_path)) is None
+    with pytest.raises(ValueError):
+        build_transport("mock", str(tmp_path))
@@ -0,0 +1,113 @@
+"""Unit tests for busyness trends (app/services/busyness_trend.py).
+
+fakeredis only; local times are America/Recife (UTC-3).
+"""
+from datetime import datetime, timedelta
+
+import fakeredis
+
+from app.config import settings
+from app.dao.redis_venue_dao import RedisVenueDAO
+from app.db.geo_redis_client import GeoRedisClient
+from app.handlers.venue_handler import nearby_response_exclude
+from app.models import Analysis, LiveForecastResponse, LiveHistoryPoint, VenueInfo, WeekRawDay
+from app.services.busyness_trend import (
+    FALLING,
+    RISING,
+    STEADY,
+    TrendService,
+    business_day_int,
+    compute_trend,
+)
+from app.utils.recife_time import RECIFE_TZ
+
+# Friday 20:30 in Recife: day_raw index 14 (6 AM anchor).
+_NOW = RECIFE_TZ.localize(datetime(2026, 6, 5, 20, 30))
+_DAY_RAW = [0] * 14 + [50, 70, 90, 40] + [0] * 6
+
+
+def _point(minutes_ago: int, busyness: int) -> LiveHistoryPoint:
+    return LiveHistoryPoint(timestamp=_NOW - timedelta(minutes=minutes_ago), busyness=busyness)
+
+
+def _live(vid: str, at: datetime, busyness: int, available: bool = True):
+    return LiveForecastResponse(
+        status="OK",
+        analysis=Analysis(venue_live_busyness=busyness,
+                          venue_live_busyness_available=available),
+        venue_info=VenueInfo(venue_id=vid, venue_current_gmttime=at.isoformat()),
+    )
+
+
+class TestComputeTrend:
+    def test_direction_from_hour_old_sample(self):
+        trend = compute_trend(60, [_point(65, 30), _point(10, 55)], None, _NOW)
+
+        assert trend.delta_1h == 30
+        assert trend.direction == RISING
+        assert trend.basis == "history"
+
+    def test_small_change_is_steady_and_falls_are_falling(self):
+        assert compute_trend(35, [_point(60, 30)], None, _NOW).direction == STEADY
+        assert compute_trend(10, [_point(60, 30)], None, _NOW).direction == FALLING
+
+    def test_sample_outside_tolerance_is_ignored(self):
+        trend = compute_trend(60, [_point(120, 10)], None, _NOW)
+
+        assert trend.delta_1h is None
+        assert trend.direction is None
+
+    def test_falls_back_to_forecast_curve(self):
+        trend = compute_trend(50, [], _DAY_RAW, _NOW)
+
+        assert trend.direction == RISING
+        assert trend.basis == "forecast"
+
+    def test_peak_eta_from_forecast(self):
+        trend = compute_trend(50, [], _DAY_RAW, _NOW)
+
+        assert trend.peak_busyness == 90
+        assert trend.peak_in_minutes == 90  # 22:00 local
+
+    def test_peak_now_is_zero_minutes(self):
+        trend = compute_trend(90, [], _DAY_RAW, _NOW.replace(hour=22))
+
+        assert trend.peak_in_minutes == 0
+        assert trend.direction == FALLING
+
+    def test_early_morning_belongs_to_previous_business_day(self):
+        saturday_2am = RECIFE_TZ.localize(datetime(2026, 6, 6, 2, 0))
+
+        assert business_day_int(saturday_2am) == 4  # Friday
+        assert business_day_int(_NOW) == 4
+
+
+class TestTrendService:
+    def _dao(self):
+        return RedisVenueDAO(GeoRedisClient(fakeredis.FakeRedis(decode_responses=True)))
+
+    def test_trends_from_history_and_weekly(self):
+        dao = self._dao()
+        dao.set_live_forecast(_live("v1", _NOW - timedelta(minutes=60), 20))
+        dao.set_live_forecast(_live("v1", _NOW, 65))
+        dao.set_week_raw_forecast("v1", WeekRawDay(day_int=4, day_raw=_DAY_RAW))
+
+        trends = TrendService(dao).trends({"v1": dao.get_live_forecast("v1")}, now=_NOW)
+
+        assert trends["v1"].delta_1h == 45
+        assert trends["v1"].direction == RISING
+        assert trends["v1"].peak_busyness == 90
+
+    def test_skips_venues_without_live_busyness(self):
+        dao = self._dao()
+
+        trends = TrendService(dao).trends({"v2": _live("v2", _NOW, 0, available=False)}, now=_NOW)
+
+        assert trends == {}
+
+
+def test_trend_excluded_from_nearby_when_disabled(monkeypatch):
+    monkeypatch.setattr(settings, "busyness_trend_enabled", False)
+    assert "trend" in nearby_response_exclude()
+    monkeypatch.setattr(settings, "busyness_trend_enabled", True)
+    assert "trend" not in nearby_response_exclude()
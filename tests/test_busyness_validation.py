@@ -0,0 +1,160 @@
+"""Unit tests for the busyness validation stage (reject / clamp / flip-flop)."""
+from datetime import datetime, timedelta, timezone
+from unittest.mock import AsyncMock, Mock
+
+import pytest
+
+from app.metrics import BUSYNESS_VALIDATION_TOTAL, LIVE_FORECAST_FETCH_RESULTS
+from app.models import (
+    Analysis,
+    LiveForecastResponse,
+    RawWindow,
+    VenueInfo,
+    WeekRawAnalysis,
+    WeekRawDay,
+    WeekRawResponse,
+)
+from app.services import VenuesRefresherService
+from app.services.busyness_validation import BusynessValidator
+
+_NOW = datetime(2026, 10, 16, 21, 0, tzinfo=timezone.utc)
+
+
+def _validator(now=_NOW, **kw):
+    kw.setdefault("reject_above", 200)
+    kw.setdefault("flipflop_delta", 50)
+    kw.setdefault("flipflop_swings", 3)
+    return BusynessValidator(now_fn=lambda: now, **kw)
+
+
+def _live(vid, busyness, available=True):
+    return LiveForecastResponse(
+        status="OK",
+        analysis=Analysis(venue_live_busyness=busyness, venue_live_busyness_available=available),
+        venue_info=VenueInfo(venue_id=vid, venue_current_gmttime=_NOW.isoformat()),
+    )
+
+
+class TestCheckValue:
+    def test_accepts_clamps_and_rejects(self):
+        v = _validator()
+        assert v.check_value(42, "live") == 42
+        assert v.check_value(130, "live") == 100
+        assert v.check_value(-1, "live") is None
+        assert v.check_value(201, "live") is None
+        assert v.check_value("50", "live") is None
+        assert v.check_value(True, "live") is None
+
+    def test_counts_outcomes_per_source(self):
+        before = BUSYNESS_VALIDATION_TOTAL.labels(source="weekly", outcome="clamped")._value.get()
+        _validator().check_value(150, "weekly")
+        after = BUSYNESS_VALIDATION_TOTAL.labels(source="weekly", outcome="clamped")._value.get()
+        assert after == before + 1
+
+
+class TestPayloads:
+    def test_live_is_clamped_on_a_copy(self):
+        original = _live("v1", 120)
+        out = _validator().validate_live(original, "live")
+        assert out.analysis.venue_live_busyness == 100
+        assert original.analysis.venue_live_busyness == 120
+
+    def test_unavailable_live_passes_through(self):
+        original = _live("v1", -5, available=False)
+        assert _validator().validate_live(original, "live") is original
+
+    def test_week_day_with_an_impossible_hour_is_rejected(self):
+        v = _validator()
+        assert v.validate_week_day(WeekRawDay(day_int=1, day_raw=[10, 500] + [0] * 22), "weekly") is None
+        clamped = v.validate_week_day(WeekRawDay(day_int=1, day_raw=[10, 110] + [0] * 22), "weekly")
+        assert clamped.day_raw[:2] == [10, 100]
+
+
+class TestFlipFlop:
+    def test_alternating_large_swings_flag_the_venue(self):
+        v = _validator()
+        results = [v.observe("v1", value, "live") for value in (10, 90, 15, 95)]
+        assert results == [False, False, False, True]
+        assert v.flagged_venue_ids() == {"v1"}
+
+    def test_small_or_monotonic_changes_do_not_flag(self):
+        v = _validator()
+        for value in (10, 40, 15, 45):  # swings below the delta
+            v.observe("small", value, "live")
+        for value in (0, 55, 100, 100):  # big but not alternating
+            v.observe("ramp", value, "live")
+        assert v.flagged_venue_ids() == set()
+
+    def test_a_reading_seen_twice_is_recorded_once(self):
+        v = _validator(flipflop_swings=2)
+        at = [(_NOW + timedelta(minutes=m)).isoformat() for m in range(3)]
+        v.observe("v1", 0, "partner", sample_at=at[0])
+        v.observe("v1", 80, "partner", sample_at=at[1])
+        # The same partner reading again (read back through the live map).
+        assert v.observe("v1", 80, "live", sample_at=at[1]) is False
+        assert v.flagged_venue_ids() == set()
+        assert v.observe("v1", 0, "partner", sample_at=at[2]) is True
+
+    def test_flags_expire(self):
+        clock = {"now": _NOW}
+        v = BusynessValidator(reject_above=200, flipflop_delta=50, flipflop_swings=2,
+                              now_fn=lambda: clock["now"])
+        for value in (0, 80, 0):
+            v.observe("v1", value, "live")
+        assert v.flagged_venue_ids() == {"v1"}
+        clock["now"] = _NOW + timedelta(hours=25)
+        assert v.flagged_venue_ids() == set()
+
+
+class TestRefresherValidation:
+    def _service(self):
+        dao = Mock()
+        dao.list_all_venues.return_value = []
+        dao.set_live_forecast.return_value = True
+        besttime = Mock()
+        besttime.get_live_forecast = AsyncMock()
+        besttime.get_week_raw_forecast = AsyncMock()
+        service = VenuesRefresherService(dao, besttime)
+        service.busyness_validator = _validator()
+        return service, dao, besttime
+
+    @pytest.mark.asyncio
+    async def test_impossible_live_value_is_not_cached(self):
+        service, dao, besttime = self._service()
+        besttime.get_live_forecast.return_value = _live("v1", 999)
+        before = LIVE_FORECAST_FETCH_RESULTS.labels(result="rejected_outlier")._value.get()
+
+        await service._fetch_and_cache_live_forecasts(["v1"])
+
+        dao.set_live_forecast.assert_not_called()
+        assert (
+            LIVE_FORECAST_FETCH_RESULTS.labels(result="rejected_outlier")._value.get()
+            == before + 1
+        )
+
+    @pytest.mark.asyncio
+    async def test_over_peak_live_value_is_cached_clamped(self):
+        service, dao, besttime = self._service()
+        besttime.get_live_forecast.return_value = _live("v1", 115)
+
+        await service._fetch_and_cache_live_forecasts(["v1"])
+
+        assert dao.set_live_forecast.call_args[0][0].analysis.venue_live_busyness == 100
+
+    @pytest.mark.asyncio
+    async def test_weekly_skips_only_the_impossible_day(self):
+        service, dao, besttime = self._service()
+        dao.list_servable_venue_ids.return_value = ["v1"]
+        besttime.get_week_raw_forecast.return_value = WeekRawResponse(
+            status="OK",
+            window=RawWindow(),
+            analysis=WeekRawAnalysis(week_raw=[
+                WeekRawDay(day_int=0, day_raw=[10] * 24),
+                WeekRawDay(day_int=1, day_raw=[-3] + [10] * 23),
+            ]),
+        )
+
+        await service.refresh_weekly_forecasts_for_all_venues()
+
+        stored_days = [c[0][1].day_int for c in dao.set_week_raw_forecast.call_args_list]
+        assert stored_days == [0]
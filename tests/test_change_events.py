@@ -0,0 +1,112 @@
+"""Unit tests for the DAO's pub/sub change notifications."""
+from unittest.mock import Mock, patch
+
+import fakeredis
+import pytest
+import redis
+
+from app.dao import RedisVenueDAO
+from app.dao.change_events import (
+    LIVE_FORECAST_DELETED,
+    LIVE_FORECAST_SET,
+    VENUE_CHANGES_CHANNEL,
+    VENUE_UPSERTED,
+    VenueChangeEvent,
+)
+from app.db.geo_redis_client import GeoRedisClient
+from app.metrics import REDIS_DAO_CHANGE_EVENTS_TOTAL
+from app.models import Analysis, LiveForecastResponse, Venue, VenueInfo
+
+
+def _venue(venue_id="v1"):
+    return Venue(venue_id=venue_id, venue_lat=-8.0, venue_lng=-34.9)
+
+
+def _live(venue_id="v1"):
+    return LiveForecastResponse(
+        status="OK",
+        analysis=Analysis(venue_live_busyness=40, venue_live_busyness_available=True),
+        venue_info=VenueInfo(venue_id=venue_id),
+    )
+
+
+def _published(client):
+    events = []
+    for call in client.publish_many.call_args_list:
+        channel, messages = call.args
+        assert channel == VENUE_CHANGES_CHANNEL
+        events.extend(VenueChangeEvent.from_json(m) for m in messages)
+    return [(e.venue_id, e.change_type) for e in events]
+
+
+class TestVenueChangeEvent:
+    def test_round_trip(self):
+        event = VenueChangeEvent("v1", LIVE_FORECAST_SET, 1.5)
+        assert VenueChangeEvent.from_json(event.to_json()) == event
+
+    @pytest.mark.parametrize("raw", ["nope", "{}", '{"venue_id":"v","change_type":"other","at":1}'])
+    def test_rejects_malformed(self, raw):
+        with pytest.raises(ValueError):
+            VenueChangeEvent.from_json(raw)
+
+
+class TestDaoPublishes:
+    def test_upsert_venue(self):
+        client = Mock()
+        client.get.return_value = None
+        RedisVenueDAO(client).upsert_venue(_venue())
+        assert _published(client) == [("v1", VENUE_UPSERTED)]
+
+    def test_upsert_venues_one_batch(self):
+        client = Mock()
+        client.mget.return_value = [None, None]
+        client.add_locations_with_json.return_value = 2
+        RedisVenueDAO(client).upsert_venues([_venue("a"), _venue("b")])
+        assert client.publish_many.call_count == 1
+        assert _published(client) == [("a", VENUE_UPSERTED), ("b", VENUE_UPSERTED)]
+
+    def test_failed_write_publishes_nothing(self):
+        client = Mock()
+        client.get.return_value = None
+        client.add_location_with_json.side_effect = redis.ConnectionError("down")
+        with pytest.raises(redis.ConnectionError):
+            RedisVenueDAO(client).upsert_venue(_venue())
+        client.publish_many.assert_not_called()
+
+    def test_live_set_and_real_delete_only(self):
+        client = Mock()
+        dao = RedisVenueDAO(client)
+        dao.set_live_forecast(_live())
+        client.del_.return_value = 1
+        dao.delete_live_forecast("v1")
+        client.del_.return_value = 0
+        dao.delete_live_forecast("v2")
+        assert _published(client) == [("v1", LIVE_FORECAST_SET), ("v1", LIVE_FORECAST_DELETED)]
+
+    def test_publish_failure_does_not_fail_write(self):
+        client = Mock()
+        client.publish_many.side_effect = redis.ConnectionError("down")
+        errors = REDIS_DAO_CHANGE_EVENTS_TOTAL.labels(change_type=LIVE_FORECAST_SET, result="error")
+        before = errors._value.get()
+        RedisVenueDAO(client).set_live_forecast(_live())
+        client.set.assert_called_once()
+        assert errors._value.get() - before == 1
+
+    def test_disabled(self):
+        client = Mock()
+        with patch("app.dao.redis_venue_dao.settings.redis_change_events_enabled", False):
+            RedisVenueDAO(client).set_live_forecast(_live())
+        client.publish_many.assert_not_called()
+
+
+def test_subscriber_receives_event():
+    raw = fakeredis.FakeRedis(decode_responses=True)
+    pubsub = raw.pubsub(ignore_subscribe_messages=True)
+    pubsub.subscribe(VENUE_CHANGES_CHANNEL)
+    dao = RedisVenueDAO(GeoRedisClient(raw))
+
+    dao.upsert_venue(_venue("v9"))
+
+    message = pubsub.get_message(timeout=1)
+    event = VenueChangeEvent.from_json(message["data"])
+    assert (event.venue_id, event.change_type) == ("v9", VENUE_UPSERTED)
@@ -0,0 +1,111 @@
+"""Unit tests for venue check-ins (app/services/checkins.py), their route and
+their stand-in live busyness."""
+from datetime import datetime, timezone
+
+import fakeredis
+from fastapi import FastAPI
+from fastapi.testclient import TestClient
+
+from app.dao.redis_venue_dao import RedisVenueDAO
+from app.db.geo_redis_client import GeoRedisClient
+from app.handlers.venue_handler import VenueHandler
+from app.models import LiveForecastResponse, Venue
+from app.routers.venue_router import router as venue_router, set_checkin_service, set_venue_handler
+from app.services.checkins import CheckinService, checkins_to_busyness
+
+
+class _Clock:
+    def __init__(self):
+        self.now = datetime.now(timezone.utc).timestamp()
+
+    def __call__(self):
+        return self.now
+
+
+def _setup():
+    raw = fakeredis.FakeRedis(decode_responses=True)
+    dao = RedisVenueDAO(GeoRedisClient(raw))
+    for venue_id in ("quiet", "tracked"):
+        dao.upsert_venue(Venue(venue_id=venue_id, venue_name=venue_id, venue_lat=-8.05, venue_lng=-34.88))
+    clock = _Clock()
+    service = CheckinService(
+        raw, window_minutes=60, dedup_minutes=30, full_count=10, min_count=2,
+        max_distance_m=200, max_per_ip=2, now_fn=clock,
+    )
+    return dao, service, clock
+
+
+def test_dedup_and_window():
+    _, service, clock = _setup()
+
+    assert service.record("quiet", "device-a", "10.0.0.1") is True
+    assert service.record("quiet", "device-a", "10.0.0.2") is False
+    assert service.record("quiet", "device-b", "10.0.0.2") is True
+    assert service.recent_counts(["quiet", "tracked"]) == {"quiet": 2}
+
+    clock.now += 45 * 60
+    assert service.record("quiet", "device-c", "10.0.0.1") is True
+    assert service.recent_counts(["quiet"]) == {"quiet": 3}
+    clock.now += 30 * 60
+    assert service.recent_counts(["quiet"]) == {"quiet": 1}
+    assert service.live("quiet") is None  # under min_count
+
+
+def test_made_up_tokens_from_one_ip_are_capped():
+    _, service, clock = _setup()
+
+    counted = [service.record("quiet", f"device-{n}", "10.0.0.1") for n in range(30)]
+
+    assert counted.count(True) == 2  # max_per_ip
+    assert service.record("quiet", "device-x", "10.0.0.2") is True
+    assert service.record("tracked", "device-y", "10.0.0.1") is True  # per venue
+    clock.now += 31 * 60
+    assert service.record("quiet", "device-z", "10.0.0.1") is True
+
+
+def test_busyness_scale():
+    assert checkins_to_busyness(5, 10) == 50
+    assert checkins_to_busyness(25, 10) == 100
+
+
+def test_checkins_stand_in_only_without_live_data():
+    dao, service, _ = _setup()
+    dao.set_live_forecast(LiveForecastResponse.model_validate({
+        "status": "OK",
+        "analysis": {"venue_live_busyness": 40, "venue_live_busyness_available": True},
+        "venue_info": {"venue_id": "tracked", "venue_current_gmttime": datetime.now(timezone.utc).isoformat()},
+    }))
+    for venue_id in ("quiet", "tracked"):
+        for n in range(3):
+            service.record(venue_id, f"device-{n}", f"10.0.0.{n}")
+    handler = VenueHandler(dao, checkin_service=service)
+
+    items = {v.venue_id: v for v in handler.get_venues_nearby(-8.05, -34.88, 1)}
+
+    assert (items["quiet"].venue_live_busyness, items["quiet"].live_source) == (30, "checkins")
+    assert (items["tracked"].venue_live_busyness, items["tracked"].live_source) == (40, "besttime")
+    assert handler.get_live_forecast("quiet")[1] == "checkins"
+    assert handler.get_live_forecast("tracked")[1] == "besttime"
+
+
+def test_checkin_route():
+    dao, service, _ = _setup()
+    set_venue_handler(VenueHandler(dao))
+    app = FastAPI()
+    app.include_router(venue_router)
+    client = TestClient(app)
+    body = {"device_token": "device-token-1", "lat": -8.0505, "lng": -34.8805}
+
+    set_checkin_service(None)
+    assert client.post("/v1/venues/quiet/checkin", json=body).status_code == 503
+    set_checkin_service(service)
+    try:
+        assert client.post("/v1/venues/quiet/checkin", json=body).json() == {"counted": True}
+        assert client.post("/v1/venues/quiet/checkin", json=body).json() == {"counted": False}
+        assert client.post("/v1/venues/nope/checkin", json=body).status_code == 404
+        assert client.post("/v1/venues/quiet/checkin", json={**body, "device_token": "x"}).status_code == 422
+        assert client.post("/v1/venues/quiet/checkin", json={"device_token": "device-token-2"}).status_code == 422
+        far = {"device_token": "device-token-2", "lat": -8.06, "lng": -34.88}  # ~1.1 km away
+        assert client.post("/v1/venues/quiet/checkin", json=far).status_code == 403
+    finally:
+        set_checkin_service(None)
@@ -0,0 +1,99 @@
+"""Unit tests for the command-line entry point (app/cli.py)."""
+from datetime import date
+from types import SimpleNamespace
+
+import fakeredis
+import pytest
+
+from app import cli
+from app.dao import redis_migrations
+
+
+@pytest.fixture(autouse=True)
+def _env(monkeypatch):
+    monkeypatch.delenv("CONFIG_FILE", raising=False)
+    monkeypatch.setenv("BESTTIME_PRIVATE_KEY", "pri")
+    monkeypatch.setenv("BESTTIME_PUBLIC_KEY", "pub")
+
+
+class _FakeContainer:
+    instances = []
+
+    def __init__(self, settings):
+        self.calls = []
+        self.closed = False
+        self.venues_refresher_service = SimpleNamespace(
+            refresh_venues_by_filter_for_default_locations=self._record("catalog", []),
+            refresh_live_forecasts_for_all_venues=self._record("live", None),
+        )
+        self.history_export_service = None
+        _FakeContainer.instances.append(self)
+
+    def _record(self, name, result):
+        async def call(**kwargs):
+            self.calls.append((name, kwargs))
+            return result
+
+        return call
+
+    async def shutdown(self):
+        self.closed = True
+
+
+@pytest.fixture
+def fake_container(monkeypatch):
+    import app.container
+
+    _FakeContainer.instances = []
+    monkeypatch.setattr(app.container, "Container", _FakeContainer)
+    return _FakeContainer.instances
+
+
+def test_parser_subcommands():
+    parser = cli.build_parser()
+
+    assert parser.parse_args([]).command is None
+    assert parser.parse_args(["refresh-live", "--include-closed"]).include_closed is True
+    assert parser.parse_args(["export", "--date", "2026-03-01"]).date == date(2026, 3, 1)
+    assert parser.parse_args(["migrate", "--target", "2"]).target == 2
+    with pytest.raises(SystemExit) as exc:
+        parser.parse_args(["export", "--date", "yesterday"])
+    assert exc.value.code == 2
+
+
+def test_no_command_serves(monkeypatch):
+    served = []
+    monkeypatch.setattr(cli, "serve", lambda settings: served.append(settings) or 0)
+
+    assert cli.main([]) == 0
+    assert len(served) == 1
+
+
+def test_refresh_commands_run_once_and_shut_down(fake_container):
+    assert cli.main(["refresh"]) == 0
+    assert cli.main(["refresh-live", "--include-closed"]) == 0
+
+    catalog, live = fake_container
+    assert catalog.calls == [("catalog", {})] and catalog.closed
+    assert live.calls == [("live", {"include_closed": True})] and live.closed
+
+
+def test_export_without_configuration_fails(fake_container):
+    assert cli.main(["export"]) == 1
+    assert fake_container[0].closed
+
+
+def test_missing_besttime_keys_fail_before_building_anything(monkeypatch, fake_container):
+    monkeypatch.setenv("BESTTIME_PRIVATE_KEY", "")
+
+    assert cli.main(["refresh"]) == 1
+    assert fake_container == []
+
+
+def test_migrate_applies_the_redis_migrations(monkeypatch):
+    client = fakeredis.FakeRedis(decode_responses=True)
+    monkeypatch.setattr("app.db.redis_factory.build_redis_client", lambda settings: client)
+    monkeypatch.setattr(client, "close", lambda: None)
+
+    assert cli.main(["migrate"]) == 0
+    assert redis_migrations.current_version(client) == redis_migrations.latest_version()
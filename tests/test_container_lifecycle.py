@@ -0,0 +1,81 @@
+"""Container construction with injected clients, shutdown, and environment
+profiles (app/container.py, app/config.py)."""
+import fakeredis
+import pytest
+
+from app.api import BestTimeAPIClient
+from app.config import Settings
+from app.container import Container
+from tests.rds_fake import InMemoryRdsVenueStore
+
+
+@pytest.fixture(autouse=True)
+def _clean_env(monkeypatch):
+    for name in ("CONFIG_FILE", "ENVIRONMENT", "DEV_MODE", "LOG_LEVEL", "BESTTIME_MODE"):
+        monkeypatch.delenv(name, raising=False)
+
+
+class _ClosableRedis(fakeredis.FakeRedis):
+    closed = False
+
+    def close(self):
+        self.closed = True
+        super().close()
+
+
+def _container(**settings):
+    redis_client = _ClosableRedis(decode_responses=True)
+    besttime = BestTimeAPIClient(
+        base_url="https://besttime.test/api/v1", api_key_public="pub", api_key_private="pri"
+    )
+    container = Container(
+        Settings(environment="test", **settings),
+        redis_client=redis_client,
+        besttime_api=besttime,
+        rds_store=InMemoryRdsVenueStore(),
+    )
+    return container, redis_client, besttime
+
+
+async def test_injected_clients_are_used_and_not_closed():
+    container, redis_client, besttime = _container()
+
+    assert container.besttime_api is besttime
+    assert container.redis_client.client is redis_client
+    container.redis_client.client.set("k", "v")
+    assert redis_client.get("k") == "v"
+
+    await container.shutdown()
+    await container.shutdown()  # idempotent
+
+    assert not redis_client.closed
+
+
+class TestEnvironmentProfiles:
+    def test_prod_keeps_the_defaults(self):
+        settings = Settings()
+
+        assert settings.environment == "prod"
+        assert settings.dev_mode is False
+        assert settings.besttime_mode == "live"
+
+    def test_dev_and_test_profiles(self):
+        dev = Settings(environment="development")
+        test = Settings(environment="test")
+
+        assert dev.environment == "dev"
+        assert dev.dev_mode is True and dev.log_level == "DEBUG"
+        assert test.besttime_mode == "replay" and test.config_reload_seconds == 0
+        assert test.config_errors() == []
+
+    def test_explicit_settings_win_over_the_profile(self, monkeypatch):
+        monkeypatch.setenv("LOG_LEVEL", "WARNING")
+        settings = Settings(environment="dev", dev_mode=False)
+
+        assert settings.dev_mode is False
+        assert settings.log_level == "WARNING"
+
+    def test_unknown_environment_is_a_config_error(self):
+        errors = Settings(environment="qa").config_errors()
+
+        assert "environment must be one of prod, staging, dev, test" in errors
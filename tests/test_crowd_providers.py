@@ -0,0 +1,248 @@
+"""Tests for pluggable crowd data providers and their merge policies."""
+from unittest.mock import AsyncMock, Mock
+
+import pytest
+
+from app.metrics import LIVE_FORECAST_FETCH_RESULTS
+from app.models import (
+    Analysis,
+    LiveForecastResponse,
+    RawWindow,
+    VenueInfo,
+    WeekRawAnalysis,
+    WeekRawDay,
+    WeekRawResponse,
+)
+from app.services import VenuesRefresherService
+from app.services.crowd_providers import (
+    BestTimeCrowdProvider,
+    CrowdProviderRegistry,
+    Region,
+    RegionalProvider,
+)
+
+
+def _live(vid, busyness, gmttime="2026-10-16T20:00:00Z", available=True, status="OK"):
+    return LiveForecastResponse(
+        status=status,
+        venue_info=VenueInfo(venue_id=vid, venue_current_gmttime=gmttime),
+        analysis=Analysis(
+            venue_live_busyness=busyness,
+            venue_live_busyness_available=available,
+        ),
+    )
+
+
+class FakeProvider:
+    def __init__(self, name, live=None, week=None, error=None):
+        self.name = name
+        self.live = live
+        self.week = week
+        self.error = error
+        self.calls = []
+
+    def covers(self, venue_id, location):
+        return True
+
+    async def get_live_forecast(self, venue_id):
+        self.calls.append(venue_id)
+        if self.error:
+            raise self.error
+        return self.live
+
+    async def get_week_raw_forecast(self, venue_id):
+        if self.error:
+            raise self.error
+        return self.week
+
+
+class TestMergePolicies:
+    @pytest.mark.asyncio
+    async def test_priority_takes_first_usable_and_stops(self):
+        first = FakeProvider("partner", live=_live("v1", 40))
+        second = FakeProvider("besttime", live=_live("v1", 90))
+        registry = CrowdProviderRegistry([first, second])
+
+        lf = await registry.get_live_forecast("v1")
+
+        assert lf.analysis.venue_live_busyness == 40
+        assert second.calls == []
+
+    @pytest.mark.asyncio
+    async def test_priority_falls_through_unavailable(self):
+        first = FakeProvider("partner", live=_live("v1", 0, available=False))
+        second = FakeProvider("besttime", live=_live("v1", 90))
+        registry = CrowdProviderRegistry([first, second])
+
+        lf = await registry.get_live_forecast("v1")
+
+        assert lf.analysis.venue_live_busyness == 90
+
+    @pytest.mark.asyncio
+    async def test_freshest_picks_latest_sample(self):
+        older = FakeProvider("besttime", live=_live("v1", 90, "2026-10-16T20:00:00Z"))
+        newer = FakeProvider("partner", live=_live("v1", 40, "2026-10-16T20:05:00Z"))
+        registry = CrowdProviderRegistry([older, newer], policy="freshest")
+
+        lf = await registry.get_live_forecast("v1")
+
+        assert lf.analysis.venue_live_busyness == 40
+
+    @pytest.mark.asyncio
+    async def test_no_usable_data_returns_last_answer(self):
+        closed = _live("v1", 0, available=False)
+        registry = CrowdProviderRegistry([FakeProvider("besttime", live=closed)])
+
+        assert await registry.get_live_forecast("v1") is closed
+
+    @pytest.mark.asyncio
+    async def test_error_propagates_only_when_no_provider_answered(self):
+        failing = FakeProvider("partner", error=RuntimeError("down"))
+        registry = CrowdProviderRegistry([failing])
+        with pytest.raises(RuntimeError):
+            await registry.get_live_forecast("v1")
+
+        fallback = FakeProvider("besttime", live=_live("v1", 55))
+        registry = CrowdProviderRegistry([failing, fallback])
+        lf = await registry.get_live_forecast("v1")
+        assert lf.analysis.venue_live_busyness == 55
+
+    @pytest.mark.asyncio
+    async def test_weekly_takes_first_ok(self):
+        def week(status):
+            return WeekRawResponse(
+                status=status,
+                window=RawWindow(),
+                analysis=WeekRawAnalysis(week_raw=[WeekRawDay(day_int=0, day_raw=[1] * 24)]),
+            )
+
+        not_ok, ok = week("Error"), week("OK")
+        registry = CrowdProviderRegistry(
+            [FakeProvider("a", week=not_ok), FakeProvider("b", week=ok)]
+        )
+
+        assert await registry.get_week_raw_forecast("v1") is ok
+
+    def test_unknown_policy_rejected(self):
+        with pytest.raises(ValueError):
+            CrowdProviderRegistry([], policy="average")
+
+
+class TestRegionalCoverage:
+    @pytest.mark.asyncio
+    async def test_regional_provider_only_covers_its_area(self):
+        recife = Region(lat=-8.05, lng=-34.88, radius_km=10)
+        partner = FakeProvider("partner", live=_live("v1", 40))
+        besttime = FakeProvider("besttime", live=_live("v1", 90))
+        locations = {"in": (-8.06, -34.89), "out": (-23.55, -46.63)}
+        registry = CrowdProviderRegistry(
+            [RegionalProvider(partner, regions=[recife]), besttime],
+            locate=locations.get,
+        )
+
+        assert [p.name for p in registry.providers_for("in")] == ["partner", "besttime"]
+        assert [p.name for p in registry.providers_for("out")] == ["besttime"]
+        # Unknown location: only covered when explicitly listed.
+        assert [p.name for p in registry.providers_for("nowhere")] == ["besttime"]
+
+    def test_explicit_venue_ids_are_covered_without_location(self):
+        regional = RegionalProvider(FakeProvider("partner"), venue_ids=frozenset({"v9"}))
+        assert regional.covers("v9", None)
+        assert not regional.covers("v1", None)
+
+
+class TestRefresherUsesRegistry:
+    def _service(self):
+        dao = Mock()
+        dao.list_all_venues.return_value = []
+        dao.set_live_forecast.return_value = True
+        besttime = Mock()
+        besttime.get_live_forecast = AsyncMock(return_value=_live("v1", 90))
+        return VenuesRefresherService(dao, besttime), dao, besttime
+
+    @pytest.mark.asyncio
+    async def test_default_is_besttime_only(self):
+        service, dao, besttime = self._service()
+
+        await service._fetch_and_cache_live_forecasts(["v1"])
+
+        besttime.get_live_forecast.assert_awaited_once_with(venue_id="v1")
+        assert dao.set_live_forecast.call_args[0][0].analysis.venue_live_busyness == 90
+
+    @pytest.mark.asyncio
+    async def test_wired_registry_prefers_partner(self):
+        service, dao, besttime = self._service()
+        partner = FakeProvider("partner", live=_live("v1", 40))
+        service.set_crowd_providers(
+            CrowdProviderRegistry([partner, BestTimeCrowdProvider(besttime)])
+        )
+
+        await service._fetch_and_cache_live_forecasts(["v1"])
+
+        besttime.get_live_forecast.assert_not_awaited()
+        assert dao.set_live_forecast.call_args[0][0].analysis.venue_live_busyness == 40
+
+    @pytest.mark.asyncio
+    async def test_partner_reading_is_neither_cached_nor_charged(self):
+        service, dao, besttime = self._service()
+        budget = Mock()
+        service.set_budget_service(budget)
+        partner = FakeProvider("partner", live=_live("v1", 40))
+        partner.stores_live = True
+        service.set_crowd_providers(
+            CrowdProviderRegistry([partner, BestTimeCrowdProvider(besttime)])
+        )
+
+        counts = await service._fetch_and_cache_live_forecasts(["v1"])
+
+        assert counts["provider_stored"] == 1
+        besttime.get_live_forecast.assert_not_awaited()
+        budget.try_register_touch.assert_not_called()
+        dao.set_live_forecast.assert_not_called()
+        dao.delete_live_forecast.assert_not_called()
+
+    @pytest.mark.asyncio
+    async def test_ledger_gates_only_the_besttime_read(self):
+        service, dao, besttime = self._service()
+        budget = Mock()
+        budget.try_register_touch.return_value = False
+        service.set_budget_service(budget)
+        service.set_crowd_providers(CrowdProviderRegistry(
+            [FakeProvider("partner"), BestTimeCrowdProvider(besttime)]
+        ))
+
+        counts = await service._fetch_and_cache_live_forecasts(["v1"])
+
+        budget.try_register_touch.assert_called_once_with("v1")
+        besttime.get_live_forecast.assert_not_awaited()
+        assert "skipped_no_provider" not in counts
+        dao.set_live_forecast.assert_not_called()
+
+    @pytest.mark.asyncio
+    async def test_uncovered_venue_is_skipped(self):
+        service, dao, _ = self._service()
+        service.set_crowd_providers(CrowdProviderRegistry([]))
+        before = LIVE_FORECAST_FETCH_RESULTS.labels(result="skipped_no_provider")._value.get()
+
+        await service._fetch_and_cache_live_forecasts(["v1"])
+
+        dao.set_live_forecast.assert_not_called()
+        dao.delete_live_forecast.assert_not_called()
+        assert (
+            LIVE_FORECAST_FETCH_RESULTS.labels(result="skipped_no_provider")._value.get()
+            == before + 1
+        )
+
+
+@pytest.mark.asyncio
+async def test_besttime_provider_passes_per_call_timeouts():
+    besttime = Mock()
+    besttime.get_live_forecast = AsyncMock(return_value=_live("v1", 90))
+    besttime.get_week_raw_forecast = AsyncMock(return_value=None)
+    provider = BestTimeCrowdProvider(besttime, live_timeout=3.0, weekly_timeout=20.0)
+
+    await provider.get_live_forecast("v1")
+    await provider.get_week_raw_forecast("v1")
+
+    besttime.get_live_forecast.assert_awaited_once_with(venue_id="v1", timeout=3.0)
+    besttime.get_week_raw_forecast.assert_awaited_once_with("v1", timeout=20.0)
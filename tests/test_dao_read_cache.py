@@ -0,0 +1,167 @@
+"""Unit tests for the serving DAO's in-process read-through cache."""
+from unittest.mock import Mock
+
+import fakeredis
+
+from app.dao import RedisVenueDAO
+from app.dao.read_cache import MISS, DaoReadCache, TTLCache
+from app.db.geo_redis_client import GeoRedisClient
+from app.metrics import REDIS_DAO_READ_CACHE_TOTAL
+from app.models import Analysis, LiveForecastResponse, Venue, VenueInfo
+
+
+class FakeClock:
+    def __init__(self):
+        self.now = 1000.0
+
+    def __call__(self):
+        return self.now
+
+
+def _venue_json(venue_id="v1"):
+    return Venue(
+        venue_id=venue_id, venue_name="Bar", venue_lat=-8.0, venue_lng=-34.9
+    ).model_dump_json()
+
+
+def _live(venue_id="v1", busyness=40):
+    return LiveForecastResponse(
+        status="OK",
+        analysis=Analysis(venue_live_busyness=busyness, venue_live_busyness_available=True),
+        venue_info=VenueInfo(venue_id=venue_id),
+    )
+
+
+class TestTTLCache:
+    def test_miss_then_hit(self):
+        cache = TTLCache("venue", 10, 5, FakeClock())
+        assert cache.get("a") is MISS
+        cache.set("a", None)
+        assert cache.get("a") is None
+
+    def test_entries_expire_after_ttl(self):
+        clock = FakeClock()
+        cache = TTLCache("venue", 10, 5, clock)
+        cache.set("a", 1)
+        clock.now += 4.9
+        assert cache.get("a") == 1
+        clock.now += 0.2
+        assert cache.get("a") is MISS
+        assert len(cache) == 0
+
+    def test_evicts_least_recently_used(self):
+        cache = TTLCache("venue", 2, 60, FakeClock())
+        cache.set("a", 1)
+        cache.set("b", 2)
+        cache.get("a")
+        cache.set("c", 3)
+        assert cache.get("b") is MISS
+        assert cache.get("a") == 1
+        assert cache.get("c") == 3
+
+    def test_counts_hits_and_misses(self):
+        hits = REDIS_DAO_READ_CACHE_TOTAL.labels(cache="live", result="hit")
+        misses = REDIS_DAO_READ_CACHE_TOTAL.labels(cache="live", result="miss")
+        h0, m0 = hits._value.get(), misses._value.get()
+        cache = TTLCache("live", 10, 60, FakeClock())
+        cache.get("a")
+        cache.set("a", 1)
+        cache.get("a")
+        assert hits._value.get() - h0 == 1
+        assert misses._value.get() - m0 == 1
+
+
+class TestCachedDAO:
+    def _dao(self, client=None):
+        return RedisVenueDAO(client or Mock(), read_cache=DaoReadCache(100, 60, FakeClock()))
+
+    def test_without_cache_every_read_hits_redis(self):
+        client = Mock()
+        client.get.return_value = _venue_json()
+        dao = RedisVenueDAO(client)
+        dao.get_venue("v1")
+        dao.get_venue("v1")
+        assert client.get.call_count == 2
+
+    def test_get_venue_served_from_cache(self):
+        client = Mock()
+        client.get.return_value = _venue_json()
+        dao = self._dao(client)
+        first = dao.get_venue("v1")
+        first.venue_name = "mutated by caller"
+        second = dao.get_venue("v1")
+        assert client.get.call_count == 1
+        assert second.venue_name == "Bar"
+
+    def test_missing_venue_is_not_cached(self):
+        client = Mock()
+        client.get.return_value = None
+        dao = self._dao(client)
+        assert dao.get_venue("v1") is None
+        assert dao.get_venue("v1") is None
+        assert client.get.call_count == 2
+
+    def test_nearby_served_from_cache_until_upsert(self):
+        client = Mock()
+        client.get_locations_within_radius.return_value = [_venue_json()]
+        client.get.return_value = None
+        dao = self._dao(client)
+        assert [v.venue_id for v in dao.get_nearby_venues(-8.0, -34.9, 5)] == ["v1"]
+        dao.get_nearby_venues(-8.0, -34.9, 5)
+        assert client.get_locations_within_radius.call_count == 1
+
+        dao.upsert_venue(Venue(venue_id="v2", venue_lat=-8.0, venue_lng=-34.9))
+        dao.get_nearby_venues(-8.0, -34.9, 5)
+        assert client.get_locations_within_radius.call_count == 2
+
+    def test_nearby_key_includes_radius_and_deprecated_flag(self):
+        client = Mock()
+        client.get_locations_within_radius.return_value = []
+        dao = self._dao(client)
+        dao.get_nearby_venues(-8.0, -34.9, 5)
+        dao.get_nearby_venues(-8.0, -34.9, 6)
+        dao.get_nearby_venues(-8.0, -34.9, 5, include_deprecated=True)
+        assert client.get_locations_within_radius.call_count == 3
+
+    def test_read_after_upsert_sees_the_new_venue(self):
+        dao = RedisVenueDAO(
+            GeoRedisClient(fakeredis.FakeRedis(decode_responses=True)),
+            read_cache=DaoReadCache(100, 60, FakeClock()),
+        )
+        dao.upsert_venue(Venue(venue_id="v1", venue_name="Old", venue_lat=-8.0, venue_lng=-34.9))
+        assert dao.get_venue("v1").venue_name == "Old"  # now cached
+
+        dao.upsert_venue(Venue(venue_id="v1", venue_name="New", venue_lat=-8.0, venue_lng=-34.9))
+
+        assert dao.get_venue("v1").venue_name == "New"
+
+    def test_delete_venue_invalidates(self):
+        client = Mock()
+        client.get.return_value = _venue_json()
+        dao = self._dao(client)
+        dao.get_venue("v1")
+        dao.delete_venue("v1")
+        client.get.return_value = None
+        assert dao.get_venue("v1") is None
+
+    def test_live_bulk_fetches_only_misses_and_caches_absence(self):
+        client = Mock()
+        client.mget.return_value = [_live("v1").model_dump_json(), None]
+        dao = self._dao(client)
+        out = dao.get_live_forecasts_bulk(["v1", "v2"])
+        assert set(out) == {"v1"}
+
+        client.mget.reset_mock()
+        client.mget.return_value = [_live("v3").model_dump_json()]
+        out = dao.get_live_forecasts_bulk(["v1", "v2", "v3"])
+        assert set(out) == {"v1", "v3"}
+        assert client.mget.call_args.args[0] == ["live_forecast_v1:v3"]
+
+    def test_set_live_forecast_invalidates(self):
+        client = Mock()
+        client.get.return_value = None
+        dao = self._dao(client)
+        assert dao.get_live_forecast("v1") is None
+        dao.set_live_forecast(_live("v1", 70))
+        client.get.return_value = _live("v1", 70).model_dump_json()
+        assert dao.get_live_forecast("v1").analysis.venue_live_busyness == 70
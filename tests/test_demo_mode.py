@@ -0,0 +1,87 @@
+"""Unit tests for the public demo mode: synthetic catalog + rate limit."""
+from datetime import datetime, timezone
+
+from fastapi import FastAPI
+from fastapi.testclient import TestClient
+
+from app.handlers import VenueHandler
+from app.middleware import DemoRateLimitMiddleware
+from app.routers.venue_router import router as venue_router, set_venue_handler
+from app.services.demo_data import DemoVenueDAO, generate_demo_catalog
+
+_LAT, _LNG = -8.05428, -34.88126
+_NOW = datetime(2026, 6, 5, 23, 0, tzinfo=timezone.utc)  # Friday night
+
+
+def _dao(**kwargs):
+    return DemoVenueDAO(_LAT, _LNG, now_fn=lambda: _NOW, **kwargs)
+
+
+class TestDemoCatalog:
+    def test_catalog_is_deterministic_and_within_spread(self):
+        a = generate_demo_catalog(_LAT, _LNG, 20, 3.0, seed=1)
+        b = generate_demo_catalog(_LAT, _LNG, 20, 3.0, seed=1)
+        assert a == b
+        dao = _dao(count=20, spread_km=3.0, seed=1)
+        assert len(dao.get_nearby_venues(_LAT, _LNG, 3.01)) == 20
+
+    def test_live_tracks_the_weekly_curve(self):
+        dao = _dao(count=8)
+        ids = list(dao.venues)
+        live = dao.get_live_forecasts_bulk(ids)
+        for vid in ids:
+            analysis = live[vid].analysis
+            assert analysis.venue_live_busyness_available
+            assert abs(analysis.venue_live_busyness - analysis.venue_forecasted_busyness) <= 10
+
+    def test_unknown_ids_are_absent(self):
+        dao = _dao(count=2)
+        assert dao.get_live_forecasts_bulk(["nope"]) == {}
+        assert dao.get_week_raw_forecasts_bulk(["nope"], 0) == {}
+
+
+class TestDemoServing:
+    def _client(self, per_minute=0):
+        app = FastAPI()
+        app.add_middleware(DemoRateLimitMiddleware, per_minute=per_minute)
+        app.include_router(venue_router)
+        set_venue_handler(VenueHandler(DemoVenueDAO(_LAT, _LNG, count=10)))
+        return TestClient(app)
+
+    def test_nearby_serves_synthetic_venues(self):
+        resp = self._client().get(f"/v1/venues/nearby?lat={_LAT}&lon={_LNG}&radius=10")
+        assert resp.status_code == 200
+        assert len(resp.json()) == 10
+
+    def test_nearby_accepts_radius_unit_and_rejects_unknown(self):
+        client = self._client()
+        meters = client.get(f"/v1/venues/nearby?lat={_LAT}&lon={_LNG}&radius=10000&unit=m")
+        assert meters.status_code == 200 and len(meters.json()) == 10
+        bad = client.get(f"/v1/venues/nearby?lat={_LAT}&lon={_LNG}&radius=10&unit=yards")
+        assert bad.status_code == 422
+
+    def test_rate_limit_returns_429_with_retry_after(self):
+        client = self._client(per_minute=2)
+        url = f"/v1/venues/nearby?lat={_LAT}&lon={_LNG}&radius=10"
+        assert client.get(url).status_code == 200
+        assert client.get(url).status_code == 200
+        resp = client.get(url)
+        assert resp.status_code == 429
+        assert int(resp.headers["Retry-After"]) >= 1
+
+
+class TestDemoRateLimitWindow:
+    def test_window_resets_after_a_minute(self):
+        clock = [0.0]
+        app = FastAPI()
+        app.add_middleware(DemoRateLimitMiddleware, per_minute=1, clock=lambda: clock[0])
+
+        @app.get("/x")
+        def _x():
+            return {"ok": True}
+
+        client = TestClient(app)
+        assert client.get("/x").status_code == 200
+        assert client.get("/x").status_code == 429
+        clock[0] = 61.0
+        assert client.get("/x").status_code == 200
@@ -0,0 +1,79 @@
+"""Unit tests for the internal diagnostics app (app/diagnostics.py)."""
+import marshal
+import tracemalloc
+
+import pytest
+from fastapi.testclient import TestClient
+
+from app.diagnostics import GcTimer, build_diagnostics_app
+
+
+@pytest.fixture
+def client():
+    jobs = [{"id": "live_forecast", "running": False}]
+    return TestClient(build_diagnostics_app(lambda: jobs))
+
+
+def test_vars_report_runtime_and_jobs(client):
+    body = client.get("/debug/vars").json()
+
+    assert body["jobs"] == [{"id": "live_forecast", "running": False}]
+    assert body["asyncio_tasks"] >= 1
+    assert body["threads"] >= 1
+    assert body["process"]["uptime_seconds"] >= 0
+    assert len(body["gc"]["counts"]) == 3
+    assert body["memory"]["max_rss_bytes"] > 0
+
+
+def test_vars_survive_a_failing_job_source():
+    def broken():
+        raise RuntimeError("scheduler gone")
+
+    body = TestClient(build_diagnostics_app(broken)).get("/debug/vars").json()
+    assert body["jobs"] == {"error": "scheduler gone"}
+
+
+def test_profile_text_and_pstats(client):
+    response = client.get("/debug/pprof/profile", params={"seconds": 0.05, "limit": 5})
+    assert response.status_code == 200
+    assert "function calls" in response.text
+
+    response = client.get("/debug/pprof/profile", params={"seconds": 0.05, "format": "pstats"})
+    assert response.status_code == 200
+    assert isinstance(marshal.loads(response.content), dict)
+
+
+def test_profile_validates_parameters(client):
+    assert client.get("/debug/pprof/profile", params={"seconds": 0}).status_code == 422
+    assert client.get("/debug/pprof/profile", params={"seconds": 1000}).status_code == 422
+    assert client.get(
+        "/debug/pprof/profile", params={"seconds": 0.01, "sort": "name"}
+    ).status_code == 400
+
+
+def test_tasks_dump(client):
+    text = client.get("/debug/pprof/tasks").text
+
+    assert "asyncio tasks" in text
+    assert "threads" in text
+
+
+def test_heap_needs_tracemalloc(client):
+    assert client.get("/debug/pprof/heap").status_code == 409
+
+    tracemalloc.start(1)
+    try:
+        response = client.get("/debug/pprof/heap", params={"limit": 3})
+    finally:
+        tracemalloc.stop()
+    assert response.status_code == 200
+    assert response.text.startswith("traced:")
+
+
+def test_gc_timer_counts_collections():
+    timer = GcTimer()
+    timer("start", {"generation": 2})
+    timer("stop", {"generation": 2})
+
+    assert timer.collections == [0, 0, 1]
+    assert timer.pause_seconds[2] >= 0
@@ -0,0 +1,146 @@
+"""Unit tests for runtime-managed discovery locations (app/services/discovery_locations.py)."""
+import json
+from types import SimpleNamespace
+from unittest.mock import AsyncMock, Mock
+
+import fakeredis
+import pytest
+from fastapi import FastAPI
+from fastapi.testclient import TestClient
+
+from app.models import VenueFilterResponse
+from app.routers.admin_trigger_router import router, set_container
+from app.services.admin_config_service import AdminConfigService
+from app.services.discovery_locations import (
+    DiscoveryLocationsService,
+    DiscoveryPointConflictError,
+    load_locations_file,
+    validate_discovery_points_config,
+)
+from app.services.venues_refresher_service import DEFAULT_LOCATIONS, VenuesRefresherService
+from tests.rds_fake import InMemoryRdsVenueStore
+
+POINT = {"id": "boa-viagem", "lat": -8.1197, "lng": -34.9012, "radius": 4000, "limit": 200}
+
+
+def _admin_config(redis_client=None):
+    return AdminConfigService(
+        redis_client or fakeredis.FakeRedis(decode_responses=True),
+        rds_store=InMemoryRdsVenueStore(),
+        validators={"discovery_points": validate_discovery_points_config},
+    )
+
+
+def _refresher(redis_client, locations_file=""):
+    api = Mock()
+    api.venue_filter = AsyncMock(return_value=VenueFilterResponse(status="OK", venues_n=0, venues=[]))
+    dao = Mock()
+    dao.list_all_venues.return_value = []
+    refresher = VenuesRefresherService(
+        dao, api, redis_client=redis_client, dev_mode=False, locations_file=locations_file
+    )
+    refresher.sync_account_inventory_to_redis = AsyncMock(return_value={})
+    refresher.update_data_quality_metrics = Mock()
+    return refresher, api
+
+
+def test_validation_fills_defaults_and_rejects_bad_points():
+    stored = validate_discovery_points_config({"points": [{"id": "a", "lat": -8.0, "lng": -34.9}]})
+    assert stored["points"][0] == {
+        "id": "a", "lat": -8.0, "lng": -34.9, "radius": 15000, "limit": 500, "current": 0,
+    }
+    with pytest.raises(ValueError):
+        validate_discovery_points_config({"points": [POINT, POINT]})
+    with pytest.raises(ValueError):
+        validate_discovery_points_config({"points": [{**POINT, "lat": 120}]})
+    with pytest.raises(TypeError):
+        validate_discovery_points_config([POINT])
+
+
+def test_locations_file_accepts_json_and_yaml(tmp_path):
+    json_file = tmp_path / "locations.json"
+    json_file.write_text(json.dumps({"points": [POINT]}))
+    yaml_file = tmp_path / "locations.yaml"
+    yaml_file.write_text("- id: olinda\n  lat: -7.99\n  lng: -34.85\n  radius: 8000\n")
+
+    assert load_locations_file(str(json_file))[0]["radius"] == 4000
+    assert load_locations_file(str(yaml_file))[0]["id"] == "olinda"
+
+
+def test_crud_keeps_counter_on_update():
+    service = DiscoveryLocationsService(_admin_config())
+
+    service.create_point(POINT)
+    with pytest.raises(DiscoveryPointConflictError):
+        service.create_point(POINT)
+    service.update_point("boa-viagem", {"current": 50})
+    updated = service.update_point("boa-viagem", {"radius": 6000})
+
+    assert (updated["radius"], updated["current"]) == (6000, 50)
+    assert service.update_point("missing", {"radius": 1}) is None
+    assert service.delete_point("boa-viagem") is True
+    assert service.list_points() == []
+
+
+@pytest.mark.asyncio
+async def test_refresh_picks_up_changes_without_restart(tmp_path):
+    redis_client = fakeredis.FakeRedis(decode_responses=True)
+    locations_file = tmp_path / "locations.json"
+    locations_file.write_text(json.dumps([POINT]))
+    refresher, api = _refresher(redis_client, str(locations_file))
+
+    await refresher.refresh_venues_by_filter_for_default_locations()
+    assert api.venue_filter.call_args[0][0].radius == 4000
+
+    locations_file.write_text(json.dumps([{**POINT, "radius": 9000}]))
+    await refresher.refresh_venues_by_filter_for_default_locations()
+    assert api.venue_filter.call_args[0][0].radius == 9000
+
+    DiscoveryLocationsService(_admin_config(redis_client)).create_point(
+        {"id": "olinda", "lat": -7.99, "lng": -34.85, "radius": 3000}
+    )
+    await refresher.refresh_venues_by_filter_for_default_locations()
+    assert api.venue_filter.call_args[0][0].lat == -7.99
+
+    assert refresher.active_locations()[0] == "admin"
+    assert _refresher(None, str(tmp_path / "missing.json"))[0].active_locations() == (
+        "default", DEFAULT_LOCATIONS
+    )
+
+
+def test_admin_endpoints():
+    redis_client = fakeredis.FakeRedis(decode_responses=True)
+    set_container(SimpleNamespace(
+        discovery_locations_service=DiscoveryLocationsService(_admin_config(redis_client)),
+        venues_refresher_service=_refresher(redis_client)[0],
+    ))
+    app = FastAPI()
+    app.include_router(router)
+    client = TestClient(app)
+
+    assert client.get("/admin/locations").json()["source"] == "default"
+    assert client.post("/admin/locations", json=POINT).status_code == 201
+    assert client.post("/admin/locations", json=POINT).status_code == 409
+    assert client.put("/admin/locations/boa-viagem", json={"radius": 0}).status_code == 400
+    assert client.put("/admin/locations/boa-viagem", json={"limit": 300}).json()["limit"] == 300
+    listed = client.get("/admin/locations").json()
+    assert listed["source"] == "admin" and listed["locations"][0]["id"] == "boa-viagem"
+    assert client.delete("/admin/locations/boa-viagem").status_code == 200
+    assert client.get("/admin/locations/boa-viagem").status_code == 404
+
+
+@pytest.mark.asyncio
+async def test_each_location_queries_with_its_own_parameters():
+    redis_client = fakeredis.FakeRedis(decode_responses=True)
+    DiscoveryLocationsService(_admin_config(redis_client))._write([
+        {**POINT, "types": ["BAR"], "live": True},
+        {"id": "olinda", "lat": -7.99, "lng": -34.85, "radius": 12000, "limit": 40},
+    ])
+    refresher, api = _refresher(redis_client)
+
+    await refresher.refresh_venues_by_filter_for_default_locations()
+
+    dense, sparse = (call[0][0] for call in api.venue_filter.call_args_list)
+    assert (dense.radius, dense.limit, dense.types, dense.live) == (4000, 200, ["BAR"], True)
+    assert (sparse.radius, sparse.limit, sparse.live) == (12000, 40, None)
+    assert sparse.types == refresher.search_params.types
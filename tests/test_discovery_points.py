@@ -356,6 +356,63 @@ class TestRefreshWithDiscoveryPoints:
         assert call_args.limit == 100  # headroom for "avail" = 300 - 200
 
 
+    @pytest.mark.asyncio
+    async def test_points_run_concurrently_and_merge_overlapping_venues(
+        self, mock_venue_dao, mock_besttime_api, mock_redis
+    ):
+        """With discovery_concurrency=3 all points are queried at once; venues
+        returned by several points count once in new_unique."""
+        import asyncio
+
+        in_flight, peak = 0, 0
+
+        async def venue_filter(params):
+            nonlocal in_flight, peak
+            in_flight += 1
+            peak = max(peak, in_flight)
+            await asyncio.sleep(0)
+            in_flight -= 1
+            return _make_filter_response(count=3)  # same v0..v2 everywhere
+
+        mock_besttime_api.venue_filter.side_effect = venue_filter
+        svc = VenuesRefresherService(
+            mock_venue_dao, mock_besttime_api, redis_client=mock_redis, discovery_concurrency=3,
+        )
+        points = [
+            {"id": f"p{i}", "lat": -8.0 - i / 10, "lng": -34.0, "radius": 5000, "limit": 500, "current": 0}
+            for i in range(3)
+        ]
+
+        total = await svc._refresh_with_discovery_points(points, -1, False)
+
+        assert peak == 3
+        assert total == 9
+        summaries = svc.last_discovery_summaries
+        assert [s.region for s in summaries] == ["p0", "p1", "p2"]
+        assert [s.fetched for s in summaries] == [3, 3, 3]
+        assert sum(s.new_unique for s in summaries) == 3
+        assert [p["current"] for p in points] == [3, 3, 3]
+
+    @pytest.mark.asyncio
+    async def test_summary_records_budget_skip_and_error(self, service, mock_besttime_api, mock_redis):
+        points = [
+            {"id": "p1", "lat": -8.0, "lng": -34.0, "radius": 5000, "limit": 500, "current": 0},
+            {"id": "p2", "lat": -8.1, "lng": -34.1, "radius": 5000, "limit": 500, "current": 0},
+            {"id": "p3", "lat": -8.2, "lng": -34.2, "radius": 5000, "limit": 500, "current": 0},
+        ]
+        mock_besttime_api.venue_filter.side_effect = [
+            Exception("API error"),
+            _make_filter_response(count=4),
+        ]
+
+        await service._refresh_with_discovery_points(points, remaining_budget=4, fetch_and_cache_live=False)
+
+        failed, fetched, skipped = service.last_discovery_summaries
+        assert failed.error == "API error" and failed.fetched == 0
+        assert (fetched.limit, fetched.fetched) == (4, 4)  # the failed point's reservation came back
+        assert skipped.skipped == "budget"
+
+
 # ===========================================================================
 # refresh_venues_by_filter_for_default_locations() — routing logic
 # ===========================================================================
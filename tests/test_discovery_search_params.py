@@ -0,0 +1,53 @@
+"""Unit tests for the configurable discovery query (SearchParams)."""
+from unittest.mock import AsyncMock, Mock
+
+import pytest
+
+from app.models import SearchParams, VenueFilterResponse
+from app.services.venues_refresher_service import VENUE_TYPES, VenuesRefresherService
+
+
+def _refresher(search_params=None):
+    besttime = Mock(venue_filter=AsyncMock(
+        return_value=VenueFilterResponse(status="OK", venues=[], venues_n=0)
+    ))
+    refresher = VenuesRefresherService(
+        venue_dao=Mock(), besttime_api=besttime, search_params=search_params,
+    )
+    return refresher, besttime
+
+
+async def _sent_query(refresher, besttime) -> dict:
+    await refresher._discover_venues_at(-8.05, -34.88, 5000, 100, fetch_and_cache_live=False)
+    return besttime.venue_filter.call_args.args[0].to_query_params()
+
+
+@pytest.mark.asyncio
+async def test_default_query_is_the_standard_nightlife_search():
+    query = await _sent_query(*_refresher())
+
+    assert query["types"] == ",".join(VENUE_TYPES)
+    assert query["busy_min"] == "0"
+    assert query["foot_traffic"] == "both"
+    assert query["own_venues_only"] == "false"
+    assert "now" not in query and "live" not in query
+    assert (query["radius"], query["limit"]) == ("5000", "100")
+
+
+@pytest.mark.asyncio
+async def test_configured_params_replace_the_defaults():
+    query = await _sent_query(*_refresher(
+        SearchParams(types=["RESTAURANT", "CAFE"], now=True, live=True)
+    ))
+
+    assert query["types"] == "RESTAURANT,CAFE"
+    assert query["now"] == "true"
+    assert query["live"] == "true"
+    assert query["lat"] == "-8.05"
+
+
+def test_settings_dict_validates_into_search_params():
+    params = SearchParams(**{"types": ["BAR"], "foot_traffic": "day"})
+
+    assert params.foot_traffic == "day"
+    assert params.busy_min == 0
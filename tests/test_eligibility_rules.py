@@ -1,8 +1,12 @@
 """Unit tests for Ex2: eligibility rows <-> blob + EligibilityRuleService."""
 import fakeredis
+import pytest
 
 from app.metrics import ELIGIBILITY_MIRROR_REHYDRATION_TOTAL
-from app.services.admin_config_service import AdminConfigService
+from app.services.admin_config_service import (
+    AdminConfigService,
+    AdminConfigVerificationError,
+)
 from app.services.eligibility_rules import EligibilityRuleService
 from app.services.venue_eligibility import (
     ADMIN_CONFIG_ELIGIBILITY_KEY,
@@ -85,6 +89,15 @@ def test_remove_last_rule_drops_override_to_defaults():
     assert svc.effective_config().from_admin_override is False
 
 
+def test_verified_remove_raises_when_the_mirror_delete_is_lost():
+    svc, store, redis = _service()
+    svc.add_rule("blocked_google_type", "casino", verify=True)
+    redis.delete = lambda *a, **k: 1  # acknowledged but never applied
+
+    with pytest.raises(AdminConfigVerificationError):
+        svc.remove_rule("blocked_google_type", "casino", verify=True)
+
+
 def test_set_full_config_replaces_rows():
     svc, store, _ = _service()
     svc.add_rule("blocked_google_type", "casino")
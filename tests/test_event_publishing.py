@@ -0,0 +1,150 @@
+"""Venue events through the Redis outbox to a broker (app/services/event_publishing.py)."""
+import json
+
+import fakeredis
+
+from app.config import Settings
+from app.dao.change_events import LIVE_FORECAST_SET, VENUE_UPSERTED
+from app.dao.redis_venue_dao import RedisVenueDAO
+from app.db.geo_redis_client import GeoRedisClient
+from app.models import Analysis, LiveForecastResponse, Venue, VenueInfo
+from app.services.event_publishing import (
+    EventOutbox,
+    EventRelay,
+    KafkaEventPublisher,
+    NatsEventPublisher,
+    build_event_publisher,
+    make_event,
+)
+from app.services.venues_refresher_service import VenuesRefresherService
+
+
+class _FakePublisher:
+    def __init__(self, failures=0):
+        self.failures = failures
+        self.batches = []
+
+    async def publish(self, events):
+        if self.failures:
+            self.failures -= 1
+            raise ConnectionError("broker unreachable")
+        self.batches.append(events)
+
+    async def close(self):
+        pass
+
+
+def _outbox(**kwargs):
+    return EventOutbox(fakeredis.FakeRedis(decode_responses=True), **kwargs)
+
+
+def _venue_event(vid):
+    return make_event(VENUE_UPSERTED, vid, {"venue_id": vid})
+
+
+async def test_relay_publishes_in_order_and_empties_the_outbox():
+    outbox = _outbox()
+    for vid in ("v1", "v2", "v3"):
+        outbox.add(_venue_event(vid))
+    publisher = _FakePublisher()
+
+    summary = await EventRelay(outbox, publisher, batch_size=2).drain()
+
+    assert [[e["venue_id"] for e in batch] for batch in publisher.batches] == [["v1", "v2"], ["v3"]]
+    assert summary == {"published": 3, "skipped": 0, "pending": 0, "error": None}
+    assert outbox.size() == 0
+
+
+async def test_failed_publish_keeps_events_for_the_next_run():
+    outbox = _outbox()
+    outbox.add(_venue_event("v1"))
+    publisher = _FakePublisher(failures=1)
+    relay = EventRelay(outbox, publisher)
+
+    failed = await relay.drain()
+    assert failed["error"] == "broker unreachable"
+    assert failed["pending"] == 1 and publisher.batches == []
+
+    retried = await relay.drain()
+    assert retried["published"] == 1 and retried["pending"] == 0
+    assert publisher.batches[0][0]["venue_id"] == "v1"
+
+
+async def test_full_outbox_drops_the_oldest_and_unreadable_entries_are_skipped():
+    outbox = _outbox(max_events=2)
+    for vid in ("v1", "v2", "v3"):
+        outbox.add(_venue_event(vid))
+    outbox.redis.rpush(outbox.key, "not json")
+    publisher = _FakePublisher()
+
+    summary = await EventRelay(outbox, publisher).drain()
+
+    assert [e["venue_id"] for e in publisher.batches[0]] == ["v2", "v3"]
+    assert summary["skipped"] == 1 and summary["pending"] == 0
+
+
+async def test_ack_removes_only_the_published_events():
+    outbox = _outbox(max_events=2)
+    for vid in ("v1", "v2"):
+        outbox.add(_venue_event(vid))
+
+    class _Overflowing(_FakePublisher):
+        async def publish(self, events):
+            # New events arrive mid-publish and trim the in-flight ones away.
+            for vid in ("v3", "v4"):
+                outbox.add(_venue_event(vid))
+            await super().publish(events)
+
+    summary = await EventRelay(outbox, _Overflowing()).drain()
+
+    assert summary["published"] == 2 and summary["pending"] == 2
+    assert [json.loads(raw)["venue_id"] for raw in outbox.peek(10)] == ["v3", "v4"]
+
+
+async def test_refresher_records_upserts_and_cached_live_forecasts():
+    redis_client = fakeredis.FakeRedis(decode_responses=True)
+    dao = RedisVenueDAO(GeoRedisClient(redis_client))
+    dao.upsert_venue(Venue(venue_id="v1", venue_name="Bar 1", venue_lat=-8.05, venue_lng=-34.88))
+
+    class _Besttime:
+        async def get_live_forecast(self, venue_id):
+            return LiveForecastResponse(
+                status="OK",
+                venue_info=VenueInfo(venue_id=venue_id),
+                analysis=Analysis(venue_live_busyness=60, venue_live_busyness_available=True),
+            )
+
+    outbox = EventOutbox(redis_client)
+    refresher = VenuesRefresherService(venue_dao=dao, besttime_api=_Besttime())
+    refresher.set_event_outbox(outbox)
+
+    await refresher._fetch_and_cache_live_one("v1", refresher._crowd_registry(), {})
+    refresher._record_venue_upserted(dao.get_venue("v1"))
+
+    events = [json.loads(raw) for raw in outbox.peek(10)]
+    assert [(e["type"], e["venue_id"]) for e in events] == [
+        (LIVE_FORECAST_SET, "v1"), (VENUE_UPSERTED, "v1"),
+    ]
+    assert events[0]["data"]["analysis"]["venue_live_busyness"] == 60
+    assert events[1]["data"]["venue_name"] == "Bar 1"
+    assert events[0]["event_id"] != events[1]["event_id"]
+
+
+def test_publisher_follows_events_broker():
+    base = {"besttime_private_key": "pri", "besttime_public_key": "pub"}
+
+    assert build_event_publisher(Settings(**base)) is None
+    kafka = build_event_publisher(Settings(
+        **base, events_broker="kafka", events_kafka_bootstrap_servers=["kafka:9092"]
+    ))
+    nats = build_event_publisher(Settings(
+        **base, events_broker="nats", events_nats_servers=["nats://nats:4222"]
+    ))
+    assert isinstance(kafka, KafkaEventPublisher) and kafka.topic == "cs_server.venue_events"
+    assert isinstance(nats, NatsEventPublisher) and nats.servers == ["nats://nats:4222"]
+    assert "events_broker kafka needs events_kafka_bootstrap_servers" in Settings(
+        **base, events_broker="kafka"
+    ).config_errors()
+    assert "events_broker must be empty, kafka or nats" in Settings(
+        **base, events_broker="rabbitmq"
+    ).config_errors()
@@ -0,0 +1,88 @@
+"""Unit tests for per-region discovery filter tuning (app/services/filter_tuner.py)."""
+from types import SimpleNamespace
+from unittest.mock import AsyncMock, Mock
+
+import fakeredis
+import pytest
+from fastapi import FastAPI
+from fastapi.testclient import TestClient
+
+from app.models import VenueFilterResponse
+from app.routers.admin_trigger_router import router, set_container
+from app.services.filter_tuner import DEFAULT_ARMS, FilterArm, FilterTuner
+from app.services.venues_refresher_service import VenuesRefresherService
+
+
+def _tuner(epsilon=0.0, arms=DEFAULT_ARMS):
+    return FilterTuner(fakeredis.FakeRedis(decode_responses=True), epsilon=epsilon, arms=arms)
+
+
+def test_tries_every_arm_then_exploits_the_best():
+    narrow, wide = FilterArm(0.5, 0, 1.0), FilterArm(1.0, 0, 1.0)
+    tuner = _tuner(arms=(narrow, wide))
+
+    assert tuner.choose("recife") == narrow
+    tuner.record("recife", narrow, live_venues=2, credits=10)
+    assert tuner.choose("recife") == wide
+    tuner.record("recife", wide, live_venues=8, credits=10)
+
+    assert tuner.choose("recife") == wide
+    # Regions learn independently.
+    assert tuner.choose("olinda") == narrow
+
+
+def test_stats_report_mean_reward_and_best_arm():
+    arm = DEFAULT_ARMS[0]
+    tuner = _tuner()
+    tuner.record("recife", arm, live_venues=3, credits=6)
+    tuner.record("recife", arm, live_venues=1, credits=0)  # zero credits counts as one
+
+    stats = tuner.stats()["recife"]
+
+    assert stats["best_arm"] == arm.key
+    assert stats["best_params"] == {"radius_scale": 0.5, "busy_min": 0, "limit_scale": 0.5}
+    assert stats["arms"][arm.key] == {"pulls": 2, "mean_reward": 0.75}
+    tuner.reset("recife")
+    assert tuner.stats() == {}
+
+
+def test_epsilon_is_validated():
+    with pytest.raises(ValueError):
+        _tuner(epsilon=1.5)
+
+
+@pytest.mark.asyncio
+async def test_refresher_queries_the_chosen_arm_and_scores_it():
+    arm = FilterArm(0.5, 20, 0.5)
+    tuner = _tuner(arms=(arm,))
+    besttime = Mock(venue_filter=AsyncMock(
+        return_value=VenueFilterResponse(status="OK", venues=[], venues_n=0)
+    ))
+    refresher = VenuesRefresherService(
+        venue_dao=Mock(), besttime_api=besttime, filter_tuner=tuner,
+    )
+
+    await refresher._discover_venues_at(
+        -8.05, -34.88, 5000, 100, fetch_and_cache_live=False, region="recife"
+    )
+
+    query = besttime.venue_filter.call_args.args[0].to_query_params()
+    assert (query["radius"], query["limit"], query["busy_min"]) == ("2500", "50", "20")
+    assert tuner.stats()["recife"]["arms"][arm.key]["pulls"] == 1
+
+
+def test_admin_endpoint_shows_regions():
+    tuner = _tuner()
+    tuner.record("recife", DEFAULT_ARMS[0], live_venues=1, credits=2)
+    set_container(SimpleNamespace(filter_tuner=tuner))
+    app = FastAPI()
+    app.include_router(router)
+    client = TestClient(app)
+
+    body = client.get("/admin/venues/filter-tuning").json()
+
+    assert body["regions"]["recife"]["best_arm"] == DEFAULT_ARMS[0].key
+    assert client.delete("/admin/venues/filter-tuning").status_code == 200
+    assert client.get("/admin/venues/filter-tuning").json()["regions"] == {}
+    set_container(SimpleNamespace(filter_tuner=None))
+    assert client.get("/admin/venues/filter-tuning").status_code == 503
@@ -0,0 +1,83 @@
+"""Unit tests for fixture seeding (app/services/fixture_seed.py)."""
+import json
+from datetime import datetime, timezone
+
+import fakeredis
+import pytest
+
+from app.config import settings
+from app.dao import RedisVenueDAO
+from app.db import GeoRedisClient
+from app.services.fixture_seed import load_fixture, parse_fixture, seed_redis
+
+
+@pytest.fixture
+def dao():
+    return RedisVenueDAO(GeoRedisClient(fakeredis.FakeRedis(decode_responses=True)))
+
+
+def test_bundled_fixture_seeds_venues_and_forecasts(dao):
+    fixture = load_fixture(settings.get_resource_path("seed_fixture.json"))
+    now = datetime(2026, 3, 1, 12, tzinfo=timezone.utc)
+
+    summary = seed_redis(dao, fixture, now=now)
+
+    assert summary == {"venues": 3, "live_forecasts": 3, "weekly_forecasts": 21}
+    venue_id = fixture.venues[0].venue_id
+    assert dao.get_venue(venue_id).venue_name == fixture.venues[0].venue_name
+    assert dao.get_live_forecast(venue_id).refreshed_at == now
+    assert dao.get_week_raw_forecast(venue_id, 4).day_raw == fixture.weekly_forecasts[4][1].day_raw
+    assert len(dao.get_nearby_venues(-8.08, -34.88, 10)) == 3
+
+
+def test_besttime_shapes_are_accepted(dao):
+    venue_static = load_fixture(settings.get_resource_path("venue_static.json"))
+    assert venue_static.venues[0].venue_lng == -122.4194
+
+    fixture = parse_fixture({
+        "weekly_forecasts": [{
+            "venue_id": "ven_123",
+            "analysis": {"week_raw": [{"day_int": 0, "day_raw": [10] * 24}]},
+        }],
+    })
+    seed_redis(dao, fixture)
+
+    assert dao.get_week_raw_forecast("ven_123", 0).day_raw == [10] * 24
+
+
+@pytest.mark.parametrize("data, message", [
+    ({"stuff": []}, "a fixture is an object"),
+    ({"venues": {"venue_id": "x"}}, "venues must be a list"),
+    ({"venues": [{"venue_id": "x"}]}, "venues[0]"),
+    ({"live_forecasts": [{"status": "OK"}]}, "live_forecasts[0]"),
+    ({"weekly_forecasts": [{"week_raw": []}]}, "weekly_forecasts[0]: missing venue_id"),
+])
+def test_bad_fixtures_are_rejected(data, message):
+    with pytest.raises(ValueError, match=message.replace("[", r"\[").replace("]", r"\]")):
+        parse_fixture(data)
+
+
+def test_invalid_json_is_a_value_error(tmp_path):
+    path = tmp_path / "fixture.json"
+    path.write_text("{not json")
+
+    with pytest.raises(ValueError, match="not valid JSON"):
+        load_fixture(path)
+
+
+def test_seed_command_writes_nothing_for_a_bad_fixture(tmp_path, monkeypatch):
+    from app import cli
+
+    monkeypatch.setenv("BESTTIME_PRIVATE_KEY", "pri")
+    monkeypatch.setenv("BESTTIME_PUBLIC_KEY", "pub")
+    client = fakeredis.FakeRedis(decode_responses=True)
+    monkeypatch.setattr("app.db.redis_factory.build_redis_client", lambda settings: client)
+    monkeypatch.setattr(client, "close", lambda: None)
+    bad = tmp_path / "bad.json"
+    bad.write_text(json.dumps({"venues": [{"venue_id": "x"}]}))
+
+    assert cli.main(["seed", "--fixture", str(bad)]) == 1
+    assert client.keys("*") == []
+
+    assert cli.main(["seed"]) == 0
+    assert client.keys("*")
@@ -0,0 +1,79 @@
+"""Unit tests for the compact weekly forecast encoding."""
+import pytest
+from unittest.mock import Mock, patch
+
+from app.dao import RedisVenueDAO
+from app.dao.forecast_codec import (
+    COMPACT_PREFIX,
+    decode_week_raw_day,
+    encode_week_raw_day,
+    pack_varints,
+    unpack_varints,
+)
+from app.models import WeekRawDay
+from app.models.venue import DayInfo
+
+
+def _day():
+    return WeekRawDay(
+        day_int=4,
+        day_raw=[0, 5, 10, 40, 63, 64, 100, 110] + [0] * 16,
+        day_info=DayInfo(day_int=4, day_max=110, day_text="Friday", venue_open="6"),
+    )
+
+
+class TestVarints:
+    @pytest.mark.parametrize("values", [[], [0], [1, 63, 64, 127, 128, 300], [-1, -64, 5]])
+    def test_round_trip(self, values):
+        assert unpack_varints(pack_varints(values)) == values
+
+    def test_small_values_take_one_byte(self):
+        assert len(pack_varints(list(range(64)))) == 64
+
+    def test_truncated_input_rejected(self):
+        with pytest.raises(ValueError):
+            unpack_varints(b"\x80")
+
+
+class TestWeekRawDayCodec:
+    def test_round_trip(self):
+        day = _day()
+        encoded = encode_week_raw_day(day)
+        assert encoded.startswith(COMPACT_PREFIX)
+        assert decode_week_raw_day(encoded) == day
+
+    def test_smaller_than_json(self):
+        day = _day()
+        assert len(encode_week_raw_day(day)) * 2 < len(day.model_dump_json(by_alias=True))
+
+    def test_decodes_legacy_json(self):
+        day = _day()
+        assert decode_week_raw_day(day.model_dump_json(by_alias=True)) == day
+
+    def test_malformed_compact_value(self):
+        with pytest.raises(ValueError):
+            decode_week_raw_day(COMPACT_PREFIX + "not base64")
+
+
+class TestDaoEncoding:
+    def test_set_writes_compact_value(self):
+        client = Mock()
+        RedisVenueDAO(client).set_week_raw_forecast("v1", _day())
+        key, value = client.set.call_args.args
+        assert key == "weekly_forecast_v1:v1_4"
+        assert value.startswith(COMPACT_PREFIX)
+
+    def test_set_writes_json_when_disabled(self):
+        client = Mock()
+        with patch("app.dao.redis_venue_dao.settings.redis_compact_forecasts", False):
+            RedisVenueDAO(client).set_week_raw_forecast("v1", _day())
+        assert client.set.call_args.args[1].startswith("{")
+
+    def test_reads_mixed_encodings(self):
+        client = Mock()
+        client.get.return_value = encode_week_raw_day(_day())
+        client.mget.return_value = [encode_week_raw_day(_day()), _day().model_dump_json(), None]
+        dao = RedisVenueDAO(client)
+        assert dao.get_week_raw_forecast("v1", 4) == _day()
+        bulk = dao.get_week_raw_forecasts_bulk(["a", "b", "c"], 4)
+        assert bulk == {"a": _day(), "b": _day()}
@@ -0,0 +1,89 @@
+"""Unit tests for GeoRedisClient write atomicity and radius units.
+
+add_location_with_json sends GEOADD + SET in one MULTI/EXEC so a crash between
+them can never leave a geo member without its JSON; the bulk variant wraps each
+chunk the same way. Radius queries take an explicit, validated unit.
+"""
+from unittest.mock import MagicMock
+
+import fakeredis
+import pytest
+
+from app.db.geo_redis_client import GeoRedisClient, radius_to_km
+
+
+def _mock_client() -> tuple[GeoRedisClient, MagicMock, MagicMock]:
+    raw = MagicMock()
+    pipe = MagicMock()
+    raw.pipeline.return_value = pipe
+    return GeoRedisClient(raw), raw, pipe
+
+
+class TestAtomicGeoUpsert:
+    def test_single_upsert_uses_one_transaction(self):
+        client, raw, pipe = _mock_client()
+
+        client.add_location_with_json("geo", "member:1", -8.0, -34.9, {"a": 1})
+
+        raw.pipeline.assert_called_once_with(transaction=True)
+        pipe.geoadd.assert_called_once_with("geo", (-34.9, -8.0, "member:1"))
+        pipe.set.assert_called_once_with("member:1", '{"a": 1}')
+        pipe.execute.assert_called_once()
+        raw.geoadd.assert_not_called()
+        raw.set.assert_not_called()
+
+    def test_failed_exec_writes_nothing(self):
+        client, raw, pipe = _mock_client()
+        pipe.execute.side_effect = ConnectionError("dropped")
+
+        try:
+            client.add_location_with_json("geo", "member:1", -8.0, -34.9, {"a": 1})
+        except ConnectionError:
+            pass
+
+        raw.geoadd.assert_not_called()
+        raw.set.assert_not_called()
+
+    def test_bulk_chunks_are_transactional(self):
+        client, raw, pipe = _mock_client()
+
+        client.add_locations_with_json(
+            "geo", [("m:1", -8.0, -34.9, {}), ("m:2", -8.1, -34.8, {})], chunk_size=1
+        )
+
+        assert [c.kwargs for c in raw.pipeline.call_args_list] == [
+            {"transaction": True}, {"transaction": True},
+        ]
+        assert pipe.execute.call_count == 2
+
+    def test_round_trip_against_fakeredis(self):
+        fake = fakeredis.FakeRedis(decode_responses=True)
+        client = GeoRedisClient(fake)
+
+        client.add_location_with_json("geo", "member:1", -8.0, -34.9, {"a": 1})
+
+        assert fake.get("member:1") == '{"a": 1}'
+        assert client.get_locations_within_radius("geo", -8.0, -34.9, 1.0) == ['{"a": 1}']
+
+
+class TestRadiusUnits:
+    def test_conversion(self):
+        assert radius_to_km(1500, "m") == 1.5
+        assert radius_to_km(2, "km") == 2
+        assert radius_to_km(1, "mi") == pytest.approx(1.609344)
+
+    @pytest.mark.parametrize("radius,unit", [(1, "yards"), (-1, "km")])
+    def test_rejects_bad_input(self, radius, unit):
+        with pytest.raises(ValueError):
+            radius_to_km(radius, unit)
+
+    def test_query_honours_unit(self):
+        fake = fakeredis.FakeRedis(decode_responses=True)
+        client = GeoRedisClient(fake)
+        # ~1.1 km north of the query point
+        client.add_location_with_json("geo", "member:1", -7.99, -34.9, {"a": 1})
+
+        assert client.get_locations_within_radius("geo", -8.0, -34.9, 500, unit="m") == []
+        assert client.get_locations_within_radius("geo", -8.0, -34.9, 1500, unit="m") == ['{"a": 1}']
+        with pytest.raises(ValueError):
+            client.get_locations_within_radius("geo", -8.0, -34.9, 1, unit="yards")
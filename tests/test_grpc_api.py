@@ -0,0 +1,142 @@
+"""gRPC VenueService (app/grpc_api/server.py) against a seeded fake Redis.
+
+Needs the generated code: `make proto` (make test-unit runs it first).
+"""
+import asyncio
+
+import fakeredis
+import pytest
+
+pytest.importorskip("grpc")
+pb = pytest.importorskip(
+    "app.grpc_api.venue_service_pb2", reason="generated code missing; run `make proto`"
+)
+
+import grpc  # noqa: E402
+
+from app.config import settings  # noqa: E402
+from app.dao import RedisVenueDAO  # noqa: E402
+from app.db import GeoRedisClient  # noqa: E402
+from app.grpc_api import venue_service_pb2_grpc as pb_grpc  # noqa: E402
+from app.grpc_api.server import GrpcServer  # noqa: E402
+from app.handlers import VenueHandler  # noqa: E402
+from app.services.fixture_seed import load_fixture, seed_redis  # noqa: E402
+
+_BAR = "ven_seed_bar_recife_antigo"
+_KEY = (("x-api-key", "app-key"),)
+
+
+class _WithKey(grpc.aio.UnaryUnaryClientInterceptor, grpc.aio.UnaryStreamClientInterceptor):
+    """Sends the test client's x-api-key on every call."""
+
+    async def intercept_unary_unary(self, continuation, details, request):
+        return await continuation(details._replace(metadata=_KEY), request)
+
+    async def intercept_unary_stream(self, continuation, details, request):
+        return await continuation(details._replace(metadata=_KEY), request)
+
+
+@pytest.fixture
+async def server():
+    redis_server = fakeredis.FakeServer()
+    dao = RedisVenueDAO(GeoRedisClient(fakeredis.FakeRedis(server=redis_server, decode_responses=True)))
+    fixture = load_fixture(settings.get_resource_path("seed_fixture.json"))
+    seed_redis(dao, fixture)
+    grpc_server = GrpcServer(
+        VenueHandler(dao),
+        fakeredis.FakeAsyncRedis(server=redis_server, decode_responses=True),
+        "127.0.0.1",
+        0,
+        {"app-key": "mobile"},
+    )
+    await grpc_server.start()
+    try:
+        yield grpc_server, dao, fixture
+    finally:
+        await grpc_server.stop(grace=0)
+
+
+@pytest.fixture
+async def stub(server):
+    grpc_server, dao, fixture = server
+    channel = grpc.aio.insecure_channel(
+        f"127.0.0.1:{grpc_server.port}", interceptors=[_WithKey()]
+    )
+    try:
+        yield pb_grpc.VenueServiceStub(channel), dao, fixture
+    finally:
+        await channel.close()
+
+
+async def test_get_venue_and_live_forecast(stub):
+    client, _, fixture = stub
+
+    venue = await client.GetVenue(pb.GetVenueRequest(venue_id=_BAR))
+    live = await client.GetLiveForecast(pb.GetLiveForecastRequest(venue_id=_BAR))
+
+    assert venue.name == fixture.venues[0].venue_name
+    assert venue.price_level == 2 and venue.HasField("rating")
+    assert live.live_busyness == 55 and live.source == "besttime"
+    assert live.HasField("refreshed_at")
+
+
+async def test_calls_without_a_known_key_are_refused(server):
+    grpc_server, _, _ = server
+    async with grpc.aio.insecure_channel(f"127.0.0.1:{grpc_server.port}") as channel:
+        client = pb_grpc.VenueServiceStub(channel)
+        for metadata in (None, (("x-api-key", "nope"),)):
+            with pytest.raises(grpc.aio.AioRpcError) as exc:
+                await client.GetVenue(pb.GetVenueRequest(venue_id=_BAR), metadata=metadata)
+            assert exc.value.code() == grpc.StatusCode.UNAUTHENTICATED
+        call = client.StreamLiveUpdates(pb.StreamLiveUpdatesRequest())
+        with pytest.raises(grpc.aio.AioRpcError) as exc:
+            await call.read()
+        assert exc.value.code() == grpc.StatusCode.UNAUTHENTICATED
+
+
+async def test_unknown_venue_is_not_found(stub):
+    client, _, _ = stub
+
+    with pytest.raises(grpc.aio.AioRpcError) as exc:
+        await client.GetVenue(pb.GetVenueRequest(venue_id="ven_missing"))
+    assert exc.value.code() == grpc.StatusCode.NOT_FOUND
+
+
+async def test_nearby_matches_the_handler(stub):
+    client, dao, _ = stub
+
+    response = await client.GetVenuesNearby(
+        pb.GetVenuesNearbyRequest(lat=-8.08, lng=-34.88, radius=10)
+    )
+    expected = VenueHandler(dao).get_venues_nearby(-8.08, -34.88, 10, verbose=True)
+
+    assert [v.venue.venue_id for v in response.venues] == [m.venue.venue_id for m in expected]
+    with pytest.raises(grpc.aio.AioRpcError) as exc:
+        await client.GetVenuesNearby(pb.GetVenuesNearbyRequest(lat=-8.08, lng=-34.88, radius=0))
+    assert exc.value.code() == grpc.StatusCode.INVALID_ARGUMENT
+
+
+async def test_stream_live_updates_follows_the_change_feed(stub):
+    client, dao, fixture = stub
+    forecast = next(f for f in fixture.live_forecasts if f.venue_info.venue_id == _BAR)
+    other = next(f for f in fixture.live_forecasts if f.venue_info.venue_id != _BAR)
+    call = client.StreamLiveUpdates(pb.StreamLiveUpdatesRequest(venue_ids=[_BAR]))
+
+    async def publish():
+        # Until the stream has subscribed, publishes go nowhere; keep writing.
+        while True:
+            dao.set_live_forecast(other)
+            forecast.analysis.venue_live_busyness = 80
+            dao.set_live_forecast(forecast)
+            await asyncio.sleep(0.05)
+
+    publisher = asyncio.create_task(publish())
+    try:
+        update = await asyncio.wait_for(call.read(), timeout=5)
+    finally:
+        publisher.cancel()
+        call.cancel()
+
+    assert update.venue_id == _BAR
+    assert update.change_type == "live_forecast_set"
+    assert update.live.live_busyness == 80
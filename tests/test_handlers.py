@@ -113,6 +113,18 @@ class TestVenueHandler:
         assert len(result) == 1
         mock_venue_dao.get_nearby_venues.assert_called_once_with(-8.0, -34.9, 5.0)
 
+    def test_get_venues_nearby_normalizes_radius_unit(self, venue_handler, mock_venue_dao):
+        """A meter radius reaches the DAO converted to kilometers."""
+        mock_venue_dao.get_nearby_venues.return_value = []
+
+        venue_handler.get_venues_nearby(lat=-8.0, lon=-34.9, radius=2500, unit="m")
+
+        mock_venue_dao.get_nearby_venues.assert_called_once_with(-8.0, -34.9, 2.5)
+
+    def test_get_venues_nearby_rejects_unknown_unit(self, venue_handler):
+        with pytest.raises(ValueError):
+            venue_handler.get_venues_nearby(lat=-8.0, lon=-34.9, radius=1, unit="yards")
+
     def test_get_venues_nearby_filters_deprecated_venues(
         self, venue_handler, mock_venue_dao
     ):
@@ -0,0 +1,130 @@
+"""Unit tests for the nightly analytics history export."""
+import gzip
+import json
+from datetime import date, datetime, timezone
+from decimal import Decimal
+from unittest.mock import AsyncMock, Mock
+
+import pytest
+
+from app.config import Settings
+from app.models import LiveHistoryPoint
+from app.services.history_export_service import (
+    CATALOG_DELTAS_DATASET,
+    HISTORY_DATASET,
+    HistoryExportService,
+    encode_rows,
+    render_path,
+)
+
+TEMPLATE = "analytics/{dataset}/date={date}/part-0000.{ext}"
+
+
+def _at(day, hour):
+    return datetime(2026, 10, day, hour, tzinfo=timezone.utc)
+
+
+def _service(history=None, venue_rows=None, fmt="ndjson"):
+    dao = Mock()
+    dao.get_live_history.side_effect = lambda vid, since=None: (history or {}).get(vid, [])
+    store = Mock()
+    store.list_active_venue_ids.return_value = list((history or {}).keys())
+    store.list_all_venue_rows.return_value = venue_rows or []
+    storage = Mock()
+    storage.put_object = AsyncMock(side_effect=lambda key, body, **kw: f"s3://bucket/{key}")
+    svc = HistoryExportService(
+        dao, store, storage, TEMPLATE, fmt=fmt, now_fn=lambda: _at(16, 0)
+    )
+    return svc, storage
+
+
+def _uploaded(storage, dataset):
+    for call in storage.put_object.await_args_list:
+        if f"/{dataset}/" in call.args[0]:
+            body = gzip.decompress(call.args[1]).decode()
+            return [json.loads(line) for line in body.splitlines()]
+    raise AssertionError(f"{dataset} not uploaded")
+
+
+class TestRenderPath:
+    def test_fields(self):
+        path = render_path("{dataset}/{year}/{month}/{day}/{date}.{ext}", "d", date(2026, 1, 5), "ndjson")
+        assert path == "d/2026/01/05/2026-01-05.ndjson.gz"
+
+    def test_unknown_field_rejected(self):
+        with pytest.raises(ValueError):
+            render_path("{bucket}/{date}", "d", date(2026, 1, 5), "ndjson")
+
+    def test_bad_template_fails_at_construction(self):
+        with pytest.raises(ValueError):
+            HistoryExportService(Mock(), Mock(), Mock(), "{nope}")
+
+
+class TestEncodeRows:
+    def test_ndjson_is_gzipped_lines(self):
+        body = encode_rows([{"a": 1}, {"a": 2}], "ndjson")
+        assert gzip.decompress(body).decode() == '{"a":1}\n{"a":2}\n'
+
+    def test_unknown_format(self):
+        with pytest.raises(ValueError):
+            encode_rows([], "csv")
+
+
+class TestExportDay:
+    @pytest.mark.asyncio
+    async def test_exports_yesterday_history_only(self):
+        history = {
+            "v1": [
+                LiveHistoryPoint(timestamp=_at(15, 10), busyness=30),
+                LiveHistoryPoint(timestamp=_at(15, 22), busyness=80),
+                LiveHistoryPoint(timestamp=_at(16, 0), busyness=10),
+            ],
+        }
+        svc, storage = _service(history=history)
+
+        summary = await svc.export_day()
+
+        assert summary["date"] == "2026-10-15"
+        assert summary[HISTORY_DATASET]["rows"] == 2
+        assert summary[HISTORY_DATASET]["uri"] == (
+            "s3://bucket/analytics/busyness_history/date=2026-10-15/part-0000.ndjson.gz"
+        )
+        rows = _uploaded(storage, HISTORY_DATASET)
+        assert [r["busyness"] for r in rows] == [30, 80]
+        assert rows[0]["venue_id"] == "v1"
+
+    @pytest.mark.asyncio
+    async def test_catalog_deltas(self):
+        venue_rows = [
+            {"venue_id": "new", "created_at": _at(15, 9), "venue_lat": Decimal("-8.05"), "venue_lng": Decimal("-34.9")},
+            {"venue_id": "old", "created_at": _at(1, 9)},
+            {"venue_id": "gone", "created_at": _at(1, 9), "deprecated_at": "2026-10-15T20:00:00+00:00",
+             "deprecated_reason": "closed"},
+        ]
+        svc, storage = _service(venue_rows=venue_rows)
+
+        summary = await svc.export_day()
+
+        assert summary[CATALOG_DELTAS_DATASET]["rows"] == 2
+        rows = _uploaded(storage, CATALOG_DELTAS_DATASET)
+        assert [(r["venue_id"], r["change"]) for r in rows] == [("new", "added"), ("gone", "deprecated")]
+        assert rows[0]["venue_lat"] == -8.05
+        assert rows[1]["deprecated_reason"] == "closed"
+
+    @pytest.mark.asyncio
+    async def test_empty_day_still_writes_both_partitions(self):
+        svc, storage = _service()
+        await svc.export_day(date(2026, 10, 1))
+        keys = sorted(c.args[0] for c in storage.put_object.await_args_list)
+        assert keys == [
+            "analytics/busyness_history/date=2026-10-01/part-0000.ndjson.gz",
+            "analytics/venue_catalog_deltas/date=2026-10-01/part-0000.ndjson.gz",
+        ]
+
+
+def test_export_needs_more_than_a_day_of_live_history():
+    assert Settings(besttime_mode="replay", history_export_enabled=True).config_errors() == []
+    errors = Settings(
+        besttime_mode="replay", history_export_enabled=True, live_history_window_hours=24
+    ).config_errors()
+    assert any(e.startswith("live_history_window_hours must be at least 25") for e in errors)
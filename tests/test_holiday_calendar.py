@@ -0,0 +1,88 @@
+"""Unit tests for the regional holiday calendar (app/services/holiday_calendar.py)."""
+import json
+from datetime import date, timedelta
+
+import fakeredis
+import pytest
+
+from app.config import settings
+from app.dao.redis_venue_dao import RedisVenueDAO
+from app.db.geo_redis_client import GeoRedisClient
+from app.handlers import VenueHandler
+from app.models import Venue
+from app.services import holiday_calendar
+from app.services.holiday_calendar import (
+    HolidayCalendar,
+    builtin_holidays,
+    easter_sunday,
+    holiday_live_refresh_minutes,
+    load_holiday_file,
+)
+from app.utils.recife_time import recife_today
+
+
+@pytest.fixture
+def calendar_on(monkeypatch):
+    monkeypatch.setattr(settings, "holiday_calendar_enabled", True)
+    monkeypatch.setattr(holiday_calendar, "_calendar", None)
+    yield
+    holiday_calendar._calendar = None
+
+
+def test_easter_and_carnaval():
+    assert easter_sunday(2027) == date(2027, 3, 28)
+    calendar = HolidayCalendar("BR")
+    # Carnaval 2027: Saturday Feb 6 through Tuesday Feb 9.
+    assert calendar.lookup(date(2027, 2, 6)).name == "Carnaval"
+    assert calendar.lookup(date(2027, 2, 9)).boost is True
+    assert calendar.lookup(date(2027, 2, 10)) is None  # Ash Wednesday
+
+
+def test_regions_layer_state_and_city_holidays():
+    assert HolidayCalendar("BR").lookup(date(2026, 3, 6)) is None
+    assert HolidayCalendar("BR-PE").lookup(date(2026, 3, 6)).name == "Data Magna de Pernambuco"
+    assert HolidayCalendar("BR-PE").lookup(date(2026, 7, 16)) is None
+    assert HolidayCalendar("BR-PE-RECIFE").lookup(date(2026, 7, 16)) is not None
+    with pytest.raises(ValueError):
+        builtin_holidays("US", 2026)
+
+
+def test_file_entries_add_and_override(tmp_path):
+    path = tmp_path / "holidays.json"
+    path.write_text(json.dumps([
+        {"date": "2026-12-31", "name": "Réveillon", "boost": True},
+        {"date": "2026-12-25", "name": "Natal (fechado)"},
+    ]))
+    calendar = HolidayCalendar("BR", load_holiday_file(str(path)))
+
+    assert calendar.lookup(date(2026, 12, 31)).boost is True
+    assert calendar.lookup(date(2026, 12, 25)).name == "Natal (fechado)"
+
+
+def test_boost_interval_only_on_boost_days(calendar_on, monkeypatch):
+    monkeypatch.setattr(settings, "holiday_live_refresh_minutes", 2)
+
+    assert holiday_live_refresh_minutes(date(2027, 2, 8)) == 2  # Carnaval
+    assert holiday_live_refresh_minutes(date(2026, 4, 21)) is None  # not a boost day
+    assert holiday_live_refresh_minutes(date(2026, 4, 22)) is None
+
+
+def test_disabled_calendar_never_boosts(monkeypatch):
+    monkeypatch.setattr(settings, "holiday_calendar_enabled", False)
+    monkeypatch.setattr(settings, "holiday_live_refresh_minutes", 2)
+    assert holiday_live_refresh_minutes(date(2027, 2, 8)) is None
+
+
+def test_nearby_annotates_special_day(calendar_on, tmp_path, monkeypatch):
+    path = tmp_path / "holidays.json"
+    tomorrow = recife_today() + timedelta(days=1)
+    path.write_text(json.dumps([{"date": tomorrow.isoformat(), "name": "Festa"}]))
+    monkeypatch.setattr(settings, "holiday_calendar_file", str(path))
+    dao = RedisVenueDAO(GeoRedisClient(fakeredis.FakeRedis(decode_responses=True)))
+    dao.upsert_venue(Venue(venue_id="v1", venue_name="v1", venue_address="a",
+                           venue_lat=-8.05, venue_lng=-34.88))
+
+    handler = VenueHandler(dao)
+    [tomorrow_item] = handler.get_venues_nearby(-8.05, -34.88, 1.0, target_day_offset=1)
+
+    assert tomorrow_item.special_day == "Festa"
@@ -0,0 +1,173 @@
+"""Tests for the HTTP listener (app/http_server.py) and the request timeout
+middleware."""
+import asyncio
+from contextlib import asynccontextmanager
+
+import fakeredis
+import httpx
+import pytest
+from fastapi import FastAPI, Request
+from fastapi.testclient import TestClient
+
+from app.config import ConfigError, Settings, settings as app_settings
+from app.dao import RedisVenueDAO
+from app.db import GeoRedisClient
+from app.handlers import VenueHandler
+from app.http_server import HttpServer, server_config
+from app.middleware import RequestTimeoutMiddleware
+from app.routers import set_venue_handler, venue_router
+from app.services.fixture_seed import load_fixture, seed_redis
+
+
+@pytest.fixture(autouse=True)
+def _clean_env(monkeypatch):
+    for name in ("CONFIG_FILE", "SERVER_PORT", "SERVER_HOST"):
+        monkeypatch.delenv(name, raising=False)
+
+
+def _settings(**values):
+    return Settings(besttime_private_key="pri", besttime_public_key="pub", **values)
+
+
+def test_defaults_listen_on_8080_without_tls():
+    config = server_config(_settings(), app="main:app")
+
+    assert (config.host, config.port) == ("0.0.0.0", 8080)
+    assert config.ssl_certfile is None
+    assert config.timeout_keep_alive == 5
+    assert config.timeout_graceful_shutdown == 30
+    assert config.forwarded_allow_ips == ["127.0.0.1"]
+
+
+def test_address_tls_and_timeouts_come_from_settings(tmp_path):
+    cert, key = tmp_path / "cert.pem", tmp_path / "key.pem"
+    cert.write_text("cert")
+    key.write_text("key")
+
+    config = server_config(_settings(
+        server_host="127.0.0.1",
+        server_port=8443,
+        server_tls_certfile=str(cert),
+        server_tls_keyfile=str(key),
+        server_idle_timeout_seconds=75,
+        server_shutdown_timeout_seconds=0,
+    ))
+
+    assert (config.host, config.port) == ("127.0.0.1", 8443)
+    assert (config.ssl_certfile, config.ssl_keyfile) == (str(cert), str(key))
+    assert config.timeout_keep_alive == 75
+    assert config.timeout_graceful_shutdown is None
+
+
+@pytest.mark.parametrize("values, message", [
+    ({"server_tls_certfile": "/nope/cert.pem"}, "must be set together"),
+    ({"server_tls_certfile": "/nope/cert.pem", "server_tls_keyfile": "/nope/key.pem"},
+     "server_tls_certfile /nope/cert.pem does not exist"),
+    ({"server_request_timeout_seconds": -1}, "server_request_timeout_seconds must not be negative"),
+    ({"server_port": 0}, "server_port must be in 1-65535"),
+])
+def test_bad_server_settings_fail_before_binding(values, message):
+    with pytest.raises(ConfigError, match=message):
+        server_config(_settings(**values))
+
+
+def test_slow_handlers_get_504():
+    app = FastAPI()
+    app.add_middleware(RequestTimeoutMiddleware, seconds=0.05)
+
+    @app.get("/slow")
+    async def slow():
+        await asyncio.sleep(1)
+
+    @app.get("/fast")
+    async def fast():
+        return {"ok": True}
+
+    client = TestClient(app)
+
+    assert client.get("/fast").json() == {"ok": True}
+    response = client.get("/slow")
+    assert response.status_code == 504
+    assert response.json() == {"detail": "request timed out"}
+
+
+def _app(events):
+    @asynccontextmanager
+    async def lifespan(app):
+        events.append("startup")
+        yield
+        events.append("shutdown")
+
+    dao = RedisVenueDAO(GeoRedisClient(fakeredis.FakeRedis(decode_responses=True)))
+    seed_redis(dao, load_fixture(app_settings.get_resource_path("seed_fixture.json")))
+    set_venue_handler(VenueHandler(dao))
+    app = FastAPI(lifespan=lifespan)
+    app.include_router(venue_router)
+
+    @app.get("/health")
+    def health():
+        return {"status": "healthy"}
+
+    return app
+
+
+async def test_start_serves_the_real_router_on_an_ephemeral_port_and_stop_shuts_down():
+    events = []
+    server = HttpServer(_settings(server_host="127.0.0.1"), app=_app(events), port=0)
+
+    await server.start()
+    try:
+        port = server.port
+        assert port and port != 8080
+        assert events == ["startup"]
+        async with httpx.AsyncClient(base_url=server.url) as client:
+            assert (await client.get("/health")).json() == {"status": "healthy"}
+            nearby = await client.get(
+                "/v1/venues/nearby", params={"lat": -8.08, "lon": -34.88, "radius": 10}
+            )
+            assert nearby.status_code == 200
+            assert isinstance(nearby.json(), list)
+        with pytest.raises(RuntimeError, match="already started"):
+            await server.start()
+    finally:
+        await server.stop()
+        await server.stop()  # idempotent
+
+    assert events == ["startup", "shutdown"]
+    with pytest.raises(httpx.ConnectError):
+        async with httpx.AsyncClient() as client:
+            await client.get(f"http://127.0.0.1:{port}/health")
+
+
+@pytest.mark.parametrize("trusted, client_ip", [
+    (["127.0.0.1"], "203.0.113.7"),
+    ([], "127.0.0.1"),  # an untrusted peer's X-Forwarded-For is ignored
+])
+async def test_client_address_comes_from_x_forwarded_for_of_a_trusted_proxy(trusted, client_ip):
+    app = FastAPI()
+
+    @app.get("/ip")
+    def ip(request: Request):
+        return {"client": request.client.host}
+
+    settings = _settings(server_host="127.0.0.1", server_forwarded_allow_ips=trusted)
+    server = HttpServer(settings, app=app, port=0)
+    await server.start()
+    try:
+        async with httpx.AsyncClient(base_url=server.url) as client:
+            response = await client.get("/ip", headers={"X-Forwarded-For": "203.0.113.7"})
+    finally:
+        await server.stop()
+
+    assert response.json() == {"client": client_ip}
+
+
+async def test_start_fails_when_the_port_is_taken():
+    first = HttpServer(_settings(server_host="127.0.0.1"), app=_app([]), port=0)
+    await first.start()
+    try:
+        second = HttpServer(_settings(server_host="127.0.0.1"), app=_app([]), port=first.port)
+        with pytest.raises(RuntimeError):
+            await second.start()
+    finally:
+        await first.stop()
@@ -7,10 +7,13 @@ named lock for live_forecast, weekly_forecast, google_places, and
 rebuild_redis so a paid-refresh job can never run twice concurrently across
 the two trigger paths.
 """
+import asyncio
 import importlib
 from unittest.mock import MagicMock
 
+import fakeredis
 import pytest
+from redis.cluster import RedisCluster
 
 from app.services import job_lock
 
@@ -26,6 +29,9 @@ def setup_function(_):
     """Every job_lock test starts from a clean module-level registry —
     module state persists across tests otherwise (no per-test instance)."""
     job_lock._running.clear()
+    job_lock._tasks.clear()
+    job_lock._lost.clear()
+    job_lock.use_distributed(None)
     admin_trigger_router._running_jobs.clear()
 
 
@@ -57,12 +63,84 @@ def test_locks_are_independent_per_job_name():
     assert job_lock.is_running("weekly_forecast") is True
 
 
-def test_locked_job_names_covers_the_four_paid_refresh_jobs():
+def test_locked_job_names_covers_the_paid_refresh_jobs_and_the_singletons():
     assert job_lock.LOCKED_JOB_NAMES == {
-        "live_forecast", "weekly_forecast", "google_places", "rebuild_redis",
+        "live_forecast", "weekly_forecast", "google_places", "rebuild_redis", "event_outbox",
+        "backup", "venue_dedup",
     }
 
 
+# ── cross-replica lock (RedisJobLock) ────────────────────────────────────────
+def _replica(redis_client, name):
+    return job_lock.RedisJobLock(redis_client, owner=name, ttl_seconds=60)
+
+
+def test_redis_lock_keeps_a_second_replica_out_until_released():
+    redis_client = fakeredis.FakeRedis(decode_responses=True)
+    other = _replica(redis_client, "replica-b")
+    job_lock.use_distributed(_replica(redis_client, "replica-a"))
+
+    assert job_lock.try_acquire("live_forecast") is True
+    assert other.try_acquire("live_forecast") is False
+    assert job_lock.holder("live_forecast")["redis"]["owner"] == "replica-a"
+
+    job_lock.release("live_forecast")
+    assert other.try_acquire("live_forecast") is True
+    assert job_lock.try_acquire("live_forecast") is False  # held by replica-b
+    assert job_lock.is_running("live_forecast") is False
+
+
+def test_redis_lock_is_only_renewed_and_released_by_its_owner():
+    redis_client = fakeredis.FakeRedis(decode_responses=True)
+    owner, other = _replica(redis_client, "replica-a"), _replica(redis_client, "replica-b")
+    owner.try_acquire("weekly_forecast")
+    redis_client.expire("job_lock:weekly_forecast", 5)
+
+    assert other.renew("weekly_forecast") is False
+    other.release("weekly_forecast")
+    assert redis_client.get("job_lock:weekly_forecast") == "replica-a"
+    assert owner.renew("weekly_forecast") is True
+    assert redis_client.ttl("job_lock:weekly_forecast") > 5
+
+
+def test_redis_lock_on_a_cluster_checks_the_owner_in_a_script():
+    # RedisCluster has no WATCH/MULTI; renew/release must not touch pipeline().
+    cluster = MagicMock(spec=RedisCluster)
+    lock = job_lock.RedisJobLock(cluster, owner="replica-a", ttl_seconds=60)
+
+    cluster.eval.return_value = 1
+    assert lock.renew("backup") is True
+    cluster.eval.return_value = 0
+    assert lock.renew("backup") is False
+    lock.release("backup")
+
+    renew_call, _, release_call = cluster.eval.call_args_list
+    assert renew_call.args == (job_lock._RENEW_SCRIPT, 1, "job_lock:backup", "replica-a", 60000)
+    assert release_call.args == (job_lock._RELEASE_SCRIPT, 1, "job_lock:backup", "replica-a")
+    cluster.pipeline.assert_not_called()
+
+
+@pytest.mark.asyncio
+async def test_a_run_that_loses_its_redis_lock_is_cancelled(monkeypatch):
+    monkeypatch.setattr(job_lock, "MIN_RENEW_INTERVAL_SECONDS", 0.01)
+    redis_client = fakeredis.FakeRedis(decode_responses=True)
+    job_lock.use_distributed(job_lock.RedisJobLock(redis_client, owner="replica-a", ttl_seconds=1))
+    assert job_lock.try_acquire("backup") is True
+    run = asyncio.create_task(asyncio.sleep(10))
+    job_lock.bind_task("backup", run)
+
+    # The key expired and another replica took it before the next renewal.
+    redis_client.set("job_lock:backup", "replica-b")
+
+    with pytest.raises(asyncio.CancelledError):
+        await run
+    assert job_lock.lost("backup") is True
+    assert job_lock.holder("backup")["lost"] is True
+    job_lock.release("backup")
+    assert redis_client.get("job_lock:backup") == "replica-b"
+    assert job_lock.lost("backup") is False
+
+
 # ── admin_trigger_router.trigger_job integration ─────────────────────────────
 @pytest.fixture(autouse=True)
 def _container():
@@ -0,0 +1,72 @@
+"""Unit tests for the nearby per-stage timing (app/latency_budget.py)."""
+import pytest
+from fastapi import FastAPI
+from fastapi.testclient import TestClient
+from opentelemetry.sdk.trace import TracerProvider
+
+from app.config import settings
+from app.handlers import VenueHandler
+from app.latency_budget import StageTimer
+from app.routers.venue_router import router as venue_router, set_venue_handler
+from app.services.demo_data import DemoVenueDAO
+
+_LAT, _LNG = -8.05428, -34.88126
+_URL = f"/v1/venues/nearby?lat={_LAT}&lon={_LNG}&radius=10"
+
+
+def _timer(ticks):
+    clock = iter(ticks)
+    return StageTimer(clock=lambda: next(clock))
+
+
+def test_stages_accumulate_and_render_in_pipeline_order():
+    timer = _timer([0.0, 0.001, 0.002, 0.005, 0.010, 0.011])
+    timer.mark("parse")  # 1ms
+    with timer.stage("live_fetch"):  # 3ms
+        pass
+    with timer.stage("live_fetch"):  # +1ms
+        pass
+
+    assert timer.durations["live_fetch"] == pytest.approx(0.004)
+    assert timer.server_timing() == "parse;dur=1.0, live_fetch;dur=4.0"
+    assert timer.summary() == "parse=1.0ms live_fetch=4.0ms"
+
+
+def test_stages_are_set_on_the_active_span():
+    timer = _timer([0.0, 0.0012, 0.002, 0.0055])
+    timer.mark("parse")
+    with timer.stage("geo_query"):
+        pass
+
+    with TracerProvider().get_tracer("test").start_as_current_span("nearby") as span:
+        timer.annotate_span()
+
+    assert span.attributes["nearby.stage.parse_ms"] == 1.2
+    assert span.attributes["nearby.stage.geo_query_ms"] == 3.5
+
+
+def _client():
+    app = FastAPI()
+    app.include_router(venue_router)
+    set_venue_handler(VenueHandler(DemoVenueDAO(_LAT, _LNG, count=5)))
+    return TestClient(app)
+
+
+def test_server_timing_header_lists_every_stage(monkeypatch):
+    monkeypatch.setattr(settings, "nearby_server_timing_enabled", True)
+
+    resp = _client().get(_URL)
+
+    assert resp.status_code == 200
+    assert len(resp.json()) == 5
+    stages = [part.split(";")[0] for part in resp.headers["Server-Timing"].split(", ")]
+    assert stages == ["parse", "geo_query", "live_fetch", "transform", "encode"]
+
+
+def test_no_header_by_default(monkeypatch):
+    monkeypatch.setattr(settings, "nearby_server_timing_enabled", False)
+
+    resp = _client().get(_URL)
+
+    assert resp.status_code == 200
+    assert "server-timing" not in resp.headers
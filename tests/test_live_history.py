@@ -0,0 +1,84 @@
+"""Unit tests for the per-venue live busyness history (live_history_v1:{id}).
+
+fakeredis only; samples are dated by the payload's own venue_current_gmttime.
+"""
+from datetime import datetime, timedelta, timezone
+
+import fakeredis
+
+from app.config import settings
+from app.dao.redis_venue_dao import LIVE_HISTORY_KEY_FORMAT, RedisVenueDAO
+from app.db.geo_redis_client import GeoRedisClient
+from app.models import Analysis, LiveForecastResponse, Venue, VenueInfo
+
+_BASE = datetime(2026, 6, 5, 20, 0, tzinfo=timezone.utc)
+
+
+def _live(vid: str, at: datetime, busyness: int, available: bool = True, gmttime=None):
+    return LiveForecastResponse(
+        status="OK",
+        analysis=Analysis(venue_live_busyness=busyness,
+                          venue_live_busyness_available=available),
+        venue_info=VenueInfo(
+            venue_id=vid,
+            venue_current_gmttime=gmttime if gmttime is not None else at.isoformat(),
+        ),
+    )
+
+
+def _dao():
+    fake = fakeredis.FakeRedis(decode_responses=True)
+    return fake, RedisVenueDAO(GeoRedisClient(fake))
+
+
+class TestLiveHistory:
+    def test_appends_samples_oldest_first(self):
+        _, dao = _dao()
+        for minutes, busy in ((0, 10), (10, 40), (20, 70)):
+            dao.set_live_forecast(_live("v1", _BASE + timedelta(minutes=minutes), busy))
+
+        points = dao.get_live_history("v1")
+
+        assert [p.busyness for p in points] == [10, 40, 70]
+        assert points[0].timestamp == _BASE
+
+    def test_since_filters_older_samples(self):
+        _, dao = _dao()
+        for minutes, busy in ((0, 10), (10, 40), (20, 70)):
+            dao.set_live_forecast(_live("v1", _BASE + timedelta(minutes=minutes), busy))
+
+        points = dao.get_live_history("v1", since=_BASE + timedelta(minutes=10))
+
+        assert [p.busyness for p in points] == [40, 70]
+
+    def test_reprojecting_same_forecast_does_not_duplicate(self):
+        _, dao = _dao()
+        forecast = _live("v1", _BASE, 55)
+        dao.set_live_forecast(forecast)
+        dao.set_live_forecast(forecast)
+        assert len(dao.get_live_history("v1")) == 1
+
+    def test_trims_samples_outside_window_and_sets_expiry(self):
+        fake, dao = _dao()
+        window = timedelta(hours=settings.live_history_window_hours)
+        dao.set_live_forecast(_live("v1", _BASE, 10))
+        dao.set_live_forecast(_live("v1", _BASE + window + timedelta(minutes=1), 20))
+
+        assert [p.busyness for p in dao.get_live_history("v1")] == [20]
+        assert 0 < fake.ttl(LIVE_HISTORY_KEY_FORMAT.format("v1")) <= window.total_seconds()
+
+    def test_unavailable_or_undatable_samples_are_skipped(self):
+        _, dao = _dao()
+        dao.set_live_forecast(_live("v1", _BASE, 10, available=False))
+        dao.set_live_forecast(_live("v1", _BASE, 10, gmttime="garbled"))
+        assert dao.get_live_history("v1") == []
+        assert dao.get_live_forecast("v1") is not None  # the live write itself still lands
+
+    def test_delete_venue_removes_history(self):
+        fake, dao = _dao()
+        dao.upsert_venue(Venue(venue_id="v1", venue_name="Bar", venue_lat=-8.05, venue_lng=-34.88))
+        dao.set_live_forecast(_live("v1", _BASE, 10))
+
+        dao.delete_venue("v1")
+
+        assert not fake.exists(LIVE_HISTORY_KEY_FORMAT.format("v1"))
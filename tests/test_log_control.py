@@ -0,0 +1,167 @@
+"""Unit tests for app.log_control (runtime log level + targeted debug)."""
+import logging
+
+import pytest
+from fastapi import FastAPI
+from fastapi.testclient import TestClient
+
+from app.log_control import (
+    LogControl,
+    RequestLogContextMiddleware,
+    TargetedDebugFilter,
+    log_control,
+)
+from app.routers.admin_trigger_router import router
+
+
+class _Clock:
+    def __init__(self):
+        self.now = 1000.0
+
+    def __call__(self):
+        return self.now
+
+
+@pytest.fixture(autouse=True)
+def _restore_root_level():
+    root = logging.getLogger()
+    level = root.level
+    yield
+    log_control.clear_targets()
+    log_control.set_level(logging.getLevelName(level))
+    root.setLevel(level)
+
+
+def _debug(msg):
+    return logging.LogRecord("t", logging.DEBUG, __file__, 1, msg, (), None)
+
+
+def test_set_level_changes_root_and_rejects_unknown():
+    control = LogControl()
+    assert control.set_level("warning") == "INFO"
+    assert logging.getLogger().level == logging.WARNING
+    with pytest.raises(ValueError):
+        control.set_level("LOUD")
+
+
+def test_module_level_overrides_the_global_level_for_its_subtree():
+    control = LogControl()
+    control.set_level("INFO")
+    control.set_module_level("app.dao", "DEBUG")
+    try:
+        dao_debug = logging.LogRecord(
+            "app.dao.redis_venue_dao", logging.DEBUG, __file__, 1, "get", (), None
+        )
+        other_debug = logging.LogRecord(
+            "app.dataloader", logging.DEBUG, __file__, 1, "get", (), None
+        )
+
+        assert logging.getLogger("app.dao.redis_venue_dao").isEnabledFor(logging.DEBUG)
+        assert control.allows(dao_debug)
+        assert not control.allows(other_debug)
+        assert control.module_levels() == {"app.dao": "DEBUG"}
+    finally:
+        assert control.set_module_level("app.dao", "NOTSET") == "DEBUG"
+
+    assert control.module_levels() == {}
+    assert logging.getLogger("app.dao").level == logging.NOTSET
+    with pytest.raises(ValueError):
+        control.set_module_level("app.dao", "LOUD")
+
+
+def test_venue_target_passes_only_matching_debug_records():
+    control = LogControl()
+    control.add_target("venue", "ven_123", minutes=5)
+
+    assert logging.getLogger().level == logging.DEBUG
+    assert control.allows(_debug("fetching ven_123 live"))
+    assert not control.allows(_debug("fetching ven_999 live"))
+
+
+def test_targets_expire_and_restore_the_global_level():
+    clock = _Clock()
+    control = LogControl(clock=clock)
+    control.add_target("venue", "ven_123", minutes=1)
+    clock.now += 61
+
+    assert not control.allows(_debug("ven_123"))
+    assert control.targets() == []
+    assert logging.getLogger().level == logging.INFO
+
+
+def test_window_and_kind_are_validated():
+    control = LogControl()
+    with pytest.raises(ValueError):
+        control.add_target("venue", "v", minutes=0)
+    with pytest.raises(ValueError):
+        control.add_target("venue", "v", minutes=10_000)
+    with pytest.raises(ValueError):
+        control.add_target("city", "recife", minutes=5)
+    with pytest.raises(ValueError):
+        control.add_target("region", "-8.05,-34.88", minutes=5)
+
+
+def _app_logging_under(control):
+    app = FastAPI()
+    app.add_middleware(RequestLogContextMiddleware)
+    seen = []
+
+    @app.get("/v1/venues/nearby")
+    def nearby(lat: float, lon: float):
+        seen.append(control.allows(_debug("nearby")))
+        return {}
+
+    @app.get("/v1/other")
+    def other():
+        seen.append(control.allows(_debug("other")))
+        return {}
+
+    return TestClient(app), seen
+
+
+def test_path_and_region_targets_match_the_request_being_served():
+    control = LogControl()
+    client, seen = _app_logging_under(control)
+    control.add_target("region", "-8.05,-34.88,5", minutes=5)
+    client.get("/v1/venues/nearby", params={"lat": -8.06, "lon": -34.89})
+    client.get("/v1/venues/nearby", params={"lat": -23.55, "lon": -46.63})
+    control.clear_targets()
+    control.add_target("path", "/v1/venues", minutes=5)
+    client.get("/v1/venues/nearby", params={"lat": 0, "lon": 0})
+    client.get("/v1/other")
+
+    assert seen == [True, False, True, False]
+
+
+def test_filter_keeps_records_at_the_global_level():
+    control = LogControl()
+    record = logging.LogRecord("t", logging.INFO, __file__, 1, "hello", (), None)
+    assert TargetedDebugFilter(control).filter(record)
+    assert not TargetedDebugFilter(control).filter(_debug("hello"))
+
+
+def test_admin_endpoints():
+    app = FastAPI()
+    app.include_router(router)
+    client = TestClient(app)
+
+    assert client.put("/admin/logging/level", json={"level": "nope"}).status_code == 400
+    body = client.put("/admin/logging/level", json={"level": "WARNING"}).json()
+    assert body["level"] == "WARNING"
+    body = client.put(
+        "/admin/logging/level", json={"level": "DEBUG", "logger": "app.services"}
+    ).json()
+    assert body["level"] == "WARNING"
+    assert body["modules"] == {"app.services": "DEBUG"}
+    body = client.put(
+        "/admin/logging/level", json={"level": "NOTSET", "logger": "app.services"}
+    ).json()
+    assert body["modules"] == {}
+
+    body = client.post(
+        "/admin/logging/debug", json={"kind": "venue", "value": "ven_1", "minutes": 10}
+    ).json()
+    assert [(t["kind"], t["value"]) for t in body["debug_targets"]] == [("venue", "ven_1")]
+
+    assert client.delete("/admin/logging/debug").json()["cleared"] == 1
+    assert client.get("/admin/logging").json()["debug_targets"] == []
@@ -0,0 +1,87 @@
+"""Unit tests for JSON log output (app/log_format.py)."""
+import io
+import json
+import logging
+import sys
+
+import pytest
+from fastapi import FastAPI
+from fastapi.testclient import TestClient
+
+from app.log_control import RequestLogContextMiddleware
+from app.log_format import JsonLogFormatter, install_log_format
+
+
+def _record(msg, *args, name="app.services.venues_refresher_service", **extra):
+    record = logging.LogRecord(name, logging.INFO, __file__, 1, msg, args, None)
+    record.__dict__.update(extra)
+    return record
+
+
+def test_component_tag_becomes_a_field():
+    entry = json.loads(JsonLogFormatter().format(
+        _record("[VenuesRefresherService] Refreshed %d venues", 12)
+    ))
+
+    assert entry["level"] == "INFO"
+    assert entry["logger"] == "app.services.venues_refresher_service"
+    assert entry["component"] == "VenuesRefresherService"
+    assert entry["message"] == "Refreshed 12 venues"
+    assert entry["ts"].endswith("+00:00")
+    assert "path" not in entry and "trace_id" not in entry
+
+
+def test_untagged_message_and_extra_fields():
+    entry = json.loads(JsonLogFormatter().format(
+        _record("plain line", venue_id="ven_1", count=3)
+    ))
+
+    assert "component" not in entry
+    assert entry["message"] == "plain line"
+    assert entry["venue_id"] == "ven_1"
+    assert entry["count"] == 3
+
+
+def test_exception_is_included():
+    try:
+        raise ValueError("bad")
+    except ValueError:
+        record = logging.LogRecord("t", logging.ERROR, __file__, 1, "failed", (), sys.exc_info())
+
+    entry = json.loads(JsonLogFormatter().format(record))
+    assert "ValueError: bad" in entry["exception"]
+
+
+def test_request_path_is_included_while_serving():
+    app = FastAPI()
+    app.add_middleware(RequestLogContextMiddleware)
+    lines = []
+
+    @app.get("/v1/venues/nearby")
+    def nearby():
+        lines.append(json.loads(JsonLogFormatter().format(_record("[VenueHandler] hit"))))
+        return {}
+
+    TestClient(app).get("/v1/venues/nearby")
+
+    assert lines[0]["path"] == "/v1/venues/nearby"
+    assert lines[0]["component"] == "VenueHandler"
+
+
+def test_install_log_format():
+    log = logging.getLogger("test_log_format.install")
+    log.propagate = False
+    stream = io.StringIO()
+    handler = logging.StreamHandler(stream)
+    log.addHandler(handler)
+    try:
+        install_log_format("text", log)
+        assert not isinstance(handler.formatter, JsonLogFormatter)
+        install_log_format("json", log)
+        log.warning("[RedisVenueDAO] slow")
+
+        assert json.loads(stream.getvalue())["component"] == "RedisVenueDAO"
+        with pytest.raises(ValueError):
+            install_log_format("xml", log)
+    finally:
+        log.removeHandler(handler)
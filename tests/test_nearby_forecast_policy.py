@@ -0,0 +1,103 @@
+"""Unit tests for the embedded foot-traffic forecast policy of nearby responses."""
+from fastapi import FastAPI
+from fastapi.testclient import TestClient
+
+from app.config import settings
+from app.handlers import VenueHandler
+from app.models import FootTrafficForecast, Venue, VenueWithLive
+from app.routers.venue_router import router as venue_router, set_venue_handler
+from app.services.demo_data import DemoVenueDAO
+
+_LAT, _LNG = -8.05428, -34.88126
+_WEEK = [FootTrafficForecast(day_int=d, day_raw=[d] * 24) for d in range(7)]
+
+
+def _merged(n=3):
+    return [
+        VenueWithLive(
+            venue=Venue(
+                venue_id=f"v{i}", venue_name=f"Bar {i}", venue_lat=_LAT, venue_lng=_LNG,
+                venue_foot_traffic_forecast=list(_WEEK),
+            )
+        )
+        for i in range(n)
+    ]
+
+
+def _apply(monkeypatch, policy, limit=0, n=3, day_int=2):
+    monkeypatch.setattr(settings, "nearby_foot_traffic_forecast", policy)
+    monkeypatch.setattr(settings, "nearby_forecast_max_venues", limit)
+    merged = _merged(n)
+    originals = [m.venue for m in merged]
+    VenueHandler(None)._apply_forecast_policy(merged, day_int)
+    return merged, originals
+
+
+class TestApplyForecastPolicy:
+    def test_full_is_untouched(self, monkeypatch):
+        merged, originals = _apply(monkeypatch, "full")
+        assert all(m.venue is o for m, o in zip(merged, originals))
+        assert all(m.forecast_url is None for m in merged)
+
+    def test_current_day_keeps_only_selected_day(self, monkeypatch):
+        merged, originals = _apply(monkeypatch, "current_day", day_int=4)
+        for m in merged:
+            assert [f.day_int for f in m.venue.venue_foot_traffic_forecast] == [4]
+            assert m.forecast_url is None
+        # Trimmed copies; the DAO's objects keep the whole week.
+        assert len(originals[0].venue_foot_traffic_forecast) == 7
+
+    def test_omit(self, monkeypatch):
+        merged, _ = _apply(monkeypatch, "omit")
+        assert all(m.venue.venue_foot_traffic_forecast is None for m in merged)
+        assert all(m.forecast_url is None for m in merged)
+
+    def test_link(self, monkeypatch):
+        merged, _ = _apply(monkeypatch, "link")
+        assert merged[1].venue.venue_foot_traffic_forecast is None
+        assert merged[1].forecast_url == "/v1/venues/v1/forecast"
+
+    def test_max_venues_links_the_rest(self, monkeypatch):
+        merged, _ = _apply(monkeypatch, "current_day", limit=2, n=4)
+        assert [len(m.venue.venue_foot_traffic_forecast or []) for m in merged] == [1, 1, 0, 0]
+        assert [m.forecast_url for m in merged] == [
+            None, None, "/v1/venues/v2/forecast", "/v1/venues/v3/forecast",
+        ]
+
+    def test_unknown_policy_falls_back_to_full(self, monkeypatch):
+        merged, originals = _apply(monkeypatch, "hourly")
+        assert all(m.venue is o for m, o in zip(merged, originals))
+
+
+class TestForecastEndpoints:
+    def _client(self):
+        dao = DemoVenueDAO(_LAT, _LNG, count=3)
+        for vid, venue in list(dao.venues.items()):
+            dao.venues[vid] = venue.model_copy(update={"venue_foot_traffic_forecast": list(_WEEK)})
+        app = FastAPI()
+        app.include_router(venue_router)
+        set_venue_handler(VenueHandler(dao))
+        return TestClient(app), dao
+
+    def test_default_response_has_week_and_no_forecast_url_key(self):
+        client, _ = self._client()
+        venues = client.get(f"/v1/venues/nearby?lat={_LAT}&lon={_LNG}&radius=10").json()
+        assert len(venues) == 3
+        for v in venues:
+            assert len(v["venue_foot_traffic_forecast"]) == 7
+            assert "forecast_url" not in v
+
+    def test_link_resolves_to_forecast_endpoint(self, monkeypatch):
+        monkeypatch.setattr(settings, "nearby_foot_traffic_forecast", "link")
+        client, _ = self._client()
+        venues = client.get(f"/v1/venues/nearby?lat={_LAT}&lon={_LNG}&radius=10").json()
+        v = venues[0]
+        assert v["venue_foot_traffic_forecast"] is None
+
+        forecast = client.get(v["forecast_url"])
+        assert forecast.status_code == 200
+        assert [d["day_int"] for d in forecast.json()] == list(range(7))
+
+    def test_forecast_endpoint_unknown_venue(self):
+        client, _ = self._client()
+        assert client.get("/v1/venues/nope/forecast").status_code == 404
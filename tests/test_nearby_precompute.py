@@ -0,0 +1,56 @@
+"""Unit tests for precomputed nearby responses (app/services/nearby_precompute.py)."""
+import json
+
+import fakeredis
+from fastapi.encoders import jsonable_encoder
+
+from app.dao.redis_venue_dao import RedisVenueDAO
+from app.db.geo_redis_client import GeoRedisClient
+from app.handlers import VenueHandler
+from app.handlers.venue_handler import nearby_response_exclude
+from app.models import Venue
+from app.services.nearby_precompute import NearbyPrecomputeService
+from app.services.venues_refresher_service import Location
+
+_LAT, _LNG = -8.0476, -34.8770
+
+
+def _setup(**kwargs):
+    redis_client = fakeredis.FakeRedis(decode_responses=True)
+    dao = RedisVenueDAO(GeoRedisClient(redis_client))
+    dao.upsert_venue(Venue(venue_id="v1", venue_name="Bar 1", venue_lat=_LAT + 0.001, venue_lng=_LNG))
+    dao.upsert_venue(Venue(venue_id="v2", venue_name="Bar 2", venue_lat=_LAT + 0.02, venue_lng=_LNG))
+    handler = VenueHandler(dao)
+    service = NearbyPrecomputeService(
+        handler, redis_client,
+        locations_source=lambda: [Location(lat=_LAT, lng=_LNG, radius=6000, limit=100)],
+        radii_km=[1, 3], **kwargs,
+    )
+    return handler, service
+
+
+def test_refresh_stores_the_rendered_response_for_each_radius():
+    handler, service = _setup()
+
+    assert service.refresh() == 2
+
+    body = service.lookup(_LAT, _LNG, 1)
+    rendered = [jsonable_encoder(item, exclude=nearby_response_exclude())
+                for item in handler.get_venues_nearby(_LAT, _LNG, 1)]
+    assert json.loads(body) == rendered
+    assert len(json.loads(service.lookup(_LAT, _LNG, 3))) == 2
+
+
+def test_lookup_matches_only_precomputed_queries():
+    _, service = _setup()
+    service.refresh()
+
+    assert service.lookup(_LAT + 0.00001, _LNG, 3000, unit="m") is not None
+    assert service.lookup(_LAT, _LNG, 2) is None
+    assert service.lookup(_LAT + 0.01, _LNG, 1) is None
+    assert service.lookup(_LAT, _LNG, 1, target_day_offset=1) is None
+    assert service.lookup(_LAT, _LNG, 1, verbose=True) is None
+
+    _, verbose_service = _setup(verbose_variants=True)
+    assert verbose_service.refresh() == 4
+    assert verbose_service.lookup(_LAT, _LNG, 1, verbose=True) is not None
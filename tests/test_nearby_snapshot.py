@@ -0,0 +1,106 @@
+"""Unit tests for the last-known-good nearby snapshot (Redis outage failover)."""
+from unittest.mock import Mock
+
+import fakeredis
+import pytest
+import redis
+
+from app.dao.redis_venue_dao import RedisVenueDAO
+from app.dao.snapshot_venue_dao import SnapshotVenueDAO
+from app.db.geo_redis_client import GeoRedisClient
+from app.handlers import VenueHandler
+from app.models import Analysis, LiveForecastResponse, Venue, VenueInfo, WeekRawDay
+
+_LAT, _LNG = -8.05, -34.88
+
+
+def _venue(vid, lat=_LAT, lng=_LNG):
+    return Venue(venue_id=vid, venue_name=f"Bar {vid}", venue_address="a",
+                 venue_lat=lat, venue_lng=lng, venue_type="BAR")
+
+
+def _source():
+    dao = RedisVenueDAO(GeoRedisClient(fakeredis.FakeRedis(decode_responses=True)))
+    dao.upsert_venues([_venue("near"), _venue("far", lat=-23.55, lng=-46.63)])
+    dao.set_live_forecast(LiveForecastResponse(
+        status="OK",
+        venue_info=VenueInfo(venue_id="near"),
+        analysis=Analysis(venue_live_busyness=70, venue_live_busyness_available=True),
+    ))
+    dao.set_week_raw_forecast("near", WeekRawDay(day_int=4, day_raw=[30] * 24))
+    return dao
+
+
+class _Clock:
+    def __init__(self):
+        self.now = 1000.0
+
+    def __call__(self):
+        return self.now
+
+
+def _down_dao():
+    return Mock(get_nearby_venues=Mock(side_effect=redis.ConnectionError("down")))
+
+
+class TestSnapshotVenueDAO:
+    def test_refresh_copies_serving_reads(self):
+        snapshot = SnapshotVenueDAO(_source(), max_age_seconds=60)
+        assert snapshot.refresh() == 2
+
+        assert [v.venue_id for v in snapshot.get_nearby_venues(_LAT, _LNG, 1.0)] == ["near"]
+        assert snapshot.get_live_forecasts_bulk(["near", "far"])["near"].analysis.venue_live_busyness == 70
+        assert set(snapshot.get_week_raw_forecasts_bulk(["near", "far"], 4)) == {"near"}
+
+    def test_empty_source_keeps_previous_snapshot(self):
+        source = _source()
+        snapshot = SnapshotVenueDAO(source, max_age_seconds=60)
+        snapshot.refresh()
+        source.client.client.flushall()
+
+        assert snapshot.refresh() == 2
+        assert snapshot.get_venue("near") is not None
+
+    def test_usable_only_within_max_age(self):
+        clock = _Clock()
+        snapshot = SnapshotVenueDAO(_source(), max_age_seconds=60, clock=clock)
+        assert not snapshot.usable()
+        snapshot.refresh()
+        clock.now += 60
+        assert snapshot.usable()
+        clock.now += 1
+        assert not snapshot.usable()
+
+
+class TestHandlerFailover:
+    def test_redis_error_serves_snapshot_flagged_stale(self):
+        snapshot = SnapshotVenueDAO(_source(), max_age_seconds=60)
+        snapshot.refresh()
+
+        result = VenueHandler(_down_dao(), snapshot=snapshot).get_venues_nearby(
+            _LAT, _LNG, 1.0, verbose=True
+        )
+
+        assert [r.venue.venue_id for r in result] == ["near"]
+        assert result[0].stale is True
+        assert result[0].live_forecast.analysis.venue_live_busyness == 70
+
+    def test_no_usable_snapshot_reraises(self):
+        snapshot = SnapshotVenueDAO(_source(), max_age_seconds=60)  # never refreshed
+        with pytest.raises(redis.ConnectionError):
+            VenueHandler(_down_dao(), snapshot=snapshot).get_venues_nearby(_LAT, _LNG, 1.0)
+
+    def test_without_snapshot_errors_propagate(self):
+        with pytest.raises(redis.ConnectionError):
+            VenueHandler(_down_dao()).get_venues_nearby(_LAT, _LNG, 1.0)
+
+    def test_healthy_redis_is_not_flagged(self):
+        source = _source()
+        snapshot = SnapshotVenueDAO(source, max_age_seconds=60)
+        snapshot.refresh()
+
+        result = VenueHandler(source, snapshot=snapshot).get_venues_nearby(
+            _LAT, _LNG, 1.0, verbose=True
+        )
+
+        assert result[0].stale is None
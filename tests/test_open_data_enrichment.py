@@ -0,0 +1,147 @@
+"""Unit tests for OpenStreetMap / Foursquare enrichment (app/services/open_data_enrichment_service.py)."""
+from datetime import datetime, timezone
+from unittest.mock import Mock, patch
+
+import fakeredis
+import pytest
+from apscheduler.triggers.cron import CronTrigger
+
+from app.api.foursquare_client import _place_to_candidate
+from app.api.osm_overpass_client import _element_to_candidate
+from app.config import Settings
+from app.models import OpenDataCandidate, Venue
+from app.services.open_data_enrichment_service import (
+    OPEN_DATA_MISS_KEY_PREFIX,
+    OpenDataEnrichmentService,
+    best_match,
+    name_similarity,
+)
+
+LAT, LNG = -8.0631, -34.8711
+
+
+def _venue(name="Bar do Geraldo", venue_id="ven_1"):
+    return Venue(venue_id=venue_id, venue_name=name, venue_lat=LAT, venue_lng=LNG)
+
+
+def _candidate(source="osm", name="Bar do Geraldo", lat=LAT, lng=LNG, **fields):
+    return OpenDataCandidate(
+        source=source, source_id=f"{source}-1", name=name, lat=lat, lng=lng, **fields
+    )
+
+
+class FakeSource:
+    def __init__(self, name, candidates=(), error=None):
+        self.name = name
+        self.candidates = list(candidates)
+        self.error = error
+
+    async def search_around(self, lat, lng, radius_m, name):
+        if self.error:
+            raise self.error
+        return self.candidates
+
+
+def test_name_similarity_folds_accents_and_containment():
+    assert name_similarity("Café São Braz", "cafe sao braz") == 1.0
+    assert name_similarity("Bar do Geraldo", "Geraldo") == 1.0
+    assert name_similarity("Bar do Geraldo", "Padaria Boa Vista") < 0.5
+
+
+def test_best_match_respects_distance_and_similarity():
+    near = _candidate(name="Geraldo Bar")
+    far = _candidate(name="Bar do Geraldo", lat=LAT + 0.01)  # ~1.1 km away
+    other = _candidate(name="Padaria Boa Vista")
+
+    match = best_match(_venue(), [far, other, near], max_distance_m=150, min_similarity=0.6)
+
+    assert match is not None and match[0] is near
+    assert best_match(_venue(), [far, other], 150, 0.6) is None
+
+
+@pytest.mark.asyncio
+async def test_later_sources_fill_gaps_of_the_primary_match():
+    osm = FakeSource("osm", [_candidate(categories=["bar"], opening_hours="Mo-Su 18:00-02:00")])
+    fsq = FakeSource("foursquare", [_candidate(
+        "foursquare", categories=["Bar", "bar"], website="https://geraldo.example", phone="+55 81 3333",
+    )])
+    service = OpenDataEnrichmentService([osm, fsq], Mock())
+
+    enrichment = await service.match_venue(_venue())
+
+    assert (enrichment.source, enrichment.sources) == ("osm", ["osm", "foursquare"])
+    assert enrichment.categories == ["bar", "Bar"]
+    assert enrichment.opening_hours == "Mo-Su 18:00-02:00"
+    assert (enrichment.website, enrichment.phone) == ("https://geraldo.example", "+55 81 3333")
+
+
+@pytest.mark.asyncio
+async def test_one_failing_source_does_not_fail_the_match():
+    service = OpenDataEnrichmentService(
+        [FakeSource("osm", error=RuntimeError("overpass busy")), FakeSource("foursquare", [_candidate("foursquare")])],
+        Mock(),
+    )
+    assert (await service.match_venue(_venue())).source == "foursquare"
+
+    service.sources = [FakeSource("osm", error=RuntimeError("down"))]
+    with pytest.raises(RuntimeError):
+        await service.match_venue(_venue())
+
+
+@pytest.mark.asyncio
+async def test_enrich_all_venues_stores_matches_and_remembers_misses():
+    venues = {"ven_1": _venue(), "ven_2": _venue("Padaria Boa Vista", "ven_2")}
+    dao = Mock()
+    dao.list_servable_venue_ids.return_value = list(venues)
+    dao.get_venue.side_effect = lambda vid: venues[vid].model_copy()
+    redis = fakeredis.FakeRedis(decode_responses=True)
+    service = OpenDataEnrichmentService(
+        [FakeSource("osm", [_candidate(website="https://geraldo.example")])], dao, redis_client=redis
+    )
+
+    with patch("app.services.open_data_enrichment_service.REQUEST_DELAY", 0):
+        summary = await service.enrich_all_venues()
+
+    assert summary == {"matched": 1, "no_match": 1, "error": 0, "skipped": 0}
+    stored = dao.upsert_venue.call_args[0][0]
+    assert stored.venue_id == "ven_1" and stored.open_data.website == "https://geraldo.example"
+    assert redis.exists(f"{OPEN_DATA_MISS_KEY_PREFIX}ven_2")
+
+    venues["ven_1"] = stored
+    with patch("app.services.open_data_enrichment_service.REQUEST_DELAY", 0):
+        summary = await service.enrich_all_venues()
+    assert summary["skipped"] == 2
+
+
+def test_client_payloads_map_to_candidates():
+    osm = _element_to_candidate({
+        "type": "way", "id": 42, "center": {"lat": LAT, "lon": LNG},
+        "tags": {"name": "Geraldo", "amenity": "bar", "cuisine": "regional;pizza",
+                 "contact:phone": "+55 81 3333", "opening_hours": "Mo-Su 18:00-02:00",
+                 "image": "https://cdn.example/geraldo.jpg", "wikimedia_commons": "File:Bar do Geraldo.jpg"},
+    })
+    assert (osm.source_id, osm.categories, osm.phone) == ("way/42", ["bar", "regional", "pizza"], "+55 81 3333")
+    assert osm.photos == [
+        "https://cdn.example/geraldo.jpg",
+        "https://commons.wikimedia.org/wiki/Special:FilePath/Bar_do_Geraldo.jpg",
+    ]
+    assert _element_to_candidate({"type": "node", "id": 1, "lat": LAT, "lon": LNG, "tags": {}}) is None
+
+    fsq = _place_to_candidate({
+        "fsq_id": "4b0588", "name": "Geraldo",
+        "geocodes": {"main": {"latitude": LAT, "longitude": LNG}},
+        "categories": [{"name": "Bar"}], "website": "https://geraldo.example",
+        "hours": {"display": "Seg-Dom 18:00-2:00"},
+        "photos": [{"prefix": "https://fastly.4sqi.net/img/general/", "suffix": "/1_a.jpg"}],
+    })
+    assert (fsq.source, fsq.categories, fsq.opening_hours) == ("foursquare", ["Bar"], "Seg-Dom 18:00-2:00")
+    assert fsq.photos == ["https://fastly.4sqi.net/img/general/original/1_a.jpg"]
+
+
+def test_default_cron_fires_on_tuesday():
+    trigger = CronTrigger.from_crontab(
+        Settings(besttime_mode="replay").open_data_enrichment_cron, timezone=timezone.utc
+    )
+    monday = datetime(2026, 10, 12, tzinfo=timezone.utc)
+
+    assert trigger.get_next_fire_time(None, monday) == datetime(2026, 10, 13, 5, 0, tzinfo=timezone.utc)
@@ -0,0 +1,93 @@
+"""The OpenAPI document (app/openapi.py and the routers' models)."""
+import importlib
+import json
+import sys
+
+import pytest
+from fastapi import FastAPI
+from fastapi.testclient import TestClient
+
+from app.openapi import OPENAPI_TAGS, install_docs, write_openapi
+from app.routers import (
+    admin_trigger_router,
+    debug_router,
+    engagement_router,
+    internal_router,
+    partner_router,
+    venue_router,
+    webhook_router,
+)
+
+
+def _app():
+    app = FastAPI(title="CS-Server API", openapi_tags=OPENAPI_TAGS, docs_url=None, redoc_url=None)
+    install_docs(app)
+    for router in (venue_router, debug_router, admin_trigger_router, engagement_router,
+                   internal_router, partner_router, webhook_router):
+        app.include_router(router)
+    return app
+
+
+def test_every_operation_has_a_declared_tag():
+    document = _app().openapi()
+    declared = {tag["name"] for tag in OPENAPI_TAGS}
+
+    for path, operations in document["paths"].items():
+        for method, operation in operations.items():
+            assert set(operation.get("tags", [])) <= declared, (method, path)
+            assert operation.get("tags"), (method, path)
+
+
+def test_client_routes_document_their_bodies_and_responses():
+    document = _app().openapi()
+    paths = document["paths"]
+
+    nearby = paths["/v1/venues/nearby"]["get"]
+    assert {p["name"] for p in nearby["parameters"]} >= {"lat", "lon", "radius"}
+    assert nearby["responses"]["200"]["content"]["application/json"]["schema"]
+
+    favorite = paths["/v1/favorites"]["post"]
+    assert favorite["requestBody"]["content"]["application/json"]["schema"]["$ref"].endswith(
+        "/EngagementRequest"
+    )
+    assert favorite["responses"]["200"]["content"]["application/json"]["schema"]["$ref"].endswith(
+        "/StatusResponse"
+    )
+    schemas = document["components"]["schemas"]
+    assert {"VenueWithLive", "MinifiedVenue", "PushSubscriptionResponse"} <= set(schemas)
+
+
+def test_served_at_openapi_json_with_swagger_ui():
+    client = TestClient(_app())
+
+    assert client.get("/openapi.json").json()["info"]["title"] == "CS-Server API"
+    page = client.get("/docs").text
+    assert "/docs/assets/swagger-ui-bundle.js" in page
+    assert "cdn.jsdelivr.net" not in page
+    assert client.get("/docs/assets/swagger-ui-bundle.js").status_code == 200
+    assert client.get("/docs/assets/swagger-ui.css").status_code == 200
+
+
+@pytest.mark.parametrize("enabled", [True, False])
+def test_main_app_follows_openapi_enabled(monkeypatch, enabled):
+    monkeypatch.delenv("CONFIG_FILE", raising=False)
+    monkeypatch.setenv("OPENAPI_ENABLED", str(enabled).lower())
+    # Import main afresh so its app is built from this environment; no
+    # connections are made until its lifespan runs.
+    monkeypatch.delitem(sys.modules, "main", raising=False)
+    client = TestClient(importlib.import_module("main").app)
+
+    expected = 200 if enabled else 404
+    assert client.get("/openapi.json").status_code == expected
+    assert client.get("/docs").status_code == expected
+    assert client.get("/docs/assets/swagger-ui.css").status_code == expected
+    assert client.get("/redoc").status_code == 404
+
+
+def test_write_openapi_to_a_file(tmp_path):
+    path = tmp_path / "openapi.json"
+
+    document = write_openapi(_app(), str(path))
+
+    assert json.loads(path.read_text()) == document
+    assert document["openapi"].startswith("3.")
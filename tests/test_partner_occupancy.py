@@ -0,0 +1,196 @@
+"""Tests for partner occupancy ingestion (POST /v1/partners/venues/{id}/occupancy).
+
+fakeredis only; readings are dated relative to a fixed clock in the service
+tests and to the real clock where the serving freshness gate is involved.
+"""
+from datetime import datetime, timedelta, timezone
+
+import fakeredis
+import pytest
+from fastapi import FastAPI
+from fastapi.testclient import TestClient
+
+from app.config import settings
+from app.dao.redis_venue_dao import PARTNER_LIVE_KEY_FORMAT, RedisVenueDAO
+from app.db.geo_redis_client import GeoRedisClient
+from app.handlers import VenueHandler
+from app.metrics import PARTNER_OCCUPANCY_READINGS_TOTAL
+from app.models import Analysis, LiveForecastResponse, Venue, VenueInfo
+from app.routers.partner_router import router, set_partner_service
+from app.services.crowd_providers import (
+    BestTimeCrowdProvider,
+    CrowdProviderRegistry,
+    RegionalProvider,
+)
+from app.services.partner_occupancy_service import (
+    PartnerAuthError,
+    PartnerCrowdProvider,
+    PartnerForbiddenError,
+    PartnerOccupancyService,
+    PartnerReadingError,
+    occupancy_to_busyness,
+)
+
+_NOW = datetime(2026, 10, 16, 21, 0, tzinfo=timezone.utc)
+_LAT, _LNG = -8.05, -34.88
+
+
+def _dao():
+    fake = fakeredis.FakeRedis(decode_responses=True)
+    return fake, RedisVenueDAO(GeoRedisClient(fake))
+
+
+def _service(dao, now=_NOW):
+    return PartnerOccupancyService(
+        dao,
+        api_keys={"secret-a": "club_a"},
+        partner_venues={"club_a": ["v1"]},
+        max_age_minutes=30,
+        now_fn=lambda: now,
+    )
+
+
+class TestOccupancyToBusyness:
+    def test_percentage_of_capacity_capped_at_100(self):
+        assert occupancy_to_busyness(50, 200) == 25
+        assert occupancy_to_busyness(250, 200) == 100
+        assert occupancy_to_busyness(0, 200) == 0
+
+    def test_rejects_bad_counts(self):
+        with pytest.raises(PartnerReadingError):
+            occupancy_to_busyness(10, 0)
+        with pytest.raises(PartnerReadingError):
+            occupancy_to_busyness(-1, 10)
+
+
+class TestPartnerOccupancyService:
+    def test_authenticate(self):
+        _, dao = _dao()
+        svc = _service(dao)
+        assert svc.authenticate("secret-a") == "club_a"
+        for bad in (None, "", "secret-b"):
+            with pytest.raises(PartnerAuthError):
+                svc.authenticate(bad)
+
+    def test_ingest_stores_reading_and_history(self):
+        fake, dao = _dao()
+        svc = _service(dao)
+
+        svc.ingest("club_a", "v1", occupancy=120, capacity=200,
+                   observed_at=_NOW - timedelta(minutes=10))
+
+        stored = dao.get_partner_live("v1")
+        assert stored.analysis.venue_live_busyness == 60
+        assert stored.analysis.venue_live_busyness_available
+        # TTL is what is left of the 30-minute window.
+        assert 0 < fake.ttl(PARTNER_LIVE_KEY_FORMAT.format("v1")) <= 20 * 60
+        history = dao.get_live_history("v1")
+        assert [(p.timestamp, p.busyness) for p in history] == [
+            (_NOW - timedelta(minutes=10), 60)
+        ]
+
+    def test_unassigned_venue_is_forbidden(self):
+        _, dao = _dao()
+        before = PARTNER_OCCUPANCY_READINGS_TOTAL.labels(result="forbidden")._value.get()
+        with pytest.raises(PartnerForbiddenError):
+            _service(dao).ingest("club_a", "v2", occupancy=1, capacity=10)
+        assert dao.get_partner_live("v2") is None
+        assert PARTNER_OCCUPANCY_READINGS_TOTAL.labels(result="forbidden")._value.get() == before + 1
+
+    def test_rejects_stale_and_future_readings(self):
+        _, dao = _dao()
+        svc = _service(dao)
+        with pytest.raises(PartnerReadingError):
+            svc.ingest("club_a", "v1", 1, 10, observed_at=_NOW - timedelta(minutes=31))
+        with pytest.raises(PartnerReadingError):
+            svc.ingest("club_a", "v1", 1, 10, observed_at=_NOW + timedelta(minutes=10))
+        assert dao.get_partner_live("v1") is None
+
+
+class TestPartnerRouter:
+    def _client(self, service):
+        app = FastAPI()
+        app.include_router(router)
+        set_partner_service(service)
+        return TestClient(app)
+
+    def test_push_requires_key(self):
+        _, dao = _dao()
+        client = self._client(_service(dao, now=datetime.now(timezone.utc)))
+        body = {"occupancy": 30, "capacity": 100}
+
+        assert client.post("/v1/partners/venues/v1/occupancy", json=body).status_code == 401
+        resp = client.post("/v1/partners/venues/v1/occupancy", json=body,
+                           headers={"X-Partner-Key": "secret-a"})
+
+        assert resp.status_code == 200
+        assert resp.json()["busyness"] == 30
+        assert resp.json()["source"] == "partner"
+
+    def test_push_for_other_venue_is_403_and_bad_counts_422(self):
+        _, dao = _dao()
+        client = self._client(_service(dao, now=datetime.now(timezone.utc)))
+        headers = {"X-Partner-Key": "secret-a"}
+
+        other = client.post("/v1/partners/venues/v2/occupancy",
+                            json={"occupancy": 1, "capacity": 10}, headers=headers)
+        bad = client.post("/v1/partners/venues/v1/occupancy",
+                          json={"occupancy": 1, "capacity": 0}, headers=headers)
+
+        assert other.status_code == 403
+        assert bad.status_code == 422
+
+    def test_unconfigured_is_503(self):
+        client = self._client(None)
+        resp = client.post("/v1/partners/venues/v1/occupancy",
+                           json={"occupancy": 1, "capacity": 10},
+                           headers={"X-Partner-Key": "secret-a"})
+        assert resp.status_code == 503
+
+
+def _besttime_live(vid, busyness):
+    return LiveForecastResponse(
+        status="OK",
+        analysis=Analysis(venue_live_busyness=busyness, venue_live_busyness_available=True),
+        venue_info=VenueInfo(
+            venue_id=vid, venue_current_gmttime=datetime.now(timezone.utc).isoformat()
+        ),
+    )
+
+
+class TestPartnerPreferredOverBestTime:
+    def test_nearby_serves_partner_reading_with_source(self, monkeypatch):
+        monkeypatch.setattr(settings, "partner_venues", {"club_a": ["v1"]})
+        _, dao = _dao()
+        for vid in ("v1", "v2"):
+            dao.upsert_venue(Venue(
+                venue_id=vid, venue_name=vid, venue_address="Rua", venue_lat=_LAT,
+                venue_lng=_LNG, forecast=True, processed=True,
+            ))
+            dao.set_live_forecast(_besttime_live(vid, 90))
+        _service(dao, now=datetime.now(timezone.utc)).ingest("club_a", "v1", 20, 100)
+
+        venues = {
+            v.venue_id: v
+            for v in VenueHandler(dao).get_venues_nearby(_LAT, _LNG, 1, verbose=False)
+        }
+
+        assert (venues["v1"].venue_live_busyness, venues["v1"].live_source) == (20, "partner")
+        assert (venues["v2"].venue_live_busyness, venues["v2"].live_source) == (90, "besttime")
+
+    @pytest.mark.asyncio
+    async def test_registry_prefers_partner_for_covered_venues(self):
+        _, dao = _dao()
+        _service(dao).ingest("club_a", "v1", 20, 100)
+
+        class BestTime:
+            async def get_live_forecast(self, venue_id):
+                return _besttime_live(venue_id, 90)
+
+        registry = CrowdProviderRegistry([
+            RegionalProvider(PartnerCrowdProvider(dao), venue_ids=frozenset({"v1"})),
+            BestTimeCrowdProvider(BestTime()),
+        ])
+
+        assert (await registry.get_live_forecast("v1")).analysis.venue_live_busyness == 20
+        assert (await registry.get_live_forecast("v2")).analysis.venue_live_busyness == 90
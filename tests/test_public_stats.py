@@ -0,0 +1,77 @@
+"""Unit tests for the public stats page (app/services/public_stats.py)."""
+from datetime import datetime, timedelta, timezone
+
+import fakeredis
+from fastapi import FastAPI
+from fastapi.testclient import TestClient
+
+from app.dao.redis_venue_dao import RedisVenueDAO
+from app.db.geo_redis_client import GeoRedisClient
+from app.models import Analysis, LiveForecastResponse, Venue, VenueInfo
+from app.routers.venue_router import router as venue_router, set_public_stats_service
+from app.services.public_stats import PublicStatsService, coarse_count
+
+_NOW = datetime(2026, 10, 16, 20, 0, tzinfo=timezone.utc)
+
+
+def _dao():
+    return RedisVenueDAO(GeoRedisClient(fakeredis.FakeRedis(decode_responses=True)))
+
+
+def _venue(vid, lat=-8.05, lng=-34.88):
+    return Venue(venue_id=vid, venue_name=vid, venue_address="a", venue_lat=lat, venue_lng=lng)
+
+
+def _live(vid, minutes_ago):
+    return LiveForecastResponse(
+        status="OK",
+        venue_info=VenueInfo(venue_id=vid),
+        analysis=Analysis(venue_live_busyness=50, venue_live_busyness_available=True),
+        refreshed_at=_NOW - timedelta(minutes=minutes_ago),
+    )
+
+
+def test_coarse_counts():
+    assert [coarse_count(n) for n in (0, 7, 10, 14, 15, 1234)] == [0, 7, 10, 10, 20, 1230]
+
+
+def test_stats_group_by_fence_city_and_report_freshness():
+    dao = _dao()
+    dao.upsert_venues([_venue("v1"), _venue("v2"), _venue("far", lat=-23.55, lng=-46.63)])
+    dao.set_live_forecast(_live("v1", 4))
+    dao.set_live_forecast(_live("v2", 10))
+
+    stats = PublicStatsService(dao, clock=lambda: _NOW).get_stats()
+
+    assert stats["venues_tracked"] == 3
+    assert stats["cities"] == [
+        {"slug": "recife", "name": "Recife", "venues": 2},
+        {"slug": "other", "name": "Other", "venues": 1},
+    ]
+    assert stats["live_data"] == {"venues_with_live_data": 2, "median_age_minutes": 7}
+
+
+def test_stats_are_reused_within_the_ttl():
+    dao = _dao()
+    ticks = iter([0.0, 10.0, 400.0])
+    service = PublicStatsService(dao, ttl_seconds=300, clock=lambda: _NOW, monotonic=lambda: next(ticks))
+
+    assert service.get_stats()["venues_tracked"] == 0
+    dao.upsert_venue(_venue("v1"))
+    assert service.get_stats()["venues_tracked"] == 0  # cached
+    assert service.get_stats()["venues_tracked"] == 1  # TTL elapsed
+
+
+def test_endpoint_is_cacheable():
+    app = FastAPI()
+    app.include_router(venue_router)
+    client = TestClient(app)
+    set_public_stats_service(None)
+    assert client.get("/v1/stats/public").status_code == 503
+
+    set_public_stats_service(PublicStatsService(_dao(), ttl_seconds=120, clock=lambda: _NOW))
+    response = client.get("/v1/stats/public")
+
+    assert response.status_code == 200
+    assert response.headers["Cache-Control"] == "public, max-age=120"
+    assert response.json()["live_data"]["median_age_minutes"] is None
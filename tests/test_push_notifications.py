@@ -0,0 +1,136 @@
+"""Unit tests for favorite-venue push notifications
+(app/services/push_notifications.py, app/api/fcm_client.py)."""
+from datetime import datetime, timezone
+
+import fakeredis
+import httpx
+import pytest
+
+from app.api.fcm_client import FcmClient, FcmUnregisteredError
+from app.dao.redis_venue_dao import RedisVenueDAO
+from app.db.geo_redis_client import GeoRedisClient
+from app.models import Analysis, LiveForecastResponse, Venue, VenueInfo
+from app.services.push_notifications import MAX_DEVICES_PER_USER, PushNotifier
+
+_NOW = datetime.now(timezone.utc)
+
+
+def _live(vid, busyness):
+    return LiveForecastResponse(
+        status="OK",
+        venue_info=VenueInfo(venue_id=vid, venue_current_gmttime=_NOW.isoformat()),
+        analysis=Analysis(venue_live_busyness=busyness, venue_live_busyness_available=True),
+    )
+
+
+class _Sender:
+    def __init__(self, unregistered=()):
+        self.sent = []
+        self.unregistered = set(unregistered)
+
+    async def send(self, token, title, body, data):
+        if token in self.unregistered:
+            raise FcmUnregisteredError("UNREGISTERED")
+        self.sent.append((token, title, data["venue_id"]))
+        return "projects/p/messages/1"
+
+
+def _notifier(sender):
+    redis = fakeredis.FakeRedis(decode_responses=True)
+    dao = RedisVenueDAO(GeoRedisClient(redis))
+    dao.upsert_venues([
+        Venue(venue_id="bar", venue_name="Bar do Zé", venue_address="a", venue_lat=-8.06, venue_lng=-34.87),
+        Venue(venue_id="club", venue_name="Club", venue_address="a", venue_lat=-8.06, venue_lng=-34.87),
+    ])
+    redis.sadd("user_favorites:u1", "bar", "club")
+    return redis, dao, PushNotifier(dao, redis, sender, default_threshold=80)
+
+
+async def test_pushes_favorites_once_per_crossing():
+    sender = _Sender()
+    _, dao, notifier = _notifier(sender)
+    notifier.subscribe("u1", "tok-a", "android")
+    notifier.subscribe("u1", "tok-b", "ios")
+    dao.set_live_forecast(_live("bar", 85))
+    dao.set_live_forecast(_live("club", 50))
+
+    await notifier.evaluate()
+    await notifier.evaluate()
+
+    assert sorted(sender.sent) == [("tok-a", "Bar do Zé", "bar"), ("tok-b", "Bar do Zé", "bar")]
+
+    dao.set_live_forecast(_live("bar", 30))
+    await notifier.evaluate()
+    dao.set_live_forecast(_live("bar", 90))
+    await notifier.evaluate()
+    assert len(sender.sent) == 4
+
+
+async def test_user_threshold_overrides_default():
+    sender = _Sender()
+    _, dao, notifier = _notifier(sender)
+    notifier.subscribe("u1", "tok-a", "android", threshold=40)
+    dao.set_live_forecast(_live("club", 50))
+
+    await notifier.evaluate()
+
+    assert sender.sent == [("tok-a", "Club", "club")]
+
+
+async def test_unregistered_token_is_dropped():
+    sender = _Sender(unregistered={"tok-dead"})
+    _, dao, notifier = _notifier(sender)
+    notifier.subscribe("u1", "tok-dead", "android")
+    notifier.subscribe("u1", "tok-a", "android")
+    dao.set_live_forecast(_live("bar", 85))
+
+    summary = await notifier.evaluate()
+
+    assert summary["unregistered"] == 1
+    assert list(notifier.get("u1").devices) == ["tok-a"]
+
+
+def test_subscription_keeps_newest_devices():
+    _, _, notifier = _notifier(_Sender())
+    for i in range(MAX_DEVICES_PER_USER + 2):
+        notifier.subscribe("u1", f"tok-{i}", "android")
+
+    devices = notifier.get("u1").devices
+    assert len(devices) == MAX_DEVICES_PER_USER
+    assert "tok-0" not in devices
+
+    notifier.unsubscribe("u1")
+    assert notifier.get("u1") is None
+
+
+async def test_fcm_client_sends_and_maps_unregistered():
+    requests = []
+
+    def handler(request: httpx.Request) -> httpx.Response:
+        requests.append(request)
+        if b"tok-dead" in request.content:
+            return httpx.Response(404, json={"error": {"status": "NOT_FOUND"}})
+        if b"tok-rotated" in request.content:
+            return httpx.Response(400, json={"error": {"status": "INVALID_ARGUMENT", "details": [
+                {"@type": "type.googleapis.com/google.firebase.fcm.v1.FcmError", "errorCode": "UNREGISTERED"},
+            ]}})
+        if b"bad-payload" in request.content:
+            return httpx.Response(400, json={"error": {"status": "INVALID_ARGUMENT", "details": [
+                {"@type": "type.googleapis.com/google.firebase.fcm.v1.FcmError", "errorCode": "INVALID_ARGUMENT"},
+            ]}})
+        return httpx.Response(200, json={"name": "projects/p/messages/1"})
+
+    client = FcmClient(
+        "p", lambda: "access", http_client=httpx.AsyncClient(transport=httpx.MockTransport(handler)),
+    )
+
+    assert await client.send("tok-a", "t", "b", {"venue_id": "bar"}) == "projects/p/messages/1"
+    assert requests[0].url.path == "/v1/projects/p/messages:send"
+    assert requests[0].headers["Authorization"] == "Bearer access"
+    with pytest.raises(FcmUnregisteredError):
+        await client.send("tok-dead", "t", "b", {})
+    with pytest.raises(FcmUnregisteredError):
+        await client.send("tok-rotated", "t", "b", {})
+    # A rejected message says nothing about the token: not unregistered.
+    with pytest.raises(httpx.HTTPStatusError):
+        await client.send("tok-a", "t", "bad-payload", {})
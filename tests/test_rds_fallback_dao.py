@@ -0,0 +1,94 @@
+"""Unit tests for serving nearby reads from RDS when Redis cannot."""
+from unittest.mock import patch
+
+import fakeredis
+import pytest
+import redis
+
+from app.dao.rds_fallback_dao import RdsFallbackVenueDAO
+from app.db.geo_redis_client import GeoRedisClient
+from app.metrics import VENUE_READ_FALLBACK_TOTAL
+from app.models import Venue
+from tests.rds_fake import InMemoryRdsVenueStore
+
+CENTER = (-8.05, -34.88)
+
+
+def _venue(venue_id, lat=CENTER[0], lng=CENTER[1], name="Boteco"):
+    return Venue(
+        venue_id=venue_id, venue_name=name, venue_address="a",
+        venue_lat=lat, venue_lng=lng, venue_type="BAR",
+    )
+
+
+@pytest.fixture
+def store():
+    store = InMemoryRdsVenueStore()
+    store.upsert_venue(_venue("near"))
+    store.upsert_venue(_venue("edge", lat=CENTER[0] + 0.017))  # ~1.9 km north
+    store.upsert_venue(_venue("far", lat=CENTER[0] + 0.05))    # ~5.6 km north
+    store.upsert_venue(_venue("church", name="Some Parish").model_copy(update={"venue_type": "CHURCH"}))
+    return store
+
+
+@pytest.fixture
+def raw():
+    return fakeredis.FakeRedis(decode_responses=True)
+
+
+def _dao(raw, store, mode="dual"):
+    return RdsFallbackVenueDAO(GeoRedisClient(raw), store, mode=mode)
+
+
+def _ids(venues):
+    return [v.venue_id for v in venues]
+
+
+def _fallbacks(reason):
+    return VENUE_READ_FALLBACK_TOTAL.labels(reason=reason)._value.get()
+
+
+def test_dual_prefers_redis(raw, store):
+    dao = _dao(raw, store)
+    dao.upsert_venue(_venue("redis_only"))
+    assert _ids(dao.get_nearby_venues(*CENTER, radius=2)) == ["redis_only"]
+
+
+def test_dual_empty_index_reads_servable_rds_rows_by_distance(raw, store):
+    before = _fallbacks("empty_index")
+    venues = _dao(raw, store).get_nearby_venues(*CENTER, radius=2)
+    assert _ids(venues) == ["near", "edge"]
+    assert _fallbacks("empty_index") - before == 1
+
+
+def test_dual_redis_error_reads_rds(raw, store):
+    dao = _dao(raw, store)
+    before = _fallbacks("redis_error")
+    with patch.object(
+        dao.client, "get_locations_within_radius", side_effect=redis.ConnectionError("down")
+    ):
+        assert _ids(dao.get_nearby_venues(*CENTER, radius=1)) == ["near"]
+    assert _fallbacks("redis_error") - before == 1
+
+
+def test_dual_empty_area_with_populated_index_stays_empty(raw, store):
+    dao = _dao(raw, store)
+    dao.upsert_venue(_venue("elsewhere", lat=-3.7, lng=-38.5))
+    assert dao.get_nearby_venues(*CENTER, radius=2) == []
+
+
+def test_rds_mode_skips_redis(raw, store):
+    dao = _dao(raw, store, mode="rds")
+    dao.upsert_venue(_venue("redis_only"))
+    assert _ids(dao.get_nearby_venues(*CENTER, radius=2000, unit="m")) == ["near", "edge"]
+
+
+def test_rds_outage_propagates(raw, store):
+    store.set_unavailable(True)
+    with pytest.raises(Exception):
+        _dao(raw, store).get_nearby_venues(*CENTER, radius=2)
+
+
+def test_rejects_unknown_mode(raw, store):
+    with pytest.raises(ValueError):
+        _dao(raw, store, mode="redis")
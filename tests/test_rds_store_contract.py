@@ -142,6 +142,26 @@ def test_weekly_composite_key(store):
     assert rec is not None and rec["payload"]["day_int"] == 0
 
 
+def test_servable_rows_in_box(store):
+    """The nearby-fallback candidate reader: servable rows inside the box only,
+    in the list_all_venue_rows shape."""
+    inside, outside, gone = _vid(), _vid(), _vid()
+    store.upsert_venue(_venue(inside, "Boteco"))
+    store.upsert_venue(Venue(
+        venue_id=outside, venue_name="Far Bar", venue_address="a",
+        venue_lat=-9.5, venue_lng=-35.7, venue_type="BAR",
+    ))
+    store.upsert_venue(_venue(gone, "Closed Bar"))
+    store.soft_delete_venue(gone, "closed", "google_places")
+
+    rows = store.list_servable_venue_rows_in_box(-8.1, -8.0, -34.9, -34.8)
+    ids = {r["venue_id"] for r in rows}
+    assert inside in ids
+    assert outside not in ids and gone not in ids
+    row = next(r for r in rows if r["venue_id"] == inside)
+    assert float(row["venue_lat"]) == -8.05
+
+
 def test_bulk_venue_reader_matches_single_reader(store):
     """get_venues_by_ids (P1) must return the same row shape as get_venue for
     every id in the set, and simply omit ids with no row (no KeyError, no
@@ -0,0 +1,94 @@
+"""Unit tests for per-user recommendations (app/services/recommendations.py)
+and their engagement routes."""
+from datetime import datetime, timezone
+
+import fakeredis
+from fastapi import FastAPI
+from fastapi.testclient import TestClient
+
+from app.dao.redis_venue_dao import RedisVenueDAO
+from app.db.geo_redis_client import GeoRedisClient
+from app.models import LiveForecastResponse, Venue
+from app.routers.engagement_router import router as engagement_router, set_recommendation_service
+from app.services.recommendations import RecommendationService, UserHistoryStore, weight_errors
+
+_WEIGHTS = {"type": 0.35, "price": 0.15, "proximity": 0.2, "busyness": 0.3}
+
+
+def _venue(venue_id, lat, venue_type="BAR", price_level=2):
+    return Venue(
+        venue_id=venue_id, venue_name=venue_id, venue_lat=lat, venue_lng=-34.88,
+        venue_type=venue_type, price_level=price_level,
+    )
+
+
+def _service():
+    raw = fakeredis.FakeRedis(decode_responses=True)
+    dao = RedisVenueDAO(GeoRedisClient(raw))
+    for venue in (
+        _venue("fav", -8.05),
+        _venue("twin", -8.052),                         # same type and price, next door
+        _venue("club", -8.052, "CLUBS", 3),
+        _venue("far_twin", -8.09),                      # same kind, ~4.4 km away
+        _venue("elsewhere", -8.30),                     # outside the radius
+    ):
+        dao.upsert_venue(venue)
+    dao.set_live_forecast(LiveForecastResponse.model_validate({
+        "status": "OK",
+        "analysis": {"venue_live_busyness": 90, "venue_live_busyness_available": True},
+        "venue_info": {
+            "venue_id": "club", "venue_current_gmttime": datetime.now(timezone.utc).isoformat(),
+        },
+    }))
+    raw.sadd("user_favorites:u1", "fav")
+    history = UserHistoryStore(raw, max_visits=2)
+    return RecommendationService(dao, history, _WEIGHTS, radius_km=5.0), raw
+
+
+def test_visits_keep_the_newest():
+    service, raw = _service()
+    for at, venue_id in enumerate(("a", "b", "c")):
+        service.history.record_visit("u2", venue_id, at=at)
+
+    assert service.history.visits("u2") == ["c", "b"]
+    assert raw.ttl("user_visits:v1:u2") > 0
+    raw.sadd("user_favorites:u2", "b", "z")
+    assert service.history.history("u2") == ["c", "b", "z"]
+
+
+def test_ranks_similar_and_lively_venues_outside_the_history():
+    service, _ = _service()
+
+    recs = service.recommend("u1")
+
+    assert [r.venue_id for r in recs] == ["twin", "club", "far_twin"]
+    assert recs[0].similar_to == "fav"
+    assert recs[1].venue_live_busyness == 90
+    assert service.recommend("u1", limit=1)[0].venue_id == "twin"
+    assert service.recommend("nobody") == []
+
+
+def test_weight_validation():
+    assert weight_errors(_WEIGHTS) == []
+    assert weight_errors({"vibe": 1}) == ["recommendation_weights: unknown signal 'vibe'"]
+    assert weight_errors({"type": 0}) == ["recommendation_weights: at least one weight must be positive"]
+
+
+def test_routes():
+    app = FastAPI()
+    app.include_router(engagement_router)
+    client = TestClient(app)
+    set_recommendation_service(None)
+    assert client.get("/v1/users/u1/recommendations").status_code == 503
+
+    service, _ = _service()
+    set_recommendation_service(service)
+    try:
+        assert client.post("/v1/visits", json={"user_id": "u3", "venue_id": "club"}).json() == {"status": "ok"}
+        body = client.get("/v1/users/u3/recommendations", params={"lat": -8.05, "lon": -34.88}).json()
+        # Ranked by closeness to the given point; the visit itself is left out.
+        assert [r["venue_id"] for r in body] == ["fav", "twin", "far_twin"]
+        assert {r["similar_to"] for r in body} == {"club"}
+        assert client.get("/v1/users/u3/recommendations?lat=-8.05").status_code == 422
+    finally:
+        set_recommendation_service(None)
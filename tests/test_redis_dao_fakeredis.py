@@ -0,0 +1,114 @@
+"""RedisVenueDAO against fakeredis: key listing, radius edges, deletes and TTLs.
+
+Unlike the Mock-based unit tests these exercise real SCAN pattern matching,
+GEORADIUS distance filtering and key expiry, so they cover the DAO paths a
+Mock cannot (anything whose result depends on what Redis actually holds).
+"""
+import fakeredis
+import pytest
+
+from app.config import settings
+from app.dao import RedisVenueDAO
+from app.db.geo_redis_client import GeoRedisClient
+from app.models import Analysis, LiveForecastResponse, Venue, VenueInfo
+
+CENTER = (-8.0, -34.9)
+# Degrees of latitude per km on Redis' earth radius (6372797.560856 m).
+DEG_PER_KM = 1 / 111.2263
+
+
+@pytest.fixture
+def raw():
+    return fakeredis.FakeRedis(decode_responses=True)
+
+
+@pytest.fixture
+def dao(raw):
+    return RedisVenueDAO(GeoRedisClient(raw))
+
+
+def _venue(venue_id, km_north=0.0):
+    return Venue(
+        venue_id=venue_id,
+        venue_lat=CENTER[0] + km_north * DEG_PER_KM,
+        venue_lng=CENTER[1],
+    )
+
+
+def _live(venue_id):
+    return LiveForecastResponse(
+        status="OK",
+        analysis=Analysis(venue_live_busyness=40, venue_live_busyness_available=True),
+        venue_info=VenueInfo(venue_id=venue_id),
+    )
+
+
+class TestKeyListing:
+    def test_list_all_venue_ids_only_matches_venue_documents(self, dao, raw):
+        dao.upsert_venues([_venue("a"), _venue("b")])
+        raw.set("venues_geo_place_v2:c", "{}")  # another schema version
+        raw.set("live_forecast_v1:a", "{}")
+
+        assert sorted(dao.list_all_venue_ids()) == ["a", "b"]
+
+    def test_list_cached_live_forecast_venue_ids(self, dao):
+        assert dao.list_cached_live_forecast_venue_ids() == []
+        dao.set_live_forecast(_live("a"))
+        dao.set_live_forecast(_live("b"))
+        dao.delete_live_forecast("b")
+
+        assert dao.list_cached_live_forecast_venue_ids() == ["a"]
+
+    def test_ids_with_pattern_characters_are_listed_verbatim(self, dao):
+        dao.set_live_forecast(_live("ven_[1]*"))
+        assert dao.list_cached_live_forecast_venue_ids() == ["ven_[1]*"]
+
+
+class TestRadius:
+    def test_filters_by_distance(self, dao):
+        dao.upsert_venues([_venue("inside", 0.99), _venue("outside", 1.01), _venue("here")])
+
+        ids = {v.venue_id for v in dao.get_nearby_venues(*CENTER, radius=1)}
+
+        assert ids == {"inside", "here"}
+
+    def test_units_are_equivalent(self, dao):
+        dao.upsert_venues([_venue("near", 0.5), _venue("far", 2.0)])
+
+        km = {v.venue_id for v in dao.get_nearby_venues(*CENTER, radius=1)}
+        m = {v.venue_id for v in dao.get_nearby_venues(*CENTER, radius=1000, unit="m")}
+
+        assert km == m == {"near"}
+
+    def test_tiny_radius_matches_only_the_point_itself(self, dao):
+        # Not 0: the stored point is geohash-quantized (sub-meter offset).
+        dao.upsert_venues([_venue("here"), _venue("next_door", 0.05)])
+        assert [v.venue_id for v in dao.get_nearby_venues(*CENTER, radius=10, unit="m")] == ["here"]
+
+    def test_count_in_radius_agrees_with_query(self, dao):
+        dao.upsert_venues([_venue("a", 0.2), _venue("b", 0.8), _venue("c", 3)])
+        assert dao.count_venues_in_radius(*CENTER, radius_m=1000) == 2
+
+
+class TestDeleteAndTtl:
+    def test_delete_venue_removes_document_geo_member_and_forecasts(self, dao, raw):
+        dao.upsert_venue(_venue("a"))
+        dao.set_live_forecast(_live("a"))
+
+        assert dao.delete_venue("a") is True
+
+        assert raw.get("venues_geo_place_v1:a") is None
+        assert raw.zscore("venues_geo_v1", "venues_geo_place_v1:a") is None
+        assert dao.list_cached_live_forecast_venue_ids() == []
+        assert dao.delete_venue("a") is False
+
+    def test_ttl_keys_expire(self, dao, raw):
+        dao.set_venue_photos("a", [{"url": "https://x"}], ttl_seconds=120)
+        dao.set_venue_photos("b", [{"url": "https://x"}])
+        dao.set_partner_live(_live("a"), ttl_seconds=30)
+
+        assert raw.ttl("venue_photos_v1:a") == 120
+        # Without an explicit TTL: the configured one (no admin override set).
+        assert raw.ttl("venue_photos_v1:b") == settings.photo_cache_ttl_days * 24 * 3600
+        assert raw.ttl("partner_live_v1:a") == 30
+        assert raw.ttl("live_forecast_v1:a") == -2  # never written
@@ -0,0 +1,75 @@
+"""Unit tests for the Redis DAO hit/miss counters and latency histogram."""
+from unittest.mock import Mock
+
+import fakeredis
+import redis
+from prometheus_client import REGISTRY
+
+from app.dao.redis_venue_dao import RedisVenueDAO
+from app.db.geo_redis_client import GeoRedisClient
+from app.models import Analysis, LiveForecastResponse, VenueInfo, WeekRawDay
+
+
+def _lookups(entity: str, result: str) -> float:
+    return REGISTRY.get_sample_value(
+        "redis_dao_cache_lookups_total", {"entity": entity, "result": result}
+    ) or 0.0
+
+
+def _op_count(operation: str) -> float:
+    return REGISTRY.get_sample_value(
+        "redis_dao_operation_duration_seconds_count", {"operation": operation}
+    ) or 0.0
+
+
+def _dao():
+    return RedisVenueDAO(GeoRedisClient(fakeredis.FakeRedis(decode_responses=True)))
+
+
+def _live(vid: str) -> LiveForecastResponse:
+    return LiveForecastResponse(status="OK", analysis=Analysis(), venue_info=VenueInfo(venue_id=vid))
+
+
+class TestForecastLookups:
+    def test_live_hit_and_miss(self):
+        dao = _dao()
+        dao.set_live_forecast(_live("v1"))
+        hit, miss = _lookups("live_forecast", "hit"), _lookups("live_forecast", "miss")
+        ops = _op_count("get_live_forecast")
+
+        dao.get_live_forecast("v1")
+        dao.get_live_forecast("absent")
+
+        assert _lookups("live_forecast", "hit") == hit + 1
+        assert _lookups("live_forecast", "miss") == miss + 1
+        assert _op_count("get_live_forecast") == ops + 2
+
+    def test_bulk_counts_each_id(self):
+        dao = _dao()
+        dao.set_live_forecast(_live("v1"))
+        hit, miss = _lookups("live_forecast", "hit"), _lookups("live_forecast", "miss")
+
+        dao.get_live_forecasts_bulk(["v1", "v2", "v3"])
+
+        assert _lookups("live_forecast", "hit") == hit + 1
+        assert _lookups("live_forecast", "miss") == miss + 2
+
+    def test_weekly_hit_and_miss(self):
+        dao = _dao()
+        dao.set_week_raw_forecast("v1", WeekRawDay(day_int=2, day_raw=[0] * 24))
+        hit, miss = _lookups("weekly_forecast", "hit"), _lookups("weekly_forecast", "miss")
+
+        dao.get_week_raw_forecast("v1", 2)
+        dao.get_week_raw_forecasts_bulk(["v1", "v2"], 2)
+
+        assert _lookups("weekly_forecast", "hit") == hit + 2
+        assert _lookups("weekly_forecast", "miss") == miss + 1
+
+    def test_redis_error_counts_as_error(self):
+        client = Mock()
+        client.get.side_effect = redis.ConnectionError("down")
+        dao = RedisVenueDAO(client)
+        errors = _lookups("live_forecast", "error")
+
+        assert dao.get_live_forecast("v1") is None
+        assert _lookups("live_forecast", "error") == errors + 1
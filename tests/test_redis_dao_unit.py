@@ -2,6 +2,7 @@
 import pytest
 from unittest.mock import Mock, MagicMock
 from app.dao import RedisVenueDAO
+from app.dao.venue_codec import decode_venue, encode_venue
 from app.models import Venue, LiveForecastResponse, VenueInfo, Analysis, WeekRawDay
 
 
@@ -37,7 +38,7 @@ class TestRedisVenueDAOUnit:
         assert call_args.kwargs["member_key"] == "venues_geo_place_v1:test_123"
         assert call_args.kwargs["lat"] == -8.07834
         assert call_args.kwargs["lon"] == -34.90938
-        assert call_args.kwargs["data"] == venue
+        assert call_args.kwargs["data"] == encode_venue(venue)
 
     def test_get_nearby_venues(self, venue_dao, mock_redis_client):
         """Test get_nearby_venues deserializes venues correctly."""
@@ -98,7 +99,7 @@ class TestRedisVenueDAOUnit:
         assert result is True
         mock_redis_client.zrem.assert_not_called()
         mock_redis_client.del_.assert_not_called()
-        stored = mock_redis_client.add_location_with_json.call_args.kwargs["data"]
+        stored = decode_venue(mock_redis_client.add_location_with_json.call_args.kwargs["data"])
         assert stored.lifecycle_status == "deprecated"
         assert stored.deprecated_reason == "google_places_closed_permanently"
         assert stored.deprecated_source == "google_places"
@@ -128,7 +129,7 @@ class TestRedisVenueDAOUnit:
             )
         )
 
-        stored = mock_redis_client.add_location_with_json.call_args.kwargs["data"]
+        stored = decode_venue(mock_redis_client.add_location_with_json.call_args.kwargs["data"])
         assert stored.lifecycle_status == "deprecated"
         assert stored.deprecated_reason == "google_places_closed_permanently"
         assert stored.google_business_status == "CLOSED_PERMANENTLY"
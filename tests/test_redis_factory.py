@@ -0,0 +1,146 @@
+"""Unit tests for the Redis topology factory, pool tuning and cluster-mode adaptations."""
+from types import SimpleNamespace
+from unittest.mock import MagicMock, patch
+
+import pytest
+import redis
+from redis.cluster import RedisCluster
+
+from app.db import redis_factory
+from app.db.geo_redis_client import GeoRedisClient
+from app.db.redis_factory import (
+    build_redis_client,
+    connection_kwargs,
+    parse_nodes,
+    prewarm_pool,
+)
+
+
+def _settings(**overrides):
+    base = dict(
+        redis_mode="standalone",
+        redis_host="redis",
+        redis_port=6379,
+        redis_password="",
+        redis_db=2,
+        redis_sentinel_nodes=[],
+        redis_sentinel_master="mymaster",
+        redis_sentinel_password="",
+        redis_cluster_nodes=[],
+        redis_max_connections=20,
+        redis_min_idle_connections=0,
+        redis_connect_timeout_seconds=1.5,
+        redis_socket_timeout_seconds=2.5,
+        redis_health_check_interval_seconds=30,
+        redis_retry_attempts=4,
+        redis_retry_backoff_base_seconds=0.01,
+        redis_retry_backoff_cap_seconds=0.5,
+    )
+    base.update(overrides)
+    return SimpleNamespace(**base)
+
+
+class TestParseNodes:
+    def test_host_port_and_default_port(self):
+        assert parse_nodes(["a:1", " b "], 26379) == [("a", 1), ("b", 26379)]
+
+    @pytest.mark.parametrize("nodes", [[], ["a:x"], [":1"]])
+    def test_rejects_bad_input(self, nodes):
+        with pytest.raises(ValueError):
+            parse_nodes(nodes, 6379)
+
+
+class TestBuildRedisClient:
+    def test_standalone(self):
+        with patch.object(redis_factory.redis, "Redis") as ctor:
+            build_redis_client(_settings(redis_password="pw"))
+        kwargs = ctor.call_args.kwargs
+        assert (kwargs["host"], kwargs["port"], kwargs["password"], kwargs["db"]) == (
+            "redis", 6379, "pw", 2
+        )
+        assert kwargs["decode_responses"] is True
+
+    def test_sentinel_uses_master_for(self):
+        with patch.object(redis_factory, "Sentinel") as sentinel_cls:
+            client = build_redis_client(_settings(
+                redis_mode="sentinel", redis_sentinel_nodes=["s1:26379", "s2"],
+            ))
+        assert sentinel_cls.call_args[0][0] == [("s1", 26379), ("s2", 26379)]
+        master_for = sentinel_cls.return_value.master_for
+        assert master_for.call_args[0] == ("mymaster",)
+        assert master_for.call_args.kwargs["password"] is None
+        assert master_for.call_args.kwargs["db"] == 2
+        assert client is sentinel_cls.return_value.master_for.return_value
+
+    def test_cluster_seeds_startup_nodes(self):
+        with patch.object(redis_factory, "RedisCluster") as cluster_cls:
+            build_redis_client(_settings(
+                redis_mode="cluster", redis_cluster_nodes=["c1:7000", "c2:7001"],
+            ))
+        nodes = cluster_cls.call_args.kwargs["startup_nodes"]
+        assert [(n.host, n.port) for n in nodes] == [("c1", 7000), ("c2", 7001)]
+
+    def test_missing_nodes_and_unknown_mode_fail(self):
+        with pytest.raises(ValueError):
+            build_redis_client(_settings(redis_mode="sentinel"))
+        with pytest.raises(ValueError):
+            build_redis_client(_settings(redis_mode="replica"))
+
+
+class TestPoolTuning:
+    def test_every_mode_gets_pool_and_timeouts(self):
+        kwargs = connection_kwargs(_settings())
+        assert kwargs["max_connections"] == 20
+        assert kwargs["socket_connect_timeout"] == 1.5
+        assert kwargs["socket_timeout"] == 2.5
+        assert kwargs["retry"]._retries == 4
+
+        with patch.object(redis_factory, "RedisCluster") as cluster_cls:
+            build_redis_client(_settings(redis_mode="cluster", redis_cluster_nodes=["c1"]))
+        assert cluster_cls.call_args.kwargs["socket_timeout"] == 2.5
+
+    def test_prewarm_opens_and_releases(self):
+        raw = MagicMock()
+        assert prewarm_pool(raw, 3) == 3
+        assert raw.connection_pool.get_connection.call_count == 3
+        assert raw.connection_pool.release.call_count == 3
+
+    def test_prewarm_stops_on_error_and_skips_cluster(self):
+        raw = MagicMock()
+        raw.connection_pool.get_connection.side_effect = [
+            MagicMock(), redis.ConnectionError("Too many connections"),
+        ]
+        assert prewarm_pool(raw, 5) == 1
+        raw.connection_pool.release.assert_called_once()
+
+        assert prewarm_pool(MagicMock(spec=RedisCluster), 5) == 0
+
+
+class TestClusterAdaptations:
+    def _cluster_client(self):
+        raw = MagicMock(spec=RedisCluster)
+        pipe = MagicMock()
+        raw.pipeline.return_value = pipe
+        return GeoRedisClient(raw), raw, pipe
+
+    def test_writes_use_a_plain_pipeline(self):
+        client, raw, pipe = self._cluster_client()
+
+        client.add_location_with_json("geo", "member:1", -8.0, -34.9, {"a": 1})
+
+        raw.pipeline.assert_called_once_with()
+        pipe.execute.assert_called_once()
+
+    def test_mget_is_split_per_slot(self):
+        client, raw, _ = self._cluster_client()
+        raw.mget_nonatomic.return_value = ["1", None]
+
+        assert client.mget(["a", "b"]) == ["1", None]
+        raw.mget.assert_not_called()
+
+    def test_standalone_keeps_transactions(self):
+        raw = MagicMock()
+        client = GeoRedisClient(raw)
+        assert not client.is_cluster
+        client.mget(["a"])
+        raw.mget.assert_called_once_with(["a"])
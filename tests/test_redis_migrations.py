@@ -0,0 +1,123 @@
+"""Unit tests for the versioned Redis key-schema migrations. fakeredis only."""
+from types import SimpleNamespace
+
+import fakeredis
+import pytest
+from fastapi import FastAPI
+from fastapi.testclient import TestClient
+
+from app.dao import redis_migrations
+from app.dao.redis_migrations import (
+    MIGRATION_LOCK_KEY,
+    SCHEMA_VERSION_KEY,
+    Migration,
+    MigrationInProgressError,
+    migrate,
+    move_geo_index,
+    rename_key_family,
+)
+from app.db.geo_redis_client import GeoRedisClient
+from app.routers.admin_trigger_router import router, set_container
+
+
+def _fake():
+    return fakeredis.FakeRedis(decode_responses=True)
+
+
+class TestMigrate:
+    def test_fresh_redis_is_stamped_with_baseline(self):
+        r = _fake()
+        result = migrate(r)
+        assert result["from"] == 0 and result["to"] == 1
+        assert [a["name"] for a in result["applied"]] == ["baseline_v1_layouts"]
+        assert r.get(SCHEMA_VERSION_KEY) == "1"
+
+    def test_rerun_applies_nothing(self):
+        r = _fake()
+        migrate(r)
+        assert migrate(r)["applied"] == []
+
+    def test_applies_pending_in_order_up_to_target(self):
+        r = _fake()
+        calls = []
+        steps = [
+            Migration(3, "c", lambda c: calls.append(3) or 0),
+            Migration(1, "a", lambda c: calls.append(1) or 0),
+            Migration(2, "b", lambda c: calls.append(2) or 0),
+        ]
+        assert migrate(r, steps, target=2)["to"] == 2
+        assert migrate(r, steps)["to"] == 3
+        assert calls == [1, 2, 3]
+
+    def test_failure_stamps_completed_steps_and_releases_lock(self):
+        r = _fake()
+
+        def boom(c):
+            raise RuntimeError("bad step")
+
+        with pytest.raises(RuntimeError):
+            migrate(r, [Migration(1, "ok", lambda c: 0), Migration(2, "bad", boom)])
+        assert r.get(SCHEMA_VERSION_KEY) == "1"
+        assert r.get(MIGRATION_LOCK_KEY) is None
+
+    def test_lock_taken_over_after_expiry_is_not_released(self):
+        r = _fake()
+
+        def slow(c):
+            # Outlived the TTL: the lock expired and another replica took it.
+            c.set(MIGRATION_LOCK_KEY, "other-replica")
+            return 0
+
+        migrate(r, [Migration(1, "slow", slow)])
+        assert r.get(MIGRATION_LOCK_KEY) == "other-replica"
+
+    def test_held_lock_rejects_concurrent_run(self):
+        r = _fake()
+        r.set(MIGRATION_LOCK_KEY, "1")
+        with pytest.raises(MigrationInProgressError):
+            migrate(r)
+        assert r.get(SCHEMA_VERSION_KEY) is None
+
+
+class TestSteps:
+    def test_rename_key_family_keeps_ttl_and_never_clobbers(self):
+        r = _fake()
+        r.set("live_forecast_v1:a", "old-a", ex=100)
+        r.set("live_forecast_v1:b", "old-b")
+        r.set("live_forecast_v2:b", "new-b")
+
+        assert rename_key_family(r, "live_forecast_v1:", "live_forecast_v2:") == 1
+        assert r.get("live_forecast_v2:a") == "old-a" and 0 < r.ttl("live_forecast_v2:a") <= 100
+        assert r.get("live_forecast_v2:b") == "new-b"
+
+    def test_move_geo_index_renames_members_and_json(self):
+        r = _fake()
+        geo = GeoRedisClient(r)
+        geo.add_location_with_json("venues_geo_v1", "venues_geo_place_v1:x", -8.05, -34.88, {"id": "x"})
+
+        assert move_geo_index(
+            r, "venues_geo_v1", "venues_geo_v2", "venues_geo_place_v1:", "venues_geo_place_v2:"
+        ) == 1
+        assert not r.exists("venues_geo_v1")
+        assert geo.get_locations_within_radius("venues_geo_v2", -8.05, -34.88, 1.0) == ['{"id": "x"}']
+
+
+class TestAdminEndpoints:
+    def _client(self, r):
+        app = FastAPI()
+        app.include_router(router)
+        set_container(SimpleNamespace(redis_client=GeoRedisClient(r)))
+        return TestClient(app)
+
+    def test_schema_and_migrate(self):
+        r = _fake()
+        client = self._client(r)
+        latest = redis_migrations.latest_version()
+        assert client.get("/admin/redis/schema").json() == {"version": 0, "latest": latest}
+        assert client.post("/admin/redis/migrate").json()["to"] == latest
+        assert client.get("/admin/redis/schema").json()["version"] == latest
+
+    def test_migrate_returns_409_while_locked(self):
+        r = _fake()
+        r.set(MIGRATION_LOCK_KEY, "1")
+        assert self._client(r).post("/admin/redis/migrate").status_code == 409
@@ -143,3 +143,18 @@ def test_run_counts_success():
     before = counter._value.get()
     asyncio.run(watcher.run())
     assert counter._value.get() == before + 1
+
+
+def test_holiday_interval_applies_without_admin_override():
+    redis = FakeRedis(None)
+    watcher = RefreshIntervalWatcher(
+        redis_client=redis, scheduler=FakeScheduler(), default_minutes=5,
+        holiday_minutes=lambda: 2,
+    )
+    watcher.check_once()
+    assert watcher.applied_minutes == 2
+
+    # An admin override still wins on holidays.
+    redis.value = "10"
+    watcher.check_once()
+    assert watcher.applied_minutes == 10
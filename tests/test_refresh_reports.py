@@ -0,0 +1,88 @@
+"""Unit tests for refresh run reports (app/services/refresh_reports.py)."""
+import asyncio
+from unittest.mock import AsyncMock, Mock
+
+import fakeredis
+import pytest
+
+from app.models import VenueFilterResponse, VenueFilterVenue
+from app.services.refresh_reports import (
+    RefreshReportStore,
+    current_report,
+    note,
+    note_error,
+    reported,
+)
+from app.services.venues_refresher_service import VenuesRefresherService
+
+
+class FakeRefresher:
+    def __init__(self, redis_client):
+        self.run_reports = RefreshReportStore(redis_client, max_entries=3)
+
+    @reported("live_forecast")
+    async def refresh(self, cached=0, errors=(), fail=False):
+        async def worker(vid):
+            note(live_cached=1)
+
+        await asyncio.gather(*(worker(i) for i in range(cached)))
+        for message in errors:
+            note_error(message)
+        if fail:
+            raise RuntimeError("selection failed")
+
+
+@pytest.mark.asyncio
+async def test_reports_count_across_workers_and_derive_status():
+    redis_client = fakeredis.FakeRedis(decode_responses=True)
+    refresher = FakeRefresher(redis_client)
+
+    await refresher.refresh(cached=3)
+    await refresher.refresh(cached=1, errors=["v9: timeout"])
+    with pytest.raises(RuntimeError):
+        await refresher.refresh(fail=True)
+
+    failed, partial, ok = refresher.run_reports.history()
+    assert (ok["status"], ok["live_cached"], ok["errors"]) == ("ok", 3, 0)
+    assert (partial["status"], partial["error_samples"]) == ("partial", ["v9: timeout"])
+    assert failed["status"] == "failed" and "selection failed" in failed["error_samples"][0]
+    assert ok["finished_at"] >= ok["started_at"]
+    assert current_report() is None
+
+
+@pytest.mark.asyncio
+async def test_history_is_capped_and_filters_by_job():
+    redis_client = fakeredis.FakeRedis(decode_responses=True)
+    refresher = FakeRefresher(redis_client)
+    for _ in range(5):
+        await refresher.refresh()
+
+    assert len(refresher.run_reports.history()) == 3
+    assert refresher.run_reports.history(job="weekly_forecast") == []
+    assert len(refresher.run_reports.history(job="live_forecast", limit=2)) == 2
+
+
+@pytest.mark.asyncio
+async def test_catalog_refresh_reports_locations_found_and_upserted():
+    redis_client = fakeredis.FakeRedis(decode_responses=True)
+    api = Mock()
+    api.venue_filter = AsyncMock(return_value=VenueFilterResponse(
+        status="OK", venues_n=2,
+        venues=[
+            VenueFilterVenue(venue_id=f"v{i}", venue_name=f"Bar {i}", venue_address="a",
+                             venue_lat=-8.05, venue_lng=-34.88, day_int=0, day_raw=[0] * 24)
+            for i in range(2)
+        ],
+    ))
+    dao = Mock()
+    dao.get_venue.return_value = None
+    refresher = VenuesRefresherService(dao, api, redis_client=redis_client)
+    refresher.sync_account_inventory_to_redis = AsyncMock(return_value={})
+    refresher.update_data_quality_metrics = Mock()
+
+    await refresher.refresh_venues_by_filter_for_default_locations()
+
+    report = refresher.run_reports.history(job="venue_catalog")[0]
+    # Three default locations, each returning the same two venues.
+    assert (report["locations"], report["venues_found"], report["upserted"]) == (3, 6, 6)
+    assert report["status"] == "ok"
@@ -0,0 +1,111 @@
+"""Unit tests for refreshed_at stamping, data_age_seconds and the live cooldown."""
+from datetime import datetime, timedelta, timezone
+
+import fakeredis
+import pytest
+
+from app.dao.redis_venue_dao import RedisVenueDAO
+from app.dao.venue_row import split_venue_for_storage, venue_from_row
+from app.db.geo_redis_client import GeoRedisClient
+from app.handlers import VenueHandler
+from app.models import Analysis, LiveForecastResponse, Venue, VenueInfo
+from app.services.venues_refresher_service import VenuesRefresherService
+
+_LAT, _LNG = -8.05, -34.88
+
+
+def _venue(vid, refreshed_at=None):
+    return Venue(venue_id=vid, venue_name=f"Bar {vid}", venue_address="a",
+                 venue_lat=_LAT, venue_lng=_LNG, refreshed_at=refreshed_at)
+
+
+def _live(vid, busyness=50, refreshed_at=None):
+    return LiveForecastResponse(
+        status="OK",
+        venue_info=VenueInfo(venue_id=vid),
+        analysis=Analysis(venue_live_busyness=busyness, venue_live_busyness_available=True),
+        refreshed_at=refreshed_at,
+    )
+
+
+class _LiveBesttime:
+    def __init__(self):
+        self.live_calls = []
+
+    async def get_live_forecast(self, venue_id):
+        self.live_calls.append(venue_id)
+        return _live(venue_id, busyness=80)
+
+
+def _dao():
+    return RedisVenueDAO(GeoRedisClient(fakeredis.FakeRedis(decode_responses=True)))
+
+
+class TestStamping:
+    @pytest.mark.asyncio
+    async def test_live_refresh_stamps_refreshed_at(self):
+        dao = _dao()
+        dao.upsert_venue(_venue("v1"))
+        refresher = VenuesRefresherService(venue_dao=dao, besttime_api=_LiveBesttime())
+
+        before = datetime.now(timezone.utc)
+        await refresher.refresh_live_forecasts_for_all_venues()
+
+        assert dao.get_live_forecast("v1").refreshed_at >= before
+
+    def test_venue_refreshed_at_survives_the_rds_row_roundtrip(self):
+        stamp = datetime(2026, 10, 16, 12, 0, tzinfo=timezone.utc)
+        columns, residual = split_venue_for_storage(_venue("v1", refreshed_at=stamp))
+
+        assert venue_from_row({**columns, "extra": residual}).refreshed_at == stamp
+
+
+class TestCooldown:
+    def _setup(self, live_age_seconds, cooldown):
+        dao = _dao()
+        dao.upsert_venues([_venue("recent"), _venue("old"), _venue("legacy")])
+        now = datetime.now(timezone.utc)
+        dao.set_live_forecast(_live("recent", refreshed_at=now - timedelta(seconds=live_age_seconds)))
+        dao.set_live_forecast(_live("old", refreshed_at=now - timedelta(hours=1)))
+        dao.set_live_forecast(_live("legacy"))
+        besttime = _LiveBesttime()
+        refresher = VenuesRefresherService(
+            venue_dao=dao, besttime_api=besttime, live_refresh_cooldown_seconds=cooldown,
+        )
+        return refresher, besttime
+
+    @pytest.mark.asyncio
+    async def test_skips_venues_refreshed_within_cooldown(self):
+        refresher, besttime = self._setup(live_age_seconds=30, cooldown=300)
+
+        await refresher.refresh_live_forecasts_for_all_venues()
+
+        assert sorted(besttime.live_calls) == ["legacy", "old"]
+
+    @pytest.mark.asyncio
+    async def test_zero_cooldown_refreshes_everything(self):
+        refresher, besttime = self._setup(live_age_seconds=30, cooldown=0)
+
+        await refresher.refresh_live_forecasts_for_all_venues()
+
+        assert sorted(besttime.live_calls) == ["legacy", "old", "recent"]
+
+
+class TestDataAge:
+    def test_uses_newest_of_venue_and_live_stamps(self):
+        now = datetime.now(timezone.utc)
+        dao = _dao()
+        dao.upsert_venue(_venue("v1", refreshed_at=now - timedelta(days=3)))
+        dao.set_live_forecast(_live("v1", refreshed_at=now - timedelta(minutes=2)))
+
+        [item] = VenueHandler(dao).get_venues_nearby(_LAT, _LNG, 1.0)
+
+        assert 115 <= item.data_age_seconds <= 125
+
+    def test_none_without_any_stamp(self):
+        dao = _dao()
+        dao.upsert_venue(_venue("v1"))
+
+        [item] = VenueHandler(dao).get_venues_nearby(_LAT, _LNG, 1.0, verbose=True)
+
+        assert item.data_age_seconds is None
@@ -0,0 +1,147 @@
+"""Region profiles (app/services/regions.py) and GET /v1/regions."""
+import importlib
+from types import SimpleNamespace
+from unittest.mock import AsyncMock, Mock
+
+import fakeredis
+import pytest
+from fastapi import FastAPI
+from fastapi.testclient import TestClient
+
+from app.models import VenueFilterResponse
+from app.services.instagram_enrichment_service import InstagramEnrichmentService
+from app.services.regions import RegionRegistry
+from app.services.venue_eligibility import default_geo_fence, validate_geo_fence
+from app.services.venues_refresher_service import VenuesRefresherService
+
+venue_router = importlib.import_module("app.routers.venue_router")
+
+RECIFE = {
+    "id": "recife",
+    "name": "Recife",
+    "points": [{"id": "centro", "lat": -8.06, "lng": -34.88, "radius": 10000, "limit": 100}],
+    "cities": ["olinda"],
+}
+NATAL = {
+    "id": "natal",
+    "name": "Natal",
+    "bbox": [-5.9, -35.3, -5.7, -35.1],
+    "points": [{"id": "ponta-negra", "lat": -5.88, "lng": -35.17, "radius": 5000}],
+    "catalog_refresh_minutes": 60,
+}
+
+
+def test_bounds_and_validation():
+    registry = RegionRegistry([RECIFE, NATAL])
+
+    min_lat, min_lng, max_lat, max_lng = registry.regions["recife"].bounds()
+    assert min_lat < -8.06 < max_lat and min_lng < -34.88 < max_lng
+    assert round(max_lat - min_lat, 2) == 0.18  # 2 x 10 km
+    assert registry.region_for(-5.8, -35.2).id == "natal"
+    assert registry.region_for(0, 0) is None
+    with pytest.raises(ValueError, match="needs a bbox or points"):
+        RegionRegistry([{"id": "x", "name": "X"}])
+    with pytest.raises(ValueError, match="duplicate region id natal"):
+        RegionRegistry([NATAL, NATAL])
+
+
+def test_regions_are_discovered_on_their_own_schedule():
+    now = [0.0]
+    registry = RegionRegistry([RECIFE, NATAL], clock=lambda: now[0])
+
+    assert [r.id for r in registry.due()] == ["recife", "natal"]
+    registry.mark_discovered(["recife", "natal"])
+    now[0] = 30 * 60
+    assert [r.id for r in registry.due()] == ["recife"]
+    now[0] = 60 * 60
+    assert [r.id for r in registry.due()] == ["recife", "natal"]
+
+
+def test_discovery_times_are_shared_through_redis():
+    redis_client = fakeredis.FakeRedis(decode_responses=True)
+    replica_a = RegionRegistry([NATAL], clock=lambda: 1000.0)
+    replica_a.set_redis(redis_client)
+    replica_a.mark_discovered(["natal"])
+
+    replica_b = RegionRegistry([NATAL], clock=lambda: 1000.0 + 30 * 60)
+    replica_b.set_redis(redis_client)
+
+    assert replica_b.due() == []
+
+
+def test_regions_must_lie_inside_the_geo_fence():
+    registry = RegionRegistry([RECIFE, NATAL])
+
+    assert registry.outside_fence(default_geo_fence()) == ["natal"]
+    assert registry.outside_fence(validate_geo_fence({"enabled": True, "cities": [
+        {"slug": "recife", "radius_km": 40}, {"slug": "natal", "radius_km": 40},
+    ]})) == []
+    assert registry.outside_fence({"enabled": False, "cities": []}) == []
+
+
+@pytest.mark.asyncio
+async def test_discovery_queries_the_due_regions_points():
+    api = Mock()
+    api.venue_filter = AsyncMock(return_value=VenueFilterResponse(status="OK", venues_n=0, venues=[]))
+    dao = Mock()
+    dao.list_all_venues.return_value = []
+    refresher = VenuesRefresherService(
+        dao, api, redis_client=fakeredis.FakeRedis(decode_responses=True), dev_mode=False
+    )
+    refresher.sync_account_inventory_to_redis = AsyncMock(return_value={})
+    refresher.update_data_quality_metrics = Mock()
+    refresher.set_regions(RegionRegistry([RECIFE, NATAL]))
+
+    assert refresher.active_locations()[0] == "regions"
+    first = await refresher.refresh_venues_by_filter_for_default_locations()
+    second = await refresher.refresh_venues_by_filter_for_default_locations()
+
+    assert [s.region for s in first] == ["recife/centro", "natal/ponta-negra"]
+    assert [s.region for s in second] == ["recife/centro"]
+    assert sorted(call[0][0].lat for call in api.venue_filter.call_args_list) == [-8.06, -8.06, -5.88]
+
+
+@pytest.mark.asyncio
+async def test_a_failed_region_stays_due():
+    api = Mock()
+    api.venue_filter = AsyncMock(side_effect=RuntimeError("BestTime down"))
+    dao = Mock()
+    dao.list_all_venues.return_value = []
+    refresher = VenuesRefresherService(
+        dao, api, redis_client=fakeredis.FakeRedis(decode_responses=True), dev_mode=False
+    )
+    refresher.sync_account_inventory_to_redis = AsyncMock(return_value={})
+    refresher.update_data_quality_metrics = Mock()
+    refresher.set_regions(RegionRegistry([NATAL]))
+
+    summaries = await refresher.refresh_venues_by_filter_for_default_locations()
+
+    assert summaries[0].error is not None
+    assert [r.id for r in refresher.regions.due()] == ["natal"]
+
+
+def test_search_city_follows_the_venue_region():
+    service = InstagramEnrichmentService(Mock(), Mock(), regions=RegionRegistry([RECIFE, NATAL]))
+
+    def venue(lat, lng, address=""):
+        return SimpleNamespace(venue_lat=lat, venue_lng=lng, venue_address=address)
+
+    assert service._search_city(venue(-5.8, -35.2, "Av. Roberto Freire")) == "Natal"
+    assert service._search_city(venue(-8.0, -34.85, "Rua do Amparo, Olinda")) == "Olinda"
+    assert service._search_city(venue(0, 0, "Somewhere")) == "Recife"  # no region: default
+
+
+def test_regions_endpoint():
+    app = FastAPI()
+    app.include_router(venue_router.router)
+    client = TestClient(app)
+
+    venue_router.set_regions(None)
+    assert client.get("/v1/regions").json() == []
+    venue_router.set_regions(RegionRegistry([NATAL]))
+    assert client.get("/v1/regions").json() == [{
+        "id": "natal",
+        "name": "Natal",
+        "bbox": [-5.9, -35.3, -5.7, -35.1],
+        "points": [{"lat": -5.88, "lng": -35.17, "radius": 5000}],
+    }]
@@ -0,0 +1,132 @@
+"""Unit tests for the retry queue of failed refresh work (app/services/retry_queue.py)."""
+import time
+from datetime import datetime, timedelta, timezone
+from unittest.mock import Mock
+
+import fakeredis
+import pytest
+
+from app.dao.redis_venue_dao import RedisVenueDAO
+from app.db.geo_redis_client import GeoRedisClient
+from app.models import Analysis, LiveForecastResponse, Venue, VenueInfo
+from app.services.retry_queue import LIVE_FORECAST, UPSERT_VENUE, RetryQueue
+from app.services.venues_refresher_service import VenuesRefresherService
+
+
+def _live(vid, refreshed_at=None):
+    return LiveForecastResponse(
+        status="OK",
+        venue_info=VenueInfo(venue_id=vid),
+        analysis=Analysis(venue_live_busyness=60, venue_live_busyness_available=True),
+        refreshed_at=refreshed_at,
+    )
+
+
+class _FlakyBesttime:
+    """Fails each venue's first `failures` live fetches, then answers."""
+
+    def __init__(self, failures=1):
+        self.failures = failures
+        self.calls = []
+
+    async def get_live_forecast(self, venue_id):
+        self.calls.append(venue_id)
+        if self.calls.count(venue_id) <= self.failures:
+            raise RuntimeError("read timeout")
+        return _live(venue_id)
+
+
+def _setup(besttime, **queue_kwargs):
+    redis_client = fakeredis.FakeRedis(decode_responses=True)
+    dao = RedisVenueDAO(GeoRedisClient(redis_client))
+    dao.upsert_venue(Venue(venue_id="v1", venue_name="Bar 1", venue_lat=-8.05, venue_lng=-34.88))
+    queue = RetryQueue(redis_client, **{"backoff_seconds": 0, **queue_kwargs})
+    refresher = VenuesRefresherService(venue_dao=dao, besttime_api=besttime)
+    refresher.set_retry_queue(queue)
+    return dao, queue, refresher
+
+
+def test_backoff_doubles_and_dead_letters_after_max_attempts():
+    queue = RetryQueue(fakeredis.FakeRedis(decode_responses=True),
+                       max_attempts=2, backoff_seconds=60, max_backoff_seconds=100)
+    queue.push(LIVE_FORECAST, "v1", "timeout")
+    queue.push(LIVE_FORECAST, "v1", "timeout again")  # already queued: no reset
+
+    assert queue.due() == []
+    [item] = queue.due(now=time.time() + 61)
+    assert (item.attempts, item.last_error) == (0, "timeout again")
+    assert queue.backoff(1) == 100  # 120 capped
+
+    assert queue.failed(item, "still failing") is False
+    assert queue.stats()["queued"] == 1
+    assert queue.failed(item, "gone") is True
+    assert queue.stats() == {"queued": 0, "due": 0, "dead": 1}
+    assert queue.dead_letters()[0]["last_error"] == "gone"
+
+    assert queue.requeue_dead() == 1
+    [again] = queue.due()
+    assert again.attempts == 0
+
+
+@pytest.mark.asyncio
+async def test_failed_live_fetch_is_queued_and_retried():
+    besttime = _FlakyBesttime(failures=1)
+    dao, queue, refresher = _setup(besttime)
+
+    await refresher.refresh_live_forecasts_for_all_venues()
+    assert dao.get_live_forecast("v1") is None
+    assert queue.stats()["queued"] == 1
+
+    summary = await refresher.process_retry_queue()
+
+    assert (summary["retried"], summary["succeeded"]) == (1, 1)
+    assert dao.get_live_forecast("v1") is not None
+    assert queue.stats()["queued"] == 0
+
+
+@pytest.mark.asyncio
+async def test_retry_failures_count_attempts_until_dead():
+    _, queue, refresher = _setup(_FlakyBesttime(failures=10), max_attempts=2)
+    queue.push(LIVE_FORECAST, "v1", "timeout")
+
+    assert (await refresher.process_retry_queue())["failed"] == 1
+    # The second retry is delayed by the (zero) backoff only.
+    assert (await refresher.process_retry_queue())["dead"] == 1
+    assert queue.stats()["dead"] == 1
+
+
+@pytest.mark.asyncio
+async def test_upsert_retry_and_obsolete_items():
+    besttime = _FlakyBesttime(failures=0)
+    dao, queue, refresher = _setup(besttime)
+    venue = Venue(venue_id="v2", venue_name="Bar 2", venue_lat=-8.06, venue_lng=-34.89)
+    queue.push(UPSERT_VENUE, "v2", "connection reset", payload=venue.model_dump(mode="json", by_alias=True))
+    queue.push(LIVE_FORECAST, "v1", "timeout")
+    # A regular refresh cached v1 after the failure: no retry needed.
+    dao.set_live_forecast(_live("v1", refreshed_at=datetime.now(timezone.utc) + timedelta(seconds=1)))
+
+    summary = await refresher.process_retry_queue()
+
+    assert (summary["succeeded"], summary["obsolete"]) == (1, 1)
+    assert dao.get_venue("v2").venue_name == "Bar 2"
+    assert besttime.calls == []
+
+
+@pytest.mark.asyncio
+async def test_a_ledger_refused_retry_is_postponed_not_dropped():
+    besttime = _FlakyBesttime(failures=0)
+    _, queue, refresher = _setup(besttime, backoff_seconds=60)
+    budget = Mock()
+    budget.try_register_touch.return_value = False
+    refresher.set_budget_service(budget)
+    queue.push(LIVE_FORECAST, "v1", "timeout")
+    [item] = queue.due(now=time.time() + 61)
+    queue.postpone(item, 0)
+
+    summary = await refresher.process_retry_queue()
+
+    assert (summary["postponed"], summary["succeeded"]) == (1, 0)
+    assert besttime.calls == []
+    assert queue.stats()["queued"] == 1 and queue.due() == []
+    [later] = queue.due(now=time.time() + 61)
+    assert later.attempts == 0
@@ -0,0 +1,161 @@
+"""Unit tests for settings hot reload (app/services/runtime_config.py)."""
+import json
+import os
+from types import SimpleNamespace
+
+import fakeredis
+import pytest
+from fastapi import FastAPI
+from fastapi.testclient import TestClient
+
+from app.config import Settings
+from app.routers.admin_trigger_router import router, set_container
+from app.services.runtime_config import OVERRIDES_KEY, RuntimeConfigService
+
+
+@pytest.fixture(autouse=True)
+def _clean_env(monkeypatch):
+    for name in ("CONFIG_FILE", "VENUES_LIVE_REFRESH_MINUTES", "LIVE_FETCH_CONCURRENCY"):
+        monkeypatch.delenv(name, raising=False)
+
+
+def _service(config_file="", redis_client=None):
+    settings = Settings(besttime_private_key="pri", besttime_public_key="pub")
+    mirror = Settings(besttime_private_key="pri", besttime_public_key="pub")
+    applied = []
+
+    def recorder(name):
+        return lambda value: applied.append((name, value))
+
+    svc = RuntimeConfigService(
+        settings,
+        redis_client or fakeredis.FakeRedis(decode_responses=True),
+        appliers={
+            "venues_live_refresh_minutes": recorder("venues_live_refresh_minutes"),
+            "live_fetch_concurrency": recorder("live_fetch_concurrency"),
+            "weekly_forecast_cron": recorder("weekly_forecast_cron"),
+        },
+        config_file=config_file,
+        mirrors=(mirror,),
+    )
+    return svc, applied, mirror
+
+
+def test_update_validates_stores_and_applies():
+    svc, applied, mirror = _service()
+
+    snapshot = svc.update({"venues_live_refresh_minutes": "10", "live_fetch_concurrency": 4})
+
+    assert applied == [("venues_live_refresh_minutes", 10), ("live_fetch_concurrency", 4)]
+    assert svc.settings.venues_live_refresh_minutes == 10
+    assert mirror.live_fetch_concurrency == 4
+    assert snapshot["fields"]["venues_live_refresh_minutes"] == {"value": 10, "source": "admin"}
+    assert json.loads(svc.redis.hget(OVERRIDES_KEY, "live_fetch_concurrency")) == 4
+
+
+@pytest.mark.parametrize("changes", [
+    {"server_port": 9000},  # not reloadable
+    {"venues_live_refresh_minutes": "soon"},  # wrong type
+    {"venues_live_refresh_minutes": 0},  # config_errors rejects it
+    {"weekly_forecast_cron": "every sunday"},  # not a crontab
+    {"live_fetch_concurrency": 2, "venues_live_refresh_minutes": -1},  # all or nothing
+])
+def test_invalid_changes_are_rejected_without_side_effects(changes):
+    svc, applied, _ = _service()
+
+    with pytest.raises(ValueError):
+        svc.update(changes)
+
+    assert applied == []
+    assert svc.redis.hgetall(OVERRIDES_KEY) == {}
+
+
+def test_other_replicas_pick_up_overrides_and_resets():
+    redis_client = fakeredis.FakeRedis(decode_responses=True)
+    writer, _, _ = _service(redis_client=redis_client)
+    reader, applied, _ = _service(redis_client=redis_client)
+    default = reader.settings.live_fetch_concurrency
+
+    writer.update({"live_fetch_concurrency": 6})
+    assert reader.check_once() == ["live_fetch_concurrency"]
+    assert reader.check_once() == []
+
+    writer.reset("live_fetch_concurrency")
+    assert reader.check_once() == ["live_fetch_concurrency"]
+    assert applied == [("live_fetch_concurrency", 6), ("live_fetch_concurrency", default)]
+    assert reader.snapshot()["fields"]["live_fetch_concurrency"]["source"] == "startup"
+
+
+def test_bad_override_in_redis_is_ignored():
+    svc, applied, _ = _service()
+    svc.redis.hset(OVERRIDES_KEY, "venues_live_refresh_minutes", "-5")
+
+    assert svc.check_once() == []
+    assert applied == []
+
+
+def test_config_file_changes_apply_reloadable_keys(tmp_path, monkeypatch):
+    path = tmp_path / "config.json"
+    path.write_text(json.dumps({"scheduler": {"venues_live_refresh_minutes": 5}}))
+    svc, applied, _ = _service(config_file=str(path))
+
+    path.write_text(json.dumps({
+        "scheduler": {"venues_live_refresh_minutes": 15, "live_fetch_concurrency": 3},
+        "server": {"server_port": 9999},
+    }))
+    stat = path.stat()
+    os.utime(path, (stat.st_atime, stat.st_mtime + 10))
+
+    assert sorted(svc.check_once()) == ["live_fetch_concurrency", "venues_live_refresh_minutes"]
+    assert svc.settings.server_port == 8080  # needs a restart
+    assert svc.snapshot()["fields"]["venues_live_refresh_minutes"]["source"] == "file"
+
+    # An admin override wins over the file.
+    svc.update({"venues_live_refresh_minutes": 7})
+    assert svc.check_once() == []
+    assert svc.settings.venues_live_refresh_minutes == 7
+
+
+def test_env_pins_a_field_against_the_file(tmp_path, monkeypatch):
+    path = tmp_path / "config.yaml"
+    path.write_text("live_fetch_concurrency: 2\n")
+    monkeypatch.setenv("LIVE_FETCH_CONCURRENCY", "1")
+    svc, applied, _ = _service(config_file=str(path))
+
+    path.write_text("live_fetch_concurrency: 8\n")
+    stat = path.stat()
+    os.utime(path, (stat.st_atime, stat.st_mtime + 10))
+
+    assert svc.check_once() == []
+    assert applied == []
+
+
+def test_failing_applier_leaves_the_value():
+    svc, _, _ = _service()
+
+    def boom(value):
+        raise RuntimeError("scheduler down")
+
+    svc.appliers["live_fetch_concurrency"] = boom
+    before = svc.settings.live_fetch_concurrency
+    svc.update({"live_fetch_concurrency": before + 1})
+
+    assert svc.settings.live_fetch_concurrency == before
+
+
+def test_admin_endpoints():
+    svc, _, _ = _service()
+    app = FastAPI()
+    app.include_router(router)
+    set_container(SimpleNamespace(runtime_config=svc))
+    client = TestClient(app)
+
+    body = client.get("/admin/runtime-config").json()
+    assert set(body["fields"]) == {
+        "live_fetch_concurrency", "venues_live_refresh_minutes", "weekly_forecast_cron",
+    }
+    response = client.put("/admin/runtime-config", json={"live_fetch_concurrency": 3})
+    assert response.json()["fields"]["live_fetch_concurrency"]["value"] == 3
+    assert client.put("/admin/runtime-config", json={"log_format": "json"}).status_code == 400
+    assert client.delete("/admin/runtime-config/live_fetch_concurrency").status_code == 200
+    assert client.delete("/admin/runtime-config/server_port").status_code == 404
@@ -0,0 +1,92 @@
+"""Unit tests for runtime control of the scheduled jobs (app/services/scheduler_control.py)."""
+import asyncio
+
+import pytest
+from apscheduler.schedulers.asyncio import AsyncIOScheduler
+from apscheduler.triggers.interval import IntervalTrigger
+
+from app.services import scheduler_control
+from app.services.scheduler_control import SchedulerControl
+
+
+@pytest.fixture(autouse=True)
+def _fresh_history():
+    scheduler_control.reset()
+    yield
+    scheduler_control.reset()
+
+
+def _tracked(job_id, body):
+    """A job reporting its runs the way main.make_job does."""
+    async def job():
+        scheduler_control.run_started(job_id, asyncio.current_task())
+        outcome, error = "success", None
+        try:
+            await body()
+        except asyncio.CancelledError:
+            outcome = "cancelled"
+            raise
+        except Exception as e:
+            outcome, error = "error", str(e)
+        finally:
+            scheduler_control.run_finished(job_id, outcome, error)
+    return job
+
+
+@pytest.fixture
+async def scheduler():
+    s = AsyncIOScheduler()
+    s.start()
+    yield s
+    s.shutdown(wait=False)
+
+
+@pytest.mark.asyncio
+async def test_pause_and_resume_one_job(scheduler):
+    scheduler.add_job(_tracked("live", asyncio.sleep), IntervalTrigger(minutes=5), id="live", name="Live")
+    control = SchedulerControl(scheduler)
+
+    assert control.pause_job("live")["paused"] is True
+    assert control.get_job("live")["next_run_at"] is None
+    resumed = control.resume_job("live")
+    assert resumed["paused"] is False and resumed["next_run_at"] is not None
+    assert control.pause_job("missing") is None
+
+    control.pause_all()
+    assert control.paused
+    control.resume_all()
+    assert not control.paused
+
+
+@pytest.mark.asyncio
+async def test_run_now_records_the_outcome_and_error(scheduler):
+    async def fail():
+        raise RuntimeError("besttime down")
+
+    scheduler.add_job(_tracked("weekly", fail), IntervalTrigger(days=7), id="weekly", name="Weekly")
+    control = SchedulerControl(scheduler)
+
+    control.run_now("weekly")
+    await asyncio.sleep(0.01)
+
+    status = control.get_job("weekly")
+    assert (status["last_status"], status["last_error"], status["runs"]) == ("error", "besttime down", 1)
+    assert status["last_started_at"] is not None and not status["running"]
+
+
+@pytest.mark.asyncio
+async def test_stop_cancels_the_run_in_flight(scheduler):
+    release = asyncio.Event()
+    scheduler.add_job(_tracked("catalog", release.wait), IntervalTrigger(hours=1), id="catalog", name="Catalog")
+    control = SchedulerControl(scheduler)
+
+    control.run_now("catalog")
+    await asyncio.sleep(0.01)
+    assert control.get_job("catalog")["running"]
+    with pytest.raises(RuntimeError):
+        control.run_now("catalog")
+
+    assert control.stop_run("catalog") is True
+    await asyncio.sleep(0.01)
+    assert control.get_job("catalog")["last_status"] == "cancelled"
+    assert control.stop_run("catalog") is False
@@ -0,0 +1,64 @@
+"""Unit tests for the systemd notify helpers."""
+import asyncio
+import os
+import socket
+
+import pytest
+
+from app import sd_notify
+
+
+@pytest.fixture
+def notify_socket(tmp_path, monkeypatch):
+    path = str(tmp_path / "notify.sock")
+    sock = socket.socket(socket.AF_UNIX, socket.SOCK_DGRAM)
+    sock.bind(path)
+    sock.settimeout(1)
+    monkeypatch.setenv("NOTIFY_SOCKET", path)
+    yield sock
+    sock.close()
+
+
+def test_notify_without_socket_is_a_no_op(monkeypatch):
+    monkeypatch.delenv("NOTIFY_SOCKET", raising=False)
+    assert sd_notify.notify("READY=1") is False
+
+
+def test_notify_sends_state(notify_socket):
+    assert sd_notify.notify("READY=1\nSTATUS=Serving") is True
+    assert notify_socket.recv(1024) == b"READY=1\nSTATUS=Serving"
+
+
+def test_unreachable_socket_is_logged_not_raised(tmp_path, monkeypatch):
+    monkeypatch.setenv("NOTIFY_SOCKET", str(tmp_path / "missing.sock"))
+    assert sd_notify.notify("READY=1") is False
+
+
+@pytest.mark.parametrize(
+    "usec, pid, expected",
+    [
+        (None, None, None),
+        ("10000000", None, 5.0),
+        ("10000000", "own", 5.0),
+        ("10000000", "1", None),
+        ("junk", None, None),
+    ],
+)
+def test_watchdog_interval(monkeypatch, usec, pid, expected):
+    for name, value in (("WATCHDOG_USEC", usec), ("WATCHDOG_PID", pid)):
+        if value is None:
+            monkeypatch.delenv(name, raising=False)
+        else:
+            monkeypatch.setenv(name, str(os.getpid()) if value == "own" else value)
+    assert sd_notify.watchdog_interval_seconds() == expected
+
+
+@pytest.mark.asyncio
+async def test_watchdog_pings_only_while_healthy(notify_socket):
+    results = iter([True, False, True])
+
+    task = asyncio.create_task(sd_notify.run_watchdog(0.01, lambda: next(results, True)))
+    messages = [await asyncio.to_thread(notify_socket.recv, 1024) for _ in range(3)]
+    task.cancel()
+
+    assert messages == [b"WATCHDOG=1", b"STATUS=Health check failing", b"WATCHDOG=1\nSTATUS=Serving"]
@@ -0,0 +1,92 @@
+"""Unit tests for secret stores (app/secret_providers.py) and how Settings uses them."""
+import httpx
+import pytest
+
+from app.config import Settings
+from app.secret_providers import (
+    FileSecretsProvider,
+    VaultSecretsProvider,
+    resolve_secrets,
+)
+
+
+@pytest.fixture(autouse=True)
+def _clean_env(monkeypatch):
+    for name in ("CONFIG_FILE", "BESTTIME_PRIVATE_KEY", "BESTTIME_PUBLIC_KEY",
+                 "REDIS_PASSWORD", "VAULT_TOKEN"):
+        monkeypatch.delenv(name, raising=False)
+
+
+def _vault(data, status=200, kv_version=2, seen=None):
+    def handler(request):
+        if seen is not None:
+            seen.append(request)
+        body = {"data": {"data": data}} if kv_version == 2 else {"data": data}
+        return httpx.Response(status, json=body)
+
+    return VaultSecretsProvider(
+        "https://vault:8200/", "cs-server/prod", "tok", kv_version=kv_version,
+        http_client=httpx.Client(transport=httpx.MockTransport(handler)),
+    )
+
+
+def test_file_provider_reads_lower_or_upper_case_names(tmp_path):
+    (tmp_path / "besttime_private_key").write_text("pri_file\n")
+    (tmp_path / "REDIS_PASSWORD").write_text("  hunter2 ")
+    (tmp_path / "openai_api_key").write_text("\n")
+    provider = FileSecretsProvider(str(tmp_path))
+
+    assert provider.get("besttime_private_key") == "pri_file"
+    assert provider.get("redis_password") == "hunter2"
+    assert provider.get("openai_api_key") is None
+    assert provider.get("rds_password") is None
+
+
+def test_vault_provider_reads_the_secret_once():
+    seen = []
+    provider = _vault({"besttime_public_key": "pub_vault"}, seen=seen)
+
+    assert provider.get("besttime_public_key") == "pub_vault"
+    assert provider.get("rds_password") is None
+    assert len(seen) == 1
+    assert str(seen[0].url) == "https://vault:8200/v1/secret/data/cs-server/prod"
+    assert seen[0].headers["X-Vault-Token"] == "tok"
+
+
+def test_vault_kv_v1_and_failures():
+    assert _vault({"rds_password": "pw"}, kv_version=1).get("rds_password") == "pw"
+    assert _vault({}, status=403).get("rds_password") is None
+
+
+def test_resolve_uses_the_first_provider_that_knows_and_skips_explicit(tmp_path):
+    (tmp_path / "besttime_private_key").write_text("pri_file")
+    providers = [
+        FileSecretsProvider(str(tmp_path)),
+        _vault({"besttime_private_key": "pri_vault", "besttime_public_key": "pub_vault",
+                "unrelated": "x"}),
+    ]
+
+    assert resolve_secrets(providers) == {
+        "besttime_private_key": "pri_file",
+        "besttime_public_key": "pub_vault",
+    }
+    assert resolve_secrets(providers, skip={"besttime_private_key"}) == {
+        "besttime_public_key": "pub_vault",
+    }
+
+
+def test_settings_fill_credentials_from_the_secrets_dir(tmp_path, monkeypatch):
+    (tmp_path / "besttime_private_key").write_text("pri_file")
+    (tmp_path / "besttime_public_key").write_text("pub_file")
+    (tmp_path / "redis_password").write_text("from_file")
+    monkeypatch.setenv("REDIS_PASSWORD", "from_env")
+
+    settings = Settings(secrets_dir=str(tmp_path))
+
+    assert settings.besttime_private_key == "pri_file"
+    assert settings.besttime_public_key == "pub_file"
+    assert settings.redis_password == "from_env"
+    assert settings.config_errors() == []
+
+    explicit = Settings(secrets_dir=str(tmp_path), besttime_private_key="pri_kwarg")
+    assert explicit.besttime_private_key == "pri_kwarg"
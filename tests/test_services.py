@@ -367,6 +367,86 @@ class TestVenuesRefresherService:
             == skipped_before
         )
 
+    @staticmethod
+    def _besttime_error(status, message):
+        import httpx
+        from app.api import BestTimeAPIError
+
+        request = httpx.Request("POST", "https://besttime.app/api/v1/forecasts/live")
+        response = httpx.Response(
+            status, json={"status": "Error", "message": message}, request=request
+        )
+        return BestTimeAPIError.from_status_error(
+            httpx.HTTPStatusError(message, request=request, response=response),
+            "/forecasts/live",
+        )
+
+    @pytest.mark.asyncio
+    async def test_live_refresh_skips_invalid_venue_and_aborts_on_quota(
+        self, refresher_service, mock_besttime_api
+    ):
+        """A typed BestTime error decides the run: an unknown venue is skipped,
+        an exhausted quota stops the remaining calls."""
+        mock_besttime_api.get_live_forecast.side_effect = [
+            self._besttime_error(404, "Venue not found"),
+            self._besttime_error(400, "Not enough API credits"),
+        ]
+        invalid_before = LIVE_FORECAST_FETCH_RESULTS.labels(
+            result="skipped_invalid_venue"
+        )._value.get()
+        aborted_before = LIVE_FORECAST_FETCH_RESULTS.labels(result="aborted")._value.get()
+
+        await refresher_service._fetch_and_cache_live_forecasts(["v1", "v2", "v3", "v4"])
+
+        assert mock_besttime_api.get_live_forecast.await_count == 2
+        assert (
+            LIVE_FORECAST_FETCH_RESULTS.labels(result="skipped_invalid_venue")._value.get()
+            == invalid_before + 1
+        )
+        assert (
+            LIVE_FORECAST_FETCH_RESULTS.labels(result="aborted")._value.get()
+            == aborted_before + 3
+        )
+
+    @pytest.mark.asyncio
+    async def test_live_refresh_runs_a_bounded_worker_pool(
+        self, mock_venue_dao, mock_besttime_api
+    ):
+        """With live_fetch_concurrency=3 at most three fetches are in flight,
+        every venue is fetched once, and failures are aggregated per venue."""
+        import asyncio
+
+        in_flight, peak, fetched = 0, 0, []
+
+        async def fetch(vid):
+            nonlocal in_flight, peak
+            in_flight += 1
+            peak = max(peak, in_flight)
+            await asyncio.sleep(0)
+            in_flight -= 1
+            fetched.append(vid)
+            if vid == "v5":
+                raise RuntimeError("boom")
+            return LiveForecastResponse(
+                status="OK",
+                venue_info=VenueInfo(venue_id=vid),
+                analysis=Analysis(venue_live_busyness=40, venue_live_busyness_available=True),
+            )
+
+        mock_besttime_api.get_live_forecast.side_effect = fetch
+        mock_venue_dao.set_live_forecast.return_value = True
+        service = VenuesRefresherService(
+            mock_venue_dao, mock_besttime_api, live_fetch_concurrency=3
+        )
+        ids = [f"v{i}" for i in range(10)]
+
+        summary = await service._fetch_and_cache_live_forecasts(ids)
+
+        assert peak == 3
+        assert sorted(fetched) == sorted(ids)
+        assert summary["cached"] == 9
+        assert list(summary["errors"]) == ["v5"]
+
     @pytest.mark.asyncio
     async def test_live_forecast_delete_when_status_not_ok(
         self, refresher_service, mock_besttime_api, mock_venue_dao
@@ -0,0 +1,97 @@
+"""Unit tests for settings loading and startup validation (app/config.py)."""
+import json
+
+import pytest
+
+from app.config import ConfigError, Settings, load_json_config
+
+_KEYS = {"besttime_private_key": "pri_test", "besttime_public_key": "pub_test"}
+
+
+@pytest.fixture(autouse=True)
+def _no_config_file(monkeypatch):
+    monkeypatch.delenv("CONFIG_FILE", raising=False)
+    monkeypatch.delenv("BESTTIME_PRIVATE_KEY", raising=False)
+    monkeypatch.delenv("BESTTIME_PUBLIC_KEY", raising=False)
+
+
+def test_yaml_and_json_files_are_flattened(tmp_path):
+    yaml_file = tmp_path / "config.yaml"
+    yaml_file.write_text(
+        "_comment: ignored\n"
+        "redis:\n  redis_host: cache\n  redis_port: 6380\n"
+        "server:\n  server_port: 9090\n"
+    )
+    json_file = tmp_path / "config.json"
+    json_file.write_text(json.dumps({"server": {"server_port": 9091}}))
+
+    assert load_json_config(str(yaml_file)) == {
+        "redis_host": "cache", "redis_port": 6380, "server_port": 9090,
+    }
+    assert load_json_config(str(json_file)) == {"server_port": 9091}
+
+
+def test_non_object_file_is_ignored(tmp_path):
+    path = tmp_path / "config.yml"
+    path.write_text("- a\n- b\n")
+
+    assert load_json_config(str(path)) == {}
+
+
+def test_env_overrides_the_file(tmp_path, monkeypatch):
+    path = tmp_path / "config.yaml"
+    path.write_text("server_port: 9090\nredis_host: cache\n")
+    monkeypatch.setenv("CONFIG_FILE", str(path))
+    monkeypatch.setenv("SERVER_PORT", "7070")
+
+    settings = Settings()
+
+    assert settings.server_port == 7070
+    assert settings.redis_host == "cache"
+
+
+def test_besttime_keys_have_no_default():
+    settings = Settings()
+
+    assert settings.besttime_private_key == ""
+    assert "besttime_private_key is required (env BESTTIME_PRIVATE_KEY)" in settings.config_errors()
+    with pytest.raises(ConfigError):
+        settings.check()
+
+
+def test_keys_not_required_in_demo_or_replay():
+    assert Settings(demo_mode=True).config_errors() == []
+    assert Settings(besttime_mode="replay").config_errors() == []
+
+
+def test_valid_settings_pass():
+    Settings(**_KEYS).check()
+
+
+def test_every_problem_is_reported():
+    settings = Settings(
+        **_KEYS,
+        server_port=0,
+        diagnostics_port=8080,
+        venues_live_refresh_minutes=0,
+        log_level="LOUD",
+        redis_mode="mesh",
+        tracing_sample_ratio=2,
+        venue_data_provider="google_places",
+    )
+
+    errors = settings.config_errors()
+
+    assert "server_port must be in 1-65535" in errors
+    assert "venues_live_refresh_minutes must be positive" in errors
+    assert "log_level 'LOUD' is not a logging level" in errors
+    assert "redis_mode must be one of standalone, sentinel, cluster" in errors
+    assert "tracing_sample_ratio must be in 0-1" in errors
+    assert "venue_data_provider google_places needs google_places_api_key" in errors
+    assert len(errors) == 6
+
+
+def test_diagnostics_port_must_differ_from_server_port():
+    errors = Settings(**_KEYS, diagnostics_port=8080).config_errors()
+
+    assert errors == ["diagnostics_port must differ from server_port"]
@@ -0,0 +1,100 @@
+"""Unit tests for the stale venue / live forecast eviction (app/services/stale_eviction.py)."""
+from datetime import datetime, timedelta, timezone
+
+import fakeredis
+
+from app.dao.redis_venue_dao import RedisVenueDAO
+from app.db.geo_redis_client import GeoRedisClient
+from app.models import Analysis, LiveForecastResponse, Venue, VenueInfo
+from app.services.stale_eviction import STALE_REASON, STALE_SOURCE, StaleEvictionService
+from tests.rds_fake import InMemoryRdsVenueStore
+
+NOW = datetime(2026, 10, 16, 12, 0, tzinfo=timezone.utc)
+
+
+def _dao():
+    return RedisVenueDAO(GeoRedisClient(fakeredis.FakeRedis(decode_responses=True)))
+
+
+def _venue(vid, refreshed_days_ago=None):
+    return Venue(
+        forecast=True, processed=True, venue_id=vid, venue_name=f"Bar {vid}",
+        venue_address="Rua X, 100", venue_lat=-8.05, venue_lng=-34.88,
+        refreshed_at=NOW - timedelta(days=refreshed_days_ago) if refreshed_days_ago is not None else None,
+    )
+
+
+def _live(vid, minutes_ago):
+    return LiveForecastResponse(
+        status="OK", analysis=Analysis(), venue_info=VenueInfo(venue_id=vid),
+        refreshed_at=NOW - timedelta(minutes=minutes_ago),
+    )
+
+
+def _seed(dao):
+    dao.upsert_venue(_venue("fresh", refreshed_days_ago=3))
+    dao.upsert_venue(_venue("defunct", refreshed_days_ago=120))
+    dao.upsert_venue(_venue("legacy"))
+    dao.set_live_forecast(_live("fresh", minutes_ago=10))
+    dao.set_live_forecast(_live("defunct", minutes_ago=600))
+
+
+def test_dry_run_reports_without_evicting():
+    dao = _dao()
+    _seed(dao)
+    service = StaleEvictionService(dao, venue_max_age_days=90, live_max_age_minutes=180)
+
+    summary = service.run(dry_run=True, now=NOW)
+
+    assert (summary.venues_checked, summary.venues_evicted, summary.venues_without_refresh_time) == (3, 1, 1)
+    assert (summary.live_checked, summary.live_deleted) == (2, 1)
+    assert summary.evicted_venue_ids == ["defunct"] and summary.deleted_live_ids == ["defunct"]
+    assert not dao.get_venue("defunct").is_deprecated()
+    assert dao.get_live_forecast("defunct") is not None
+    assert service.last_summary is summary
+
+
+def test_run_soft_deletes_stale_venues_and_drops_old_live_forecasts():
+    dao = _dao()
+    _seed(dao)
+    service = StaleEvictionService(dao, venue_max_age_days=90, live_max_age_minutes=180)
+
+    summary = service.run(now=NOW)
+
+    assert not summary.dry_run and summary.errors == 0
+    defunct = dao.get_venue("defunct")
+    assert defunct.is_deprecated() and defunct.deprecated_reason == "stale_not_refreshed"
+    assert not dao.get_venue("fresh").is_deprecated()
+    assert not dao.get_venue("legacy").is_deprecated()
+    assert dao.get_live_forecast("defunct") is None
+    assert dao.get_live_forecast("fresh") is not None
+
+    # Already deprecated venues are not checked again.
+    assert service.run(now=NOW).venues_checked == 2
+
+
+def test_a_rediscovered_venue_is_active_again():
+    dao = _dao()
+    _seed(dao)
+    StaleEvictionService(dao, venue_max_age_days=90, live_max_age_minutes=180).run(now=NOW)
+    assert dao.get_venue("defunct").is_deprecated()
+
+    dao.upsert_venue(_venue("defunct", refreshed_days_ago=0))
+
+    assert dao.get_venue("defunct").is_active()
+
+    rds_store = InMemoryRdsVenueStore()
+    rds_store.upsert_venue(_venue("defunct", refreshed_days_ago=120))
+    rds_store.soft_delete_venue("defunct", STALE_REASON, STALE_SOURCE)
+    rds_store.upsert_venue(_venue("defunct", refreshed_days_ago=0))
+
+    assert rds_store.list_active_venue_ids() == ["defunct"]
+
+
+def test_zero_age_keeps_that_kind():
+    dao = _dao()
+    _seed(dao)
+    summary = StaleEvictionService(dao, venue_max_age_days=0, live_max_age_minutes=0).run(now=NOW)
+
+    assert (summary.venues_checked, summary.live_checked) == (0, 0)
+    assert not dao.get_venue("defunct").is_deprecated()
@@ -0,0 +1,140 @@
+"""Tenant keyspaces, selection and per-tenant projection (app/tenancy.py)."""
+import fakeredis
+import pytest
+from fastapi import FastAPI
+from fastapi.testclient import TestClient
+
+from app.config import Settings
+from app.dao.read_cache import DaoReadCache
+from app.dao.venue_budget_dao import VenueBudgetDao
+from app.dao.redis_venue_dao import RedisVenueDAO
+from app.db.geo_redis_client import GeoRedisClient
+from app.middleware import TenantMiddleware
+from app.models import LiveForecastResponse, Venue
+from app.services.redis_projection_service import RedisProjectionService
+from app.services.venue_budget_service import VenueBudgetService
+from app.tenancy import TenantRegistry, UnknownTenantError, current_tenant, tenant_scope
+from tests.rds_fake import InMemoryRdsVenueStore
+
+_RECIFE = (-8.05, -34.88)
+_OLINDA = (-8.01, -34.85)
+_TENANTS = {
+    "recife": {"api_keys": ["key-recife"], "bbox": [-8.16, -35.02, -8.03, -34.86]},
+    "olinda": {"api_keys": ["key-olinda"], "bbox": [-8.03, -34.87, -7.96, -34.82]},
+}
+
+
+def _venue(vid, point):
+    return Venue(venue_id=vid, venue_name=vid, venue_address="a",
+                 venue_lat=point[0], venue_lng=point[1], venue_type="BAR")
+
+
+def test_each_tenant_reads_and_writes_its_own_keyspace():
+    redis_client = fakeredis.FakeRedis(decode_responses=True)
+    dao = RedisVenueDAO(GeoRedisClient(redis_client), read_cache=DaoReadCache(100, 60))
+
+    with tenant_scope("recife"):
+        dao.upsert_venue(_venue("v1", _RECIFE))
+        assert dao.get_venue("v1") is not None  # now cached for recife
+        assert [v.venue_id for v in dao.get_nearby_venues(*_RECIFE, 1)] == ["v1"]
+    with tenant_scope("olinda"):
+        assert dao.get_venue("v1") is None
+        assert dao.get_nearby_venues(*_RECIFE, 1) == []
+    assert dao.get_venue("v1") is None  # unscoped
+    assert redis_client.exists("t:recife:venues_geo_v1")
+    with tenant_scope("recife"):
+        dao.set_live_forecast(LiveForecastResponse.model_validate(
+            {"status": "OK", "venue_info": {"venue_id": "v1"}, "analysis": {}}
+        ))
+        # SCAN results come back unprefixed
+        assert dao.list_cached_live_forecast_venue_ids() == ["v1"]
+    assert dao.list_cached_live_forecast_venue_ids() == []
+
+
+def test_registry_resolves_api_key_then_default():
+    registry = TenantRegistry(_TENANTS, default_tenant="recife")
+
+    assert registry.resolve(api_key="key-olinda").id == "olinda"
+    assert registry.resolve(api_key="key-olinda", tenant_id="olinda").id == "olinda"
+    assert registry.resolve().id == "recife"
+    assert registry.resolve(tenant_id="recife").id == "recife"
+    with pytest.raises(UnknownTenantError):
+        registry.resolve(api_key="nope")
+    # The header alone never selects another tenant.
+    with pytest.raises(UnknownTenantError, match="requires its API key"):
+        registry.resolve(tenant_id="olinda")
+    with pytest.raises(UnknownTenantError, match="requires its API key"):
+        registry.resolve(api_key="key-olinda", tenant_id="recife")
+    with pytest.raises(UnknownTenantError):
+        registry.resolve(tenant_id="caruaru")
+    with pytest.raises(UnknownTenantError):
+        TenantRegistry(_TENANTS).resolve()
+
+
+def test_middleware_scopes_each_request():
+    app = FastAPI()
+    app.add_middleware(TenantMiddleware, registry=TenantRegistry(_TENANTS))
+
+    @app.get("/whoami")
+    def whoami():  # sync: runs in the thread pool
+        return {"tenant": current_tenant()}
+
+    @app.get("/admin/whoami")
+    def admin_whoami():
+        return {"tenant": current_tenant()}
+
+    client = TestClient(app)
+
+    assert client.get("/whoami", headers={"X-API-Key": "key-olinda"}).json() == {"tenant": "olinda"}
+    assert client.get("/whoami").status_code == 401  # no key, no default tenant
+    assert client.get("/whoami", headers={"X-Tenant-Id": "olinda"}).status_code == 401
+    assert client.get("/whoami", headers={"X-API-Key": "nope"}).status_code == 401
+    # Admin routes have their own auth and run unscoped.
+    assert client.get("/admin/whoami").json() == {"tenant": None}
+
+
+def test_projection_writes_each_tenant_its_bbox():
+    dao = RedisVenueDAO(GeoRedisClient(fakeredis.FakeRedis(decode_responses=True)))
+    store = InMemoryRdsVenueStore()
+    store.upsert_venue(_venue("recife_bar", _RECIFE))
+    store.upsert_venue(_venue("olinda_bar", _OLINDA))
+
+    summary = RedisProjectionService(dao, store).rebuild_redis_for_tenants(TenantRegistry(_TENANTS))
+
+    assert summary["venues"] == 2
+    assert {t: s["venues"] for t, s in summary["tenants"].items()} == {"recife": 1, "olinda": 1}
+    with tenant_scope("recife"):
+        assert dao.get_venue("recife_bar") is not None and dao.get_venue("olinda_bar") is None
+    with tenant_scope("olinda"):
+        assert dao.get_venue("olinda_bar") is not None and dao.get_venue("recife_bar") is None
+    # The unprefixed keyspace (gRPC, area index, webhooks, ...) keeps every venue.
+    assert summary["unscoped"]["venues"] == 2
+    assert dao.get_venue("recife_bar") is not None and dao.get_venue("olinda_bar") is not None
+
+
+def test_tenant_quota_counts_only_its_own_new_venues():
+    redis_client = fakeredis.FakeRedis(decode_responses=True)
+    budget = VenueBudgetService(
+        redis_client, VenueBudgetDao(redis_client), year_month_provider=lambda: "2026-10"
+    )
+
+    budget.record_new_venue_from_discovery(tenant_id="recife")
+    budget.record_new_venue_from_discovery(tenant_id="recife")
+    assert budget.record_new_venue_from_discovery(tenant_id="olinda") == 3  # shared count
+
+    assert budget.tenant_cap_remaining("recife", 2) == 0
+    assert budget.tenant_cap_remaining("olinda", 2) == 1
+
+
+def test_config_errors():
+    assert Settings(besttime_mode="replay", tenants=_TENANTS, default_tenant="recife").config_errors() == []
+    errors = Settings(
+        tenants={"a:b": {}, "c": {"api_keys": ["k"]}, "d": {"api_keys": ["k"]}},
+        default_tenant="x",
+    ).config_errors()
+    assert "tenant id 'a:b' must match [A-Za-z0-9_-]+" in errors
+    assert "tenants share an API key" in errors
+    assert "default_tenant x is not in tenants" in errors
+    assert any(e.startswith("tenants: ") for e in Settings(
+        tenants={"a": {"bbox": [1, 2]}}
+    ).config_errors())
@@ -0,0 +1,138 @@
+"""Unit tests for OpenTelemetry tracing (app/tracing.py, TracingMiddleware).
+
+Spans go to an in-memory exporter on an SDK provider installed once for the
+test process (the global provider can only be set once).
+"""
+import fakeredis
+import pytest
+from fastapi import FastAPI
+from fastapi.testclient import TestClient
+from opentelemetry import trace
+from opentelemetry.sdk.trace import TracerProvider
+from opentelemetry.sdk.trace.export import SimpleSpanProcessor
+from opentelemetry.sdk.trace.export.in_memory_span_exporter import InMemorySpanExporter
+from opentelemetry.trace import SpanKind, StatusCode
+
+from app.dao.redis_venue_dao import RedisVenueDAO
+from app.db.geo_redis_client import GeoRedisClient
+from app.middleware import TracingMiddleware
+from app.models import Venue
+from app.tracing import traced
+
+_EXPORTER = InMemorySpanExporter()
+
+
+@pytest.fixture(autouse=True)
+def spans():
+    provider = trace.get_tracer_provider()
+    if not isinstance(provider, TracerProvider):
+        provider = TracerProvider()
+        trace.set_tracer_provider(provider)
+        provider.add_span_processor(SimpleSpanProcessor(_EXPORTER))
+    _EXPORTER.clear()
+    yield _EXPORTER
+    _EXPORTER.clear()
+
+
+class TestTraced:
+    def test_sync_function_runs_in_named_span(self, spans):
+        @traced("unit.sync")
+        def add(a, b):
+            return a + b
+
+        assert add(1, 2) == 3
+        assert [s.name for s in spans.get_finished_spans()] == ["unit.sync"]
+
+    async def test_async_function_nests_under_caller(self, spans):
+        @traced("unit.inner")
+        async def inner():
+            return trace.get_current_span().get_span_context().trace_id
+
+        @traced()
+        async def outer():
+            return await inner()
+
+        trace_id = await outer()
+
+        inner_span, outer_span = spans.get_finished_spans()
+        assert inner_span.name == "unit.inner"
+        assert outer_span.name.endswith("outer")
+        assert inner_span.parent.span_id == outer_span.context.span_id
+        assert inner_span.context.trace_id == trace_id
+
+    def test_exception_is_recorded(self, spans):
+        @traced("unit.fails")
+        def boom():
+            raise ValueError("nope")
+
+        with pytest.raises(ValueError):
+            boom()
+
+        (span,) = spans.get_finished_spans()
+        assert span.status.status_code == StatusCode.ERROR
+        assert span.events[0].name == "exception"
+
+
+class TestTracingMiddleware:
+    @pytest.fixture
+    def client(self):
+        app = FastAPI()
+        app.add_middleware(TracingMiddleware)
+
+        @app.get("/v1/items/{item_id}")
+        def item(item_id: str):
+            return {"id": item_id}
+
+        @app.get("/v1/broken")
+        def broken():
+            raise RuntimeError("down")
+
+        @app.get("/metrics")
+        def metrics():
+            return {}
+
+        return TestClient(app, raise_server_exceptions=False)
+
+    def test_server_span_named_after_route(self, client, spans):
+        assert client.get("/v1/items/42").status_code == 200
+
+        (span,) = spans.get_finished_spans()
+        assert span.name == "GET /v1/items/{item_id}"
+        assert span.kind == SpanKind.SERVER
+        assert span.attributes["http.route"] == "/v1/items/{item_id}"
+        assert span.attributes["http.response.status_code"] == 200
+
+    def test_continues_incoming_traceparent(self, client, spans):
+        trace_id = "4bf92f3577b34da6a3ce929d0e0e4736"
+        client.get(
+            "/v1/items/1",
+            headers={"traceparent": f"00-{trace_id}-00f067aa0ba902b7-01"},
+        )
+
+        (span,) = spans.get_finished_spans()
+        assert format(span.context.trace_id, "032x") == trace_id
+        assert format(span.parent.span_id, "016x") == "00f067aa0ba902b7"
+
+    def test_server_error_marks_span(self, client, spans):
+        assert client.get("/v1/broken").status_code == 500
+
+        (span,) = spans.get_finished_spans()
+        assert span.status.status_code == StatusCode.ERROR
+
+    def test_excluded_paths_are_not_traced(self, client, spans):
+        client.get("/metrics")
+
+        assert spans.get_finished_spans() == ()
+
+
+def test_dao_operations_get_spans(spans):
+    dao = RedisVenueDAO(GeoRedisClient(fakeredis.FakeRedis(decode_responses=True)))
+
+    with trace.get_tracer(__name__).start_as_current_span("parent") as parent:
+        dao.upsert_venue(Venue(
+            venue_id="v1", venue_name="v1", venue_address="a", venue_lat=-8.0, venue_lng=-34.9,
+        ))
+
+    dao_spans = [s for s in spans.get_finished_spans() if s.name == "RedisVenueDAO.upsert_venue"]
+    assert len(dao_spans) == 1
+    assert dao_spans[0].parent.span_id == parent.get_span_context().span_id
@@ -0,0 +1,83 @@
+"""Unit tests for transparent compression of large Redis values."""
+import json
+
+import fakeredis
+import pytest
+
+from app.dao import RedisVenueDAO
+from app.db.geo_redis_client import GeoRedisClient
+from app.db.value_compression import ValueCompressor
+from app.models import Venue
+
+BIG = json.dumps({"venue_id": "v1", "text": "busy bar " * 200})
+
+
+class TestValueCompressor:
+    def test_none_is_passthrough(self):
+        assert ValueCompressor("none", 1).encode(BIG) == BIG
+
+    def test_below_threshold_is_plain(self):
+        assert ValueCompressor("gzip", len(BIG) + 1).encode(BIG) == BIG
+
+    def test_gzip_round_trip(self):
+        codec = ValueCompressor("gzip", 10)
+        encoded = codec.encode(BIG)
+        assert encoded.startswith("\x1fGZ1:")
+        assert len(encoded) < len(BIG) / 3
+        assert codec.decode(encoded) == BIG
+
+    def test_incompressible_value_stays_plain(self):
+        value = "x9Q"
+        assert ValueCompressor("gzip", 1).encode(value) == value
+
+    def test_decode_ignores_configured_algorithm(self):
+        encoded = ValueCompressor("gzip", 10).encode(BIG)
+        assert ValueCompressor("none").decode(encoded) == BIG
+
+    def test_plain_and_none_pass_through(self):
+        codec = ValueCompressor("gzip")
+        assert codec.decode('{"a":1}') == '{"a":1}'
+        assert codec.decode(None) is None
+
+    def test_corrupt_payload(self):
+        with pytest.raises(ValueError):
+            ValueCompressor().decode("\x1fGZ1:AAAA")
+
+    def test_unknown_algorithm(self):
+        with pytest.raises(ValueError):
+            ValueCompressor("brotli")
+
+
+class TestGeoRedisClientCompression:
+    def _client(self, algorithm="gzip"):
+        raw = fakeredis.FakeRedis(decode_responses=True)
+        return raw, GeoRedisClient(raw, compressor=ValueCompressor(algorithm, 100))
+
+    def test_set_get_mget(self):
+        raw, client = self._client()
+        client.set("k", BIG)
+        client.setex("t", 60, BIG)
+        assert raw.get("k").startswith("\x1f")
+        assert client.get("k") == BIG
+        assert client.mget(["k", "t", "missing"]) == [BIG, BIG, None]
+
+    def test_corrupt_value_reads_as_absent(self):
+        raw, client = self._client()
+        raw.set("k", "\x1fGZ1:AAAA")
+        assert client.get("k") is None
+
+    def test_venue_documents_round_trip_through_dao(self):
+        raw, client = self._client()
+        dao = RedisVenueDAO(client)
+        venue = Venue(
+            venue_id="v1", venue_name="Bar " * 100, venue_lat=-8.0, venue_lng=-34.9
+        )
+        dao.upsert_venue(venue)
+        assert raw.get("venues_geo_place_v1:v1").startswith("\x1f")
+        assert dao.get_venue("v1").venue_name == venue.venue_name
+        assert [v.venue_id for v in dao.get_nearby_venues(-8.0, -34.9, 1)] == ["v1"]
+
+    def test_reads_values_written_before_compression_was_enabled(self):
+        raw, client = self._client()
+        raw.set("k", BIG)
+        assert client.get("k") == BIG
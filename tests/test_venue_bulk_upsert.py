@@ -0,0 +1,179 @@
+"""Unit tests for the pipelined bulk venue upsert (RedisVenueDAO.upsert_venues).
+
+Covers key/geo parity with the single-item upsert_venue, the lifecycle
+preservation both paths share, chunked round-trips, and the projector's
+per-venue fallback when a bulk write fails. fakeredis only.
+"""
+from __future__ import annotations
+
+import fakeredis
+import pytest
+import redis
+
+from app.dao.redis_venue_dao import RedisVenueDAO, VENUES_GEO_KEY_V1
+from app.db.geo_redis_client import GeoRedisClient
+from app.models import Venue
+from app.services.redis_projection_service import RedisProjectionService
+from tests.rds_fake import InMemoryRdsVenueStore
+
+_LAT, _LNG = -8.05, -34.88
+
+
+def _venue(vid: str, name: str = "Bar", **kwargs) -> Venue:
+    return Venue(venue_id=vid, venue_name=name, venue_address="a",
+                 venue_lat=_LAT, venue_lng=_LNG, venue_type="BAR", **kwargs)
+
+
+class _CountingPipelines:
+    """Wraps a fakeredis client, counting pipeline executes."""
+
+    def __init__(self, inner):
+        self._inner = inner
+        self.executes = 0
+
+    def pipeline(self, *args, **kwargs):
+        pipe = self._inner.pipeline(*args, **kwargs)
+        outer = self
+        original = pipe.execute
+
+        def _execute(*a, **kw):
+            outer.executes += 1
+            return original(*a, **kw)
+
+        pipe.execute = _execute
+        return pipe
+
+    def __getattr__(self, name):
+        return getattr(self._inner, name)
+
+
+class _FailingPipelines(_CountingPipelines):
+    """Raises a connection error on the listed (1-based) pipeline executes."""
+
+    def __init__(self, inner, fail_on):
+        super().__init__(inner)
+        self.fail_on = set(fail_on)
+
+    def pipeline(self, *args, **kwargs):
+        pipe = super().pipeline(*args, **kwargs)
+        counted = pipe.execute
+        outer = self
+
+        def _execute(*a, **kw):
+            if outer.executes + 1 in outer.fail_on:
+                outer.executes += 1
+                raise redis.ConnectionError("connection reset")
+            return counted(*a, **kw)
+
+        pipe.execute = _execute
+        return pipe
+
+
+def _dao(raw=None) -> RedisVenueDAO:
+    return RedisVenueDAO(GeoRedisClient(raw or fakeredis.FakeRedis(decode_responses=True)))
+
+
+class TestUpsertVenues:
+    def test_bulk_matches_single_upsert(self):
+        single, bulk = _dao(), _dao()
+        single.upsert_venue(_venue("v1"))
+        single.upsert_venue(_venue("v2", "Bar 2"))
+
+        written = bulk.upsert_venues([_venue("v1"), _venue("v2", "Bar 2")])
+
+        assert written == 2
+        for vid in ("v1", "v2"):
+            assert bulk.get_venue(vid) == single.get_venue(vid)
+        nearby = {v.venue_id for v in bulk.get_nearby_venues(_LAT, _LNG, 1.0)}
+        assert nearby == {"v1", "v2"}
+
+    def test_bulk_preserves_deprecated_lifecycle(self):
+        dao = _dao()
+        dao.upsert_venue(_venue(
+            "closed", lifecycle_status="deprecated",
+            deprecated_reason="google_places_closed_permanently",
+            google_business_status="CLOSED_PERMANENTLY",
+        ))
+
+        dao.upsert_venues([_venue("closed", "Re-seen")])
+
+        stored = dao.get_venue("closed")
+        assert stored.lifecycle_status == "deprecated"
+        assert stored.deprecated_reason == "google_places_closed_permanently"
+        assert stored.google_business_status == "CLOSED_PERMANENTLY"
+
+    def test_writes_one_round_trip_per_chunk(self):
+        raw = _CountingPipelines(fakeredis.FakeRedis(decode_responses=True))
+        dao = _dao(raw)
+
+        dao.upsert_venues([_venue(f"v{i}") for i in range(5)], chunk_size=2)
+
+        assert raw.executes == 3
+        assert raw.zcard(VENUES_GEO_KEY_V1) == 5
+
+    def test_empty_input_is_a_no_op(self):
+        raw = _CountingPipelines(fakeredis.FakeRedis(decode_responses=True))
+        assert _dao(raw).upsert_venues([]) == 0
+        assert raw.executes == 0
+
+    def test_failed_chunk_reported_per_venue(self):
+        raw = _FailingPipelines(fakeredis.FakeRedis(decode_responses=True), fail_on={2})
+        dao = _dao(raw)
+        errors = {}
+
+        written = dao.upsert_venues(
+            [_venue(f"v{i}") for i in range(5)], chunk_size=2, errors=errors
+        )
+
+        assert written == 3
+        assert set(errors) == {"v2", "v3"}
+        assert all(isinstance(e, redis.ConnectionError) for e in errors.values())
+        assert {v.venue_id for v in dao.get_nearby_venues(_LAT, _LNG, 1.0)} == {"v0", "v1", "v4"}
+
+    def test_failed_chunk_raises_without_collector(self):
+        raw = _FailingPipelines(fakeredis.FakeRedis(decode_responses=True), fail_on={1})
+        with pytest.raises(redis.ConnectionError):
+            _dao(raw).upsert_venues([_venue("v1")])
+
+
+class TestProjectorBulkVenueWrite:
+    def test_bulk_failure_falls_back_to_per_venue(self):
+        store = InMemoryRdsVenueStore()
+        store.upsert_venue(_venue("a"))
+        store.upsert_venue(_venue("b"))
+        dao = _dao()
+
+        def _boom(venues, chunk_size=500):
+            raise RuntimeError("pipeline down")
+
+        dao.upsert_venues = _boom
+
+        summary = RedisProjectionService(dao, store).rebuild_redis_from_rds()
+
+        assert summary["venues"] == 2
+        assert summary["errors"] == 0
+        assert dao.get_venue("a") is not None and dao.get_venue("b") is not None
+
+    def test_only_failed_chunk_is_retried_per_venue(self):
+        store = InMemoryRdsVenueStore()
+        for vid in ("a", "b"):
+            store.upsert_venue(_venue(vid))
+        dao = _dao()
+        bulk = dao.upsert_venues
+        singles = []
+
+        def _partial(venues, chunk_size=500, errors=None):
+            errors["b"] = redis.ConnectionError("connection reset")
+            return bulk([v for v in venues if v.venue_id != "b"])
+
+        def _single(venue):
+            singles.append(venue.venue_id)
+            RedisVenueDAO.upsert_venue(dao, venue)
+
+        dao.upsert_venues = _partial
+        dao.upsert_venue = _single
+
+        summary = RedisProjectionService(dao, store).rebuild_redis_from_rds()
+
+        assert singles == ["b"]
+        assert summary["venues"] == 2 and summary["errors"] == 0
@@ -0,0 +1,115 @@
+"""Unit tests for temporary venue closures (app/services/venue_closures.py)."""
+from datetime import date
+from types import SimpleNamespace
+
+import fakeredis
+import pytest
+from fastapi import FastAPI
+from fastapi.testclient import TestClient
+
+from app.dao.redis_venue_dao import RedisVenueDAO
+from app.db.geo_redis_client import GeoRedisClient
+from app.handlers import VenueHandler
+from app.models import Venue
+from app.routers.admin_trigger_router import router, set_container
+from app.services.admin_config_service import AdminConfigService
+from app.services.venue_closures import (
+    VenueClosuresService,
+    closed_venue_ids,
+    load_closed_venue_ids_from_redis,
+    validate_venue_closures_config,
+)
+from app.services.venues_refresher_service import VenuesRefresherService
+from tests.rds_fake import InMemoryRdsVenueStore
+
+_LAT, _LNG = -8.05, -34.88
+_TODAY = date(2026, 7, 10)
+
+
+def _admin_config(redis_client=None):
+    return AdminConfigService(
+        redis_client or fakeredis.FakeRedis(decode_responses=True),
+        rds_store=InMemoryRdsVenueStore(),
+        validators={"venue_closures": validate_venue_closures_config},
+    )
+
+
+def _service(config=None):
+    return VenueClosuresService(config or _admin_config(), today=lambda: _TODAY)
+
+
+def test_window_is_inclusive_and_resumes_after_end():
+    closures = {"v1": {"start": "2026-07-01", "end": "2026-07-10"}}
+
+    assert closed_venue_ids(closures, date(2026, 7, 1)) == {"v1"}
+    assert closed_venue_ids(closures, date(2026, 7, 10)) == {"v1"}
+    assert closed_venue_ids(closures, date(2026, 7, 11)) == set()
+    assert closed_venue_ids(closures, date(2026, 6, 30)) == set()
+
+
+def test_validation_rejects_inverted_and_ended_windows():
+    with pytest.raises(ValueError):
+        validate_venue_closures_config({"v1": {"start": "2026-07-10", "end": "2026-07-01"}})
+    with pytest.raises(ValueError):
+        _service().set_closure("v1", date(2026, 7, 1), date(2026, 7, 9))
+
+
+def test_writes_prune_ended_windows():
+    config = _admin_config()
+    config.set("venue_closures", {"old": {"start": "2026-06-01", "end": "2026-06-30"}})
+    service = _service(config)
+
+    service.set_closure("v1", date(2026, 7, 10), date(2026, 7, 31), reason="Renovation")
+
+    assert set(service.list_closures()) == {"v1"}
+    assert service.list_closures()["v1"]["reason"] == "Renovation"
+
+
+def test_closed_venue_is_left_out_of_nearby():
+    config = _admin_config()
+    config.set("venue_closures", {"v1": {"start": "2026-01-01", "end": "2099-12-31"}})
+    dao = RedisVenueDAO(GeoRedisClient(fakeredis.FakeRedis(decode_responses=True)))
+    dao.upsert_venues([
+        Venue(venue_id=vid, venue_name=vid, venue_address="a", venue_lat=_LAT, venue_lng=_LNG)
+        for vid in ("v1", "v2")
+    ])
+
+    result = VenueHandler(dao, admin_config_service=config).get_venues_nearby(_LAT, _LNG, 1.0)
+
+    assert [r.venue_id for r in result] == ["v2"]
+
+
+def test_refresh_selection_skips_closed_venues():
+    redis_client = fakeredis.FakeRedis(decode_responses=True)
+    _admin_config(redis_client).set(
+        "venue_closures", {"v1": {"start": "2026-01-01", "end": "2099-12-31"}}
+    )
+    dao = RedisVenueDAO(GeoRedisClient(redis_client))
+    dao.upsert_venues([
+        Venue(venue_id=vid, venue_name=vid, venue_address="a", venue_lat=_LAT, venue_lng=_LNG)
+        for vid in ("v1", "v2")
+    ])
+    refresher = VenuesRefresherService(venue_dao=dao, besttime_api=None, redis_client=redis_client)
+
+    assert refresher._select_refresh_venue_ids("live_forecast") == ["v2"]
+    assert load_closed_venue_ids_from_redis(None) == set()
+
+
+def test_admin_endpoints():
+    set_container(SimpleNamespace(venue_closures_service=_service()))
+    app = FastAPI()
+    app.include_router(router)
+    client = TestClient(app)
+
+    response = client.put(
+        "/admin/venues/v1/closure",
+        json={"start": "2026-07-10", "end": "2026-07-20", "reason": "Private event"},
+    )
+    assert response.status_code == 200
+    assert response.json()["end"] == "2026-07-20"
+    assert client.put(
+        "/admin/venues/v1/closure", json={"start": "2026-07-20", "end": "2026-07-10"}
+    ).status_code == 400
+    assert set(client.get("/admin/venues/closures").json()["closures"]) == {"v1"}
+    assert client.delete("/admin/venues/v1/closure").status_code == 200
+    assert client.delete("/admin/venues/v1/closure").status_code == 404
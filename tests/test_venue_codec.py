@@ -0,0 +1,59 @@
+"""Unit tests for schema-versioned venue documents (app/dao/venue_codec.py)."""
+import json
+
+import fakeredis
+import pytest
+
+from app.dao.redis_venue_dao import RedisVenueDAO
+from app.dao.venue_codec import (
+    SCHEMA_VERSION_FIELD,
+    VENUE_SCHEMA_VERSION,
+    decode_venue,
+    encode_venue,
+)
+from app.db.geo_redis_client import GeoRedisClient
+from app.models import Venue
+
+# Written before schema_version existed: longitude as venue_lon, price tier 0.
+_LEGACY = json.dumps({
+    "forecast": True, "processed": True, "venue_id": "old", "venue_name": "Bar Antigo",
+    "venue_lat": -8.05, "venue_lon": -34.88, "price_level": 0,
+})
+
+
+def test_encoded_documents_carry_the_version():
+    venue = Venue(venue_id="v1", venue_name="Bar do Zé", venue_lat=-8.05, venue_lng=-34.88)
+
+    raw = encode_venue(venue)
+
+    assert json.loads(raw)[SCHEMA_VERSION_FIELD] == VENUE_SCHEMA_VERSION
+    assert decode_venue(raw) == venue
+
+
+def test_legacy_documents_are_upgraded_on_read():
+    venue = decode_venue(_LEGACY)
+
+    assert (venue.venue_id, venue.venue_lng, venue.price_level) == ("old", -34.88, None)
+    # A newer document than this build knows still parses.
+    newer = json.dumps({SCHEMA_VERSION_FIELD: 99, "venue_id": "new", "venue_lat": 1.0, "venue_lng": 2.0, "x": 1})
+    assert decode_venue(newer).venue_id == "new"
+    with pytest.raises(ValueError):
+        decode_venue(json.dumps({SCHEMA_VERSION_FIELD: "two", "venue_lat": 1.0, "venue_lng": 2.0}))
+
+
+def test_dao_reads_legacy_records_and_rewrites_them_on_write():
+    raw = fakeredis.FakeRedis(decode_responses=True)
+    dao = RedisVenueDAO(GeoRedisClient(raw))
+    raw.geoadd("venues_geo_v1", (-34.88, -8.05, "venues_geo_place_v1:old"))
+    raw.set("venues_geo_place_v1:old", _LEGACY)
+
+    [nearby] = dao.get_nearby_venues(-8.05, -34.88, 1)
+    assert nearby.venue_lng == -34.88
+    assert dao.get_venue("old").venue_name == "Bar Antigo"
+    assert [v.venue_id for v in dao.list_all_venues()] == ["old"]
+
+    dao.upsert_venue(nearby)
+
+    stored = json.loads(raw.get("venues_geo_place_v1:old"))
+    assert stored[SCHEMA_VERSION_FIELD] == VENUE_SCHEMA_VERSION
+    assert "venue_lon" not in stored and stored["price_level"] is None
@@ -0,0 +1,36 @@
+"""The concrete venue DAOs must satisfy the VenueDAO interface."""
+import inspect
+
+import pytest
+
+from app.dao import PrioritizedVenueDAO, RedisVenueDAO, VenueDAO
+from app.dao.rds_fallback_dao import RdsFallbackVenueDAO
+from app.dao.venue_repository import VenueRepository
+
+
+def _members(protocol):
+    return {
+        name: member
+        for cls in protocol.__mro__
+        if cls.__dict__.get("_is_protocol")
+        for name, member in vars(cls).items()
+        if callable(member) and not name.startswith("_")
+    }
+
+
+@pytest.mark.parametrize(
+    "impl, protocol",
+    [
+        (RedisVenueDAO, VenueDAO),
+        (RdsFallbackVenueDAO, VenueDAO),
+        (VenueRepository, PrioritizedVenueDAO),
+    ],
+)
+def test_implements_interface(impl, protocol):
+    members = _members(protocol)
+    assert "get_nearby_venues" in members
+    for name, member in members.items():
+        assert hasattr(impl, name), f"{impl.__name__} lacks {name}"
+        wanted = list(inspect.signature(member).parameters)
+        actual = list(inspect.signature(getattr(impl, name)).parameters)
+        assert actual[: len(wanted)] == wanted, f"{impl.__name__}.{name}{actual} vs {wanted}"
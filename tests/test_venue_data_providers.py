@@ -0,0 +1,91 @@
+"""Tests for pluggable venue discovery providers (app/services/venue_data_providers.py)."""
+from unittest.mock import AsyncMock, Mock
+
+import pytest
+
+from app.models import VenueFilterParams, VenueFilterResponse
+from app.services import VenuesRefresherService
+from app.services.crowd_providers import BestTimeCrowdProvider
+from app.services.venue_data_providers import (
+    GOOGLE_VENUE_ID_PREFIX,
+    BestTimeVenueDataProvider,
+    GooglePlacesVenueDataProvider,
+    _place_to_venue,
+    build_venue_data_provider,
+)
+
+
+def _place(place_id="ChIJabc", **overrides):
+    place = {
+        "id": place_id,
+        "displayName": {"text": "Bar do Geraldo"},
+        "formattedAddress": "Rua da Moeda, Recife",
+        "location": {"latitude": -8.06, "longitude": -34.87},
+        "primaryType": "bar",
+        "rating": 4.6,
+        "userRatingCount": 812,
+        "priceLevel": "PRICE_LEVEL_MODERATE",
+    }
+    place.update(overrides)
+    return place
+
+
+def _params(limit=10):
+    return VenueFilterParams(lat=-8.06, lng=-34.87, radius=2000, limit=limit)
+
+
+@pytest.mark.asyncio
+async def test_google_places_results_map_to_filter_venues():
+    places_api = Mock()
+    places_api.search_nearby = AsyncMock(return_value=[_place(), _place("ChIJnoloc", location={})])
+    provider = GooglePlacesVenueDataProvider(places_api, included_types=["bar"])
+
+    response = await provider.search_nearby(_params(limit=5))
+
+    places_api.search_nearby.assert_awaited_once_with(
+        -8.06, -34.87, 2000, max_results=5, included_types=["bar"]
+    )
+    assert response.status == "OK" and response.venues_n == 1
+    venue = response.venues[0]
+    assert venue.venue_id == f"{GOOGLE_VENUE_ID_PREFIX}ChIJabc"
+    assert (venue.venue_name, venue.venue_type, venue.price_level) == ("Bar do Geraldo", "BAR", 2)
+    assert (venue.rating, venue.reviews) == (4.6, 812)
+    assert venue.day_raw == []
+
+
+@pytest.mark.asyncio
+async def test_refresher_discovers_through_the_wired_provider():
+    dao = Mock()
+    dao.get_venue.return_value = None
+    besttime = Mock()
+    besttime.venue_filter = AsyncMock()
+    service = VenuesRefresherService(dao, besttime)
+    provider = Mock()
+    provider.search_nearby = AsyncMock(return_value=VenueFilterResponse(
+        status="OK", venues=[_place_to_venue(_place(), day_int=4)], venues_n=1,
+    ))
+    service.set_venue_data_provider(provider)
+
+    ids = await service.discover_and_upsert_venues_via_filter(_params())
+
+    besttime.venue_filter.assert_not_awaited()
+    assert ids == [f"{GOOGLE_VENUE_ID_PREFIX}ChIJabc"]
+
+
+def test_build_provider_by_name():
+    besttime = Mock()
+    assert isinstance(build_venue_data_provider("besttime", besttime), BestTimeVenueDataProvider)
+    assert isinstance(
+        build_venue_data_provider("google_places", besttime, google_places_api=Mock()),
+        GooglePlacesVenueDataProvider,
+    )
+    with pytest.raises(ValueError):
+        build_venue_data_provider("google_places", besttime)
+    with pytest.raises(ValueError):
+        build_venue_data_provider("foursquare", besttime)
+
+
+def test_besttime_crowd_provider_skips_google_venues():
+    provider = BestTimeCrowdProvider(Mock())
+    assert provider.covers("ven_123", None)
+    assert not provider.covers(f"{GOOGLE_VENUE_ID_PREFIX}ChIJabc", None)
@@ -0,0 +1,174 @@
+"""Unit tests for the fuzzy venue dedup (app/services/venue_dedup.py)."""
+from datetime import datetime, timezone
+from unittest.mock import AsyncMock, Mock
+
+import fakeredis
+import pytest
+
+from app.config import Settings
+from app.dao.redis_venue_dao import RedisVenueDAO
+from app.db.geo_redis_client import GeoRedisClient
+from app.models import Venue, VenueFilterParams, VenueFilterResponse
+from app.services.admin_config_service import AdminConfigService
+from app.services.checkins import CheckinService
+from app.services.engagement_service import EngagementService
+from app.services.partner_occupancy_service import PartnerOccupancyService
+from app.services.venue_dedup import (
+    ALIASES_KEY,
+    VenueDeduplicator,
+    dedup_name,
+    dedup_similarity,
+)
+from app.services.venue_notes import VenueNotesService, validate_venue_notes_config
+from app.services.venues_refresher_service import VenuesRefresherService
+from tests.rds_fake import InMemoryRdsVenueStore
+
+
+def _venue(vid, name, lat=-8.0630, lng=-34.8711, reviews=None):
+    return Venue(
+        forecast=True, processed=True, venue_id=vid, venue_name=name,
+        venue_address="Rua da Moeda, 100", venue_lat=lat, venue_lng=lng, reviews=reviews,
+    )
+
+
+def _dedup(**kwargs):
+    redis_client = fakeredis.FakeRedis(decode_responses=True)
+    dao = RedisVenueDAO(GeoRedisClient(redis_client))
+    return dao, VenueDeduplicator(dao, redis_client, **kwargs)
+
+
+def test_names_ignore_accents_punctuation_and_city_words():
+    assert dedup_name("Bar do Ze - Recife", {"recife"}) == "bar do ze"
+    assert dedup_name("Bar do Zé", {"recife"}) == "bar do ze"
+    assert dedup_similarity("boteco do ze", "ze boteco do") == 1.0
+    # Containing the other name is not enough.
+    assert dedup_similarity("bar", "bar do ze") < 0.9
+    assert dedup_similarity("", "bar") == 0.0
+
+
+def test_find_duplicate_needs_a_similar_name_nearby():
+    dao, dedup = _dedup()
+    dao.upsert_venue(_venue("ze", "Bar do Zé"))
+    dao.upsert_venue(_venue("far", "Bar do Zé", lat=-8.0700))  # ~780 m south
+
+    assert dedup.find_duplicate(_venue("new", "Bar do Ze - Recife", lat=-8.0632)).venue_id == "ze"
+    assert dedup.find_duplicate(_venue("other", "Bar da Maria")) is None
+    assert dedup.find_duplicate(_venue("ze", "Bar do Zé")) is None  # itself
+
+
+def test_cleanup_keeps_the_most_reviewed_and_records_aliases():
+    dao, dedup = _dedup()
+    dao.upsert_venue(_venue("a", "Bar do Zé", reviews=10))
+    dao.upsert_venue(_venue("b", "Bar do Ze - Recife", lat=-8.0632, reviews=250))
+    dao.upsert_venue(_venue("c", "BAR DO ZE", lat=-8.0634))
+    dao.upsert_venue(_venue("d", "Bar da Maria"))
+
+    dry = dedup.run(dry_run=True)
+    assert (dry.venues_checked, dry.clusters, dry.venues_merged) == (4, 1, 2)
+    assert dedup.aliases() == {} and dao.get_venue("a").is_active()
+
+    summary = dedup.run(dry_run=False)
+
+    assert summary.merged == {"a": "b", "c": "b"}
+    assert dao.get_venue("a").is_deprecated() and dao.get_venue("a").deprecated_reason == "duplicate"
+    assert dao.get_venue("b").is_active() and dao.get_venue("d").is_active()
+    assert dedup.aliases() == {"a": "b", "c": "b"}
+    assert dedup.last_summary is summary
+    assert dedup.run(dry_run=False).venues_merged == 0
+
+
+def test_a_merge_moves_references_to_the_kept_venue():
+    dao, dedup = _dedup()
+    redis_client = dedup.redis
+    dao.upsert_venue(_venue("a", "Bar do Zé", reviews=10))
+    dao.upsert_venue(_venue("b", "Bar do Ze - Recife", lat=-8.0632, reviews=250))
+    rds_store = InMemoryRdsVenueStore()
+    engagement = EngagementService(redis_client, rds_store, pseudonymization_key="k")
+    engagement.add_favorite("u1", "a")
+    engagement.add_favorite("u2", "b")
+    engagement.add_hot_like("u1", "a")
+    checkins = CheckinService(redis_client, min_count=1)
+    checkins.record("a", "device", "10.0.0.1")
+    checkins.record("b", "other-device", "10.0.0.2")
+    notes = VenueNotesService(AdminConfigService(
+        redis_client, rds_store=rds_store, validators={"venue_notes": validate_venue_notes_config},
+    ))
+    notes.set_note("a", "Live music on Fridays", public=True)
+    partners = PartnerOccupancyService(
+        dao, api_keys={"key": "club"}, partner_venues={"club": ["a"]},
+        now_fn=lambda: datetime.now(timezone.utc),
+    )
+    partners.set_alias_resolver(dedup.alias_of)
+    for service in (engagement, checkins, notes):
+        dedup.add_reference_mover(service.move_venue)
+
+    assert dedup.run(dry_run=False).merged == {"a": "b"}
+
+    assert redis_client.smembers("user_favorites:u1") == {"b"}
+    assert rds_store.active_favorite_venue_ids(engagement.pseudonymize("u1")) == ["b"]
+    assert redis_client.smembers("hot_likes:v1:b") == {"u1"}
+    assert redis_client.ttl("hot_likes:v1:b") > 0
+    assert checkins.recent_counts(["a", "b"]) == {"b": 2}
+    assert notes.list_notes().keys() == {"b"}
+    # A partner configured for the duplicate reports for the kept venue.
+    assert "b" in partners.venue_ids()
+    assert partners.ingest("club", "a", 10, 100).venue_info.venue_id == "b"
+    assert dao.get_partner_live("b") is not None
+
+
+def test_a_failed_move_leaves_the_duplicate_for_the_next_run():
+    dao, dedup = _dedup()
+    dao.upsert_venue(_venue("a", "Bar do Zé", reviews=10))
+    dao.upsert_venue(_venue("b", "Bar do Ze - Recife", lat=-8.0632, reviews=250))
+    dedup.add_reference_mover(Mock(side_effect=RuntimeError("redis down")))
+
+    summary = dedup.run(dry_run=False)
+
+    assert (summary.errors, summary.venues_merged) == (1, 0)
+    assert dao.get_venue("a").is_active() and dedup.aliases() == {}
+
+
+def test_aliases_stay_one_hop():
+    redis_client = fakeredis.FakeRedis(decode_responses=True)
+    dedup = VenueDeduplicator(Mock(), redis_client)
+    redis_client.hset(ALIASES_KEY, "old", "dup")
+
+    dedup._record_alias("dup", "kept")
+
+    assert dedup.aliases() == {"old": "kept", "dup": "kept"}
+
+
+@pytest.mark.asyncio
+async def test_refresh_skips_a_new_venue_that_duplicates_an_existing_one():
+    dao, dedup = _dedup()
+    dao.upsert_venue(_venue("ven_ze", "Bar do Zé"))
+    api = Mock()
+    api.venue_filter = AsyncMock(return_value=VenueFilterResponse.model_validate({
+        "status": "OK",
+        "venues_n": 2,
+        "venues": [
+            {"venue_id": "ven_ze2", "venue_name": "Bar do Ze - Recife", "day_int": 0, "day_raw": [],
+             "venue_lat": -8.0632, "venue_lng": -34.8711, "venue_address": "Rua da Moeda"},
+            {"venue_id": "ven_new", "venue_name": "Bar da Maria", "day_int": 0, "day_raw": [],
+             "venue_lat": -8.0632, "venue_lng": -34.8711, "venue_address": "Rua da Moeda"},
+        ],
+    }))
+    refresher = VenuesRefresherService(dao, api)
+    refresher.set_deduplicator(dedup)
+
+    ids = await refresher.discover_and_upsert_venues_via_filter(
+        VenueFilterParams(lat=-8.06, lng=-34.87, radius=1000)
+    )
+
+    assert ids == ["ven_ze", "ven_new"]
+    assert dao.get_venue("ven_ze2") is None
+    assert dedup.alias_of("ven_ze2") == "ven_ze"
+
+
+def test_config_errors():
+    assert Settings(besttime_mode="replay", dedup_enabled=True).config_errors() == []
+    errors = Settings(
+        besttime_mode="replay", dedup_max_distance_m=0, dedup_min_name_similarity=1.5
+    ).config_errors()
+    assert "dedup_max_distance_m must be positive" in errors
+    assert "dedup_min_name_similarity must be in (0, 1]" in errors
@@ -0,0 +1,140 @@
+"""POST /admin/venues/import (app/services/venue_import.py)."""
+import importlib
+import json
+from types import SimpleNamespace
+
+import fakeredis
+from fastapi import FastAPI
+from fastapi.testclient import TestClient
+
+from app.dao.redis_venue_dao import RedisVenueDAO
+from app.dao.venue_budget_dao import VenueBudgetDao
+from app.db.geo_redis_client import GeoRedisClient
+from app.models import Venue
+from app.services.event_publishing import EventOutbox
+from app.services.venue_budget_service import ADMIN_CONFIG_BUDGET_KEY, VenueBudgetService
+
+admin_trigger_router = importlib.import_module("app.routers.admin_trigger_router")
+
+CSV = """venue_id,venue_name,venue_address,venue_lat,venue_lon,venue_type,price_level
+ven_partner_1,Bar do Parceiro,Rua da Moeda 10,-8.063,-34.872,BAR,2
+ven_partner_2,Restaurante Parceiro,,-8.120,-34.900,,
+"""
+
+
+def _client(outbox=None, budget=None):
+    redis_client = fakeredis.FakeRedis(decode_responses=True)
+    dao = RedisVenueDAO(GeoRedisClient(redis_client))
+    admin_trigger_router.set_container(SimpleNamespace(
+        pipeline_repository=dao, event_outbox=outbox, venue_budget_service=budget,
+    ))
+    app = FastAPI()
+    app.include_router(admin_trigger_router.router)
+    return TestClient(app), dao
+
+
+def test_csv_import_upserts_every_row():
+    client, dao = _client()
+
+    response = client.post(
+        "/admin/venues/import", content=CSV, headers={"Content-Type": "text/csv"}
+    )
+
+    assert response.status_code == 200
+    assert response.json() == {
+        "dry_run": False, "venues": 2, "new_venues": 2, "live_forecasts": 0,
+        "weekly_forecasts": 0, "failed": {},
+    }
+    venue = dao.get_venue("ven_partner_1")
+    assert (venue.venue_lng, venue.price_level, venue.venue_type) == (-34.872, 2, "BAR")
+    assert venue.refreshed_at is not None
+    assert dao.get_venue("ven_partner_2").venue_type is None
+
+
+def test_json_import_writes_forecasts_and_records_events():
+    outbox = EventOutbox(fakeredis.FakeRedis(decode_responses=True))
+    client, dao = _client(outbox)
+    payload = {
+        "venues": [{"venue_id": "v1", "venue_name": "Bar", "venue_lat": -8.05, "venue_lng": -34.88}],
+        "live_forecasts": [
+            {"status": "OK", "venue_info": {"venue_id": "v1"},
+             "analysis": {"venue_live_busyness": 40, "venue_live_busyness_available": True}},
+            # Not part of this import: skipped.
+            {"status": "OK", "venue_info": {"venue_id": "other"},
+             "analysis": {"venue_live_busyness": 90, "venue_live_busyness_available": True}},
+        ],
+    }
+
+    body = client.post("/admin/venues/import", json=payload).json()
+
+    assert (body["venues"], body["live_forecasts"]) == (1, 1)
+    assert dao.get_live_forecast("v1").analysis.venue_live_busyness == 40
+    assert dao.get_live_forecast("other") is None
+    assert [json.loads(raw)["venue_id"] for raw in outbox.peek(10)] == ["v1"]
+
+
+def test_invalid_rows_reject_the_whole_import():
+    client, dao = _client()
+    payload = [
+        {"venue_id": "v1", "venue_name": "Bar", "venue_lat": -8.05, "venue_lng": -34.88},
+        {"venue_id": "v1", "venue_name": "Bar", "venue_lat": -8.05, "venue_lng": -34.88},
+        {"venue_name": "no id", "venue_lat": 95, "venue_lng": -34.88},
+        {"venue_id": "v4"},
+        {"venue_id": "v5", "venue_lat": 0, "venue_lng": -34.88},
+    ]
+
+    response = client.post("/admin/venues/import", json=payload)
+
+    assert response.status_code == 422
+    errors = response.json()["detail"]["errors"]
+    assert errors[0].startswith("venues[3]:")  # model errors come first
+    assert errors[1:] == [
+        "venue v1: duplicate venue_id",
+        "venue 'no id': missing venue_id",
+        "venue 'no id': coordinates out of range (95.0, -34.88)",
+        "venue v5: empty venue_name",
+        "venue v5: zero coordinate (0.0, -34.88)",
+    ]
+    assert dao.get_venue("v1") is None
+
+
+def test_dry_run_and_bad_payloads_write_nothing():
+    client, dao = _client()
+
+    dry = client.post("/admin/venues/import?dry_run=true", content=CSV,
+                      headers={"Content-Type": "text/csv"})
+    unknown = client.post("/admin/venues/import", content="venue_id,capacity\nv1,10\n",
+                          headers={"Content-Type": "text/csv"})
+    not_json = client.post("/admin/venues/import", content="{",
+                           headers={"Content-Type": "application/json"})
+
+    assert dry.json() == {"dry_run": True, "venues": 2, "live_forecasts": 0, "weekly_forecasts": 0}
+    assert dao.get_venue("ven_partner_1") is None
+    assert "unknown CSV columns capacity" in unknown.json()["detail"]["errors"][0]
+    assert not_json.json()["detail"]["errors"][0].startswith("payload is not valid JSON")
+
+
+def test_new_venues_are_charged_to_the_monthly_budget():
+    redis_client = fakeredis.FakeRedis(decode_responses=True)
+    redis_client.set(ADMIN_CONFIG_BUDGET_KEY, '{"monthly_quota": 3, "manual_reserve": 1}')
+    budget = VenueBudgetService(redis_client, VenueBudgetDao(redis_client))
+    client, dao = _client(budget=budget)
+    dao.upsert_venue(Venue(venue_id="ven_partner_1", venue_name="Bar do Parceiro",
+                           venue_lat=-8.063, venue_lng=-34.872))
+
+    # Only ven_partner_2 is new; an import may use the manual reserve.
+    first = client.post("/admin/venues/import", content=CSV, headers={"Content-Type": "text/csv"})
+    assert (first.json()["venues"], first.json()["new_venues"]) == (2, 1)
+    assert budget.get_snapshot().month_counter == 1
+
+    too_many = [
+        {"venue_id": f"v{i}", "venue_name": "Bar", "venue_lat": -8.05, "venue_lng": -34.88}
+        for i in range(3)
+    ]
+    refused = client.post("/admin/venues/import", json=too_many)
+    assert refused.status_code == 429
+    assert refused.json()["detail"]["errors"] == [
+        "import adds 3 new venues but only 2 remain in this month's new-venue budget"
+    ]
+    assert dao.get_venue("v0") is None
+    assert budget.get_snapshot().month_counter == 1
@@ -0,0 +1,129 @@
+"""Unit tests for venue photos and links (app/services/venue_media.py) and
+their admin, public and nearby routes."""
+import importlib
+from datetime import datetime, timezone
+from types import SimpleNamespace
+
+import fakeredis
+import pytest
+from fastapi import FastAPI
+from fastapi.testclient import TestClient
+
+from app.config import settings
+from app.dao.redis_venue_dao import RedisVenueDAO
+from app.db.geo_redis_client import GeoRedisClient
+from app.handlers.venue_handler import VenueHandler, nearby_response_exclude
+from app.models import OpenDataEnrichment, Venue
+from app.models.instagram import VenueInstagram
+from app.routers.venue_router import router as venue_router, set_venue_handler
+from app.services import venue_media
+from app.services.venue_media import (
+    EnrichmentMediaProvider,
+    VenueMediaService,
+    build_media_provider,
+    media_errors,
+    social_network_for,
+)
+
+admin_trigger_router = importlib.import_module("app.routers.admin_trigger_router")
+
+
+def _open_data(website, photos=()):
+    return OpenDataEnrichment(
+        source="osm", source_id="node/1", matched_name="x", match_distance_m=5.0,
+        match_score=1.0, website=website, photos=list(photos), enriched_at=datetime.now(timezone.utc),
+    )
+
+
+def _service(monkeypatch):
+    monkeypatch.setattr(venue_media, "REQUEST_DELAY", 0)
+    dao = RedisVenueDAO(GeoRedisClient(fakeredis.FakeRedis(decode_responses=True)))
+    photos = [f"https://upload.wikimedia.org/bardoze_{i}.jpg" for i in range(3)]
+    for venue_id, website, venue_photos in (
+        ("site", "https://bardoze.com.br", photos),
+        ("fb_only", "https://www.facebook.com/bardoze", ()),
+        ("bare", None, ()),
+    ):
+        dao.upsert_venue(Venue(
+            venue_id=venue_id, venue_name=venue_id, venue_lat=-8.05, venue_lng=-34.88,
+            open_data=_open_data(website, venue_photos) if website else None,
+        ))
+    dao.set_venue_instagram(VenueInstagram(
+        venue_id="site", instagram_handle="bardoze",
+        instagram_url="https://instagram.com/bardoze", status="found",
+    ))
+    return VenueMediaService(dao, EnrichmentMediaProvider(dao, max_photos=2), max_photos=2)
+
+
+def test_social_links_and_validation():
+    assert social_network_for("https://m.facebook.com/bardoze") == "facebook"
+    assert social_network_for("https://twitter.com/bardoze") == "x"
+    assert social_network_for("https://bardoze.com.br") is None
+    assert media_errors(["https://cdn.example/a.jpg"], None, {"tiktok": "https://tiktok.com/@b"}, 2) == []
+    assert media_errors(
+        ["ftp://a", "https://b", "https://c"], "bardoze.com", {"orkut": "https://orkut.com/b"}, 2
+    ) == [
+        "at most 2 photos",
+        "photo 'ftp://a' is not an http(s) URL",
+        "website 'bardoze.com' is not an http(s) URL",
+        "unknown social network 'orkut'; expected one of instagram, facebook, tiktok, x, youtube",
+    ]
+    assert build_media_provider("none", None) is None
+    with pytest.raises(ValueError):
+        build_media_provider("flickr", None)
+
+
+@pytest.mark.asyncio
+async def test_enrichment_keeps_curated_media(monkeypatch):
+    service = _service(monkeypatch)
+    service.set_media("bare", ["https://cdn.example/bare.jpg"])
+
+    summary = await service.enrich_all_venues()
+
+    assert summary == {"enriched": 2, "no_media": 0, "error": 0, "skipped": 1}
+    site = service.get_media("site")[1]
+    assert (site.website, site.social_links, site.source) == (
+        "https://bardoze.com.br", {"instagram": "https://instagram.com/bardoze"}, "enrichment",
+    )
+    # Open-data photos, capped at max_photos.
+    assert site.photos == ["https://upload.wikimedia.org/bardoze_0.jpg", "https://upload.wikimedia.org/bardoze_1.jpg"]
+    fb_only = service.get_media("fb_only")[1]
+    assert (fb_only.website, fb_only.social_links, fb_only.photos) == (
+        None, {"facebook": "https://www.facebook.com/bardoze"}, [],
+    )
+    assert service.get_media("bare")[1].source == "admin"
+    # Curated media survives a forced run; cleared media is fetched again.
+    assert (await service.enrich_all_venues(force_refresh=True))["skipped"] == 1
+    assert service.clear_media("bare") is True
+    assert (await service.enrich_all_venues())["no_media"] == 1
+    assert service.get_media("nope") == (False, None)
+
+
+def test_routes(monkeypatch):
+    service = _service(monkeypatch)
+    admin = FastAPI()
+    admin.include_router(admin_trigger_router.router)
+    admin_trigger_router.set_container(SimpleNamespace(venue_media_service=service))
+    client = TestClient(admin)
+    body = {"photos": ["https://cdn.example/a.jpg"], "social_links": {"instagram": "https://instagram.com/b"}}
+
+    assert client.put("/admin/venues/bare/media", json=body).json()["media"]["source"] == "admin"
+    assert client.put("/admin/venues/bare/media", json={"website": "nope"}).status_code == 400
+    assert client.put("/admin/venues/nope/media", json=body).status_code == 404
+    assert client.get("/admin/venues/site/media").json() == {"venue_id": "site", "media": None}
+
+    public = FastAPI()
+    public.include_router(venue_router)
+    set_venue_handler(VenueHandler(service.venue_dao))
+    assert TestClient(public).get("/v1/venues/bare/media").json()["photos"] == ["https://cdn.example/a.jpg"]
+    assert TestClient(public).get("/v1/venues/site/media").status_code == 404
+    assert "media" in nearby_response_exclude()
+    monkeypatch.setattr(settings, "nearby_media_enabled", True)
+    assert "media" not in nearby_response_exclude()
+    nearby = {v["venue_id"]: v for v in TestClient(public).get(
+        "/v1/venues/nearby", params={"lat": -8.05, "lon": -34.88, "radius": 1},
+    ).json()}
+    assert nearby["bare"]["media"]["photos"] == ["https://cdn.example/a.jpg"]
+
+    assert client.delete("/admin/venues/bare/media").json() == {"status": "ok", "venue_id": "bare"}
+    assert TestClient(public).get("/v1/venues/bare/media").status_code == 404
@@ -0,0 +1,138 @@
+"""Unit tests for per-venue operator notes (app/services/venue_notes.py)."""
+from types import SimpleNamespace
+from unittest.mock import MagicMock
+
+import fakeredis
+import pytest
+from fastapi import FastAPI
+from fastapi.testclient import TestClient
+
+from app.dao.redis_venue_dao import RedisVenueDAO
+from app.db.geo_redis_client import GeoRedisClient
+from app.handlers import VenueHandler
+from app.models import Venue
+from app.routers.admin_trigger_router import router, set_container
+from app.services.admin_config_service import AdminConfigService
+from app.services.venue_notes import (
+    VenueNotesService,
+    load_public_status_notes,
+    validate_venue_notes_config,
+)
+from tests.rds_fake import InMemoryRdsVenueStore
+
+_LAT, _LNG = -8.05, -34.88
+
+
+def _admin_config():
+    return AdminConfigService(
+        fakeredis.FakeRedis(decode_responses=True),
+        rds_store=InMemoryRdsVenueStore(),
+        validators={"venue_notes": validate_venue_notes_config},
+    )
+
+
+def test_set_and_delete_note_persist_through_admin_config():
+    config = _admin_config()
+    service = VenueNotesService(config)
+
+    stored = service.set_note("v1", "Closed for renovation until July", public=True)
+
+    assert stored["note"] == "Closed for renovation until July"
+    assert stored["updated_at"] is not None
+    assert config.rds_store.get_admin_config("venue_notes")["value"]["v1"]["public"] is True
+    assert service.delete_note("v1") is True
+    assert service.delete_note("v1") is False
+    assert service.list_notes() == {}
+
+
+def test_concurrent_edits_of_different_venues_are_both_kept():
+    config = _admin_config()
+    service = VenueNotesService(config)
+    service.set_note("v1", "Closed until July")
+    # Another operator's edit committed in RDS; its mirror write has not landed yet.
+    notes = config.rds_store.get_admin_config("venue_notes")["value"]
+    notes["v3"] = {**notes["v1"], "note": "Live data missing on Sundays"}
+    config.rds_store.upsert_admin_config("venue_notes", notes)
+
+    service.set_note("v2", "BestTime data unreliable here")
+
+    assert set(service.list_notes()) == {"v1", "v2", "v3"}
+    assert set(config.rds_store.get_admin_config("venue_notes")["value"]) == {"v1", "v2", "v3"}
+
+
+def test_a_failed_mirror_write_leaves_rds_unchanged():
+    config = _admin_config()
+    service = VenueNotesService(config)
+    service.set_note("v1", "Closed until July")
+    config.redis = MagicMock(wraps=config.redis)
+    config.redis.set.side_effect = ConnectionError("redis down")
+
+    with pytest.raises(ConnectionError):
+        service.set_note("v2", "BestTime data unreliable here")
+
+    assert set(config.rds_store.get_admin_config("venue_notes")["value"]) == {"v1"}
+
+
+def test_validator_rejects_bad_notes():
+    with pytest.raises(TypeError):
+        validate_venue_notes_config(["not", "an", "object"])
+    with pytest.raises(ValueError):
+        validate_venue_notes_config({"v1": {"note": ""}})
+    with pytest.raises(ValueError):
+        validate_venue_notes_config({"v1": {"note": "x" * 501}})
+
+
+def test_only_public_notes_are_served():
+    config = _admin_config()
+    service = VenueNotesService(config)
+    service.set_note("v1", "BestTime data unreliable here", public=False)
+    service.set_note("v2", "Closed until July", public=True)
+
+    assert load_public_status_notes(config) == {"v2": "Closed until July"}
+    assert load_public_status_notes(None) == {}
+
+
+def test_nearby_response_carries_public_status_note():
+    config = _admin_config()
+    VenueNotesService(config).set_note("v1", "Closed until July", public=True)
+    dao = RedisVenueDAO(GeoRedisClient(fakeredis.FakeRedis(decode_responses=True)))
+    dao.upsert_venues([
+        Venue(venue_id=vid, venue_name=vid, venue_address="a", venue_lat=_LAT, venue_lng=_LNG)
+        for vid in ("v1", "v2")
+    ])
+
+    result = VenueHandler(dao, admin_config_service=config).get_venues_nearby(_LAT, _LNG, 1.0)
+
+    assert {r.venue_id: r.status_note for r in result} == {"v1": "Closed until July", "v2": None}
+
+
+def test_admin_endpoints():
+    service = VenueNotesService(_admin_config())
+    set_container(SimpleNamespace(venue_notes_service=service))
+    app = FastAPI()
+    app.include_router(router)
+    client = TestClient(app)
+
+    response = client.put("/admin/venues/v1/note", json={"note": "Unreliable data", "public": False})
+    assert response.status_code == 200
+    assert response.json()["note"] == "Unreliable data"
+    assert client.put("/admin/venues/v1/note", json={"note": ""}).status_code == 422
+    assert client.get("/admin/venues/notes").json()["notes"]["v1"]["public"] is False
+    assert client.delete("/admin/venues/v1/note").status_code == 200
+    assert client.delete("/admin/venues/v1/note").status_code == 404
+
+
+def test_verified_note_endpoints_read_the_notes_back():
+    config = _admin_config()
+    set_container(SimpleNamespace(venue_notes_service=VenueNotesService(config)))
+    app = FastAPI()
+    app.include_router(router)
+    client = TestClient(app)
+
+    response = client.put("/admin/venues/v1/note?verify=true", json={"note": "Closed until July"})
+    assert response.status_code == 200 and response.json()["verified"] is True
+
+    config.redis = MagicMock(wraps=config.redis)
+    config.redis.set.return_value = True  # acknowledged but never landed
+    assert client.put("/admin/venues/v2/note?verify=true", json={"note": "x"}).status_code == 502
+    assert client.delete("/admin/venues/v1/note?verify=true").status_code == 502
@@ -0,0 +1,72 @@
+"""Unit tests for occupancy estimation (app/services/venue_occupancy.py) and
+estimated_occupancy on nearby items."""
+from datetime import datetime, timezone
+from unittest.mock import patch
+
+import fakeredis
+
+from app.config import settings
+from app.dao.redis_venue_dao import RedisVenueDAO
+from app.db.geo_redis_client import GeoRedisClient
+from app.handlers.venue_handler import VenueHandler, nearby_response_exclude
+from app.models import LiveForecastResponse, Venue
+from app.services.venue_occupancy import estimate_occupancy, venue_capacity
+
+_NOW = datetime(2026, 10, 16, 22, 0, tzinfo=timezone.utc)
+
+
+def _venue(venue_id="v1", venue_type="BAR", dwell=(None, None)):
+    return Venue(
+        venue_id=venue_id, venue_name="Bar", venue_lat=-8.05, venue_lng=-34.88,
+        venue_type=venue_type, venue_dwell_time_min=dwell[0], venue_dwell_time_max=dwell[1],
+    )
+
+
+def test_capacity_comes_from_hint_then_type_then_default(monkeypatch):
+    monkeypatch.setattr(settings, "occupancy_capacity_by_type", {"BAR": 80})
+    monkeypatch.setattr(settings, "occupancy_default_capacity", 50)
+    monkeypatch.setattr(settings, "area_capacity_hints", {"known": 120})
+
+    assert venue_capacity(_venue("known")) == 120
+    assert venue_capacity(_venue(venue_type="bar")) == 80
+    assert venue_capacity(_venue(venue_type=None)) == 50
+
+
+def test_short_dwell_scales_the_estimate_down(monkeypatch):
+    monkeypatch.setattr(settings, "occupancy_capacity_by_type", {"BAR": 80})
+    monkeypatch.setattr(settings, "occupancy_reference_dwell_minutes", 60)
+
+    assert estimate_occupancy(_venue(), 50) == 40
+    assert estimate_occupancy(_venue(dwell=(20, 40)), 50) == 20
+    # Longer stays never exceed capacity; only one bound known is used alone.
+    assert estimate_occupancy(_venue(dwell=(90, 180)), 100) == 80
+    assert estimate_occupancy(_venue(dwell=(None, 15)), 100) == 20
+    assert estimate_occupancy(_venue(), None) is None
+
+
+def _dao():
+    dao = RedisVenueDAO(GeoRedisClient(fakeredis.FakeRedis(decode_responses=True)))
+    for venue_id, gmttime in (("fresh", _NOW), ("old", datetime(2026, 10, 16, 12, 0, tzinfo=timezone.utc))):
+        dao.upsert_venue(_venue(venue_id))
+        dao.set_live_forecast(LiveForecastResponse.model_validate({
+            "status": "OK",
+            "analysis": {"venue_live_busyness": 50, "venue_live_busyness_available": True},
+            "venue_info": {"venue_id": venue_id, "venue_current_gmttime": gmttime.isoformat()},
+        }))
+    return dao
+
+
+def test_nearby_estimated_occupancy(monkeypatch):
+    handler = VenueHandler(_dao())
+    monkeypatch.setattr(settings, "occupancy_capacity_by_type", {"BAR": 80})
+    assert "estimated_occupancy" in nearby_response_exclude()
+
+    monkeypatch.setattr(settings, "nearby_occupancy_enabled", True)
+    with patch("app.handlers.venue_handler.utc_now", return_value=_NOW):
+        verbose = handler.get_venues_nearby(-8.05, -34.88, 1, verbose=True)
+        minified = handler.get_venues_nearby(-8.05, -34.88, 1)
+
+    assert "estimated_occupancy" not in nearby_response_exclude()
+    # A stale live value gives no estimate, as it gives no live busyness.
+    assert {v.venue.venue_id: v.estimated_occupancy for v in verbose} == {"fresh": 40, "old": None}
+    assert {v.venue_id: v.estimated_occupancy for v in minified} == {"fresh": 40, "old": None}
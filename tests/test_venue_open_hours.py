@@ -0,0 +1,131 @@
+"""Unit tests for open-now from stored hours: skipping closed venues in the live
+refresh and `open_now` on nearby (app/services/venue_open_hours.py)."""
+from datetime import datetime, timezone
+from unittest.mock import Mock, patch
+
+import fakeredis
+
+from app.config import settings
+from app.dao.redis_venue_dao import RedisVenueDAO
+from app.db.geo_redis_client import GeoRedisClient
+from app.handlers.venue_handler import VenueHandler
+from app.models import (
+    DayInfo,
+    DayInfoV2,
+    LiveForecastResponse,
+    OpenCloseDetail,
+    Venue,
+    WeekRawDay,
+)
+from app.services.venue_open_hours import open_at, open_now_bulk
+from app.services.venues_refresher_service import VenuesRefresherService
+from app.utils.recife_time import RECIFE_TZ
+from app.utils.venue_time import venue_local_time
+
+
+def _day(day_int, *periods, open_24h=None, closed=False):
+    return WeekRawDay(
+        day_int=day_int,
+        day_raw=[0] * 24 if closed else [40] * 24,
+        day_info=DayInfo(
+            day_int=day_int,
+            venue_open="Closed" if closed else "",
+            venue_open_close_v2=DayInfoV2(
+                open_24h=open_24h,
+                h24=[OpenCloseDetail(opens=o, closes=c) for o, c in periods],
+            ),
+        ),
+    )
+
+
+def test_open_at_checks_today_and_yesterdays_late_hours():
+    lunch_and_dinner = _day(2, (11, 15), (18, 23))
+    until_two = _day(1, (20, 2))
+
+    assert open_at(lunch_and_dinner, until_two, 12 * 60) is True
+    assert open_at(lunch_and_dinner, until_two, 16 * 60) is False
+    assert open_at(lunch_and_dinner, until_two, 16 * 60, margin_minutes=60) is True
+    # 01:00 Wednesday is still Tuesday night.
+    assert open_at(lunch_and_dinner, until_two, 60) is True
+    assert open_at(lunch_and_dinner, _day(1, closed=True), 60) is False
+    assert open_at(_day(2, open_24h=True), _day(1, closed=True), 4 * 60) is True
+
+
+def test_open_at_is_unknown_without_stored_hours():
+    assert open_at(None, _day(1, (20, 2)), 12 * 60) is None
+    no_hours = WeekRawDay(day_int=2, day_raw=[30] * 24)
+    assert open_at(no_hours, _day(1, (20, 2)), 12 * 60) is None
+
+
+def test_live_refresh_skips_only_venues_known_to_be_closed():
+    # Wednesday 10:00 in Recife: day_int 2, yesterday 1.
+    now = RECIFE_TZ.localize(datetime(2026, 10, 14, 10, 0))
+    dao = Mock()
+    dao.get_week_raw_forecasts_bulk.side_effect = lambda ids, day_int: {
+        2: {"cafe": _day(2, (7, 14)), "bar": _day(2, (18, 2))},
+        1: {"cafe": _day(1, (7, 14)), "bar": _day(1, (18, 2))},
+    }[day_int]
+    refresher = VenuesRefresherService(dao, Mock(), live_skip_closed=True)
+
+    assert refresher._skip_closed_venues(["cafe", "bar", "new"], now=now) == ["cafe", "new"]
+
+    refresher.live_skip_closed = False
+    assert refresher._skip_closed_venues(["cafe", "bar"], now=now) == ["cafe", "bar"]
+
+
+def test_day_info_open_windows():
+    assert _day(2, (18, 2)).day_info.open_windows() == [(18 * 60, 26 * 60)]
+    assert _day(2, open_24h=True).day_info.open_windows() == [(0, 24 * 60)]
+    assert _day(2, closed=True).day_info.open_windows([0] * 24) == []
+    assert DayInfo(day_int=2).open_windows([30] * 24) is None
+
+
+def test_open_now_uses_each_venues_timezone():
+    # Wednesday 23:30 UTC: 20:30 in Recife, 19:30 in Manaus, Thursday 00:30 in Lisbon.
+    now_utc = datetime(2026, 10, 14, 23, 30, tzinfo=timezone.utc)
+    days = {
+        2: {"recife": _day(2, (18, 21)), "manaus": _day(2, (20, 23)), "lisbon": _day(2, (22, 1))},
+        1: {"recife": _day(1, closed=True), "manaus": _day(1, closed=True)},
+        3: {"lisbon": _day(3, closed=True)},
+    }
+    calls = []
+
+    def load_day(ids, day_int):
+        calls.append(day_int)
+        return {vid: d for vid, d in days.get(day_int, {}).items() if vid in ids}
+
+    result = open_now_bulk(
+        {"recife": None, "manaus": "America/Manaus", "lisbon": "Europe/Lisbon", "unknown": "Nowhere/City"},
+        now_utc,
+        load_day,
+    )
+
+    assert result == {"recife": True, "manaus": False, "lisbon": True, "unknown": None}
+    assert sorted(calls) == [1, 2, 3]
+    assert venue_local_time("Nowhere/City", now_utc).hour == 20  # Recife fallback
+
+
+def test_nearby_open_now_field_and_filter(monkeypatch):
+    monkeypatch.setattr(settings, "nearby_open_now_enabled", True)
+    dao = RedisVenueDAO(GeoRedisClient(fakeredis.FakeRedis(decode_responses=True)))
+    for vid in ("bar", "cafe", "new"):
+        dao.upsert_venue(Venue(venue_id=vid, venue_name=vid, venue_lat=-8.05, venue_lng=-34.88))
+    for day_int in (1, 2):
+        dao.set_week_raw_forecast("bar", _day(day_int, (18, 2)))
+        dao.set_week_raw_forecast("cafe", _day(day_int, (7, 14)))
+    dao.set_live_forecast(LiveForecastResponse.model_validate({
+        "status": "OK", "analysis": {},
+        "venue_info": {"venue_id": "bar", "venue_timezone": "America/Recife"},
+    }))
+    handler = VenueHandler(dao)
+    # Wednesday 22:00 in Recife.
+    now_utc = datetime(2026, 10, 15, 1, 0, tzinfo=timezone.utc)
+
+    with patch("app.handlers.venue_handler.utc_now", return_value=now_utc):
+        everything = handler.get_venues_nearby(-8.05, -34.88, 1, verbose=True)
+        open_only = handler.get_venues_nearby(-8.05, -34.88, 1, verbose=False, open_now=True)
+
+    assert {m.venue.venue_id: m.open_now for m in everything} == {
+        "bar": True, "cafe": False, "new": None,
+    }
+    assert [(v.venue_id, v.open_now) for v in open_only] == [("bar", True)]
@@ -0,0 +1,79 @@
+"""Unit tests for the nearby score (app/services/venue_score.py) and
+GET /v1/venues/nearby?sort=score."""
+from datetime import datetime, timezone
+from unittest.mock import patch
+
+import fakeredis
+from fastapi import FastAPI
+from fastapi.testclient import TestClient
+
+from app.config import Settings, settings
+from app.dao.redis_venue_dao import RedisVenueDAO
+from app.db.geo_redis_client import GeoRedisClient
+from app.handlers.venue_handler import VenueHandler
+from app.models import LiveForecastResponse, Venue
+from app.routers.venue_router import router as venue_router, set_venue_handler
+from app.services.venue_score import score_errors, score_signals, score_venue
+
+_NOW = datetime(2026, 10, 16, 22, 0, tzinfo=timezone.utc)
+
+
+def _venue(venue_id="v1", lat=-8.05, **fields):
+    return Venue(venue_id=venue_id, venue_name=venue_id, venue_lat=lat, venue_lng=-34.88, **fields)
+
+
+def test_signals_and_weighted_score(monkeypatch):
+    monkeypatch.setattr(settings, "score_reviews_saturation", 100)
+    venue = _venue(rating=4.5, reviews=100, price_level=2)
+
+    assert score_signals(venue, 60, 0.5, 2.0) == {
+        "busyness": 0.6, "rating": 0.9, "reviews": 1.0, "price": 2 / 3, "distance": 0.75,
+    }
+    monkeypatch.setattr(settings, "score_weights", {"busyness": 1, "distance": 1})
+    assert score_venue(venue, 60, 0.5, 2.0) == 67.5
+    # Missing data earns nothing; past the radius is as far as it gets.
+    assert score_venue(_venue(), None, 5.0, 2.0) == 0.0
+
+
+def test_weight_validation():
+    assert score_errors({"busyness": 1}) == []
+    assert score_errors({"vibes": 1, "rating": -1}) == [
+        "score_weights: unknown signal 'vibes'", "score_weights: rating must not be negative",
+    ]
+    assert score_errors({"rating": 0}) == ["score_weights: at least one weight must be positive"]
+    assert "score_reviews_saturation must be positive" in Settings(
+        score_reviews_saturation=0
+    ).config_errors()
+
+
+def _client():
+    dao = RedisVenueDAO(GeoRedisClient(fakeredis.FakeRedis(decode_responses=True)))
+    # "busy" has the live crowd; "good" is closer and better rated.
+    dao.upsert_venue(_venue("busy", lat=-8.058, rating=3.0))
+    dao.upsert_venue(_venue("good", lat=-8.05, rating=4.8, reviews=400, price_level=1))
+    dao.set_live_forecast(LiveForecastResponse.model_validate({
+        "status": "OK",
+        "analysis": {"venue_live_busyness": 70, "venue_live_busyness_available": True},
+        "venue_info": {"venue_id": "busy", "venue_current_gmttime": _NOW.isoformat()},
+    }))
+    set_venue_handler(VenueHandler(dao))
+    app = FastAPI()
+    app.include_router(venue_router)
+    return TestClient(app)
+
+
+def test_nearby_sort_by_score():
+    client = _client()
+    params = {"lat": -8.05, "lon": -34.88, "radius": 1}
+
+    with patch("app.handlers.venue_handler.utc_now", return_value=_NOW):
+        default = client.get("/v1/venues/nearby", params=params).json()
+        scored = client.get("/v1/venues/nearby", params={**params, "sort": "score"}).json()
+        verbose = client.get("/v1/venues/nearby", params={**params, "sort": "score", "verbose": True}).json()
+
+    assert [v["venue_id"] for v in default] == ["busy", "good"]
+    assert "score" not in default[0]
+    assert [v["venue_id"] for v in scored] == ["good", "busy"]
+    assert scored[0]["score"] > scored[1]["score"] > 0
+    assert [v["venue"]["venue_id"] for v in verbose] == ["good", "busy"]
+    assert client.get("/v1/venues/nearby", params={**params, "sort": "rating"}).status_code == 422
@@ -0,0 +1,101 @@
+"""Unit tests for user venue suggestions (app/services/venue_suggestions.py)
+and their public and admin routes."""
+import importlib
+from types import SimpleNamespace
+from unittest.mock import AsyncMock, Mock
+
+import fakeredis
+import pytest
+from fastapi import FastAPI
+from fastapi.testclient import TestClient
+
+from app.handlers.add_venue_handler import AddVenueOutcome
+from app.routers.venue_router import router as venue_router, set_venue_suggestion_service
+from app.services.venue_suggestions import (
+    SuggestionQueueFullError,
+    SuggestionStateError,
+    VenueSuggestionService,
+)
+
+admin_trigger_router = importlib.import_module("app.routers.admin_trigger_router")
+
+_CREATED = AddVenueOutcome(status_code=201, body={"status": "created", "venue_id": "ven_new"})
+
+
+def _service(outcome=_CREATED, max_pending=10):
+    handler = Mock()
+    handler.add = AsyncMock(return_value=outcome)
+    raw = fakeredis.FakeRedis(decode_responses=True)
+    return VenueSuggestionService(raw, handler, max_pending=max_pending)
+
+
+def test_submit_dedups_pending_and_bounds_the_queue():
+    service = _service(max_pending=2)
+
+    first = service.submit("Bar do Zé", "Rua X, 10", -8.05, -34.88)
+    again = service.submit(" bar do zé", "RUA X, 10 ", -8.05, -34.88)
+    service.submit("Tasca", "Rua Y", -8.06, -34.89)
+
+    assert again.id == first.id
+    assert service.pending_count() == 2
+    with pytest.raises(SuggestionQueueFullError):
+        service.submit("Third", "Rua Z", -8.07, -34.9)
+    assert [s.venue_name for s in service.entries()] == ["Bar do Zé", "Tasca"]
+
+
+@pytest.mark.asyncio
+async def test_approve_adds_the_venue_once():
+    service = _service()
+    suggestion = service.submit("Bar do Zé", "Rua X, 10", -8.05, -34.88)
+
+    approved, outcome = await service.approve(suggestion.id)
+
+    assert (approved.status, approved.venue_id, outcome.status_code) == ("approved", "ven_new", 201)
+    request = service.add_venue_handler.add.call_args.args[0]
+    assert (request.venue_name, request.venue_lat) == ("Bar do Zé", -8.05)
+    assert service.pending_count() == 0
+    with pytest.raises(SuggestionStateError):
+        await service.approve(suggestion.id)
+    # Decided: the same venue can be suggested again.
+    assert service.submit("Bar do Zé", "Rua X, 10", -8.05, -34.88).id != suggestion.id
+    assert await service.approve("nope") == (None, None)
+
+
+@pytest.mark.asyncio
+async def test_failed_add_leaves_the_suggestion_pending():
+    service = _service(AddVenueOutcome(status_code=429, body={"error": "monthly cap reached"}))
+    suggestion = service.submit("Bar do Zé", "Rua X, 10", -8.05, -34.88)
+
+    still, outcome = await service.approve(suggestion.id)
+
+    assert (still.status, outcome.status_code) == ("pending", 429)
+    rejected = service.reject(suggestion.id, "duplicate of ven_1")
+    assert (rejected.status, rejected.reason) == ("rejected", "duplicate of ven_1")
+    assert [s.id for s in service.entries("rejected")] == [suggestion.id]
+
+
+def test_routes():
+    service = _service()
+    public = FastAPI()
+    public.include_router(venue_router)
+    admin = FastAPI()
+    admin.include_router(admin_trigger_router.router)
+    set_venue_suggestion_service(service)
+    admin_trigger_router.set_container(SimpleNamespace(venue_suggestion_service=service))
+    body = {"venue_name": "Bar do Zé", "venue_address": "Rua X, 10", "venue_lat": -8.05, "venue_lng": -34.88}
+    try:
+        created = TestClient(public).post("/v1/venues/suggest", json=body)
+        assert created.status_code == 202 and created.json()["status"] == "pending"
+        suggestion_id = created.json()["id"]
+
+        client = TestClient(admin)
+        listed = client.get("/admin/venues/suggestions").json()
+        assert listed["pending"] == 1 and listed["entries"][0]["id"] == suggestion_id
+        approved = client.post(f"/admin/venues/suggestions/{suggestion_id}/approve")
+        assert approved.status_code == 201
+        assert approved.json()["suggestion"]["venue_id"] == "ven_new"
+        assert client.post(f"/admin/venues/suggestions/{suggestion_id}/reject").status_code == 409
+        assert client.post("/admin/venues/suggestions/nope/reject").status_code == 404
+    finally:
+        set_venue_suggestion_service(None)
+    assert TestClient(public).post("/v1/venues/suggest", json=body).status_code == 503
@@ -0,0 +1,95 @@
+"""Unit tests for venue-local time and forecast indexes (app/utils/venue_time.py)
+and the forecast-at-time endpoint."""
+from datetime import datetime, timezone
+from unittest.mock import patch
+
+import fakeredis
+from fastapi import FastAPI
+from fastapi.testclient import TestClient
+
+from app.config import settings
+from app.dao.redis_venue_dao import RedisVenueDAO
+from app.db.geo_redis_client import GeoRedisClient
+from app.handlers.venue_handler import VenueHandler, nearby_response_exclude
+from app.models import LiveForecastResponse, Venue, WeekRawDay
+from app.routers.venue_router import router as venue_router, set_venue_handler
+from app.utils.venue_time import forecast_slot, slot_for_local_hour, venue_local_time, venue_tz
+
+# Thursday 23:30 UTC: 20:30 Thursday in Recife, 00:30 Friday in Lisbon (WEST).
+_AT = datetime(2026, 10, 15, 23, 30, tzinfo=timezone.utc)
+
+
+def test_forecast_slot_in_the_venues_timezone():
+    assert forecast_slot(venue_local_time("America/Recife", _AT)) == (3, 14)
+    # Past midnight still belongs to Thursday's day_raw.
+    assert forecast_slot(venue_local_time("Europe/Lisbon", _AT)) == (3, 18)
+    # A naive instant is UTC; an unknown timezone is Recife.
+    assert venue_local_time(None, _AT.replace(tzinfo=None)).hour == 20
+    assert venue_tz("Mars/Olympus").zone == "America/Recife"
+
+
+def test_slot_for_local_hour():
+    assert slot_for_local_hour(3, 6) == (3, 0)
+    assert slot_for_local_hour(3, 23) == (3, 17)
+    assert slot_for_local_hour(4, 0) == (3, 18)
+    assert slot_for_local_hour(0, 5) == (6, 23)
+
+
+def _dao():
+    dao = RedisVenueDAO(GeoRedisClient(fakeredis.FakeRedis(decode_responses=True)))
+    dao.upsert_venue(Venue(venue_id="lx", venue_name="Tasca", venue_lat=38.71, venue_lng=-9.14))
+    dao.set_week_raw_forecast("lx", WeekRawDay(day_int=3, day_raw=list(range(24))))
+    dao.set_live_forecast(LiveForecastResponse.model_validate({
+        "status": "OK", "analysis": {},
+        "venue_info": {"venue_id": "lx", "venue_timezone": "Europe/Lisbon"},
+    }))
+    return dao
+
+
+def _client(dao):
+    set_venue_handler(VenueHandler(dao))
+    app = FastAPI()
+    app.include_router(venue_router)
+    return TestClient(app)
+
+
+def test_forecast_at_converts_timestamps_to_venue_time():
+    client = _client(_dao())
+
+    body = client.get("/v1/venues/lx/forecast/at", params={"at": "2026-10-15T23:30:00Z"}).json()
+    assert (body["tz"], body["day_int"], body["hour_index"], body["busyness"]) == (
+        "Europe/Lisbon", 3, 18, 18,
+    )
+    assert body["local_time"].startswith("2026-10-16T00:30:00+01:00")
+
+    body = client.get("/v1/venues/lx/forecast/at", params={"day_int": 4, "hour": 0}).json()
+    assert (body["day_int"], body["hour_index"], body["local_time"]) == (3, 18, None)
+
+    # No stored forecast for the day: no busyness.
+    assert client.get("/v1/venues/lx/forecast/at?day_int=0&hour=12").json()["busyness"] is None
+
+
+def test_forecast_at_rejects_bad_requests():
+    client = _client(_dao())
+
+    assert client.get("/v1/venues/lx/forecast/at").status_code == 422
+    assert client.get("/v1/venues/lx/forecast/at?day_int=3").status_code == 422
+    assert client.get(
+        "/v1/venues/lx/forecast/at", params={"at": "2026-10-15T23:30:00Z", "hour": 1}
+    ).status_code == 422
+    assert client.get("/v1/venues/lx/forecast/at?day_int=7&hour=1").status_code == 422
+    assert client.get("/v1/venues/nope/forecast/at?day_int=3&hour=1").status_code == 404
+
+
+def test_nearby_tz_and_local_time(monkeypatch):
+    dao = _dao()
+    handler = VenueHandler(dao)
+
+    with patch("app.handlers.venue_handler.utc_now", return_value=_AT):
+        assert "local_time" in nearby_response_exclude()
+        monkeypatch.setattr(settings, "nearby_local_time_enabled", True)
+        [venue] = handler.get_venues_nearby(38.71, -9.14, 1, verbose=True)
+
+    assert "local_time" not in nearby_response_exclude()
+    assert venue.tz == "Europe/Lisbon"
+    assert venue.local_time.isoformat() == "2026-10-16T00:30:00+01:00"
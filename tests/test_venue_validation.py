@@ -0,0 +1,153 @@
+"""Venue validation and the quarantine list (app/services/venue_validation.py)."""
+import importlib
+from types import SimpleNamespace
+from unittest.mock import AsyncMock, Mock
+
+import fakeredis
+import pytest
+from fastapi import FastAPI
+from fastapi.testclient import TestClient
+
+from app.dao.redis_venue_dao import RedisVenueDAO
+from app.dao.venue_repository import VenueRepository
+from app.db.geo_redis_client import GeoRedisClient
+from app.models import Venue, VenueFilterParams, VenueFilterResponse
+from app.services.venue_validation import (
+    VenueQuarantine,
+    VenueRejectedError,
+    VenueValidator,
+    venue_problems,
+)
+from app.services.venues_refresher_service import VenuesRefresherService
+from tests.rds_fake import InMemoryRdsVenueStore
+
+admin_trigger_router = importlib.import_module("app.routers.admin_trigger_router")
+
+
+def _venue(vid="v1", name="Bar do Zé", lat=-8.06, lng=-34.87, **extra):
+    return Venue(venue_id=vid, venue_name=name, venue_address="Rua X", venue_lat=lat, venue_lng=lng, **extra)
+
+
+def _quarantine(max_entries=1000):
+    return VenueQuarantine(fakeredis.FakeRedis(decode_responses=True), max_entries=max_entries)
+
+
+def test_problems():
+    assert venue_problems(_venue()) == ([], [])
+    assert venue_problems(_venue(name=" "))[0] == ["empty venue_name"]
+    assert venue_problems(_venue(lat=0.0, lng=0.0))[0] == ["zero coordinate (0.0, 0.0)"]
+    assert venue_problems(_venue(lat=-95))[0] == ["coordinates out of range (-95.0, -34.87)"]
+    assert venue_problems(_venue(lat=float("nan")))[0] == ["coordinates are not numbers"]
+    assert venue_problems(_venue(rating=7.5, price_level=0, reviews=-1))[1] == [
+        "rating", "price_level", "reviews",
+    ]
+
+
+def test_rejected_and_flagged_venues_are_quarantined():
+    quarantine = _quarantine()
+    validator = VenueValidator(quarantine)
+
+    assert validator.check(_venue("bad", lng=0.0), source="inventory") is None
+    fixed = validator.check(_venue("odd", rating=9.0, price_level=2), source="discovery")
+
+    assert (fixed.rating, fixed.price_level) == (None, 2)
+    entries = quarantine.entries()
+    assert [(e.venue_id, e.outcome, e.source) for e in entries] == [
+        ("odd", "flagged", "discovery"), ("bad", "rejected", "inventory"),
+    ]
+    assert entries[0].problems == ["rating out of bounds (9.0), dropped"]
+    assert entries[0].record["rating"] == 9.0
+    assert [e.venue_id for e in quarantine.entries(outcome="rejected")] == ["bad"]
+
+    # Seen again: one entry, counted twice. Valid later: released.
+    validator.check(_venue("bad", lng=0.0), source="inventory")
+    assert quarantine.get("bad").occurrences == 2
+    validator.check(_venue("bad"), source="inventory")
+    assert quarantine.get("bad") is None
+
+
+def test_quarantine_keeps_the_newest_entries():
+    quarantine = _quarantine(max_entries=2)
+    validator = VenueValidator(quarantine)
+    for vid in ("a", "b", "c"):
+        validator.check(_venue(vid, name=""), source="discovery")
+
+    assert quarantine.count() == 2
+    assert [e.venue_id for e in quarantine.entries()] == ["c", "b"]
+
+
+@pytest.mark.asyncio
+async def test_discovery_does_not_upsert_invalid_venues():
+    redis_client = fakeredis.FakeRedis(decode_responses=True)
+    dao = RedisVenueDAO(GeoRedisClient(redis_client))
+    api = Mock()
+    api.venue_filter = AsyncMock(return_value=VenueFilterResponse.model_validate({
+        "status": "OK",
+        "venues_n": 2,
+        "venues": [
+            {"venue_id": "null_island", "venue_name": "Bar", "venue_address": "",
+             "venue_lat": 0.0, "venue_lng": 0.0, "day_int": 0, "day_raw": []},
+            {"venue_id": "ok", "venue_name": "Bar do Zé", "venue_address": "Rua X",
+             "venue_lat": -8.06, "venue_lng": -34.87, "day_int": 0, "day_raw": []},
+        ],
+    }))
+    refresher = VenuesRefresherService(dao, api, redis_client=redis_client)
+    quarantine = VenueQuarantine(redis_client)
+    refresher.set_venue_quarantine(quarantine)
+
+    ids = await refresher.discover_and_upsert_venues_via_filter(
+        VenueFilterParams(lat=-8.06, lng=-34.87, radius=1000)
+    )
+
+    assert ids == ["ok"]
+    assert dao.get_venue("null_island") is None
+    assert quarantine.get("null_island").outcome == "rejected"
+
+
+def test_every_repository_write_is_validated():
+    redis_client = fakeredis.FakeRedis(decode_responses=True)
+    repo = VenueRepository(GeoRedisClient(redis_client), InMemoryRdsVenueStore())
+    quarantine = VenueQuarantine(redis_client)
+    repo.set_venue_quarantine(quarantine)
+
+    with pytest.raises(VenueRejectedError, match="zero coordinate"):
+        repo.upsert_venue(_venue("null_island", lat=0.0, lng=0.0))
+    repo.upsert_venue(_venue("odd", rating=9.0))
+    errors = {}
+    written = repo.upsert_venues([_venue("ok"), _venue("nameless", name="")], errors=errors)
+
+    assert written == 1
+    assert isinstance(errors["nameless"], VenueRejectedError)
+    assert repo.get_venue("null_island") is None and repo.get_venue("nameless") is None
+    assert repo.get_venue("odd").rating is None
+    assert {e.venue_id: e.source for e in quarantine.entries()} == {
+        "nameless": "write", "odd": "write", "null_island": "write",
+    }
+
+
+def test_a_later_stage_does_not_release_a_flagged_venue():
+    quarantine = _quarantine()
+    fixed = VenueValidator(quarantine).check(_venue("odd", rating=9.0), source="discovery")
+
+    # The repository re-checks the corrected copy the refresher writes.
+    VenueValidator(quarantine).check(fixed, source="write")
+
+    assert quarantine.get("odd").outcome == "flagged"
+
+
+def test_admin_endpoints():
+    quarantine = _quarantine()
+    VenueValidator(quarantine).check(_venue("bad", name=""), source="discovery")
+    admin_trigger_router.set_container(SimpleNamespace(venue_quarantine=quarantine))
+    app = FastAPI()
+    app.include_router(admin_trigger_router.router)
+    client = TestClient(app)
+
+    body = client.get("/admin/venues/quarantine").json()
+    assert body["count"] == 1
+    assert body["entries"][0]["problems"] == ["empty venue_name"]
+    assert client.get("/admin/venues/quarantine?outcome=flagged").json()["entries"] == []
+    assert client.get("/admin/venues/quarantine?outcome=other").status_code == 422
+    assert client.delete("/admin/venues/quarantine/bad").status_code == 200
+    assert client.delete("/admin/venues/quarantine/bad").status_code == 404
+    assert client.delete("/admin/venues/quarantine").json() == {"status": "ok", "cleared": 0}
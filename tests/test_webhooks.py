@@ -0,0 +1,258 @@
+"""Unit tests for busyness alert webhooks (app/services/webhooks.py)."""
+import json
+from datetime import datetime, timezone
+
+import fakeredis
+import httpx
+import pytest
+from fastapi import FastAPI
+from fastapi.testclient import TestClient
+from pydantic import ValidationError
+
+from app.dao.redis_venue_dao import RedisVenueDAO
+from app.db.geo_redis_client import GeoRedisClient
+from app.models import Analysis, LiveForecastResponse, Venue, VenueInfo
+from app.routers.webhook_router import router as webhook_router, set_webhook_service
+from app.services.webhooks import (
+    WebhookError,
+    WebhookRule,
+    WebhookService,
+    is_blocked_address,
+    sign,
+)
+
+_NOW = datetime.now(timezone.utc)
+
+
+def _live(vid, busyness):
+    return LiveForecastResponse(
+        status="OK",
+        venue_info=VenueInfo(venue_id=vid, venue_current_gmttime=_NOW.isoformat()),
+        analysis=Analysis(venue_live_busyness=busyness, venue_live_busyness_available=True),
+    )
+
+
+class _Receiver:
+    """httpx transport recording requests; answers `status` (or raises)."""
+
+    def __init__(self, status=200):
+        self.status = status
+        self.requests = []
+
+    def __call__(self, request: httpx.Request) -> httpx.Response:
+        self.requests.append(request)
+        if self.status is None:
+            raise httpx.ConnectError("refused", request=request)
+        return httpx.Response(self.status)
+
+
+class _Resolver:
+    """Fake DNS: every host resolves to `addresses`."""
+
+    def __init__(self, addresses=("93.184.216.34",)):
+        self.addresses = list(addresses)
+
+    def __call__(self, host):
+        return self.addresses
+
+
+def _service(receiver, **kwargs):
+    redis = fakeredis.FakeRedis(decode_responses=True)
+    dao = RedisVenueDAO(GeoRedisClient(redis))
+    dao.upsert_venues([
+        Venue(venue_id="bar", venue_name="Bar", venue_address="a", venue_lat=-8.06, venue_lng=-34.87),
+        Venue(venue_id="far", venue_name="Far", venue_address="a", venue_lat=-8.30, venue_lng=-35.00),
+    ])
+    client = httpx.AsyncClient(transport=httpx.MockTransport(receiver))
+    kwargs.setdefault("resolver", _Resolver())
+    return dao, WebhookService(
+        dao, redis, http_client=client, backoff_seconds=0, api_keys={"client-key": "app"}, **kwargs,
+    )
+
+
+def test_rule_needs_exactly_one_target():
+    WebhookRule(threshold=80, venue_id="bar")
+    WebhookRule(threshold=80, lat=-8.06, lon=-34.87, radius_km=2)
+    with pytest.raises(ValidationError):
+        WebhookRule(threshold=80)
+    with pytest.raises(ValidationError):
+        WebhookRule(threshold=80, venue_id="bar", lat=-8.06, lon=-34.87, radius_km=2)
+    with pytest.raises(ValidationError):
+        WebhookRule(threshold=80, lat=-8.06, lon=-34.87)
+
+
+def test_register_refuses_plain_http():
+    _, service = _service(_Receiver())
+    with pytest.raises(WebhookError):
+        service.register("http://example.com/hook", WebhookRule(threshold=80, venue_id="bar"))
+
+
+def test_blocked_addresses():
+    for address in ("127.0.0.1", "10.1.2.3", "192.168.0.1", "169.254.169.254",
+                    "100.64.0.1", "::1", "fe80::1", "fd00:ec2::254", "::ffff:127.0.0.1", "0.0.0.0"):
+        assert is_blocked_address(address), address
+    assert not is_blocked_address("93.184.216.34")
+    assert not is_blocked_address("2606:2800:220:1::1")
+
+
+def test_register_refuses_non_public_destinations():
+    _, service = _service(_Receiver(), resolver=_Resolver(["93.184.216.34", "10.0.0.5"]))
+    rule = WebhookRule(threshold=80, venue_id="bar")
+    for url in ("https://127.0.0.1/hook", "https://[::1]/hook", "https://169.254.169.254/latest",
+                "https://internal.example/hook"):
+        with pytest.raises(WebhookError):
+            service.register(url, rule)
+
+    def unresolvable(host):
+        raise OSError("Name or service not known")
+
+    service.resolver = unresolvable
+    with pytest.raises(WebhookError):
+        service.register("https://nowhere.example/hook", rule)
+
+
+async def test_delivery_rechecks_the_destination():
+    receiver = _Receiver()
+    resolver = _Resolver()
+    dao, service = _service(receiver, resolver=resolver)
+    hook = service.register("https://example.com/hook", WebhookRule(threshold=80, venue_id="bar"))
+    resolver.addresses = ["127.0.0.1"]  # DNS rebound after registration
+    dao.set_live_forecast(_live("bar", 85))
+
+    await service.evaluate()
+
+    assert receiver.requests == []
+    assert service.deliveries(hook.id)[0].error.startswith("refused")
+
+
+async def test_delivery_connects_to_the_checked_address():
+    receiver = _Receiver()
+    dao, service = _service(receiver)
+    service.register("https://example.com:8443/hook", WebhookRule(threshold=80, venue_id="bar"))
+    dao.set_live_forecast(_live("bar", 85))
+
+    await service.evaluate()
+
+    (request,) = receiver.requests
+    assert request.url == "https://93.184.216.34:8443/hook"
+    assert request.headers["Host"] == "example.com:8443"
+    assert request.extensions["sni_hostname"] == "example.com"
+
+
+def test_default_client_ignores_proxy_settings_from_the_environment():
+    service = WebhookService(None, fakeredis.FakeRedis(decode_responses=True))
+
+    assert service.http.trust_env is False
+
+
+async def test_background_evaluation_delivers_concurrently():
+    receiver = _Receiver()
+    dao, service = _service(receiver, max_concurrency=2)
+    for threshold in (50, 60, 70):
+        service.register("https://example.com/hook", WebhookRule(threshold=threshold, venue_id="bar"))
+    dao.set_live_forecast(_live("bar", 85))
+
+    assert service.evaluate_in_background() is True
+    assert service.evaluate_in_background() is False  # previous one still running
+    await service.close()
+
+    assert len(receiver.requests) == 3
+
+
+async def test_fires_once_per_crossing_with_signed_body():
+    receiver = _Receiver()
+    dao, service = _service(receiver)
+    hook = service.register("https://example.com/hook", WebhookRule(threshold=80, venue_id="bar"))
+
+    dao.set_live_forecast(_live("bar", 85))
+    await service.evaluate()
+    await service.evaluate()  # still above: no new event
+
+    assert len(receiver.requests) == 1
+    request = receiver.requests[0]
+    body = json.loads(request.content)
+    assert body["venue_id"] == "bar"
+    assert body["venue_busyness"] == 85
+    assert request.headers["X-Webhook-Signature"] == sign(hook.secret, request.content)
+
+    dao.set_live_forecast(_live("bar", 40))
+    await service.evaluate()
+    dao.set_live_forecast(_live("bar", 90))
+    await service.evaluate()
+
+    assert len(receiver.requests) == 2
+    assert [d.status for d in service.deliveries(hook.id)] == ["delivered", "delivered"]
+
+
+async def test_radius_rule_matches_only_nearby_venues():
+    receiver = _Receiver()
+    dao, service = _service(receiver)
+    service.register(
+        "https://example.com/hook", WebhookRule(threshold=90, lat=-8.06, lon=-34.87, radius_km=2),
+    )
+    dao.set_live_forecast(_live("bar", 95))
+    dao.set_live_forecast(_live("far", 99))
+
+    summary = await service.evaluate()
+
+    assert summary["events"] == 1
+    assert json.loads(receiver.requests[0].content)["venue_id"] == "bar"
+
+
+async def test_failed_delivery_is_retried_then_given_up():
+    receiver = _Receiver(status=500)
+    dao, service = _service(receiver, max_attempts=2)
+    hook = service.register("https://example.com/hook", WebhookRule(threshold=80, venue_id="bar"))
+    dao.set_live_forecast(_live("bar", 85))
+
+    # Zero backoff: each evaluation also runs the retry that just came due.
+    await service.evaluate()  # first POST and retry 1 fail
+    await service.evaluate()  # retry 2 fails: given up
+    await service.evaluate()  # nothing left to send
+
+    assert len(receiver.requests) == 3
+    assert [d.status for d in service.deliveries(hook.id)] == ["failed", "retrying", "retrying"]
+    assert service.retry_queue.stats()["dead"] == 1
+
+
+async def test_retry_succeeds_after_receiver_recovers():
+    receiver = _Receiver(status=None)
+    dao, service = _service(receiver)
+    hook = service.register("https://example.com/hook", WebhookRule(threshold=80, venue_id="bar"))
+    dao.set_live_forecast(_live("bar", 85))
+    await service.evaluate()
+
+    receiver.status = 204
+    await service.evaluate()
+
+    assert service.deliveries(hook.id)[0].status == "delivered"
+    assert service.retry_queue.stats()["queued"] == 0
+
+
+def test_routes_need_the_secret():
+    _, service = _service(_Receiver())
+    app = FastAPI()
+    app.include_router(webhook_router)
+    set_webhook_service(service)
+    try:
+        client = TestClient(app)
+        registration = {"url": "https://example.com/hook", "rule": {"threshold": 80, "venue_id": "bar"}}
+        assert client.post("/v1/webhooks", json=registration).status_code == 401
+        assert client.post(
+            "/v1/webhooks", json=registration, headers={"X-Webhook-Key": "wrong"}
+        ).status_code == 401
+        created = client.post("/v1/webhooks", json=registration, headers={"X-Webhook-Key": "client-key"})
+        assert created.status_code == 201
+        assert service.get(created.json()["id"]).client_id == "app"
+        hook_id, secret = created.json()["id"], created.json()["secret"]
+
+        assert client.get(f"/v1/webhooks/{hook_id}").status_code == 404
+        got = client.get(f"/v1/webhooks/{hook_id}", headers={"X-Webhook-Secret": secret})
+        assert got.status_code == 200
+        assert "secret" not in got.json()
+        assert client.delete(
+            f"/v1/webhooks/{hook_id}", headers={"X-Webhook-Secret": secret}
+        ).status_code == 204
+        assert service.get(hook_id) is None
+    finally:
+        set_webhook_service(None)
@@ -0,0 +1,119 @@
+"""Unit tests for the Thursday weekend weekly-forecast prefetch."""
+from datetime import datetime, timezone
+
+import fakeredis
+import pytest
+from apscheduler.triggers.cron import CronTrigger
+
+from app.config import Settings
+from app.dao.redis_venue_dao import RedisVenueDAO
+from app.dao.venue_budget_dao import VenueBudgetDao
+from app.dao.venue_repository import VenueRepository
+from app.db.geo_redis_client import GeoRedisClient
+from app.models import Venue, WeekRawDay
+from app.services.crowd_providers import Region
+from app.services.venue_budget_service import VenueBudgetService
+from app.services.venues_refresher_service import VenuesRefresherService
+from tests.rds_fake import InMemoryRdsVenueStore
+
+_RECIFE = Region(lat=-8.05, lng=-34.88, radius_km=5)
+
+
+def _venue(vid, lat=-8.05, lng=-34.88, priority=0):
+    return Venue(
+        forecast=True, processed=True, venue_id=vid, venue_name=f"Venue {vid}",
+        venue_address=f"addr {vid}", venue_lat=lat, venue_lng=lng, priority=priority,
+    )
+
+
+class _WeekBesttime:
+    def __init__(self):
+        self.weekly_calls = []
+
+    async def get_week_raw_forecast(self, venue_id):
+        self.weekly_calls.append(venue_id)
+
+        class _Resp:
+            status = "OK"
+
+            class analysis:
+                week_raw = [WeekRawDay(day_int=d, day_raw=[20] * 24) for d in range(7)]
+
+        return _Resp()
+
+
+def _setup(venues, cached_days=None, budget_x=None):
+    fake = fakeredis.FakeRedis(decode_responses=True)
+    store = InMemoryRdsVenueStore()
+    for v in venues:
+        store.upsert_venue(v)
+    serving = RedisVenueDAO(GeoRedisClient(fake))
+    for vid, days in (cached_days or {}).items():
+        for d in days:
+            serving.set_week_raw_forecast(vid, WeekRawDay(day_int=d, day_raw=[5] * 24))
+    besttime = _WeekBesttime()
+    refresher = VenuesRefresherService(
+        venue_dao=VenueRepository(GeoRedisClient(fake), rds_store=store),
+        besttime_api=besttime,
+        redis_client=fake,
+    )
+    if budget_x is not None:
+        fake.set(
+            "admin_config:venue_monthly_budget",
+            f'{{"monthly_quota": 500, "manual_reserve": {500 - budget_x}}}',
+        )
+        refresher.set_budget_service(VenueBudgetService(
+            redis_client=fake,
+            budget_dao=VenueBudgetDao(fake),
+            year_month_provider=lambda: "2026-10",
+        ))
+    return refresher, besttime, store
+
+
+@pytest.mark.asyncio
+async def test_fetches_only_venues_missing_a_weekend_day():
+    refresher, besttime, store = _setup(
+        [_venue("full"), _venue("no_sat"), _venue("none")],
+        cached_days={"full": [4, 5], "no_sat": [4]},
+    )
+
+    summary = await refresher.prefetch_weekend_forecasts(days=[4, 5])
+
+    assert sorted(besttime.weekly_calls) == ["no_sat", "none"]
+    assert summary["already_cached"] == 1
+    assert summary["fetched"] == 2
+    # The fetched week lands in the system of record for the projector.
+    assert store.get_enrichment("besttime.weekly_forecast", "none#5") is not None
+
+
+@pytest.mark.asyncio
+async def test_only_venues_inside_regions():
+    refresher, besttime, _ = _setup([_venue("recife"), _venue("far", lat=-23.55, lng=-46.63)])
+
+    summary = await refresher.prefetch_weekend_forecasts(days=[4, 5], regions=[_RECIFE])
+
+    assert besttime.weekly_calls == ["recife"]
+    assert summary["selected"] == 2
+    assert summary["in_regions"] == 1
+
+
+@pytest.mark.asyncio
+async def test_uses_priority_planner_selection():
+    refresher, besttime, _ = _setup(
+        [_venue("top", priority=0), _venue("mid", priority=1), _venue("low", priority=2)],
+        budget_x=2,
+    )
+
+    summary = await refresher.prefetch_weekend_forecasts(days=[4, 5])
+
+    assert sorted(besttime.weekly_calls) == ["mid", "top"]
+    assert summary["selected"] == 2
+
+
+def test_default_cron_fires_on_thursday():
+    trigger = CronTrigger.from_crontab(
+        Settings(besttime_mode="replay").weekend_prefetch_cron, timezone=timezone.utc
+    )
+    monday = datetime(2026, 10, 12, tzinfo=timezone.utc)
+
+    assert trigger.get_next_fire_time(None, monday) == datetime(2026, 10, 15, 12, 0, tzinfo=timezone.utc)